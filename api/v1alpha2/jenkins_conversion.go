@@ -0,0 +1,4 @@
+package v1alpha2
+
+// Hub marks v1alpha2 as the version all other Jenkins versions are converted through, it's the storage version
+func (*Jenkins) Hub() {}
@@ -3,16 +3,37 @@ package v1alpha2
 import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // JenkinsSpec defines the desired state of Jenkins
 // +k8s:openapi-gen=true
 type JenkinsSpec struct {
+	// InheritFrom is the name of Jenkins CR in the same namespace which spec is deep-merged into this CR as defaults,
+	// only the values which differ from the inherited ones have to be set in this CR. The values set in this CR
+	// override the inherited ones including zero values and lists are replaced as a whole. The merged spec isn't
+	// stored in this CR, the hash of the inherited spec is recorded in jenkins.io/inherited-spec-hash annotation.
+	// +optional
+	InheritFrom string `json:"inheritFrom,omitempty"`
+
 	// Master represents Jenkins master pod properties and Jenkins plugins.
 	// Every single change here requires a pod restart.
 	Master JenkinsMaster `json:"master"`
 
+	// ExternalJenkins makes the operator manage the configuration of an existing Jenkins which isn't created by
+	// the operator, e.g. one running on a VM. Jenkins master pod and its Kubernetes resources aren't created, only
+	// the base configuration groovy scripts, Configuration as Code, groovy scripts and seed jobs are applied.
+	// +optional
+	ExternalJenkins *ExternalJenkins `json:"externalJenkins,omitempty"`
+
+	// SkipUserConfiguration suspends the user configuration phase, Jenkins is provisioned with the base configuration
+	// only. Seed jobs, groovy scripts, Configuration as Code and backups aren't validated nor applied until it's disabled,
+	// e.g. when seed job repositories are temporarily unreachable.
+	// +optional
+	SkipUserConfiguration bool `json:"skipUserConfiguration,omitempty"`
+
 	// SeedJobs defines list of Jenkins Seed Job configurations
 	// More info: https://jenkinsci.github.io/kubernetes-operator/docs/getting-started/latest/configuration#configure-seed-jobs-and-pipelines
 	// +optional
@@ -22,6 +43,35 @@ type JenkinsSpec struct {
 	// +optional
 	SeedJobAgentImage string `json:"seedJobAgentImage,omitempty"`
 
+	// Jobs defines Jenkins folders and views managed by the operator
+	// +optional
+	Jobs Jobs `json:"jobs,omitempty"`
+
+	// SeedJobAgentPriorityClassName is the name of the PriorityClass used by the seed job agent pod.
+	// The preemption policy of the agent pod is taken from the PriorityClass.
+	// Backups are executed in the Jenkins master pod sidecar so they use spec.master.priorityClassName.
+	// +optional
+	SeedJobAgentPriorityClassName string `json:"seedJobAgentPriorityClassName,omitempty"`
+
+	// SeedJobAgentWorkspaceCache configures PersistentVolumeClaim used by the seed job agent to keep cloned
+	// repositories between seed job runs, seed jobs use workspaces keyed by the repository URL
+	// +optional
+	SeedJobAgentWorkspaceCache *SeedJobAgentWorkspaceCache `json:"seedJobAgentWorkspaceCache,omitempty"`
+
+	// SeedJobAgentTemplate customizes the seed job agent pod, settings which are not defined are taken from
+	// spec.master (nodeSelector, tolerations) or operator defaults
+	// +optional
+	SeedJobAgentTemplate *SeedJobAgentTemplate `json:"seedJobAgentTemplate,omitempty"`
+
+	// SeedJobAgentSecret defines how the inbound secret of the seed job agent is passed to the agent pod and rotated
+	// +optional
+	SeedJobAgentSecret *SeedJobAgentSecret `json:"seedJobAgentSecret,omitempty"`
+
+	// SCMWebhook enables the operator endpoint /scm-webhook/<namespace>/<name> which accepts GitHub and GitLab
+	// push events and triggers the seed jobs of the pushed repository and branch
+	// +optional
+	SCMWebhook *SCMWebhook `json:"scmWebhook,omitempty"`
+
 	// ValidateSecurityWarnings enables or disables validating potential security warnings in Jenkins plugins via admission webhooks.
 	//+optional
 	ValidateSecurityWarnings bool `json:"validateSecurityWarnings,omitempty"`
@@ -55,6 +105,24 @@ type JenkinsSpec struct {
 	// +optional
 	Restore Restore `json:"restore,omitempty"`
 
+	// Values is the ConfigMap which key/values are available in groovy scripts and Configuration as Code templates
+	// as {{ .Values.key }}. The keys numExecutors, kubernetesCloudName, seedJobsViewRegex and nonSeedJobsViewRegex
+	// override the defaults of the operator base configuration.
+	// +optional
+	Values *ConfigMapRef `json:"values,omitempty"`
+
+	// BaseGroovyScripts defines groovy scripts applied in the base configuration phase after the operator base
+	// configuration, e.g. to configure Kubernetes clouds of external clusters. The scripts can reference secrets
+	// the same way as spec.groovyScripts.
+	// +optional
+	BaseGroovyScripts GroovyScripts `json:"baseGroovyScripts,omitempty"`
+
+	// GroovyScriptOutput records the tail of the output of the groovy scripts in status.groovyScriptResults,
+	// status.baseConfigurationProgress and the groovy script events, only the errors are recorded by default. The output
+	// may contain secrets printed by the scripts.
+	// +optional
+	GroovyScriptOutput bool `json:"groovyScriptOutput,omitempty"`
+
 	// GroovyScripts defines configuration of Jenkins customization via groovy scripts
 	// +optional
 	GroovyScripts GroovyScripts `json:"groovyScripts,omitempty"`
@@ -63,6 +131,36 @@ type JenkinsSpec struct {
 	// +optional
 	ConfigurationAsCode ConfigurationAsCode `json:"configurationAsCode,omitempty"`
 
+	// ReadinessCheck is the groovy smoke test script executed after the base and user configuration is applied,
+	// the Jenkins CR is marked as ready in status only when the script succeeds
+	// +optional
+	ReadinessCheck *ReadinessCheck `json:"readinessCheck,omitempty"`
+
+	// ExtraResources are Kubernetes objects applied and owned by the operator alongside the Jenkins instance,
+	// e.g. ExternalSecret, Certificate or custom Service. The objects are created in the Jenkins CR namespace.
+	// Only ConfigMap, Service, PersistentVolumeClaim, Ingress, NetworkPolicy, PodDisruptionBudget, Certificate,
+	// ExternalSecret, ServiceMonitor and PodMonitor are allowed, kinds granting permissions or running workloads
+	// are rejected.
+	// +optional
+	ExtraResources []ExtraResource `json:"extraResources,omitempty"`
+
+	// PrometheusRule generates Prometheus Operator PrometheusRule with alerts of the Jenkins instance: backup is
+	// stale, instance is not ready and the master pod is restarted too often. The PrometheusRule CRD has to be installed.
+	// +optional
+	PrometheusRule *PrometheusRule `json:"prometheusRule,omitempty"`
+
+	// AdoptionPolicy defines what happens when a resource managed by the operator already exists but isn't owned by
+	// this Jenkins CR: Adopt (default) takes it over and sets the owner reference, Fail stops the reconciliation and
+	// Ignore leaves the resource untouched
+	// +kubebuilder:validation:Enum=Adopt;Fail;Ignore
+	// +optional
+	AdoptionPolicy AdoptionPolicy `json:"adoptionPolicy,omitempty"`
+
+	// ImmutableConfiguration enables the immutable mode: the operator never updates its groovy ConfigMaps and
+	// the operator credentials Secret, every change creates a new immutable version referenced by Jenkins master pod
+	// +optional
+	ImmutableConfiguration *ImmutableConfiguration `json:"immutableConfiguration,omitempty"`
+
 	// Roles defines list of extra RBAC roles for the Jenkins Master pod service account
 	// +optional
 	Roles []rbacv1.RoleRef `json:"roles,omitempty"`
@@ -73,6 +171,15 @@ type JenkinsSpec struct {
 
 	// JenkinsAPISettings defines configuration used by the operator to gain admin access to the Jenkins API
 	JenkinsAPISettings JenkinsAPISettings `json:"jenkinsAPISettings"`
+
+	// CommonLabels are added to every Kubernetes resource created by the operator for this Jenkins CR.
+	// Labels required by the operator take precedence over them.
+	// +optional
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+
+	// CommonAnnotations are added to every Kubernetes resource created by the operator for this Jenkins CR
+	// +optional
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
 }
 
 // AuthorizationStrategy defines authorization strategy of the operator for the Jenkins API
@@ -88,6 +195,42 @@ const (
 // JenkinsAPISettings defines configuration used by the operator to gain admin access to the Jenkins API
 type JenkinsAPISettings struct {
 	AuthorizationStrategy AuthorizationStrategy `json:"authorizationStrategy"`
+
+	// Hostname is the hostname or IP of Jenkins API, it overrides the operator --jenkins-api-hostname setting
+	// for this Jenkins. When set, port and useNodePort of this CR are used as well.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+
+	// Port is the port on which Jenkins API is reachable on the hostname, it overrides the operator
+	// --jenkins-api-port setting for this Jenkins
+	// +optional
+	Port int `json:"port,omitempty"`
+
+	// UseNodePort tells operator to connect to Jenkins API using the hostname and the service nodePort, it overrides
+	// the operator --jenkins-api-use-nodeport setting for this Jenkins
+	// +optional
+	UseNodePort bool `json:"useNodePort,omitempty"`
+
+	// AuthProxy configures how the operator gets through an auth proxy (e.g. OAuth proxy sidecar) in front of Jenkins
+	// +optional
+	AuthProxy *AuthProxy `json:"authProxy,omitempty"`
+}
+
+// AuthProxy defines how the operator authenticates through an auth proxy in front of Jenkins.
+type AuthProxy struct {
+	// HeaderName is the HTTP header added to all operator requests to Jenkins API, e.g. X-Forwarded-Access-Token.
+	// The Authorization header is used by the operator to authenticate in Jenkins and can't be set here.
+	// +optional
+	HeaderName string `json:"headerName,omitempty"`
+
+	// HeaderValueSecretKeySelector selects the secret key with the value of the header
+	// +optional
+	HeaderValueSecretKeySelector *SecretKeySelector `json:"headerValueSecretKeySelector,omitempty"`
+
+	// BypassPort is the port of the Jenkins master pod which is not protected by the auth proxy, when set the operator
+	// connects to this port on the Jenkins master pod IP instead of the Jenkins HTTP service
+	// +optional
+	BypassPort int32 `json:"bypassPort,omitempty"`
 }
 
 // ServiceAccount defines Kubernetes service account attributes
@@ -120,6 +263,11 @@ type Notification struct {
 	Teams        *MicrosoftTeams   `json:"teams,omitempty"`
 	Mailgun      *Mailgun          `json:"mailgun,omitempty"`
 	SMTP         *SMTP             `json:"smtp,omitempty"`
+	Webhook      *Webhook          `json:"webhook,omitempty"`
+	PagerDuty    *PagerDuty        `json:"pagerDuty,omitempty"`
+	// Custom is handled by the notification provider registered out of the operator tree
+	// +optional
+	Custom *CustomNotification `json:"custom,omitempty"`
 }
 
 // Slack is handler for Slack notification channel.
@@ -153,6 +301,64 @@ type Mailgun struct {
 	From                    string            `json:"from"`
 }
 
+// Webhook is handler for sending notifications as HTTP POST requests to any URL.
+type Webhook struct {
+	// The URL the notifications are sent to
+	URLSecretKeySelector SecretKeySelector `json:"urlSecretKeySelector"`
+	// HeadersSecret is the name of a Secret in the Jenkins CR namespace, every key/value of the Secret is sent as
+	// an HTTP header, e.g. Authorization
+	// +optional
+	HeadersSecret *SecretRef `json:"headersSecret,omitempty"`
+	// PayloadTemplate is a Go template of the request body, the available fields are .Title, .Level, .Phase,
+	// .Namespace, .Name and .Messages, the json function escapes a value as a JSON string. When not set
+	// the fields are sent as a JSON object.
+	// +optional
+	PayloadTemplate string `json:"payloadTemplate,omitempty"`
+}
+
+// PagerDutySeverity is the severity of PagerDuty alert.
+type PagerDutySeverity string
+
+const (
+	// PagerDutySeverityCritical - critical PagerDuty alert
+	PagerDutySeverityCritical PagerDutySeverity = "critical"
+
+	// PagerDutySeverityError - error PagerDuty alert
+	PagerDutySeverityError PagerDutySeverity = "error"
+
+	// PagerDutySeverityWarning - warning PagerDuty alert
+	PagerDutySeverityWarning PagerDutySeverity = "warning"
+
+	// PagerDutySeverityInfo - info PagerDuty alert
+	PagerDutySeverityInfo PagerDutySeverity = "info"
+)
+
+// PagerDuty is handler for triggering PagerDuty alerts via Events API v2.
+type PagerDuty struct {
+	// The integration key of PagerDuty service
+	RoutingKeySecretKeySelector SecretKeySelector `json:"routingKeySecretKeySelector"`
+	// WarningSeverity is the severity of alerts triggered by warning notifications, defaults to error
+	// +kubebuilder:validation:Enum=critical;error;warning;info
+	// +optional
+	WarningSeverity PagerDutySeverity `json:"warningSeverity,omitempty"`
+	// InfoSeverity is the severity of alerts triggered by info notifications, defaults to info
+	// +kubebuilder:validation:Enum=critical;error;warning;info
+	// +optional
+	InfoSeverity PagerDutySeverity `json:"infoSeverity,omitempty"`
+}
+
+// CustomNotification is handler for notification providers registered out of the operator tree.
+type CustomNotification struct {
+	// Provider is the kind of the notification provider registered by notifications.RegisterCustomProvider
+	Provider string `json:"provider"`
+	// Parameters configure the provider, e.g. the channel or the recipients
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// SecretParameters configure the provider with values of secrets, e.g. API tokens
+	// +optional
+	SecretParameters map[string]SecretKeySelector `json:"secretParameters,omitempty"`
+}
+
 // SecretKeySelector selects a key of a Secret.
 type SecretKeySelector struct {
 	// The name of the secret in the pod's namespace to select from.
@@ -285,6 +491,14 @@ type JenkinsMaster struct {
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 
+	// ValidateImageArchitectures enables verification that the images of Jenkins master containers and of the seed job
+	// agent support the CPU architectures of the nodes matched by their nodeSelector and tolerations. The architectures
+	// are read from the image manifest list in the registry with the credentials of imagePullSecrets, the operator needs
+	// permission to list nodes. Unsupported architectures are reported as warning events, images which can't be
+	// inspected aren't reported.
+	// +optional
+	ValidateImageArchitectures bool `json:"validateImageArchitectures,omitempty"`
+
 	// SecurityContext that applies to all the containers of the Jenkins
 	// Master. As per kubernetes specification, it can be overridden
 	// for each container individually.
@@ -331,6 +545,51 @@ type JenkinsMaster struct {
 	//       memory: 600Mi
 	Containers []Container `json:"containers,omitempty"`
 
+	// InitContainers is a list of containers which are run before the Jenkins master container starts,
+	// they can be used e.g. to seed Jenkins home directory or to import certificates
+	// +optional
+	InitContainers []Container `json:"initContainers,omitempty"`
+
+	// TrustedCertificates is a list of ConfigMaps with PEM encoded certificates which are imported
+	// into the Java truststore used by Jenkins master, e.g. to trust internal services signed by a private CA
+	// +optional
+	TrustedCertificates []ConfigMapRef `json:"trustedCertificates,omitempty"`
+
+	// EmptyDir configures storage medium and size limit of emptyDir volumes created by the operator
+	// for Jenkins master pod, e.g. jenkins-home
+	// +optional
+	EmptyDir *corev1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+
+	// JenkinsHomePersistentVolumeClaim configures PersistentVolumeClaim used as jenkins-home volume instead of emptyDir,
+	// the claim is expanded when the size grows, the storage class has to allow volume expansion
+	// +optional
+	JenkinsHomePersistentVolumeClaim *JenkinsHomePersistentVolumeClaim `json:"jenkinsHomePersistentVolumeClaim,omitempty"`
+
+	// ImageRollback keeps VolumeSnapshot of Jenkins home PersistentVolumeClaim taken before Jenkins master image
+	// upgrade for the given window, when the image is reverted within the window Jenkins home is recreated from
+	// the snapshot instead of restoring the backup. It requires spec.master.jenkinsHomePersistentVolumeClaim.
+	// +optional
+	ImageRollback *ImageRollback `json:"imageRollback,omitempty"`
+
+	// BuildRetention configures global build discarder applied to all jobs in Jenkins,
+	// it's applied without Jenkins master pod restart
+	// +optional
+	BuildRetention *BuildRetention `json:"buildRetention,omitempty"`
+
+	// UpdateCenter configures update center used to download plugins by the operator and by Jenkins
+	// +optional
+	UpdateCenter *UpdateCenter `json:"updateCenter,omitempty"`
+
+	// AgentListener configures the inbound TCP agent port and the agent protocols of Jenkins
+	// +optional
+	AgentListener *AgentListener `json:"agentListener,omitempty"`
+
+	// ReadinessGates are extra conditions verified after all Jenkins master containers are ready and before
+	// the base configuration is applied, e.g. endpoints of sidecars initializing Jenkins. They're verified once per
+	// Jenkins master pod.
+	// +optional
+	ReadinessGates []ReadinessGate `json:"readinessGates,omitempty"`
+
 	// ImagePullSecrets is an optional list of references to secrets in the same namespace to use for pulling any of the images used by this PodSpec.
 	// If specified, these secrets will be passed to individual puller implementations for them to use. For example,
 	// in the case of docker, only DockerConfig type secrets are honored.
@@ -370,9 +629,34 @@ type JenkinsMaster struct {
 	// +optional
 	Plugins []Plugin `json:"plugins,omitempty"`
 
+	// LockPlugins makes the operator install and verify exactly the plugins recorded in status.pluginsLock,
+	// including dependencies, instead of resolving dependencies again on every Jenkins master pod restart
+	// +optional
+	LockPlugins bool `json:"lockPlugins,omitempty"`
+
+	// PluginUpgradePolicy defines how the operator handles plugins which versions differ from the spec:
+	// Pinned restarts Jenkins master pod to install exactly the versions from the spec, PatchOnly and Latest accept
+	// newer versions installed in Jenkins and upgrade the plugins from the spec to the patch or any newer version
+	// reported in status.availableUpdates by the update center API followed by Jenkins safe restart
+	// +kubebuilder:validation:Enum=Pinned;PatchOnly;Latest
+	// +optional
+	PluginUpgradePolicy PluginUpgradePolicy `json:"pluginUpgradePolicy,omitempty"`
+
+	// SkipBaseConfiguration makes the operator skip installation and verification of plugins and the base
+	// configuration groovy scripts, it's meant for pre-baked Jenkins images with everything installed and configured.
+	// The operator manages only Kubernetes resources, user configuration and backups.
+	// +optional
+	SkipBaseConfiguration bool `json:"skipBaseConfiguration,omitempty"`
+
 	// DisableCSRFProtection allows you to toggle CSRF Protection on Jenkins
 	DisableCSRFProtection bool `json:"disableCSRFProtection"`
 
+	// DisableKubernetesCloud tells operator to not configure Kubernetes cloud of kubernetes plugin, not create
+	// the slave service and not grant Jenkins access to pods, for instances using only external or static agents.
+	// Seed jobs can't be used then because the seed job agent connects through the slave service.
+	// +optional
+	DisableKubernetesCloud bool `json:"disableKubernetesCloud,omitempty"`
+
 	// PriorityClassName for Jenkins master pod
 	// +optional
 	PriorityClassName string `json:"priorityClassName,omitempty"`
@@ -380,6 +664,75 @@ type JenkinsMaster struct {
 	// HostAliases for Jenkins master pod and SeedJob agent
 	// +optional
 	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// HealthCheckPath is the path of Jenkins HTTP endpoint used by readiness and liveness probes of Jenkins master
+	// container to decide if Jenkins is ready, defaults to /login. Instances with mandatory SSO which redirect /login
+	// can use e.g. /instance-identity/ instead. The --prefix from JENKINS_OPTS is prepended to the path.
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	HealthCheckPath string `json:"healthCheckPath,omitempty"`
+
+	// PodPendingTimeout is how long the Jenkins master pod can stay in Pending phase before the operator
+	// stops the reconcile loop and reports a diagnosis, defaults to 2m
+	// +optional
+	PodPendingTimeout *metav1.Duration `json:"podPendingTimeout,omitempty"`
+
+	// PodEventsFilter selects events of Jenkins master pod included in the notification sent when the pod is
+	// pending for longer than PodPendingTimeout, by default non-Normal events since the provisioning start are included
+	// +optional
+	PodEventsFilter *PodEventsFilter `json:"podEventsFilter,omitempty"`
+
+	// HeapSizing computes the JVM heap size (-Xmx and -Xms) of Jenkins master container from its memory limit,
+	// or the memory request when the limit is not set, and appends it to JAVA_OPTS
+	// +optional
+	HeapSizing *HeapSizing `json:"heapSizing,omitempty"`
+
+	// CrashLogs collects the log tail, JVM fatal error (hs_err) file and GC log of terminated Jenkins master container
+	// into a ConfigMap referenced from the pod restart notification
+	// +optional
+	CrashLogs *CrashLogs `json:"crashLogs,omitempty"`
+}
+
+// CrashLogs defines the crash logs collection of Jenkins master pod.
+type CrashLogs struct {
+	// TailLines is the number of lines collected from each log, defaults to 500
+	// +optional
+	TailLines int64 `json:"tailLines,omitempty"`
+
+	// GCLogs enables JVM garbage collection logging to the crash logs directory in Jenkins home
+	// +optional
+	GCLogs bool `json:"gcLogs,omitempty"`
+}
+
+// PodEventsFilter defines which events of Jenkins master pod are reported when the pod fails to start.
+type PodEventsFilter struct {
+	// LookbackWindow includes events which occurred up to this duration before the provisioning start, e.g. events
+	// of a previous scheduling attempt, defaults to 0
+	// +optional
+	LookbackWindow *metav1.Duration `json:"lookbackWindow,omitempty"`
+
+	// Types is the list of event types to include, e.g. Warning, all types except Normal are included when empty
+	// +optional
+	Types []string `json:"types,omitempty"`
+
+	// Reasons is the list of event reasons to include, e.g. FailedScheduling or FailedMount, all reasons are included
+	// when empty
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
+
+	// MessageRegex includes only events which message matches the regular expression
+	// +optional
+	MessageRegex string `json:"messageRegex,omitempty"`
+}
+
+// HeapSizing defines JVM heap size of Jenkins master as a percentage of the container memory.
+type HeapSizing struct {
+	// MaxPercentage is the maximum heap size (-Xmx) in percent of the container memory, between 1 and 100
+	MaxPercentage int32 `json:"maxPercentage"`
+
+	// InitialPercentage is the initial heap size (-Xms) in percent of the container memory, defaults to MaxPercentage
+	// +optional
+	InitialPercentage int32 `json:"initialPercentage,omitempty"`
 }
 
 // Service defines Kubernetes service attributes
@@ -460,6 +813,11 @@ type JenkinsStatus struct {
 	// +optional
 	BaseConfigurationCompletedTime *metav1.Time `json:"baseConfigurationCompletedTime,omitempty"`
 
+	// BaseConfigurationProgress is the progress of applying groovy scripts in the base configuration phase,
+	// it's updated after every applied script
+	// +optional
+	BaseConfigurationProgress *ConfigurationProgress `json:"baseConfigurationProgress,omitempty"`
+
 	// UserConfigurationCompletedTime is a time when Jenkins user configuration phase has been completed
 	// +optional
 	UserConfigurationCompletedTime *metav1.Time `json:"userConfigurationCompletedTime,omitempty"`
@@ -472,6 +830,18 @@ type JenkinsStatus struct {
 	// +optional
 	LastBackup uint64 `json:"lastBackup,omitempty"`
 
+	// LastBackupTime is a time when the latest successful backup has been made
+	// +optional
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+
+	// LastBackupNumber is the number of the latest successful backup
+	// +optional
+	LastBackupNumber uint64 `json:"lastBackupNumber,omitempty"`
+
+	// LastBackupError is the error message of the latest failed backup, it's cleared by the next successful backup
+	// +optional
+	LastBackupError string `json:"lastBackupError,omitempty"`
+
 	// PendingBackup is the pending backup number
 	// +optional
 	PendingBackup uint64 `json:"pendingBackup,omitempty"`
@@ -480,6 +850,21 @@ type JenkinsStatus struct {
 	// +optional
 	BackupDoneBeforePodDeletion bool `json:"backupDoneBeforePodDeletion,omitempty"`
 
+	// BackupEstimate is the latest estimated backup size and duration made in backup dry run mode
+	// +optional
+	BackupEstimate *BackupEstimate `json:"backupEstimate,omitempty"`
+
+	// BackupDestinations is the status of additional backup destinations
+	// +optional
+	BackupDestinations []BackupDestinationStatus `json:"backupDestinations,omitempty"`
+
+	// BackupCommands is the outcome of the last backup and restore commands executed by the operator in the backup
+	// container, one entry per action
+	// +listType=map
+	// +listMapKey=action
+	// +optional
+	BackupCommands []BackupCommandStatus `json:"backupCommands,omitempty"`
+
 	// UserAndPasswordHash is a SHA256 hash made from user and password
 	// +optional
 	UserAndPasswordHash string `json:"userAndPasswordHash,omitempty"`
@@ -488,150 +873,1082 @@ type JenkinsStatus struct {
 	// +optional
 	CreatedSeedJobs []string `json:"createdSeedJobs,omitempty"`
 
+	// SeedJobPlugins are the installed plugins which generate the seed jobs in name:version format, the seed jobs
+	// are applied again when their versions change
+	// +optional
+	SeedJobPlugins []string `json:"seedJobPlugins,omitempty"`
+
 	// AppliedGroovyScripts is a list with all applied groovy scripts in Jenkins by the operator
 	// +optional
 	AppliedGroovyScripts []AppliedGroovyScript `json:"appliedGroovyScripts,omitempty"`
-}
 
-// +kubebuilder:object:root=true
-// +kubebuilder:subresource:status
-// +genclient
-// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+	// GroovyScriptResults is the result of the last execution of every groovy script run by the operator,
+	// failed scripts are kept until they succeed or they are removed from the spec
+	// +optional
+	GroovyScriptResults []GroovyScriptResult `json:"groovyScriptResults,omitempty"`
 
-// Jenkins is the Schema for the jenkins API
-// +k8s:openapi-gen=true
-type Jenkins struct {
-	metav1.TypeMeta   `json:",inline"`
-	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// LastReconcileError is the error of the latest failed reconcile loop, it's cleared when the reconcile loop succeeds
+	// +optional
+	LastReconcileError *ReconcileError `json:"lastReconcileError,omitempty"`
 
-	// Spec defines the desired state of the Jenkins
-	Spec JenkinsSpec `json:"spec,omitempty"`
+	// PodStartingDiagnosis describes why the Jenkins master pod didn't start within the pending timeout
+	// +optional
+	PodStartingDiagnosis string `json:"podStartingDiagnosis,omitempty"`
 
-	// Status defines the observed state of Jenkins
-	Status JenkinsStatus `json:"status,omitempty"`
-}
+	// SeedJobAgent is the state of the seed job agent inbound secret rotation
+	// +optional
+	SeedJobAgent *SeedJobAgentStatus `json:"seedJobAgent,omitempty"`
 
-// +kubebuilder:object:root=true
-// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+	// BackoffUntil is the time the Jenkins master pod pending for longer than the pending timeout is recreated,
+	// the delay doubles with every recreation
+	// +optional
+	BackoffUntil *metav1.Time `json:"backoffUntil,omitempty"`
 
-// JenkinsList contains a list of Jenkins
-type JenkinsList struct {
-	metav1.TypeMeta `json:",inline"`
-	metav1.ListMeta `json:"metadata,omitempty"`
-	Items           []Jenkins `json:"items"`
-}
+	// PodStartingRetries is the number of times the Jenkins master pod has been recreated because it was pending
+	// for longer than the pending timeout, it's reset once the pod starts
+	// +optional
+	PodStartingRetries int `json:"podStartingRetries,omitempty"`
 
-// JenkinsCredentialType defines type of Jenkins credential used to seed job mechanism.
-type JenkinsCredentialType string
+	// PluginsLock is the full set of plugins installed in Jenkins, it's recorded after plugins from the spec
+	// have been successfully installed
+	// +optional
+	PluginsLock *PluginsLock `json:"pluginsLock,omitempty"`
 
-const (
-	// NoJenkinsCredentialCredentialType define none Jenkins credential type
-	NoJenkinsCredentialCredentialType JenkinsCredentialType = ""
-	// BasicSSHCredentialType define basic SSH Jenkins credential type
-	BasicSSHCredentialType JenkinsCredentialType = "basicSSHUserPrivateKey"
-	// UsernamePasswordCredentialType define username & password Jenkins credential type
-	UsernamePasswordCredentialType JenkinsCredentialType = "usernamePassword"
-	GithubAppCredentialType        JenkinsCredentialType = "githubApp"
-	// ExternalCredentialType defines other credential type
-	ExternalCredentialType JenkinsCredentialType = "external"
-)
+	// PluginUpgrades are plugins in name:version format which the operator has installed by the update center API,
+	// the same upgrade is retried with exponential backoff until the installed plugins match the spec
+	// +optional
+	PluginUpgrades []string `json:"pluginUpgrades,omitempty"`
 
-// AllowedJenkinsCredentialMap contains all allowed Jenkins credentials types.
-var AllowedJenkinsCredentialMap = map[string]string{
-	string(NoJenkinsCredentialCredentialType): "",
-	string(BasicSSHCredentialType):            "",
-	string(UsernamePasswordCredentialType):    "",
-	string(GithubAppCredentialType):           "",
-	string(ExternalCredentialType):            "",
-}
+	// PluginUpgradeAttempts is the number of times the plugin upgrades have been installed
+	// +optional
+	PluginUpgradeAttempts int `json:"pluginUpgradeAttempts,omitempty"`
 
-// SeedJob defines configuration for seed job
-// More info: https://jenkinsci.github.io/kubernetes-operator/docs/getting-started/latest/configuration/#configure-seed-jobs-and-pipelines.
-type SeedJob struct {
-	// ID is the unique seed job name
-	ID string `json:"id,omitempty"`
+	// PluginUpgradeTime is the last time the plugin upgrades have been installed
+	// +optional
+	PluginUpgradeTime *metav1.Time `json:"pluginUpgradeTime,omitempty"`
 
-	// CredentialID is the Kubernetes secret name which stores repository access credentials
-	CredentialID string `json:"credentialID,omitempty"`
+	// AvailableUpdates lists Jenkins core and plugin updates available in the update center, it's updated periodically
+	// when the update check is enabled
+	// +optional
+	AvailableUpdates *AvailableUpdates `json:"availableUpdates,omitempty"`
 
-	// Description is the description of the seed job
+	// RestoreRehearsal is the result of the latest restore rehearsal
 	// +optional
-	Description string `json:"description,omitempty"`
+	RestoreRehearsal *RestoreRehearsalStatus `json:"restoreRehearsal,omitempty"`
 
-	// Targets is the repository path where are seed job definitions
-	Targets string `json:"targets,omitempty"`
+	// RestoreDryRun is the content of the backup which would be restored, listed by spec.restore.dryRun
+	// +optional
+	RestoreDryRun *RestoreDryRunStatus `json:"restoreDryRun,omitempty"`
 
-	// RepositoryBranch is the repository branch where are seed job definitions
-	RepositoryBranch string `json:"repositoryBranch,omitempty"`
+	// Ready is true when the base and user configuration is applied and the readiness check script succeeded,
+	// it's reset when Jenkins master pod is recreated
+	// +optional
+	Ready bool `json:"ready,omitempty"`
 
-	// RepositoryURL is the repository access URL. Can be SSH or HTTPS.
-	RepositoryURL string `json:"repositoryUrl,omitempty"`
+	// JenkinsVersion is the version of Jenkins core running in the master pod, it's read from Jenkins API after
+	// the base configuration is applied
+	// +optional
+	JenkinsVersion string `json:"jenkinsVersion,omitempty"`
 
-	// JenkinsCredentialType is the https://jenkinsci.github.io/kubernetes-credentials-provider-plugin/ credential type
+	// SeedJobs is the number of created seed jobs out of the seed jobs defined in spec, e.g. 2/3
 	// +optional
-	JenkinsCredentialType JenkinsCredentialType `json:"credentialType,omitempty"`
+	SeedJobs string `json:"seedJobs,omitempty"`
 
-	// BitbucketPushTrigger is used for Bitbucket web hooks
+	// ReadinessCheckMessage is the output of the last failed readiness check script
 	// +optional
-	BitbucketPushTrigger bool `json:"bitbucketPushTrigger"`
+	ReadinessCheckMessage string `json:"readinessCheckMessage,omitempty"`
 
-	// GitHubPushTrigger is used for GitHub web hooks
+	// ReconcileTimings is the time spent in the phases of the last completed reconcile run which spanned multiple
+	// reconcile loops or took at least a minute, it's updated when any of the durations changes by more than a second
 	// +optional
-	GitHubPushTrigger bool `json:"githubPushTrigger"`
+	ReconcileTimings *ReconcileTimings `json:"reconcileTimings,omitempty"`
 
-	// BuildPeriodically is setting for scheduled trigger
+	// AppliedExtraResources are the objects applied from spec.extraResources, the objects removed from the spec
+	// are deleted
 	// +optional
-	BuildPeriodically string `json:"buildPeriodically"`
+	AppliedExtraResources []ExtraResourceReference `json:"appliedExtraResources,omitempty"`
 
-	// PollSCM is setting for polling changes in SCM
+	// PodRestarts counts Jenkins master pod restarts by their source, it tells whether the restarts are caused by
+	// the platform, the operator or changes of Jenkins CR
 	// +optional
-	PollSCM string `json:"pollSCM"`
+	PodRestarts *PodRestarts `json:"podRestarts,omitempty"`
 
-	// IgnoreMissingFiles is setting for Job DSL API plugin to ignore files that miss
+	// LastKnownGoodSpec is the snapshot of spec taken when Jenkins became ready, the spec is rolled back to it when
+	// Jenkins master pod enters crash loop after a spec change
 	// +optional
-	IgnoreMissingFiles bool `json:"ignoreMissingFiles"`
+	// +kubebuilder:pruning:PreserveUnknownFields
+	LastKnownGoodSpec *runtime.RawExtension `json:"lastKnownGoodSpec,omitempty"`
 
-	// AdditionalClasspath is setting for Job DSL API plugin to set Additional Classpath
+	// LastKnownGoodGeneration is the metadata.generation of Jenkins CR the LastKnownGoodSpec snapshot was taken from
 	// +optional
-	AdditionalClasspath string `json:"additionalClasspath"`
+	LastKnownGoodGeneration int64 `json:"lastKnownGoodGeneration,omitempty"`
 
-	// FailOnMissingPlugin is setting for Job DSL API plugin that fails job if required plugin is missing
+	// Degraded is set when the spec has been rolled back to LastKnownGoodSpec, it's cleared when the spec is changed
 	// +optional
-	FailOnMissingPlugin bool `json:"failOnMissingPlugin"`
+	Degraded bool `json:"degraded,omitempty"`
 
-	// UnstableOnDeprecation is setting for Job DSL API plugin that sets build status as unstable if build using deprecated features
+	// DegradedReason describes why the spec has been rolled back
 	// +optional
-	UnstableOnDeprecation bool `json:"unstableOnDeprecation"`
-}
+	DegradedReason string `json:"degradedReason,omitempty"`
 
-// Handler defines a specific action that should be taken.
-type Handler struct {
-	// Exec specifies the action to take.
-	Exec *corev1.ExecAction `json:"exec,omitempty"`
-}
+	// JenkinsHomeVolumeResize is the progress of the latest expansion of Jenkins home PersistentVolumeClaim
+	// +optional
+	JenkinsHomeVolumeResize *VolumeResize `json:"jenkinsHomeVolumeResize,omitempty"`
 
-// Backup defines configuration of Jenkins backup.
-type Backup struct {
-	// ContainerName is the container name responsible for backup operation
-	ContainerName string `json:"containerName"`
+	// JenkinsHomePersistentVolumeClaimName is the name of Jenkins home PersistentVolumeClaim created by the latest
+	// migration to another storage class, the default name is used when not set
+	// +optional
+	JenkinsHomePersistentVolumeClaimName string `json:"jenkinsHomePersistentVolumeClaimName,omitempty"`
 
-	// Action defines action which performs backup in backup container sidecar
-	Action Handler `json:"action"`
+	// JenkinsHomeVolumeMigration is the progress of the latest migration of Jenkins home to another storage class
+	// +optional
+	JenkinsHomeVolumeMigration *VolumeMigration `json:"jenkinsHomeVolumeMigration,omitempty"`
 
-	// Interval tells how often make backup in seconds
-	// Defaults to 30.
-	Interval uint64 `json:"interval"`
+	// ImageRollback is the Jenkins home snapshot taken before the latest Jenkins master image upgrade
+	// +optional
+	ImageRollback *ImageRollbackStatus `json:"imageRollback,omitempty"`
 
-	// MakeBackupBeforePodDeletion tells operator to make backup before Jenkins master pod deletion
+	// ConfigurationAsCodeGitRepository is the latest pull of spec.configurationAsCode.gitRepository
+	// +optional
+	ConfigurationAsCodeGitRepository *GitRepositoryStatus `json:"configurationAsCodeGitRepository,omitempty"`
+
+	// ConfigurationVersions are the immutable versions of the resources managed by the operator used by Jenkins
+	// master pod when spec.immutableConfiguration is set
+	// +optional
+	ConfigurationVersions *ConfigurationVersions `json:"configurationVersions,omitempty"`
+
+	// Conditions are the observations of Jenkins CR state following Kubernetes conventions, e.g.
+	// kubectl wait --for=condition=Ready jenkins/<name>
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// Features lists the optional subsystems of the operator and whether they are active for this Jenkins instance,
+	// the reason of a disabled feature tells what is missing, e.g. an API or a plugin
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Features []FeatureStatus `json:"features,omitempty"`
+}
+
+// Condition types of Jenkins CR status.
+const (
+	// ConditionReady is true when the base and user configuration is applied and the readiness check script succeeded
+	ConditionReady = "Ready"
+
+	// ConditionBaseConfigurationCompleted is true when the base configuration phase has been completed
+	ConditionBaseConfigurationCompleted = "BaseConfigurationCompleted"
+
+	// ConditionUserConfigurationCompleted is true when the user configuration phase has been completed
+	ConditionUserConfigurationCompleted = "UserConfigurationCompleted"
+
+	// ConditionPluginsVerified is true when the plugins installed in Jenkins match the spec
+	ConditionPluginsVerified = "PluginsVerified"
+
+	// ConditionBackupHealthy is true when the latest backup has been made successfully
+	ConditionBackupHealthy = "BackupHealthy"
+
+	// ConditionResourceQuotaSufficient is false when the ResourceQuotas in the namespace don't have room for
+	// the Jenkins master pod and the pod isn't created
+	ConditionResourceQuotaSufficient = "ResourceQuotaSufficient"
+
+	// ConditionStalled is true when the reconcile loop has been requeueing for longer than the operator stalled
+	// threshold, the message describes why
+	ConditionStalled = "Stalled"
+)
+
+// Names of the optional subsystems reported in Jenkins CR status features.
+const (
+	// FeatureBackup - backups made by the backup container or by the operator to S3-compatible object storage
+	FeatureBackup = "Backup"
+
+	// FeatureRoute - OpenShift Route of Jenkins HTTP service
+	FeatureRoute = "Route"
+
+	// FeatureIngress - Ingress of Jenkins applied from spec.extraResources
+	FeatureIngress = "Ingress"
+
+	// FeatureConfigurationAsCode - Jenkins customization by Configuration as Code plugin
+	FeatureConfigurationAsCode = "ConfigurationAsCode"
+
+	// FeatureNotifications - notifications about Jenkins status sent by the operator
+	FeatureNotifications = "Notifications"
+)
+
+// FeatureStatus defines whether an optional subsystem of the operator is active for the Jenkins instance.
+type FeatureStatus struct {
+	// Name is the name of the feature, e.g. Backup
+	Name string `json:"name"`
+
+	// Enabled is true when the feature is active
+	Enabled bool `json:"enabled"`
+
+	// Reason is the reason of the feature state in CamelCase, e.g. NotConfigured, APINotAvailable or PluginNotInstalled
+	Reason string `json:"reason"`
+
+	// Message is a human readable description of the feature state
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ImageRollbackPhase is the phase of Jenkins home snapshot taken before Jenkins master image upgrade.
+type ImageRollbackPhase string
+
+const (
+	// ImageRollbackSnapshotting - Jenkins master pod with the previous image has been deleted and the snapshot of
+	// Jenkins home is being taken before the pod with the new image is created
+	ImageRollbackSnapshotting ImageRollbackPhase = "Snapshotting"
+
+	// ImageRollbackAvailable - the snapshot has been taken, reverting the image restores Jenkins home from it
+	ImageRollbackAvailable ImageRollbackPhase = "Available"
+
+	// ImageRollbackRollingBack - the image has been reverted, Jenkins home is being recreated from the snapshot
+	ImageRollbackRollingBack ImageRollbackPhase = "RollingBack"
+
+	// ImageRollbackRolledBack - Jenkins home has been recreated from the snapshot
+	ImageRollbackRolledBack ImageRollbackPhase = "RolledBack"
+)
+
+// ImageRollbackStatus is the Jenkins home snapshot taken before Jenkins master image upgrade.
+type ImageRollbackStatus struct {
+	// Phase is the current phase of the rollback
+	Phase ImageRollbackPhase `json:"phase"`
+
+	// PreviousImage is Jenkins master image before the upgrade
+	PreviousImage string `json:"previousImage"`
+
+	// Image is Jenkins master image after the upgrade
+	Image string `json:"image"`
+
+	// VolumeSnapshotName is the name of the VolumeSnapshot of Jenkins home
+	VolumeSnapshotName string `json:"volumeSnapshotName"`
+
+	// ExpirationTime is the time the snapshot is deleted and the image can't be rolled back anymore
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+}
+
+// VolumeResizePhase is the phase of PersistentVolumeClaim expansion.
+type VolumeResizePhase string
+
+const (
+	// VolumeResizeResizing - the volume is being expanded by the storage provider
+	VolumeResizeResizing VolumeResizePhase = "Resizing"
+
+	// VolumeResizeFileSystemResizePending - the volume has been expanded, the file system is resized when the volume
+	// is mounted again
+	VolumeResizeFileSystemResizePending VolumeResizePhase = "FileSystemResizePending"
+
+	// VolumeResizeCompleted - the capacity of the volume matches the requested size
+	VolumeResizeCompleted VolumeResizePhase = "Completed"
+)
+
+// VolumeResize is the progress of PersistentVolumeClaim expansion.
+type VolumeResize struct {
+	// Phase is the current phase of the expansion
+	Phase VolumeResizePhase `json:"phase"`
+
+	// RequestedSize is the size the claim is expanded to
+	RequestedSize resource.Quantity `json:"requestedSize"`
+
+	// Capacity is the last observed capacity of the claim
+	// +optional
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+
+	// StartTime is the time the expansion has been requested
+	StartTime metav1.Time `json:"startTime"`
+
+	// CompletionTime is the time the capacity of the claim reached the requested size
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// PodRestarted is set when Jenkins master pod has been restarted to finish the file system resize
+	// +optional
+	PodRestarted bool `json:"podRestarted,omitempty"`
+}
+
+// VolumeMigrationPhase is the phase of Jenkins home migration to another storage class.
+type VolumeMigrationPhase string
+
+const (
+	// VolumeMigrationCopying - Jenkins master pod is stopped and the data is copied to the new claim by the migration Job
+	VolumeMigrationCopying VolumeMigrationPhase = "Copying"
+
+	// VolumeMigrationSwitched - Jenkins master pod uses the new claim, the old claim is deleted once Jenkins is ready
+	// and spec.master.jenkinsHomePersistentVolumeClaim.previousClaimRetention has elapsed
+	VolumeMigrationSwitched VolumeMigrationPhase = "Switched"
+
+	// VolumeMigrationCompleted - the old claim has been deleted
+	VolumeMigrationCompleted VolumeMigrationPhase = "Completed"
+
+	// VolumeMigrationFailed - the migration Job failed, Jenkins master pod keeps using the old claim
+	VolumeMigrationFailed VolumeMigrationPhase = "Failed"
+)
+
+// VolumeMigration is the progress of Jenkins home migration to another storage class.
+type VolumeMigration struct {
+	// Phase is the current phase of the migration
+	Phase VolumeMigrationPhase `json:"phase"`
+
+	// StorageClassName is the storage class Jenkins home is migrated to
+	StorageClassName string `json:"storageClassName"`
+
+	// SourceClaimName is the PersistentVolumeClaim the data is copied from
+	SourceClaimName string `json:"sourceClaimName"`
+
+	// TargetClaimName is the PersistentVolumeClaim the data is copied to
+	TargetClaimName string `json:"targetClaimName"`
+
+	// StartTime is the time the migration has been started
+	StartTime metav1.Time `json:"startTime"`
+
+	// CompletionTime is the time the old claim has been deleted
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message describes why the migration failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// GitRepositoryStatus is the latest pull of the Git repository with Configuration as Code.
+type GitRepositoryStatus struct {
+	// URL is the URL of the pulled Git repository
+	URL string `json:"url"`
+
+	// Branch is the pulled branch
+	Branch string `json:"branch"`
+
+	// Path is the directory of the Git repository with the YAML files
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Revision is the commit of the YAML files in the managed ConfigMap
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// LastSyncTime is the time of the latest pull
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Message is the error of the latest pull, the ConfigMap keeps the YAML files of the previous revision
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PodRestarts counts Jenkins master pod restarts by their source.
+type PodRestarts struct {
+	// Operator is the number of restarts made by the operator to apply Jenkins CR, e.g. changed Jenkins CR, an operator
+	// upgrade or changed plugins
+	// +optional
+	Operator uint64 `json:"operator,omitempty"`
+
+	// Kubernetes is the number of restarts caused by the platform, e.g. a failed pod
+	// +optional
+	Kubernetes uint64 `json:"kubernetes,omitempty"`
+
+	// LastSource is the source of the latest restart
+	// +optional
+	LastSource string `json:"lastSource,omitempty"`
+
+	// LastTime is the time of the latest restart
+	// +optional
+	LastTime *metav1.Time `json:"lastTime,omitempty"`
+}
+
+// AdoptionPolicy defines how pre-existing resources not owned by the Jenkins CR are handled.
+type AdoptionPolicy string
+
+const (
+	// AdoptAdoptionPolicy takes over the pre-existing resource and sets the Jenkins CR as its owner
+	AdoptAdoptionPolicy AdoptionPolicy = "Adopt"
+	// FailAdoptionPolicy stops the reconciliation with an error
+	FailAdoptionPolicy AdoptionPolicy = "Fail"
+	// IgnoreAdoptionPolicy leaves the pre-existing resource untouched
+	IgnoreAdoptionPolicy AdoptionPolicy = "Ignore"
+)
+
+// ImmutableConfiguration defines the immutable mode of the resources managed by the operator.
+type ImmutableConfiguration struct {
+	// HistoryLimit is the number of versions of every resource kept for audit, 10 by default. The version used by
+	// Jenkins master pod is never deleted
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HistoryLimit int `json:"historyLimit,omitempty"`
+}
+
+// ConfigurationVersions are the immutable versions of the resources managed by the operator used by Jenkins master pod.
+type ConfigurationVersions struct {
+	// ScriptsConfigMap is the name of the ConfigMap version with the init scripts
+	// +optional
+	ScriptsConfigMap string `json:"scriptsConfigMap,omitempty"`
+
+	// InitConfigurationConfigMap is the name of the ConfigMap version with the init.groovy.d scripts
+	// +optional
+	InitConfigurationConfigMap string `json:"initConfigurationConfigMap,omitempty"`
+
+	// BaseConfigurationConfigMap is the name of the ConfigMap version with the base configuration groovy scripts
+	// +optional
+	BaseConfigurationConfigMap string `json:"baseConfigurationConfigMap,omitempty"`
+
+	// OperatorCredentialsSecret is the name of the Secret version with the operator credentials
+	// +optional
+	OperatorCredentialsSecret string `json:"operatorCredentialsSecret,omitempty"`
+}
+
+// ReconcileError describes the error of the latest failed reconcile loop.
+type ReconcileError struct {
+	// Message is the error message
+	Message string `json:"message"`
+
+	// Phase is the configuration phase in which the reconcile loop failed: base or user
+	Phase string `json:"phase"`
+
+	// Time is a time when the reconcile loop failed with the error the last time
+	Time metav1.Time `json:"time"`
+
+	// Count is the number of consecutive reconcile loops which failed with the same error
+	Count uint64 `json:"count"`
+}
+
+// ReconcileTimings defines time spent in the phases of a reconcile run. The run may span multiple requeued
+// reconcile loops, e.g. while waiting for Jenkins master pod.
+type ReconcileTimings struct {
+	// ResourcesEnsure is the time spent on ensuring Kubernetes resources required by Jenkins master pod
+	// +optional
+	ResourcesEnsure metav1.Duration `json:"resourcesEnsure,omitempty"`
+
+	// PodWait is the time spent on waiting for Jenkins master pod to be ready
+	// +optional
+	PodWait metav1.Duration `json:"podWait,omitempty"`
+
+	// PluginsVerify is the time spent on verifying installed plugins
+	// +optional
+	PluginsVerify metav1.Duration `json:"pluginsVerify,omitempty"`
+
+	// BaseGroovyScripts is the time spent on applying base configuration groovy scripts
+	// +optional
+	BaseGroovyScripts metav1.Duration `json:"baseGroovyScripts,omitempty"`
+
+	// UserGroovyScripts is the time spent on applying user groovy scripts and Configuration as Code
+	// +optional
+	UserGroovyScripts metav1.Duration `json:"userGroovyScripts,omitempty"`
+
+	// Total is the time from the start to the completion of the reconcile run
+	// +optional
+	Total metav1.Duration `json:"total,omitempty"`
+}
+
+// PluginsLock defines resolved plugin graph of Jenkins.
+type PluginsLock struct {
+	// SpecHash is the hash of base plugins and user plugins the lock has been recorded for
+	SpecHash string `json:"specHash"`
+
+	// Plugins is the sorted list of all installed plugins including dependencies in name:version format
+	Plugins []string `json:"plugins"`
+}
+
+// PluginUpgradePolicy defines how plugins which versions differ from the spec are handled.
+type PluginUpgradePolicy string
+
+const (
+	// PinnedPluginUpgradePolicy installs exactly the plugin versions from the spec, it's the default
+	PinnedPluginUpgradePolicy PluginUpgradePolicy = "Pinned"
+	// PatchOnlyPluginUpgradePolicy upgrades plugins to newer versions which differ only in the last version part
+	PatchOnlyPluginUpgradePolicy PluginUpgradePolicy = "PatchOnly"
+	// LatestPluginUpgradePolicy upgrades plugins to the latest version
+	LatestPluginUpgradePolicy PluginUpgradePolicy = "Latest"
+)
+
+// AvailableUpdates defines Jenkins core and plugin versions newer than the running ones.
+type AvailableUpdates struct {
+	// Core is the latest Jenkins core version when it's newer than the running one
+	// +optional
+	Core string `json:"core,omitempty"`
+
+	// Plugins are installed plugins with newer versions available in name:version format
+	// +optional
+	Plugins []string `json:"plugins,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.jenkinsVersion`
+// +kubebuilder:printcolumn:name="Seed Jobs",type=string,JSONPath=`.status.seedJobs`
+// +kubebuilder:printcolumn:name="Last Backup",type=date,JSONPath=`.status.lastBackupTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Jenkins is the Schema for the jenkins API
+// +k8s:openapi-gen=true
+type Jenkins struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the Jenkins
+	Spec JenkinsSpec `json:"spec,omitempty"`
+
+	// Status defines the observed state of Jenkins
+	Status JenkinsStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JenkinsList contains a list of Jenkins
+type JenkinsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Jenkins `json:"items"`
+}
+
+// JenkinsCredentialType defines type of Jenkins credential used to seed job mechanism.
+type JenkinsCredentialType string
+
+const (
+	// NoJenkinsCredentialCredentialType define none Jenkins credential type
+	NoJenkinsCredentialCredentialType JenkinsCredentialType = ""
+	// BasicSSHCredentialType define basic SSH Jenkins credential type
+	BasicSSHCredentialType JenkinsCredentialType = "basicSSHUserPrivateKey"
+	// UsernamePasswordCredentialType define username & password Jenkins credential type
+	UsernamePasswordCredentialType JenkinsCredentialType = "usernamePassword"
+	GithubAppCredentialType        JenkinsCredentialType = "githubApp"
+	// ExternalCredentialType defines other credential type
+	ExternalCredentialType JenkinsCredentialType = "external"
+)
+
+// AllowedJenkinsCredentialMap contains all allowed Jenkins credentials types.
+var AllowedJenkinsCredentialMap = map[string]string{
+	string(NoJenkinsCredentialCredentialType): "",
+	string(BasicSSHCredentialType):            "",
+	string(UsernamePasswordCredentialType):    "",
+	string(GithubAppCredentialType):           "",
+	string(ExternalCredentialType):            "",
+}
+
+// Jobs defines Jenkins folders and views used to organize jobs.
+type Jobs struct {
+	// Folders is a list of Jenkins folders created in the Jenkins root
+	// +optional
+	Folders []Folder `json:"folders,omitempty"`
+
+	// Views is a list of Jenkins list views created in the Jenkins root
+	// +optional
+	Views []View `json:"views,omitempty"`
+
+	// DefaultViews configures the list views of seed jobs and non-seed jobs created by the base configuration
+	// +optional
+	DefaultViews *DefaultViews `json:"defaultViews,omitempty"`
+}
+
+// Folder defines Jenkins folder.
+type Folder struct {
+	// Name is the Jenkins folder name
+	Name string `json:"name"`
+
+	// Description is the Jenkins folder description
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// View defines Jenkins list view.
+type View struct {
+	// Name is the Jenkins view name
+	Name string `json:"name"`
+
+	// Description is the Jenkins view description
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// IncludeRegex is the Java regular expression used to select jobs shown in the view
+	// +optional
+	IncludeRegex string `json:"includeRegex,omitempty"`
+}
+
+// DefaultViews defines the list views created by the base configuration.
+type DefaultViews struct {
+	// Disabled turns off creating the default views
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// SeedJobs overrides the view of seed jobs, 'seed-jobs' view is created by default
+	// +optional
+	SeedJobs *DefaultView `json:"seedJobs,omitempty"`
+
+	// NonSeedJobs overrides the view of jobs other than seed jobs, 'non-seed-jobs' view is created by default
+	// +optional
+	NonSeedJobs *DefaultView `json:"nonSeedJobs,omitempty"`
+}
+
+// DefaultView defines the name and the jobs of a default list view.
+type DefaultView struct {
+	// Name is the Jenkins view name, the default name is used when not set
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// IncludeRegex is the Java regular expression used to select jobs shown in the view, spec.values or
+	// the default regex is used when not set
+	// +optional
+	IncludeRegex string `json:"includeRegex,omitempty"`
+}
+
+// SeedJobAgentSecret defines the inbound secret of the seed job agent, the static agent registered in Jenkins by the operator.
+type SeedJobAgentSecret struct {
+	// RequireManagedSecret stores the inbound secret in a Kubernetes Secret managed by the operator, the agent pod
+	// reads it by secret reference instead of the plain JENKINS_SECRET value in the agent Deployment
+	// +optional
+	RequireManagedSecret bool `json:"requireManagedSecret,omitempty"`
+
+	// RotationInterval is how often the inbound secret is rotated, e.g. 720h. Jenkins derives the secret from
+	// the agent name so the agent is registered under a new name, the previous agent doesn't accept new builds
+	// and it's removed once its running builds finish. The secret isn't rotated when not set.
+	// +optional
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+}
+
+// SeedJobAgentStatus is the observed state of the seed job agent inbound secret rotation.
+type SeedJobAgentStatus struct {
+	// NodeName is the Jenkins node name of the current seed job agent, it changes with every secret rotation
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// SecretRotationTime is the time the secret of the current seed job agent has been issued
+	// +optional
+	SecretRotationTime *metav1.Time `json:"secretRotationTime,omitempty"`
+
+	// RetiringNodeName is the Jenkins node name of the agent with the previous secret, it doesn't accept new builds
+	// and it's removed once its running builds finish
+	// +optional
+	RetiringNodeName string `json:"retiringNodeName,omitempty"`
+}
+
+// SeedJobAgentWorkspaceCache defines PersistentVolumeClaim of seed job agent workspaces.
+type SeedJobAgentWorkspaceCache struct {
+	// Size is the requested storage size of the PersistentVolumeClaim
+	Size resource.Quantity `json:"size"`
+
+	// StorageClassName is the storage class of the PersistentVolumeClaim, the default one is used when not set
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+}
+
+// SeedJobAgentTemplate defines the customization of the seed job agent pod.
+type SeedJobAgentTemplate struct {
+	// Image is the image of the seed job agent container, it takes precedence over spec.seedJobAgentImage
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Resources are compute resources required by the seed job agent container
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector is a selector which must be true for the seed job agent pod to fit on a node,
+	// spec.master.nodeSelector is used when not set
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations of the seed job agent pod, spec.master.tolerations are used when not set
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// JavaOpts are JVM options of the seed job agent passed in JAVA_OPTS environment variable
+	// +optional
+	JavaOpts string `json:"javaOpts,omitempty"`
+
+	// Labels are additional labels of the seed job agent pod
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// JenkinsHomePersistentVolumeClaim defines PersistentVolumeClaim of Jenkins home.
+type JenkinsHomePersistentVolumeClaim struct {
+	// Size is the requested storage size of the PersistentVolumeClaim, it can only grow
+	Size resource.Quantity `json:"size"`
+
+	// StorageClassName is the storage class of the PersistentVolumeClaim, the default one is used when not set.
+	// When it changes Jenkins home is migrated to a new PersistentVolumeClaim of the storage class.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// MigrationImage is the image of the Job which copies Jenkins home to the PersistentVolumeClaim of another
+	// storage class, rsync is used when the image provides it, busybox:1.35 by default
+	// +optional
+	MigrationImage string `json:"migrationImage,omitempty"`
+
+	// PreviousClaimRetention is how long the PersistentVolumeClaim Jenkins home has been migrated from is kept once
+	// Jenkins is ready with the new one, 24h by default. The claim is kept until it's deleted manually when set to 0s.
+	// +optional
+	PreviousClaimRetention *metav1.Duration `json:"previousClaimRetention,omitempty"`
+}
+
+// ImageRollback defines how long Jenkins home snapshot taken before Jenkins master image upgrade is kept.
+type ImageRollback struct {
+	// Window is how long the image can be reverted after the upgrade, e.g. 24h
+	Window metav1.Duration `json:"window"`
+
+	// VolumeSnapshotClassName is the VolumeSnapshotClass of the snapshot, the default one is used when not set
+	// +optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// SCMWebhook defines how SCM webhook requests are validated.
+type SCMWebhook struct {
+	// SecretName is the name of Secret with the 'secret' key used to validate GitHub webhook signatures
+	// and GitLab webhook tokens
+	SecretName string `json:"secretName"`
+}
+
+// SeedJob defines configuration for seed job
+// More info: https://jenkinsci.github.io/kubernetes-operator/docs/getting-started/latest/configuration/#configure-seed-jobs-and-pipelines.
+type SeedJob struct {
+	// ID is the unique seed job name
+	ID string `json:"id,omitempty"`
+
+	// CredentialID is the Kubernetes secret name which stores repository access credentials
+	CredentialID string `json:"credentialID,omitempty"`
+
+	// CredentialFolder is the Jenkins folder where the seed job and its credential are created, the credential
+	// is stored in the folder credential store instead of the global one so it's visible only to jobs in the folder
+	// +optional
+	CredentialFolder string `json:"credentialFolder,omitempty"`
+
+	// Folder is the Jenkins folder created by the operator where the seed job is placed, the jobs generated by
+	// the seed job with relative names are created in the folder too, it has to match credentialFolder when both are set
+	// +optional
+	Folder string `json:"folder,omitempty"`
+
+	// EphemeralCredential mounts the credential Secret into the seed job agent in a memory-backed volume instead of
+	// creating Jenkins credential, the credential is used by git in the agent and it's never stored in Jenkins. It can
+	// be used only with basicSSHUserPrivateKey or usernamePassword credential type. The basicSSHUserPrivateKey Secret
+	// has to contain knownHosts key with the SSH host keys of the repository servers.
+	// +optional
+	EphemeralCredential bool `json:"ephemeralCredential,omitempty"`
+
+	// Description is the description of the seed job
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Targets is the repository path where are seed job definitions
+	Targets string `json:"targets,omitempty"`
+
+	// RepositoryBranch is the repository branch where are seed job definitions
+	RepositoryBranch string `json:"repositoryBranch,omitempty"`
+
+	// RepositoryURL is the repository access URL. Can be SSH or HTTPS.
+	RepositoryURL string `json:"repositoryUrl,omitempty"`
+
+	// JenkinsCredentialType is the https://jenkinsci.github.io/kubernetes-credentials-provider-plugin/ credential type
+	// +optional
+	JenkinsCredentialType JenkinsCredentialType `json:"credentialType,omitempty"`
+
+	// BitbucketPushTrigger is used for Bitbucket web hooks
+	// +optional
+	BitbucketPushTrigger bool `json:"bitbucketPushTrigger"`
+
+	// GitHubPushTrigger is used for GitHub web hooks
+	// +optional
+	GitHubPushTrigger bool `json:"githubPushTrigger"`
+
+	// GitLabPushTrigger is used for GitLab web hooks
+	// +optional
+	GitLabPushTrigger bool `json:"gitlabPushTrigger"`
+
+	// BuildPeriodically is setting for scheduled trigger
+	// +optional
+	BuildPeriodically string `json:"buildPeriodically"`
+
+	// PollSCM is setting for polling changes in SCM
+	// +optional
+	PollSCM string `json:"pollSCM"`
+
+	// IgnoreMissingFiles is setting for Job DSL API plugin to ignore files that miss
+	// +optional
+	IgnoreMissingFiles bool `json:"ignoreMissingFiles"`
+
+	// AdditionalClasspath is setting for Job DSL API plugin to set Additional Classpath
+	// +optional
+	AdditionalClasspath string `json:"additionalClasspath"`
+
+	// FailOnMissingPlugin is setting for Job DSL API plugin that fails job if required plugin is missing
+	// +optional
+	FailOnMissingPlugin bool `json:"failOnMissingPlugin"`
+
+	// UnstableOnDeprecation is setting for Job DSL API plugin that sets build status as unstable if build using deprecated features
+	// +optional
+	UnstableOnDeprecation bool `json:"unstableOnDeprecation"`
+
+	// BranchSource makes the seed job generate a multibranch pipeline or a GitHub organization folder instead of
+	// a Job DSL seed job, targets are not used then
+	// +optional
+	BranchSource *SeedJobBranchSource `json:"branchSource,omitempty"`
+
+	// DeepValidation verifies the repository URL, branch and credential by git ls-remote from a short-lived pod,
+	// the wrong credential is reported by validation before the seed job fails
+	// +optional
+	DeepValidation *SeedJobDeepValidation `json:"deepValidation,omitempty"`
+}
+
+// SeedJobDeepValidation defines the verification of seed job repository access from a short-lived pod.
+type SeedJobDeepValidation struct {
+	// Image is the image with git used by the validation pod, defaults to alpine/git
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// SeedJobBranchSourceKind is the kind of Jenkins job generated from a branch source.
+type SeedJobBranchSourceKind string
+
+const (
+	// MultibranchPipelineBranchSourceKind generates a multibranch pipeline of a single repository
+	MultibranchPipelineBranchSourceKind SeedJobBranchSourceKind = "MultibranchPipeline"
+	// GitHubOrganizationBranchSourceKind generates an organization folder with multibranch pipelines of all
+	// repositories of a GitHub organization or user
+	GitHubOrganizationBranchSourceKind SeedJobBranchSourceKind = "GitHubOrganization"
+)
+
+// SeedJobBranchSource defines the branch source of a multibranch pipeline or a GitHub organization folder.
+type SeedJobBranchSource struct {
+	// Kind is the kind of generated Jenkins job
+	// +kubebuilder:validation:Enum=MultibranchPipeline;GitHubOrganization
+	Kind SeedJobBranchSourceKind `json:"kind"`
+
+	// Owner is the GitHub organization or user. It's required for GitHubOrganization, a MultibranchPipeline with
+	// owner uses GitHub branch source for the repository, otherwise Git branch source for the seed job repositoryUrl
+	// +optional
+	Owner string `json:"owner,omitempty"`
+
+	// Repository is the GitHub repository name of MultibranchPipeline with GitHub branch source
+	// +optional
+	Repository string `json:"repository,omitempty"`
+
+	// RepositoryPattern is the regular expression of GitHub repository names included in GitHubOrganization folder
+	// Defaults to all repositories.
+	// +optional
+	RepositoryPattern string `json:"repositoryPattern,omitempty"`
+
+	// ScriptPath is the path of the pipeline script in the repositories
+	// Defaults to Jenkinsfile.
+	// +optional
+	ScriptPath string `json:"scriptPath,omitempty"`
+
+	// ScanInterval tells how often the branches are scanned when not triggered by web hooks, one of 1m, 2m, 5m,
+	// 10m, 15m, 20m, 25m, 30m, 1h, 2h, 4h, 8h, 12h, 1d, 2d, 1w, 2w, 4w
+	// Defaults to 1d.
+	// +optional
+	ScanInterval string `json:"scanInterval,omitempty"`
+
+	// Discovery defines which heads are discovered in the repositories
+	// +optional
+	Discovery SeedJobBranchDiscovery `json:"discovery,omitempty"`
+}
+
+// SeedJobBranchDiscovery defines discovery behaviors of a branch source. Only branches are discovered if
+// no behavior is enabled.
+type SeedJobBranchDiscovery struct {
+	// Branches discovers branches, the branches filed as pull requests are excluded when pull requests are discovered
+	// +optional
+	Branches bool `json:"branches,omitempty"`
+
+	// PullRequestsFromOrigin discovers pull requests from the origin repository, GitHub branch source only
+	// +optional
+	PullRequestsFromOrigin bool `json:"pullRequestsFromOrigin,omitempty"`
+
+	// PullRequestsFromForks discovers pull requests from forks by contributors with write permission, GitHub branch
+	// source only
+	// +optional
+	PullRequestsFromForks bool `json:"pullRequestsFromForks,omitempty"`
+
+	// Tags discovers tags
+	// +optional
+	Tags bool `json:"tags,omitempty"`
+}
+
+// Handler defines a specific action that should be taken.
+type Handler struct {
+	// Exec specifies the action to take.
+	Exec *corev1.ExecAction `json:"exec,omitempty"`
+
+	// Timeout is how long the operator waits for the exec command to complete, the command is cancelled and fails
+	// when it takes longer. The command isn't limited when it's not set.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// BackupCommandOutcome is the outcome of the backup or restore command
+type BackupCommandOutcome string
+
+const (
+	// BackupCommandSucceeded - the command has completed successfully
+	BackupCommandSucceeded BackupCommandOutcome = "Succeeded"
+	// BackupCommandFailed - the command has failed
+	BackupCommandFailed BackupCommandOutcome = "Failed"
+	// BackupCommandTimedOut - the command has been cancelled after the handler timeout
+	BackupCommandTimedOut BackupCommandOutcome = "TimedOut"
+)
+
+// BackupCommandStatus is the outcome of the last backup or restore command executed by the operator.
+type BackupCommandStatus struct {
+	// Action is the executed action: backup, restore, getLatest or list
+	Action string `json:"action"`
+
+	// Outcome is Succeeded, Failed or TimedOut
+	Outcome BackupCommandOutcome `json:"outcome"`
+
+	// StartTime is a time when the command has been started
+	StartTime metav1.Time `json:"startTime"`
+
+	// Duration is how long the command has been running
+	Duration metav1.Duration `json:"duration"`
+
+	// Message is the error of the failed command
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Backup defines configuration of Jenkins backup.
+type Backup struct {
+	// ContainerName is the container name responsible for backup operation, it's not required when spec.backup.s3 is set
+	// +optional
+	ContainerName string `json:"containerName"`
+
+	// Action defines action which performs backup in backup container sidecar
+	// +optional
+	Action Handler `json:"action"`
+
+	// Interval tells how often make backup in seconds
+	// Defaults to 30.
+	Interval uint64 `json:"interval"`
+
+	// Schedule tells when make backup in Jenkins cron format e.g. "H 2 * * *", it takes precedence over interval
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// MakeBackupBeforePodDeletion tells operator to make backup before Jenkins master pod deletion
 	MakeBackupBeforePodDeletion bool `json:"makeBackupBeforePodDeletion"`
+
+	// DryRun tells operator to estimate backup size and duration every interval instead of making backups,
+	// the estimate is reported in status.backupEstimate
+	// +optional
+	DryRun *BackupDryRun `json:"dryRun,omitempty"`
+
+	// Destinations are the additional backup destinations (e.g. S3 next to a local volume), every destination
+	// action is executed after the main backup action with the same backup number
+	// +optional
+	Destinations []BackupDestination `json:"destinations,omitempty"`
+
+	// ParallelDestinations tells operator to execute the backup destinations actions in parallel instead of
+	// in sequence
+	// +optional
+	ParallelDestinations bool `json:"parallelDestinations,omitempty"`
+
+	// S3 tells operator to make and restore backups of Jenkins home itself using S3-compatible object storage,
+	// backup and restore containers and actions are not required then
+	// +optional
+	S3 *S3Backup `json:"s3,omitempty"`
+}
+
+// S3Backup defines S3-compatible object storage where operator uploads Jenkins backups.
+type S3Backup struct {
+	// Endpoint is the URL of S3-compatible object storage e.g. https://s3.eu-west-1.amazonaws.com or http://minio:9000,
+	// buckets are addressed in path-style
+	Endpoint string `json:"endpoint"`
+
+	// Region is the region of the bucket used to sign requests
+	// Defaults to us-east-1.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Bucket is the name of bucket where backups are stored
+	Bucket string `json:"bucket"`
+
+	// Prefix is the key prefix of backups in the bucket, backups are stored as <prefix>/<backup number>.tar.gz
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// CredentialsSecretName is the name of Secret in the Jenkins CR namespace with accessKeyId and secretAccessKey data
+	CredentialsSecretName string `json:"credentialsSecretName"`
+
+	// KeepLast is the number of the latest backups kept in the bucket, older backups are deleted after every backup
+	// Defaults to 0 which keeps all backups.
+	// +optional
+	KeepLast uint64 `json:"keepLast,omitempty"`
+
+	// Excludes are the paths relative to Jenkins home which are not backed up e.g. workspace
+	// +optional
+	Excludes []string `json:"excludes,omitempty"`
+}
+
+// BackupDestination defines an additional Jenkins backup destination.
+type BackupDestination struct {
+	// Name is the unique name of backup destination
+	Name string `json:"name"`
+
+	// ContainerName is the container name responsible for backup operation
+	// Defaults to spec.backup.containerName.
+	// +optional
+	ContainerName string `json:"containerName,omitempty"`
+
+	// Action defines action which performs backup to the destination in backup container sidecar
+	Action Handler `json:"action"`
+}
+
+// BackupDestinationStatus is the status of an additional Jenkins backup destination.
+type BackupDestinationStatus struct {
+	// Name is the backup destination name
+	Name string `json:"name"`
+
+	// LastBackup is the latest backup number made to the destination
+	// +optional
+	LastBackup uint64 `json:"lastBackup,omitempty"`
+
+	// LastBackupTime is a time when the latest backup to the destination has been made
+	// +optional
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+
+	// Error is the error message of the latest failed backup to the destination
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// BackupDryRun defines how Jenkins backup size and duration are estimated.
+type BackupDryRun struct {
+	// Excludes are the paths relative to Jenkins home which are not included in the backup
+	// +optional
+	Excludes []string `json:"excludes,omitempty"`
+
+	// ThroughputBytesPerSecond is the expected backup transfer throughput used to estimate backup duration
+	// Defaults to 52428800 (50 MiB/s).
+	// +optional
+	ThroughputBytesPerSecond int64 `json:"throughputBytesPerSecond,omitempty"`
+}
+
+// BackupEstimate is the estimated size and duration of Jenkins backup.
+type BackupEstimate struct {
+	// SizeBytes is the estimated backup size in bytes
+	SizeBytes int64 `json:"sizeBytes"`
+
+	// DurationSeconds is the estimated backup duration in seconds
+	DurationSeconds int64 `json:"durationSeconds"`
+
+	// EstimatedTime is a time when the estimate has been made
+	EstimatedTime metav1.Time `json:"estimatedTime"`
 }
 
 // Restore defines configuration of Jenkins backup restore operation.
 type Restore struct {
-	// ContainerName is the container name responsible for restore backup operation
+	// ContainerName is the container name responsible for restore backup operation, it's not required when
+	// spec.backup.s3 is set
+	// +optional
 	ContainerName string `json:"containerName"`
 
 	// Action defines action which performs restore backup in restore container sidecar
+	// +optional
 	Action Handler `json:"action"`
 
 	// GetLatestAction defines action which returns the latest backup number. If there is no backup "-1" should be
@@ -642,6 +1959,83 @@ type Restore struct {
 	// RecoveryOnce if want to restore specific backup set this field and then Jenkins will be restarted and desired backup will be restored
 	// +optional
 	RecoveryOnce uint64 `json:"recoveryOnce,omitempty"`
+
+	// Rehearsal tells operator to periodically restore the latest backup into a temporary Jenkins CR, check if
+	// the restored Jenkins is healthy and then remove it, the result is reported in status.restoreRehearsal
+	// +optional
+	Rehearsal *RestoreRehearsal `json:"rehearsal,omitempty"`
+
+	// BackupNumber tells operator to restore the given backup instead of the latest one when Jenkins master pod is
+	// recreated, spec.restore.recoveryOnce takes precedence
+	// +optional
+	BackupNumber uint64 `json:"backupNumber,omitempty"`
+
+	// DryRun tells operator to list the jobs and configuration files of the backup which would be restored instead
+	// of restoring it, live Jenkins home isn't changed and spec.restore.recoveryOnce doesn't restart Jenkins master
+	// pod. The result is reported in status.restoreDryRun and by notification.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// ListAction defines action which prints the files of the backup passed as the argument in restore container
+	// sidecar, one path relative to Jenkins home per line. It's required by spec.restore.dryRun when
+	// spec.restore.containerName is set.
+	// +optional
+	ListAction Handler `json:"listAction"`
+}
+
+// RestoreDryRunStatus is the content of the backup which would be restored, listed by restore dry run.
+type RestoreDryRunStatus struct {
+	// BackupNumber is the listed backup number
+	BackupNumber uint64 `json:"backupNumber"`
+
+	// Time is a time when the backup has been listed
+	Time metav1.Time `json:"time"`
+
+	// Jobs are the full names of the jobs in the backup
+	// +optional
+	Jobs []string `json:"jobs,omitempty"`
+
+	// ConfigurationFiles are the configuration files in the root of Jenkins home in the backup
+	// +optional
+	ConfigurationFiles []string `json:"configurationFiles,omitempty"`
+
+	// Error is the error message if the backup couldn't be listed
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// RestoreRehearsal defines how often backups are verified by restoring them into a temporary Jenkins CR.
+type RestoreRehearsal struct {
+	// Interval tells how often make restore rehearsal in seconds
+	// Defaults to 86400.
+	// +optional
+	Interval uint64 `json:"interval,omitempty"`
+
+	// Timeout is the time in seconds in which the temporary Jenkins has to restore the backup and become healthy
+	// Defaults to 1800.
+	// +optional
+	Timeout uint64 `json:"timeout,omitempty"`
+}
+
+// RestoreRehearsalStatus is the result of the latest restore rehearsal.
+type RestoreRehearsalStatus struct {
+	// BackupNumber is the backup number restored during the rehearsal
+	BackupNumber uint64 `json:"backupNumber"`
+
+	// Succeeded tells if the backup has been restored and the temporary Jenkins was healthy
+	// +optional
+	Succeeded bool `json:"succeeded,omitempty"`
+
+	// Message describes the rehearsal result
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is a time when the rehearsal has been started
+	StartTime metav1.Time `json:"startTime"`
+
+	// CompletionTime is a time when the rehearsal has been completed, it's not set while the rehearsal is in progress
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
 // AppliedGroovyScript is the applied groovy script in Jenkins by the operator.
@@ -656,11 +2050,215 @@ type AppliedGroovyScript struct {
 	Hash string `json:"hash"`
 }
 
+// GroovyScriptResult is the result of the last execution of the groovy script in Jenkins by the operator.
+type GroovyScriptResult struct {
+	// ConfigurationType is the name of the configuration type(base-groovy, user-groovy, user-casc, seed-jobs)
+	ConfigurationType string `json:"configurationType"`
+	// Source is the name of source where is located groovy script
+	Source string `json:"source"`
+	// Name is the name of the groovy script
+	Name string `json:"name"`
+	// Hash is the hash of the groovy script and secrets which it uses
+	Hash string `json:"hash"`
+	// Succeeded tells if the groovy script has been executed successfully
+	Succeeded bool `json:"succeeded"`
+	// Error is the error of the failed groovy script with the tail of its output when spec.groovyScriptOutput
+	// is enabled
+	// +optional
+	Error string `json:"error,omitempty"`
+	// Time is a time when the groovy script has been executed
+	Time metav1.Time `json:"time"`
+}
+
+// ConfigurationProgress defines progress of applying groovy scripts.
+type ConfigurationProgress struct {
+	// ConfigurationType is the configuration type of the scripts being applied (base-groovy, base-user-groovy)
+	ConfigurationType string `json:"configurationType"`
+
+	// Applied is the number of applied groovy scripts of the configuration type
+	Applied int `json:"applied"`
+
+	// Total is the number of groovy scripts of the configuration type
+	Total int `json:"total"`
+
+	// LastScript is the last executed groovy script in source/name format
+	// +optional
+	LastScript string `json:"lastScript,omitempty"`
+
+	// LastScriptOutput is the tail of the output of the last executed groovy script when spec.groovyScriptOutput
+	// is enabled
+	// +optional
+	LastScriptOutput string `json:"lastScriptOutput,omitempty"`
+
+	// LastScriptError is the error of the last executed groovy script when it has failed
+	// +optional
+	LastScriptError string `json:"lastScriptError,omitempty"`
+}
+
 // SecretRef is reference to Kubernetes secret.
 type SecretRef struct {
 	Name string `json:"name"`
 }
 
+// ExternalJenkins defines the existing Jenkins instance configured by the operator.
+type ExternalJenkins struct {
+	// URL is the Jenkins URL reachable from the operator and the seed job agent, e.g. https://jenkins.example.com/
+	URL string `json:"url"`
+
+	// CredentialsSecret is the name of Kubernetes Secret with the 'user' and 'password' keys of Jenkins user
+	// with the Overall/Administer permission, the password can be an API token
+	CredentialsSecret SecretRef `json:"credentialsSecret"`
+}
+
+// UpdateCenter defines Jenkins update center.
+type UpdateCenter struct {
+	// URL is the update center JSON URL, e.g. https://updates.example.com/update-center.json
+	URL string `json:"url"`
+
+	// ClientCertificate is the Kubernetes secret with PEM encoded client certificate (tls.crt) and private key (tls.key)
+	// used for mutual TLS authentication to the update center
+	// +optional
+	ClientCertificate *SecretRef `json:"clientCertificate,omitempty"`
+}
+
+// BuildRetention defines how long builds and their artifacts are kept, 0 means no limit.
+type BuildRetention struct {
+	// DaysToKeep is the number of days to keep builds
+	// +optional
+	DaysToKeep int `json:"daysToKeep,omitempty"`
+
+	// NumToKeep is the max number of builds to keep per job
+	// +optional
+	NumToKeep int `json:"numToKeep,omitempty"`
+
+	// ArtifactDaysToKeep is the number of days to keep artifacts of builds
+	// +optional
+	ArtifactDaysToKeep int `json:"artifactDaysToKeep,omitempty"`
+
+	// ArtifactNumToKeep is the max number of builds per job with kept artifacts
+	// +optional
+	ArtifactNumToKeep int `json:"artifactNumToKeep,omitempty"`
+}
+
+// AgentPortPolicy defines how the inbound TCP agent port of Jenkins is chosen.
+type AgentPortPolicy string
+
+const (
+	// FixedAgentPortPolicy makes Jenkins listen for agents on the port exposed by the slave service
+	FixedAgentPortPolicy AgentPortPolicy = "Fixed"
+	// RandomAgentPortPolicy makes Jenkins listen for agents on a random port, agents have to reach Jenkins master
+	// pod directly
+	RandomAgentPortPolicy AgentPortPolicy = "Random"
+	// DisabledAgentPortPolicy disables the inbound TCP agent port, only WebSocket agents can connect
+	DisabledAgentPortPolicy AgentPortPolicy = "Disabled"
+)
+
+// AgentListener defines the inbound TCP agent listener of Jenkins.
+type AgentListener struct {
+	// PortPolicy defines how the inbound TCP agent port is chosen
+	// Defaults to Fixed.
+	// +kubebuilder:validation:Enum=Fixed;Random;Disabled
+	// +optional
+	PortPolicy AgentPortPolicy `json:"portPolicy,omitempty"`
+
+	// Port is the inbound TCP agent port of Fixed policy, it's the Jenkins master container port targeted by
+	// the slave service. Changing it restarts Jenkins master pod.
+	// Defaults to 50000.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// Protocols are the enabled agent protocols, e.g. JNLP4-connect and Ping. The insecure JNLP-connect,
+	// JNLP2-connect, JNLP3-connect and CLI-connect protocols can't be enabled.
+	// Defaults to the protocols enabled by Jenkins.
+	// +optional
+	Protocols []string `json:"protocols,omitempty"`
+}
+
+// ReadinessGate is a condition of Jenkins master pod, exactly one of HTTPGet and Plugins has to be set.
+type ReadinessGate struct {
+	// Name identifies the gate in logs
+	Name string `json:"name"`
+
+	// HTTPGet requires the endpoint of Jenkins master pod to respond with a status code from 200 to 399
+	// +optional
+	HTTPGet *ReadinessGateHTTPGet `json:"httpGet,omitempty"`
+
+	// Plugins requires the plugins to be installed, enabled and active in Jenkins
+	// +optional
+	Plugins []string `json:"plugins,omitempty"`
+}
+
+// ReadinessGateHTTPGet is an HTTP GET request sent by the operator to Jenkins master pod IP.
+type ReadinessGateHTTPGet struct {
+	// Path is the path of the request, e.g. /ready
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Port is the port of Jenkins master pod
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// Scheme is HTTP or HTTPS, the certificate of HTTPS endpoint isn't verified
+	// Defaults to HTTP.
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	// +optional
+	Scheme corev1.URIScheme `json:"scheme,omitempty"`
+}
+
+// ExtraResource is a Kubernetes object applied and owned by the operator, exactly one of Manifest and ConfigMapRef
+// has to be set.
+type ExtraResource struct {
+	// Manifest is the raw Kubernetes object
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:EmbeddedResource
+	Manifest *runtime.RawExtension `json:"manifest,omitempty"`
+
+	// ConfigMapRef is the ConfigMap which values are YAML manifests, a value may contain multiple documents
+	// +optional
+	ConfigMapRef *ConfigMapRef `json:"configMapRef,omitempty"`
+}
+
+// PrometheusRule defines alerts of the Jenkins instance generated as Prometheus Operator PrometheusRule.
+type PrometheusRule struct {
+	// Labels are added to the PrometheusRule object, e.g. to match ruleSelector of Prometheus
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// AlertLabels are added to every alert, e.g. severity or team used by Alertmanager routing
+	// +optional
+	AlertLabels map[string]string `json:"alertLabels,omitempty"`
+
+	// BackupStaleAfter is how long there can be no new backup before JenkinsBackupStale alert fires, defaults to 24h,
+	// the alert is generated only when spec.backup is configured
+	// +optional
+	BackupStaleAfter *metav1.Duration `json:"backupStaleAfter,omitempty"`
+
+	// NotReadyFor is how long the instance can be not ready before JenkinsNotReady alert fires, defaults to 15m
+	// +optional
+	NotReadyFor *metav1.Duration `json:"notReadyFor,omitempty"`
+
+	// RestartStormRestarts is the number of Jenkins master pod restarts within RestartStormWindow which fires
+	// JenkinsRestartStorm alert, defaults to 3
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RestartStormRestarts int32 `json:"restartStormRestarts,omitempty"`
+
+	// RestartStormWindow is the time window in which Jenkins master pod restarts are counted, defaults to 1h
+	// +optional
+	RestartStormWindow *metav1.Duration `json:"restartStormWindow,omitempty"`
+}
+
+// ExtraResourceReference identifies an object applied from spec.extraResources.
+type ExtraResourceReference struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+}
+
 // ConfigMapRef is reference to Kubernetes ConfigMap.
 type ConfigMapRef struct {
 	Name string `json:"name"`
@@ -670,14 +2268,89 @@ type ConfigMapRef struct {
 type Customization struct {
 	Secret         SecretRef      `json:"secret"`
 	Configurations []ConfigMapRef `json:"configurations"`
+
+	// Templating enables Go template placeholders in the ConfigMaps resolved by the operator before the scripts are
+	// applied, e.g. {{ .Namespace }}, {{ fqdn .HTTPServiceName }}, {{ secret "name" "key" }} or {{ b64 "value" }}.
+	// The secret function reads only the Secrets referenced by spec.groovyScripts.secret and
+	// spec.configurationAsCode.secret
+	// +optional
+	Templating bool `json:"templating,omitempty"`
 }
 
 // GroovyScripts defines configuration of Jenkins customization via groovy scripts.
 type GroovyScripts struct {
 	Customization `json:",inline"`
+
+	// Parallelism is the maximum number of groovy scripts applied concurrently, scripts are applied one by one
+	// when it's not set
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Parallelism int `json:"parallelism,omitempty"`
+
+	// Dependencies declares which groovy scripts have to be applied before the script, keys and values are the
+	// ConfigMap keys, e.g. jobs.groovy: [credentials.groovy]. Scripts with dependencies are applied one by one
+	// after the scripts they depend on.
+	// +optional
+	Dependencies map[string][]string `json:"dependencies,omitempty"`
+}
+
+// ReadinessCheck defines the groovy smoke test script of Jenkins.
+type ReadinessCheck struct {
+	// ConfigMapRef is the ConfigMap with the groovy script
+	ConfigMapRef ConfigMapRef `json:"configMap"`
+
+	// Key is the key of the ConfigMap with the groovy script, it can be omitted when the ConfigMap has only one key
+	// +optional
+	Key string `json:"key,omitempty"`
 }
 
 // ConfigurationAsCode defines configuration of Jenkins customization via Configuration as Code Jenkins plugin.
 type ConfigurationAsCode struct {
 	Customization `json:",inline"`
+
+	// ReloadStrategy defines how changes of the ConfigMaps and the Secret are applied to running Jenkins: Restart
+	// recreates Jenkins master pod, HotReload mounts the ConfigMaps into Jenkins master pod and reloads them by
+	// the Configuration as Code reload API. The changed ConfigMap entries are applied one by one when it's not set
+	// +kubebuilder:validation:Enum=Restart;HotReload
+	// +optional
+	ReloadStrategy ReloadStrategy `json:"reloadStrategy,omitempty"`
+
+	// GitRepository is the Git repository with Configuration as Code YAML files, the operator pulls the files
+	// periodically into a managed ConfigMap which is applied together with spec.configurationAsCode.configurations
+	// +optional
+	GitRepository *ConfigurationAsCodeGitRepository `json:"gitRepository,omitempty"`
+}
+
+// ConfigurationAsCodeGitRepository defines the Git repository with Configuration as Code YAML files.
+type ConfigurationAsCodeGitRepository struct {
+	// URL is the HTTP(S) URL of the Git repository, e.g. https://github.com/example/jenkins-config.git
+	URL string `json:"url"`
+
+	// Branch is the branch of the Git repository, master by default
+	// +optional
+	Branch string `json:"branch,omitempty"`
+
+	// Path is the directory of the Git repository with the YAML files, the root directory by default. Only *.yaml
+	// and *.yml files directly in the directory are pulled
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// CredentialsSecret is the Secret with username and password keys used to pull the Git repository, e.g. a user
+	// and a personal access token. A public repository is pulled without credentials when it's not set
+	// +optional
+	CredentialsSecret *SecretRef `json:"credentialsSecret,omitempty"`
+
+	// SyncInterval is how often the Git repository is pulled, 5m by default
+	// +optional
+	SyncInterval *metav1.Duration `json:"syncInterval,omitempty"`
 }
+
+// ReloadStrategy defines how changes of the Configuration as Code are applied to running Jenkins.
+type ReloadStrategy string
+
+const (
+	// RestartReloadStrategy recreates Jenkins master pod when the Configuration as Code changes
+	RestartReloadStrategy ReloadStrategy = "Restart"
+	// HotReloadReloadStrategy reloads the Configuration as Code mounted into Jenkins master pod by the reload API
+	HotReloadReloadStrategy ReloadStrategy = "HotReload"
+)
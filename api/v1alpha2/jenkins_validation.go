@@ -0,0 +1,74 @@
+package v1alpha2
+
+import (
+	"fmt"
+)
+
+// ValidateMutuallyExclusiveFeatures returns messages about features which can't be used together in Jenkins CR spec,
+// it's shared by the admission webhook and the reconcile loop validation
+func ValidateMutuallyExclusiveFeatures(spec JenkinsSpec) []string {
+	var messages []string
+
+	if spec.Master.SkipBaseConfiguration {
+		if len(spec.Master.BasePlugins) > 0 {
+			messages = append(messages, "spec.master.basePlugins can't be set together with spec.master.skipBaseConfiguration, base plugins aren't installed by the operator")
+		}
+		if spec.Master.LockPlugins {
+			messages = append(messages, "spec.master.lockPlugins can't be enabled together with spec.master.skipBaseConfiguration, plugins aren't installed by the operator")
+		}
+	}
+
+	if authProxy := spec.JenkinsAPISettings.AuthProxy; authProxy != nil && authProxy.BypassPort > 0 {
+		if len(spec.JenkinsAPISettings.Hostname) > 0 || spec.JenkinsAPISettings.UseNodePort {
+			messages = append(messages, "spec.jenkinsAPISettings.authProxy.bypassPort can't be set together with spec.jenkinsAPISettings.hostname or useNodePort, the operator connects to the Jenkins master pod IP")
+		}
+	}
+
+	messages = append(messages, validateBackupConflicts(spec)...)
+
+	return messages
+}
+
+func validateBackupConflicts(spec JenkinsSpec) []string {
+	backup := spec.Backup
+	if len(backup.ContainerName) == 0 && backup.S3 == nil {
+		return nil
+	}
+
+	var messages []string
+	if backup.DryRun != nil {
+		if len(backup.Destinations) > 0 {
+			messages = append(messages, "spec.backup.destinations can't be set together with spec.backup.dryRun, backups aren't made in dry run")
+		}
+		if backup.MakeBackupBeforePodDeletion {
+			messages = append(messages, "spec.backup.makeBackupBeforePodDeletion can't be enabled together with spec.backup.dryRun, backups aren't made in dry run")
+		}
+		return messages
+	}
+
+	if backup.S3 == nil && len(backup.Destinations) == 0 && !mountsPersistentVolume(spec.Master, backup.ContainerName) {
+		messages = append(messages, fmt.Sprintf("spec.backup requires container '%s' to mount a persistentVolumeClaim volume or spec.backup.destinations to be set, backups stored in the Jenkins master pod are lost with the pod", backup.ContainerName))
+	}
+	return messages
+}
+
+// mountsPersistentVolume returns true if the container mounts a volume backed by a PersistentVolumeClaim
+func mountsPersistentVolume(master JenkinsMaster, containerName string) bool {
+	persistentVolumes := map[string]bool{}
+	for _, volume := range master.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			persistentVolumes[volume.Name] = true
+		}
+	}
+	for _, container := range master.Containers {
+		if container.Name != containerName {
+			continue
+		}
+		for _, volumeMount := range container.VolumeMounts {
+			if persistentVolumes[volumeMount.Name] {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package v1alpha2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestValidateMutuallyExclusiveFeatures(t *testing.T) {
+	backupSpec := func() JenkinsSpec {
+		return JenkinsSpec{
+			Master: JenkinsMaster{
+				Containers: []Container{
+					{Name: "jenkins-master"},
+					{Name: "backup", VolumeMounts: []corev1.VolumeMount{{Name: "backup", MountPath: "/backup"}}},
+				},
+				Volumes: []corev1.Volume{
+					{
+						Name: "backup",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "jenkins-backup"},
+						},
+					},
+				},
+			},
+			Backup: Backup{ContainerName: "backup", Interval: 30},
+		}
+	}
+
+	t.Run("empty spec", func(t *testing.T) {
+		assert.Empty(t, ValidateMutuallyExclusiveFeatures(JenkinsSpec{}))
+	})
+	t.Run("base plugins with skipped base configuration", func(t *testing.T) {
+		spec := JenkinsSpec{Master: JenkinsMaster{
+			SkipBaseConfiguration: true,
+			BasePlugins:           []Plugin{{Name: "git", Version: "4.11.3"}},
+			LockPlugins:           true,
+		}}
+
+		messages := ValidateMutuallyExclusiveFeatures(spec)
+
+		assert.Len(t, messages, 2)
+		assert.Contains(t, messages[0], "spec.master.basePlugins")
+		assert.Contains(t, messages[1], "spec.master.lockPlugins")
+	})
+	t.Run("auth proxy bypass port with node port", func(t *testing.T) {
+		spec := JenkinsSpec{JenkinsAPISettings: JenkinsAPISettings{
+			UseNodePort: true,
+			AuthProxy:   &AuthProxy{BypassPort: 8081},
+		}}
+
+		messages := ValidateMutuallyExclusiveFeatures(spec)
+
+		assert.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "spec.jenkinsAPISettings.authProxy.bypassPort")
+	})
+	t.Run("backup on persistent volume", func(t *testing.T) {
+		assert.Empty(t, ValidateMutuallyExclusiveFeatures(backupSpec()))
+	})
+	t.Run("backup without persistent volume", func(t *testing.T) {
+		spec := backupSpec()
+		spec.Master.Volumes[0].VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+
+		messages := ValidateMutuallyExclusiveFeatures(spec)
+
+		assert.Equal(t, []string{"spec.backup requires container 'backup' to mount a persistentVolumeClaim volume or spec.backup.destinations to be set, backups stored in the Jenkins master pod are lost with the pod"}, messages)
+	})
+	t.Run("backup without persistent volume to destinations", func(t *testing.T) {
+		spec := backupSpec()
+		spec.Master.Volumes = nil
+		spec.Backup.Destinations = []BackupDestination{{Name: "s3"}}
+
+		assert.Empty(t, ValidateMutuallyExclusiveFeatures(spec))
+	})
+	t.Run("backup to S3", func(t *testing.T) {
+		spec := JenkinsSpec{Backup: Backup{S3: &S3Backup{Bucket: "backups"}}}
+
+		assert.Empty(t, ValidateMutuallyExclusiveFeatures(spec))
+	})
+	t.Run("backup to S3 dry run before pod deletion", func(t *testing.T) {
+		spec := JenkinsSpec{Backup: Backup{S3: &S3Backup{Bucket: "backups"}, DryRun: &BackupDryRun{}, MakeBackupBeforePodDeletion: true}}
+
+		messages := ValidateMutuallyExclusiveFeatures(spec)
+
+		assert.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "spec.backup.makeBackupBeforePodDeletion")
+	})
+	t.Run("backup dry run with backup options", func(t *testing.T) {
+		spec := backupSpec()
+		spec.Backup.DryRun = &BackupDryRun{}
+		spec.Backup.Destinations = []BackupDestination{{Name: "s3"}}
+		spec.Backup.MakeBackupBeforePodDeletion = true
+
+		messages := ValidateMutuallyExclusiveFeatures(spec)
+
+		assert.Len(t, messages, 2)
+		assert.Contains(t, messages[0], "spec.backup.destinations")
+		assert.Contains(t, messages[1], "spec.backup.makeBackupBeforePodDeletion")
+	})
+	t.Run("webhook rejects conflicting spec", func(t *testing.T) {
+		jenkins := &Jenkins{Spec: JenkinsSpec{Master: JenkinsMaster{SkipBaseConfiguration: true, LockPlugins: true}}}
+
+		assert.Error(t, jenkins.ValidateCreate())
+		assert.Error(t, jenkins.ValidateUpdate(&Jenkins{}))
+	})
+}
@@ -24,6 +24,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/maximba/kubernetes-operator/pkg/log"
@@ -58,10 +59,13 @@ func (in *Jenkins) SetupWebhookWithManager(mgr ctrl.Manager) error {
 }
 
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
-// +kubebuilder:webhook:path=/validate-jenkins-io-jenkins-io-v1alpha2-jenkins,mutating=false,failurePolicy=fail,sideEffects=None,groups=jenkins.io.jenkins.io,resources=jenkins,verbs=create;update,versions=v1alpha2,name=vjenkins.kb.io,admissionReviewVersions={v1,v1beta1}
+// +kubebuilder:webhook:path=/validate-jenkins-io-v1alpha2-jenkins,mutating=false,failurePolicy=fail,sideEffects=None,groups=jenkins.io,resources=jenkins,verbs=create;update,versions=v1alpha2,name=vjenkins.kb.io,admissionReviewVersions={v1,v1beta1}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (in *Jenkins) ValidateCreate() error {
+	if err := validateSpec(*in); err != nil {
+		return err
+	}
 	if in.Spec.ValidateSecurityWarnings {
 		jenkinslog.Info("validate create", "name", in.Name)
 		return Validate(*in)
@@ -72,6 +76,9 @@ func (in *Jenkins) ValidateCreate() error {
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (in *Jenkins) ValidateUpdate(old runtime.Object) error {
+	if err := validateSpec(*in); err != nil {
+		return err
+	}
 	if in.Spec.ValidateSecurityWarnings {
 		jenkinslog.Info("validate update", "name", in.Name)
 		return Validate(*in)
@@ -84,6 +91,14 @@ func (in *Jenkins) ValidateDelete() error {
 	return nil
 }
 
+// validateSpec rejects Jenkins CR with mutually exclusive features enabled
+func validateSpec(r Jenkins) error {
+	if messages := ValidateMutuallyExclusiveFeatures(r.Spec); len(messages) > 0 {
+		return errors.New(strings.Join(messages, "; "))
+	}
+	return nil
+}
+
 type SecurityValidator struct {
 	PluginDataCache PluginsInfo
 	isCached        bool
@@ -0,0 +1,83 @@
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScriptRunPhase is the execution phase of the JenkinsScriptRun.
+type ScriptRunPhase string
+
+const (
+	// ScriptRunPending means the script waits for the Jenkins instance to be ready
+	ScriptRunPending ScriptRunPhase = "Pending"
+	// ScriptRunRunning means the script is being executed
+	ScriptRunRunning ScriptRunPhase = "Running"
+	// ScriptRunSucceeded means the script has been executed successfully
+	ScriptRunSucceeded ScriptRunPhase = "Succeeded"
+	// ScriptRunFailed means the script execution failed or the script couldn't be executed
+	ScriptRunFailed ScriptRunPhase = "Failed"
+)
+
+// JenkinsScriptRunSpec defines the groovy script executed once against the Jenkins instance.
+type JenkinsScriptRunSpec struct {
+	// JenkinsName is the name of the Jenkins CR in the same namespace the script is executed against
+	JenkinsName string `json:"jenkinsName"`
+
+	// Script is the groovy script executed in the Jenkins script console, the spec is ignored once the script
+	// has been executed, create a new JenkinsScriptRun to run it again
+	Script string `json:"script"`
+}
+
+// JenkinsScriptRunStatus defines the result of the script execution.
+type JenkinsScriptRunStatus struct {
+	// Phase is the execution phase, one of Pending, Running, Succeeded, Failed
+	// +optional
+	Phase ScriptRunPhase `json:"phase,omitempty"`
+
+	// Output is the output of the script, truncated to the last 32KiB
+	// +optional
+	Output string `json:"output,omitempty"`
+
+	// Message explains why the script couldn't be executed or failed
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is the time when the script execution has started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time when the script execution has finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Jenkins",type=string,JSONPath=`.spec.jenkinsName`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JenkinsScriptRun is a one-off groovy script executed by the operator against the Jenkins instance, access to it
+// can be granted with Kubernetes RBAC instead of sharing the Jenkins script console
+// +k8s:openapi-gen=true
+type JenkinsScriptRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the script to execute
+	Spec JenkinsScriptRunSpec `json:"spec,omitempty"`
+
+	// Status defines the result of the script execution
+	Status JenkinsScriptRunStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JenkinsScriptRunList contains a list of JenkinsScriptRun
+type JenkinsScriptRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []JenkinsScriptRun `json:"items"`
+}
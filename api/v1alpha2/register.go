@@ -52,5 +52,5 @@ func JenkinsTypeMeta() metav1.TypeMeta {
 }
 
 func init() {
-	SchemeBuilder.Register(&Jenkins{}, &JenkinsList{})
+	SchemeBuilder.Register(&Jenkins{}, &JenkinsList{}, &JenkinsScriptRun{}, &JenkinsScriptRunList{})
 }
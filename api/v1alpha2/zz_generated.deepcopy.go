@@ -24,9 +24,30 @@ package v1alpha2
 import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentListener) DeepCopyInto(out *AgentListener) {
+	*out = *in
+	if in.Protocols != nil {
+		in, out := &in.Protocols, &out.Protocols
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentListener.
+func (in *AgentListener) DeepCopy() *AgentListener {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentListener)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppliedGroovyScript) DeepCopyInto(out *AppliedGroovyScript) {
 	*out = *in
@@ -42,10 +63,67 @@ func (in *AppliedGroovyScript) DeepCopy() *AppliedGroovyScript {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthProxy) DeepCopyInto(out *AuthProxy) {
+	*out = *in
+	if in.HeaderValueSecretKeySelector != nil {
+		in, out := &in.HeaderValueSecretKeySelector, &out.HeaderValueSecretKeySelector
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthProxy.
+func (in *AuthProxy) DeepCopy() *AuthProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AvailableUpdates) DeepCopyInto(out *AvailableUpdates) {
+	*out = *in
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AvailableUpdates.
+func (in *AvailableUpdates) DeepCopy() *AvailableUpdates {
+	if in == nil {
+		return nil
+	}
+	out := new(AvailableUpdates)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Backup) DeepCopyInto(out *Backup) {
 	*out = *in
 	in.Action.DeepCopyInto(&out.Action)
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(BackupDryRun)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]BackupDestination, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3Backup)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backup.
@@ -58,6 +136,109 @@ func (in *Backup) DeepCopy() *Backup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupCommandStatus) DeepCopyInto(out *BackupCommandStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupCommandStatus.
+func (in *BackupCommandStatus) DeepCopy() *BackupCommandStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupCommandStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupDestination) DeepCopyInto(out *BackupDestination) {
+	*out = *in
+	in.Action.DeepCopyInto(&out.Action)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupDestination.
+func (in *BackupDestination) DeepCopy() *BackupDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupDestinationStatus) DeepCopyInto(out *BackupDestinationStatus) {
+	*out = *in
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupDestinationStatus.
+func (in *BackupDestinationStatus) DeepCopy() *BackupDestinationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupDestinationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupDryRun) DeepCopyInto(out *BackupDryRun) {
+	*out = *in
+	if in.Excludes != nil {
+		in, out := &in.Excludes, &out.Excludes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupDryRun.
+func (in *BackupDryRun) DeepCopy() *BackupDryRun {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupDryRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupEstimate) DeepCopyInto(out *BackupEstimate) {
+	*out = *in
+	in.EstimatedTime.DeepCopyInto(&out.EstimatedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupEstimate.
+func (in *BackupEstimate) DeepCopy() *BackupEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildRetention) DeepCopyInto(out *BuildRetention) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildRetention.
+func (in *BuildRetention) DeepCopy() *BuildRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapRef) DeepCopyInto(out *ConfigMapRef) {
 	*out = *in
@@ -77,6 +258,11 @@ func (in *ConfigMapRef) DeepCopy() *ConfigMapRef {
 func (in *ConfigurationAsCode) DeepCopyInto(out *ConfigurationAsCode) {
 	*out = *in
 	in.Customization.DeepCopyInto(&out.Customization)
+	if in.GitRepository != nil {
+		in, out := &in.GitRepository, &out.GitRepository
+		*out = new(ConfigurationAsCodeGitRepository)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationAsCode.
@@ -89,6 +275,61 @@ func (in *ConfigurationAsCode) DeepCopy() *ConfigurationAsCode {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationAsCodeGitRepository) DeepCopyInto(out *ConfigurationAsCodeGitRepository) {
+	*out = *in
+	if in.CredentialsSecret != nil {
+		in, out := &in.CredentialsSecret, &out.CredentialsSecret
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.SyncInterval != nil {
+		in, out := &in.SyncInterval, &out.SyncInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationAsCodeGitRepository.
+func (in *ConfigurationAsCodeGitRepository) DeepCopy() *ConfigurationAsCodeGitRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationAsCodeGitRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationProgress) DeepCopyInto(out *ConfigurationProgress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationProgress.
+func (in *ConfigurationProgress) DeepCopy() *ConfigurationProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationVersions) DeepCopyInto(out *ConfigurationVersions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationVersions.
+func (in *ConfigurationVersions) DeepCopy() *ConfigurationVersions {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationVersions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Container) DeepCopyInto(out *Container) {
 	*out = *in
@@ -161,6 +402,50 @@ func (in *Container) DeepCopy() *Container {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrashLogs) DeepCopyInto(out *CrashLogs) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrashLogs.
+func (in *CrashLogs) DeepCopy() *CrashLogs {
+	if in == nil {
+		return nil
+	}
+	out := new(CrashLogs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomNotification) DeepCopyInto(out *CustomNotification) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretParameters != nil {
+		in, out := &in.SecretParameters, &out.SecretParameters
+		*out = make(map[string]SecretKeySelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomNotification.
+func (in *CustomNotification) DeepCopy() *CustomNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Customization) DeepCopyInto(out *Customization) {
 	*out = *in
@@ -183,110 +468,392 @@ func (in *Customization) DeepCopy() *Customization {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GroovyScripts) DeepCopyInto(out *GroovyScripts) {
+func (in *DefaultView) DeepCopyInto(out *DefaultView) {
 	*out = *in
-	in.Customization.DeepCopyInto(&out.Customization)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroovyScripts.
-func (in *GroovyScripts) DeepCopy() *GroovyScripts {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultView.
+func (in *DefaultView) DeepCopy() *DefaultView {
 	if in == nil {
 		return nil
 	}
-	out := new(GroovyScripts)
+	out := new(DefaultView)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Handler) DeepCopyInto(out *Handler) {
+func (in *DefaultViews) DeepCopyInto(out *DefaultViews) {
 	*out = *in
-	if in.Exec != nil {
-		in, out := &in.Exec, &out.Exec
-		*out = new(corev1.ExecAction)
-		(*in).DeepCopyInto(*out)
+	if in.SeedJobs != nil {
+		in, out := &in.SeedJobs, &out.SeedJobs
+		*out = new(DefaultView)
+		**out = **in
+	}
+	if in.NonSeedJobs != nil {
+		in, out := &in.NonSeedJobs, &out.NonSeedJobs
+		*out = new(DefaultView)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Handler.
-func (in *Handler) DeepCopy() *Handler {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultViews.
+func (in *DefaultViews) DeepCopy() *DefaultViews {
 	if in == nil {
 		return nil
 	}
-	out := new(Handler)
+	out := new(DefaultViews)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Jenkins) DeepCopyInto(out *Jenkins) {
+func (in *ExternalJenkins) DeepCopyInto(out *ExternalJenkins) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.CredentialsSecret = in.CredentialsSecret
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Jenkins.
-func (in *Jenkins) DeepCopy() *Jenkins {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalJenkins.
+func (in *ExternalJenkins) DeepCopy() *ExternalJenkins {
 	if in == nil {
 		return nil
 	}
-	out := new(Jenkins)
+	out := new(ExternalJenkins)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Jenkins) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtraResource) DeepCopyInto(out *ExtraResource) {
+	*out = *in
+	if in.Manifest != nil {
+		in, out := &in.Manifest, &out.Manifest
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
 	}
-	return nil
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(ConfigMapRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraResource.
+func (in *ExtraResource) DeepCopy() *ExtraResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtraResource)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JenkinsAPISettings) DeepCopyInto(out *JenkinsAPISettings) {
+func (in *ExtraResourceReference) DeepCopyInto(out *ExtraResourceReference) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsAPISettings.
-func (in *JenkinsAPISettings) DeepCopy() *JenkinsAPISettings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraResourceReference.
+func (in *ExtraResourceReference) DeepCopy() *ExtraResourceReference {
 	if in == nil {
 		return nil
 	}
-	out := new(JenkinsAPISettings)
+	out := new(ExtraResourceReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JenkinsList) DeepCopyInto(out *JenkinsList) {
+func (in *FeatureStatus) DeepCopyInto(out *FeatureStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Jenkins, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsList.
-func (in *JenkinsList) DeepCopy() *JenkinsList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureStatus.
+func (in *FeatureStatus) DeepCopy() *FeatureStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(JenkinsList)
+	out := new(FeatureStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *JenkinsList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Folder) DeepCopyInto(out *Folder) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Folder.
+func (in *Folder) DeepCopy() *Folder {
+	if in == nil {
+		return nil
+	}
+	out := new(Folder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitRepositoryStatus) DeepCopyInto(out *GitRepositoryStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepositoryStatus.
+func (in *GitRepositoryStatus) DeepCopy() *GitRepositoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitRepositoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroovyScriptResult) DeepCopyInto(out *GroovyScriptResult) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroovyScriptResult.
+func (in *GroovyScriptResult) DeepCopy() *GroovyScriptResult {
+	if in == nil {
+		return nil
+	}
+	out := new(GroovyScriptResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroovyScripts) DeepCopyInto(out *GroovyScripts) {
+	*out = *in
+	in.Customization.DeepCopyInto(&out.Customization)
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroovyScripts.
+func (in *GroovyScripts) DeepCopy() *GroovyScripts {
+	if in == nil {
+		return nil
+	}
+	out := new(GroovyScripts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Handler) DeepCopyInto(out *Handler) {
+	*out = *in
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(corev1.ExecAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Handler.
+func (in *Handler) DeepCopy() *Handler {
+	if in == nil {
+		return nil
+	}
+	out := new(Handler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeapSizing) DeepCopyInto(out *HeapSizing) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeapSizing.
+func (in *HeapSizing) DeepCopy() *HeapSizing {
+	if in == nil {
+		return nil
+	}
+	out := new(HeapSizing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRollback) DeepCopyInto(out *ImageRollback) {
+	*out = *in
+	out.Window = in.Window
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRollback.
+func (in *ImageRollback) DeepCopy() *ImageRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRollbackStatus) DeepCopyInto(out *ImageRollbackStatus) {
+	*out = *in
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRollbackStatus.
+func (in *ImageRollbackStatus) DeepCopy() *ImageRollbackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRollbackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImmutableConfiguration) DeepCopyInto(out *ImmutableConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImmutableConfiguration.
+func (in *ImmutableConfiguration) DeepCopy() *ImmutableConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ImmutableConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Jenkins) DeepCopyInto(out *Jenkins) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Jenkins.
+func (in *Jenkins) DeepCopy() *Jenkins {
+	if in == nil {
+		return nil
+	}
+	out := new(Jenkins)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Jenkins) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsAPISettings) DeepCopyInto(out *JenkinsAPISettings) {
+	*out = *in
+	if in.AuthProxy != nil {
+		in, out := &in.AuthProxy, &out.AuthProxy
+		*out = new(AuthProxy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsAPISettings.
+func (in *JenkinsAPISettings) DeepCopy() *JenkinsAPISettings {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsAPISettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsHomePersistentVolumeClaim) DeepCopyInto(out *JenkinsHomePersistentVolumeClaim) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.PreviousClaimRetention != nil {
+		in, out := &in.PreviousClaimRetention, &out.PreviousClaimRetention
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsHomePersistentVolumeClaim.
+func (in *JenkinsHomePersistentVolumeClaim) DeepCopy() *JenkinsHomePersistentVolumeClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsHomePersistentVolumeClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsList) DeepCopyInto(out *JenkinsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Jenkins, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsList.
+func (in *JenkinsList) DeepCopy() *JenkinsList {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JenkinsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
 		return c
 	}
 	return nil
@@ -328,6 +895,55 @@ func (in *JenkinsMaster) DeepCopyInto(out *JenkinsMaster) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TrustedCertificates != nil {
+		in, out := &in.TrustedCertificates, &out.TrustedCertificates
+		*out = make([]ConfigMapRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.EmptyDir != nil {
+		in, out := &in.EmptyDir, &out.EmptyDir
+		*out = new(corev1.EmptyDirVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JenkinsHomePersistentVolumeClaim != nil {
+		in, out := &in.JenkinsHomePersistentVolumeClaim, &out.JenkinsHomePersistentVolumeClaim
+		*out = new(JenkinsHomePersistentVolumeClaim)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageRollback != nil {
+		in, out := &in.ImageRollback, &out.ImageRollback
+		*out = new(ImageRollback)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BuildRetention != nil {
+		in, out := &in.BuildRetention, &out.BuildRetention
+		*out = new(BuildRetention)
+		**out = **in
+	}
+	if in.UpdateCenter != nil {
+		in, out := &in.UpdateCenter, &out.UpdateCenter
+		*out = new(UpdateCenter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AgentListener != nil {
+		in, out := &in.AgentListener, &out.AgentListener
+		*out = new(AgentListener)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessGates != nil {
+		in, out := &in.ReadinessGates, &out.ReadinessGates
+		*out = make([]ReadinessGate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ImagePullSecrets != nil {
 		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
 		*out = make([]corev1.LocalObjectReference, len(*in))
@@ -364,6 +980,26 @@ func (in *JenkinsMaster) DeepCopyInto(out *JenkinsMaster) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PodPendingTimeout != nil {
+		in, out := &in.PodPendingTimeout, &out.PodPendingTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PodEventsFilter != nil {
+		in, out := &in.PodEventsFilter, &out.PodEventsFilter
+		*out = new(PodEventsFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HeapSizing != nil {
+		in, out := &in.HeapSizing, &out.HeapSizing
+		*out = new(HeapSizing)
+		**out = **in
+	}
+	if in.CrashLogs != nil {
+		in, out := &in.CrashLogs, &out.CrashLogs
+		*out = new(CrashLogs)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsMaster.
@@ -377,15 +1013,140 @@ func (in *JenkinsMaster) DeepCopy() *JenkinsMaster {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JenkinsSpec) DeepCopyInto(out *JenkinsSpec) {
+func (in *JenkinsScriptRun) DeepCopyInto(out *JenkinsScriptRun) {
 	*out = *in
-	in.Master.DeepCopyInto(&out.Master)
-	if in.SeedJobs != nil {
-		in, out := &in.SeedJobs, &out.SeedJobs
-		*out = make([]SeedJob, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsScriptRun.
+func (in *JenkinsScriptRun) DeepCopy() *JenkinsScriptRun {
+	if in == nil {
+		return nil
 	}
-	if in.Notifications != nil {
+	out := new(JenkinsScriptRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JenkinsScriptRun) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsScriptRunList) DeepCopyInto(out *JenkinsScriptRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]JenkinsScriptRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsScriptRunList.
+func (in *JenkinsScriptRunList) DeepCopy() *JenkinsScriptRunList {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsScriptRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JenkinsScriptRunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsScriptRunSpec) DeepCopyInto(out *JenkinsScriptRunSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsScriptRunSpec.
+func (in *JenkinsScriptRunSpec) DeepCopy() *JenkinsScriptRunSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsScriptRunSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsScriptRunStatus) DeepCopyInto(out *JenkinsScriptRunStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsScriptRunStatus.
+func (in *JenkinsScriptRunStatus) DeepCopy() *JenkinsScriptRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsScriptRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsSpec) DeepCopyInto(out *JenkinsSpec) {
+	*out = *in
+	in.Master.DeepCopyInto(&out.Master)
+	if in.ExternalJenkins != nil {
+		in, out := &in.ExternalJenkins, &out.ExternalJenkins
+		*out = new(ExternalJenkins)
+		**out = **in
+	}
+	if in.SeedJobs != nil {
+		in, out := &in.SeedJobs, &out.SeedJobs
+		*out = make([]SeedJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Jobs.DeepCopyInto(&out.Jobs)
+	if in.SeedJobAgentWorkspaceCache != nil {
+		in, out := &in.SeedJobAgentWorkspaceCache, &out.SeedJobAgentWorkspaceCache
+		*out = new(SeedJobAgentWorkspaceCache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SeedJobAgentTemplate != nil {
+		in, out := &in.SeedJobAgentTemplate, &out.SeedJobAgentTemplate
+		*out = new(SeedJobAgentTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SeedJobAgentSecret != nil {
+		in, out := &in.SeedJobAgentSecret, &out.SeedJobAgentSecret
+		*out = new(SeedJobAgentSecret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SCMWebhook != nil {
+		in, out := &in.SCMWebhook, &out.SCMWebhook
+		*out = new(SCMWebhook)
+		**out = **in
+	}
+	if in.Notifications != nil {
 		in, out := &in.Notifications, &out.Notifications
 		*out = make([]Notification, len(*in))
 		for i := range *in {
@@ -396,15 +1157,57 @@ func (in *JenkinsSpec) DeepCopyInto(out *JenkinsSpec) {
 	in.SlaveService.DeepCopyInto(&out.SlaveService)
 	in.Backup.DeepCopyInto(&out.Backup)
 	in.Restore.DeepCopyInto(&out.Restore)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = new(ConfigMapRef)
+		**out = **in
+	}
+	in.BaseGroovyScripts.DeepCopyInto(&out.BaseGroovyScripts)
 	in.GroovyScripts.DeepCopyInto(&out.GroovyScripts)
 	in.ConfigurationAsCode.DeepCopyInto(&out.ConfigurationAsCode)
+	if in.ReadinessCheck != nil {
+		in, out := &in.ReadinessCheck, &out.ReadinessCheck
+		*out = new(ReadinessCheck)
+		**out = **in
+	}
+	if in.ExtraResources != nil {
+		in, out := &in.ExtraResources, &out.ExtraResources
+		*out = make([]ExtraResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PrometheusRule != nil {
+		in, out := &in.PrometheusRule, &out.PrometheusRule
+		*out = new(PrometheusRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImmutableConfiguration != nil {
+		in, out := &in.ImmutableConfiguration, &out.ImmutableConfiguration
+		*out = new(ImmutableConfiguration)
+		**out = **in
+	}
 	if in.Roles != nil {
 		in, out := &in.Roles, &out.Roles
 		*out = make([]v1.RoleRef, len(*in))
 		copy(*out, *in)
 	}
 	in.ServiceAccount.DeepCopyInto(&out.ServiceAccount)
-	out.JenkinsAPISettings = in.JenkinsAPISettings
+	in.JenkinsAPISettings.DeepCopyInto(&out.JenkinsAPISettings)
+	if in.CommonLabels != nil {
+		in, out := &in.CommonLabels, &out.CommonLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CommonAnnotations != nil {
+		in, out := &in.CommonAnnotations, &out.CommonAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsSpec.
@@ -428,186 +1231,695 @@ func (in *JenkinsStatus) DeepCopyInto(out *JenkinsStatus) {
 		in, out := &in.BaseConfigurationCompletedTime, &out.BaseConfigurationCompletedTime
 		*out = (*in).DeepCopy()
 	}
+	if in.BaseConfigurationProgress != nil {
+		in, out := &in.BaseConfigurationProgress, &out.BaseConfigurationProgress
+		*out = new(ConfigurationProgress)
+		**out = **in
+	}
 	if in.UserConfigurationCompletedTime != nil {
 		in, out := &in.UserConfigurationCompletedTime, &out.UserConfigurationCompletedTime
 		*out = (*in).DeepCopy()
 	}
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.BackupEstimate != nil {
+		in, out := &in.BackupEstimate, &out.BackupEstimate
+		*out = new(BackupEstimate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupDestinations != nil {
+		in, out := &in.BackupDestinations, &out.BackupDestinations
+		*out = make([]BackupDestinationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BackupCommands != nil {
+		in, out := &in.BackupCommands, &out.BackupCommands
+		*out = make([]BackupCommandStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.CreatedSeedJobs != nil {
 		in, out := &in.CreatedSeedJobs, &out.CreatedSeedJobs
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SeedJobPlugins != nil {
+		in, out := &in.SeedJobPlugins, &out.SeedJobPlugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.AppliedGroovyScripts != nil {
 		in, out := &in.AppliedGroovyScripts, &out.AppliedGroovyScripts
 		*out = make([]AppliedGroovyScript, len(*in))
 		copy(*out, *in)
 	}
+	if in.GroovyScriptResults != nil {
+		in, out := &in.GroovyScriptResults, &out.GroovyScriptResults
+		*out = make([]GroovyScriptResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastReconcileError != nil {
+		in, out := &in.LastReconcileError, &out.LastReconcileError
+		*out = new(ReconcileError)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SeedJobAgent != nil {
+		in, out := &in.SeedJobAgent, &out.SeedJobAgent
+		*out = new(SeedJobAgentStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackoffUntil != nil {
+		in, out := &in.BackoffUntil, &out.BackoffUntil
+		*out = (*in).DeepCopy()
+	}
+	if in.PluginsLock != nil {
+		in, out := &in.PluginsLock, &out.PluginsLock
+		*out = new(PluginsLock)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PluginUpgrades != nil {
+		in, out := &in.PluginUpgrades, &out.PluginUpgrades
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PluginUpgradeTime != nil {
+		in, out := &in.PluginUpgradeTime, &out.PluginUpgradeTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AvailableUpdates != nil {
+		in, out := &in.AvailableUpdates, &out.AvailableUpdates
+		*out = new(AvailableUpdates)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestoreRehearsal != nil {
+		in, out := &in.RestoreRehearsal, &out.RestoreRehearsal
+		*out = new(RestoreRehearsalStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestoreDryRun != nil {
+		in, out := &in.RestoreDryRun, &out.RestoreDryRun
+		*out = new(RestoreDryRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReconcileTimings != nil {
+		in, out := &in.ReconcileTimings, &out.ReconcileTimings
+		*out = new(ReconcileTimings)
+		**out = **in
+	}
+	if in.AppliedExtraResources != nil {
+		in, out := &in.AppliedExtraResources, &out.AppliedExtraResources
+		*out = make([]ExtraResourceReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodRestarts != nil {
+		in, out := &in.PodRestarts, &out.PodRestarts
+		*out = new(PodRestarts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastKnownGoodSpec != nil {
+		in, out := &in.LastKnownGoodSpec, &out.LastKnownGoodSpec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JenkinsHomeVolumeResize != nil {
+		in, out := &in.JenkinsHomeVolumeResize, &out.JenkinsHomeVolumeResize
+		*out = new(VolumeResize)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JenkinsHomeVolumeMigration != nil {
+		in, out := &in.JenkinsHomeVolumeMigration, &out.JenkinsHomeVolumeMigration
+		*out = new(VolumeMigration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageRollback != nil {
+		in, out := &in.ImageRollback, &out.ImageRollback
+		*out = new(ImageRollbackStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigurationAsCodeGitRepository != nil {
+		in, out := &in.ConfigurationAsCodeGitRepository, &out.ConfigurationAsCodeGitRepository
+		*out = new(GitRepositoryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigurationVersions != nil {
+		in, out := &in.ConfigurationVersions, &out.ConfigurationVersions
+		*out = new(ConfigurationVersions)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]FeatureStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsStatus.
+func (in *JenkinsStatus) DeepCopy() *JenkinsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Jobs) DeepCopyInto(out *Jobs) {
+	*out = *in
+	if in.Folders != nil {
+		in, out := &in.Folders, &out.Folders
+		*out = make([]Folder, len(*in))
+		copy(*out, *in)
+	}
+	if in.Views != nil {
+		in, out := &in.Views, &out.Views
+		*out = make([]View, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultViews != nil {
+		in, out := &in.DefaultViews, &out.DefaultViews
+		*out = new(DefaultViews)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Jobs.
+func (in *Jobs) DeepCopy() *Jobs {
+	if in == nil {
+		return nil
+	}
+	out := new(Jobs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Mailgun) DeepCopyInto(out *Mailgun) {
+	*out = *in
+	out.APIKeySecretKeySelector = in.APIKeySecretKeySelector
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mailgun.
+func (in *Mailgun) DeepCopy() *Mailgun {
+	if in == nil {
+		return nil
+	}
+	out := new(Mailgun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MicrosoftTeams) DeepCopyInto(out *MicrosoftTeams) {
+	*out = *in
+	out.WebHookURLSecretKeySelector = in.WebHookURLSecretKeySelector
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MicrosoftTeams.
+func (in *MicrosoftTeams) DeepCopy() *MicrosoftTeams {
+	if in == nil {
+		return nil
+	}
+	out := new(MicrosoftTeams)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Notification) DeepCopyInto(out *Notification) {
+	*out = *in
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(Slack)
+		**out = **in
+	}
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = new(MicrosoftTeams)
+		**out = **in
+	}
+	if in.Mailgun != nil {
+		in, out := &in.Mailgun, &out.Mailgun
+		*out = new(Mailgun)
+		**out = **in
+	}
+	if in.SMTP != nil {
+		in, out := &in.SMTP, &out.SMTP
+		*out = new(SMTP)
+		**out = **in
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(Webhook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PagerDuty != nil {
+		in, out := &in.PagerDuty, &out.PagerDuty
+		*out = new(PagerDuty)
+		**out = **in
+	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = new(CustomNotification)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Notification.
+func (in *Notification) DeepCopy() *Notification {
+	if in == nil {
+		return nil
+	}
+	out := new(Notification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDuty) DeepCopyInto(out *PagerDuty) {
+	*out = *in
+	out.RoutingKeySecretKeySelector = in.RoutingKeySecretKeySelector
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDuty.
+func (in *PagerDuty) DeepCopy() *PagerDuty {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDuty)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Plugin) DeepCopyInto(out *Plugin) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plugin.
+func (in *Plugin) DeepCopy() *Plugin {
+	if in == nil {
+		return nil
+	}
+	out := new(Plugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginData) DeepCopyInto(out *PluginData) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginData.
+func (in *PluginData) DeepCopy() *PluginData {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginInfo) DeepCopyInto(out *PluginInfo) {
+	*out = *in
+	if in.SecurityWarnings != nil {
+		in, out := &in.SecurityWarnings, &out.SecurityWarnings
+		*out = make([]Warning, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginInfo.
+func (in *PluginInfo) DeepCopy() *PluginInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginsInfo) DeepCopyInto(out *PluginsInfo) {
+	*out = *in
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]PluginInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginsInfo.
+func (in *PluginsInfo) DeepCopy() *PluginsInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginsInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginsLock) DeepCopyInto(out *PluginsLock) {
+	*out = *in
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginsLock.
+func (in *PluginsLock) DeepCopy() *PluginsLock {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginsLock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodEventsFilter) DeepCopyInto(out *PodEventsFilter) {
+	*out = *in
+	if in.LookbackWindow != nil {
+		in, out := &in.LookbackWindow, &out.LookbackWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodEventsFilter.
+func (in *PodEventsFilter) DeepCopy() *PodEventsFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(PodEventsFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodRestarts) DeepCopyInto(out *PodRestarts) {
+	*out = *in
+	if in.LastTime != nil {
+		in, out := &in.LastTime, &out.LastTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodRestarts.
+func (in *PodRestarts) DeepCopy() *PodRestarts {
+	if in == nil {
+		return nil
+	}
+	out := new(PodRestarts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusRule) DeepCopyInto(out *PrometheusRule) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AlertLabels != nil {
+		in, out := &in.AlertLabels, &out.AlertLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.BackupStaleAfter != nil {
+		in, out := &in.BackupStaleAfter, &out.BackupStaleAfter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.NotReadyFor != nil {
+		in, out := &in.NotReadyFor, &out.NotReadyFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RestartStormWindow != nil {
+		in, out := &in.RestartStormWindow, &out.RestartStormWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusRule.
+func (in *PrometheusRule) DeepCopy() *PrometheusRule {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessCheck) DeepCopyInto(out *ReadinessCheck) {
+	*out = *in
+	out.ConfigMapRef = in.ConfigMapRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessCheck.
+func (in *ReadinessCheck) DeepCopy() *ReadinessCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessGate) DeepCopyInto(out *ReadinessGate) {
+	*out = *in
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(ReadinessGateHTTPGet)
+		**out = **in
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessGate.
+func (in *ReadinessGate) DeepCopy() *ReadinessGate {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessGateHTTPGet) DeepCopyInto(out *ReadinessGateHTTPGet) {
+	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsStatus.
-func (in *JenkinsStatus) DeepCopy() *JenkinsStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessGateHTTPGet.
+func (in *ReadinessGateHTTPGet) DeepCopy() *ReadinessGateHTTPGet {
 	if in == nil {
 		return nil
 	}
-	out := new(JenkinsStatus)
+	out := new(ReadinessGateHTTPGet)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Mailgun) DeepCopyInto(out *Mailgun) {
+func (in *ReconcileError) DeepCopyInto(out *ReconcileError) {
 	*out = *in
-	out.APIKeySecretKeySelector = in.APIKeySecretKeySelector
+	in.Time.DeepCopyInto(&out.Time)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mailgun.
-func (in *Mailgun) DeepCopy() *Mailgun {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileError.
+func (in *ReconcileError) DeepCopy() *ReconcileError {
 	if in == nil {
 		return nil
 	}
-	out := new(Mailgun)
+	out := new(ReconcileError)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MicrosoftTeams) DeepCopyInto(out *MicrosoftTeams) {
+func (in *ReconcileTimings) DeepCopyInto(out *ReconcileTimings) {
 	*out = *in
-	out.WebHookURLSecretKeySelector = in.WebHookURLSecretKeySelector
+	out.ResourcesEnsure = in.ResourcesEnsure
+	out.PodWait = in.PodWait
+	out.PluginsVerify = in.PluginsVerify
+	out.BaseGroovyScripts = in.BaseGroovyScripts
+	out.UserGroovyScripts = in.UserGroovyScripts
+	out.Total = in.Total
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MicrosoftTeams.
-func (in *MicrosoftTeams) DeepCopy() *MicrosoftTeams {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileTimings.
+func (in *ReconcileTimings) DeepCopy() *ReconcileTimings {
 	if in == nil {
 		return nil
 	}
-	out := new(MicrosoftTeams)
+	out := new(ReconcileTimings)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Notification) DeepCopyInto(out *Notification) {
+func (in *Restore) DeepCopyInto(out *Restore) {
 	*out = *in
-	if in.Slack != nil {
-		in, out := &in.Slack, &out.Slack
-		*out = new(Slack)
-		**out = **in
-	}
-	if in.Teams != nil {
-		in, out := &in.Teams, &out.Teams
-		*out = new(MicrosoftTeams)
-		**out = **in
-	}
-	if in.Mailgun != nil {
-		in, out := &in.Mailgun, &out.Mailgun
-		*out = new(Mailgun)
-		**out = **in
-	}
-	if in.SMTP != nil {
-		in, out := &in.SMTP, &out.SMTP
-		*out = new(SMTP)
+	in.Action.DeepCopyInto(&out.Action)
+	in.GetLatestAction.DeepCopyInto(&out.GetLatestAction)
+	if in.Rehearsal != nil {
+		in, out := &in.Rehearsal, &out.Rehearsal
+		*out = new(RestoreRehearsal)
 		**out = **in
 	}
+	in.ListAction.DeepCopyInto(&out.ListAction)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Notification.
-func (in *Notification) DeepCopy() *Notification {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Restore.
+func (in *Restore) DeepCopy() *Restore {
 	if in == nil {
 		return nil
 	}
-	out := new(Notification)
+	out := new(Restore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Plugin) DeepCopyInto(out *Plugin) {
+func (in *RestoreDryRunStatus) DeepCopyInto(out *RestoreDryRunStatus) {
 	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Jobs != nil {
+		in, out := &in.Jobs, &out.Jobs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigurationFiles != nil {
+		in, out := &in.ConfigurationFiles, &out.ConfigurationFiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plugin.
-func (in *Plugin) DeepCopy() *Plugin {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreDryRunStatus.
+func (in *RestoreDryRunStatus) DeepCopy() *RestoreDryRunStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Plugin)
+	out := new(RestoreDryRunStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PluginData) DeepCopyInto(out *PluginData) {
+func (in *RestoreRehearsal) DeepCopyInto(out *RestoreRehearsal) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginData.
-func (in *PluginData) DeepCopy() *PluginData {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreRehearsal.
+func (in *RestoreRehearsal) DeepCopy() *RestoreRehearsal {
 	if in == nil {
 		return nil
 	}
-	out := new(PluginData)
+	out := new(RestoreRehearsal)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PluginInfo) DeepCopyInto(out *PluginInfo) {
+func (in *RestoreRehearsalStatus) DeepCopyInto(out *RestoreRehearsalStatus) {
 	*out = *in
-	if in.SecurityWarnings != nil {
-		in, out := &in.SecurityWarnings, &out.SecurityWarnings
-		*out = make([]Warning, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginInfo.
-func (in *PluginInfo) DeepCopy() *PluginInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreRehearsalStatus.
+func (in *RestoreRehearsalStatus) DeepCopy() *RestoreRehearsalStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PluginInfo)
+	out := new(RestoreRehearsalStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PluginsInfo) DeepCopyInto(out *PluginsInfo) {
+func (in *S3Backup) DeepCopyInto(out *S3Backup) {
 	*out = *in
-	if in.Plugins != nil {
-		in, out := &in.Plugins, &out.Plugins
-		*out = make([]PluginInfo, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Excludes != nil {
+		in, out := &in.Excludes, &out.Excludes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginsInfo.
-func (in *PluginsInfo) DeepCopy() *PluginsInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Backup.
+func (in *S3Backup) DeepCopy() *S3Backup {
 	if in == nil {
 		return nil
 	}
-	out := new(PluginsInfo)
+	out := new(S3Backup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Restore) DeepCopyInto(out *Restore) {
+func (in *SCMWebhook) DeepCopyInto(out *SCMWebhook) {
 	*out = *in
-	in.Action.DeepCopyInto(&out.Action)
-	in.GetLatestAction.DeepCopyInto(&out.GetLatestAction)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Restore.
-func (in *Restore) DeepCopy() *Restore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCMWebhook.
+func (in *SCMWebhook) DeepCopy() *SCMWebhook {
 	if in == nil {
 		return nil
 	}
-	out := new(Restore)
+	out := new(SCMWebhook)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -679,6 +1991,16 @@ func (in *SecurityValidator) DeepCopy() *SecurityValidator {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SeedJob) DeepCopyInto(out *SeedJob) {
 	*out = *in
+	if in.BranchSource != nil {
+		in, out := &in.BranchSource, &out.BranchSource
+		*out = new(SeedJobBranchSource)
+		**out = **in
+	}
+	if in.DeepValidation != nil {
+		in, out := &in.DeepValidation, &out.DeepValidation
+		*out = new(SeedJobDeepValidation)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedJob.
@@ -691,6 +2013,149 @@ func (in *SeedJob) DeepCopy() *SeedJob {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedJobAgentSecret) DeepCopyInto(out *SeedJobAgentSecret) {
+	*out = *in
+	if in.RotationInterval != nil {
+		in, out := &in.RotationInterval, &out.RotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedJobAgentSecret.
+func (in *SeedJobAgentSecret) DeepCopy() *SeedJobAgentSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedJobAgentSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedJobAgentStatus) DeepCopyInto(out *SeedJobAgentStatus) {
+	*out = *in
+	if in.SecretRotationTime != nil {
+		in, out := &in.SecretRotationTime, &out.SecretRotationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedJobAgentStatus.
+func (in *SeedJobAgentStatus) DeepCopy() *SeedJobAgentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedJobAgentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedJobAgentTemplate) DeepCopyInto(out *SeedJobAgentTemplate) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedJobAgentTemplate.
+func (in *SeedJobAgentTemplate) DeepCopy() *SeedJobAgentTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedJobAgentTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedJobAgentWorkspaceCache) DeepCopyInto(out *SeedJobAgentWorkspaceCache) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedJobAgentWorkspaceCache.
+func (in *SeedJobAgentWorkspaceCache) DeepCopy() *SeedJobAgentWorkspaceCache {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedJobAgentWorkspaceCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedJobBranchDiscovery) DeepCopyInto(out *SeedJobBranchDiscovery) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedJobBranchDiscovery.
+func (in *SeedJobBranchDiscovery) DeepCopy() *SeedJobBranchDiscovery {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedJobBranchDiscovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedJobBranchSource) DeepCopyInto(out *SeedJobBranchSource) {
+	*out = *in
+	out.Discovery = in.Discovery
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedJobBranchSource.
+func (in *SeedJobBranchSource) DeepCopy() *SeedJobBranchSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedJobBranchSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedJobDeepValidation) DeepCopyInto(out *SeedJobDeepValidation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedJobDeepValidation.
+func (in *SeedJobDeepValidation) DeepCopy() *SeedJobDeepValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedJobDeepValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Service) DeepCopyInto(out *Service) {
 	*out = *in
@@ -763,6 +2228,26 @@ func (in *Slack) DeepCopy() *Slack {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateCenter) DeepCopyInto(out *UpdateCenter) {
+	*out = *in
+	if in.ClientCertificate != nil {
+		in, out := &in.ClientCertificate, &out.ClientCertificate
+		*out = new(SecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateCenter.
+func (in *UpdateCenter) DeepCopy() *UpdateCenter {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateCenter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Version) DeepCopyInto(out *Version) {
 	*out = *in
@@ -778,6 +2263,67 @@ func (in *Version) DeepCopy() *Version {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *View) DeepCopyInto(out *View) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new View.
+func (in *View) DeepCopy() *View {
+	if in == nil {
+		return nil
+	}
+	out := new(View)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeMigration) DeepCopyInto(out *VolumeMigration) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeMigration.
+func (in *VolumeMigration) DeepCopy() *VolumeMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeResize) DeepCopyInto(out *VolumeResize) {
+	*out = *in
+	out.RequestedSize = in.RequestedSize.DeepCopy()
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeResize.
+func (in *VolumeResize) DeepCopy() *VolumeResize {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeResize)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Warning) DeepCopyInto(out *Warning) {
 	*out = *in
@@ -797,3 +2343,24 @@ func (in *Warning) DeepCopy() *Warning {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Webhook) DeepCopyInto(out *Webhook) {
+	*out = *in
+	out.URLSecretKeySelector = in.URLSecretKeySelector
+	if in.HeadersSecret != nil {
+		in, out := &in.HeadersSecret, &out.HeadersSecret
+		*out = new(SecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Webhook.
+func (in *Webhook) DeepCopy() *Webhook {
+	if in == nil {
+		return nil
+	}
+	out := new(Webhook)
+	in.DeepCopyInto(out)
+	return out
+}
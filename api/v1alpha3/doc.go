@@ -0,0 +1,4 @@
+// Package v1alpha3 contains API Schema definitions for the jenkins.io v1alpha3 API group
+// +kubebuilder:object:generate=true
+// +groupName=jenkins.io
+package v1alpha3
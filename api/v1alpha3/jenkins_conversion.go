@@ -0,0 +1,111 @@
+package v1alpha3
+
+import (
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+var _ conversion.Convertible = &Jenkins{}
+
+// ConvertTo converts this Jenkins to the hub version v1alpha2
+func (in *Jenkins) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha2.Jenkins)
+	// v1alpha2 Jenkins ignores SetGroupVersionKind so the type meta is set here for the conversion response
+	dst.TypeMeta = v1alpha2.JenkinsTypeMeta()
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Status = in.Status
+
+	spec := in.Spec
+	dst.Spec = v1alpha2.JenkinsSpec{
+		InheritFrom:                   spec.InheritFrom,
+		Master:                        spec.Master,
+		ExternalJenkins:               spec.ExternalJenkins,
+		SkipUserConfiguration:         spec.Configuration.Skip,
+		SeedJobs:                      spec.SeedJobs.Jobs,
+		SeedJobAgentImage:             spec.SeedJobs.Agent.Image,
+		Jobs:                          spec.Jobs,
+		SeedJobAgentPriorityClassName: spec.SeedJobs.Agent.PriorityClassName,
+		SeedJobAgentWorkspaceCache:    spec.SeedJobs.Agent.WorkspaceCache,
+		SeedJobAgentTemplate:          spec.SeedJobs.Agent.Template,
+		SeedJobAgentSecret:            spec.SeedJobs.Agent.Secret,
+		SCMWebhook:                    spec.SeedJobs.SCMWebhook,
+		ValidateSecurityWarnings:      spec.ValidateSecurityWarnings,
+		Notifications:                 spec.Notifications,
+		Service:                       spec.Services.Master,
+		SlaveService:                  spec.Services.Agent,
+		Backup:                        spec.Backup.Backup,
+		Restore:                       spec.Backup.Restore,
+		Values:                        spec.Configuration.Values,
+		BaseGroovyScripts:             spec.Configuration.BaseGroovyScripts,
+		GroovyScriptOutput:            spec.Configuration.GroovyScriptOutput,
+		GroovyScripts:                 spec.Configuration.GroovyScripts,
+		ConfigurationAsCode:           spec.Configuration.ConfigurationAsCode,
+		ReadinessCheck:                spec.Configuration.ReadinessCheck,
+		ExtraResources:                spec.ExtraResources,
+		PrometheusRule:                spec.PrometheusRule,
+		AdoptionPolicy:                spec.AdoptionPolicy,
+		ImmutableConfiguration:        spec.ImmutableConfiguration,
+		Roles:                         spec.Roles,
+		ServiceAccount:                spec.ServiceAccount,
+		JenkinsAPISettings:            spec.JenkinsAPISettings,
+		CommonLabels:                  spec.CommonLabels,
+		CommonAnnotations:             spec.CommonAnnotations,
+	}
+	return nil
+}
+
+// ConvertFrom converts from the hub version v1alpha2 to this Jenkins
+func (in *Jenkins) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha2.Jenkins)
+	in.ObjectMeta = src.ObjectMeta
+	in.Status = src.Status
+
+	spec := src.Spec
+	in.Spec = JenkinsSpec{
+		InheritFrom:     spec.InheritFrom,
+		Master:          spec.Master,
+		ExternalJenkins: spec.ExternalJenkins,
+		Services: Services{
+			Master: spec.Service,
+			Agent:  spec.SlaveService,
+		},
+		SeedJobs: SeedJobs{
+			Jobs: spec.SeedJobs,
+			Agent: SeedJobAgent{
+				Image:             spec.SeedJobAgentImage,
+				PriorityClassName: spec.SeedJobAgentPriorityClassName,
+				WorkspaceCache:    spec.SeedJobAgentWorkspaceCache,
+				Template:          spec.SeedJobAgentTemplate,
+				Secret:            spec.SeedJobAgentSecret,
+			},
+			SCMWebhook: spec.SCMWebhook,
+		},
+		Jobs: spec.Jobs,
+		Configuration: Configuration{
+			Skip:                spec.SkipUserConfiguration,
+			Values:              spec.Values,
+			BaseGroovyScripts:   spec.BaseGroovyScripts,
+			GroovyScriptOutput:  spec.GroovyScriptOutput,
+			GroovyScripts:       spec.GroovyScripts,
+			ConfigurationAsCode: spec.ConfigurationAsCode,
+			ReadinessCheck:      spec.ReadinessCheck,
+		},
+		Backup: Backup{
+			Backup:  spec.Backup,
+			Restore: spec.Restore,
+		},
+		ValidateSecurityWarnings: spec.ValidateSecurityWarnings,
+		Notifications:            spec.Notifications,
+		ExtraResources:           spec.ExtraResources,
+		PrometheusRule:           spec.PrometheusRule,
+		AdoptionPolicy:           spec.AdoptionPolicy,
+		ImmutableConfiguration:   spec.ImmutableConfiguration,
+		Roles:                    spec.Roles,
+		ServiceAccount:           spec.ServiceAccount,
+		JenkinsAPISettings:       spec.JenkinsAPISettings,
+		CommonLabels:             spec.CommonLabels,
+		CommonAnnotations:        spec.CommonAnnotations,
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package v1alpha3
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestJenkinsConversion(t *testing.T) {
+	hub := &v1alpha2.Jenkins{
+		TypeMeta:   v1alpha2.JenkinsTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default", Generation: 2},
+		Spec: v1alpha2.JenkinsSpec{
+			InheritFrom: "base",
+			Master: v1alpha2.JenkinsMaster{
+				Containers: []v1alpha2.Container{{Name: "jenkins-master", Image: "jenkins/jenkins:lts"}},
+				Plugins:    []v1alpha2.Plugin{{Name: "git", Version: "4.7.1"}},
+			},
+			ExternalJenkins:               &v1alpha2.ExternalJenkins{URL: "https://jenkins.example.com/", CredentialsSecret: v1alpha2.SecretRef{Name: "jenkins-credentials"}},
+			SkipUserConfiguration:         true,
+			SeedJobs:                      []v1alpha2.SeedJob{{ID: "jenkins-operator", RepositoryURL: "https://github.com/maximba/kubernetes-operator.git"}},
+			SeedJobAgentImage:             "jenkins/inbound-agent:latest",
+			SeedJobAgentPriorityClassName: "high",
+			SeedJobAgentWorkspaceCache:    &v1alpha2.SeedJobAgentWorkspaceCache{},
+			SeedJobAgentTemplate:          &v1alpha2.SeedJobAgentTemplate{NodeSelector: map[string]string{"kind": "agent"}},
+			SeedJobAgentSecret:            &v1alpha2.SeedJobAgentSecret{RequireManagedSecret: true},
+			SCMWebhook:                    &v1alpha2.SCMWebhook{},
+			Jobs:                          v1alpha2.Jobs{Folders: []v1alpha2.Folder{{Name: "team"}}},
+			ValidateSecurityWarnings:      true,
+			Notifications:                 []v1alpha2.Notification{{Name: "slack", LoggingLevel: v1alpha2.NotificationLevelWarning}},
+			Service:                       v1alpha2.Service{Type: corev1.ServiceTypeNodePort, Port: 8080},
+			SlaveService:                  v1alpha2.Service{Type: corev1.ServiceTypeClusterIP, Port: 50000},
+			Backup:                        v1alpha2.Backup{ContainerName: "backup", Interval: 30},
+			Restore:                       v1alpha2.Restore{ContainerName: "backup", RecoveryOnce: 3},
+			Values:                        &v1alpha2.ConfigMapRef{Name: "values"},
+			BaseGroovyScripts:             v1alpha2.GroovyScripts{Parallelism: 2},
+			GroovyScriptOutput:            true,
+			GroovyScripts:                 v1alpha2.GroovyScripts{Parallelism: 4},
+			ConfigurationAsCode:           v1alpha2.ConfigurationAsCode{Customization: v1alpha2.Customization{Secret: v1alpha2.SecretRef{Name: "casc"}}},
+			ReadinessCheck:                &v1alpha2.ReadinessCheck{},
+			ExtraResources:                []v1alpha2.ExtraResource{{}},
+			PrometheusRule:                &v1alpha2.PrometheusRule{},
+			AdoptionPolicy:                v1alpha2.FailAdoptionPolicy,
+			ImmutableConfiguration:        &v1alpha2.ImmutableConfiguration{HistoryLimit: 5},
+			Roles:                         []rbacv1.RoleRef{{Kind: "Role", Name: "deployer"}},
+			ServiceAccount:                v1alpha2.ServiceAccount{Annotations: map[string]string{"a": "b"}},
+			JenkinsAPISettings:            v1alpha2.JenkinsAPISettings{AuthorizationStrategy: v1alpha2.ServiceAccountAuthorizationStrategy},
+			CommonLabels:                  map[string]string{"team": "ci"},
+			CommonAnnotations:             map[string]string{"owner": "ci"},
+		},
+		Status: v1alpha2.JenkinsStatus{BaseConfigurationCompletedTime: &metav1.Time{}},
+	}
+
+	jenkins := &Jenkins{}
+	require.NoError(t, jenkins.ConvertFrom(hub.DeepCopy()))
+
+	t.Run("spec is grouped", func(t *testing.T) {
+		assert.Equal(t, hub.ObjectMeta, jenkins.ObjectMeta)
+		assert.Equal(t, hub.Spec.SeedJobs, jenkins.Spec.SeedJobs.Jobs)
+		assert.Equal(t, hub.Spec.SeedJobAgentImage, jenkins.Spec.SeedJobs.Agent.Image)
+		assert.Equal(t, hub.Spec.SeedJobAgentTemplate, jenkins.Spec.SeedJobs.Agent.Template)
+		assert.Equal(t, hub.Spec.SeedJobAgentSecret, jenkins.Spec.SeedJobs.Agent.Secret)
+		assert.Equal(t, hub.Spec.Service, jenkins.Spec.Services.Master)
+		assert.Equal(t, hub.Spec.SlaveService, jenkins.Spec.Services.Agent)
+		assert.Equal(t, hub.Spec.Backup, jenkins.Spec.Backup.Backup)
+		assert.Equal(t, hub.Spec.Restore, jenkins.Spec.Backup.Restore)
+		assert.True(t, jenkins.Spec.Configuration.Skip)
+		assert.Equal(t, hub.Spec.ConfigurationAsCode, jenkins.Spec.Configuration.ConfigurationAsCode)
+		assert.Equal(t, hub.Status, jenkins.Status)
+	})
+	t.Run("round trip", func(t *testing.T) {
+		converted := &v1alpha2.Jenkins{}
+
+		require.NoError(t, jenkins.ConvertTo(converted))
+
+		assert.Equal(t, hub, converted)
+	})
+}
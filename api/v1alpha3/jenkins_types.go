@@ -0,0 +1,241 @@
+package v1alpha3
+
+import (
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// JenkinsSpec defines the desired state of Jenkins, the settings are grouped by the part of Jenkins they configure
+type JenkinsSpec struct {
+	// InheritFrom is the name of Jenkins CR in the same namespace which spec is deep-merged into this CR as defaults,
+	// only the values which differ from the inherited ones have to be set in this CR. The values set in this CR
+	// override the inherited ones including zero values and lists are replaced as a whole. The merged spec isn't
+	// stored in this CR, the hash of the inherited spec is recorded in jenkins.io/inherited-spec-hash annotation.
+	// +optional
+	InheritFrom string `json:"inheritFrom,omitempty"`
+
+	// Master represents Jenkins master pod properties and Jenkins plugins.
+	// Every single change here requires a pod restart.
+	Master v1alpha2.JenkinsMaster `json:"master"`
+
+	// ExternalJenkins makes the operator manage the configuration of an existing Jenkins which isn't created by
+	// the operator, e.g. one running on a VM. Jenkins master pod and its Kubernetes resources aren't created, only
+	// the base configuration groovy scripts, Configuration as Code, groovy scripts and seed jobs are applied.
+	// +optional
+	ExternalJenkins *v1alpha2.ExternalJenkins `json:"externalJenkins,omitempty"`
+
+	// Services defines Kubernetes services of Jenkins master and agents
+	// +optional
+	Services Services `json:"services,omitempty"`
+
+	// SeedJobs defines Jenkins seed jobs and the agent which runs them
+	// +optional
+	SeedJobs SeedJobs `json:"seedJobs,omitempty"`
+
+	// Jobs defines Jenkins folders and views managed by the operator
+	// +optional
+	Jobs v1alpha2.Jobs `json:"jobs,omitempty"`
+
+	// Configuration defines the user configuration of Jenkins applied after the base configuration
+	// +optional
+	Configuration Configuration `json:"configuration,omitempty"`
+
+	// Backup defines configuration of Jenkins backup and restore
+	// More info: https://jenkinsci.github.io/kubernetes-operator/docs/getting-started/latest/configure-backup-and-restore/
+	// +optional
+	Backup Backup `json:"backup,omitempty"`
+
+	// ValidateSecurityWarnings enables or disables validating potential security warnings in Jenkins plugins via admission webhooks.
+	// +optional
+	ValidateSecurityWarnings bool `json:"validateSecurityWarnings,omitempty"`
+
+	// Notifications defines list of a services which are used to inform about Jenkins status
+	// Can be used to integrate chat services like Slack, Microsoft Teams or Mailgun
+	// +optional
+	Notifications []v1alpha2.Notification `json:"notifications,omitempty"`
+
+	// ExtraResources are Kubernetes objects applied and owned by the operator alongside the Jenkins instance,
+	// e.g. ExternalSecret, Certificate or custom Service. The objects are created in the Jenkins CR namespace.
+	// Only ConfigMap, Service, PersistentVolumeClaim, Ingress, NetworkPolicy, PodDisruptionBudget, Certificate,
+	// ExternalSecret, ServiceMonitor and PodMonitor are allowed, kinds granting permissions or running workloads
+	// are rejected.
+	// +optional
+	ExtraResources []v1alpha2.ExtraResource `json:"extraResources,omitempty"`
+
+	// PrometheusRule generates Prometheus Operator PrometheusRule with alerts of the Jenkins instance: backup is
+	// stale, instance is not ready and the master pod is restarted too often. The PrometheusRule CRD has to be installed.
+	// +optional
+	PrometheusRule *v1alpha2.PrometheusRule `json:"prometheusRule,omitempty"`
+
+	// AdoptionPolicy defines what happens when a resource managed by the operator already exists but isn't owned by
+	// this Jenkins CR: Adopt takes it over and sets the owner reference, Fail stops the reconciliation and
+	// Ignore leaves the resource untouched
+	// +kubebuilder:validation:Enum=Adopt;Fail;Ignore
+	// +kubebuilder:default=Adopt
+	// +optional
+	AdoptionPolicy v1alpha2.AdoptionPolicy `json:"adoptionPolicy,omitempty"`
+
+	// ImmutableConfiguration enables the immutable mode: the operator never updates its groovy ConfigMaps and
+	// the operator credentials Secret, every change creates a new immutable version referenced by Jenkins master pod
+	// +optional
+	ImmutableConfiguration *v1alpha2.ImmutableConfiguration `json:"immutableConfiguration,omitempty"`
+
+	// Roles defines list of extra RBAC roles for the Jenkins Master pod service account
+	// +optional
+	Roles []rbacv1.RoleRef `json:"roles,omitempty"`
+
+	// ServiceAccount defines Jenkins master service account attributes
+	// +optional
+	ServiceAccount v1alpha2.ServiceAccount `json:"serviceAccount,omitempty"`
+
+	// JenkinsAPISettings defines configuration used by the operator to gain admin access to the Jenkins API
+	// +kubebuilder:default={authorizationStrategy: createUser}
+	// +optional
+	JenkinsAPISettings v1alpha2.JenkinsAPISettings `json:"jenkinsAPISettings,omitempty"`
+
+	// CommonLabels are added to every Kubernetes resource created by the operator for this Jenkins CR.
+	// Labels required by the operator take precedence over them.
+	// +optional
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+
+	// CommonAnnotations are added to every Kubernetes resource created by the operator for this Jenkins CR
+	// +optional
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+}
+
+// Services defines Kubernetes services of Jenkins
+type Services struct {
+	// Master is Kubernetes service of Jenkins master HTTP pod
+	// Defaults to :
+	// port: 8080
+	// type: ClusterIP or NodePort if the operator connects to Jenkins API by node port
+	// +optional
+	Master v1alpha2.Service `json:"master,omitempty"`
+
+	// Agent is Kubernetes service of Jenkins agent pods
+	// +kubebuilder:default={type: ClusterIP, port: 50000}
+	// +optional
+	Agent v1alpha2.Service `json:"agent,omitempty"`
+}
+
+// SeedJobs defines Jenkins seed jobs and the agent which runs them
+type SeedJobs struct {
+	// Jobs defines list of Jenkins Seed Job configurations
+	// More info: https://jenkinsci.github.io/kubernetes-operator/docs/getting-started/latest/configuration#configure-seed-jobs-and-pipelines
+	// +optional
+	Jobs []v1alpha2.SeedJob `json:"jobs,omitempty"`
+
+	// Agent defines the seed job agent pod
+	// +optional
+	Agent SeedJobAgent `json:"agent,omitempty"`
+
+	// SCMWebhook enables the operator endpoint /scm-webhook/<namespace>/<name> which accepts GitHub and GitLab
+	// push events and triggers the seed jobs of the pushed repository and branch
+	// +optional
+	SCMWebhook *v1alpha2.SCMWebhook `json:"scmWebhook,omitempty"`
+}
+
+// SeedJobAgent defines the seed job agent pod
+type SeedJobAgent struct {
+	// Image defines the image that will be used by the seed job agent. If not defined jenkins/inbound-agent will be used.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// PriorityClassName is the name of the PriorityClass used by the seed job agent pod.
+	// The preemption policy of the agent pod is taken from the PriorityClass.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// WorkspaceCache configures PersistentVolumeClaim used by the seed job agent to keep cloned
+	// repositories between seed job runs, seed jobs use workspaces keyed by the repository URL
+	// +optional
+	WorkspaceCache *v1alpha2.SeedJobAgentWorkspaceCache `json:"workspaceCache,omitempty"`
+
+	// Template customizes the seed job agent pod, settings which are not defined are taken from
+	// spec.master (nodeSelector, tolerations) or operator defaults
+	// +optional
+	Template *v1alpha2.SeedJobAgentTemplate `json:"template,omitempty"`
+
+	// Secret defines how the inbound secret of the seed job agent is passed to the agent pod and rotated
+	// +optional
+	Secret *v1alpha2.SeedJobAgentSecret `json:"secret,omitempty"`
+}
+
+// Configuration defines the user configuration of Jenkins
+type Configuration struct {
+	// Skip suspends the user configuration phase, Jenkins is provisioned with the base configuration
+	// only. Seed jobs, groovy scripts, Configuration as Code and backups aren't validated nor applied until it's disabled,
+	// e.g. when seed job repositories are temporarily unreachable.
+	// +optional
+	Skip bool `json:"skip,omitempty"`
+
+	// Values is the ConfigMap which key/values are available in groovy scripts and Configuration as Code templates
+	// as {{ .Values.key }}. The keys numExecutors, kubernetesCloudName, seedJobsViewRegex and nonSeedJobsViewRegex
+	// override the defaults of the operator base configuration.
+	// +optional
+	Values *v1alpha2.ConfigMapRef `json:"values,omitempty"`
+
+	// BaseGroovyScripts defines groovy scripts applied in the base configuration phase after the operator base
+	// configuration, e.g. to configure Kubernetes clouds of external clusters
+	// +optional
+	BaseGroovyScripts v1alpha2.GroovyScripts `json:"baseGroovyScripts,omitempty"`
+
+	// GroovyScriptOutput records the tail of the output of the groovy scripts in status.groovyScriptResults,
+	// status.baseConfigurationProgress and the groovy script events, only the errors are recorded by default. The output
+	// may contain secrets printed by the scripts.
+	// +optional
+	GroovyScriptOutput bool `json:"groovyScriptOutput,omitempty"`
+
+	// GroovyScripts defines configuration of Jenkins customization via groovy scripts
+	// +optional
+	GroovyScripts v1alpha2.GroovyScripts `json:"groovyScripts,omitempty"`
+
+	// ConfigurationAsCode defines configuration of Jenkins customization via Configuration as Code Jenkins plugin
+	// +optional
+	ConfigurationAsCode v1alpha2.ConfigurationAsCode `json:"configurationAsCode,omitempty"`
+
+	// ReadinessCheck is the groovy smoke test script executed after the base and user configuration is applied,
+	// the Jenkins CR is marked as ready in status only when the script succeeds
+	// +optional
+	ReadinessCheck *v1alpha2.ReadinessCheck `json:"readinessCheck,omitempty"`
+}
+
+// Backup defines configuration of Jenkins backup and restore
+type Backup struct {
+	v1alpha2.Backup `json:",inline"`
+
+	// Restore defines configuration of Jenkins backup restore
+	// +optional
+	Restore v1alpha2.Restore `json:"restore,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.jenkinsVersion`
+// +kubebuilder:printcolumn:name="Seed Jobs",type=string,JSONPath=`.status.seedJobs`
+// +kubebuilder:printcolumn:name="Last Backup",type=date,JSONPath=`.status.lastBackupTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Jenkins is the Schema for the jenkins API
+type Jenkins struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the Jenkins
+	Spec JenkinsSpec `json:"spec,omitempty"`
+
+	// Status defines the observed state of Jenkins
+	Status v1alpha2.JenkinsStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// JenkinsList contains a list of Jenkins
+type JenkinsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Jenkins `json:"items"`
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/backuprestore"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// cloneJenkins creates the Jenkins CR requested by jenkins.io/clone-to annotation with the same configuration and
+// the latest backup of the Jenkins CR restored, the annotation is removed whether the clone is created or not
+func (r *JenkinsReconciler) cloneJenkins(jenkins *v1alpha2.Jenkins) (requeue bool, err error) {
+	name, found := jenkins.Annotations[backuprestore.CloneToAnnotation]
+	if !found {
+		return false, nil
+	}
+	logger := logx.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace)
+
+	var problem string
+	switch {
+	case len(name) == 0 || name == jenkins.Name:
+		problem = fmt.Sprintf("invalid clone name '%s'", name)
+	case !backuprestore.IsS3Backup(jenkins) && (len(jenkins.Spec.Restore.ContainerName) == 0 || jenkins.Spec.Restore.Action.Exec == nil):
+		problem = "spec.restore is not configured, the backup can't be restored into the clone"
+	default:
+		err = r.Client.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: name}, &v1alpha2.Jenkins{})
+		if err == nil {
+			problem = fmt.Sprintf("Jenkins CR '%s' already exists", name)
+		} else if !apierrors.IsNotFound(err) {
+			return false, errors.WithStack(err)
+		}
+	}
+
+	if len(problem) > 0 {
+		message := fmt.Sprintf("Jenkins CR can't be cloned, %s", problem)
+		logger.V(log.VWarn).Info(message)
+		r.notifyClone(jenkins, v1alpha2.NotificationLevelWarning, message)
+	} else {
+		if err = r.Client.Create(context.TODO(), backuprestore.NewClone(jenkins, name)); err != nil {
+			return false, errors.WithStack(err)
+		}
+		message := fmt.Sprintf("Jenkins CR has been cloned into '%s'", name)
+		if jenkins.Status.LastBackup > 0 {
+			message = fmt.Sprintf("%s, backup '%d' will be restored", message, jenkins.Status.LastBackup)
+		} else {
+			message = fmt.Sprintf("%s, there is no backup to restore yet", message)
+		}
+		logger.Info(message)
+		r.notifyClone(jenkins, v1alpha2.NotificationLevelInfo, message)
+	}
+
+	delete(jenkins.Annotations, backuprestore.CloneToAnnotation)
+	return true, errors.WithStack(r.Client.Update(context.TODO(), jenkins))
+}
+
+func (r *JenkinsReconciler) notifyClone(jenkins *v1alpha2.Jenkins, level v1alpha2.NotificationLevel, message string) {
+	if r.NotificationEvents == nil {
+		return
+	}
+	*r.NotificationEvents <- event.Event{
+		Jenkins: *jenkins,
+		Phase:   event.PhaseBase,
+		Level:   level,
+		Reason:  reason.NewJenkinsCloned(reason.HumanSource, []string{message}),
+	}
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/backuprestore"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestJenkinsReconciler_cloneJenkins(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	ctx := context.TODO()
+	newJenkins := func() *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "production",
+				Namespace:   "default",
+				Annotations: map[string]string{backuprestore.CloneToAnnotation: "staging"},
+			},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{Containers: []v1alpha2.Container{{Name: "jenkins-master", Image: "jenkins/jenkins:lts"}}},
+				Backup: v1alpha2.Backup{ContainerName: "backup", MakeBackupBeforePodDeletion: true},
+				Restore: v1alpha2.Restore{
+					ContainerName: "backup",
+					Action:        v1alpha2.Handler{Exec: &corev1.ExecAction{Command: []string{"/home/user/bin/restore.sh"}}},
+				},
+				JenkinsAPISettings: v1alpha2.JenkinsAPISettings{Hostname: "jenkins.example.com", AuthorizationStrategy: v1alpha2.CreateUserAuthorizationStrategy},
+				Service:            v1alpha2.Service{Type: corev1.ServiceTypeNodePort, NodePort: 30303},
+			},
+			Status: v1alpha2.JenkinsStatus{LastBackup: 7},
+		}
+	}
+	newReconciler := func(jenkins *v1alpha2.Jenkins) (*JenkinsReconciler, chan event.Event) {
+		notifications := make(chan event.Event, 1)
+		return &JenkinsReconciler{
+			Client:             fake.NewClientBuilder().WithObjects(jenkins).Build(),
+			NotificationEvents: &notifications,
+		}, notifications
+	}
+
+	t.Run("no annotation", func(t *testing.T) {
+		jenkins := newJenkins()
+		jenkins.Annotations = nil
+		reconciler, _ := newReconciler(jenkins)
+
+		requeue, err := reconciler.cloneJenkins(jenkins)
+
+		assert.NoError(t, err)
+		assert.False(t, requeue)
+	})
+	t.Run("clone", func(t *testing.T) {
+		jenkins := newJenkins()
+		reconciler, notifications := newReconciler(jenkins)
+
+		requeue, err := reconciler.cloneJenkins(jenkins)
+
+		require.NoError(t, err)
+		assert.True(t, requeue)
+		clone := &v1alpha2.Jenkins{}
+		require.NoError(t, reconciler.Client.Get(ctx, types.NamespacedName{Namespace: "default", Name: "staging"}, clone))
+		assert.Equal(t, "production", clone.Labels[backuprestore.ClonedFromLabel])
+		assert.Empty(t, clone.OwnerReferences)
+		assert.True(t, resources.IsQuietDown(clone))
+		assert.Equal(t, "jenkins/jenkins:lts", clone.Spec.Master.Containers[0].Image)
+		assert.Equal(t, uint64(7), clone.Spec.Restore.RecoveryOnce)
+		assert.NotNil(t, clone.Spec.Backup.DryRun)
+		assert.False(t, clone.Spec.Backup.MakeBackupBeforePodDeletion)
+		assert.Empty(t, clone.Spec.JenkinsAPISettings.Hostname)
+		assert.Equal(t, corev1.ServiceTypeNodePort, clone.Spec.Service.Type)
+		assert.Equal(t, int32(0), clone.Spec.Service.NodePort)
+
+		source := &v1alpha2.Jenkins{}
+		require.NoError(t, reconciler.Client.Get(ctx, types.NamespacedName{Namespace: "default", Name: "production"}, source))
+		assert.NotContains(t, source.Annotations, backuprestore.CloneToAnnotation)
+		notification := <-notifications
+		assert.Equal(t, v1alpha2.NotificationLevelInfo, notification.Level)
+		assert.Equal(t, []string{"Jenkins CR has been cloned into 'staging', backup '7' will be restored"}, notification.Reason.Short())
+	})
+	t.Run("restore not configured", func(t *testing.T) {
+		jenkins := newJenkins()
+		jenkins.Spec.Restore = v1alpha2.Restore{}
+		reconciler, notifications := newReconciler(jenkins)
+
+		requeue, err := reconciler.cloneJenkins(jenkins)
+
+		require.NoError(t, err)
+		assert.True(t, requeue)
+		assert.Error(t, reconciler.Client.Get(ctx, types.NamespacedName{Namespace: "default", Name: "staging"}, &v1alpha2.Jenkins{}))
+		notification := <-notifications
+		assert.Equal(t, v1alpha2.NotificationLevelWarning, notification.Level)
+	})
+}
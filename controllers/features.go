@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/backuprestore"
+
+	"github.com/bndr/gojenkins"
+)
+
+// Reasons of Jenkins CR status features
+const (
+	featureReasonConfigured         = "Configured"
+	featureReasonNotConfigured      = "NotConfigured"
+	featureReasonAPINotAvailable    = "APINotAvailable"
+	featureReasonPluginNotInstalled = "PluginNotInstalled"
+	featureReasonExternalJenkins    = "ExternalJenkins"
+)
+
+const (
+	configurationAsCodePluginName = "configuration-as-code"
+	ingressKind                   = "Ingress"
+	fetchAllPlugins               = 1
+)
+
+// setFeatures updates the optional subsystems active for the Jenkins instance in status, it returns true if
+// the status has changed
+func setFeatures(jenkins *v1alpha2.Jenkins, routeAPIAvailable bool, installedPlugins *gojenkins.Plugins) bool {
+	features := []v1alpha2.FeatureStatus{
+		getBackupFeature(jenkins),
+		getRouteFeature(jenkins, routeAPIAvailable),
+		getIngressFeature(jenkins),
+		getConfigurationAsCodeFeature(jenkins, installedPlugins),
+		getNotificationsFeature(jenkins),
+	}
+	if reflect.DeepEqual(features, jenkins.Status.Features) {
+		return false
+	}
+	jenkins.Status.Features = features
+	return true
+}
+
+func getBackupFeature(jenkins *v1alpha2.Jenkins) v1alpha2.FeatureStatus {
+	switch {
+	case backuprestore.IsS3Backup(jenkins):
+		return enabledFeature(v1alpha2.FeatureBackup, "Backups are made by the operator to S3-compatible object storage")
+	case backuprestore.IsBackupConfigured(jenkins):
+		return enabledFeature(v1alpha2.FeatureBackup, fmt.Sprintf("Backups are made by container '%s'", jenkins.Spec.Backup.ContainerName))
+	default:
+		return disabledFeature(v1alpha2.FeatureBackup, featureReasonNotConfigured, "Neither spec.backup.containerName nor spec.backup.s3 is set")
+	}
+}
+
+func getRouteFeature(jenkins *v1alpha2.Jenkins, routeAPIAvailable bool) v1alpha2.FeatureStatus {
+	switch {
+	case jenkins.Spec.ExternalJenkins != nil:
+		return disabledFeature(v1alpha2.FeatureRoute, featureReasonExternalJenkins, "Route isn't created for Jenkins which isn't created by the operator")
+	case !routeAPIAvailable:
+		return disabledFeature(v1alpha2.FeatureRoute, featureReasonAPINotAvailable, "OpenShift Route API isn't available in the cluster")
+	default:
+		return enabledFeature(v1alpha2.FeatureRoute, "Route of Jenkins HTTP service is created")
+	}
+}
+
+func getIngressFeature(jenkins *v1alpha2.Jenkins) v1alpha2.FeatureStatus {
+	for _, resource := range jenkins.Status.AppliedExtraResources {
+		if resource.Kind == ingressKind {
+			return enabledFeature(v1alpha2.FeatureIngress, fmt.Sprintf("Ingress '%s' is applied from spec.extraResources", resource.Name))
+		}
+	}
+	return disabledFeature(v1alpha2.FeatureIngress, featureReasonNotConfigured, "No Ingress is applied from spec.extraResources")
+}
+
+// getConfigurationAsCodeFeature reads the plugin state from Jenkins, the plugin can be missing in spec when it's
+// bundled in the Jenkins image or spec.master.skipBaseConfiguration is set
+func getConfigurationAsCodeFeature(jenkins *v1alpha2.Jenkins, installedPlugins *gojenkins.Plugins) v1alpha2.FeatureStatus {
+	if len(jenkins.Spec.ConfigurationAsCode.Configurations) == 0 {
+		return disabledFeature(v1alpha2.FeatureConfigurationAsCode, featureReasonNotConfigured, "spec.configurationAsCode.configurations is empty")
+	}
+	plugin := installedPlugins.Contains(configurationAsCodePluginName)
+	if plugin == nil || !plugin.Active || !plugin.Enabled || plugin.Deleted {
+		return disabledFeature(v1alpha2.FeatureConfigurationAsCode, featureReasonPluginNotInstalled,
+			fmt.Sprintf("Plugin '%s' is not installed or not active in Jenkins", configurationAsCodePluginName))
+	}
+	return enabledFeature(v1alpha2.FeatureConfigurationAsCode, "Configuration as Code is reapplied when the ConfigMaps or the Secret change")
+}
+
+func getNotificationsFeature(jenkins *v1alpha2.Jenkins) v1alpha2.FeatureStatus {
+	if len(jenkins.Spec.Notifications) == 0 {
+		return disabledFeature(v1alpha2.FeatureNotifications, featureReasonNotConfigured, "spec.notifications is empty")
+	}
+	return enabledFeature(v1alpha2.FeatureNotifications, fmt.Sprintf("%d notification providers are configured", len(jenkins.Spec.Notifications)))
+}
+
+func enabledFeature(name, message string) v1alpha2.FeatureStatus {
+	return v1alpha2.FeatureStatus{Name: name, Enabled: true, Reason: featureReasonConfigured, Message: message}
+}
+
+func disabledFeature(name, reason, message string) v1alpha2.FeatureStatus {
+	return v1alpha2.FeatureStatus{Name: name, Enabled: false, Reason: reason, Message: message}
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/bndr/gojenkins"
+	"github.com/stretchr/testify/assert"
+)
+
+func newInstalledPlugins(plugins ...gojenkins.Plugin) *gojenkins.Plugins {
+	return &gojenkins.Plugins{Raw: &gojenkins.PluginResponse{Plugins: plugins}}
+}
+
+func findFeature(t *testing.T, jenkins *v1alpha2.Jenkins, name string) v1alpha2.FeatureStatus {
+	for _, feature := range jenkins.Status.Features {
+		if feature.Name == name {
+			return feature
+		}
+	}
+	t.Fatalf("feature '%s' not found", name)
+	return v1alpha2.FeatureStatus{}
+}
+
+func TestSetFeatures(t *testing.T) {
+	t.Run("nothing configured", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{}
+
+		assert.True(t, setFeatures(jenkins, false, newInstalledPlugins()))
+
+		assert.Len(t, jenkins.Status.Features, 5)
+		for _, feature := range jenkins.Status.Features {
+			assert.False(t, feature.Enabled, feature.Name)
+		}
+		assert.Equal(t, featureReasonNotConfigured, findFeature(t, jenkins, v1alpha2.FeatureBackup).Reason)
+		assert.Equal(t, featureReasonAPINotAvailable, findFeature(t, jenkins, v1alpha2.FeatureRoute).Reason)
+		assert.Equal(t, featureReasonNotConfigured, findFeature(t, jenkins, v1alpha2.FeatureIngress).Reason)
+		assert.Equal(t, featureReasonNotConfigured, findFeature(t, jenkins, v1alpha2.FeatureConfigurationAsCode).Reason)
+		assert.Equal(t, featureReasonNotConfigured, findFeature(t, jenkins, v1alpha2.FeatureNotifications).Reason)
+		assert.False(t, setFeatures(jenkins, false, newInstalledPlugins()))
+	})
+	t.Run("everything configured", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				Backup:        v1alpha2.Backup{ContainerName: "backup"},
+				Notifications: []v1alpha2.Notification{{Name: "slack"}},
+				ConfigurationAsCode: v1alpha2.ConfigurationAsCode{
+					Customization: v1alpha2.Customization{Configurations: []v1alpha2.ConfigMapRef{{Name: "casc"}}},
+				},
+			},
+			Status: v1alpha2.JenkinsStatus{
+				AppliedExtraResources: []v1alpha2.ExtraResourceReference{{APIVersion: "networking.k8s.io/v1", Kind: "Ingress", Name: "jenkins"}},
+			},
+		}
+
+		installedPlugins := newInstalledPlugins(gojenkins.Plugin{ShortName: "configuration-as-code", Active: true, Enabled: true})
+
+		assert.True(t, setFeatures(jenkins, true, installedPlugins))
+
+		for _, feature := range jenkins.Status.Features {
+			assert.True(t, feature.Enabled, feature.Name)
+			assert.Equal(t, featureReasonConfigured, feature.Reason)
+		}
+	})
+	t.Run("configuration as code plugin in spec but not active", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					BasePlugins: []v1alpha2.Plugin{{Name: "configuration-as-code", Version: "1.0"}},
+				},
+				ConfigurationAsCode: v1alpha2.ConfigurationAsCode{
+					Customization: v1alpha2.Customization{Configurations: []v1alpha2.ConfigMapRef{{Name: "casc"}}},
+				},
+			},
+		}
+		installedPlugins := newInstalledPlugins(gojenkins.Plugin{ShortName: "configuration-as-code", Active: false, Enabled: true})
+
+		setFeatures(jenkins, true, installedPlugins)
+
+		feature := findFeature(t, jenkins, v1alpha2.FeatureConfigurationAsCode)
+		assert.False(t, feature.Enabled)
+		assert.Equal(t, featureReasonPluginNotInstalled, feature.Reason)
+	})
+	t.Run("route of external Jenkins", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{Spec: v1alpha2.JenkinsSpec{ExternalJenkins: &v1alpha2.ExternalJenkins{URL: "https://jenkins.example.com"}}}
+
+		setFeatures(jenkins, true, newInstalledPlugins())
+
+		feature := findFeature(t, jenkins, v1alpha2.FeatureRoute)
+		assert.False(t, feature.Enabled)
+		assert.Equal(t, featureReasonExternalJenkins, feature.Reason)
+	})
+}
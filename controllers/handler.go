@@ -7,6 +7,7 @@ import (
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
 	"github.com/maximba/kubernetes-operator/pkg/constants"
 	"github.com/maximba/kubernetes-operator/pkg/log"
+	notificationevent "github.com/maximba/kubernetes-operator/pkg/notifications/event"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -38,7 +39,7 @@ func (e *enqueueRequestForJenkins) Update(evt event.UpdateEvent, q workqueue.Rat
 			jenkinsName = req2.Name
 		}
 
-		log.Log.WithValues("cr", jenkinsName).Info(
+		log.Log.WithValues("cr", jenkinsName, "namespace", evt.ObjectNew.GetNamespace()).Info(
 			fmt.Sprintf("%T/%s has been updated", evt.ObjectNew, evt.ObjectNew.GetName()))
 	}
 
@@ -76,24 +77,25 @@ func (e *enqueueRequestForJenkins) getOwnerReconcileRequests(object metav1.Objec
 }
 
 type jenkinsDecorator struct {
-	handler handler.EventHandler
+	handler            handler.EventHandler
+	notificationEvents *chan notificationevent.Event
 }
 
 func (e *jenkinsDecorator) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
-	log.Log.WithValues("cr", evt.Object.GetName()).Info(fmt.Sprintf("%T/%s was created", evt.Object, evt.Object.GetName()))
+	log.Log.WithValues("cr", evt.Object.GetName(), "namespace", evt.Object.GetNamespace()).Info(fmt.Sprintf("%T/%s was created", evt.Object, evt.Object.GetName()))
 	e.handler.Create(evt, q)
 }
 
 func (e *jenkinsDecorator) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
-	if !reflect.DeepEqual(evt.ObjectOld.(*v1alpha2.Jenkins).Spec, evt.ObjectNew.(*v1alpha2.Jenkins).Spec) {
-		log.Log.WithValues("cr", evt.ObjectNew.GetName()).Info(
-			fmt.Sprintf("%T/%s has been updated", evt.ObjectNew, evt.ObjectNew.GetName()))
+	oldJenkins, newJenkins := evt.ObjectOld.(*v1alpha2.Jenkins), evt.ObjectNew.(*v1alpha2.Jenkins)
+	if !reflect.DeepEqual(oldJenkins.Spec, newJenkins.Spec) {
+		e.notifySpecUpdate(oldJenkins, newJenkins)
 	}
 	e.handler.Update(evt, q)
 }
 
 func (e *jenkinsDecorator) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
-	log.Log.WithValues("cr", evt.Object.GetName()).Info(fmt.Sprintf("%T/%s was deleted", evt.Object, evt.Object.GetName()))
+	log.Log.WithValues("cr", evt.Object.GetName(), "namespace", evt.Object.GetNamespace()).Info(fmt.Sprintf("%T/%s was deleted", evt.Object, evt.Object.GetName()))
 	e.handler.Delete(evt, q)
 }
 
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/inheritance"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// inheritSpec merges spec of the Jenkins CR referenced by spec.inheritFrom into the Jenkins CR in memory, the Jenkins CR
+// isn't updated, only the hash of the inherited spec is recorded in its annotation
+func (r *JenkinsReconciler) inheritSpec(jenkins *v1alpha2.Jenkins) error {
+	if len(jenkins.Spec.InheritFrom) == 0 {
+		if _, found := jenkins.Annotations[inheritance.InheritedSpecHashAnnotation]; found {
+			return r.setInheritedSpecHash(jenkins, nil)
+		}
+		return nil
+	}
+
+	parent, err := r.getInheritedJenkins(jenkins)
+	if err != nil {
+		return err
+	}
+	// the typed Jenkins CR doesn't tell the zero values set by the user from the values which aren't set
+	stored := &unstructured.Unstructured{}
+	stored.SetGroupVersionKind(v1alpha2.GroupVersion.WithKind("Jenkins"))
+	if err = r.Client.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name}, stored); err != nil {
+		return errors.WithStack(err)
+	}
+	spec, _, err := unstructured.NestedMap(stored.Object, "spec")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	hash, err := inheritance.Inherit(jenkins, spec, parent)
+	if err != nil {
+		return err
+	}
+	if jenkins.Annotations[inheritance.InheritedSpecHashAnnotation] != hash {
+		logx.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace).Info(fmt.Sprintf("Inheriting spec from Jenkins CR '%s'", parent.Name))
+		return r.setInheritedSpecHash(jenkins, &hash)
+	}
+	return nil
+}
+
+// setInheritedSpecHash patches only the inherited spec hash annotation, so the merged spec isn't stored in
+// the Jenkins CR, the annotation is removed when hash is nil
+func (r *JenkinsReconciler) setInheritedSpecHash(jenkins *v1alpha2.Jenkins, hash *string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{inheritance.InheritedSpecHashAnnotation: hash},
+		},
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	patched := jenkins.DeepCopy()
+	if err = r.Client.Patch(context.TODO(), patched, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return errors.WithStack(err)
+	}
+	jenkins.Annotations = patched.Annotations
+	jenkins.ResourceVersion = patched.ResourceVersion
+	return nil
+}
+
+// getInheritedJenkins returns the Jenkins CR referenced by spec.inheritFrom, it fails when the inheritance
+// chain contains a cycle
+func (r *JenkinsReconciler) getInheritedJenkins(jenkins *v1alpha2.Jenkins) (*v1alpha2.Jenkins, error) {
+	var parent *v1alpha2.Jenkins
+	visited := map[string]bool{jenkins.Name: true}
+	for name := jenkins.Spec.InheritFrom; len(name) > 0; {
+		if visited[name] {
+			return nil, errors.Errorf("spec.inheritFrom of Jenkins CR '%s' creates a cycle", jenkins.Name)
+		}
+		visited[name] = true
+
+		current := &v1alpha2.Jenkins{}
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: name}, current)
+		if err != nil && apierrors.IsNotFound(err) {
+			return nil, errors.Errorf("inherited Jenkins CR '%s' not found", name)
+		} else if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if parent == nil {
+			parent = current
+		}
+		name = current.Spec.InheritFrom
+	}
+	return parent, nil
+}
+
+// inheritingJenkins maps Jenkins CR to reconcile requests of Jenkins CRs which inherit from it
+func (r *JenkinsReconciler) inheritingJenkins(object client.Object) []reconcile.Request {
+	jenkinsList := &v1alpha2.JenkinsList{}
+	if err := r.Client.List(context.TODO(), jenkinsList, client.InNamespace(object.GetNamespace())); err != nil {
+		logx.Info(fmt.Sprintf("Failed to list Jenkins CRs inheriting from '%s': %s", object.GetName(), err))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, jenkins := range jenkinsList.Items {
+		if jenkins.Spec.InheritFrom == object.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/inheritance"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestJenkinsReconciler_inheritSpec(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	ctx := context.TODO()
+	key := types.NamespacedName{Namespace: "default", Name: "team-a"}
+	parent := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			Master: v1alpha2.JenkinsMaster{
+				Containers:            []v1alpha2.Container{{Name: "jenkins-master", Image: "jenkins/jenkins:2.319.1-lts"}},
+				DisableCSRFProtection: true,
+			},
+		},
+	}
+	// created from manifest with only the values set by the user
+	jenkins := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": v1alpha2.GroupVersion.String(),
+		"kind":       "Jenkins",
+		"metadata":   map[string]interface{}{"name": key.Name, "namespace": key.Namespace},
+		"spec": map[string]interface{}{
+			"inheritFrom": parent.Name,
+			"seedJobs":    []interface{}{map[string]interface{}{"id": "team-a"}},
+		},
+	}}
+	reconciler := &JenkinsReconciler{Client: fake.NewClientBuilder().WithObjects(parent, jenkins).Build()}
+	getStored := func(t *testing.T) *v1alpha2.Jenkins {
+		stored := &v1alpha2.Jenkins{}
+		require.NoError(t, reconciler.Client.Get(ctx, key, stored))
+		return stored
+	}
+
+	t.Run("merges spec in memory", func(t *testing.T) {
+		reconciled := getStored(t)
+
+		require.NoError(t, reconciler.inheritSpec(reconciled))
+
+		assert.Equal(t, "jenkins/jenkins:2.319.1-lts", reconciled.Spec.Master.Containers[0].Image)
+		assert.Equal(t, []v1alpha2.SeedJob{{ID: "team-a"}}, reconciled.Spec.SeedJobs)
+		stored := getStored(t)
+		assert.Empty(t, stored.Spec.Master.Containers)
+		assert.NotEmpty(t, stored.Annotations[inheritance.InheritedSpecHashAnnotation])
+		assert.Equal(t, stored.Annotations, reconciled.Annotations)
+		assert.Equal(t, stored.ResourceVersion, reconciled.ResourceVersion)
+	})
+	t.Run("zero value overrides inherited value", func(t *testing.T) {
+		stored := getStored(t)
+		require.NoError(t, reconciler.inheritSpec(stored))
+		assert.True(t, stored.Spec.Master.DisableCSRFProtection)
+
+		stored = getStored(t)
+		stored.Spec.Master.DisableCSRFProtection = false
+		require.NoError(t, reconciler.Client.Update(ctx, stored))
+
+		require.NoError(t, reconciler.inheritSpec(stored))
+
+		assert.False(t, stored.Spec.Master.DisableCSRFProtection)
+		assert.Equal(t, "jenkins/jenkins:2.319.1-lts", stored.Spec.Master.Containers[0].Image)
+	})
+	t.Run("annotation removed when inheritance is disabled", func(t *testing.T) {
+		stored := getStored(t)
+		stored.Spec.InheritFrom = ""
+		require.NoError(t, reconciler.Client.Update(ctx, stored))
+
+		require.NoError(t, reconciler.inheritSpec(stored))
+
+		assert.NotContains(t, getStored(t).Annotations, inheritance.InheritedSpecHashAnnotation)
+	})
+}
@@ -21,29 +21,38 @@ import (
 	"fmt"
 	"math/rand"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
 	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
 	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/backuprestore"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/base"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/user"
 	"github.com/maximba/kubernetes-operator/pkg/constants"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
 	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/metrics"
 	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
 	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
 	"github.com/maximba/kubernetes-operator/pkg/plugins"
+	"github.com/maximba/kubernetes-operator/pkg/runtimeconfig"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -73,7 +82,11 @@ type JenkinsReconciler struct {
 	ClientSet                    kubernetes.Clientset
 	Config                       rest.Config
 	NotificationEvents           *chan event.Event
+	Events                       k8sevent.Recorder
 	KubernetesClusterDomain      string
+	RuntimeConfig                *runtimeconfig.Config
+
+	state reconcileState
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -81,18 +94,28 @@ func (r *JenkinsReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	jenkinsHandler := &enqueueRequestForJenkins{}
 	configMapResource := &source.Kind{Type: &corev1.ConfigMap{TypeMeta: metav1.TypeMeta{APIVersion: APIVersion, Kind: ConfigMapKind}}}
 	secretResource := &source.Kind{Type: &corev1.Secret{TypeMeta: metav1.TypeMeta{APIVersion: APIVersion, Kind: SecretKind}}}
-	decorator := jenkinsDecorator{handler: &handler.EnqueueRequestForObject{}}
+	decorator := jenkinsDecorator{handler: &handler.EnqueueRequestForObject{}, notificationEvents: r.NotificationEvents}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha2.Jenkins{}).
 		Owns(&corev1.Pod{}).
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.PersistentVolumeClaim{}).
 		Watches(secretResource, jenkinsHandler).
 		Watches(configMapResource, jenkinsHandler).
+		Watches(secretResource, handler.EnqueueRequestsFromMapFunc(r.seedJobCredentialJenkins)).
 		Watches(&source.Kind{Type: &v1alpha2.Jenkins{}}, &decorator).
+		Watches(&source.Kind{Type: &v1alpha2.Jenkins{}}, handler.EnqueueRequestsFromMapFunc(r.inheritingJenkins)).
+		WithOptions(controller.Options{RateLimiter: r.newRateLimiter()}).
 		Complete(r)
 }
 
+// newRateLimiter returns the workqueue rate limiter which delays failed reconciles of every Jenkins CR separately,
+// so a CR requeued over and over doesn't starve the others. The delays follow the current runtime settings.
+func (r *JenkinsReconciler) newRateLimiter() workqueue.RateLimiter {
+	return r.RuntimeConfig.NewWorkqueueRateLimiter()
+}
+
 func (r *JenkinsReconciler) newJenkinsReconcilier(jenkins *v1alpha2.Jenkins) configuration.Configuration {
 	config := configuration.Configuration{
 		Client:                       r.Client,
@@ -103,6 +126,8 @@ func (r *JenkinsReconciler) newJenkinsReconcilier(jenkins *v1alpha2.Jenkins) con
 		Config:                       &r.Config,
 		JenkinsAPIConnectionSettings: r.JenkinsAPIConnectionSettings,
 		KubernetesClusterDomain:      r.KubernetesClusterDomain,
+		JenkinsClientOptions:         []jenkinsclient.Option{metrics.WithJenkinsAPIRequestDuration(jenkins)},
+		Events:                       r.Events,
 	}
 	return config
 }
@@ -111,32 +136,56 @@ func (r *JenkinsReconciler) newJenkinsReconcilier(jenkins *v1alpha2.Jenkins) con
 // +kubebuilder:rbac:groups=jenkins.io,resources=jenkins/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=jenkins.io,resources=jenkins/finalizers,verbs=update
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=core,resources=services;configmaps;secrets,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=core,resources=services;configmaps;secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments;daemonsets;replicasets;statefulsets,verbs=*
-// +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update
-// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=core,resources=pods/portforward,verbs=create
 // +kubebuilder:rbac:groups=core,resources=pods/log,verbs=get;list;watch
-// +kubebuilder:rbac:groups=core,resources=pods;pods/exec,verbs=*
+// +kubebuilder:rbac:groups=core,resources=pods;pods/exec;pods/portforward,verbs=*
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;watch;list;create;patch
 // +kubebuilder:rbac:groups=apps;jenkins-operator,resources=deployments/finalizers,verbs=update
 // +kubebuilder:rbac:groups=jenkins.io,resources=*,verbs=*
-// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch
-// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=core,resources=limitranges,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=resourcequotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=image.openshift.io,resources=imagestreams,verbs=get;list;watch
 // +kubebuilder:rbac:groups=build.openshift.io,resources=builds;buildconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
 
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.7.0/pkg/reconcile
 func (r *JenkinsReconciler) Reconcile(_ context.Context, request ctrl.Request) (ctrl.Result, error) {
-	reconcileFailLimit := uint64(10)
-	logger := logx.WithValues("cr", request.Name)
+	reconcileFailLimit := r.RuntimeConfig.Get().ReconcileFailLimit
+	logger := logx.WithValues("cr", request.Name, "namespace", request.Namespace)
 	logger.V(log.VDebug).Info("Reconciling Jenkins")
 
+	start := time.Now()
 	result, jenkins, err := r.reconcile(request)
+	// the periodic requeue of ready Jenkins, e.g. to pull Configuration as Code from Git, isn't a pending reconcile
+	requeue := result.Requeue || (result.RequeueAfter > 0 && (jenkins == nil || !jenkins.Status.Ready))
+	if err == nil && !requeue {
+		r.state.deleteTimer(request.NamespacedName)
+	}
+	if jenkins != nil {
+		metrics.SetStatusMetrics(jenkins)
+		metrics.ObserveReconcileDuration(jenkins, time.Since(start))
+	} else if err == nil {
+		deleted := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Namespace: request.Namespace, Name: request.Name}}
+		metrics.DeleteStatusMetrics(deleted)
+		metrics.DeleteReconcileMetrics(deleted)
+		r.state.forget(request.NamespacedName)
+		configuration.ForgetAppliedResources(request.NamespacedName)
+	}
 	if err != nil && apierrors.IsConflict(err) {
 		return reconcile.Result{Requeue: true}, nil
 	} else if err != nil {
+		if jenkins != nil {
+			metrics.IncReconcileFailures(jenkins, reconcilePhase(jenkins))
+		}
 		lastErrors, found := reconcileErrors[request.Name]
 		if found {
 			if err.Error() == lastErrors.err.Error() {
@@ -152,8 +201,9 @@ func (r *JenkinsReconciler) Reconcile(_ context.Context, request ctrl.Request) (
 			}
 		}
 		reconcileErrors[request.Name] = lastErrors
+		r.setLastReconcileError(jenkins, err, lastErrors.counter)
 		if lastErrors.counter >= reconcileFailLimit {
-			if log.Debug {
+			if log.IsDebug() {
 				logger.V(log.VWarn).Info(fmt.Sprintf("Reconcile loop failed %d times with the same errors, giving up: %+v", reconcileFailLimit, err))
 			} else {
 				logger.V(log.VWarn).Info(fmt.Sprintf("Reconcile loop failed %d times with the same errors, giving up: %s", reconcileFailLimit, err))
@@ -168,10 +218,11 @@ func (r *JenkinsReconciler) Reconcile(_ context.Context, request ctrl.Request) (
 					[]string{fmt.Sprintf("Reconcile loop failed %d times with the same errors, giving up: %s", reconcileFailLimit, err)},
 				),
 			}
+			r.state.forget(request.NamespacedName)
 			return reconcile.Result{Requeue: false}, nil
 		}
 
-		if log.Debug {
+		if log.IsDebug() {
 			logger.V(log.VWarn).Info(fmt.Sprintf("Reconcile loop failed: %+v", err))
 		} else if err.Error() != fmt.Sprintf("Operation cannot be fulfilled on jenkins.jenkins.io \"%s\": the object has been modified; please apply your changes to the latest version and try again", request.Name) {
 			logger.V(log.VWarn).Info(fmt.Sprintf("Reconcile loop failed: %s", err))
@@ -180,7 +231,7 @@ func (r *JenkinsReconciler) Reconcile(_ context.Context, request ctrl.Request) (
 		if groovyErr, ok := err.(*jenkinsclient.GroovyScriptExecutionFailed); ok {
 			*r.NotificationEvents <- event.Event{
 				Jenkins: *jenkins,
-				Phase:   event.PhaseBase,
+				Phase:   groovyScriptPhase(groovyErr.ConfigurationType),
 				Level:   v1alpha2.NotificationLevelWarning,
 				Reason: reason.NewGroovyScriptExecutionFailed(
 					reason.OperatorSource,
@@ -188,18 +239,125 @@ func (r *JenkinsReconciler) Reconcile(_ context.Context, request ctrl.Request) (
 					[]string{fmt.Sprintf("%s Source '%s' Name '%s' groovy script execution failed, logs: %+v", groovyErr.ConfigurationType, groovyErr.Source, groovyErr.Name, groovyErr.Logs)}...,
 				),
 			}
+			r.state.forget(request.NamespacedName)
 			return reconcile.Result{Requeue: false}, nil
 		}
+		r.detectStalledReconcile(jenkins, true, err)
 		return reconcile.Result{Requeue: true}, nil
 	}
+	r.setLastReconcileError(jenkins, nil, 0)
+	r.detectStalledReconcile(jenkins, requeue, nil)
 	if result.Requeue && result.RequeueAfter == 0 {
 		result.RequeueAfter = time.Duration(rand.Intn(10)) * time.Millisecond
 	}
 	return result, nil
 }
 
+// groovyScriptPhase returns the configuration phase in which the groovy scripts of the configuration type are applied
+func groovyScriptPhase(configurationType string) event.Phase {
+	if strings.HasPrefix(configurationType, "user-") || configurationType == "seed-jobs" {
+		return event.PhaseUser
+	}
+	return event.PhaseBase
+}
+
+// setLastReconcileError records the reconcile loop error in status, the error is cleared when err is nil
+func (r *JenkinsReconciler) setLastReconcileError(jenkins *v1alpha2.Jenkins, err error, count uint64) {
+	if jenkins == nil || (err == nil && jenkins.Status.LastReconcileError == nil) {
+		return
+	}
+	logger := logx.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace)
+
+	if err == nil {
+		jenkins.Status.LastReconcileError = nil
+	} else {
+		jenkins.Status.LastReconcileError = &v1alpha2.ReconcileError{
+			Message: err.Error(),
+			Phase:   string(reconcilePhase(jenkins)),
+			Time:    metav1.Now(),
+			Count:   count,
+		}
+	}
+	if updateErr := r.Client.Status().Update(context.TODO(), jenkins); updateErr != nil {
+		logger.V(log.VWarn).Info(fmt.Sprintf("Failed to update last reconcile error in status: %s", updateErr))
+	}
+}
+
+// reconcilePhase returns the configuration phase which the reconcile loop of the CR is in
+func reconcilePhase(jenkins *v1alpha2.Jenkins) event.Phase {
+	if jenkins.Status.BaseConfigurationCompletedTime != nil {
+		return event.PhaseUser
+	}
+	return event.PhaseBase
+}
+
+// detectStalledReconcile tracks how long the CR has been requeueing and sets the Stalled condition when it exceeds
+// the stalled threshold, the condition is cleared once the reconcile loop completes.
+func (r *JenkinsReconciler) detectStalledReconcile(jenkins *v1alpha2.Jenkins, requeue bool, lastErr error) {
+	stalledThreshold := r.RuntimeConfig.Get().ReconcileStalledThreshold
+	if jenkins == nil {
+		return
+	}
+	logger := logx.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace)
+	key := types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name}
+	if stalledThreshold == 0 {
+		// the detection could have been disabled at runtime
+		r.state.deleteRequeueStartTime(key)
+		return
+	}
+	stalled := meta.IsStatusConditionTrue(jenkins.Status.Conditions, v1alpha2.ConditionStalled)
+
+	if !requeue {
+		r.state.deleteRequeueStartTime(key)
+		if stalled {
+			configuration.SetCondition(jenkins, v1alpha2.ConditionStalled, metav1.ConditionFalse, configuration.ConditionReasonCompleted,
+				"Reconcile loop has been completed")
+			if err := r.Client.Status().Update(context.TODO(), jenkins); err != nil {
+				logger.V(log.VWarn).Info(fmt.Sprintf("Failed to clear stalled condition: %s", err))
+				return
+			}
+			logger.Info("Reconcile loop is no longer stalled")
+		}
+		return
+	}
+
+	requeueStartTime, found := r.state.getRequeueStartTime(key)
+	if !found {
+		return
+	}
+	requeueDuration := time.Since(requeueStartTime)
+	if requeueDuration < stalledThreshold || stalled {
+		return
+	}
+
+	phase := event.PhaseBase
+	message := fmt.Sprintf("Reconcile loop has been requeueing for %s and base configuration phase is not complete", requeueDuration.Round(time.Second))
+	if jenkins.Status.BaseConfigurationCompletedTime != nil {
+		phase = event.PhaseUser
+		message = fmt.Sprintf("Reconcile loop has been requeueing for %s and user configuration phase is not complete", requeueDuration.Round(time.Second))
+	}
+	verbose := message
+	if lastErr != nil {
+		verbose = fmt.Sprintf("%s, last error: %s", message, lastErr)
+	}
+
+	configuration.SetCondition(jenkins, v1alpha2.ConditionStalled, metav1.ConditionTrue, configuration.ConditionReasonReconcileStalled, verbose)
+	if err := r.Client.Status().Update(context.TODO(), jenkins); err != nil {
+		logger.V(log.VWarn).Info(fmt.Sprintf("Failed to set stalled condition: %s", err))
+		return
+	}
+
+	logger.V(log.VWarn).Info(verbose)
+	*r.NotificationEvents <- event.Event{
+		Jenkins: *jenkins,
+		Phase:   phase,
+		Level:   v1alpha2.NotificationLevelWarning,
+		Reason:  reason.NewReconcileLoopStalled(reason.OperatorSource, []string{message}, verbose),
+	}
+}
+
 func (r *JenkinsReconciler) reconcile(request reconcile.Request) (reconcile.Result, *v1alpha2.Jenkins, error) {
-	logger := logx.WithValues("cr", request.Name)
+	logger := logx.WithValues("cr", request.Name, "namespace", request.Namespace)
 	// Fetch the Jenkins instance
 	jenkins := &v1alpha2.Jenkins{}
 	var err error
@@ -215,6 +373,24 @@ func (r *JenkinsReconciler) reconcile(request reconcile.Request) (reconcile.Resu
 		return reconcile.Result{}, nil, errors.WithStack(err)
 	}
 	var requeue bool
+	requeue, err = r.cloneJenkins(jenkins)
+	if err != nil {
+		return reconcile.Result{}, jenkins, err
+	}
+	if requeue {
+		return reconcile.Result{Requeue: true}, jenkins, nil
+	}
+	requeue, err = r.rollbackCrashLoop(jenkins)
+	if err != nil {
+		return reconcile.Result{}, jenkins, err
+	}
+	if requeue {
+		return reconcile.Result{Requeue: true}, jenkins, nil
+	}
+	// the Jenkins CR isn't updated after the inherited spec has been merged into it
+	if err = r.inheritSpec(jenkins); err != nil {
+		return reconcile.Result{}, jenkins, err
+	}
 	requeue, err = r.setDefaults(jenkins)
 	if err != nil {
 		return reconcile.Result{}, jenkins, err
@@ -223,7 +399,12 @@ func (r *JenkinsReconciler) reconcile(request reconcile.Request) (reconcile.Resu
 		return reconcile.Result{Requeue: true}, jenkins, nil
 	}
 
+	timer := r.state.getTimer(request.NamespacedName)
+	timer.StartLoop()
+
 	config := r.newJenkinsReconcilier(jenkins)
+	config.Timer = timer
+	config.PendingRestart = configuration.NewPendingRestart()
 	// Reconcile base configuration
 	baseConfiguration := base.New(config, r.JenkinsAPIConnectionSettings)
 
@@ -240,6 +421,7 @@ func (r *JenkinsReconciler) reconcile(request reconcile.Request) (reconcile.Resu
 			Level:   v1alpha2.NotificationLevelWarning,
 			Reason:  reason.NewBaseConfigurationFailed(reason.HumanSource, []string{message}, append([]string{message}, baseMessages...)...),
 		}
+		metrics.IncReconcileFailures(jenkins, event.PhaseBase)
 		logger.V(log.VWarn).Info(message)
 		for _, msg := range baseMessages {
 			logger.V(log.VWarn).Info(msg)
@@ -260,14 +442,28 @@ func (r *JenkinsReconciler) reconcile(request reconcile.Request) (reconcile.Resu
 		return reconcile.Result{Requeue: false}, jenkins, nil
 	}
 
-	if jenkins.Status.BaseConfigurationCompletedTime == nil {
+	baseJustCompleted := jenkins.Status.BaseConfigurationCompletedTime == nil
+	if baseJustCompleted {
 		now := metav1.Now()
 		jenkins.Status.BaseConfigurationCompletedTime = &now
+	}
+	versionChanged, err := setJenkinsVersion(jenkins, jenkinsClient)
+	if err != nil {
+		return reconcile.Result{}, jenkins, err
+	}
+	installedPlugins, err := jenkinsClient.GetPlugins(fetchAllPlugins)
+	if err != nil {
+		return reconcile.Result{}, jenkins, errors.WithStack(err)
+	}
+	featuresChanged := setFeatures(jenkins, resources.IsRouteAPIAvailable(&r.ClientSet), installedPlugins)
+	if configuration.SetCondition(jenkins, v1alpha2.ConditionBaseConfigurationCompleted, metav1.ConditionTrue, configuration.ConditionReasonCompleted,
+		"Base configuration phase is complete") || baseJustCompleted || versionChanged || featuresChanged {
 		err = r.Client.Status().Update(context.TODO(), jenkins)
 		if err != nil {
 			return reconcile.Result{}, jenkins, errors.WithStack(err)
 		}
-
+	}
+	if baseJustCompleted {
 		message := fmt.Sprintf("Base configuration phase is complete, took %s",
 			jenkins.Status.BaseConfigurationCompletedTime.Sub(jenkins.Status.ProvisionStartTime.Time))
 		*r.NotificationEvents <- event.Event{
@@ -279,6 +475,17 @@ func (r *JenkinsReconciler) reconcile(request reconcile.Request) (reconcile.Resu
 		logger.Info(message)
 	}
 
+	if jenkins.Spec.SkipUserConfiguration {
+		logger.V(log.VDebug).Info("User configuration phase is suspended by spec.skipUserConfiguration")
+		return reconcile.Result{}, jenkins, nil
+	}
+
+	if setSeedJobsSummary(jenkins) {
+		if err = r.Client.Status().Update(context.TODO(), jenkins); err != nil {
+			return reconcile.Result{}, jenkins, errors.WithStack(err)
+		}
+	}
+
 	// Reconcile casc, seedjobs and backups
 	userConfiguration := user.New(config, jenkinsClient)
 
@@ -296,6 +503,7 @@ func (r *JenkinsReconciler) reconcile(request reconcile.Request) (reconcile.Resu
 			Reason:  reason.NewUserConfigurationFailed(reason.HumanSource, []string{message}, append([]string{message}, messages...)...),
 		}
 
+		metrics.IncReconcileFailures(jenkins, event.PhaseUser)
 		logger.V(log.VWarn).Info(message)
 		for _, msg := range messages {
 			logger.V(log.VWarn).Info(msg)
@@ -311,6 +519,8 @@ func (r *JenkinsReconciler) reconcile(request reconcile.Request) (reconcile.Resu
 	if result.Requeue {
 		return result, jenkins, nil
 	}
+	// the Git repository with Configuration as Code is pulled periodically
+	gitRepositorySyncAfter := result.RequeueAfter
 
 	// Reconcile seedjobs, backups
 	result, err = userConfiguration.ReconcileOthers()
@@ -321,13 +531,19 @@ func (r *JenkinsReconciler) reconcile(request reconcile.Request) (reconcile.Resu
 		return result, jenkins, nil
 	}
 
-	if jenkins.Status.UserConfigurationCompletedTime == nil {
+	userJustCompleted := jenkins.Status.UserConfigurationCompletedTime == nil
+	if userJustCompleted {
 		now := metav1.Now()
 		jenkins.Status.UserConfigurationCompletedTime = &now
+	}
+	if configuration.SetCondition(jenkins, v1alpha2.ConditionUserConfigurationCompleted, metav1.ConditionTrue, configuration.ConditionReasonCompleted,
+		"User configuration phase is complete") || userJustCompleted {
 		err = r.Client.Status().Update(context.TODO(), jenkins)
 		if err != nil {
 			return reconcile.Result{}, jenkins, errors.WithStack(err)
 		}
+	}
+	if userJustCompleted {
 		message := fmt.Sprintf("User configuration phase is complete, took %s",
 			jenkins.Status.UserConfigurationCompletedTime.Sub(jenkins.Status.ProvisionStartTime.Time))
 		*r.NotificationEvents <- event.Event{
@@ -338,12 +554,36 @@ func (r *JenkinsReconciler) reconcile(request reconcile.Request) (reconcile.Resu
 		}
 		logger.Info(message)
 	}
-	return reconcile.Result{}, jenkins, nil
+
+	result, err = r.checkReadiness(jenkins, jenkinsClient)
+	if err != nil {
+		return reconcile.Result{}, jenkins, err
+	}
+	if result.RequeueAfter > 0 {
+		return result, jenkins, nil
+	}
+	if err = r.recordLastKnownGoodSpec(jenkins); err != nil {
+		return reconcile.Result{}, jenkins, err
+	}
+
+	if timings := timer.Timings(); timer.Significant() && configuration.ReconcileTimingsChanged(jenkins.Status.ReconcileTimings, timings) {
+		jenkins.Status.ReconcileTimings = &timings
+		err = r.Client.Status().Update(context.TODO(), jenkins)
+		if err != nil {
+			return reconcile.Result{}, jenkins, errors.WithStack(err)
+		}
+	}
+	return reconcile.Result{RequeueAfter: gitRepositorySyncAfter}, jenkins, nil
 }
 
 func (r *JenkinsReconciler) setDefaults(jenkins *v1alpha2.Jenkins) (requeue bool, err error) {
 	changed := false
-	logger := logx.WithValues("cr", jenkins.Name)
+	logger := logx.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace)
+
+	limitRanges := &corev1.LimitRangeList{}
+	if err = r.Client.List(context.TODO(), limitRanges, client.InNamespace(jenkins.Namespace)); err != nil {
+		return false, errors.WithStack(err)
+	}
 
 	var jenkinsContainer v1alpha2.Container
 	if len(jenkins.Spec.Master.Containers) == 0 {
@@ -399,13 +639,13 @@ func (r *JenkinsReconciler) setDefaults(jenkins *v1alpha2.Jenkins) (requeue bool
 	if isResourceRequirementsNotSet(jenkinsContainer.Resources) {
 		logger.Info("Setting default Jenkins master container resource requirements")
 		changed = true
-		jenkinsContainer.Resources = resources.NewResourceRequirements("1", "500Mi", "1500m", "3Gi")
+		jenkinsContainer.Resources = resources.FitResourceRequirementsToLimitRanges(resources.NewResourceRequirements("1", "500Mi", "1500m", "3Gi"), limitRanges.Items)
 	}
 	if reflect.DeepEqual(jenkins.Spec.Service, v1alpha2.Service{}) {
 		logger.Info("Setting default Jenkins master service")
 		changed = true
 		var serviceType = corev1.ServiceTypeClusterIP
-		if r.JenkinsAPIConnectionSettings.UseNodePort {
+		if configuration.GetJenkinsAPIConnectionSettings(r.JenkinsAPIConnectionSettings, jenkins).UseNodePort {
 			serviceType = corev1.ServiceTypeNodePort
 		}
 		jenkins.Spec.Service = v1alpha2.Service{
@@ -423,12 +663,12 @@ func (r *JenkinsReconciler) setDefaults(jenkins *v1alpha2.Jenkins) (requeue bool
 	}
 	if len(jenkins.Spec.Master.Containers) > 1 {
 		for i, container := range jenkins.Spec.Master.Containers[1:] {
-			if r.setDefaultsForContainer(jenkins, container.Name, i+1) {
+			if r.setDefaultsForContainer(jenkins, container.Name, i+1, limitRanges.Items) {
 				changed = true
 			}
 		}
 	}
-	if len(jenkins.Spec.Backup.ContainerName) > 0 && jenkins.Spec.Backup.Interval == 0 {
+	if backuprestore.IsBackupConfigured(jenkins) && jenkins.Spec.Backup.Interval == 0 {
 		logger.Info("Setting default backup interval")
 		changed = true
 		jenkins.Spec.Backup.Interval = 30
@@ -455,10 +695,11 @@ func (r *JenkinsReconciler) setDefaults(jenkins *v1alpha2.Jenkins) (requeue bool
 		jenkins.Spec.JenkinsAPISettings.AuthorizationStrategy = v1alpha2.CreateUserAuthorizationStrategy
 	}
 
-	if changed {
+	// the defaults of the spec merged with the inherited one are kept in memory only
+	if changed && len(jenkins.Spec.InheritFrom) == 0 {
 		return changed, errors.WithStack(r.Client.Update(context.TODO(), jenkins))
 	}
-	return changed, nil
+	return false, nil
 }
 
 func isJavaOpsVariableNotSet(container v1alpha2.Container) bool {
@@ -470,9 +711,9 @@ func isJavaOpsVariableNotSet(container v1alpha2.Container) bool {
 	return true
 }
 
-func (r *JenkinsReconciler) setDefaultsForContainer(jenkins *v1alpha2.Jenkins, containerName string, containerIndex int) bool {
+func (r *JenkinsReconciler) setDefaultsForContainer(jenkins *v1alpha2.Jenkins, containerName string, containerIndex int, limitRanges []corev1.LimitRange) bool {
 	changed := false
-	logger := logx.WithValues("cr", jenkins.Name, "container", containerName)
+	logger := logx.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace, "container", containerName)
 
 	if len(jenkins.Spec.Master.Containers[containerIndex].ImagePullPolicy) == 0 {
 		logger.Info(fmt.Sprintf("Setting default container image pull policy: %s", corev1.PullAlways))
@@ -482,7 +723,7 @@ func (r *JenkinsReconciler) setDefaultsForContainer(jenkins *v1alpha2.Jenkins, c
 	if isResourceRequirementsNotSet(jenkins.Spec.Master.Containers[containerIndex].Resources) {
 		logger.Info("Setting default container resource requirements")
 		changed = true
-		jenkins.Spec.Master.Containers[containerIndex].Resources = resources.NewResourceRequirements("50m", "50Mi", "100m", "100Mi")
+		jenkins.Spec.Master.Containers[containerIndex].Resources = resources.FitResourceRequirementsToLimitRanges(resources.NewResourceRequirements("50m", "50Mi", "100m", "100Mi"), limitRanges)
 	}
 	return changed
 }
@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/runtimeconfig"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestJenkinsReconciler_setLastReconcileError(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	ctx := context.TODO()
+	key := types.NamespacedName{Namespace: "default", Name: "jenkins"}
+
+	t.Run("records and clears error", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}
+		reconciler := &JenkinsReconciler{Client: fake.NewClientBuilder().WithObjects(jenkins).Build()}
+
+		reconciler.setLastReconcileError(jenkins, errors.New("plugins installation failed"), 2)
+
+		actual := &v1alpha2.Jenkins{}
+		require.NoError(t, reconciler.Client.Get(ctx, key, actual))
+		require.NotNil(t, actual.Status.LastReconcileError)
+		assert.Equal(t, "plugins installation failed", actual.Status.LastReconcileError.Message)
+		assert.Equal(t, "base", actual.Status.LastReconcileError.Phase)
+		assert.Equal(t, uint64(2), actual.Status.LastReconcileError.Count)
+		assert.False(t, actual.Status.LastReconcileError.Time.IsZero())
+
+		reconciler.setLastReconcileError(jenkins, nil, 0)
+
+		actual = &v1alpha2.Jenkins{}
+		require.NoError(t, reconciler.Client.Get(ctx, key, actual))
+		assert.Nil(t, actual.Status.LastReconcileError)
+	})
+	t.Run("records user configuration phase", func(t *testing.T) {
+		now := metav1.Now()
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Status:     v1alpha2.JenkinsStatus{BaseConfigurationCompletedTime: &now},
+		}
+		reconciler := &JenkinsReconciler{Client: fake.NewClientBuilder().WithObjects(jenkins).Build()}
+
+		reconciler.setLastReconcileError(jenkins, errors.New("seed job failed"), 1)
+
+		actual := &v1alpha2.Jenkins{}
+		require.NoError(t, reconciler.Client.Get(ctx, key, actual))
+		require.NotNil(t, actual.Status.LastReconcileError)
+		assert.Equal(t, "user", actual.Status.LastReconcileError.Phase)
+	})
+}
+
+func TestGroovyScriptPhase(t *testing.T) {
+	assert.Equal(t, event.PhaseBase, groovyScriptPhase("base-groovy"))
+	assert.Equal(t, event.PhaseBase, groovyScriptPhase("base-user-groovy"))
+	assert.Equal(t, event.PhaseUser, groovyScriptPhase("user-groovy"))
+	assert.Equal(t, event.PhaseUser, groovyScriptPhase("user-casc"))
+	assert.Equal(t, event.PhaseUser, groovyScriptPhase("seed-jobs"))
+}
+
+func TestJenkinsReconciler_detectStalledReconcile(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	ctx := context.TODO()
+	key := types.NamespacedName{Namespace: "default", Name: "jenkins"}
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}
+	notificationEvents := make(chan event.Event, 10)
+	reconciler := &JenkinsReconciler{
+		Client:             fake.NewClientBuilder().WithObjects(jenkins).Build(),
+		NotificationEvents: &notificationEvents,
+		RuntimeConfig:      runtimeconfig.New(runtimeconfig.Settings{ReconcileStalledThreshold: time.Minute}),
+	}
+	getStalledCondition := func(t *testing.T) *metav1.Condition {
+		actual := &v1alpha2.Jenkins{}
+		require.NoError(t, reconciler.Client.Get(ctx, key, actual))
+		return meta.FindStatusCondition(actual.Status.Conditions, v1alpha2.ConditionStalled)
+	}
+
+	t.Run("requeueing within threshold", func(t *testing.T) {
+		reconciler.detectStalledReconcile(jenkins, true, nil)
+		reconciler.detectStalledReconcile(jenkins, true, nil)
+
+		assert.Nil(t, getStalledCondition(t))
+		assert.Empty(t, notificationEvents)
+	})
+	t.Run("requeueing over threshold", func(t *testing.T) {
+		reconciler.state.requeueStartTimes[key] = time.Now().Add(-time.Hour)
+
+		reconciler.detectStalledReconcile(jenkins, true, errors.New("Jenkins API is not available"))
+		reconciler.detectStalledReconcile(jenkins, true, errors.New("Jenkins API is not available"))
+
+		condition := getStalledCondition(t)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, configuration.ConditionReasonReconcileStalled, condition.Reason)
+		assert.Contains(t, condition.Message, "base configuration phase is not complete, last error: Jenkins API is not available")
+		assert.Len(t, notificationEvents, 1)
+	})
+	t.Run("reconcile loop completed", func(t *testing.T) {
+		reconciler.detectStalledReconcile(jenkins, false, nil)
+
+		condition := getStalledCondition(t)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.NotContains(t, reconciler.state.requeueStartTimes, key)
+	})
+	t.Run("detection disabled", func(t *testing.T) {
+		reconciler.state.requeueStartTimes[key] = time.Now()
+		_, err := reconciler.RuntimeConfig.Apply(map[string]string{runtimeconfig.ReconcileStalledThresholdKey: "0s"})
+		require.NoError(t, err)
+
+		reconciler.detectStalledReconcile(jenkins, true, nil)
+
+		assert.NotContains(t, reconciler.state.requeueStartTimes, key)
+	})
+}
+
+func TestJenkinsReconciler_Reconcile_DeletedJenkins(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	key := types.NamespacedName{Namespace: "default", Name: "jenkins"}
+	reconciler := &JenkinsReconciler{
+		Client:        fake.NewClientBuilder().Build(),
+		RuntimeConfig: runtimeconfig.New(runtimeconfig.Settings{ReconcileFailLimit: 10, ReconcileStalledThreshold: time.Minute}),
+	}
+	reconciler.state.getTimer(key)
+	reconciler.state.getRequeueStartTime(key)
+
+	result, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+	assert.NotContains(t, reconciler.state.timers, key)
+	assert.NotContains(t, reconciler.state.requeueStartTimes, key)
+}
+
+func TestReconcileState(t *testing.T) {
+	state := &reconcileState{}
+	key := types.NamespacedName{Namespace: "default", Name: "jenkins"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			other := types.NamespacedName{Namespace: "default", Name: fmt.Sprintf("jenkins-%d", i)}
+			state.getTimer(other)
+			state.getRequeueStartTime(other)
+			state.deleteTimer(other)
+			state.deleteRequeueStartTime(other)
+		}(i)
+	}
+	wg.Wait()
+
+	timer := state.getTimer(key)
+	assert.Same(t, timer, state.getTimer(key))
+	state.deleteTimer(key)
+	assert.NotSame(t, timer, state.getTimer(key))
+
+	requeueStartTime, found := state.getRequeueStartTime(key)
+	assert.False(t, found)
+	actual, found := state.getRequeueStartTime(key)
+	assert.True(t, found)
+	assert.Equal(t, requeueStartTime, actual)
+	state.deleteRequeueStartTime(key)
+	_, found = state.getRequeueStartTime(key)
+	assert.False(t, found)
+
+	state.getTimer(key)
+	state.getRequeueStartTime(key)
+	state.forget(key)
+	assert.Empty(t, state.timers)
+	assert.Empty(t, state.requeueStartTimes)
+}
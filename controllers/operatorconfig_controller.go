@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/runtimeconfig"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// OperatorConfigReconciler applies operator settings from a ConfigMap at runtime
+type OperatorConfigReconciler struct {
+	Client        client.Client
+	ConfigMap     types.NamespacedName
+	RuntimeConfig *runtimeconfig.Config
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	isOperatorConfigMap := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetName() == r.ConfigMap.Name && object.GetNamespace() == r.ConfigMap.Namespace
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("operator-config").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(isOperatorConfigMap)).
+		Complete(r)
+}
+
+// Reconcile applies the operator ConfigMap data, when the ConfigMap is removed the defaults are restored.
+func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	logger := logx.WithValues("configmap", request.NamespacedName.String())
+
+	var data map[string]string
+	configMap := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, request.NamespacedName, configMap)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, errors.WithStack(err)
+	} else if err == nil {
+		data = configMap.Data
+	}
+
+	settings, err := r.RuntimeConfig.Apply(data)
+	if err != nil {
+		logger.V(log.VWarn).Info(fmt.Sprintf("Invalid operator configuration, keeping previous settings: %s", err))
+		return ctrl.Result{}, nil
+	}
+
+	log.SetDebug(settings.Debug)
+	logger.Info(fmt.Sprintf("Operator configuration applied: %+v", settings))
+	return ctrl.Result{}, nil
+}
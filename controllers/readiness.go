@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const readinessCheckRetryInterval = time.Minute
+
+const readyMessage = "Jenkins is configured and ready"
+
+// checkReadiness executes the readiness check script and marks the Jenkins CR as ready when it succeeds,
+// the failed script is retried until it succeeds
+func (r *JenkinsReconciler) checkReadiness(jenkins *v1alpha2.Jenkins, jenkinsClient jenkinsclient.Jenkins) (reconcile.Result, error) {
+	if jenkins.Status.Ready {
+		if setReadyCondition(jenkins, metav1.ConditionTrue, configuration.ConditionReasonReadinessCheckSucceeded, readyMessage) {
+			return reconcile.Result{}, errors.WithStack(r.Client.Status().Update(context.TODO(), jenkins))
+		}
+		return reconcile.Result{}, nil
+	}
+	logger := logx.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace)
+
+	if jenkins.Spec.ReadinessCheck != nil {
+		script, err := r.getReadinessCheckScript(jenkins)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		logs, err := jenkinsClient.ExecuteScript(script)
+		if _, failed := err.(*jenkinsclient.GroovyScriptExecutionFailed); failed {
+			if jenkins.Status.ReadinessCheckMessage != logs {
+				jenkins.Status.ReadinessCheckMessage = logs
+				setReadyCondition(jenkins, metav1.ConditionFalse, configuration.ConditionReasonReadinessCheckFailed, "Readiness check script failed")
+				if err := r.Client.Status().Update(context.TODO(), jenkins); err != nil {
+					return reconcile.Result{}, errors.WithStack(err)
+				}
+				message := "Readiness check script failed, Jenkins is not ready"
+				*r.NotificationEvents <- event.Event{
+					Jenkins: *jenkins,
+					Phase:   event.PhaseUser,
+					Level:   v1alpha2.NotificationLevelWarning,
+					Reason:  reason.NewGroovyScriptExecutionFailed(reason.OperatorSource, []string{message}, fmt.Sprintf("%s, logs: %s", message, logs)),
+				}
+				logger.V(log.VWarn).Info(fmt.Sprintf("%s, logs: %s", message, logs))
+			}
+			return reconcile.Result{RequeueAfter: readinessCheckRetryInterval}, nil
+		} else if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	jenkins.Status.Ready = true
+	jenkins.Status.ReadinessCheckMessage = ""
+	setReadyCondition(jenkins, metav1.ConditionTrue, configuration.ConditionReasonReadinessCheckSucceeded, readyMessage)
+	if err := r.Client.Status().Update(context.TODO(), jenkins); err != nil {
+		return reconcile.Result{}, errors.WithStack(err)
+	}
+	logger.Info("Jenkins is ready")
+	return reconcile.Result{}, nil
+}
+
+// setReadyCondition sets the Ready condition in status, it returns true if the condition has changed
+func setReadyCondition(jenkins *v1alpha2.Jenkins, status metav1.ConditionStatus, conditionReason, message string) bool {
+	return configuration.SetCondition(jenkins, v1alpha2.ConditionReady, status, conditionReason, message)
+}
+
+// getReadinessCheckScript returns the readiness check script from the ConfigMap referenced by spec.readinessCheck
+func (r *JenkinsReconciler) getReadinessCheckScript(jenkins *v1alpha2.Jenkins) (string, error) {
+	readinessCheck := jenkins.Spec.ReadinessCheck
+	configMap := &corev1.ConfigMap{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: readinessCheck.ConfigMapRef.Name}, configMap)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if len(readinessCheck.Key) > 0 {
+		script, found := configMap.Data[readinessCheck.Key]
+		if !found {
+			return "", errors.Errorf("ConfigMap '%s' doesn't contain '%s' key", configMap.Name, readinessCheck.Key)
+		}
+		return script, nil
+	}
+	if len(configMap.Data) != 1 {
+		return "", errors.Errorf("ConfigMap '%s' must contain exactly one key when spec.readinessCheck.key is not set", configMap.Name)
+	}
+	for _, script := range configMap.Data {
+		return script, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileState keeps the progress of the reconcile loops of Jenkins CRs between reconciles, it's shared by
+// the reconcile workers
+type reconcileState struct {
+	mutex sync.Mutex
+	// requeueStartTimes are the times since the Jenkins CRs have been requeueing
+	requeueStartTimes map[types.NamespacedName]time.Time
+	// timers measure the phases of the pending reconcile loops
+	timers map[types.NamespacedName]*configuration.ReconcileTimer
+}
+
+// getTimer returns the timer of the pending reconcile loop of the Jenkins CR, a new timer is started when there
+// isn't one
+func (s *reconcileState) getTimer(key types.NamespacedName) *configuration.ReconcileTimer {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.timers == nil {
+		s.timers = map[types.NamespacedName]*configuration.ReconcileTimer{}
+	}
+	timer, found := s.timers[key]
+	if !found {
+		timer = configuration.NewReconcileTimer()
+		s.timers[key] = timer
+	}
+	return timer
+}
+
+// deleteTimer forgets the timer when the reconcile loop of the Jenkins CR is done
+func (s *reconcileState) deleteTimer(key types.NamespacedName) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.timers, key)
+}
+
+// getRequeueStartTime returns the time since the Jenkins CR has been requeueing, the time is recorded on the first
+// requeue and found is false then
+func (s *reconcileState) getRequeueStartTime(key types.NamespacedName) (requeueStartTime time.Time, found bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.requeueStartTimes == nil {
+		s.requeueStartTimes = map[types.NamespacedName]time.Time{}
+	}
+	requeueStartTime, found = s.requeueStartTimes[key]
+	if !found {
+		requeueStartTime = time.Now()
+		s.requeueStartTimes[key] = requeueStartTime
+	}
+	return requeueStartTime, found
+}
+
+// deleteRequeueStartTime forgets the requeue start time when the Jenkins CR is no longer requeueing
+func (s *reconcileState) deleteRequeueStartTime(key types.NamespacedName) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.requeueStartTimes, key)
+}
+
+// forget drops the whole state of the Jenkins CR when its reconcile loop has given up or the CR has been deleted
+func (s *reconcileState) forget(key types.NamespacedName) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.timers, key)
+	delete(s.requeueStartTimes, key)
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// crashLoopBackOffReason is the waiting reason of a container restarted by kubelet after repeated failures
+const crashLoopBackOffReason = "CrashLoopBackOff"
+
+// recordLastKnownGoodSpec takes a snapshot of spec once Jenkins is ready, it's taken once per Jenkins CR generation
+func (r *JenkinsReconciler) recordLastKnownGoodSpec(jenkins *v1alpha2.Jenkins) error {
+	if !jenkins.Status.Ready || (jenkins.Status.LastKnownGoodSpec != nil && jenkins.Status.LastKnownGoodGeneration == jenkins.Generation) {
+		return nil
+	}
+	spec, err := json.Marshal(jenkins.Spec)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	jenkins.Status.LastKnownGoodSpec = &runtime.RawExtension{Raw: spec}
+	jenkins.Status.LastKnownGoodGeneration = jenkins.Generation
+	return errors.WithStack(r.Client.Status().Update(context.TODO(), jenkins))
+}
+
+// rollbackCrashLoop rolls the spec back to status.lastKnownGoodSpec when Jenkins master container is in crash loop and
+// the spec has changed since the snapshot was taken, the CR is marked as degraded until the spec is changed again
+func (r *JenkinsReconciler) rollbackCrashLoop(jenkins *v1alpha2.Jenkins) (requeue bool, err error) {
+	status := &jenkins.Status
+	// the spec inherited from another Jenkins CR is merged in memory and can't be rolled back by updating the CR
+	if len(jenkins.Spec.InheritFrom) > 0 || status.LastKnownGoodSpec == nil || status.LastKnownGoodGeneration == jenkins.Generation {
+		return false, nil
+	}
+	logger := logx.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace)
+
+	if status.Degraded {
+		status.Degraded = false
+		status.DegradedReason = ""
+		if err = r.Client.Status().Update(context.TODO(), jenkins); err != nil {
+			return false, errors.WithStack(err)
+		}
+		logger.Info("Spec has been changed after the rollback, Jenkins CR is no longer degraded")
+	}
+
+	pod := &corev1.Pod{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: resources.GetJenkinsMasterPodName(jenkins)}, pod)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.WithStack(err)
+	}
+	var waiting *corev1.ContainerStateWaiting
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == resources.JenkinsMasterContainerName {
+			waiting = containerStatus.State.Waiting
+		}
+	}
+	if waiting == nil || waiting.Reason != crashLoopBackOffReason {
+		return false, nil
+	}
+
+	spec := v1alpha2.JenkinsSpec{}
+	if err = json.Unmarshal(status.LastKnownGoodSpec.Raw, &spec); err != nil {
+		return false, errors.Wrap(err, "failed to decode status.lastKnownGoodSpec")
+	}
+	failedGeneration, goodGeneration := jenkins.Generation, status.LastKnownGoodGeneration
+	jenkins.Spec = spec
+	if err = r.Client.Update(context.TODO(), jenkins); err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	message := fmt.Sprintf("Jenkins master pod is in crash loop after spec change, spec has been rolled back to generation %d", goodGeneration)
+	verbose := fmt.Sprintf("Jenkins master container is in %s with spec generation %d, spec has been rolled back to generation %d: %s",
+		crashLoopBackOffReason, failedGeneration, goodGeneration, waiting.Message)
+	jenkins.Status.Degraded = true
+	jenkins.Status.DegradedReason = verbose
+	// the rolled back spec is the known good one
+	jenkins.Status.LastKnownGoodGeneration = jenkins.Generation
+	if err = r.Client.Status().Update(context.TODO(), jenkins); err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	logger.V(log.VWarn).Info(verbose)
+	*r.NotificationEvents <- event.Event{
+		Jenkins: *jenkins,
+		Phase:   event.PhaseBase,
+		Level:   v1alpha2.NotificationLevelWarning,
+		Reason:  reason.NewCrashLoopRollback(reason.OperatorSource, []string{message}, verbose),
+	}
+	return true, nil
+}
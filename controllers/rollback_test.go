@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestJenkinsReconciler_rollbackCrashLoop(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	ctx := context.TODO()
+	goodSpec := v1alpha2.JenkinsSpec{
+		Master: v1alpha2.JenkinsMaster{Containers: []v1alpha2.Container{{Name: resources.JenkinsMasterContainerName, Image: "jenkins/jenkins:2.289"}}},
+	}
+	rawGoodSpec, err := json.Marshal(goodSpec)
+	require.NoError(t, err)
+	newJenkins := func() *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default", Generation: 3},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{Containers: []v1alpha2.Container{{Name: resources.JenkinsMasterContainerName, Image: "jenkins/jenkins:broken"}}},
+			},
+			Status: v1alpha2.JenkinsStatus{
+				LastKnownGoodSpec:       &runtime.RawExtension{Raw: rawGoodSpec},
+				LastKnownGoodGeneration: 2,
+			},
+		}
+	}
+	newPod := func(jenkins *v1alpha2.Jenkins, waitingReason string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: resources.GetJenkinsMasterPodName(jenkins), Namespace: "default"},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  resources.JenkinsMasterContainerName,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: waitingReason, Message: "back-off 40s restarting failed container"}},
+			}}},
+		}
+	}
+	newReconciler := func(jenkins *v1alpha2.Jenkins, pod *corev1.Pod) (*JenkinsReconciler, chan event.Event) {
+		notifications := make(chan event.Event, 1)
+		return &JenkinsReconciler{
+			Client:             fake.NewClientBuilder().WithObjects(jenkins, pod).Build(),
+			NotificationEvents: &notifications,
+		}, notifications
+	}
+
+	t.Run("rollback", func(t *testing.T) {
+		jenkins := newJenkins()
+		reconciler, notifications := newReconciler(jenkins, newPod(jenkins, crashLoopBackOffReason))
+
+		requeue, err := reconciler.rollbackCrashLoop(jenkins)
+
+		require.NoError(t, err)
+		assert.True(t, requeue)
+		updated := &v1alpha2.Jenkins{}
+		require.NoError(t, reconciler.Client.Get(ctx, types.NamespacedName{Namespace: "default", Name: "jenkins"}, updated))
+		assert.Equal(t, "jenkins/jenkins:2.289", updated.Spec.Master.Containers[0].Image)
+		assert.True(t, updated.Status.Degraded)
+		assert.Equal(t, "Jenkins master container is in CrashLoopBackOff with spec generation 3, spec has been rolled back to generation 2: back-off 40s restarting failed container", updated.Status.DegradedReason)
+		assert.Equal(t, updated.Generation, updated.Status.LastKnownGoodGeneration)
+		notification := <-notifications
+		assert.Equal(t, v1alpha2.NotificationLevelWarning, notification.Level)
+		assert.Equal(t, []string{"Jenkins master pod is in crash loop after spec change, spec has been rolled back to generation 2"}, notification.Reason.Short())
+	})
+	t.Run("pod is not in crash loop", func(t *testing.T) {
+		jenkins := newJenkins()
+		reconciler, _ := newReconciler(jenkins, newPod(jenkins, "ContainerCreating"))
+
+		requeue, err := reconciler.rollbackCrashLoop(jenkins)
+
+		require.NoError(t, err)
+		assert.False(t, requeue)
+		assert.Equal(t, "jenkins/jenkins:broken", jenkins.Spec.Master.Containers[0].Image)
+	})
+	t.Run("spec not changed since snapshot", func(t *testing.T) {
+		jenkins := newJenkins()
+		jenkins.Status.LastKnownGoodGeneration = jenkins.Generation
+		reconciler, _ := newReconciler(jenkins, newPod(jenkins, crashLoopBackOffReason))
+
+		requeue, err := reconciler.rollbackCrashLoop(jenkins)
+
+		require.NoError(t, err)
+		assert.False(t, requeue)
+		assert.Equal(t, "jenkins/jenkins:broken", jenkins.Spec.Master.Containers[0].Image)
+	})
+	t.Run("degraded is cleared when spec changes", func(t *testing.T) {
+		jenkins := newJenkins()
+		jenkins.Status.Degraded = true
+		jenkins.Status.DegradedReason = "rolled back"
+		reconciler, _ := newReconciler(jenkins, newPod(jenkins, ""))
+
+		requeue, err := reconciler.rollbackCrashLoop(jenkins)
+
+		require.NoError(t, err)
+		assert.False(t, requeue)
+		updated := &v1alpha2.Jenkins{}
+		require.NoError(t, reconciler.Client.Get(ctx, types.NamespacedName{Namespace: "default", Name: "jenkins"}, updated))
+		assert.False(t, updated.Status.Degraded)
+		assert.Empty(t, updated.Status.DegradedReason)
+	})
+}
+
+func TestJenkinsReconciler_recordLastKnownGoodSpec(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	newJenkins := func(ready bool) *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default", Generation: 5},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{Containers: []v1alpha2.Container{{Name: resources.JenkinsMasterContainerName, Image: "jenkins/jenkins:lts"}}},
+			},
+			Status: v1alpha2.JenkinsStatus{Ready: ready},
+		}
+	}
+
+	t.Run("ready", func(t *testing.T) {
+		jenkins := newJenkins(true)
+		reconciler := &JenkinsReconciler{Client: fake.NewClientBuilder().WithObjects(jenkins).Build()}
+
+		err := reconciler.recordLastKnownGoodSpec(jenkins)
+
+		require.NoError(t, err)
+		require.NotNil(t, jenkins.Status.LastKnownGoodSpec)
+		assert.Equal(t, int64(5), jenkins.Status.LastKnownGoodGeneration)
+		spec := v1alpha2.JenkinsSpec{}
+		require.NoError(t, json.Unmarshal(jenkins.Status.LastKnownGoodSpec.Raw, &spec))
+		assert.Equal(t, jenkins.Spec, spec)
+	})
+	t.Run("not ready", func(t *testing.T) {
+		jenkins := newJenkins(false)
+		reconciler := &JenkinsReconciler{Client: fake.NewClientBuilder().WithObjects(jenkins).Build()}
+
+		err := reconciler.recordLastKnownGoodSpec(jenkins)
+
+		require.NoError(t, err)
+		assert.Nil(t, jenkins.Status.LastKnownGoodSpec)
+	})
+}
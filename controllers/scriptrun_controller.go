@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	scriptRunPendingRequeueDelay = 10 * time.Second
+	scriptRunMaxOutputBytes      = 32 * 1024
+)
+
+// JenkinsScriptRunReconciler executes groovy scripts of JenkinsScriptRun resources once and records the output
+// in their status
+type JenkinsScriptRunReconciler struct {
+	Client                       client.Client
+	ClientSet                    kubernetes.Clientset
+	Config                       rest.Config
+	JenkinsAPIConnectionSettings jenkinsclient.JenkinsAPIConnectionSettings
+	KubernetesClusterDomain      string
+
+	// getJenkinsClient is used in tests instead of the Jenkins API client
+	getJenkinsClient func(jenkins *v1alpha2.Jenkins) (jenkinsclient.Jenkins, error)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *JenkinsScriptRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha2.JenkinsScriptRun{}).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=jenkins.io,resources=jenkinsscriptruns,verbs=get;list;watch
+// +kubebuilder:rbac:groups=jenkins.io,resources=jenkinsscriptruns/status,verbs=get;update;patch
+
+// Reconcile executes the script once the Jenkins instance is configured, finished runs are never executed again.
+func (r *JenkinsScriptRunReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	logger := logx.WithValues("scriptrun", request.NamespacedName.String())
+
+	run := &v1alpha2.JenkinsScriptRun{}
+	err := r.Client.Get(ctx, request.NamespacedName, run)
+	if err != nil && apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		return ctrl.Result{}, errors.WithStack(err)
+	}
+
+	switch run.Status.Phase {
+	case v1alpha2.ScriptRunSucceeded, v1alpha2.ScriptRunFailed:
+		return ctrl.Result{}, nil
+	case v1alpha2.ScriptRunRunning:
+		// the operator has been restarted during the execution, the script must not be executed twice
+		return ctrl.Result{}, r.complete(ctx, run, v1alpha2.ScriptRunFailed, "",
+			"The operator has been restarted during the script execution, the script may have been partially executed")
+	}
+
+	jenkins := &v1alpha2.Jenkins{}
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: run.Namespace, Name: run.Spec.JenkinsName}, jenkins)
+	if err != nil && apierrors.IsNotFound(err) {
+		return ctrl.Result{}, r.complete(ctx, run, v1alpha2.ScriptRunFailed, "",
+			fmt.Sprintf("Jenkins CR '%s' not found", run.Spec.JenkinsName))
+	} else if err != nil {
+		return ctrl.Result{}, errors.WithStack(err)
+	}
+
+	if jenkins.Status.BaseConfigurationCompletedTime == nil {
+		if run.Status.Phase != v1alpha2.ScriptRunPending {
+			run.Status.Phase = v1alpha2.ScriptRunPending
+			run.Status.Message = fmt.Sprintf("Waiting for Jenkins CR '%s' to be configured", jenkins.Name)
+			if err := r.Client.Status().Update(ctx, run); err != nil {
+				return ctrl.Result{}, errors.WithStack(err)
+			}
+		}
+		return ctrl.Result{RequeueAfter: scriptRunPendingRequeueDelay}, nil
+	}
+
+	jenkinsClient, err := r.jenkinsClient(jenkins)
+	if err != nil {
+		logger.V(log.VWarn).Info(fmt.Sprintf("Failed to connect to Jenkins API: %s", err))
+		return ctrl.Result{RequeueAfter: scriptRunPendingRequeueDelay}, nil
+	}
+
+	now := metav1.Now()
+	run.Status.Phase = v1alpha2.ScriptRunRunning
+	run.Status.Message = ""
+	run.Status.StartTime = &now
+	// the Running phase has to be persisted before the execution, a conflict means another reconcile loop took over
+	if err := r.Client.Status().Update(ctx, run); err != nil {
+		return ctrl.Result{}, errors.WithStack(err)
+	}
+
+	logger.Info(fmt.Sprintf("Executing groovy script in Jenkins CR '%s'", jenkins.Name))
+	output, err := jenkinsClient.ExecuteScript(run.Spec.Script)
+	if err != nil {
+		logger.V(log.VWarn).Info(fmt.Sprintf("Groovy script failed: %s", err))
+		return ctrl.Result{}, r.complete(ctx, run, v1alpha2.ScriptRunFailed, output, err.Error())
+	}
+	return ctrl.Result{}, r.complete(ctx, run, v1alpha2.ScriptRunSucceeded, output, "")
+}
+
+func (r *JenkinsScriptRunReconciler) complete(ctx context.Context, run *v1alpha2.JenkinsScriptRun, phase v1alpha2.ScriptRunPhase, output, message string) error {
+	now := metav1.Now()
+	if len(output) > scriptRunMaxOutputBytes {
+		output = output[len(output)-scriptRunMaxOutputBytes:]
+	}
+	run.Status.Phase = phase
+	run.Status.Output = output
+	run.Status.Message = message
+	run.Status.CompletionTime = &now
+	return errors.WithStack(r.Client.Status().Update(ctx, run))
+}
+
+func (r *JenkinsScriptRunReconciler) jenkinsClient(jenkins *v1alpha2.Jenkins) (jenkinsclient.Jenkins, error) {
+	if r.getJenkinsClient != nil {
+		return r.getJenkinsClient(jenkins)
+	}
+	config := configuration.Configuration{
+		Client:                       r.Client,
+		ClientSet:                    r.ClientSet,
+		Config:                       &r.Config,
+		Jenkins:                      jenkins,
+		JenkinsAPIConnectionSettings: r.JenkinsAPIConnectionSettings,
+		KubernetesClusterDomain:      r.KubernetesClusterDomain,
+	}
+	return config.GetJenkinsClient()
+}
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestJenkinsScriptRunReconciler_Reconcile(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	ctx := context.TODO()
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "list-plugins"}}
+	newRun := func(phase v1alpha2.ScriptRunPhase) *v1alpha2.JenkinsScriptRun {
+		return &v1alpha2.JenkinsScriptRun{
+			ObjectMeta: metav1.ObjectMeta{Name: "list-plugins", Namespace: "default"},
+			Spec:       v1alpha2.JenkinsScriptRunSpec{JenkinsName: "jenkins", Script: "println 'hello'"},
+			Status:     v1alpha2.JenkinsScriptRunStatus{Phase: phase},
+		}
+	}
+	now := metav1.Now()
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+		Status:     v1alpha2.JenkinsStatus{BaseConfigurationCompletedTime: &now},
+	}
+	getRun := func(t *testing.T, reconciler *JenkinsScriptRunReconciler) *v1alpha2.JenkinsScriptRun {
+		run := &v1alpha2.JenkinsScriptRun{}
+		require.NoError(t, reconciler.Client.Get(ctx, request.NamespacedName, run))
+		return run
+	}
+
+	t.Run("executes script", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		jenkinsClient := jenkinsclient.NewMockJenkins(mockCtrl)
+		jenkinsClient.EXPECT().ExecuteScript("println 'hello'").Return("hello", nil)
+		reconciler := &JenkinsScriptRunReconciler{
+			Client: fake.NewClientBuilder().WithObjects(jenkins, newRun("")).Build(),
+			getJenkinsClient: func(*v1alpha2.Jenkins) (jenkinsclient.Jenkins, error) {
+				return jenkinsClient, nil
+			},
+		}
+
+		_, err := reconciler.Reconcile(ctx, request)
+
+		require.NoError(t, err)
+		run := getRun(t, reconciler)
+		assert.Equal(t, v1alpha2.ScriptRunSucceeded, run.Status.Phase)
+		assert.Equal(t, "hello", run.Status.Output)
+		assert.NotNil(t, run.Status.StartTime)
+		assert.NotNil(t, run.Status.CompletionTime)
+
+		_, err = reconciler.Reconcile(ctx, request)
+
+		require.NoError(t, err)
+	})
+	t.Run("script failure with truncated output", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		jenkinsClient := jenkinsclient.NewMockJenkins(mockCtrl)
+		output := strings.Repeat("a", scriptRunMaxOutputBytes) + "end"
+		jenkinsClient.EXPECT().ExecuteScript("println 'hello'").Return(output, assert.AnError)
+		reconciler := &JenkinsScriptRunReconciler{
+			Client: fake.NewClientBuilder().WithObjects(jenkins, newRun("")).Build(),
+			getJenkinsClient: func(*v1alpha2.Jenkins) (jenkinsclient.Jenkins, error) {
+				return jenkinsClient, nil
+			},
+		}
+
+		_, err := reconciler.Reconcile(ctx, request)
+
+		require.NoError(t, err)
+		run := getRun(t, reconciler)
+		assert.Equal(t, v1alpha2.ScriptRunFailed, run.Status.Phase)
+		assert.Equal(t, assert.AnError.Error(), run.Status.Message)
+		assert.Len(t, run.Status.Output, scriptRunMaxOutputBytes)
+		assert.True(t, strings.HasSuffix(run.Status.Output, "end"))
+	})
+	t.Run("waits for Jenkins configuration", func(t *testing.T) {
+		notConfigured := jenkins.DeepCopy()
+		notConfigured.Status.BaseConfigurationCompletedTime = nil
+		reconciler := &JenkinsScriptRunReconciler{
+			Client: fake.NewClientBuilder().WithObjects(notConfigured, newRun("")).Build(),
+		}
+
+		result, err := reconciler.Reconcile(ctx, request)
+
+		require.NoError(t, err)
+		assert.Equal(t, scriptRunPendingRequeueDelay, result.RequeueAfter)
+		assert.Equal(t, v1alpha2.ScriptRunPending, getRun(t, reconciler).Status.Phase)
+	})
+	t.Run("Jenkins not found", func(t *testing.T) {
+		reconciler := &JenkinsScriptRunReconciler{
+			Client: fake.NewClientBuilder().WithObjects(newRun("")).Build(),
+		}
+
+		_, err := reconciler.Reconcile(ctx, request)
+
+		require.NoError(t, err)
+		run := getRun(t, reconciler)
+		assert.Equal(t, v1alpha2.ScriptRunFailed, run.Status.Phase)
+		assert.Equal(t, "Jenkins CR 'jenkins' not found", run.Status.Message)
+	})
+	t.Run("interrupted execution is not repeated", func(t *testing.T) {
+		reconciler := &JenkinsScriptRunReconciler{
+			Client: fake.NewClientBuilder().WithObjects(jenkins, newRun(v1alpha2.ScriptRunRunning)).Build(),
+		}
+
+		_, err := reconciler.Reconcile(ctx, request)
+
+		require.NoError(t, err)
+		assert.Equal(t, v1alpha2.ScriptRunFailed, getRun(t, reconciler).Status.Phase)
+	})
+}
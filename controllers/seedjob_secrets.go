@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// seedJobCredentialJenkins maps Secret to reconcile requests of Jenkins CRs which seed jobs use it as a credential,
+// so a rotated or broken credential is re-validated and re-applied right away. Unlike enqueueRequestForJenkins it
+// doesn't rely on labels, the Secret may be recreated without them e.g. by an external secrets controller.
+func (r *JenkinsReconciler) seedJobCredentialJenkins(object client.Object) []reconcile.Request {
+	jenkinsList := &v1alpha2.JenkinsList{}
+	if err := r.Client.List(context.TODO(), jenkinsList, client.InNamespace(object.GetNamespace())); err != nil {
+		logx.Info(fmt.Sprintf("Failed to list Jenkins CRs using seed job credential '%s': %s", object.GetName(), err))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, jenkins := range jenkinsList.Items {
+		if usesSeedJobCredential(jenkins, object.GetName()) {
+			logx.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace).V(log.VDebug).Info(fmt.Sprintf("Seed job credential Secret '%s' has changed", object.GetName()))
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name}})
+		}
+	}
+	return requests
+}
+
+func usesSeedJobCredential(jenkins v1alpha2.Jenkins, secretName string) bool {
+	for _, seedJob := range jenkins.Spec.SeedJobs {
+		if seedJob.CredentialID != secretName {
+			continue
+		}
+		switch seedJob.JenkinsCredentialType {
+		case v1alpha2.BasicSSHCredentialType, v1alpha2.UsernamePasswordCredentialType, v1alpha2.GithubAppCredentialType:
+			return true
+		}
+	}
+	return false
+}
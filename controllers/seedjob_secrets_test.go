@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestJenkinsReconciler_seedJobCredentialJenkins(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	newJenkins := func(namespace, name string, seedJobs ...v1alpha2.SeedJob) *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       v1alpha2.JenkinsSpec{SeedJobs: seedJobs},
+		}
+	}
+	reconciler := &JenkinsReconciler{
+		Client: fake.NewClientBuilder().WithObjects(
+			newJenkins("default", "ssh", v1alpha2.SeedJob{ID: "jobs", CredentialID: "deploy-key", JenkinsCredentialType: v1alpha2.BasicSSHCredentialType}),
+			newJenkins("default", "password", v1alpha2.SeedJob{ID: "jobs", CredentialID: "deploy-key", JenkinsCredentialType: v1alpha2.UsernamePasswordCredentialType}),
+			newJenkins("default", "external", v1alpha2.SeedJob{ID: "jobs", CredentialID: "deploy-key", JenkinsCredentialType: v1alpha2.ExternalCredentialType}),
+			newJenkins("default", "other", v1alpha2.SeedJob{ID: "jobs", CredentialID: "other-key", JenkinsCredentialType: v1alpha2.BasicSSHCredentialType}),
+			newJenkins("other", "ssh", v1alpha2.SeedJob{ID: "jobs", CredentialID: "deploy-key", JenkinsCredentialType: v1alpha2.BasicSSHCredentialType}),
+		).Build(),
+	}
+
+	t.Run("referenced secret", func(t *testing.T) {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "deploy-key", Namespace: "default"}}
+
+		requests := reconciler.seedJobCredentialJenkins(secret)
+
+		assert.ElementsMatch(t, []reconcile.Request{
+			{NamespacedName: types.NamespacedName{Namespace: "default", Name: "ssh"}},
+			{NamespacedName: types.NamespacedName{Namespace: "default", Name: "password"}},
+		}, requests)
+	})
+	t.Run("not referenced secret", func(t *testing.T) {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unused", Namespace: "default"}}
+
+		requests := reconciler.seedJobCredentialJenkins(secret)
+
+		assert.Empty(t, requests)
+	})
+}
@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const redactedValue = "<redacted>"
+
+// sensitiveName matches field and environment variable names which values must not be logged nor sent in notifications
+var sensitiveName = regexp.MustCompile(`(?i)(password|passwd|secret|token|apikey|api_key|credential|private_?key)`)
+
+// notifySpecUpdate logs the changes made in spec of Jenkins CR together with the field managers which made them and
+// sends them as an info notification
+func (e *jenkinsDecorator) notifySpecUpdate(oldJenkins, newJenkins *v1alpha2.Jenkins) {
+	logger := log.Log.WithValues("cr", newJenkins.Name, "namespace", newJenkins.Namespace)
+	changes, err := diffJenkinsSpec(oldJenkins.Spec, newJenkins.Spec)
+	if err != nil {
+		logger.V(log.VWarn).Info(fmt.Sprintf("Failed to compute changes of Jenkins CR spec: %s", err))
+	}
+	message := fmt.Sprintf("%T/%s has been updated by %s, %d field(s) changed", newJenkins, newJenkins.Name, specUpdatedBy(newJenkins), len(changes))
+	logger.Info(message)
+	for _, change := range changes {
+		logger.Info(change)
+	}
+
+	if e.notificationEvents == nil {
+		return
+	}
+	*e.notificationEvents <- event.Event{
+		Jenkins: *newJenkins,
+		Phase:   event.PhaseBase,
+		Level:   v1alpha2.NotificationLevelInfo,
+		Reason:  reason.NewSpecUpdated(reason.HumanSource, []string{message}, append([]string{message}, changes...)...),
+	}
+}
+
+// diffJenkinsSpec returns field-level changes between the old and new spec of Jenkins CR, one change per line in
+// the form 'spec.path: old -> new'. Values of sensitive fields and environment variables are redacted.
+func diffJenkinsSpec(oldSpec, newSpec v1alpha2.JenkinsSpec) ([]string, error) {
+	oldValue, err := toUnstructured(oldSpec)
+	if err != nil {
+		return nil, err
+	}
+	newValue, err := toUnstructured(newSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	diffValues("spec", false, oldValue, newValue, &changes)
+	return changes, nil
+}
+
+func toUnstructured(spec v1alpha2.JenkinsSpec) (interface{}, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func diffValues(path string, sensitive bool, oldValue, newValue interface{}, changes *[]string) {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		sensitive = sensitive || isSensitiveEnvVar(oldMap) || isSensitiveEnvVar(newMap)
+		for _, key := range mergedKeys(oldMap, newMap) {
+			diffValues(path+"."+key, sensitive || sensitiveName.MatchString(key), oldMap[key], newMap[key], changes)
+		}
+		return
+	}
+
+	oldList, oldIsList := oldValue.([]interface{})
+	newList, newIsList := newValue.([]interface{})
+	if oldIsList && newIsList {
+		for i := 0; i < len(oldList) || i < len(newList); i++ {
+			var oldItem, newItem interface{}
+			if i < len(oldList) {
+				oldItem = oldList[i]
+			}
+			if i < len(newList) {
+				newItem = newList[i]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), sensitive, oldItem, newItem, changes)
+		}
+		return
+	}
+
+	if reflect.DeepEqual(oldValue, newValue) {
+		return
+	}
+	*changes = append(*changes, fmt.Sprintf("%s: %s -> %s", path, formatValue(sensitive, oldValue), formatValue(sensitive, newValue)))
+}
+
+// isSensitiveEnvVar returns true if the object is an environment variable with a sensitive name
+func isSensitiveEnvVar(object map[string]interface{}) bool {
+	name, ok := object["name"].(string)
+	if !ok {
+		return false
+	}
+	_, hasValue := object["value"]
+	return hasValue && sensitiveName.MatchString(name)
+}
+
+func mergedKeys(oldMap, newMap map[string]interface{}) []string {
+	keys := make([]string, 0, len(oldMap)+len(newMap))
+	for key := range oldMap {
+		keys = append(keys, key)
+	}
+	for key := range newMap {
+		if _, found := oldMap[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatValue(sensitive bool, value interface{}) string {
+	if value == nil {
+		return "<unset>"
+	}
+	if sensitive {
+		return redactedValue
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// specUpdatedBy returns the field managers which changed spec of the object most recently
+func specUpdatedBy(object metav1.Object) string {
+	var managers []string
+	var lastUpdate *metav1.Time
+	for _, entry := range object.GetManagedFields() {
+		if entry.FieldsV1 == nil || entry.Time == nil || !strings.Contains(string(entry.FieldsV1.Raw), `"f:spec"`) {
+			continue
+		}
+		switch {
+		case lastUpdate == nil || lastUpdate.Before(entry.Time):
+			lastUpdate = entry.Time
+			managers = []string{entry.Manager}
+		case lastUpdate.Equal(entry.Time):
+			managers = append(managers, entry.Manager)
+		}
+	}
+	if len(managers) == 0 {
+		return "unknown"
+	}
+	return strings.Join(managers, ", ")
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiffJenkinsSpec(t *testing.T) {
+	t.Run("no changes", func(t *testing.T) {
+		changes, err := diffJenkinsSpec(v1alpha2.JenkinsSpec{}, v1alpha2.JenkinsSpec{})
+
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+	t.Run("changed, added and removed fields", func(t *testing.T) {
+		oldSpec := v1alpha2.JenkinsSpec{
+			Master: v1alpha2.JenkinsMaster{
+				Containers: []v1alpha2.Container{{Name: "jenkins-master", Image: "jenkins/jenkins:2.277.4-lts"}},
+				Plugins:    []v1alpha2.Plugin{{Name: "git", Version: "4.11.3"}},
+			},
+		}
+		newSpec := v1alpha2.JenkinsSpec{
+			Master: v1alpha2.JenkinsMaster{
+				Containers: []v1alpha2.Container{{Name: "jenkins-master", Image: "jenkins/jenkins:2.289.1-lts"}},
+			},
+			SeedJobAgentImage: "jenkins/inbound-agent:4.10-3",
+		}
+
+		changes, err := diffJenkinsSpec(oldSpec, newSpec)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			`spec.master.containers[0].image: "jenkins/jenkins:2.277.4-lts" -> "jenkins/jenkins:2.289.1-lts"`,
+			`spec.master.plugins: [{"name":"git","version":"4.11.3"}] -> <unset>`,
+			`spec.seedJobAgentImage: <unset> -> "jenkins/inbound-agent:4.10-3"`,
+		}, changes)
+	})
+	t.Run("sensitive values are redacted", func(t *testing.T) {
+		spec := func(password, user string) v1alpha2.JenkinsSpec {
+			return v1alpha2.JenkinsSpec{Master: v1alpha2.JenkinsMaster{Containers: []v1alpha2.Container{{
+				Name: "jenkins-master",
+				Env: []corev1.EnvVar{
+					{Name: "ADMIN_PASSWORD", Value: password},
+					{Name: "ADMIN_USER", Value: user},
+				},
+			}}}}
+		}
+
+		changes, err := diffJenkinsSpec(spec("old-secret", "admin"), spec("new-secret", "jenkins"))
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			`spec.master.containers[0].env[0].value: <redacted> -> <redacted>`,
+			`spec.master.containers[0].env[1].value: "admin" -> "jenkins"`,
+		}, changes)
+	})
+}
+
+func TestSpecUpdatedBy(t *testing.T) {
+	older := metav1.NewTime(time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC))
+	newer := metav1.NewTime(older.Add(time.Minute))
+	specFields := &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:master":{}}}`)}
+	statusFields := &metav1.FieldsV1{Raw: []byte(`{"f:status":{}}`)}
+
+	t.Run("latest spec manager", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{ManagedFields: []metav1.ManagedFieldsEntry{
+			{Manager: "kubectl-client-side-apply", Operation: metav1.ManagedFieldsOperationUpdate, Time: &older, FieldsV1: specFields},
+			{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &newer, FieldsV1: specFields},
+			{Manager: "jenkins-operator", Operation: metav1.ManagedFieldsOperationUpdate, Time: &newer, FieldsV1: statusFields},
+		}}}
+
+		assert.Equal(t, "kubectl-edit", specUpdatedBy(jenkins))
+	})
+	t.Run("no managed fields", func(t *testing.T) {
+		assert.Equal(t, "unknown", specUpdatedBy(&v1alpha2.Jenkins{}))
+	})
+}
@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/user/seedjobs"
+)
+
+const jenkinsVersionGroovyScript = "println Jenkins.VERSION"
+
+// setJenkinsVersion reads the version of Jenkins core once per Jenkins master pod, it returns true if the status
+// has changed
+func setJenkinsVersion(jenkins *v1alpha2.Jenkins, jenkinsClient jenkinsclient.Jenkins) (bool, error) {
+	if len(jenkins.Status.JenkinsVersion) > 0 {
+		return false, nil
+	}
+	output, err := jenkinsClient.ExecuteScript(jenkinsVersionGroovyScript)
+	if err != nil {
+		return false, err
+	}
+	jenkins.Status.JenkinsVersion = strings.TrimSpace(output)
+	return len(jenkins.Status.JenkinsVersion) > 0, nil
+}
+
+// setSeedJobsSummary updates the number of created seed jobs shown by kubectl when seed jobs are added or removed
+// in spec, it returns true if the status has changed
+func setSeedJobsSummary(jenkins *v1alpha2.Jenkins) bool {
+	summary := seedjobs.Summary(*jenkins)
+	if summary == jenkins.Status.SeedJobs {
+		return false
+	}
+	jenkins.Status.SeedJobs = summary
+	return true
+}
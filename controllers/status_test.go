@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetJenkinsVersion(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	jenkinsClient := jenkinsclient.NewMockJenkins(mockCtrl)
+	jenkinsClient.EXPECT().ExecuteScript(jenkinsVersionGroovyScript).Return("2.289.1\n", nil).Times(1)
+	jenkins := &v1alpha2.Jenkins{}
+
+	changed, err := setJenkinsVersion(jenkins, jenkinsClient)
+
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "2.289.1", jenkins.Status.JenkinsVersion)
+
+	changed, err = setJenkinsVersion(jenkins, jenkinsClient)
+
+	require.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestSetSeedJobsSummary(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{}
+
+	t.Run("no seed jobs", func(t *testing.T) {
+		assert.False(t, setSeedJobsSummary(jenkins))
+		assert.Empty(t, jenkins.Status.SeedJobs)
+	})
+	t.Run("seed job added", func(t *testing.T) {
+		jenkins.Spec.SeedJobs = []v1alpha2.SeedJob{{ID: "first"}, {ID: "second"}}
+		jenkins.Status.CreatedSeedJobs = []string{"first", "removed"}
+
+		assert.True(t, setSeedJobsSummary(jenkins))
+		assert.Equal(t, "1/2", jenkins.Status.SeedJobs)
+	})
+	t.Run("unchanged", func(t *testing.T) {
+		assert.False(t, setSeedJobsSummary(jenkins))
+	})
+}
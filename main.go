@@ -19,27 +19,40 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	r "runtime"
+	"strings"
+	"time"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/api/v1alpha3"
 	"github.com/maximba/kubernetes-operator/controllers"
 	"github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/backuprestore"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
 	"github.com/maximba/kubernetes-operator/pkg/constants"
+	"github.com/maximba/kubernetes-operator/pkg/credentials"
 	"github.com/maximba/kubernetes-operator/pkg/event"
 	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/metrics"
 	"github.com/maximba/kubernetes-operator/pkg/notifications"
 	e "github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/runtimeconfig"
+	"github.com/maximba/kubernetes-operator/pkg/scmwebhook"
+	"github.com/maximba/kubernetes-operator/pkg/supportbundle"
+	"github.com/maximba/kubernetes-operator/pkg/updates"
 	"github.com/maximba/kubernetes-operator/version"
 
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -69,6 +82,7 @@ func printInfo() {
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(v1alpha2.AddToScheme(scheme))
+	utilruntime.Must(v1alpha3.AddToScheme(scheme))
 	utilruntime.Must(routev1.AddToScheme(scheme))
 	utilruntime.Must(corev1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
@@ -90,10 +104,30 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", isRunningInCluster, "Enable leader election for controller manager. "+
 		"Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&validateSecurityWarnings, "validate-security-warnings", false, "Enable validation for potential security warnings in jenkins custom resource plugins")
+	conversionWebhook := flag.Bool("conversion-webhook", false, "Serve the conversion webhook of Jenkins CR API versions, required to use the jenkins.io/v1alpha3 API.")
 	hostname := flag.String("jenkins-api-hostname", "", "Hostname or IP of Jenkins API. It can be service name, node IP or localhost.")
 	port := flag.Int("jenkins-api-port", 0, "The port on which Jenkins API is running. Note: If you want to use nodePort don't set this setting and --jenkins-api-use-nodeport must be true.")
 	useNodePort := flag.Bool("jenkins-api-use-nodeport", false, "Connect to Jenkins API using the service nodePort instead of service port. If you want to set this as true - don't set --jenkins-api-port.")
+	usePortForward := flag.Bool("jenkins-api-use-port-forward", false, "Connect to Jenkins API through the Kubernetes API server port-forward of the Jenkins master pod, e.g. when NetworkPolicies block traffic from the operator. Don't set --jenkins-api-hostname, --jenkins-api-port and --jenkins-api-use-nodeport together with it.")
 	kubernetesClusterDomain := flag.String("cluster-domain", "cluster.local", "Use custom domain name instead of 'cluster.local'.")
+	stalledThreshold := flag.Duration("reconcile-stalled-threshold", 15*time.Minute, "How long a Jenkins CR can be requeued before it is marked as stalled. Set to 0 to disable the detection.")
+	reconcileFailLimit := flag.Uint64("reconcile-fail-limit", 10, "The number of the same consecutive reconcile errors after which the operator gives up.")
+	jenkinsMetricsInterval := flag.Duration("jenkins-metrics-interval", 0, "How often queue and executor metrics are scraped from Jenkins API and re-exported by the operator. Set to 0 to disable scraping.")
+	diskUsageInterval := flag.Duration("jenkins-home-disk-usage-interval", 0, "How often utilization of Jenkins home volume is checked by executing df in Jenkins master container, e.g. 5m. Disabled by default.")
+	diskUsageThreshold := flag.Int("jenkins-home-disk-usage-threshold", 90, "Used space of Jenkins home volume in percent, from 0 to 100, above which a warning notification is sent.")
+	credentialsUsageInterval := flag.Duration("credentials-usage-report-interval", 0, "How often credentials present in Jenkins are compared with the ones managed by the operator, e.g. 1h. The differences are exported as metrics and reported as events. Disabled by default.")
+	updateCheckInterval := flag.Duration("update-check-interval", 0, "How often Jenkins core and plugins are compared with the latest versions in the update center, available updates are written into status and sent as an info notification. Set to 0 to disable checking.")
+	updateCenterURL := flag.String("update-center-url", updates.DefaultUpdateCenterURL, "URL of the update center JSON used by the update check.")
+	restoreRehearsalInterval := flag.Duration("restore-rehearsal-check-interval", time.Minute, "How often restore rehearsals of Jenkins CRs with spec.restore.rehearsal are checked. Set to 0 to disable restore rehearsals.")
+	scmWebhookAddr := flag.String("scm-webhook-bind-address", "", "The address the SCM webhook endpoint triggering seed jobs binds to, e.g. ':8082'. Leave empty to disable the endpoint.")
+	supportBundle := flag.Bool("support-bundle", false, "Serves support bundles of Jenkins CRs at /support-bundle/<namespace>/<name> on the metrics endpoint, behind the same authentication and authorization as the metrics.")
+	workqueueBaseDelay := flag.Duration("workqueue-base-delay", 5*time.Millisecond, "The initial delay of requeuing a Jenkins CR after a failed reconcile, the delay doubles on every consecutive failure of the same CR.")
+	workqueueMaxDelay := flag.Duration("workqueue-max-delay", 1000*time.Second, "The maximum delay of requeuing a Jenkins CR after failed reconciles.")
+	notificationProxy := flag.String("notification-proxy", "", "URL of the proxy used to send Slack, Microsoft Teams, webhook and PagerDuty notifications. When empty the proxy is taken from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.")
+	kubeAPIQPS := flag.Float64("kube-api-qps", 20, "The maximum queries per second of the operator to the Kubernetes API.")
+	kubeAPIBurst := flag.Int("kube-api-burst", 30, "The maximum burst of the operator queries to the Kubernetes API.")
+	operatorConfigMap := flag.String("operator-config-map", "", "Name of the ConfigMap, in the watch namespace or given as 'namespace/name', with operator settings applied at runtime. "+
+		"Supported keys: "+strings.Join(runtimeconfig.Keys, ", ")+", the ConfigMap with other keys is rejected.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -101,7 +135,11 @@ func main() {
 	flag.Parse()
 
 	debug := &opts.Development
-	log.Debug = *debug
+	log.SetDebug(*debug)
+	if opts.Level == nil {
+		opts.Level = log.Level
+	}
+	opts.DestWritter = io.MultiWriter(os.Stderr, log.Recent)
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 	printInfo()
 
@@ -120,13 +158,35 @@ func main() {
 		}
 	}
 
+	// validate settings which can be changed at runtime
+	if *workqueueBaseDelay <= 0 || *workqueueMaxDelay < *workqueueBaseDelay {
+		fatal(errors.New("invalid command line parameters: --workqueue-base-delay must be positive and not greater than --workqueue-max-delay"), *debug)
+	}
+	if *kubeAPIQPS <= 0 || *kubeAPIBurst <= 0 {
+		fatal(errors.New("invalid command line parameters: --kube-api-qps and --kube-api-burst must be positive"), *debug)
+	}
+	if err := runtimeconfig.ValidateProxy(*notificationProxy); err != nil {
+		fatal(errors.Wrap(err, "invalid command line parameters: --notification-proxy"), *debug)
+	}
+	runtimeConfig := runtimeconfig.New(runtimeconfig.Settings{
+		Debug:                     *debug,
+		ReconcileFailLimit:        *reconcileFailLimit,
+		ReconcileStalledThreshold: *stalledThreshold,
+		WorkqueueBaseDelay:        *workqueueBaseDelay,
+		WorkqueueMaxDelay:         *workqueueMaxDelay,
+		NotificationProxy:         *notificationProxy,
+		KubeAPIQPS:                float32(*kubeAPIQPS),
+		KubeAPIBurst:              *kubeAPIBurst,
+	})
+
 	// get a config to talk to the API server
 	cfg, err := config.GetConfig()
 	if err != nil {
 		fatal(errors.Wrap(err, "failed to get config"), *debug)
 	}
+	cfg.RateLimiter = runtimeConfig.NewKubeAPIRateLimiter()
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     fmt.Sprintf("%s:%d", metricsHost, metricsPort),
 		Port:                   9443,
@@ -155,10 +215,10 @@ func main() {
 		logger.Info("Route API found: Route creation will be performed")
 	}
 	notificationEvents := make(chan e.Event)
-	go notifications.Listen(notificationEvents, events, mgr.GetClient())
+	go notifications.Listen(notificationEvents, events, mgr.GetClient(), runtimeConfig.NotificationProxy)
 
 	// validate jenkins API connection
-	jenkinsAPIConnectionSettings := client.JenkinsAPIConnectionSettings{Hostname: *hostname, Port: *port, UseNodePort: *useNodePort}
+	jenkinsAPIConnectionSettings := client.JenkinsAPIConnectionSettings{Hostname: *hostname, Port: *port, UseNodePort: *useNodePort, UsePortForward: *usePortForward}
 	if err := jenkinsAPIConnectionSettings.Validate(); err != nil {
 		fatal(errors.Wrap(err, "invalid command line parameters"), *debug)
 	}
@@ -168,6 +228,27 @@ func main() {
 		fatal(errors.Wrap(err, "Kubernetes cluster domain can't be empty"), *debug)
 	}
 
+	if *diskUsageThreshold < 0 || *diskUsageThreshold > 100 {
+		fatal(errors.New("invalid command line parameters: --jenkins-home-disk-usage-threshold must be between 0 and 100"), *debug)
+	}
+
+	if *operatorConfigMap != "" {
+		configMapNamespace, configMapName, err := cache.SplitMetaNamespaceKey(*operatorConfigMap)
+		if err != nil {
+			fatal(errors.Wrap(err, "invalid operator ConfigMap name"), *debug)
+		}
+		if configMapNamespace == "" {
+			configMapNamespace = namespace
+		}
+		if err = (&controllers.OperatorConfigReconciler{
+			Client:        mgr.GetClient(),
+			ConfigMap:     types.NamespacedName{Namespace: configMapNamespace, Name: configMapName},
+			RuntimeConfig: runtimeConfig,
+		}).SetupWithManager(mgr); err != nil {
+			fatal(errors.Wrap(err, "unable to create operator config controller"), *debug)
+		}
+	}
+
 	if err = (&controllers.JenkinsReconciler{
 		Client:                       mgr.GetClient(),
 		Scheme:                       mgr.GetScheme(),
@@ -175,12 +256,115 @@ func main() {
 		ClientSet:                    *clientSet,
 		Config:                       *cfg,
 		NotificationEvents:           &notificationEvents,
+		Events:                       events,
 		KubernetesClusterDomain:      *kubernetesClusterDomain,
+		RuntimeConfig:                runtimeConfig,
 	}).SetupWithManager(mgr); err != nil {
 		fatal(errors.Wrap(err, "unable to create Jenkins controller"), *debug)
 	}
 
-	if validateSecurityWarnings {
+	if err = (&controllers.JenkinsScriptRunReconciler{
+		Client:                       mgr.GetClient(),
+		ClientSet:                    *clientSet,
+		Config:                       *cfg,
+		JenkinsAPIConnectionSettings: jenkinsAPIConnectionSettings,
+		KubernetesClusterDomain:      *kubernetesClusterDomain,
+	}).SetupWithManager(mgr); err != nil {
+		fatal(errors.Wrap(err, "unable to create JenkinsScriptRun controller"), *debug)
+	}
+
+	if *jenkinsMetricsInterval > 0 {
+		if err = mgr.Add(&metrics.JenkinsCollector{
+			Client:                       mgr.GetClient(),
+			ClientSet:                    *clientSet,
+			Config:                       *cfg,
+			JenkinsAPIConnectionSettings: jenkinsAPIConnectionSettings,
+			KubernetesClusterDomain:      *kubernetesClusterDomain,
+			Interval:                     *jenkinsMetricsInterval,
+		}); err != nil {
+			fatal(errors.Wrap(err, "unable to add Jenkins metrics collector"), *debug)
+		}
+	}
+
+	if *diskUsageInterval > 0 {
+		if err = mgr.Add(&metrics.DiskUsageMonitor{
+			Client:              mgr.GetClient(),
+			ClientSet:           *clientSet,
+			Config:              *cfg,
+			NotificationEvents:  &notificationEvents,
+			Interval:            *diskUsageInterval,
+			ThresholdPercentage: *diskUsageThreshold,
+		}); err != nil {
+			fatal(errors.Wrap(err, "unable to add Jenkins home disk usage monitor"), *debug)
+		}
+	}
+
+	if *credentialsUsageInterval > 0 {
+		if err = mgr.Add(&credentials.UsageReporter{
+			Client:                       mgr.GetClient(),
+			ClientSet:                    *clientSet,
+			Config:                       *cfg,
+			Events:                       events,
+			JenkinsAPIConnectionSettings: jenkinsAPIConnectionSettings,
+			KubernetesClusterDomain:      *kubernetesClusterDomain,
+			Interval:                     *credentialsUsageInterval,
+		}); err != nil {
+			fatal(errors.Wrap(err, "unable to add credentials usage reporter"), *debug)
+		}
+	}
+
+	if *updateCheckInterval > 0 {
+		if err = mgr.Add(&updates.Checker{
+			Client:                       mgr.GetClient(),
+			ClientSet:                    *clientSet,
+			Config:                       *cfg,
+			JenkinsAPIConnectionSettings: jenkinsAPIConnectionSettings,
+			KubernetesClusterDomain:      *kubernetesClusterDomain,
+			NotificationEvents:           &notificationEvents,
+			Interval:                     *updateCheckInterval,
+			UpdateCenterURL:              *updateCenterURL,
+		}); err != nil {
+			fatal(errors.Wrap(err, "unable to add update check"), *debug)
+		}
+	}
+
+	if *restoreRehearsalInterval > 0 {
+		if err = mgr.Add(&backuprestore.RestoreRehearsal{
+			Client:                       mgr.GetClient(),
+			ClientSet:                    *clientSet,
+			Config:                       *cfg,
+			JenkinsAPIConnectionSettings: jenkinsAPIConnectionSettings,
+			KubernetesClusterDomain:      *kubernetesClusterDomain,
+			Interval:                     *restoreRehearsalInterval,
+		}); err != nil {
+			fatal(errors.Wrap(err, "unable to add restore rehearsal"), *debug)
+		}
+	}
+
+	if len(*scmWebhookAddr) > 0 {
+		if err = mgr.Add(&scmwebhook.Server{
+			Client:                       mgr.GetClient(),
+			ClientSet:                    *clientSet,
+			Config:                       *cfg,
+			JenkinsAPIConnectionSettings: jenkinsAPIConnectionSettings,
+			KubernetesClusterDomain:      *kubernetesClusterDomain,
+			BindAddress:                  *scmWebhookAddr,
+		}); err != nil {
+			fatal(errors.Wrap(err, "unable to add SCM webhook server"), *debug)
+		}
+	}
+
+	if *supportBundle {
+		if err = mgr.AddMetricsExtraHandler(supportbundle.PathPrefix, &supportbundle.Server{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Logs:      log.Recent,
+		}); err != nil {
+			fatal(errors.Wrap(err, "unable to add support bundle endpoint"), *debug)
+		}
+	}
+
+	if validateSecurityWarnings || *conversionWebhook {
 		if err = (&v1alpha2.Jenkins{}).SetupWebhookWithManager(mgr); err != nil {
 			fatal(errors.Wrap(err, "unable to create Webhook"), *debug)
 		}
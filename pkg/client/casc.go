@@ -0,0 +1,29 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/bndr/gojenkins"
+	"github.com/pkg/errors"
+)
+
+// ReloadConfigurationAsCode reloads the Configuration as Code from the sources in CASC_JENKINS_CONFIG
+func (jenkins *jenkins) ReloadConfigurationAsCode() error {
+	output := ""
+	ar := gojenkins.NewAPIRequest("POST", "/configuration-as-code/reload", nil)
+	if err := jenkins.Requester.SetCrumb(ar); err != nil {
+		return err
+	}
+	ar.Suffix = ""
+
+	r, err := jenkins.Requester.Do(ar, &output, nil)
+	if err != nil {
+		return errors.Wrap(err, "couldn't reload Configuration as Code")
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		return errors.Errorf("couldn't reload Configuration as Code, invalid status code '%d', response '%s'", r.StatusCode, output)
+	}
+	return nil
+}
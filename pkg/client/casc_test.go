@@ -0,0 +1,57 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bndr/gojenkins"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadConfigurationAsCode(t *testing.T) {
+	newJenkinsClient := func(ts *httptest.Server) *jenkins {
+		jenkinsClient := &jenkins{}
+		jenkinsClient.Server = ts.URL
+		jenkinsClient.Requester = &gojenkins.Requester{
+			Base:      ts.URL,
+			SslVerify: true,
+			Client:    ts.Client(),
+			BasicAuth: &gojenkins.BasicAuth{Username: "unused", Password: "unused"},
+		}
+		return jenkinsClient
+	}
+
+	t.Run("reloaded", func(t *testing.T) {
+		var reloaded bool
+		ts := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			if request.Method == http.MethodPost && request.URL.Path == "/configuration-as-code/reload" {
+				reloaded = true
+				return
+			}
+			responseWriter.WriteHeader(http.StatusNotFound)
+		}))
+		defer ts.Close()
+
+		err := newJenkinsClient(ts).ReloadConfigurationAsCode()
+
+		assert.NoError(t, err)
+		assert.True(t, reloaded)
+	})
+	t.Run("throw 500", func(t *testing.T) {
+		ts := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+			if request.URL.Path == "/configuration-as-code/reload" {
+				responseWriter.WriteHeader(http.StatusInternalServerError)
+				_, _ = fmt.Fprint(responseWriter, "invalid configuration")
+				return
+			}
+			responseWriter.WriteHeader(http.StatusNotFound)
+		}))
+		defer ts.Close()
+
+		err := newJenkinsClient(ts).ReloadConfigurationAsCode()
+
+		assert.EqualError(t, err, "couldn't reload Configuration as Code, invalid status code '500', response 'invalid configuration'")
+	})
+}
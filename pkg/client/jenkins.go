@@ -57,6 +57,7 @@ type Jenkins interface {
 	Poll() (int, error)
 	ExecuteScript(groovyScript string) (logs string, err error)
 	GetNodeSecret(name string) (string, error)
+	ReloadConfigurationAsCode() error
 }
 
 type jenkins struct {
@@ -68,6 +69,9 @@ type JenkinsAPIConnectionSettings struct {
 	Hostname    string
 	Port        int
 	UseNodePort bool
+	// UsePortForward tunnels connections to the Jenkins master pod through the Kubernetes API server port-forward,
+	// it's required when NetworkPolicies block traffic from the operator to Jenkins
+	UsePortForward bool
 }
 
 type setBearerToken struct {
@@ -87,6 +91,47 @@ func (t *setBearerToken) RoundTrip(r *http.Request) (*http.Response, error) {
 	return t.transport().RoundTrip(r)
 }
 
+// Option configures the Jenkins API client.
+type Option func(httpClient *http.Client)
+
+// WithHeader adds the HTTP header to all requests to Jenkins API, e.g. the token of an auth proxy in front of Jenkins.
+func WithHeader(name, value string) Option {
+	return func(httpClient *http.Client) {
+		httpClient.Transport = &setHeader{name: name, value: value, rt: httpClient.Transport}
+	}
+}
+
+type setHeader struct {
+	rt    http.RoundTripper
+	name  string
+	value string
+}
+
+func (t *setHeader) transport() http.RoundTripper {
+	if t.rt != nil {
+		return t.rt
+	}
+	return http.DefaultTransport
+}
+
+func (t *setHeader) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.Header.Set(t.name, t.value)
+	return t.transport().RoundTrip(r)
+}
+
+const maxRedirects = 10
+
+// checkRedirect fails on redirects to another host, e.g. to the login page of an auth proxy in front of Jenkins
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return errors.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if req.URL.Host != via[0].URL.Host {
+		return errors.Errorf("Jenkins API request has been redirected to '%s://%s%s', Jenkins may be behind an auth proxy", req.URL.Scheme, req.URL.Host, req.URL.Path)
+	}
+	return nil
+}
+
 // CreateOrUpdateJob creates or updates a job from config.
 func (jenkins *jenkins) CreateOrUpdateJob(config, jobName string) (job *gojenkins.Job, created bool, err error) {
 	// create or update
@@ -122,6 +167,10 @@ func (j JenkinsAPIConnectionSettings) Validate() error {
 		return errors.New("can't use service port and nodePort both. Please use port or nodePort")
 	}
 
+	if j.UsePortForward && (j.Hostname != "" || j.Port > 0 || j.UseNodePort) {
+		return errors.New("can't use port-forward together with hostname, port or nodePort")
+	}
+
 	if j.Port < 0 {
 		return errors.New("service port cannot be lower than 0")
 	}
@@ -134,16 +183,16 @@ func (j JenkinsAPIConnectionSettings) Validate() error {
 }
 
 // NewUserAndPasswordAuthorization creates Jenkins API client with user and password authorization.
-func NewUserAndPasswordAuthorization(url, userName, passwordOrToken string) (Jenkins, error) {
-	return newClient(url, userName, passwordOrToken)
+func NewUserAndPasswordAuthorization(url, userName, passwordOrToken string, options ...Option) (Jenkins, error) {
+	return newClient(url, userName, passwordOrToken, options...)
 }
 
 // NewBearerTokenAuthorization creates Jenkins API client with bearer token authorization.
-func NewBearerTokenAuthorization(url, token string) (Jenkins, error) {
-	return newClient(url, "", token)
+func NewBearerTokenAuthorization(url, token string, options ...Option) (Jenkins, error) {
+	return newClient(url, "", token, options...)
 }
 
-func newClient(url, userName, passwordOrToken string) (Jenkins, error) {
+func newClient(url, userName, passwordOrToken string, options ...Option) (Jenkins, error) {
 	if strings.HasSuffix(url, "/") {
 		url = url[:len(url)-1]
 	}
@@ -158,8 +207,9 @@ func newClient(url, userName, passwordOrToken string) (Jenkins, error) {
 	}
 
 	httpClient := &http.Client{
-		Jar:     jar,
-		Timeout: 20 * time.Second,
+		Jar:           jar,
+		Timeout:       20 * time.Second,
+		CheckRedirect: checkRedirect,
 	}
 
 	if len(userName) > 0 && len(passwordOrToken) > 0 {
@@ -167,6 +217,9 @@ func newClient(url, userName, passwordOrToken string) (Jenkins, error) {
 	} else {
 		httpClient.Transport = &setBearerToken{token: passwordOrToken, rt: httpClient.Transport}
 	}
+	for _, option := range options {
+		option(httpClient)
+	}
 
 	jenkinsClient.Requester = &gojenkins.Requester{
 		Base:      url,
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJenkinsAPIConnectionSettings_Validate(t *testing.T) {
+	t.Run("port-forward", func(t *testing.T) {
+		settings := JenkinsAPIConnectionSettings{UsePortForward: true}
+
+		assert.NoError(t, settings.Validate())
+	})
+	t.Run("port-forward with hostname", func(t *testing.T) {
+		settings := JenkinsAPIConnectionSettings{Hostname: "localhost", UsePortForward: true}
+
+		assert.EqualError(t, settings.Validate(), "can't use port-forward together with hostname, port or nodePort")
+	})
+	t.Run("port and nodePort", func(t *testing.T) {
+		settings := JenkinsAPIConnectionSettings{Hostname: "localhost", Port: 8080, UseNodePort: true}
+
+		assert.EqualError(t, settings.Validate(), "can't use service port and nodePort both. Please use port or nodePort")
+	})
+}
+
+func TestCheckRedirect(t *testing.T) {
+	jenkins, _ := http.NewRequest(http.MethodGet, "http://jenkins:8080/api/json", nil)
+
+	t.Run("same host", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://jenkins:8080/job/test/", nil)
+
+		assert.NoError(t, checkRedirect(req, []*http.Request{jenkins}))
+	})
+	t.Run("login page of auth proxy", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "https://login.example.com/oauth2/auth?client_id=jenkins", nil)
+
+		assert.EqualError(t, checkRedirect(req, []*http.Request{jenkins}),
+			"Jenkins API request has been redirected to 'https://login.example.com/oauth2/auth', Jenkins may be behind an auth proxy")
+	})
+}
+
+func TestWithHeader(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		_, _ = responseWriter.Write([]byte(request.Header.Get("X-Forwarded-Access-Token")))
+	}))
+	defer ts.Close()
+	httpClient := &http.Client{}
+	WithHeader("X-Forwarded-Access-Token", "access-token")(httpClient)
+
+	response, err := httpClient.Get(ts.URL)
+
+	require.NoError(t, err)
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", string(body))
+}
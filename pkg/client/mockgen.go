@@ -632,3 +632,17 @@ func (mr *MockJenkinsMockRecorder) ExecuteScript(groovyScript interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteScript", reflect.TypeOf((*MockJenkins)(nil).ExecuteScript), groovyScript)
 }
+
+// ReloadConfigurationAsCode mocks base method
+func (m *MockJenkins) ReloadConfigurationAsCode() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReloadConfigurationAsCode")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReloadConfigurationAsCode indicates an expected call of ReloadConfigurationAsCode
+func (mr *MockJenkinsMockRecorder) ReloadConfigurationAsCode() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReloadConfigurationAsCode", reflect.TypeOf((*MockJenkins)(nil).ReloadConfigurationAsCode))
+}
@@ -0,0 +1,84 @@
+package configuration
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"sync"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	stackerr "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// appliedResource is the hash of the object sent with the latest apply and the resource version returned by it
+type appliedResource struct {
+	hash            string
+	resourceVersion string
+}
+
+// appliedResourceCache remembers resources applied by the operator, a resource which hasn't changed on either side
+// since the latest apply doesn't have to be applied again. The resources are grouped by the Jenkins CR owning them,
+// so they can be forgotten together when the CR is deleted.
+type appliedResourceCache struct {
+	mutex     sync.Mutex
+	resources map[types.NamespacedName]map[string]appliedResource
+}
+
+var appliedResources = appliedResourceCache{resources: map[types.NamespacedName]map[string]appliedResource{}}
+
+// ForgetAppliedResources drops the resources applied for the Jenkins CR, it's called when the CR has been deleted
+func ForgetAppliedResources(jenkins types.NamespacedName) {
+	appliedResources.forget(jenkins)
+}
+
+func (c *appliedResourceCache) owner(jenkins *v1alpha2.Jenkins) types.NamespacedName {
+	return types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name}
+}
+
+func (c *appliedResourceCache) key(gvk schema.GroupVersionKind, obj client.Object) string {
+	return gvk.String() + "/" + obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// isApplied returns true if the object with the given hash has been applied and the existing resource hasn't been
+// modified by anyone since then
+func (c *appliedResourceCache) isApplied(jenkins *v1alpha2.Jenkins, gvk schema.GroupVersionKind, existing client.Object, hash string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	applied, found := c.resources[c.owner(jenkins)][c.key(gvk, existing)]
+	return found && applied.hash == hash && applied.resourceVersion == existing.GetResourceVersion()
+}
+
+func (c *appliedResourceCache) set(jenkins *v1alpha2.Jenkins, gvk schema.GroupVersionKind, obj client.Object, hash string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	owner := c.owner(jenkins)
+	if c.resources[owner] == nil {
+		c.resources[owner] = map[string]appliedResource{}
+	}
+	c.resources[owner][c.key(gvk, obj)] = appliedResource{hash: hash, resourceVersion: obj.GetResourceVersion()}
+}
+
+func (c *appliedResourceCache) delete(jenkins *v1alpha2.Jenkins, gvk schema.GroupVersionKind, obj client.Object) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.resources[c.owner(jenkins)], c.key(gvk, obj))
+}
+
+func (c *appliedResourceCache) forget(jenkins types.NamespacedName) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.resources, jenkins)
+}
+
+func objectHash(obj client.Object) (string, error) {
+	content, err := json.Marshal(obj)
+	if err != nil {
+		return "", stackerr.WithStack(err)
+	}
+	hash := sha256.Sum256(content)
+	return base64.StdEncoding.EncodeToString(hash[:]), nil
+}
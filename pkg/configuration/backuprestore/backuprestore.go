@@ -3,6 +3,7 @@ package backuprestore
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -10,19 +11,21 @@ import (
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
 	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
 	"github.com/maximba/kubernetes-operator/pkg/configuration"
-	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/cron"
 	"github.com/maximba/kubernetes-operator/pkg/log"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	k8s "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type backupTrigger struct {
 	interval uint64
-	ticker   *time.Ticker
+	schedule string
+	done     chan struct{}
 }
 
 type backupTriggers struct {
@@ -34,7 +37,7 @@ func (t *backupTriggers) stop(logger logr.Logger, namespace string, name string)
 	trigger, found := t.triggers[key]
 	if found {
 		logger.Info(fmt.Sprintf("Stopping backup trigger for '%s'", key))
-		trigger.ticker.Stop()
+		close(trigger.done)
 		delete(t.triggers, key)
 	} else {
 		logger.V(log.VWarn).Info(fmt.Sprintf("Can't stop backup trigger for '%s', not found, skipping", key))
@@ -87,6 +90,19 @@ func (bar *BackupAndRestore) Validate() []string {
 		if restore.Action.Exec == nil {
 			messages = append(messages, "spec.restore.action.exec is not configured")
 		}
+		if restore.Rehearsal != nil && restore.GetLatestAction.Exec == nil {
+			messages = append(messages, "spec.restore.rehearsal requires spec.restore.getLatestAction.exec")
+		}
+		if restore.DryRun && restore.ListAction.Exec == nil {
+			messages = append(messages, "spec.restore.dryRun requires spec.restore.listAction.exec")
+		}
+	} else if !IsS3Backup(bar.Configuration.Jenkins) {
+		if restore.Rehearsal != nil {
+			messages = append(messages, "spec.restore.rehearsal requires spec.restore.containerName")
+		}
+		if restore.DryRun {
+			messages = append(messages, "spec.restore.dryRun requires spec.restore.containerName or spec.backup.s3")
+		}
 	}
 
 	backup := bar.Configuration.Jenkins.Spec.Backup
@@ -98,9 +114,28 @@ func (bar *BackupAndRestore) Validate() []string {
 		if backup.Action.Exec == nil {
 			messages = append(messages, "spec.backup.action.exec is not configured")
 		}
-		if backup.Interval == 0 {
+		if backup.Interval == 0 && len(backup.Schedule) == 0 {
 			messages = append(messages, "spec.backup.interval is not configured")
 		}
+		if backup.DryRun != nil {
+			if backup.DryRun.ThroughputBytesPerSecond < 0 {
+				messages = append(messages, "spec.backup.dryRun.throughputBytesPerSecond can't be negative")
+			}
+			for _, exclude := range backup.DryRun.Excludes {
+				if len(exclude) == 0 || filepath.IsAbs(exclude) || strings.HasPrefix(filepath.Clean(exclude), "..") {
+					messages = append(messages, fmt.Sprintf("spec.backup.dryRun.excludes '%s' must be a path relative to Jenkins home", exclude))
+				}
+			}
+		}
+	}
+
+	messages = append(messages, validateBackupDestinations(backup, allContainers)...)
+	messages = append(messages, validateS3Backup(bar.Configuration.Jenkins)...)
+	if IsS3Backup(bar.Configuration.Jenkins) && backup.Interval == 0 && len(backup.Schedule) == 0 {
+		messages = append(messages, "spec.backup.interval is not configured")
+	}
+	if err := cron.Validate(backup.Schedule); err != nil {
+		messages = append(messages, fmt.Sprintf("spec.backup.schedule: %s", err))
 	}
 
 	if len(restore.ContainerName) > 0 && len(backup.ContainerName) == 0 {
@@ -119,7 +154,8 @@ const noBackup = "-1"
 // Restore performs Jenkins restore backup operation
 func (bar *BackupAndRestore) Restore(jenkinsClient jenkinsclient.Jenkins) error {
 	jenkins := bar.Configuration.Jenkins
-	if len(jenkins.Spec.Restore.ContainerName) == 0 || jenkins.Spec.Restore.Action.Exec == nil {
+	s3Backup := IsS3Backup(jenkins)
+	if !s3Backup && (len(jenkins.Spec.Restore.ContainerName) == 0 || jenkins.Spec.Restore.Action.Exec == nil) {
 		bar.logger.V(log.VDebug).Info("Skipping restore backup, backup restore not configured")
 		return nil
 	}
@@ -128,7 +164,7 @@ func (bar *BackupAndRestore) Restore(jenkinsClient jenkinsclient.Jenkins) error
 		return nil
 	}
 
-	if jenkins.Status.LastBackup == 0 && jenkins.Spec.Restore.GetLatestAction.Exec == nil {
+	if jenkins.Status.LastBackup == 0 && !s3Backup && jenkins.Spec.Restore.GetLatestAction.Exec == nil {
 		bar.logger.V(log.VDebug).Info("Skipping restore backup")
 		if jenkins.Status.PendingBackup == 0 {
 			jenkins.Status.PendingBackup = 1
@@ -137,43 +173,51 @@ func (bar *BackupAndRestore) Restore(jenkinsClient jenkinsclient.Jenkins) error
 		return nil
 	}
 
-	podName := resources.GetJenkinsMasterPodName(jenkins)
 	var backupNumber = jenkins.Status.LastBackup
 
-	if jenkins.Spec.Restore.GetLatestAction.Exec != nil {
-		command := jenkins.Spec.Restore.GetLatestAction.Exec.Command
-		backupNumberRaw, _, err := bar.Exec(podName, jenkins.Spec.Restore.ContainerName, command)
+	if s3Backup {
+		latestBackup, err := bar.getLatestS3Backup()
 		if err != nil {
 			return err
 		}
-
-		backupNumberString := strings.TrimSuffix(backupNumberRaw.String(), "\n")
-		if backupNumberString == noBackup {
-			bar.logger.V(log.VDebug).Info("Skipping restore backup, get latest action returned -1")
+		if latestBackup == 0 {
+			bar.logger.V(log.VDebug).Info("Skipping restore backup, there is no backup in S3 bucket")
 			jenkins.Status.LastBackup = 0
 			jenkins.Status.PendingBackup = 1
 			return bar.Client.Status().Update(context.TODO(), jenkins)
 		}
-
-		backupNumber, err = strconv.ParseUint(backupNumberString, 10, 64)
+		backupNumber = latestBackup
+	} else if jenkins.Spec.Restore.GetLatestAction.Exec != nil {
+		latestBackup, err := bar.getLatestContainerBackup()
 		if err != nil {
-			return errors.Wrapf(err, "invalid backup number '%s' returned by get last backup number action", backupNumberString)
+			return err
 		}
-
-		if backupNumber < 1 {
-			return errors.Errorf("invalid backup number '%d' returned by get last backup number action", backupNumber)
+		if latestBackup == 0 {
+			bar.logger.V(log.VDebug).Info("Skipping restore backup, get latest action returned -1")
+			jenkins.Status.LastBackup = 0
+			jenkins.Status.PendingBackup = 1
+			return bar.Client.Status().Update(context.TODO(), jenkins)
 		}
+		backupNumber = latestBackup
 	} else {
 		bar.logger.V(log.VWarn).Info("spec.restore.getLatestAction not set, you may loose backup history when Jenkins CR status will be clear")
 	}
 
-	if jenkins.Spec.Restore.RecoveryOnce != 0 {
+	latestBackup := backupNumber
+	if jenkins.Spec.Restore.DryRun {
+		bar.logger.V(log.VDebug).Info("spec.restore.dryRun is set, restoring the latest backup")
+	} else if jenkins.Spec.Restore.RecoveryOnce != 0 {
 		backupNumber = jenkins.Spec.Restore.RecoveryOnce
+	} else if jenkins.Spec.Restore.BackupNumber != 0 {
+		backupNumber = jenkins.Spec.Restore.BackupNumber
 	}
 	bar.logger.Info(fmt.Sprintf("Restoring backup '%d'", backupNumber))
-	command := jenkins.Spec.Restore.Action.Exec.Command
-	command = append(command, fmt.Sprintf("%d", backupNumber))
-	_, _, err := bar.Exec(podName, jenkins.Spec.Restore.ContainerName, command)
+	var err error
+	if s3Backup {
+		err = bar.restoreFromS3(backupNumber)
+	} else {
+		_, err = bar.execAction(restoreCommandAction, jenkins.Spec.Restore.ContainerName, jenkins.Spec.Restore.Action, fmt.Sprintf("%d", backupNumber))
+	}
 
 	if err == nil {
 		_, err := jenkinsClient.ExecuteScript("Jenkins.instance.reload()")
@@ -181,86 +225,213 @@ func (bar *BackupAndRestore) Restore(jenkinsClient jenkinsclient.Jenkins) error
 			return err
 		}
 		//TODO fix me because we're doing two saves unatomically
-		jenkins.Spec.Restore.RecoveryOnce = 0
-		err = bar.Client.Update(context.TODO(), jenkins)
-		if err != nil {
-			return err
-		}
-		key := types.NamespacedName{
-			Namespace: jenkins.Namespace,
-			Name:      jenkins.Name,
-		}
-		err = bar.Client.Get(context.TODO(), key, jenkins)
-		if err != nil {
-			return err
+		if !jenkins.Spec.Restore.DryRun && jenkins.Spec.Restore.RecoveryOnce != 0 {
+			// only recoveryOnce is patched, the spec inherited from another Jenkins CR is merged in memory
+			patched := jenkins.DeepCopy()
+			patched.Spec.Restore.RecoveryOnce = 0
+			err = bar.Client.Patch(context.TODO(), patched, k8s.MergeFrom(jenkins))
+			if err != nil {
+				return err
+			}
+			jenkins.Spec.Restore.RecoveryOnce = 0
+			jenkins.ResourceVersion = patched.ResourceVersion
 		}
-		bar.Configuration.Jenkins = jenkins
 
 		jenkins.Status.RestoredBackup = backupNumber
+		// the next backup mustn't overwrite the backups newer than the restored one
 		jenkins.Status.PendingBackup = backupNumber + 1
+		if latestBackup > backupNumber {
+			jenkins.Status.PendingBackup = latestBackup + 1
+		}
 		return bar.Client.Status().Update(context.TODO(), jenkins)
 	}
 
 	return err
 }
 
+// getLatestContainerBackup returns the backup number printed by spec.restore.getLatestAction or 0 if there is no backup
+func (bar *BackupAndRestore) getLatestContainerBackup() (uint64, error) {
+	jenkins := bar.Configuration.Jenkins
+	backupNumberRaw, err := bar.execAction(getLatestCommandAction, jenkins.Spec.Restore.ContainerName, jenkins.Spec.Restore.GetLatestAction)
+	if err != nil {
+		return 0, err
+	}
+
+	backupNumberString := strings.TrimSuffix(backupNumberRaw.String(), "\n")
+	if backupNumberString == noBackup {
+		return 0, nil
+	}
+
+	backupNumber, err := strconv.ParseUint(backupNumberString, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid backup number '%s' returned by get last backup number action", backupNumberString)
+	}
+	if backupNumber < 1 {
+		return 0, errors.Errorf("invalid backup number '%d' returned by get last backup number action", backupNumber)
+	}
+	return backupNumber, nil
+}
+
 // Backup performs Jenkins backup operation
 func (bar *BackupAndRestore) Backup(setBackupDoneBeforePodDeletion bool) error {
 	jenkins := bar.Configuration.Jenkins
-	if len(jenkins.Spec.Backup.ContainerName) == 0 || jenkins.Spec.Backup.Action.Exec == nil {
+	if !IsS3Backup(jenkins) && (len(jenkins.Spec.Backup.ContainerName) == 0 || jenkins.Spec.Backup.Action.Exec == nil) {
 		bar.logger.V(log.VDebug).Info("Skipping restore backup, backup restore not configured")
 		return nil
 	}
+	if bar.IsBackupDryRun() {
+		bar.logger.V(log.VDebug).Info("Skipping backup, backup dry run enabled")
+		return nil
+	}
 	if jenkins.Status.PendingBackup == jenkins.Status.LastBackup {
+		if hasFailedBackupDestinations(jenkins) {
+			return bar.retryBackupDestinations()
+		}
 		bar.logger.V(log.VDebug).Info("Skipping backup")
 		return nil
 	}
 	backupNumber := jenkins.Status.PendingBackup
 	bar.logger.Info(fmt.Sprintf("Performing backup '%d'", backupNumber))
-	podName := resources.GetJenkinsMasterPodName(jenkins)
-	command := jenkins.Spec.Backup.Action.Exec.Command
-	command = append(command, fmt.Sprintf("%d", backupNumber))
-	_, _, err := bar.Exec(podName, jenkins.Spec.Backup.ContainerName, command)
+	var err error
+	if IsS3Backup(jenkins) {
+		err = bar.backupToS3(backupNumber)
+	} else {
+		_, err = bar.execAction(backupCommandAction, jenkins.Spec.Backup.ContainerName, jenkins.Spec.Backup.Action, fmt.Sprintf("%d", backupNumber))
+	}
 
-	if err == nil {
-		bar.logger.V(log.VDebug).Info(fmt.Sprintf("Backup completed '%d', updating status", backupNumber))
-		if jenkins.Status.RestoredBackup == 0 {
-			jenkins.Status.RestoredBackup = backupNumber
+	if err != nil {
+		conditionChanged := configuration.SetCondition(jenkins, v1alpha2.ConditionBackupHealthy, metav1.ConditionFalse,
+			configuration.ConditionReasonBackupFailed, fmt.Sprintf("Backup '%d' failed: %s", backupNumber, err))
+		if jenkins.Status.LastBackupError != err.Error() || conditionChanged {
+			jenkins.Status.LastBackupError = err.Error()
+			if updateErr := bar.Client.Status().Update(context.TODO(), jenkins); updateErr != nil {
+				bar.logger.V(log.VWarn).Info(fmt.Sprintf("Failed to record backup error in status: %s", updateErr))
+			}
 		}
-		jenkins.Status.LastBackup = backupNumber
-		jenkins.Status.PendingBackup = backupNumber
-		jenkins.Status.BackupDoneBeforePodDeletion = setBackupDoneBeforePodDeletion
-		return bar.Client.Status().Update(context.TODO(), jenkins)
+		return err
 	}
 
+	// the backup is made, the destinations where it has failed are retried with the same backup number
+	destinationsErr := bar.backupToDestinations(backupNumber)
+
+	bar.logger.V(log.VDebug).Info(fmt.Sprintf("Backup completed '%d', updating status", backupNumber))
+	if jenkins.Status.RestoredBackup == 0 {
+		jenkins.Status.RestoredBackup = backupNumber
+	}
+	jenkins.Status.LastBackup = backupNumber
+	jenkins.Status.PendingBackup = backupNumber
+	now := metav1.Now()
+	jenkins.Status.LastBackupTime = &now
+	jenkins.Status.LastBackupNumber = backupNumber
+	jenkins.Status.LastBackupError = ""
+	configuration.SetCondition(jenkins, v1alpha2.ConditionBackupHealthy, metav1.ConditionTrue,
+		configuration.ConditionReasonBackupSucceeded, fmt.Sprintf("Backup '%d' has been made", backupNumber))
+	jenkins.Status.BackupDoneBeforePodDeletion = setBackupDoneBeforePodDeletion
+	if err := bar.Client.Status().Update(context.TODO(), jenkins); err != nil {
+		return err
+	}
+	return destinationsErr
+}
+
+// backupToDestinations copies the backup to spec.backup.destinations and records the outcome in status
+func (bar *BackupAndRestore) backupToDestinations(backupNumber uint64) error {
+	jenkins := bar.Configuration.Jenkins
+	if len(jenkins.Spec.Backup.Destinations) == 0 {
+		return nil
+	}
+	var err error
+	jenkins.Status.BackupDestinations, err = backupToDestinations(jenkins.Spec.Backup, jenkins.Status.BackupDestinations, backupNumber,
+		func(containerName string, handler v1alpha2.Handler, args ...string) error {
+			_, err := bar.execHandler(containerName, handler, args...)
+			return err
+		})
 	return err
 }
 
-func triggerBackup(ticker *time.Ticker, k8sClient k8s.Client, logger logr.Logger, namespace, name string) {
-	for range ticker.C {
-		jenkins := &v1alpha2.Jenkins{}
-		err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, jenkins)
-		if err != nil && apierrors.IsNotFound(err) {
-			triggers.stop(logger, namespace, name)
-			return // abort
-		} else if err != nil {
-			logger.V(log.VWarn).Info(fmt.Sprintf("backup trigger, error when fetching CR: %s", err))
+// retryBackupDestinations copies the latest backup to the destinations where it has failed, the backup itself isn't
+// made again
+func (bar *BackupAndRestore) retryBackupDestinations() error {
+	jenkins := bar.Configuration.Jenkins
+	backupNumber := jenkins.Status.LastBackup
+	bar.logger.Info(fmt.Sprintf("Retrying backup '%d' to failed destinations", backupNumber))
+	destinationsErr := bar.backupToDestinations(backupNumber)
+	if err := bar.Client.Status().Update(context.TODO(), jenkins); err != nil {
+		return err
+	}
+	return destinationsErr
+}
+
+// IsBackupConfigured returns true if backups are made by backup container or by operator to S3-compatible object storage
+func IsBackupConfigured(jenkins *v1alpha2.Jenkins) bool {
+	return len(jenkins.Spec.Backup.ContainerName) > 0 || IsS3Backup(jenkins)
+}
+
+// IsBackupTriggerConfigured returns true if backups are triggered periodically by interval or cron schedule
+func IsBackupTriggerConfigured(jenkins *v1alpha2.Jenkins) bool {
+	return IsBackupConfigured(jenkins) && (jenkins.Spec.Backup.Interval > 0 || len(jenkins.Spec.Backup.Schedule) > 0)
+}
+
+func triggerBackup(interval time.Duration, done <-chan struct{}, k8sClient k8s.Client, logger logr.Logger, namespace, name string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if !requestBackup(k8sClient, logger, namespace, name) {
+				return // abort
+			}
 		}
-		if jenkins.Status.LastBackup == jenkins.Status.PendingBackup {
-			jenkins.Status.PendingBackup++
-			err = k8sClient.Status().Update(context.TODO(), jenkins)
-			if err != nil {
-				logger.V(log.VWarn).Info(fmt.Sprintf("backup trigger, error when updating CR: %s", err))
+	}
+}
+
+func triggerScheduledBackup(schedule *cron.Schedule, done <-chan struct{}, k8sClient k8s.Client, logger logr.Logger, namespace, name string) {
+	for {
+		next := schedule.Next(time.Now())
+		if next.IsZero() {
+			logger.V(log.VWarn).Info(fmt.Sprintf("backup trigger, spec.backup.schedule of '%s/%s' is never due", namespace, name))
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-done:
+			timer.Stop()
+			return
+		case <-timer.C:
+			if !requestBackup(k8sClient, logger, namespace, name) {
+				return // abort
 			}
 		}
 	}
 }
 
+// requestBackup bumps the pending backup number if there is no backup in progress, it returns false if the CR
+// has been deleted
+func requestBackup(k8sClient k8s.Client, logger logr.Logger, namespace, name string) bool {
+	jenkins := &v1alpha2.Jenkins{}
+	err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, jenkins)
+	if err != nil && apierrors.IsNotFound(err) {
+		triggers.stop(logger, namespace, name)
+		return false
+	} else if err != nil {
+		logger.V(log.VWarn).Info(fmt.Sprintf("backup trigger, error when fetching CR: %s", err))
+	}
+	if jenkins.Status.LastBackup == jenkins.Status.PendingBackup {
+		jenkins.Status.PendingBackup++
+		err = k8sClient.Status().Update(context.TODO(), jenkins)
+		if err != nil {
+			logger.V(log.VWarn).Info(fmt.Sprintf("backup trigger, error when updating CR: %s", err))
+		}
+	}
+	return true
+}
+
 // EnsureBackupTrigger creates or update trigger which update CR to make backup
 func (bar *BackupAndRestore) EnsureBackupTrigger() error {
 	trigger, found := triggers.get(bar.Configuration.Jenkins.Namespace, bar.Configuration.Jenkins.Name)
 
-	isBackupConfigured := len(bar.Configuration.Jenkins.Spec.Backup.ContainerName) > 0 && bar.Configuration.Jenkins.Spec.Backup.Interval > 0
+	isBackupConfigured := IsBackupTriggerConfigured(bar.Configuration.Jenkins)
 	if found && !isBackupConfigured {
 		bar.StopBackupTrigger()
 		return nil
@@ -268,13 +439,13 @@ func (bar *BackupAndRestore) EnsureBackupTrigger() error {
 
 	// configured backup has no trigger
 	if !found && isBackupConfigured {
-		bar.startBackupTrigger()
-		return nil
+		return bar.startBackupTrigger()
 	}
 
-	if found && isBackupConfigured && bar.Configuration.Jenkins.Spec.Backup.Interval != trigger.interval {
+	backup := bar.Configuration.Jenkins.Spec.Backup
+	if found && isBackupConfigured && (backup.Interval != trigger.interval || backup.Schedule != trigger.schedule) {
 		bar.StopBackupTrigger()
-		bar.startBackupTrigger()
+		return bar.startBackupTrigger()
 	}
 
 	return nil
@@ -291,12 +462,26 @@ func (bar *BackupAndRestore) IsBackupTriggerEnabled() bool {
 	return enabled
 }
 
-func (bar *BackupAndRestore) startBackupTrigger() {
+func (bar *BackupAndRestore) startBackupTrigger() error {
+	jenkins := bar.Configuration.Jenkins
+	trigger := backupTrigger{
+		interval: jenkins.Spec.Backup.Interval,
+		schedule: jenkins.Spec.Backup.Schedule,
+		done:     make(chan struct{}),
+	}
+	if len(trigger.schedule) > 0 {
+		schedule, err := cron.Parse(trigger.schedule, triggers.key(jenkins.Namespace, jenkins.Name))
+		if err != nil {
+			return errors.Wrap(err, "invalid spec.backup.schedule")
+		}
+		bar.logger.Info(fmt.Sprintf("Starting backup trigger with schedule '%s'", trigger.schedule))
+		triggers.add(jenkins.Namespace, jenkins.Name, trigger)
+		go triggerScheduledBackup(schedule, trigger.done, bar.Client, bar.logger, jenkins.Namespace, jenkins.Name)
+		return nil
+	}
+
 	bar.logger.Info("Starting backup trigger")
-	ticker := time.NewTicker(time.Duration(bar.Configuration.Jenkins.Spec.Backup.Interval) * time.Second)
-	triggers.add(bar.Configuration.Jenkins.Namespace, bar.Configuration.Jenkins.Name, backupTrigger{
-		interval: bar.Configuration.Jenkins.Spec.Backup.Interval,
-		ticker:   ticker,
-	})
-	go triggerBackup(ticker, bar.Client, bar.logger, bar.Configuration.Jenkins.Namespace, bar.Configuration.Jenkins.Name)
+	triggers.add(jenkins.Namespace, jenkins.Name, trigger)
+	go triggerBackup(time.Duration(trigger.interval)*time.Second, trigger.done, bar.Client, bar.logger, jenkins.Namespace, jenkins.Name)
+	return nil
 }
@@ -0,0 +1,140 @@
+package backuprestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBackupAndRestore_Validate_Schedule(t *testing.T) {
+	newJenkins := func(schedule string) *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{Spec: v1alpha2.JenkinsSpec{
+			Master: v1alpha2.JenkinsMaster{Containers: []v1alpha2.Container{{Name: "backup"}}},
+			Backup: v1alpha2.Backup{
+				ContainerName: "backup",
+				Action:        v1alpha2.Handler{Exec: &corev1.ExecAction{Command: []string{"backup"}}},
+				Schedule:      schedule,
+			},
+			Restore: v1alpha2.Restore{
+				ContainerName: "backup",
+				Action:        v1alpha2.Handler{Exec: &corev1.ExecAction{Command: []string{"backup"}}},
+			},
+		}}
+	}
+
+	t.Run("schedule without interval", func(t *testing.T) {
+		bar := New(configuration.Configuration{Jenkins: newJenkins("H 2 * * *")}, log.Log)
+
+		assert.Empty(t, bar.Validate())
+	})
+	t.Run("invalid schedule", func(t *testing.T) {
+		bar := New(configuration.Configuration{Jenkins: newJenkins("H 25 * * *")}, log.Log)
+
+		messages := bar.Validate()
+
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "spec.backup.schedule: invalid cron spec 'H 25 * * *'")
+	})
+	t.Run("neither interval nor schedule", func(t *testing.T) {
+		bar := New(configuration.Configuration{Jenkins: newJenkins("")}, log.Log)
+
+		assert.Equal(t, []string{"spec.backup.interval is not configured"}, bar.Validate())
+	})
+}
+
+func TestBackupAndRestore_Validate_RestoreDryRun(t *testing.T) {
+	t.Run("container without list action", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{Spec: v1alpha2.JenkinsSpec{
+			Master: v1alpha2.JenkinsMaster{Containers: []v1alpha2.Container{{Name: "backup"}}},
+			Backup: v1alpha2.Backup{
+				ContainerName: "backup",
+				Action:        v1alpha2.Handler{Exec: &corev1.ExecAction{Command: []string{"backup"}}},
+				Interval:      30,
+			},
+			Restore: v1alpha2.Restore{
+				ContainerName: "backup",
+				Action:        v1alpha2.Handler{Exec: &corev1.ExecAction{Command: []string{"restore"}}},
+				DryRun:        true,
+			},
+		}}
+		bar := New(configuration.Configuration{Jenkins: jenkins}, log.Log)
+
+		assert.Equal(t, []string{"spec.restore.dryRun requires spec.restore.listAction.exec"}, bar.Validate())
+	})
+	t.Run("restore not configured", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{Spec: v1alpha2.JenkinsSpec{Restore: v1alpha2.Restore{DryRun: true}}}
+		bar := New(configuration.Configuration{Jenkins: jenkins}, log.Log)
+
+		assert.Equal(t, []string{"spec.restore.dryRun requires spec.restore.containerName or spec.backup.s3"}, bar.Validate())
+	})
+}
+
+func TestBackupAndRestore_Backup_Status(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			Backup: v1alpha2.Backup{
+				Schedule: "H 2 * * *",
+				S3: &v1alpha2.S3Backup{
+					Endpoint:              "http://minio:9000",
+					Bucket:                "backups",
+					CredentialsSecretName: "missing",
+				},
+			},
+		},
+		Status: v1alpha2.JenkinsStatus{LastBackup: 2, PendingBackup: 3, LastBackupNumber: 2},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(jenkins).Build()
+	bar := New(configuration.Configuration{Client: k8sClient, Jenkins: jenkins}, log.Log)
+
+	err := bar.Backup(false)
+
+	require.Error(t, err)
+	actual := &v1alpha2.Jenkins{}
+	require.NoError(t, k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "jenkins"}, actual))
+	assert.Equal(t, err.Error(), actual.Status.LastBackupError)
+	assert.Equal(t, uint64(2), actual.Status.LastBackupNumber)
+	assert.Nil(t, actual.Status.LastBackupTime)
+}
+
+func TestRequestBackup(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+		Status:     v1alpha2.JenkinsStatus{LastBackup: 4, PendingBackup: 4},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(jenkins).Build()
+	key := types.NamespacedName{Namespace: "default", Name: "jenkins"}
+
+	t.Run("bumps pending backup", func(t *testing.T) {
+		assert.True(t, requestBackup(k8sClient, log.Log, "default", "jenkins"))
+
+		actual := &v1alpha2.Jenkins{}
+		require.NoError(t, k8sClient.Get(context.TODO(), key, actual))
+		assert.Equal(t, uint64(5), actual.Status.PendingBackup)
+	})
+	t.Run("backup in progress", func(t *testing.T) {
+		assert.True(t, requestBackup(k8sClient, log.Log, "default", "jenkins"))
+
+		actual := &v1alpha2.Jenkins{}
+		require.NoError(t, k8sClient.Get(context.TODO(), key, actual))
+		assert.Equal(t, uint64(5), actual.Status.PendingBackup)
+	})
+	t.Run("deleted CR", func(t *testing.T) {
+		assert.False(t, requestBackup(k8sClient, log.Log, "default", "deleted"))
+	})
+}
@@ -0,0 +1,25 @@
+package backuprestore
+
+import (
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+)
+
+const (
+	// CloneToAnnotation is the annotation of Jenkins CR with the name of the Jenkins CR to create as its clone, it's
+	// removed by the operator once the clone is created
+	CloneToAnnotation = "jenkins.io/clone-to"
+	// ClonedFromLabel is the label of Jenkins CR created as a clone with the name of the cloned Jenkins CR
+	ClonedFromLabel = "jenkins.io/cloned-from"
+)
+
+// NewClone returns Jenkins CR with the configuration of the given Jenkins CR which restores its latest backup. Both
+// share the backup storage, so the clone doesn't make backups. The clone is started in the quiet-down mode, so
+// the restored jobs aren't run until the quiet-down mode is cancelled in the cloned Jenkins. The clone isn't owned by
+// the given Jenkins CR and it isn't removed together with it.
+func NewClone(jenkins *v1alpha2.Jenkins, name string) *v1alpha2.Jenkins {
+	clone := newRestoredJenkins(jenkins, name, jenkins.Status.LastBackup)
+	clone.Labels = map[string]string{ClonedFromLabel: jenkins.Name}
+	clone.Annotations = map[string]string{resources.QuietDownAnnotation: "true"}
+	return clone
+}
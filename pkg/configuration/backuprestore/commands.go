@@ -0,0 +1,92 @@
+package backuprestore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	backupCommandAction    = "backup"
+	restoreCommandAction   = "restore"
+	getLatestCommandAction = "getLatest"
+	listCommandAction      = "list"
+)
+
+// execHandler executes the handler command with the given arguments in the Jenkins master pod container,
+// the command is cancelled when it takes longer than the handler timeout
+func (bar *BackupAndRestore) execHandler(containerName string, handler v1alpha2.Handler, args ...string) (bytes.Buffer, error) {
+	ctx := context.Background()
+	if handler.Timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, handler.Timeout.Duration)
+		defer cancel()
+	}
+	podName := resources.GetJenkinsMasterPodName(bar.Configuration.Jenkins)
+	command := append(append([]string{}, handler.Exec.Command...), args...)
+	stdout, _, err := bar.ExecContext(ctx, podName, containerName, command)
+	if ctx.Err() == context.DeadlineExceeded {
+		return stdout, &commandTimeoutError{timeout: handler.Timeout.Duration, err: err}
+	}
+	return stdout, err
+}
+
+// execAction executes the handler command like execHandler and records its outcome in the Jenkins CR status
+func (bar *BackupAndRestore) execAction(action, containerName string, handler v1alpha2.Handler, args ...string) (bytes.Buffer, error) {
+	startTime := metav1.Now()
+	stdout, err := bar.execHandler(containerName, handler, args...)
+	status := v1alpha2.BackupCommandStatus{
+		Action:    action,
+		Outcome:   backupCommandOutcome(err),
+		StartTime: startTime,
+		Duration:  metav1.Duration{Duration: time.Since(startTime.Time).Round(time.Millisecond)},
+	}
+	if err != nil {
+		status.Message = err.Error()
+	}
+
+	jenkins := bar.Configuration.Jenkins
+	setBackupCommandStatus(&jenkins.Status, status)
+	if updateErr := bar.Client.Status().Update(context.TODO(), jenkins); updateErr != nil {
+		bar.logger.V(log.VWarn).Info(fmt.Sprintf("Failed to record '%s' command outcome in status: %s", action, updateErr))
+	}
+	return stdout, err
+}
+
+// setBackupCommandStatus replaces the status of the command with the same action
+func setBackupCommandStatus(jenkinsStatus *v1alpha2.JenkinsStatus, status v1alpha2.BackupCommandStatus) {
+	for i := range jenkinsStatus.BackupCommands {
+		if jenkinsStatus.BackupCommands[i].Action == status.Action {
+			jenkinsStatus.BackupCommands[i] = status
+			return
+		}
+	}
+	jenkinsStatus.BackupCommands = append(jenkinsStatus.BackupCommands, status)
+}
+
+func backupCommandOutcome(err error) v1alpha2.BackupCommandOutcome {
+	if err == nil {
+		return v1alpha2.BackupCommandSucceeded
+	}
+	if _, timedOut := err.(*commandTimeoutError); timedOut {
+		return v1alpha2.BackupCommandTimedOut
+	}
+	return v1alpha2.BackupCommandFailed
+}
+
+// commandTimeoutError is returned when the command has been cancelled after the handler timeout
+type commandTimeoutError struct {
+	timeout time.Duration
+	err     error
+}
+
+func (e *commandTimeoutError) Error() string {
+	return fmt.Sprintf("command timed out after %s: %s", e.timeout, e.err)
+}
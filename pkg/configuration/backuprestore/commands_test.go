@@ -0,0 +1,40 @@
+package backuprestore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetBackupCommandStatus(t *testing.T) {
+	status := &v1alpha2.JenkinsStatus{}
+
+	setBackupCommandStatus(status, v1alpha2.BackupCommandStatus{Action: backupCommandAction, Outcome: v1alpha2.BackupCommandFailed})
+	setBackupCommandStatus(status, v1alpha2.BackupCommandStatus{Action: getLatestCommandAction, Outcome: v1alpha2.BackupCommandSucceeded})
+	setBackupCommandStatus(status, v1alpha2.BackupCommandStatus{Action: backupCommandAction, Outcome: v1alpha2.BackupCommandTimedOut})
+
+	assert.Equal(t, []v1alpha2.BackupCommandStatus{
+		{Action: backupCommandAction, Outcome: v1alpha2.BackupCommandTimedOut},
+		{Action: getLatestCommandAction, Outcome: v1alpha2.BackupCommandSucceeded},
+	}, status.BackupCommands)
+}
+
+func TestBackupCommandOutcome(t *testing.T) {
+	t.Run("succeeded", func(t *testing.T) {
+		assert.Equal(t, v1alpha2.BackupCommandSucceeded, backupCommandOutcome(nil))
+	})
+	t.Run("failed", func(t *testing.T) {
+		assert.Equal(t, v1alpha2.BackupCommandFailed, backupCommandOutcome(errors.New("exit code 1")))
+	})
+	t.Run("timed out", func(t *testing.T) {
+		err := &commandTimeoutError{timeout: 5 * time.Minute, err: context.DeadlineExceeded}
+
+		assert.Equal(t, v1alpha2.BackupCommandTimedOut, backupCommandOutcome(err))
+		assert.EqualError(t, err, "command timed out after 5m0s: context deadline exceeded")
+	})
+}
@@ -0,0 +1,117 @@
+package backuprestore
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// destinationExec executes backup handler command with the given arguments in the given container
+type destinationExec func(containerName string, handler v1alpha2.Handler, args ...string) error
+
+// backupToDestinations executes backup actions of additional destinations and returns their updated statuses,
+// destinations which already have the given backup number are skipped so a failed run can be retried
+func backupToDestinations(backup v1alpha2.Backup, statuses []v1alpha2.BackupDestinationStatus, backupNumber uint64,
+	exec destinationExec) ([]v1alpha2.BackupDestinationStatus, error) {
+	current := map[string]v1alpha2.BackupDestinationStatus{}
+	for _, status := range statuses {
+		current[status.Name] = status
+	}
+
+	newStatuses := make([]v1alpha2.BackupDestinationStatus, len(backup.Destinations))
+	run := func(i int, destination v1alpha2.BackupDestination) {
+		status := current[destination.Name]
+		status.Name = destination.Name
+		if status.LastBackup == backupNumber {
+			newStatuses[i] = status
+			return
+		}
+
+		containerName := destination.ContainerName
+		if len(containerName) == 0 {
+			containerName = backup.ContainerName
+		}
+		if err := exec(containerName, destination.Action, fmt.Sprintf("%d", backupNumber)); err != nil {
+			status.Error = err.Error()
+		} else {
+			now := metav1.Now()
+			status.LastBackup = backupNumber
+			status.LastBackupTime = &now
+			status.Error = ""
+		}
+		newStatuses[i] = status
+	}
+
+	if backup.ParallelDestinations {
+		var wg sync.WaitGroup
+		for i, destination := range backup.Destinations {
+			wg.Add(1)
+			go func(i int, destination v1alpha2.BackupDestination) {
+				defer wg.Done()
+				run(i, destination)
+			}(i, destination)
+		}
+		wg.Wait()
+	} else {
+		for i, destination := range backup.Destinations {
+			run(i, destination)
+		}
+	}
+
+	var failed []string
+	for _, status := range newStatuses {
+		if len(status.Error) > 0 {
+			failed = append(failed, fmt.Sprintf("'%s': %s", status.Name, status.Error))
+		}
+	}
+	if len(failed) > 0 {
+		return newStatuses, errors.Errorf("backup '%d' failed for destinations %s", backupNumber, strings.Join(failed, ", "))
+	}
+	return newStatuses, nil
+}
+
+// hasFailedBackupDestinations returns true if the latest backup hasn't been copied to some destination because it
+// has failed, the destinations added after the latest backup are skipped until the next one
+func hasFailedBackupDestinations(jenkins *v1alpha2.Jenkins) bool {
+	if jenkins.Status.LastBackup == 0 {
+		return false
+	}
+	for _, destination := range jenkins.Spec.Backup.Destinations {
+		for _, status := range jenkins.Status.BackupDestinations {
+			if status.Name == destination.Name && len(status.Error) > 0 && status.LastBackup != jenkins.Status.LastBackup {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func validateBackupDestinations(backup v1alpha2.Backup, allContainers map[string]v1alpha2.Container) []string {
+	var messages []string
+	names := map[string]bool{}
+	for _, destination := range backup.Destinations {
+		if len(destination.Name) == 0 {
+			messages = append(messages, "spec.backup.destinations name is not configured")
+		} else if names[destination.Name] {
+			messages = append(messages, fmt.Sprintf("spec.backup.destinations name '%s' is not unique", destination.Name))
+		}
+		names[destination.Name] = true
+
+		if len(destination.ContainerName) > 0 {
+			if _, found := allContainers[destination.ContainerName]; !found {
+				messages = append(messages, fmt.Sprintf("backup destination '%s' container '%s' not found in CR spec.master.containers", destination.Name, destination.ContainerName))
+			}
+		} else if backup.S3 != nil {
+			messages = append(messages, fmt.Sprintf("spec.backup.destinations '%s' containerName is required when spec.backup.s3 is set", destination.Name))
+		}
+		if destination.Action.Exec == nil {
+			messages = append(messages, fmt.Sprintf("spec.backup.destinations '%s' action.exec is not configured", destination.Name))
+		}
+	}
+	return messages
+}
@@ -0,0 +1,154 @@
+package backuprestore
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func backupDestination(name, containerName string) v1alpha2.BackupDestination {
+	return v1alpha2.BackupDestination{
+		Name:          name,
+		ContainerName: containerName,
+		Action:        v1alpha2.Handler{Exec: &corev1.ExecAction{Command: []string{"/home/user/bin/backup-" + name + ".sh"}}},
+	}
+}
+
+func TestBackupToDestinations(t *testing.T) {
+	backup := v1alpha2.Backup{
+		ContainerName: "backup",
+		Destinations:  []v1alpha2.BackupDestination{backupDestination("pvc", ""), backupDestination("s3", "backup-s3")},
+	}
+
+	t.Run("sequential", func(t *testing.T) {
+		var executed [][]string
+		statuses, err := backupToDestinations(backup, nil, 3, func(containerName string, handler v1alpha2.Handler, args ...string) error {
+			executed = append(executed, append(append([]string{containerName}, handler.Exec.Command...), args...))
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"backup", "/home/user/bin/backup-pvc.sh", "3"},
+			{"backup-s3", "/home/user/bin/backup-s3.sh", "3"},
+		}, executed)
+		if assert.Len(t, statuses, 2) {
+			assert.Equal(t, "pvc", statuses[0].Name)
+			assert.Equal(t, uint64(3), statuses[0].LastBackup)
+			assert.NotNil(t, statuses[0].LastBackupTime)
+			assert.Equal(t, "s3", statuses[1].Name)
+			assert.Equal(t, uint64(3), statuses[1].LastBackup)
+		}
+	})
+	t.Run("parallel", func(t *testing.T) {
+		parallelBackup := backup
+		parallelBackup.ParallelDestinations = true
+		var mutex sync.Mutex
+		executed := map[string]bool{}
+
+		statuses, err := backupToDestinations(parallelBackup, nil, 3, func(containerName string, handler v1alpha2.Handler, args ...string) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			executed[containerName] = true
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]bool{"backup": true, "backup-s3": true}, executed)
+		assert.Len(t, statuses, 2)
+	})
+	t.Run("failed destination is reported and retried", func(t *testing.T) {
+		statuses, err := backupToDestinations(backup, nil, 3, func(containerName string, handler v1alpha2.Handler, args ...string) error {
+			if containerName == "backup-s3" {
+				return errors.New("access denied")
+			}
+			return nil
+		})
+
+		assert.EqualError(t, err, "backup '3' failed for destinations 's3': access denied")
+		assert.Equal(t, uint64(3), statuses[0].LastBackup)
+		assert.Equal(t, uint64(0), statuses[1].LastBackup)
+		assert.Equal(t, "access denied", statuses[1].Error)
+
+		var executed []string
+		statuses, err = backupToDestinations(backup, statuses, 3, func(containerName string, handler v1alpha2.Handler, args ...string) error {
+			executed = append(executed, containerName)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"backup-s3"}, executed)
+		assert.Equal(t, uint64(3), statuses[1].LastBackup)
+		assert.Empty(t, statuses[1].Error)
+	})
+}
+
+func TestHasFailedBackupDestinations(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{
+		Spec: v1alpha2.JenkinsSpec{Backup: v1alpha2.Backup{
+			ContainerName: "backup",
+			Destinations:  []v1alpha2.BackupDestination{backupDestination("pvc", ""), backupDestination("s3", "backup-s3")},
+		}},
+		Status: v1alpha2.JenkinsStatus{LastBackup: 3, PendingBackup: 3},
+	}
+
+	t.Run("all destinations have the latest backup", func(t *testing.T) {
+		jenkins.Status.BackupDestinations = []v1alpha2.BackupDestinationStatus{{Name: "pvc", LastBackup: 3}, {Name: "s3", LastBackup: 3}}
+
+		assert.False(t, hasFailedBackupDestinations(jenkins))
+	})
+	t.Run("failed destination", func(t *testing.T) {
+		jenkins.Status.BackupDestinations = []v1alpha2.BackupDestinationStatus{{Name: "pvc", LastBackup: 3}, {Name: "s3", LastBackup: 2, Error: "access denied"}}
+
+		assert.True(t, hasFailedBackupDestinations(jenkins))
+	})
+	t.Run("failed destination removed from spec", func(t *testing.T) {
+		jenkins.Status.BackupDestinations = []v1alpha2.BackupDestinationStatus{{Name: "pvc", LastBackup: 3}, {Name: "nfs", Error: "access denied"}}
+
+		assert.False(t, hasFailedBackupDestinations(jenkins))
+	})
+	t.Run("new destination", func(t *testing.T) {
+		jenkins.Status.BackupDestinations = []v1alpha2.BackupDestinationStatus{{Name: "pvc", LastBackup: 3}}
+
+		assert.False(t, hasFailedBackupDestinations(jenkins))
+	})
+}
+
+func TestValidateBackupDestinations(t *testing.T) {
+	allContainers := map[string]v1alpha2.Container{"backup": {Name: "backup"}}
+	t.Run("valid", func(t *testing.T) {
+		backup := v1alpha2.Backup{Destinations: []v1alpha2.BackupDestination{backupDestination("pvc", ""), backupDestination("s3", "backup")}}
+
+		assert.Empty(t, validateBackupDestinations(backup, allContainers))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		noAction := backupDestination("gcs", "")
+		noAction.Action.Exec = nil
+		backup := v1alpha2.Backup{Destinations: []v1alpha2.BackupDestination{
+			backupDestination("s3", ""),
+			backupDestination("s3", "missing"),
+			noAction,
+		}}
+
+		assert.Equal(t, []string{
+			"spec.backup.destinations name 's3' is not unique",
+			"backup destination 's3' container 'missing' not found in CR spec.master.containers",
+			"spec.backup.destinations 'gcs' action.exec is not configured",
+		}, validateBackupDestinations(backup, allContainers))
+	})
+	t.Run("S3 backup", func(t *testing.T) {
+		backup := v1alpha2.Backup{
+			S3:           &v1alpha2.S3Backup{Endpoint: "http://minio:9000", Bucket: "backups", CredentialsSecretName: "minio"},
+			Destinations: []v1alpha2.BackupDestination{backupDestination("pvc", ""), backupDestination("gcs", "backup")},
+		}
+
+		assert.Equal(t, []string{
+			"spec.backup.destinations 'pvc' containerName is required when spec.backup.s3 is set",
+		}, validateBackupDestinations(backup, allContainers))
+	})
+}
@@ -0,0 +1,205 @@
+package backuprestore
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RestoreDryRun lists the jobs and configuration files of the backup selected by spec.restore when spec.restore.dryRun
+// is set, live Jenkins home isn't changed. The result is reported in status.restoreDryRun and by notification.
+func (bar *BackupAndRestore) RestoreDryRun() error {
+	jenkins := bar.Configuration.Jenkins
+	if !jenkins.Spec.Restore.DryRun {
+		if jenkins.Status.RestoreDryRun != nil {
+			jenkins.Status.RestoreDryRun = nil
+			return bar.Client.Status().Update(context.TODO(), jenkins)
+		}
+		return nil
+	}
+	if !IsS3Backup(jenkins) && (len(jenkins.Spec.Restore.ContainerName) == 0 || jenkins.Spec.Restore.ListAction.Exec == nil) {
+		bar.logger.V(log.VDebug).Info("Skipping restore dry run, spec.restore.listAction not configured")
+		return nil
+	}
+
+	status := &v1alpha2.RestoreDryRunStatus{Time: metav1.Now()}
+	backupNumber, err := bar.getRestoreDryRunBackup()
+	if err == nil && backupNumber == 0 {
+		err = errors.New("there is no backup to restore")
+	}
+	if err == nil {
+		status.BackupNumber = backupNumber
+		if last := jenkins.Status.RestoreDryRun; last != nil && last.BackupNumber == backupNumber && len(last.Error) == 0 {
+			return nil
+		}
+		bar.logger.Info(fmt.Sprintf("Listing backup '%d' for restore dry run", backupNumber))
+		var paths []string
+		if paths, err = bar.listBackup(backupNumber); err == nil {
+			status.Jobs, status.ConfigurationFiles = parseBackupPaths(paths)
+		}
+	}
+
+	previous := jenkins.Status.RestoreDryRun
+	if err != nil {
+		status.Error = err.Error()
+		bar.logger.V(log.VWarn).Info(fmt.Sprintf("Restore dry run failed: %s", err))
+		if previous != nil && previous.Error == status.Error {
+			return nil
+		}
+	}
+	jenkins.Status.RestoreDryRun = status
+	if err := bar.Client.Status().Update(context.TODO(), jenkins); err != nil {
+		return errors.WithStack(err)
+	}
+	bar.notifyRestoreDryRun(status)
+	return nil
+}
+
+// getRestoreDryRunBackup returns the backup number which would be restored or 0 if there is no backup
+func (bar *BackupAndRestore) getRestoreDryRunBackup() (uint64, error) {
+	jenkins := bar.Configuration.Jenkins
+	switch {
+	case jenkins.Spec.Restore.RecoveryOnce != 0:
+		return jenkins.Spec.Restore.RecoveryOnce, nil
+	case jenkins.Spec.Restore.BackupNumber != 0:
+		return jenkins.Spec.Restore.BackupNumber, nil
+	case IsS3Backup(jenkins):
+		return bar.getLatestS3Backup()
+	case jenkins.Spec.Restore.GetLatestAction.Exec != nil:
+		return bar.getLatestContainerBackup()
+	default:
+		return jenkins.Status.LastBackup, nil
+	}
+}
+
+// listBackup returns the paths relative to Jenkins home of the files in the given backup
+func (bar *BackupAndRestore) listBackup(backupNumber uint64) ([]string, error) {
+	jenkins := bar.Configuration.Jenkins
+	if IsS3Backup(jenkins) {
+		config := jenkins.Spec.Backup.S3
+		client, err := bar.newS3Client(*config)
+		if err != nil {
+			return nil, err
+		}
+		archive, err := client.getObject(context.TODO(), s3BackupKey(config.Prefix, backupNumber))
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = archive.Close() }()
+		return listTarGz(archive)
+	}
+
+	stdout, err := bar.execAction(listCommandAction, jenkins.Spec.Restore.ContainerName, jenkins.Spec.Restore.ListAction, fmt.Sprintf("%d", backupNumber))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list backup")
+	}
+	var paths []string
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		paths = append(paths, scanner.Text())
+	}
+	return paths, errors.WithStack(scanner.Err())
+}
+
+func (bar *BackupAndRestore) notifyRestoreDryRun(status *v1alpha2.RestoreDryRunStatus) {
+	if bar.Notifications == nil {
+		return
+	}
+	level := v1alpha2.NotificationLevelInfo
+	short := []string{fmt.Sprintf("Restore dry run of backup '%d' found %d jobs and %d configuration files",
+		status.BackupNumber, len(status.Jobs), len(status.ConfigurationFiles))}
+	verbose := []string{short[0]}
+	if len(status.Error) > 0 {
+		level = v1alpha2.NotificationLevelWarning
+		short = []string{"Restore dry run failed"}
+		verbose = []string{fmt.Sprintf("Restore dry run failed: %s", status.Error)}
+	} else {
+		for _, job := range status.Jobs {
+			verbose = append(verbose, fmt.Sprintf("Job '%s'", job))
+		}
+		for _, file := range status.ConfigurationFiles {
+			verbose = append(verbose, fmt.Sprintf("Configuration file '%s'", file))
+		}
+	}
+	*bar.Notifications <- event.Event{
+		Jenkins: *bar.Configuration.Jenkins,
+		Phase:   event.PhaseUser,
+		Level:   level,
+		Reason:  reason.NewRestoreDryRun(reason.OperatorSource, short, verbose...),
+	}
+}
+
+// listTarGz returns the paths of the files in the gzipped tar archive
+func listTarGz(archive io.Reader) ([]string, error) {
+	gzipReader, err := gzip.NewReader(archive)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid backup archive")
+	}
+	defer func() { _ = gzipReader.Close() }()
+
+	var paths []string
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return paths, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid backup archive")
+		}
+		if header.Typeflag == tar.TypeReg {
+			paths = append(paths, header.Name)
+		}
+	}
+}
+
+// parseBackupPaths returns the full names of the jobs and the configuration files in the root of Jenkins home found
+// in the paths of the backup files, jobs in folders are nested in jobs/<folder>/jobs/<job>
+func parseBackupPaths(paths []string) (jobs []string, configurationFiles []string) {
+	for _, backupPath := range paths {
+		backupPath = strings.TrimPrefix(path.Clean("/"+strings.TrimSpace(backupPath)), "/")
+		parts := strings.Split(backupPath, "/")
+		if len(parts) == 1 {
+			switch path.Ext(backupPath) {
+			case ".xml", ".yaml", ".yml":
+				configurationFiles = append(configurationFiles, backupPath)
+			}
+			continue
+		}
+		if job, ok := jobName(parts); ok {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Strings(jobs)
+	sort.Strings(configurationFiles)
+	return jobs, configurationFiles
+}
+
+// jobName returns the full name of the job if the path parts are jobs/<name>(/jobs/<name>)*/config.xml
+func jobName(parts []string) (string, bool) {
+	if len(parts)%2 == 0 || parts[len(parts)-1] != "config.xml" {
+		return "", false
+	}
+	var names []string
+	for i := 0; i < len(parts)-1; i += 2 {
+		if parts[i] != "jobs" || len(parts[i+1]) == 0 {
+			return "", false
+		}
+		names = append(names, parts[i+1])
+	}
+	return strings.Join(names, "/"), true
+}
@@ -0,0 +1,139 @@
+package backuprestore
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseBackupPaths(t *testing.T) {
+	jobs, configurationFiles := parseBackupPaths([]string{
+		"./",
+		"./config.xml",
+		"./jenkins.yaml",
+		"./secret.key",
+		"./jobs/build/config.xml",
+		"./jobs/build/builds/1/build.xml",
+		"jobs/team/config.xml",
+		"jobs/team/jobs/deploy/config.xml",
+		"jobs/team/builds/config.xml",
+		"plugins/git/config.xml",
+		"users/admin/config.xml",
+	})
+
+	assert.Equal(t, []string{"build", "team", "team/deploy"}, jobs)
+	assert.Equal(t, []string{"config.xml", "jenkins.yaml"}, configurationFiles)
+}
+
+func TestListTarGz(t *testing.T) {
+	archive := newTarGz(t, "./", "./config.xml", "./jobs/build/config.xml")
+
+	paths, err := listTarGz(bytes.NewReader(archive))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"./config.xml", "./jobs/build/config.xml"}, paths)
+
+	_, err = listTarGz(bytes.NewReader([]byte("not an archive")))
+	assert.Error(t, err)
+}
+
+func TestBackupAndRestore_RestoreDryRun_S3(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	s3 := newFakeS3("backups", "jenkins/3.tar.gz")
+	s3.objects["jenkins/2.tar.gz"] = newTarGz(t, "./", "./config.xml", "./jobs/build/config.xml")
+	server := httptest.NewServer(s3)
+	defer server.Close()
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			Backup: v1alpha2.Backup{
+				Interval: 30,
+				S3: &v1alpha2.S3Backup{
+					Endpoint:              server.URL,
+					Bucket:                "backups",
+					Prefix:                "jenkins",
+					CredentialsSecretName: "s3",
+				},
+			},
+			Restore: v1alpha2.Restore{BackupNumber: 2, DryRun: true},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3", Namespace: "default"},
+		Data:       map[string][]byte{S3AccessKeyIDSecretKey: []byte("access"), S3SecretAccessKeySecretKey: []byte("secret")},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(jenkins, secret).Build()
+	notifications := make(chan event.Event, 10)
+	bar := New(configuration.Configuration{Client: fakeClient, Jenkins: jenkins, Notifications: &notifications}, log.Log)
+
+	t.Run("selected backup", func(t *testing.T) {
+		require.NoError(t, bar.RestoreDryRun())
+
+		actual := &v1alpha2.Jenkins{}
+		require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "jenkins"}, actual))
+		require.NotNil(t, actual.Status.RestoreDryRun)
+		assert.Equal(t, uint64(2), actual.Status.RestoreDryRun.BackupNumber)
+		assert.Equal(t, []string{"build"}, actual.Status.RestoreDryRun.Jobs)
+		assert.Equal(t, []string{"config.xml"}, actual.Status.RestoreDryRun.ConfigurationFiles)
+		assert.Empty(t, actual.Status.RestoreDryRun.Error)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, v1alpha2.NotificationLevelInfo, (<-notifications).Level)
+	})
+	t.Run("already listed", func(t *testing.T) {
+		require.NoError(t, bar.RestoreDryRun())
+
+		assert.Len(t, notifications, 0)
+	})
+	t.Run("invalid latest backup", func(t *testing.T) {
+		jenkins.Spec.Restore.BackupNumber = 0
+
+		require.NoError(t, bar.RestoreDryRun())
+		require.NoError(t, bar.RestoreDryRun())
+
+		assert.Equal(t, uint64(3), jenkins.Status.RestoreDryRun.BackupNumber)
+		assert.Contains(t, jenkins.Status.RestoreDryRun.Error, "invalid backup archive")
+		require.Len(t, notifications, 1)
+		assert.Equal(t, v1alpha2.NotificationLevelWarning, (<-notifications).Level)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		jenkins.Spec.Restore.DryRun = false
+
+		require.NoError(t, bar.RestoreDryRun())
+
+		assert.Nil(t, jenkins.Status.RestoreDryRun)
+	})
+}
+
+func newTarGz(t *testing.T, paths ...string) []byte {
+	buffer := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, path := range paths {
+		header := &tar.Header{Name: path, Mode: 0644, Typeflag: tar.TypeReg}
+		if path[len(path)-1] == '/' {
+			header.Typeflag = tar.TypeDir
+			header.Mode = 0755
+		}
+		require.NoError(t, tarWriter.WriteHeader(header))
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return buffer.Bytes()
+}
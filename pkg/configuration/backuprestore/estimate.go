@@ -0,0 +1,85 @@
+package backuprestore
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultBackupThroughputBytesPerSecond is the backup throughput used to estimate backup duration when it's not
+// configured
+const DefaultBackupThroughputBytesPerSecond int64 = 50 * 1024 * 1024
+
+// IsBackupDryRun returns true if backups are only estimated
+func (bar *BackupAndRestore) IsBackupDryRun() bool {
+	return bar.Configuration.Jenkins.Spec.Backup.DryRun != nil
+}
+
+// EstimateBackup estimates Jenkins backup size and duration without transferring any data and reports
+// the estimate in status, the estimate is refreshed every backup interval. Jenkins home of external Jenkins can't be
+// measured, so it's never estimated.
+func (bar *BackupAndRestore) EstimateBackup() error {
+	jenkins := bar.Configuration.Jenkins
+	if !bar.IsBackupDryRun() || jenkins.Spec.ExternalJenkins != nil {
+		return nil
+	}
+	interval := time.Duration(jenkins.Spec.Backup.Interval) * time.Second
+	if jenkins.Status.BackupEstimate != nil && time.Since(jenkins.Status.BackupEstimate.EstimatedTime.Time) < interval {
+		return nil
+	}
+
+	podName := resources.GetJenkinsMasterPodName(jenkins)
+	command := backupSizeCommand(resources.GetJenkinsHomePath(jenkins), jenkins.Spec.Backup.DryRun.Excludes)
+	stdout, stderr, err := bar.Exec(podName, resources.JenkinsMasterContainerName, command)
+	if err != nil {
+		return errors.Wrapf(err, "failed to estimate backup size, stderr '%s'", stderr.String())
+	}
+	sizeBytes, err := parseBackupSize(stdout.String())
+	if err != nil {
+		return err
+	}
+
+	throughput := jenkins.Spec.Backup.DryRun.ThroughputBytesPerSecond
+	if throughput <= 0 {
+		throughput = DefaultBackupThroughputBytesPerSecond
+	}
+	jenkins.Status.BackupEstimate = &v1alpha2.BackupEstimate{
+		SizeBytes:       sizeBytes,
+		DurationSeconds: (sizeBytes + throughput - 1) / throughput,
+		EstimatedTime:   metav1.Now(),
+	}
+	bar.logger.V(log.VDebug).Info(fmt.Sprintf("Estimated backup size %d bytes and duration %ds",
+		jenkins.Status.BackupEstimate.SizeBytes, jenkins.Status.BackupEstimate.DurationSeconds))
+	return errors.WithStack(bar.Client.Status().Update(context.TODO(), jenkins))
+}
+
+func backupSizeCommand(jenkinsHomePath string, excludes []string) []string {
+	command := []string{"du", "-sk"}
+	for _, exclude := range excludes {
+		command = append(command, "--exclude="+filepath.Join(jenkinsHomePath, exclude))
+	}
+	return append(command, jenkinsHomePath)
+}
+
+// parseBackupSize parses output of 'du -sk' command and returns size in bytes
+func parseBackupSize(output string) (int64, error) {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return 0, errors.Errorf("unexpected du output '%s'", output)
+	}
+	kilobytes, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unexpected du output '%s'", output)
+	}
+	return kilobytes * 1024, nil
+}
@@ -0,0 +1,48 @@
+package backuprestore
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupSizeCommand(t *testing.T) {
+	command := backupSizeCommand("/var/lib/jenkins", []string{"workspace", "caches/"})
+
+	assert.Equal(t, []string{"du", "-sk", "--exclude=/var/lib/jenkins/workspace", "--exclude=/var/lib/jenkins/caches", "/var/lib/jenkins"}, command)
+}
+
+func TestParseBackupSize(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		size, err := parseBackupSize("1536\t/var/lib/jenkins\n")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1536*1024), size)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, output := range []string{"", "du: cannot access '/var/lib/jenkins'"} {
+			_, err := parseBackupSize(output)
+
+			assert.Error(t, err, output)
+		}
+	})
+}
+
+func TestEstimateBackup(t *testing.T) {
+	t.Run("external Jenkins is skipped", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{Spec: v1alpha2.JenkinsSpec{
+			ExternalJenkins: &v1alpha2.ExternalJenkins{URL: "https://jenkins.example.com"},
+			Backup:          v1alpha2.Backup{Interval: 30, DryRun: &v1alpha2.BackupDryRun{}},
+		}}
+		bar := New(configuration.Configuration{Jenkins: jenkins}, log.Log)
+
+		err := bar.EstimateBackup()
+
+		assert.NoError(t, err)
+		assert.Nil(t, jenkins.Status.BackupEstimate)
+	})
+}
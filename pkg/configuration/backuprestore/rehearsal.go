@@ -0,0 +1,250 @@
+package backuprestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/metrics"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// RestoreRehearsalLabel is the label of temporary Jenkins CR with the name of Jenkins CR which backup is verified
+	RestoreRehearsalLabel = "jenkins.io/restore-rehearsal-of"
+
+	// DefaultRestoreRehearsalInterval is the default interval of restore rehearsals in seconds
+	DefaultRestoreRehearsalInterval uint64 = 24 * 60 * 60
+	// DefaultRestoreRehearsalTimeout is the default timeout of restore rehearsal in seconds
+	DefaultRestoreRehearsalTimeout uint64 = 30 * 60
+)
+
+// RestoreRehearsal periodically verifies backups of Jenkins CRs with spec.restore.rehearsal set, the latest backup
+// is restored into a temporary Jenkins CR, the restored Jenkins is checked if it's up and lists jobs and then
+// the temporary CR is removed. The result is reported in CR status and metrics.
+type RestoreRehearsal struct {
+	Client                       k8s.Client
+	ClientSet                    kubernetes.Clientset
+	Config                       rest.Config
+	JenkinsAPIConnectionSettings jenkinsclient.JenkinsAPIConnectionSettings
+	KubernetesClusterDomain      string
+	Interval                     time.Duration
+}
+
+// Start checks restore rehearsals every interval until the context is done.
+func (r *RestoreRehearsal) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.checkAll(ctx)
+		}
+	}
+}
+
+func (r *RestoreRehearsal) checkAll(ctx context.Context) {
+	jenkinsList := &v1alpha2.JenkinsList{}
+	if err := r.Client.List(ctx, jenkinsList); err != nil {
+		log.Log.V(log.VWarn).Info(fmt.Sprintf("Failed to list Jenkins CRs for restore rehearsal: %s", err))
+		return
+	}
+
+	for i := range jenkinsList.Items {
+		jenkins := &jenkinsList.Items[i]
+		// external Jenkins has no master pod the backups could be restored from
+		if jenkins.Spec.Restore.Rehearsal == nil || IsRestoreRehearsal(jenkins) || jenkins.Spec.ExternalJenkins != nil {
+			continue
+		}
+		if err := r.check(ctx, jenkins); err != nil {
+			log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace).V(log.VWarn).Info(fmt.Sprintf("Failed to check restore rehearsal: %s", err))
+		}
+	}
+}
+
+func (r *RestoreRehearsal) check(ctx context.Context, jenkins *v1alpha2.Jenkins) error {
+	status := jenkins.Status.RestoreRehearsal
+	if status == nil || status.CompletionTime != nil {
+		if !isRestoreRehearsalDue(jenkins, time.Now()) {
+			return nil
+		}
+		return r.start(ctx, jenkins)
+	}
+
+	rehearsal := &v1alpha2.Jenkins{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: jenkins.Namespace, Name: GetRestoreRehearsalName(jenkins)}, rehearsal)
+	if err != nil && apierrors.IsNotFound(err) {
+		return r.complete(ctx, jenkins, false, "temporary Jenkins CR not found")
+	} else if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if rehearsal.Status.UserConfigurationCompletedTime != nil && rehearsal.Status.RestoredBackup == status.BackupNumber {
+		if err := r.checkHealth(rehearsal); err != nil {
+			return r.complete(ctx, jenkins, false, fmt.Sprintf("restored Jenkins is not healthy: %s", err))
+		}
+		return r.complete(ctx, jenkins, true, fmt.Sprintf("backup '%d' restored successfully", status.BackupNumber))
+	}
+
+	timeout := jenkins.Spec.Restore.Rehearsal.Timeout
+	if timeout == 0 {
+		timeout = DefaultRestoreRehearsalTimeout
+	}
+	if time.Since(status.StartTime.Time) > time.Duration(timeout)*time.Second {
+		return r.complete(ctx, jenkins, false, fmt.Sprintf("backup '%d' not restored in %d seconds", status.BackupNumber, timeout))
+	}
+	return nil
+}
+
+func (r *RestoreRehearsal) start(ctx context.Context, jenkins *v1alpha2.Jenkins) error {
+	backupNumber := jenkins.Status.LastBackup
+	log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace).Info(fmt.Sprintf("Starting restore rehearsal of backup '%d'", backupNumber))
+
+	err := r.Client.Create(ctx, newRestoreRehearsalJenkins(jenkins, backupNumber))
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.WithStack(err)
+	}
+
+	jenkins.Status.RestoreRehearsal = &v1alpha2.RestoreRehearsalStatus{
+		BackupNumber: backupNumber,
+		StartTime:    metav1.Now(),
+	}
+	return errors.WithStack(r.Client.Status().Update(ctx, jenkins))
+}
+
+func (r *RestoreRehearsal) complete(ctx context.Context, jenkins *v1alpha2.Jenkins, succeeded bool, message string) error {
+	logger := log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace)
+	if succeeded {
+		logger.Info(fmt.Sprintf("Restore rehearsal completed, %s", message))
+	} else {
+		logger.V(log.VWarn).Info(fmt.Sprintf("Restore rehearsal failed, %s", message))
+	}
+
+	rehearsal := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Namespace: jenkins.Namespace, Name: GetRestoreRehearsalName(jenkins)}}
+	err := r.Client.Delete(ctx, rehearsal)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.WithStack(err)
+	}
+
+	now := metav1.Now()
+	jenkins.Status.RestoreRehearsal.Succeeded = succeeded
+	jenkins.Status.RestoreRehearsal.Message = message
+	jenkins.Status.RestoreRehearsal.CompletionTime = &now
+	metrics.SetRestoreRehearsalMetrics(jenkins, succeeded, now.Time)
+	return errors.WithStack(r.Client.Status().Update(ctx, jenkins))
+}
+
+func (r *RestoreRehearsal) checkHealth(rehearsal *v1alpha2.Jenkins) error {
+	config := configuration.Configuration{
+		Client:                       r.Client,
+		ClientSet:                    r.ClientSet,
+		Config:                       &r.Config,
+		Jenkins:                      rehearsal,
+		JenkinsAPIConnectionSettings: r.JenkinsAPIConnectionSettings,
+		KubernetesClusterDomain:      r.KubernetesClusterDomain,
+	}
+	jenkinsClient, err := config.GetJenkinsClient()
+	if err != nil {
+		return err
+	}
+	_, err = jenkinsClient.GetAllJobNames()
+	return errors.WithStack(err)
+}
+
+// IsRestoreRehearsal returns true if the Jenkins CR is a temporary CR created by restore rehearsal
+func IsRestoreRehearsal(jenkins *v1alpha2.Jenkins) bool {
+	_, ok := jenkins.Labels[RestoreRehearsalLabel]
+	return ok
+}
+
+// GetRestoreRehearsalName returns name of temporary Jenkins CR used by restore rehearsal
+func GetRestoreRehearsalName(jenkins *v1alpha2.Jenkins) string {
+	return fmt.Sprintf("%s-restore-rehearsal", jenkins.Name)
+}
+
+func isRestoreRehearsalDue(jenkins *v1alpha2.Jenkins, now time.Time) bool {
+	if jenkins.Status.LastBackup == 0 || jenkins.Status.UserConfigurationCompletedTime == nil {
+		return false
+	}
+	status := jenkins.Status.RestoreRehearsal
+	if status == nil {
+		return true
+	}
+	interval := jenkins.Spec.Restore.Rehearsal.Interval
+	if interval == 0 {
+		interval = DefaultRestoreRehearsalInterval
+	}
+	return now.Sub(status.StartTime.Time) >= time.Duration(interval)*time.Second
+}
+
+// newRestoreRehearsalJenkins returns temporary Jenkins CR which restores the given backup, it doesn't make backups,
+// doesn't run seed jobs nor the restored jobs and doesn't expose Jenkins outside of the cluster
+func newRestoreRehearsalJenkins(jenkins *v1alpha2.Jenkins, backupNumber uint64) *v1alpha2.Jenkins {
+	rehearsal := newRestoredJenkins(jenkins, GetRestoreRehearsalName(jenkins), backupNumber)
+	rehearsal.Labels = map[string]string{RestoreRehearsalLabel: jenkins.Name}
+	// the restored jobs would be started by SCM triggers and cron against the same repositories and deployment
+	// targets as the jobs of the given Jenkins CR
+	rehearsal.Annotations = map[string]string{resources.QuietDownAnnotation: "true"}
+	rehearsal.OwnerReferences = []metav1.OwnerReference{
+		{
+			BlockOwnerDeletion: &[]bool{true}[0],
+			Kind:               v1alpha2.Kind,
+			Name:               jenkins.Name,
+			APIVersion:         v1alpha2.GroupVersion.String(),
+			UID:                jenkins.UID,
+		},
+	}
+
+	rehearsal.Spec.SeedJobs = nil
+	for _, service := range []*v1alpha2.Service{&rehearsal.Spec.Service, &rehearsal.Spec.SlaveService} {
+		service.Type = corev1.ServiceTypeClusterIP
+	}
+	return rehearsal
+}
+
+// newRestoredJenkins returns Jenkins CR with the spec of the given Jenkins CR which restores the given backup,
+// it doesn't make backups into the storage of the given Jenkins CR and doesn't take over its resources
+func newRestoredJenkins(jenkins *v1alpha2.Jenkins, name string, backupNumber uint64) *v1alpha2.Jenkins {
+	restored := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: jenkins.Namespace,
+		},
+		Spec: *jenkins.Spec.DeepCopy(),
+	}
+
+	restored.Spec.Restore.Rehearsal = nil
+	restored.Spec.Restore.RecoveryOnce = backupNumber
+	restored.Spec.Restore.DryRun = false
+	restored.Spec.Backup.DryRun = &v1alpha2.BackupDryRun{}
+	restored.Spec.Backup.MakeBackupBeforePodDeletion = false
+	restored.Spec.Backup.Destinations = nil
+	// extra resources have fixed names, they would be taken over from the given Jenkins CR
+	restored.Spec.ExtraResources = nil
+	restored.Spec.JenkinsAPISettings = v1alpha2.JenkinsAPISettings{
+		AuthorizationStrategy: restored.Spec.JenkinsAPISettings.AuthorizationStrategy,
+		AuthProxy:             restored.Spec.JenkinsAPISettings.AuthProxy,
+	}
+	for _, service := range []*v1alpha2.Service{&restored.Spec.Service, &restored.Spec.SlaveService} {
+		service.NodePort = 0
+		service.LoadBalancerIP = ""
+		service.LoadBalancerSourceRanges = nil
+	}
+	return restored
+}
@@ -0,0 +1,163 @@
+package backuprestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func rehearsalJenkins() *v1alpha2.Jenkins {
+	now := metav1.Now()
+	return &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			Backup: v1alpha2.Backup{
+				ContainerName:               "backup",
+				Interval:                    30,
+				MakeBackupBeforePodDeletion: true,
+				Destinations:                []v1alpha2.BackupDestination{{Name: "s3"}},
+			},
+			Restore: v1alpha2.Restore{
+				ContainerName: "backup",
+				Rehearsal:     &v1alpha2.RestoreRehearsal{Interval: 3600, Timeout: 600},
+			},
+			SeedJobs: []v1alpha2.SeedJob{{ID: "jenkins-operator"}},
+			Service: v1alpha2.Service{
+				Type:     corev1.ServiceTypeNodePort,
+				Port:     8080,
+				NodePort: 30303,
+			},
+		},
+		Status: v1alpha2.JenkinsStatus{
+			LastBackup:                     5,
+			UserConfigurationCompletedTime: &now,
+		},
+	}
+}
+
+func TestNewRestoreRehearsalJenkins(t *testing.T) {
+	jenkins := rehearsalJenkins()
+
+	rehearsal := newRestoreRehearsalJenkins(jenkins, 5)
+
+	assert.Equal(t, "jenkins-restore-rehearsal", rehearsal.Name)
+	assert.True(t, IsRestoreRehearsal(rehearsal))
+	assert.True(t, resources.IsQuietDown(rehearsal))
+	assert.Nil(t, rehearsal.Spec.Restore.Rehearsal)
+	assert.Equal(t, uint64(5), rehearsal.Spec.Restore.RecoveryOnce)
+	assert.NotNil(t, rehearsal.Spec.Backup.DryRun)
+	assert.False(t, rehearsal.Spec.Backup.MakeBackupBeforePodDeletion)
+	assert.Empty(t, rehearsal.Spec.Backup.Destinations)
+	assert.Empty(t, rehearsal.Spec.SeedJobs)
+	assert.Equal(t, corev1.ServiceTypeClusterIP, rehearsal.Spec.Service.Type)
+	assert.Equal(t, int32(0), rehearsal.Spec.Service.NodePort)
+	assert.Equal(t, int32(8080), rehearsal.Spec.Service.Port)
+	// source CR is not modified
+	assert.NotNil(t, jenkins.Spec.Restore.Rehearsal)
+	assert.Len(t, jenkins.Spec.SeedJobs, 1)
+}
+
+func TestIsRestoreRehearsalDue(t *testing.T) {
+	now := time.Now()
+	t.Run("no backup", func(t *testing.T) {
+		jenkins := rehearsalJenkins()
+		jenkins.Status.LastBackup = 0
+
+		assert.False(t, isRestoreRehearsalDue(jenkins, now))
+	})
+	t.Run("first rehearsal", func(t *testing.T) {
+		assert.True(t, isRestoreRehearsalDue(rehearsalJenkins(), now))
+	})
+	t.Run("interval", func(t *testing.T) {
+		jenkins := rehearsalJenkins()
+		jenkins.Status.RestoreRehearsal = &v1alpha2.RestoreRehearsalStatus{StartTime: metav1.NewTime(now.Add(-time.Minute))}
+
+		assert.False(t, isRestoreRehearsalDue(jenkins, now))
+		assert.True(t, isRestoreRehearsalDue(jenkins, now.Add(time.Hour)))
+	})
+}
+
+func TestRestoreRehearsal_check(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	ctx := context.TODO()
+	rehearsalKey := types.NamespacedName{Namespace: "default", Name: "jenkins-restore-rehearsal"}
+
+	t.Run("start", func(t *testing.T) {
+		jenkins := rehearsalJenkins()
+		fakeClient := fake.NewClientBuilder().WithObjects(jenkins).Build()
+		restoreRehearsal := &RestoreRehearsal{Client: fakeClient}
+
+		err := restoreRehearsal.check(ctx, jenkins)
+
+		require.NoError(t, err)
+		rehearsal := &v1alpha2.Jenkins{}
+		assert.NoError(t, fakeClient.Get(ctx, rehearsalKey, rehearsal))
+		assert.Equal(t, uint64(5), rehearsal.Spec.Restore.RecoveryOnce)
+		if assert.NotNil(t, jenkins.Status.RestoreRehearsal) {
+			assert.Equal(t, uint64(5), jenkins.Status.RestoreRehearsal.BackupNumber)
+			assert.Nil(t, jenkins.Status.RestoreRehearsal.CompletionTime)
+		}
+	})
+	t.Run("timeout", func(t *testing.T) {
+		jenkins := rehearsalJenkins()
+		jenkins.Status.RestoreRehearsal = &v1alpha2.RestoreRehearsalStatus{
+			BackupNumber: 5,
+			StartTime:    metav1.NewTime(time.Now().Add(-time.Hour)),
+		}
+		fakeClient := fake.NewClientBuilder().WithObjects(jenkins, newRestoreRehearsalJenkins(jenkins, 5)).Build()
+		restoreRehearsal := &RestoreRehearsal{Client: fakeClient}
+
+		err := restoreRehearsal.check(ctx, jenkins)
+
+		require.NoError(t, err)
+		err = fakeClient.Get(ctx, rehearsalKey, &v1alpha2.Jenkins{})
+		assert.True(t, apierrors.IsNotFound(err))
+		assert.False(t, jenkins.Status.RestoreRehearsal.Succeeded)
+		assert.Equal(t, "backup '5' not restored in 600 seconds", jenkins.Status.RestoreRehearsal.Message)
+		assert.NotNil(t, jenkins.Status.RestoreRehearsal.CompletionTime)
+	})
+	t.Run("in progress", func(t *testing.T) {
+		jenkins := rehearsalJenkins()
+		jenkins.Status.RestoreRehearsal = &v1alpha2.RestoreRehearsalStatus{BackupNumber: 5, StartTime: metav1.Now()}
+		fakeClient := fake.NewClientBuilder().WithObjects(jenkins, newRestoreRehearsalJenkins(jenkins, 5)).Build()
+		restoreRehearsal := &RestoreRehearsal{Client: fakeClient}
+
+		err := restoreRehearsal.check(ctx, jenkins)
+
+		require.NoError(t, err)
+		assert.NoError(t, fakeClient.Get(ctx, rehearsalKey, &v1alpha2.Jenkins{}))
+		assert.Nil(t, jenkins.Status.RestoreRehearsal.CompletionTime)
+	})
+}
+
+func TestRestoreRehearsal_checkAll(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	ctx := context.TODO()
+
+	t.Run("external Jenkins is skipped", func(t *testing.T) {
+		jenkins := rehearsalJenkins()
+		jenkins.Spec.ExternalJenkins = &v1alpha2.ExternalJenkins{URL: "https://jenkins.example.com"}
+		fakeClient := fake.NewClientBuilder().WithObjects(jenkins).Build()
+		restoreRehearsal := &RestoreRehearsal{Client: fakeClient}
+
+		restoreRehearsal.checkAll(ctx)
+
+		err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: GetRestoreRehearsalName(jenkins)}, &v1alpha2.Jenkins{})
+		assert.True(t, apierrors.IsNotFound(err))
+		stored := &v1alpha2.Jenkins{}
+		require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: jenkins.Name}, stored))
+		assert.Nil(t, stored.Status.RestoreRehearsal)
+	})
+}
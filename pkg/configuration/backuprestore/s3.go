@@ -0,0 +1,232 @@
+package backuprestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// S3AccessKeyIDSecretKey is access key ID data key in Kubernetes secret used to access S3 backup bucket
+	S3AccessKeyIDSecretKey = "accessKeyId"
+	// S3SecretAccessKeySecretKey is secret access key data key in Kubernetes secret used to access S3 backup bucket
+	S3SecretAccessKeySecretKey = "secretAccessKey"
+
+	s3BackupSuffix = ".tar.gz"
+)
+
+// IsS3Backup returns true if backups are made by operator to S3-compatible object storage
+func IsS3Backup(jenkins *v1alpha2.Jenkins) bool {
+	return jenkins.Spec.Backup.S3 != nil
+}
+
+// backupToS3 archives Jenkins home, uploads the archive to the bucket and deletes backups exceeding retention
+func (bar *BackupAndRestore) backupToS3(backupNumber uint64) error {
+	jenkins := bar.Configuration.Jenkins
+	config := jenkins.Spec.Backup.S3
+	client, err := bar.newS3Client(*config)
+	if err != nil {
+		return err
+	}
+
+	archive, err := ioutil.TempFile("", "jenkins-backup-*"+s3BackupSuffix)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		_ = archive.Close()
+		_ = os.Remove(archive.Name())
+	}()
+
+	hash := sha256.New()
+	podName := resources.GetJenkinsMasterPodName(jenkins)
+	command := s3BackupCommand(resources.GetJenkinsHomePath(jenkins), config.Excludes)
+	if _, err = bar.ExecStream(podName, resources.JenkinsMasterContainerName, command, nil, io.MultiWriter(archive, hash)); err != nil {
+		return errors.Wrap(err, "failed to archive Jenkins home")
+	}
+	size, err := archive.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err = archive.Seek(0, io.SeekStart); err != nil {
+		return errors.WithStack(err)
+	}
+
+	key := s3BackupKey(config.Prefix, backupNumber)
+	bar.logger.V(log.VDebug).Info(fmt.Sprintf("Uploading backup '%d' of %d bytes to S3 bucket '%s' key '%s'", backupNumber, size, config.Bucket, key))
+	if err = client.putObject(context.TODO(), key, archive, size, hex.EncodeToString(hash.Sum(nil))); err != nil {
+		return err
+	}
+
+	if err = bar.deleteExpiredS3Backups(client, *config); err != nil {
+		bar.logger.V(log.VWarn).Info(fmt.Sprintf("Failed to delete backups exceeding spec.backup.s3.keepLast: %s", err))
+	}
+	return nil
+}
+
+// restoreFromS3 downloads the backup from the bucket and extracts it into Jenkins home
+func (bar *BackupAndRestore) restoreFromS3(backupNumber uint64) error {
+	jenkins := bar.Configuration.Jenkins
+	config := jenkins.Spec.Backup.S3
+	client, err := bar.newS3Client(*config)
+	if err != nil {
+		return err
+	}
+
+	archive, err := client.getObject(context.TODO(), s3BackupKey(config.Prefix, backupNumber))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = archive.Close() }()
+
+	podName := resources.GetJenkinsMasterPodName(jenkins)
+	command := []string{"tar", "-C", resources.GetJenkinsHomePath(jenkins), "-xzf", "-"}
+	_, err = bar.ExecStream(podName, resources.JenkinsMasterContainerName, command, archive, ioutil.Discard)
+	return errors.Wrap(err, "failed to extract backup into Jenkins home")
+}
+
+// getLatestS3Backup returns the latest backup number in the bucket or 0 if there is no backup
+func (bar *BackupAndRestore) getLatestS3Backup() (uint64, error) {
+	config := bar.Configuration.Jenkins.Spec.Backup.S3
+	client, err := bar.newS3Client(*config)
+	if err != nil {
+		return 0, err
+	}
+	backupNumbers, err := listS3Backups(client, config.Prefix)
+	if err != nil || len(backupNumbers) == 0 {
+		return 0, err
+	}
+	return backupNumbers[len(backupNumbers)-1], nil
+}
+
+func (bar *BackupAndRestore) deleteExpiredS3Backups(client *s3Client, config v1alpha2.S3Backup) error {
+	if config.KeepLast == 0 {
+		return nil
+	}
+	backupNumbers, err := listS3Backups(client, config.Prefix)
+	if err != nil {
+		return err
+	}
+	for _, backupNumber := range expiredBackups(backupNumbers, config.KeepLast) {
+		bar.logger.Info(fmt.Sprintf("Deleting backup '%d' from S3 bucket '%s'", backupNumber, config.Bucket))
+		if err = client.deleteObject(context.TODO(), s3BackupKey(config.Prefix, backupNumber)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bar *BackupAndRestore) newS3Client(config v1alpha2.S3Backup) (*s3Client, error) {
+	secret := &corev1.Secret{}
+	name := types.NamespacedName{Namespace: bar.Configuration.Jenkins.Namespace, Name: config.CredentialsSecretName}
+	if err := bar.Client.Get(context.TODO(), name, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to get S3 credentials secret '%s'", config.CredentialsSecretName)
+	}
+	for _, key := range []string{S3AccessKeyIDSecretKey, S3SecretAccessKeySecretKey} {
+		if len(secret.Data[key]) == 0 {
+			return nil, errors.Errorf("required data '%s' not found in secret '%s'", key, config.CredentialsSecretName)
+		}
+	}
+	return newS3Client(config.Endpoint, config.Region, config.Bucket,
+		string(secret.Data[S3AccessKeyIDSecretKey]), string(secret.Data[S3SecretAccessKeySecretKey]))
+}
+
+// listS3Backups returns sorted numbers of backups in the bucket, objects which aren't backups are ignored
+func listS3Backups(client *s3Client, prefix string) ([]uint64, error) {
+	keyPrefix := s3BackupPrefix(prefix)
+	keys, err := client.listObjects(context.TODO(), keyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	var backupNumbers []uint64
+	for _, key := range keys {
+		name := strings.TrimPrefix(key, keyPrefix)
+		if !strings.HasSuffix(name, s3BackupSuffix) {
+			continue
+		}
+		backupNumber, err := strconv.ParseUint(strings.TrimSuffix(name, s3BackupSuffix), 10, 64)
+		if err != nil || backupNumber == 0 {
+			continue
+		}
+		backupNumbers = append(backupNumbers, backupNumber)
+	}
+	sort.Slice(backupNumbers, func(i, j int) bool { return backupNumbers[i] < backupNumbers[j] })
+	return backupNumbers, nil
+}
+
+// expiredBackups returns the backup numbers exceeding the number of backups to keep, backupNumbers must be sorted
+func expiredBackups(backupNumbers []uint64, keepLast uint64) []uint64 {
+	if uint64(len(backupNumbers)) <= keepLast {
+		return nil
+	}
+	return backupNumbers[:uint64(len(backupNumbers))-keepLast]
+}
+
+func s3BackupKey(prefix string, backupNumber uint64) string {
+	return fmt.Sprintf("%s%d%s", s3BackupPrefix(prefix), backupNumber, s3BackupSuffix)
+}
+
+func s3BackupPrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if len(prefix) == 0 {
+		return ""
+	}
+	return prefix + "/"
+}
+
+// s3BackupCommand returns command which writes archive of Jenkins home to stdout, tar exit code 1 is ignored because
+// it only means that some files have been changed while being archived by running Jenkins
+func s3BackupCommand(jenkinsHomePath string, excludes []string) []string {
+	command := []string{"sh", "-c", `tar "$@"; code=$?; [ $code -le 1 ] || exit $code`, "tar", "-C", jenkinsHomePath, "-czf", "-"}
+	for _, exclude := range excludes {
+		command = append(command, "--exclude=./"+filepath.Clean(exclude))
+	}
+	return append(command, ".")
+}
+
+func validateS3Backup(jenkins *v1alpha2.Jenkins) []string {
+	config := jenkins.Spec.Backup.S3
+	if config == nil {
+		return nil
+	}
+
+	var messages []string
+	if endpoint, err := url.Parse(config.Endpoint); err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || len(endpoint.Host) == 0 {
+		messages = append(messages, fmt.Sprintf("spec.backup.s3.endpoint '%s' must be http or https URL", config.Endpoint))
+	}
+	if len(config.Bucket) == 0 {
+		messages = append(messages, "spec.backup.s3.bucket is not configured")
+	}
+	if len(config.CredentialsSecretName) == 0 {
+		messages = append(messages, "spec.backup.s3.credentialsSecretName is not configured")
+	}
+	for _, exclude := range config.Excludes {
+		if len(exclude) == 0 || filepath.IsAbs(exclude) || strings.HasPrefix(filepath.Clean(exclude), "..") {
+			messages = append(messages, fmt.Sprintf("spec.backup.s3.excludes '%s' must be a path relative to Jenkins home", exclude))
+		}
+	}
+	if len(jenkins.Spec.Backup.ContainerName) > 0 || jenkins.Spec.Backup.Action.Exec != nil {
+		messages = append(messages, "spec.backup.s3 can't be used together with spec.backup.containerName and spec.backup.action")
+	}
+	if len(jenkins.Spec.Restore.ContainerName) > 0 || jenkins.Spec.Restore.Action.Exec != nil || jenkins.Spec.Restore.GetLatestAction.Exec != nil {
+		messages = append(messages, "spec.backup.s3 can't be used together with spec.restore.containerName and spec.restore actions")
+	}
+	return messages
+}
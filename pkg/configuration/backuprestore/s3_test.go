@@ -0,0 +1,113 @@
+package backuprestore
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestS3BackupCommand(t *testing.T) {
+	command := s3BackupCommand("/var/lib/jenkins", []string{"workspace", "caches/"})
+
+	assert.Equal(t, []string{"sh", "-c", `tar "$@"; code=$?; [ $code -le 1 ] || exit $code`, "tar",
+		"-C", "/var/lib/jenkins", "-czf", "-", "--exclude=./workspace", "--exclude=./caches", "."}, command)
+}
+
+func TestS3BackupKey(t *testing.T) {
+	assert.Equal(t, "7.tar.gz", s3BackupKey("", 7))
+	assert.Equal(t, "jenkins/production/7.tar.gz", s3BackupKey("/jenkins/production/", 7))
+}
+
+func TestExpiredBackups(t *testing.T) {
+	assert.Equal(t, []uint64{1, 2}, expiredBackups([]uint64{1, 2, 3, 4, 5}, 3))
+	assert.Empty(t, expiredBackups([]uint64{1, 2}, 3))
+}
+
+func TestBackupAndRestore_S3(t *testing.T) {
+	s3 := newFakeS3("backups", "jenkins/1.tar.gz", "jenkins/10.tar.gz", "jenkins/2.tar.gz", "jenkins/3.tar.gz", "jenkins/notes.txt", "other/11.tar.gz")
+	server := httptest.NewServer(s3)
+	defer server.Close()
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			Backup: v1alpha2.Backup{
+				Interval: 30,
+				S3: &v1alpha2.S3Backup{
+					Endpoint:              server.URL,
+					Bucket:                "backups",
+					Prefix:                "jenkins",
+					CredentialsSecretName: "s3",
+					KeepLast:              2,
+				},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3", Namespace: "default"},
+		Data:       map[string][]byte{S3AccessKeyIDSecretKey: []byte("access"), S3SecretAccessKeySecretKey: []byte("secret")},
+	}
+	bar := New(configuration.Configuration{Client: fake.NewClientBuilder().WithObjects(secret).Build(), Jenkins: jenkins}, log.Log)
+
+	t.Run("latest backup", func(t *testing.T) {
+		backupNumber, err := bar.getLatestS3Backup()
+
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), backupNumber)
+	})
+	t.Run("retention", func(t *testing.T) {
+		client, err := bar.newS3Client(*jenkins.Spec.Backup.S3)
+		require.NoError(t, err)
+
+		require.NoError(t, bar.deleteExpiredS3Backups(client, *jenkins.Spec.Backup.S3))
+
+		assert.Equal(t, []string{"jenkins/10.tar.gz", "jenkins/3.tar.gz", "jenkins/notes.txt", "other/11.tar.gz"}, s3.keys())
+	})
+	t.Run("missing credentials", func(t *testing.T) {
+		config := *jenkins.Spec.Backup.S3
+		config.CredentialsSecretName = "missing"
+
+		_, err := bar.newS3Client(config)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestValidateS3Backup(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{Spec: v1alpha2.JenkinsSpec{Backup: v1alpha2.Backup{S3: &v1alpha2.S3Backup{
+			Endpoint:              "https://s3.eu-west-1.amazonaws.com",
+			Bucket:                "backups",
+			CredentialsSecretName: "s3",
+			Excludes:              []string{"workspace"},
+		}}}}
+
+		assert.Empty(t, validateS3Backup(jenkins))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{Spec: v1alpha2.JenkinsSpec{
+			Backup: v1alpha2.Backup{
+				ContainerName: "backup",
+				S3:            &v1alpha2.S3Backup{Endpoint: "minio:9000", Excludes: []string{"../secrets"}},
+			},
+			Restore: v1alpha2.Restore{ContainerName: "backup"},
+		}}
+
+		assert.Equal(t, []string{
+			"spec.backup.s3.endpoint 'minio:9000' must be http or https URL",
+			"spec.backup.s3.bucket is not configured",
+			"spec.backup.s3.credentialsSecretName is not configured",
+			"spec.backup.s3.excludes '../secrets' must be a path relative to Jenkins home",
+			"spec.backup.s3 can't be used together with spec.backup.containerName and spec.backup.action",
+			"spec.backup.s3 can't be used together with spec.restore.containerName and spec.restore actions",
+		}, validateS3Backup(jenkins))
+	})
+}
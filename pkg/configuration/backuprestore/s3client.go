@@ -0,0 +1,230 @@
+package backuprestore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	s3Service          = "s3"
+	s3DefaultRegion    = "us-east-1"
+	s3SigningAlgorithm = "AWS4-HMAC-SHA256"
+	s3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	s3AmzDateFormat    = "20060102T150405Z"
+	s3DateFormat       = "20060102"
+
+	// s3RequestTimeout limits every request including the transfer of the backup archive, the stuck object storage
+	// mustn't block the reconcile loop
+	s3RequestTimeout = 30 * time.Minute
+)
+
+// s3Client is the minimal client of S3-compatible object storage used to store Jenkins backups, requests are signed
+// with AWS Signature Version 4 and buckets are addressed in path-style so it works with AWS S3, MinIO, Ceph etc.
+type s3Client struct {
+	endpoint        *url.URL
+	region          string
+	bucket          string
+	accessKeyID     string
+	secretAccessKey string
+	httpClient      *http.Client
+	now             func() time.Time
+}
+
+func newS3Client(endpoint, region, bucket, accessKeyID, secretAccessKey string) (*s3Client, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid S3 endpoint '%s'", endpoint)
+	}
+	if len(region) == 0 {
+		region = s3DefaultRegion
+	}
+	return &s3Client{
+		endpoint:        endpointURL,
+		region:          region,
+		bucket:          bucket,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		httpClient:      &http.Client{Timeout: s3RequestTimeout},
+		now:             time.Now,
+	}, nil
+}
+
+type s3ListBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// putObject uploads object of the given size, payloadHash is hex encoded SHA256 of the body
+func (c *s3Client) putObject(ctx context.Context, key string, body io.Reader, size int64, payloadHash string) error {
+	response, err := c.do(ctx, http.MethodPut, key, nil, body, size, payloadHash)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+// getObject returns body of the object, the caller has to close it
+func (c *s3Client) getObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	response, err := c.do(ctx, http.MethodGet, key, nil, nil, 0, s3EmptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+func (c *s3Client) deleteObject(ctx context.Context, key string) error {
+	response, err := c.do(ctx, http.MethodDelete, key, nil, nil, 0, s3EmptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+// listObjects returns keys of all objects with the given prefix
+func (c *s3Client) listObjects(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	continuationToken := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if len(continuationToken) > 0 {
+			query.Set("continuation-token", continuationToken)
+		}
+		response, err := c.do(ctx, http.MethodGet, "", query, nil, 0, s3EmptyPayloadHash)
+		if err != nil {
+			return nil, err
+		}
+		result := s3ListBucketResult{}
+		err = xml.NewDecoder(response.Body).Decode(&result)
+		_ = response.Body.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid response of listing S3 bucket '%s'", c.bucket)
+		}
+		for _, content := range result.Contents {
+			keys = append(keys, content.Key)
+		}
+		if !result.IsTruncated || len(result.NextContinuationToken) == 0 {
+			return keys, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+func (c *s3Client) do(ctx context.Context, method, key string, query url.Values, body io.Reader, size int64, payloadHash string) (*http.Response, error) {
+	requestURL := *c.endpoint
+	requestURL.Path = strings.TrimSuffix(requestURL.Path, "/") + "/" + c.bucket
+	if len(key) > 0 {
+		requestURL.Path += "/" + key
+	}
+	requestURL.RawPath = s3EscapePath(requestURL.Path)
+	requestURL.RawQuery = ""
+
+	request, err := http.NewRequestWithContext(ctx, method, requestURL.String(), body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	request.URL.RawQuery = s3CanonicalQuery(query)
+	if body != nil {
+		request.ContentLength = size
+	}
+	c.sign(request, payloadHash)
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		message, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+		_ = response.Body.Close()
+		return nil, errors.Errorf("S3 request %s '%s' failed with status %d: %s", method, requestURL.Path, response.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return response, nil
+}
+
+// sign signs the request with AWS Signature Version 4
+func (c *s3Client) sign(request *http.Request, payloadHash string) {
+	now := c.now().UTC()
+	amzDate := now.Format(s3AmzDateFormat)
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", now.Format(s3DateFormat), c.region, s3Service)
+
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", request.URL.Host, payloadHash, amzDate)
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s3SigningAlgorithm, amzDate, scope, hex.EncodeToString(canonicalRequestHash[:])}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+c.secretAccessKey), now.Format(s3DateFormat))
+	for _, part := range []string{c.region, s3Service, "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgorithm, c.accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3CanonicalQuery returns query string with sorted parameters encoded as required by AWS Signature Version 4
+func s3CanonicalQuery(query url.Values) string {
+	var keys []string
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var parameters []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parameters = append(parameters, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+	return strings.Join(parameters, "&")
+}
+
+func s3EscapePath(path string) string {
+	return s3Escape(path, false)
+}
+
+// s3Escape URI encodes every byte except the unreserved characters, slash is kept in paths
+func s3Escape(value string, escapeSlash bool) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			builder.WriteByte(b)
+		case b == '/' && !escapeSlash:
+			builder.WriteByte(b)
+		default:
+			builder.WriteString(fmt.Sprintf("%%%02X", b))
+		}
+	}
+	return builder.String()
+}
@@ -1,9 +1,12 @@
 package base
 
 import (
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
 
 	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -12,6 +15,11 @@ func (r *JenkinsBaseConfigurationReconciler) createScriptsConfigMap(meta metav1.
 	if err != nil {
 		return err
 	}
+	if resources.IsImmutableConfiguration(r.Configuration.Jenkins) {
+		return r.ensureConfigMapVersion(configMap, resources.ScriptsConfigurationVersion, func(versions *v1alpha2.ConfigurationVersions) *string {
+			return &versions.ScriptsConfigMap
+		})
+	}
 	return stackerr.WithStack(r.CreateOrUpdateResource(configMap))
 }
 
@@ -20,13 +28,33 @@ func (r *JenkinsBaseConfigurationReconciler) createInitConfigurationConfigMap(me
 	if err != nil {
 		return err
 	}
+	if resources.IsImmutableConfiguration(r.Configuration.Jenkins) {
+		return r.ensureConfigMapVersion(configMap, resources.InitConfigurationVersion, func(versions *v1alpha2.ConfigurationVersions) *string {
+			return &versions.InitConfigurationConfigMap
+		})
+	}
 	return stackerr.WithStack(r.CreateOrUpdateResource(configMap))
 }
 
 func (r *JenkinsBaseConfigurationReconciler) createBaseConfigurationConfigMap(meta metav1.ObjectMeta) error {
-	configMap, err := resources.NewBaseConfigurationConfigMap(meta, r.Configuration.Jenkins, r.KubernetesClusterDomain)
+	values, err := configuration.GetValues(r.Client, r.Configuration.Jenkins)
+	if err != nil {
+		return err
+	}
+	configMap, err := resources.NewBaseConfigurationConfigMap(meta, r.Configuration.Jenkins, r.KubernetesClusterDomain, values)
 	if err != nil {
 		return err
 	}
+	if resources.IsImmutableConfiguration(r.Configuration.Jenkins) {
+		return r.ensureConfigMapVersion(configMap, resources.BaseConfigurationVersion, func(versions *v1alpha2.ConfigurationVersions) *string {
+			return &versions.BaseConfigurationConfigMap
+		})
+	}
 	return stackerr.WithStack(r.CreateOrUpdateResource(configMap))
 }
+
+func (r *JenkinsBaseConfigurationReconciler) ensureConfigMapVersion(configMap *corev1.ConfigMap, version string,
+	current func(*v1alpha2.ConfigurationVersions) *string) error {
+	return r.ensureConfigurationVersion(resources.NewConfigMapVersion(r.Configuration.Jenkins, configMap, version),
+		&corev1.ConfigMapList{}, version, current)
+}
@@ -28,23 +28,23 @@ func (r *JenkinsBaseConfigurationReconciler) compareContainers(expected corev1.C
 		messages = append(messages, "Image has changed")
 		verbose = append(verbose, fmt.Sprintf("Image has changed to '%+v' in container '%s'", expected.Image, expected.Name))
 	}
-	if !reflect.DeepEqual(expected.ImagePullPolicy, actual.ImagePullPolicy) {
+	if len(expected.ImagePullPolicy) > 0 && expected.ImagePullPolicy != actual.ImagePullPolicy {
 		messages = append(messages, "Image pull policy has changed")
 		verbose = append(verbose, fmt.Sprintf("Image pull policy has changed to '%+v' in container '%s'", expected.ImagePullPolicy, expected.Name))
 	}
-	if !reflect.DeepEqual(expected.Lifecycle, actual.Lifecycle) {
+	if !reflect.DeepEqual(withLifecycleDefaults(expected.Lifecycle), withLifecycleDefaults(actual.Lifecycle)) {
 		messages = append(messages, "Lifecycle has changed")
 		verbose = append(verbose, fmt.Sprintf("Lifecycle has changed to '%+v' in container '%s'", expected.Lifecycle, expected.Name))
 	}
-	if !reflect.DeepEqual(expected.LivenessProbe, actual.LivenessProbe) {
+	if !reflect.DeepEqual(withProbeDefaults(expected.LivenessProbe), withProbeDefaults(actual.LivenessProbe)) {
 		messages = append(messages, "Liveness probe has changed")
 		verbose = append(verbose, fmt.Sprintf("Liveness probe has changed to '%+v' in container '%s'", expected.LivenessProbe, expected.Name))
 	}
-	if !reflect.DeepEqual(expected.Ports, actual.Ports) {
+	if !reflect.DeepEqual(withPortDefaults(expected.Ports), withPortDefaults(actual.Ports)) {
 		messages = append(messages, "Ports have changed")
 		verbose = append(verbose, fmt.Sprintf("Ports have changed to '%+v' in container '%s'", expected.Ports, expected.Name))
 	}
-	if !reflect.DeepEqual(expected.ReadinessProbe, actual.ReadinessProbe) {
+	if !reflect.DeepEqual(withProbeDefaults(expected.ReadinessProbe), withProbeDefaults(actual.ReadinessProbe)) {
 		messages = append(messages, "Readiness probe has changed")
 		verbose = append(verbose, fmt.Sprintf("Readiness probe has changed to '%+v' in container '%s'", expected.ReadinessProbe, expected.Name))
 	}
@@ -68,26 +68,77 @@ func (r *JenkinsBaseConfigurationReconciler) compareContainers(expected corev1.C
 }
 
 func compareContainerResources(expected corev1.ResourceRequirements, actual corev1.ResourceRequirements) bool {
-	expectedRequestCPU, expectedRequestCPUSet := expected.Requests[corev1.ResourceCPU]
-	expectedRequestMemory, expectedRequestMemorySet := expected.Requests[corev1.ResourceMemory]
-	expectedLimitCPU, expectedLimitCPUSet := expected.Limits[corev1.ResourceCPU]
-	expectedLimitMemory, expectedLimitMemorySet := expected.Limits[corev1.ResourceMemory]
-	actualRequestCPU, actualRequestCPUSet := actual.Requests[corev1.ResourceCPU]
-	actualRequestMemory, actualRequestMemorySet := actual.Requests[corev1.ResourceMemory]
-	actualLimitCPU, actualLimitCPUSet := actual.Limits[corev1.ResourceCPU]
-	actualLimitMemory, actualLimitMemorySet := actual.Limits[corev1.ResourceMemory]
+	for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage} {
+		if !compareResourceQuantity(expected.Requests, actual.Requests, resourceName) {
+			return false
+		}
+		if !compareResourceQuantity(expected.Limits, actual.Limits, resourceName) {
+			return false
+		}
+	}
+	return true
+}
 
-	if expectedRequestCPUSet && (!actualRequestCPUSet || expectedRequestCPU.String() != actualRequestCPU.String()) {
-		return false
+// compareResourceQuantity checks if the expected resource quantity, when set, is equal to the actual one
+func compareResourceQuantity(expected corev1.ResourceList, actual corev1.ResourceList, resourceName corev1.ResourceName) bool {
+	expectedQuantity, expectedSet := expected[resourceName]
+	if !expectedSet {
+		return true
+	}
+	actualQuantity, actualSet := actual[resourceName]
+	return actualSet && expectedQuantity.Cmp(actualQuantity) == 0
+}
+
+// withProbeDefaults returns copy of the probe with the fields defaulted by API server set, the probe read back
+// from the pod would differ from the one without them
+func withProbeDefaults(probe *corev1.Probe) *corev1.Probe {
+	if probe == nil {
+		return nil
 	}
-	if expectedRequestMemorySet && (!actualRequestMemorySet || expectedRequestMemory.String() != actualRequestMemory.String()) {
-		return false
+	probe = probe.DeepCopy()
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = 1
 	}
-	if expectedLimitCPUSet && (!actualLimitCPUSet || expectedLimitCPU.String() != actualLimitCPU.String()) {
-		return false
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = 10
 	}
-	if expectedLimitMemorySet && (!actualLimitMemorySet || expectedLimitMemory.String() != actualLimitMemory.String()) {
-		return false
+	if probe.SuccessThreshold == 0 {
+		probe.SuccessThreshold = 1
 	}
-	return true
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = 3
+	}
+	if probe.HTTPGet != nil && len(probe.HTTPGet.Scheme) == 0 {
+		probe.HTTPGet.Scheme = corev1.URISchemeHTTP
+	}
+	return probe
+}
+
+// withLifecycleDefaults returns copy of the lifecycle with the scheme of HTTP handlers defaulted by API server set
+func withLifecycleDefaults(lifecycle *corev1.Lifecycle) *corev1.Lifecycle {
+	if lifecycle == nil {
+		return nil
+	}
+	lifecycle = lifecycle.DeepCopy()
+	for _, handler := range []*corev1.Handler{lifecycle.PostStart, lifecycle.PreStop} {
+		if handler != nil && handler.HTTPGet != nil && len(handler.HTTPGet.Scheme) == 0 {
+			handler.HTTPGet.Scheme = corev1.URISchemeHTTP
+		}
+	}
+	return lifecycle
+}
+
+// withPortDefaults returns copy of the ports with the protocol defaulted by API server set
+func withPortDefaults(ports []corev1.ContainerPort) []corev1.ContainerPort {
+	if len(ports) == 0 {
+		return nil
+	}
+	defaulted := make([]corev1.ContainerPort, len(ports))
+	for i, port := range ports {
+		if len(port.Protocol) == 0 {
+			port.Protocol = corev1.ProtocolTCP
+		}
+		defaulted[i] = port
+	}
+	return defaulted
 }
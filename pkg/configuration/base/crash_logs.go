@@ -0,0 +1,87 @@
+package base
+
+import (
+	"fmt"
+
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// saveCrashLogs stores logs of terminated containers of Jenkins master pod in a config map, so they are available
+// after the pod is deleted. It returns a message referencing the config map or an empty string if nothing was saved.
+func (r *JenkinsBaseConfigurationReconciler) saveCrashLogs(pod corev1.Pod) string {
+	if r.Configuration.Jenkins.Spec.Master.CrashLogs == nil {
+		return ""
+	}
+	tailLines := resources.GetCrashLogsTailLines(r.Configuration.Jenkins)
+
+	logs := map[string]string{}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.State.Terminated == nil {
+			continue
+		}
+		containerLogs, err := r.Configuration.GetContainerLogs(pod.Name, containerStatus.Name, tailLines)
+		if err != nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Failed to get logs of container '%s': %s", containerStatus.Name, err))
+		} else {
+			logs[fmt.Sprintf("%s.log", containerStatus.Name)] = containerLogs
+		}
+		if message := containerStatus.State.Terminated.Message; len(message) > 0 {
+			logs[fmt.Sprintf("%s-termination-message.txt", containerStatus.Name)] = message
+		}
+	}
+	for name, content := range r.getCrashFiles(pod, tailLines) {
+		logs[name] = content
+	}
+	if len(logs) == 0 {
+		return ""
+	}
+
+	configMap := resources.NewCrashLogsConfigMap(resources.NewResourceObjectMeta(r.Configuration.Jenkins), r.Configuration.Jenkins, pod.Name, logs)
+	if err := r.CreateOrUpdateResource(configMap); err != nil {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Failed to save crash logs: %s", err))
+		return ""
+	}
+	return fmt.Sprintf("Crash logs saved in ConfigMap '%s'", configMap.Name)
+}
+
+// getCrashFiles returns the latest JVM fatal error file and GC log from the crash logs directory, they are read through
+// a running container of the pod which mounts the Jenkins home volume, e.g. the backup container. The fatal error file
+// is removed once read, so it's not reported again for a later crash.
+func (r *JenkinsBaseConfigurationReconciler) getCrashFiles(pod corev1.Pod, tailLines int64) map[string]string {
+	running := map[string]bool{}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		running[containerStatus.Name] = containerStatus.State.Running != nil
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if !running[container.Name] {
+			continue
+		}
+		for _, volumeMount := range container.VolumeMounts {
+			if volumeMount.Name != resources.JenkinsHomeVolumeName {
+				continue
+			}
+			crashLogsPath := fmt.Sprintf("%s/%s", volumeMount.MountPath, resources.CrashLogsDirectory)
+			commands := map[string]string{
+				"hs_err.log": fmt.Sprintf(`file=$(ls -t %s/hs_err_pid*.log 2>/dev/null | head -n 1); [ -z "$file" ] || { head -n %d "$file"; rm -f "$file"; }`, crashLogsPath, tailLines),
+				"gc.log":     fmt.Sprintf(`[ ! -f %s/gc.log ] || tail -n %d %s/gc.log`, crashLogsPath, tailLines, crashLogsPath),
+			}
+			files := map[string]string{}
+			for name, command := range commands {
+				stdout, _, err := r.Configuration.Exec(pod.Name, container.Name, []string{"sh", "-c", command})
+				if err != nil {
+					r.logger.V(log.VWarn).Info(fmt.Sprintf("Failed to read '%s' in container '%s': %s", name, container.Name, err))
+					continue
+				}
+				if stdout.Len() > 0 {
+					files[name] = stdout.String()
+				}
+			}
+			return files
+		}
+	}
+	return nil
+}
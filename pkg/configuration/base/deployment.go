@@ -5,10 +5,10 @@ import (
 	"fmt"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
 	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
 	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
-	"github.com/maximba/kubernetes-operator/version"
 
 	stackerr "github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -38,15 +38,8 @@ func (r *JenkinsBaseConfigurationReconciler) ensureJenkinsDeployment(meta metav1
 			return reconcile.Result{}, stackerr.WithStack(err)
 		}
 
-		now := metav1.Now()
-		r.Configuration.Jenkins.Status = v1alpha2.JenkinsStatus{
-			OperatorVersion:     version.Version,
-			ProvisionStartTime:  &now,
-			LastBackup:          r.Configuration.Jenkins.Status.LastBackup,
-			PendingBackup:       r.Configuration.Jenkins.Status.LastBackup,
-			UserAndPasswordHash: userAndPasswordHash,
-		}
-		return reconcile.Result{Requeue: true}, r.Client.Update(context.TODO(), r.Configuration.Jenkins)
+		configuration.ResetJenkinsMasterPodStatus(r.Configuration.Jenkins, userAndPasswordHash)
+		return reconcile.Result{Requeue: true}, r.Client.Status().Update(context.TODO(), r.Configuration.Jenkins)
 	} else if err != nil && !apierrors.IsNotFound(err) {
 		return reconcile.Result{}, stackerr.WithStack(err)
 	}
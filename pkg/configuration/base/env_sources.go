@@ -0,0 +1,128 @@
+package base
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// envSourcesHashAnnotation is the hash of Secrets and ConfigMaps referenced by environment variables of Jenkins
+// master containers, the pod is restarted when it changes
+const envSourcesHashAnnotation = "jenkins.io/env-sources-hash"
+
+const (
+	secretEnvSource    = "secret"
+	configMapEnvSource = "configmap"
+)
+
+// envSource is a Secret or ConfigMap referenced by env or envFrom of Jenkins master containers
+type envSource struct {
+	kind string
+	name string
+}
+
+// getEnvSources returns sorted unique Secrets and ConfigMaps referenced by env and envFrom of Jenkins master containers
+func getEnvSources(jenkins *v1alpha2.Jenkins) []envSource {
+	found := map[envSource]bool{}
+	for _, container := range jenkins.Spec.Master.Containers {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil {
+				found[envSource{kind: secretEnvSource, name: envFrom.SecretRef.Name}] = true
+			}
+			if envFrom.ConfigMapRef != nil {
+				found[envSource{kind: configMapEnvSource, name: envFrom.ConfigMapRef.Name}] = true
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				found[envSource{kind: secretEnvSource, name: env.ValueFrom.SecretKeyRef.Name}] = true
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				found[envSource{kind: configMapEnvSource, name: env.ValueFrom.ConfigMapKeyRef.Name}] = true
+			}
+		}
+	}
+
+	var sources []envSource
+	for source := range found {
+		sources = append(sources, source)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].kind != sources[j].kind {
+			return sources[i].kind < sources[j].kind
+		}
+		return sources[i].name < sources[j].name
+	})
+	return sources
+}
+
+// calculateEnvSourcesHash returns hash of data of Secrets and ConfigMaps referenced by environment variables of
+// Jenkins master containers and labels them to be watched, an empty string is returned when there are no references
+func (r *JenkinsBaseConfigurationReconciler) calculateEnvSourcesHash() (string, error) {
+	sources := getEnvSources(r.Configuration.Jenkins)
+	if len(sources) == 0 {
+		return "", nil
+	}
+
+	hash := sha256.New()
+	for _, source := range sources {
+		var object client.Object
+		data := map[string][]byte{}
+		secret := &corev1.Secret{}
+		configMap := &corev1.ConfigMap{}
+		if source.kind == secretEnvSource {
+			object = secret
+		} else {
+			object = configMap
+		}
+
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.Configuration.Jenkins.Namespace, Name: source.name}, object)
+		if err != nil && apierrors.IsNotFound(err) {
+			// missing optional sources are allowed, missing required ones are reported by Kubernetes
+			_, _ = hash.Write([]byte(fmt.Sprintf("%s/%s:missing\n", source.kind, source.name)))
+			continue
+		} else if err != nil {
+			return "", stackerr.WithStack(err)
+		}
+		if err = r.addLabelsForWatchedResources(object); err != nil {
+			return "", err
+		}
+
+		if source.kind == secretEnvSource {
+			data = secret.Data
+		} else {
+			for key, value := range configMap.Data {
+				data[key] = []byte(value)
+			}
+			for key, value := range configMap.BinaryData {
+				data[key] = value
+			}
+		}
+		var keys []string
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		_, _ = hash.Write([]byte(fmt.Sprintf("%s/%s\n", source.kind, source.name)))
+		for _, key := range keys {
+			_, _ = hash.Write([]byte(key))
+			_, _ = hash.Write(data[key])
+			_, _ = hash.Write([]byte("\n"))
+		}
+	}
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil)), nil
+}
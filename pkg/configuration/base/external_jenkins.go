@@ -0,0 +1,111 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/backuprestore"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	stackerr "github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcileExternalJenkins applies the base configuration to Jenkins which isn't created by the operator. Kubernetes
+// resources of Jenkins master pod aren't created and plugins aren't verified because the operator can't install them.
+func (r *JenkinsBaseConfigurationReconciler) reconcileExternalJenkins(metaObject metav1.ObjectMeta) (reconcile.Result, jenkinsclient.Jenkins, error) {
+	jenkins := r.Configuration.Jenkins
+	if jenkins.Status.ProvisionStartTime == nil {
+		now := metav1.Now()
+		jenkins.Status.ProvisionStartTime = &now
+		if err := r.Client.Status().Update(context.TODO(), jenkins); err != nil {
+			return reconcile.Result{}, nil, stackerr.WithStack(err)
+		}
+	}
+
+	start := time.Now()
+	if err := r.createBaseConfigurationConfigMap(metaObject); err != nil {
+		return reconcile.Result{}, nil, err
+	}
+	for _, customization := range []v1alpha2.Customization{
+		jenkins.Spec.BaseGroovyScripts.Customization,
+		jenkins.Spec.GroovyScripts.Customization,
+		jenkins.Spec.ConfigurationAsCode.Customization,
+	} {
+		if err := r.addLabelForWatchesResources(customization); err != nil {
+			return reconcile.Result{}, nil, err
+		}
+	}
+	if err := r.addLabelForWatchedValues(); err != nil {
+		return reconcile.Result{}, nil, err
+	}
+	r.Timer.Record(configuration.ResourcesEnsurePhase, start)
+	r.logger.V(log.VDebug).Info("Kubernetes resources of external Jenkins are present")
+
+	jenkinsClient, err := r.Configuration.GetJenkinsClient()
+	if err != nil {
+		return reconcile.Result{}, nil, err
+	}
+	r.logger.V(log.VDebug).Info(fmt.Sprintf("Jenkins API client of external Jenkins '%s' set", jenkins.Spec.ExternalJenkins.URL))
+
+	if jenkins.Spec.Master.SkipBaseConfiguration {
+		r.logger.V(log.VDebug).Info("Base configuration is disabled, skipping base groovy scripts")
+		return reconcile.Result{}, jenkinsClient, nil
+	}
+
+	start = time.Now()
+	result, err := r.ensureBaseConfiguration(jenkinsClient)
+	r.Timer.Record(configuration.BaseGroovyScriptsPhase, start)
+
+	return result, jenkinsClient, err
+}
+
+// validateExternalJenkins verifies spec.externalJenkins, the features which require Jenkins master pod created by
+// the operator can't be used with external Jenkins
+func validateExternalJenkins(jenkins *v1alpha2.Jenkins) []string {
+	externalJenkins := jenkins.Spec.ExternalJenkins
+	if externalJenkins == nil {
+		return nil
+	}
+
+	var messages []string
+	if jenkinsURL, err := url.Parse(externalJenkins.URL); err != nil || (jenkinsURL.Scheme != "http" && jenkinsURL.Scheme != "https") || len(jenkinsURL.Host) == 0 {
+		messages = append(messages, fmt.Sprintf("spec.externalJenkins.url '%s' must be absolute http or https URL", externalJenkins.URL))
+	}
+	if len(externalJenkins.CredentialsSecret.Name) == 0 {
+		messages = append(messages, "spec.externalJenkins.credentialsSecret.name is not set")
+	}
+	if jenkins.Spec.JenkinsAPISettings.AuthorizationStrategy == v1alpha2.ServiceAccountAuthorizationStrategy {
+		messages = append(messages, fmt.Sprintf("spec.jenkinsAPISettings.authorizationStrategy '%s' can't be used with spec.externalJenkins", v1alpha2.ServiceAccountAuthorizationStrategy))
+	}
+	// the secrets are mounted into Jenkins master pod and read by groovy scripts from the files
+	secrets := []struct {
+		field string
+		name  string
+	}{
+		{"spec.baseGroovyScripts.secret", jenkins.Spec.BaseGroovyScripts.Secret.Name},
+		{"spec.groovyScripts.secret", jenkins.Spec.GroovyScripts.Secret.Name},
+		{"spec.configurationAsCode.secret", jenkins.Spec.ConfigurationAsCode.Secret.Name},
+	}
+	for _, secret := range secrets {
+		if len(secret.name) > 0 {
+			messages = append(messages, fmt.Sprintf("%s can't be used with spec.externalJenkins, the secret can't be mounted into external Jenkins", secret.field))
+		}
+	}
+	if len(jenkins.Spec.ConfigurationAsCode.ReloadStrategy) > 0 {
+		messages = append(messages, "spec.configurationAsCode.reloadStrategy can't be used with spec.externalJenkins, it requires Jenkins master pod")
+	}
+	if jenkins.Spec.ConfigurationAsCode.GitRepository != nil {
+		messages = append(messages, "spec.configurationAsCode.gitRepository can't be used with spec.externalJenkins, the repository is pulled in Jenkins master pod")
+	}
+	if backuprestore.IsBackupConfigured(jenkins) || len(jenkins.Spec.Restore.ContainerName) > 0 {
+		messages = append(messages, "spec.backup and spec.restore can't be used with spec.externalJenkins, they are executed in Jenkins master pod")
+	}
+	return messages
+}
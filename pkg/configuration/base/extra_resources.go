@@ -0,0 +1,226 @@
+package base
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// extraResourceHashAnnotation is the hash of the applied extra resource, the object is updated only when it changes
+const extraResourceHashAnnotation = "jenkins.io/extra-resource-hash"
+
+// allowedExtraResourceKinds are the namespaced kinds which can be applied from spec.extraResources. The objects are
+// applied with the operator RBAC, so kinds granting permissions or running workloads, e.g. RoleBinding, Pod or
+// Secret with a service account token, and cluster-scoped kinds are rejected to prevent privilege escalation.
+var allowedExtraResourceKinds = map[schema.GroupKind]bool{
+	{Group: "", Kind: "ConfigMap"}:                           true,
+	{Group: "", Kind: "Service"}:                             true,
+	{Group: "", Kind: "PersistentVolumeClaim"}:               true,
+	{Group: "networking.k8s.io", Kind: "Ingress"}:            true,
+	{Group: "networking.k8s.io", Kind: "NetworkPolicy"}:      true,
+	{Group: "policy", Kind: "PodDisruptionBudget"}:           true,
+	{Group: "cert-manager.io", Kind: "Certificate"}:          true,
+	{Group: "external-secrets.io", Kind: "ExternalSecret"}:   true,
+	{Group: "monitoring.coreos.com", Kind: "ServiceMonitor"}: true,
+	{Group: "monitoring.coreos.com", Kind: "PodMonitor"}:     true,
+}
+
+// validateExtraResourceKind returns an error if the kind of the object can't be applied from spec.extraResources
+func validateExtraResourceKind(object *unstructured.Unstructured) error {
+	groupKind := object.GroupVersionKind().GroupKind()
+	if groupKind.Group == rbacv1.GroupName {
+		return stackerr.Errorf("RBAC kind '%s' can't be applied", groupKind)
+	}
+	if !allowedExtraResourceKinds[groupKind] {
+		return stackerr.Errorf("kind '%s' isn't allowed, allowed kinds are %s", groupKind, strings.Join(getAllowedExtraResourceKinds(), ", "))
+	}
+	return nil
+}
+
+func getAllowedExtraResourceKinds() []string {
+	var kinds []string
+	for groupKind := range allowedExtraResourceKinds {
+		kinds = append(kinds, groupKind.String())
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// ensureExtraResources applies objects from spec.extraResources and deletes the objects removed from the spec
+func (r *JenkinsBaseConfigurationReconciler) ensureExtraResources() error {
+	objects, err := r.getExtraResources()
+	if err != nil {
+		return err
+	}
+
+	var applied []v1alpha2.ExtraResourceReference
+	for _, object := range objects {
+		if err := r.applyExtraResource(object); err != nil {
+			return err
+		}
+		applied = append(applied, v1alpha2.ExtraResourceReference{
+			APIVersion: object.GetAPIVersion(),
+			Kind:       object.GetKind(),
+			Name:       object.GetName(),
+		})
+	}
+
+	for _, reference := range r.Configuration.Jenkins.Status.AppliedExtraResources {
+		if containsExtraResourceReference(applied, reference) {
+			continue
+		}
+		if err := r.deleteExtraResource(reference); err != nil {
+			return err
+		}
+	}
+
+	if reflect.DeepEqual(applied, r.Configuration.Jenkins.Status.AppliedExtraResources) {
+		return nil
+	}
+	r.Configuration.Jenkins.Status.AppliedExtraResources = applied
+	return stackerr.WithStack(r.Client.Status().Update(context.TODO(), r.Configuration.Jenkins))
+}
+
+// getExtraResources returns objects from spec.extraResources in the Jenkins CR namespace
+func (r *JenkinsBaseConfigurationReconciler) getExtraResources() ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	for index, extraResource := range r.Configuration.Jenkins.Spec.ExtraResources {
+		if extraResource.Manifest != nil {
+			decoded, err := decodeExtraResources(string(extraResource.Manifest.Raw))
+			if err != nil {
+				return nil, stackerr.Wrapf(err, "invalid spec.extraResources[%d].manifest", index)
+			}
+			objects = append(objects, decoded...)
+		}
+		if extraResource.ConfigMapRef != nil {
+			configMap := &corev1.ConfigMap{}
+			err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.Configuration.Jenkins.Namespace, Name: extraResource.ConfigMapRef.Name}, configMap)
+			if err != nil {
+				return nil, stackerr.WithStack(err)
+			}
+			var keys []string
+			for key := range configMap.Data {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				decoded, err := decodeExtraResources(configMap.Data[key])
+				if err != nil {
+					return nil, stackerr.Wrapf(err, "invalid manifest '%s' in ConfigMap '%s'", key, configMap.Name)
+				}
+				objects = append(objects, decoded...)
+			}
+		}
+	}
+
+	for _, object := range objects {
+		if err := validateExtraResourceKind(object); err != nil {
+			return nil, stackerr.Wrapf(err, "invalid extra resource '%s'", object.GetName())
+		}
+		object.SetNamespace(r.Configuration.Jenkins.Namespace)
+	}
+	return objects, nil
+}
+
+// decodeExtraResources decodes objects from YAML or JSON manifests, YAML may contain multiple documents
+func decodeExtraResources(manifests string) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(manifests), 4096)
+	for {
+		content := map[string]interface{}{}
+		if err := decoder.Decode(&content); err == io.EOF {
+			return objects, nil
+		} else if err != nil {
+			return nil, stackerr.WithStack(err)
+		}
+		if len(content) == 0 {
+			continue
+		}
+
+		object := &unstructured.Unstructured{Object: content}
+		if len(object.GetAPIVersion()) == 0 || len(object.GetKind()) == 0 || len(object.GetName()) == 0 {
+			return nil, stackerr.New("apiVersion, kind and metadata.name are required")
+		}
+		objects = append(objects, object)
+	}
+}
+
+func (r *JenkinsBaseConfigurationReconciler) applyExtraResource(object *unstructured.Unstructured) error {
+	object.SetLabels(resources.MergeMaps(r.Configuration.Jenkins.Spec.CommonLabels, object.GetLabels(), resources.BuildResourceLabels(r.Configuration.Jenkins)))
+	content, err := json.Marshal(object.Object)
+	if err != nil {
+		return stackerr.WithStack(err)
+	}
+	hash := sha256.Sum256(content)
+	object.SetAnnotations(resources.MergeMaps(object.GetAnnotations(), map[string]string{
+		extraResourceHashAnnotation: base64.StdEncoding.EncodeToString(hash[:]),
+	}))
+
+	found := &unstructured.Unstructured{}
+	found.SetGroupVersionKind(object.GroupVersionKind())
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Namespace: object.GetNamespace(), Name: object.GetName()}, found)
+	if err != nil && apierrors.IsNotFound(err) {
+		r.logger.Info(fmt.Sprintf("Creating extra resource %s '%s'", object.GetKind(), object.GetName()))
+		return stackerr.WithStack(r.CreateResource(object))
+	} else if err != nil {
+		return stackerr.WithStack(err)
+	}
+
+	if found.GetAnnotations()[extraResourceHashAnnotation] == object.GetAnnotations()[extraResourceHashAnnotation] {
+		return nil
+	}
+	r.logger.Info(fmt.Sprintf("Updating extra resource %s '%s'", object.GetKind(), object.GetName()))
+	object.SetResourceVersion(found.GetResourceVersion())
+	return stackerr.WithStack(r.UpdateResource(object))
+}
+
+// deleteExtraResource deletes the object removed from spec.extraResources if it's still owned by the Jenkins CR
+func (r *JenkinsBaseConfigurationReconciler) deleteExtraResource(reference v1alpha2.ExtraResourceReference) error {
+	object := &unstructured.Unstructured{}
+	object.SetAPIVersion(reference.APIVersion)
+	object.SetKind(reference.Kind)
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.Configuration.Jenkins.Namespace, Name: reference.Name}, object)
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return stackerr.WithStack(err)
+	}
+	if !metav1.IsControlledBy(object, r.Configuration.Jenkins) {
+		return nil
+	}
+
+	r.logger.Info(fmt.Sprintf("Deleting extra resource %s '%s'", reference.Kind, reference.Name))
+	err = r.Client.Delete(context.TODO(), object)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return stackerr.WithStack(err)
+	}
+	return nil
+}
+
+func containsExtraResourceReference(references []v1alpha2.ExtraResourceReference, reference v1alpha2.ExtraResourceReference) bool {
+	for _, r := range references {
+		if r == reference {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,573 @@
+package base
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/constants"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	docker "github.com/docker/distribution/reference"
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// imageArchitecturesCacheTTL is how long the architectures read from the registry are reused, tags can be pushed again
+	imageArchitecturesCacheTTL = time.Hour
+	// imageInspectionRetryInterval delays the next registry request for an image which couldn't be inspected
+	imageInspectionRetryInterval = 5 * time.Minute
+	registryTimeout              = 30 * time.Second
+	// nodeListCacheTTL is how long the list of nodes is reused by validation of all Jenkins CRs
+	nodeListCacheTTL = 5 * time.Minute
+	// maxRegistryResponseSize limits manifests and image configs read from the registry
+	maxRegistryResponseSize = 4 << 20
+
+	dockerHubDomain       = "docker.io"
+	dockerHubRegistry     = "registry-1.docker.io"
+	nodeArchitectureLabel = "kubernetes.io/arch"
+
+	imageArchitectureUnsupportedEventReason k8sevent.Reason = "ImageArchitectureUnsupported"
+)
+
+// manifestMediaTypes are accepted when the image manifest is requested, manifest lists are preferred
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+var authChallengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// imageManifest is the subset of manifest list and image manifest fields needed to read the image architectures
+type imageManifest struct {
+	Manifests []struct {
+		Platform struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+		} `json:"platform"`
+	} `json:"manifests"`
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+}
+
+// imageConfig is the subset of image config fields needed to read the architecture of a single platform image
+type imageConfig struct {
+	Architecture string `json:"architecture"`
+}
+
+type registryCredentials struct {
+	username string
+	password string
+}
+
+// registryClient reads image manifests with the Docker Registry HTTP API V2
+type registryClient struct {
+	httpClient *http.Client
+	// credentials are keyed by the registry domain, e.g. docker.io
+	credentials map[string]registryCredentials
+}
+
+// imageArchitectures returns the sorted linux architectures the image is published for
+func (c *registryClient) imageArchitectures(ctx context.Context, image string) ([]string, error) {
+	named, err := docker.ParseNormalizedNamed(image)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	named = docker.TagNameOnly(named)
+	domain, repository := docker.Domain(named), docker.Path(named)
+	host := domain
+	if domain == dockerHubDomain {
+		host = dockerHubRegistry
+	}
+	var manifestReference string
+	if digested, ok := named.(docker.Digested); ok {
+		manifestReference = digested.Digest().String()
+	} else if tagged, ok := named.(docker.Tagged); ok {
+		manifestReference = tagged.Tag()
+	}
+
+	baseURL := fmt.Sprintf("https://%s/v2/%s", host, repository)
+	content, err := c.get(ctx, baseURL+"/manifests/"+manifestReference, domain, repository, manifestMediaTypes)
+	if err != nil {
+		return nil, err
+	}
+	manifest := imageManifest{}
+	if err = json.Unmarshal(content, &manifest); err != nil {
+		return nil, stackerr.Wrapf(err, "invalid manifest of image '%s'", image)
+	}
+
+	if len(manifest.Manifests) > 0 {
+		var architectures []string
+		for _, platformManifest := range manifest.Manifests {
+			// attestation manifests have unknown platform
+			if platformManifest.Platform.OS == "linux" {
+				architectures = append(architectures, platformManifest.Platform.Architecture)
+			}
+		}
+		return uniqueSortedStrings(architectures), nil
+	}
+	if len(manifest.Config.Digest) == 0 {
+		return nil, stackerr.Errorf("manifest of image '%s' has neither platforms nor config", image)
+	}
+	content, err = c.get(ctx, baseURL+"/blobs/"+manifest.Config.Digest, domain, repository, nil)
+	if err != nil {
+		return nil, err
+	}
+	config := imageConfig{}
+	if err = json.Unmarshal(content, &config); err != nil {
+		return nil, stackerr.Wrapf(err, "invalid config of image '%s'", image)
+	}
+	return []string{config.Architecture}, nil
+}
+
+// get requests the registry and authorizes the request again if the registry asks for credentials or a token
+func (c *registryClient) get(ctx context.Context, requestURL, domain, repository string, accept []string) ([]byte, error) {
+	response, err := c.do(ctx, requestURL, accept, "")
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusUnauthorized {
+		_ = response.Body.Close()
+		authorization, err := c.authorize(ctx, response.Header.Get("WWW-Authenticate"), domain, repository)
+		if err != nil {
+			return nil, err
+		}
+		response, err = c.do(ctx, requestURL, accept, authorization)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, stackerr.Errorf("registry responded '%s' to '%s'", response.Status, requestURL)
+	}
+	content, err := ioutil.ReadAll(io.LimitReader(response.Body, maxRegistryResponseSize))
+	return content, stackerr.WithStack(err)
+}
+
+func (c *registryClient) do(ctx context.Context, requestURL string, accept []string, authorization string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	if len(accept) > 0 {
+		request.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	if len(authorization) > 0 {
+		request.Header.Set("Authorization", authorization)
+	}
+	response, err := c.httpClient.Do(request)
+	return response, stackerr.WithStack(err)
+}
+
+// authorize returns the Authorization header value for the registry challenge, the bearer token is requested
+// anonymously if there are no credentials of the registry
+func (c *registryClient) authorize(ctx context.Context, challenge, domain, repository string) (string, error) {
+	credentials, found := c.credentials[domain]
+	scheme, params := parseAuthChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if !found {
+			return "", stackerr.Errorf("registry '%s' requires credentials", domain)
+		}
+		return "Basic " + basicAuth(credentials), nil
+	case "bearer":
+	default:
+		return "", stackerr.Errorf("unsupported registry '%s' authentication challenge '%s'", domain, challenge)
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil || len(params["realm"]) == 0 {
+		return "", stackerr.Errorf("invalid registry '%s' authentication realm '%s'", domain, params["realm"])
+	}
+	query := tokenURL.Query()
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if len(scope) == 0 {
+		scope = fmt.Sprintf("repository:%s:pull", repository)
+	}
+	query.Set("scope", scope)
+	tokenURL.RawQuery = query.Encode()
+
+	authorization := ""
+	if found {
+		authorization = "Basic " + basicAuth(credentials)
+	}
+	response, err := c.do(ctx, tokenURL.String(), nil, authorization)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode != http.StatusOK {
+		return "", stackerr.Errorf("registry '%s' token request responded '%s'", domain, response.Status)
+	}
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err = json.NewDecoder(io.LimitReader(response.Body, maxRegistryResponseSize)).Decode(&token); err != nil {
+		return "", stackerr.WithStack(err)
+	}
+	if len(token.Token) == 0 {
+		token.Token = token.AccessToken
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// parseAuthChallenge returns the lower case scheme and the parameters of WWW-Authenticate header value
+func parseAuthChallenge(challenge string) (string, map[string]string) {
+	challenge = strings.TrimSpace(challenge)
+	scheme := challenge
+	if index := strings.Index(challenge, " "); index >= 0 {
+		scheme = challenge[:index]
+	}
+	params := map[string]string{}
+	for _, match := range authChallengeParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	return strings.ToLower(scheme), params
+}
+
+func basicAuth(credentials registryCredentials) string {
+	return base64.StdEncoding.EncodeToString([]byte(credentials.username + ":" + credentials.password))
+}
+
+// registryDomain normalizes the server of docker config, e.g. https://index.docker.io/v1/ is docker.io
+func registryDomain(server string) string {
+	domain := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	if index := strings.Index(domain, "/"); index >= 0 {
+		domain = domain[:index]
+	}
+	switch domain {
+	case "index.docker.io", dockerHubRegistry:
+		return dockerHubDomain
+	}
+	return domain
+}
+
+// imageArchitecturesCacheEntry holds the image architectures or nil if the image couldn't be inspected
+type imageArchitecturesCacheEntry struct {
+	architectures []string
+	expires       time.Time
+}
+
+// imageArchitecturesCache reuses the image architectures between reconcile loops, the registries aren't requested
+// every time the Jenkins CR is validated
+type imageArchitecturesCache struct {
+	mutex   sync.Mutex
+	entries map[string]imageArchitecturesCacheEntry
+}
+
+var imageArchitectures = imageArchitecturesCache{entries: map[string]imageArchitecturesCacheEntry{}}
+
+// get returns the image architectures, no architectures are returned when the image can't be inspected
+func (c *imageArchitecturesCache) get(ctx context.Context, registry *registryClient, image string) []string {
+	c.mutex.Lock()
+	entry, found := c.entries[image]
+	c.mutex.Unlock()
+	if found && time.Now().Before(entry.expires) {
+		return entry.architectures
+	}
+
+	architectures, err := registry.imageArchitectures(ctx, image)
+	entry = imageArchitecturesCacheEntry{architectures: architectures, expires: time.Now().Add(imageArchitecturesCacheTTL)}
+	if err != nil {
+		log.Log.V(log.VWarn).Info(fmt.Sprintf("Failed to inspect image '%s', its architectures aren't validated: %s", image, err))
+		entry = imageArchitecturesCacheEntry{expires: time.Now().Add(imageInspectionRetryInterval)}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries[image] = entry
+	return entry.architectures
+}
+
+// nodeListCache reuses the list of nodes between reconcile loops of all Jenkins CRs, nodes aren't listed every time
+// a Jenkins CR is validated
+type nodeListCache struct {
+	mutex   sync.Mutex
+	nodes   []corev1.Node
+	expires time.Time
+}
+
+var nodeList = &nodeListCache{}
+
+// get returns the cached nodes or lists them again when the cache has expired
+func (c *nodeListCache) get(list func() (*corev1.NodeList, error)) ([]corev1.Node, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if time.Now().Before(c.expires) {
+		return c.nodes, nil
+	}
+	nodes, err := list()
+	if err != nil {
+		return nil, err
+	}
+	c.nodes, c.expires = nodes.Items, time.Now().Add(nodeListCacheTTL)
+	return c.nodes, nil
+}
+
+// reportedImageArchitectureWarnings are the last warnings emitted for Jenkins CRs by namespace and name, the same
+// warnings aren't emitted again in every reconcile loop
+type reportedImageArchitectureWarnings struct {
+	mutex    sync.Mutex
+	warnings map[string]string
+}
+
+var imageArchitectureWarnings = &reportedImageArchitectureWarnings{warnings: map[string]string{}}
+
+// changed records the warnings of the Jenkins CR and returns true if they differ from the previous ones
+func (w *reportedImageArchitectureWarnings) changed(jenkins *v1alpha2.Jenkins, warnings []string) bool {
+	key := jenkins.Namespace + "/" + jenkins.Name
+	joined := strings.Join(warnings, "\n")
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.warnings[key] == joined {
+		return false
+	}
+	w.warnings[key] = joined
+	return true
+}
+
+// nodeArchitectures returns the sorted architectures of schedulable nodes matched by the node selector whose
+// NoSchedule and NoExecute taints are tolerated. Pod affinity isn't considered.
+func nodeArchitectures(nodes []corev1.Node, nodeSelector map[string]string, tolerations []corev1.Toleration) []string {
+	selector := labels.SelectorFromSet(nodeSelector)
+	var architectures []string
+	for _, node := range nodes {
+		if node.Spec.Unschedulable || !selector.Matches(labels.Set(node.Labels)) || !areTaintsTolerated(node.Spec.Taints, tolerations) {
+			continue
+		}
+		architecture := node.Labels[nodeArchitectureLabel]
+		if len(architecture) == 0 {
+			architecture = node.Status.NodeInfo.Architecture
+		}
+		if len(architecture) > 0 {
+			architectures = append(architectures, architecture)
+		}
+	}
+	return uniqueSortedStrings(architectures)
+}
+
+// areTaintsTolerated returns true if the taints which prevent scheduling are tolerated
+func areTaintsTolerated(taints []corev1.Taint, tolerations []corev1.Toleration) bool {
+	for i := range taints {
+		taint := &taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for _, toleration := range tolerations {
+			if toleration.ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// unsupportedArchitectures returns the node architectures the image isn't published for
+func unsupportedArchitectures(imageArchitectures, nodeArchitectures []string) []string {
+	supported := map[string]bool{}
+	for _, architecture := range imageArchitectures {
+		supported[architecture] = true
+	}
+	var unsupported []string
+	for _, architecture := range nodeArchitectures {
+		if !supported[architecture] {
+			unsupported = append(unsupported, architecture)
+		}
+	}
+	return unsupported
+}
+
+func uniqueSortedStrings(values []string) []string {
+	unique := map[string]bool{}
+	var result []string
+	for _, value := range values {
+		if !unique[value] {
+			unique[value] = true
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// checkImageArchitectures verifies if the images of Jenkins master pod and the seed job agent support the
+// architectures of the nodes they can be scheduled on, e.g. amd64 only image on arm64 node fails with exec format error.
+// The nodes can be matched by affinity which isn't considered, so the unsupported architectures are reported as
+// warning events instead of validation errors.
+func (r *JenkinsBaseConfigurationReconciler) checkImageArchitectures(jenkins *v1alpha2.Jenkins) error {
+	if !jenkins.Spec.Master.ValidateImageArchitectures {
+		return nil
+	}
+
+	nodes, err := nodeList.get(func() (*corev1.NodeList, error) {
+		return r.ClientSet.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	})
+	if apierrors.IsForbidden(err) {
+		r.logger.V(log.VWarn).Info("Image architectures aren't validated, the operator isn't allowed to list nodes")
+		return nil
+	} else if err != nil {
+		return stackerr.WithStack(err)
+	}
+	credentials, err := r.imagePullCredentials()
+	if err != nil {
+		return err
+	}
+	registry := &registryClient{httpClient: &http.Client{Timeout: registryTimeout}, credentials: credentials}
+
+	r.reportImageArchitectureWarnings(jenkins, getImageArchitectureWarnings(registry, jenkins, nodes))
+	return nil
+}
+
+// getImageArchitectureWarnings returns the containers and the seed job agent whose images don't support all
+// architectures of the nodes they can be scheduled on
+func getImageArchitectureWarnings(registry *registryClient, jenkins *v1alpha2.Jenkins, nodes []corev1.Node) []string {
+	var warnings []string
+	masterArchitectures := nodeArchitectures(nodes, jenkins.Spec.Master.NodeSelector, jenkins.Spec.Master.Tolerations)
+	for _, container := range append(jenkins.Spec.Master.InitContainers, jenkins.Spec.Master.Containers...) {
+		if msg := validateImageArchitecture(registry, container.Image, masterArchitectures); len(msg) > 0 {
+			warnings = append(warnings, fmt.Sprintf("Container `%s` - %s spec.master.nodeSelector", container.Name, msg))
+		}
+	}
+
+	if len(jenkins.Spec.SeedJobs) == 0 {
+		return warnings
+	}
+	agentImage, agentNodeSelector, agentTolerations, nodeSelectorField := seedJobAgentImage(jenkins)
+	agentArchitectures := nodeArchitectures(nodes, agentNodeSelector, agentTolerations)
+	if msg := validateImageArchitecture(registry, agentImage, agentArchitectures); len(msg) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Seed job agent - %s %s", msg, nodeSelectorField))
+	}
+	return warnings
+}
+
+// reportImageArchitectureWarnings logs the warnings and emits them as warning events when they have changed
+func (r *JenkinsBaseConfigurationReconciler) reportImageArchitectureWarnings(jenkins *v1alpha2.Jenkins, warnings []string) {
+	if !imageArchitectureWarnings.changed(jenkins, warnings) {
+		return
+	}
+	for _, warning := range warnings {
+		r.logger.V(log.VWarn).Info(warning)
+		if r.Configuration.Events != nil {
+			r.Configuration.Events.Emit(jenkins, k8sevent.TypeWarning, imageArchitectureUnsupportedEventReason, warning)
+		}
+	}
+}
+
+// validateImageArchitecture returns the message without the node selector field if the image doesn't support all
+// node architectures
+func validateImageArchitecture(registry *registryClient, image string, nodeArchitectures []string) string {
+	if len(nodeArchitectures) == 0 || len(image) == 0 {
+		return ""
+	}
+	architectures := imageArchitectures.get(context.TODO(), registry, image)
+	if len(architectures) == 0 {
+		return ""
+	}
+	unsupported := unsupportedArchitectures(architectures, nodeArchitectures)
+	if len(unsupported) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("image '%s' is published for '%s' only, it doesn't support '%s' architecture of nodes matched by",
+		image, strings.Join(architectures, ", "), strings.Join(unsupported, ", "))
+}
+
+// seedJobAgentImage returns the image, the node selector and the tolerations of the seed job agent the same way
+// the agent deployment is created
+func seedJobAgentImage(jenkins *v1alpha2.Jenkins) (string, map[string]string, []corev1.Toleration, string) {
+	image := jenkins.Spec.SeedJobAgentImage
+	if len(image) == 0 {
+		image = constants.DefaultSeedJobAgentImage
+	}
+	nodeSelector, nodeSelectorField := jenkins.Spec.Master.NodeSelector, "spec.master.nodeSelector"
+	tolerations := jenkins.Spec.Master.Tolerations
+	if template := jenkins.Spec.SeedJobAgentTemplate; template != nil {
+		if len(template.Image) > 0 {
+			image = template.Image
+		}
+		if len(template.NodeSelector) > 0 {
+			nodeSelector, nodeSelectorField = template.NodeSelector, "spec.seedJobAgentTemplate.nodeSelector"
+		}
+		if len(template.Tolerations) > 0 {
+			tolerations = template.Tolerations
+		}
+	}
+	return image, nodeSelector, tolerations, nodeSelectorField
+}
+
+// imagePullCredentials reads registry credentials of spec.master.imagePullSecrets, both docker config secrets
+// and secrets with docker-server, docker-username and docker-password keys are supported
+func (r *JenkinsBaseConfigurationReconciler) imagePullCredentials() (map[string]registryCredentials, error) {
+	credentials := map[string]registryCredentials{}
+	for _, reference := range r.Configuration.Jenkins.Spec.Master.ImagePullSecrets {
+		secret := &corev1.Secret{}
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: reference.Name, Namespace: r.Configuration.Jenkins.Namespace}, secret)
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, stackerr.WithStack(err)
+		}
+
+		if server, found := secret.Data["docker-server"]; found {
+			credentials[registryDomain(string(server))] = registryCredentials{
+				username: string(secret.Data["docker-username"]),
+				password: string(secret.Data["docker-password"]),
+			}
+		}
+		dockerConfig := struct {
+			Auths map[string]struct {
+				Username string `json:"username"`
+				Password string `json:"password"`
+				Auth     string `json:"auth"`
+			} `json:"auths"`
+		}{}
+		content, found := secret.Data[corev1.DockerConfigJsonKey]
+		if !found {
+			continue
+		}
+		if err = json.Unmarshal(content, &dockerConfig); err != nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid docker config in Secret '%s': %s", reference.Name, err))
+			continue
+		}
+		for server, auth := range dockerConfig.Auths {
+			username, password := auth.Username, auth.Password
+			if decoded, err := base64.StdEncoding.DecodeString(auth.Auth); err == nil && len(auth.Auth) > 0 {
+				if parts := strings.SplitN(string(decoded), ":", 2); len(parts) == 2 {
+					username, password = parts[0], parts[1]
+				}
+			}
+			credentials[registryDomain(server)] = registryCredentials{username: username, password: password}
+		}
+	}
+	return credentials, nil
+}
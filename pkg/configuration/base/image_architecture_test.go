@@ -0,0 +1,257 @@
+package base
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	manifestListContent = `{"manifests":[
+		{"platform":{"architecture":"arm64","os":"linux"}},
+		{"platform":{"architecture":"amd64","os":"linux"}},
+		{"platform":{"architecture":"unknown","os":"unknown"}}]}`
+	singleManifestContent = `{"config":{"digest":"sha256:config"}}`
+	imageConfigContent    = `{"architecture":"amd64","os":"linux"}`
+)
+
+// newFakeRegistry serves jenkins/multi-arch manifest list and jenkins/single-arch image manifest to the clients
+// with bearer token issued for basic credentials user:password
+func newFakeRegistry(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			username, password, ok := r.BasicAuth()
+			if !ok || username != "user" || password != "password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, "registry", r.URL.Query().Get("service"))
+			_, _ = fmt.Fprint(w, `{"token":"secret-token"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/jenkins/multi-arch/manifests/latest":
+			assert.Contains(t, r.Header.Get("Accept"), "application/vnd.docker.distribution.manifest.list.v2+json")
+			_, _ = fmt.Fprint(w, manifestListContent)
+		case "/v2/jenkins/single-arch/manifests/1.0":
+			_, _ = fmt.Fprint(w, singleManifestContent)
+		case "/v2/jenkins/single-arch/blobs/sha256:config":
+			_, _ = fmt.Fprint(w, imageConfigContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRegistryClient_ImageArchitectures(t *testing.T) {
+	server := newFakeRegistry(t)
+	domain := strings.TrimPrefix(server.URL, "https://")
+	registry := &registryClient{
+		httpClient:  server.Client(),
+		credentials: map[string]registryCredentials{domain: {username: "user", password: "password"}},
+	}
+
+	t.Run("manifest list", func(t *testing.T) {
+		architectures, err := registry.imageArchitectures(context.TODO(), domain+"/jenkins/multi-arch")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"amd64", "arm64"}, architectures)
+	})
+	t.Run("single platform image", func(t *testing.T) {
+		architectures, err := registry.imageArchitectures(context.TODO(), domain+"/jenkins/single-arch:1.0")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"amd64"}, architectures)
+	})
+	t.Run("image not found", func(t *testing.T) {
+		_, err := registry.imageArchitectures(context.TODO(), domain+"/jenkins/missing:1.0")
+
+		assert.Error(t, err)
+	})
+	t.Run("no credentials", func(t *testing.T) {
+		anonymous := &registryClient{httpClient: server.Client()}
+
+		_, err := anonymous.imageArchitectures(context.TODO(), domain+"/jenkins/multi-arch")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestParseAuthChallenge(t *testing.T) {
+	scheme, params := parseAuthChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:jenkins/jenkins:pull"`)
+
+	assert.Equal(t, "bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:jenkins/jenkins:pull",
+	}, params)
+}
+
+func TestNodeArchitectures(t *testing.T) {
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{nodeArchitectureLabel: "amd64", "pool": "default"}}},
+		{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{nodeArchitectureLabel: "arm64", "pool": "graviton"}}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"pool": "graviton"}},
+			Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{Architecture: "arm64"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{nodeArchitectureLabel: "s390x"}},
+			Spec:       corev1.NodeSpec{Unschedulable: true},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{nodeArchitectureLabel: "ppc64le", "pool": "gpu"}},
+			Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "gpu", Effect: corev1.TaintEffectNoSchedule}}},
+		},
+	}
+
+	t.Run("all nodes", func(t *testing.T) {
+		assert.Equal(t, []string{"amd64", "arm64"}, nodeArchitectures(nodes, nil, nil))
+	})
+	t.Run("node selector", func(t *testing.T) {
+		assert.Equal(t, []string{"arm64"}, nodeArchitectures(nodes, map[string]string{"pool": "graviton"}, nil))
+	})
+	t.Run("no matching nodes", func(t *testing.T) {
+		assert.Empty(t, nodeArchitectures(nodes, map[string]string{"pool": "gpu"}, nil))
+	})
+	t.Run("tolerated taint", func(t *testing.T) {
+		tolerations := []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}}
+
+		assert.Equal(t, []string{"ppc64le"}, nodeArchitectures(nodes, map[string]string{"pool": "gpu"}, tolerations))
+	})
+}
+
+func TestReportImageArchitectureWarnings(t *testing.T) {
+	log.SetupLogger(true)
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "architectures", Namespace: defaultNamespace}}
+	recorder := &fakeRecorder{}
+	r := JenkinsBaseConfigurationReconciler{
+		logger:        log.Log,
+		Configuration: configuration.Configuration{Jenkins: jenkins, Events: recorder},
+	}
+	warnings := []string{"Container `jenkins-master` - image 'jenkins/jenkins:amd64' is published for 'amd64' only"}
+
+	r.reportImageArchitectureWarnings(jenkins, warnings)
+
+	require.Len(t, recorder.events, 1)
+	assert.Equal(t, k8sevent.TypeWarning, recorder.events[0].eventType)
+	assert.Equal(t, imageArchitectureUnsupportedEventReason, recorder.events[0].reason)
+	assert.Equal(t, warnings[0], recorder.events[0].message)
+
+	t.Run("same warnings aren't emitted again", func(t *testing.T) {
+		r.reportImageArchitectureWarnings(jenkins, warnings)
+
+		assert.Len(t, recorder.events, 1)
+	})
+	t.Run("fixed image", func(t *testing.T) {
+		r.reportImageArchitectureWarnings(jenkins, nil)
+		r.reportImageArchitectureWarnings(jenkins, warnings)
+
+		assert.Len(t, recorder.events, 2)
+	})
+}
+
+func TestNodeListCache(t *testing.T) {
+	cache := &nodeListCache{}
+	calls := 0
+	list := func() (*corev1.NodeList, error) {
+		calls++
+		return &corev1.NodeList{Items: []corev1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node"}}}}, nil
+	}
+
+	nodes, err := cache.get(list)
+	require.NoError(t, err)
+	assert.Len(t, nodes, 1)
+	nodes, err = cache.get(list)
+	require.NoError(t, err)
+	assert.Len(t, nodes, 1)
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestValidateImageArchitecture(t *testing.T) {
+	log.SetupLogger(true)
+	server := newFakeRegistry(t)
+	domain := strings.TrimPrefix(server.URL, "https://")
+	registry := &registryClient{
+		httpClient:  server.Client(),
+		credentials: map[string]registryCredentials{domain: {username: "user", password: "password"}},
+	}
+
+	t.Run("supported", func(t *testing.T) {
+		assert.Empty(t, validateImageArchitecture(registry, domain+"/jenkins/multi-arch", []string{"arm64"}))
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		image := domain + "/jenkins/single-arch:1.0"
+
+		got := validateImageArchitecture(registry, image, []string{"amd64", "arm64"})
+
+		assert.Equal(t, fmt.Sprintf("image '%s' is published for 'amd64' only, it doesn't support 'arm64' architecture of nodes matched by", image), got)
+	})
+	t.Run("image can't be inspected", func(t *testing.T) {
+		assert.Empty(t, validateImageArchitecture(registry, domain+"/jenkins/missing:1.0", []string{"arm64"}))
+	})
+}
+
+func TestImagePullCredentials(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: defaultNamespace},
+		Spec: v1alpha2.JenkinsSpec{
+			Master: v1alpha2.JenkinsMaster{
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "docker-config"}, {Name: "docker-server"}, {Name: "missing"}},
+			},
+		},
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte("hub-user:hub-password"))
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "docker-config", Namespace: defaultNamespace},
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{
+				"https://index.docker.io/v1/":{"auth":"` + auth + `"},
+				"quay.io":{"username":"quay-user","password":"quay-password"}}}`)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "docker-server", Namespace: defaultNamespace},
+			Data: map[string][]byte{
+				"docker-server":   []byte("registry.local:5000"),
+				"docker-username": []byte("local-user"),
+				"docker-password": []byte("local-password"),
+			},
+		},
+	).Build()
+	reconciler := New(configuration.Configuration{Client: fakeClient, Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+
+	credentials, err := reconciler.imagePullCredentials()
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]registryCredentials{
+		"docker.io":           {username: "hub-user", password: "hub-password"},
+		"quay.io":             {username: "quay-user", password: "quay-password"},
+		"registry.local:5000": {username: "local-user", password: "local-password"},
+	}, credentials)
+}
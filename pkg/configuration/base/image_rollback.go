@@ -0,0 +1,239 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// imageRollbackCheckInterval is the requeue delay while Jenkins home snapshot is taken or the claim is recreated
+	imageRollbackCheckInterval = 5 * time.Second
+	// imageRollbackSnapshotTimeout is how long Jenkins master pod waits for Jenkins home snapshot, the image is upgraded
+	// without the snapshot afterwards
+	imageRollbackSnapshotTimeout = 10 * time.Minute
+
+	imageRollbackSkippedEventReason k8sevent.Reason = "ImageRollbackSkipped"
+)
+
+// isImageRollbackConfigured returns true if Jenkins home snapshot is taken before Jenkins master image upgrade
+func isImageRollbackConfigured(jenkins *v1alpha2.Jenkins) bool {
+	return jenkins.Spec.Master.ImageRollback != nil && jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim != nil
+}
+
+// prepareImageRollback records Jenkins master image upgrade or revert in status before Jenkins master pod is restarted,
+// the snapshot is taken or Jenkins home is recreated from it once the pod is deleted
+func (r *JenkinsBaseConfigurationReconciler) prepareImageRollback(currentJenkinsMasterPod corev1.Pod) error {
+	jenkins := r.Configuration.Jenkins
+	if !isImageRollbackConfigured(jenkins) {
+		return nil
+	}
+	var currentImage string
+	for _, container := range currentJenkinsMasterPod.Spec.Containers {
+		if container.Name == resources.JenkinsMasterContainerName {
+			currentImage = container.Image
+		}
+	}
+	masterContainer := r.Configuration.GetJenkinsMasterContainer()
+	if masterContainer == nil || len(currentImage) == 0 || currentImage == masterContainer.Image {
+		return nil
+	}
+	image := masterContainer.Image
+
+	status := jenkins.Status.ImageRollback
+	reverted := status != nil && status.PreviousImage == image && status.Image == currentImage
+	switch {
+	case reverted && status.Phase == v1alpha2.ImageRollbackRollingBack:
+		return nil
+	case reverted && status.Phase == v1alpha2.ImageRollbackAvailable && !isImageRollbackExpired(status):
+		r.logger.Info(fmt.Sprintf("Jenkins master image has been reverted to '%s', Jenkins home will be recreated from VolumeSnapshot '%s'",
+			image, status.VolumeSnapshotName))
+		status.Phase = v1alpha2.ImageRollbackRollingBack
+	case status != nil && status.Phase == v1alpha2.ImageRollbackSnapshotting && status.PreviousImage == currentImage && status.Image == image:
+		return nil
+	default:
+		if err := r.deleteJenkinsHomeVolumeSnapshot(); err != nil {
+			return err
+		}
+		r.logger.Info(fmt.Sprintf("Jenkins master image is upgraded from '%s' to '%s', Jenkins home snapshot will be taken", currentImage, image))
+		jenkins.Status.ImageRollback = &v1alpha2.ImageRollbackStatus{
+			Phase:              v1alpha2.ImageRollbackSnapshotting,
+			PreviousImage:      currentImage,
+			Image:              image,
+			VolumeSnapshotName: resources.GetJenkinsHomeVolumeSnapshotName(jenkins),
+		}
+	}
+	return stackerr.WithStack(r.Client.Status().Update(context.TODO(), jenkins))
+}
+
+// ensureImageRollbackBeforePodCreation takes Jenkins home snapshot after Jenkins master pod with the previous image has
+// been deleted or recreates Jenkins home from the snapshot when the image has been reverted, it returns true if Jenkins
+// home has been rolled back. The image is upgraded without the snapshot when VolumeSnapshot CRD isn't installed or the
+// snapshot isn't taken in time.
+func (r *JenkinsBaseConfigurationReconciler) ensureImageRollbackBeforePodCreation() (reconcile.Result, bool, error) {
+	jenkins := r.Configuration.Jenkins
+	status := jenkins.Status.ImageRollback
+	if !isImageRollbackConfigured(jenkins) || status == nil {
+		return reconcile.Result{}, false, nil
+	}
+
+	switch status.Phase {
+	case v1alpha2.ImageRollbackSnapshotting:
+		snapshot, err := r.getJenkinsHomeVolumeSnapshot()
+		if meta.IsNoMatchError(stackerr.Cause(err)) {
+			return reconcile.Result{}, false, r.skipImageRollback("VolumeSnapshot CRD isn't installed")
+		} else if err != nil {
+			return reconcile.Result{}, false, err
+		}
+		if snapshot == nil {
+			r.logger.Info(fmt.Sprintf("Creating Jenkins home VolumeSnapshot '%s'", status.VolumeSnapshotName))
+			err = r.CreateResource(resources.NewJenkinsHomeVolumeSnapshot(resources.NewResourceObjectMeta(jenkins), jenkins))
+			if meta.IsNoMatchError(stackerr.Cause(err)) {
+				return reconcile.Result{}, false, r.skipImageRollback("VolumeSnapshot CRD isn't installed")
+			}
+			return reconcile.Result{Requeue: true, RequeueAfter: imageRollbackCheckInterval}, false, stackerr.WithStack(err)
+		}
+		if snapshot.GetDeletionTimestamp() != nil {
+			// the snapshot of the previous upgrade is being deleted
+			return reconcile.Result{Requeue: true, RequeueAfter: imageRollbackCheckInterval}, false, nil
+		}
+		if message, found, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); found {
+			return reconcile.Result{}, false, r.skipImageRollback(fmt.Sprintf("VolumeSnapshot '%s' failed: %s", status.VolumeSnapshotName, message))
+		}
+		if _, found, _ := unstructured.NestedString(snapshot.Object, "status", "creationTime"); !found {
+			if created := snapshot.GetCreationTimestamp(); time.Since(created.Time) > imageRollbackSnapshotTimeout {
+				return reconcile.Result{}, false, r.skipImageRollback(fmt.Sprintf("VolumeSnapshot '%s' hasn't been taken in %s",
+					status.VolumeSnapshotName, imageRollbackSnapshotTimeout))
+			}
+			// Jenkins master pod mustn't write to the volume before the snapshot is taken
+			return reconcile.Result{Requeue: true, RequeueAfter: imageRollbackCheckInterval}, false, nil
+		}
+		expirationTime := metav1.NewTime(time.Now().Add(jenkins.Spec.Master.ImageRollback.Window.Duration))
+		status.Phase = v1alpha2.ImageRollbackAvailable
+		status.ExpirationTime = &expirationTime
+		r.logger.Info(fmt.Sprintf("Jenkins home VolumeSnapshot '%s' has been taken, image '%s' can be restored until %s",
+			status.VolumeSnapshotName, status.PreviousImage, expirationTime.Format(time.RFC3339)))
+		return reconcile.Result{}, false, stackerr.WithStack(r.Client.Status().Update(context.TODO(), jenkins))
+	case v1alpha2.ImageRollbackRollingBack:
+		claim := &corev1.PersistentVolumeClaim{}
+		name := resources.GetJenkinsHomePersistentVolumeClaimName(jenkins)
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: name}, claim)
+		if apierrors.IsNotFound(err) {
+			// the claim is created from the snapshot in the next reconcile loop
+			return reconcile.Result{Requeue: true}, false, nil
+		} else if err != nil {
+			return reconcile.Result{}, false, stackerr.WithStack(err)
+		}
+		if claim.DeletionTimestamp != nil {
+			return reconcile.Result{Requeue: true, RequeueAfter: imageRollbackCheckInterval}, false, nil
+		}
+		if dataSource := claim.Spec.DataSource; dataSource == nil || dataSource.Kind != resources.VolumeSnapshotKind || dataSource.Name != status.VolumeSnapshotName {
+			r.logger.Info(fmt.Sprintf("Deleting Jenkins home PersistentVolumeClaim '%s' to recreate it from VolumeSnapshot '%s'", name, status.VolumeSnapshotName))
+			if err = r.Client.Delete(context.TODO(), claim); err != nil && !apierrors.IsNotFound(err) {
+				return reconcile.Result{}, false, stackerr.WithStack(err)
+			}
+			return reconcile.Result{Requeue: true, RequeueAfter: imageRollbackCheckInterval}, false, nil
+		}
+		r.logger.Info(fmt.Sprintf("Jenkins home has been recreated from VolumeSnapshot '%s'", status.VolumeSnapshotName))
+		status.Phase = v1alpha2.ImageRollbackRolledBack
+		return reconcile.Result{}, true, stackerr.WithStack(r.Client.Status().Update(context.TODO(), jenkins))
+	}
+	return reconcile.Result{}, false, nil
+}
+
+// skipImageRollback upgrades Jenkins master image without Jenkins home snapshot, so the pod isn't blocked by the snapshot
+// which can't be taken, the image can't be rolled back then
+func (r *JenkinsBaseConfigurationReconciler) skipImageRollback(cause string) error {
+	jenkins := r.Configuration.Jenkins
+	status := jenkins.Status.ImageRollback
+	message := fmt.Sprintf("Jenkins master image is upgraded from '%s' to '%s' without Jenkins home snapshot, it can't be rolled back: %s",
+		status.PreviousImage, status.Image, cause)
+	r.logger.Info(message)
+	if r.Configuration.Events != nil {
+		r.Configuration.Events.Emit(jenkins, k8sevent.TypeWarning, imageRollbackSkippedEventReason, message)
+	}
+
+	if err := r.deleteJenkinsHomeVolumeSnapshot(); err != nil {
+		return err
+	}
+	jenkins.Status.ImageRollback = nil
+	return stackerr.WithStack(r.Client.Status().Update(context.TODO(), jenkins))
+}
+
+// ensureImageRollbackExpiration deletes Jenkins home snapshot when the rollback window has passed or spec.master.imageRollback
+// has been removed
+func (r *JenkinsBaseConfigurationReconciler) ensureImageRollbackExpiration() error {
+	jenkins := r.Configuration.Jenkins
+	status := jenkins.Status.ImageRollback
+	if status == nil {
+		return nil
+	}
+	if isImageRollbackConfigured(jenkins) && (!isImageRollbackExpired(status) ||
+		status.Phase == v1alpha2.ImageRollbackSnapshotting || status.Phase == v1alpha2.ImageRollbackRollingBack) {
+		return nil
+	}
+
+	if err := r.deleteJenkinsHomeVolumeSnapshot(); err != nil {
+		return err
+	}
+	jenkins.Status.ImageRollback = nil
+	return stackerr.WithStack(r.Client.Status().Update(context.TODO(), jenkins))
+}
+
+// jenkinsHomeVolumeSnapshotDataSource returns the snapshot which Jenkins home PersistentVolumeClaim is created from
+// when Jenkins master image is being rolled back
+func jenkinsHomeVolumeSnapshotDataSource(jenkins *v1alpha2.Jenkins) *corev1.TypedLocalObjectReference {
+	status := jenkins.Status.ImageRollback
+	if !isImageRollbackConfigured(jenkins) || status == nil || status.Phase != v1alpha2.ImageRollbackRollingBack {
+		return nil
+	}
+	return resources.NewJenkinsHomeVolumeSnapshotDataSource(status.VolumeSnapshotName)
+}
+
+func isImageRollbackExpired(status *v1alpha2.ImageRollbackStatus) bool {
+	return status.ExpirationTime != nil && time.Now().After(status.ExpirationTime.Time)
+}
+
+func (r *JenkinsBaseConfigurationReconciler) getJenkinsHomeVolumeSnapshot() (*unstructured.Unstructured, error) {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetAPIVersion(resources.VolumeSnapshotAPIVersion)
+	snapshot.SetKind(resources.VolumeSnapshotKind)
+	name := resources.GetJenkinsHomeVolumeSnapshotName(r.Configuration.Jenkins)
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.Configuration.Jenkins.Namespace, Name: name}, snapshot)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	return snapshot, nil
+}
+
+func (r *JenkinsBaseConfigurationReconciler) deleteJenkinsHomeVolumeSnapshot() error {
+	snapshot, err := r.getJenkinsHomeVolumeSnapshot()
+	if meta.IsNoMatchError(stackerr.Cause(err)) {
+		return nil
+	}
+	if err != nil || snapshot == nil || !metav1.IsControlledBy(snapshot, r.Configuration.Jenkins) {
+		return err
+	}
+
+	r.logger.Info(fmt.Sprintf("Deleting Jenkins home VolumeSnapshot '%s'", snapshot.GetName()))
+	err = r.Client.Delete(context.TODO(), snapshot)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return stackerr.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package base
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestImageRollback(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	const previousImage, image = "jenkins/jenkins:2.263.1-lts", "jenkins/jenkins:2.277.1-lts"
+	newJenkins := func(status *v1alpha2.ImageRollbackStatus) *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: defaultNamespace},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					Containers:                       []v1alpha2.Container{{Name: resources.JenkinsMasterContainerName, Image: image}},
+					JenkinsHomePersistentVolumeClaim: &v1alpha2.JenkinsHomePersistentVolumeClaim{Size: resource.MustParse("10Gi")},
+					ImageRollback:                    &v1alpha2.ImageRollback{Window: metav1.Duration{Duration: time.Hour}},
+				},
+			},
+			Status: v1alpha2.JenkinsStatus{ImageRollback: status},
+		}
+	}
+	newPod := func(image string) corev1.Pod {
+		return corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: resources.JenkinsMasterContainerName, Image: image}}}}
+	}
+	newReconciler := func(jenkins *v1alpha2.Jenkins, objects ...k8sclient.Object) *JenkinsBaseConfigurationReconciler {
+		return New(configuration.Configuration{
+			Client:  fake.NewClientBuilder().WithObjects(append(objects, jenkins)...).Build(),
+			Jenkins: jenkins,
+			Scheme:  scheme.Scheme,
+		}, client.JenkinsAPIConnectionSettings{})
+	}
+	getSnapshot := func(t *testing.T, reconciler *JenkinsBaseConfigurationReconciler) *unstructured.Unstructured {
+		snapshot, err := reconciler.getJenkinsHomeVolumeSnapshot()
+		require.NoError(t, err)
+		return snapshot
+	}
+	available := func() *v1alpha2.ImageRollbackStatus {
+		expirationTime := metav1.NewTime(time.Now().Add(time.Minute))
+		return &v1alpha2.ImageRollbackStatus{
+			Phase:              v1alpha2.ImageRollbackAvailable,
+			PreviousImage:      previousImage,
+			Image:              image,
+			VolumeSnapshotName: "jenkins-operator-home-jenkins-rollback",
+			ExpirationTime:     &expirationTime,
+		}
+	}
+
+	t.Run("upgrade", func(t *testing.T) {
+		jenkins := newJenkins(nil)
+		reconciler := newReconciler(jenkins)
+
+		require.NoError(t, reconciler.prepareImageRollback(newPod(previousImage)))
+
+		require.NotNil(t, jenkins.Status.ImageRollback)
+		assert.Equal(t, v1alpha2.ImageRollbackSnapshotting, jenkins.Status.ImageRollback.Phase)
+		assert.Equal(t, previousImage, jenkins.Status.ImageRollback.PreviousImage)
+		assert.Equal(t, image, jenkins.Status.ImageRollback.Image)
+	})
+	t.Run("take snapshot", func(t *testing.T) {
+		jenkins := newJenkins(&v1alpha2.ImageRollbackStatus{
+			Phase:              v1alpha2.ImageRollbackSnapshotting,
+			PreviousImage:      previousImage,
+			Image:              image,
+			VolumeSnapshotName: "jenkins-operator-home-jenkins-rollback",
+		})
+		reconciler := newReconciler(jenkins)
+
+		result, rolledBack, err := reconciler.ensureImageRollbackBeforePodCreation()
+
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+		assert.False(t, rolledBack)
+		snapshot := getSnapshot(t, reconciler)
+		require.NotNil(t, snapshot)
+		claimName, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+		assert.Equal(t, "jenkins-operator-home-jenkins", claimName)
+
+		require.NoError(t, unstructured.SetNestedField(snapshot.Object, "2021-03-01T10:00:00Z", "status", "creationTime"))
+		require.NoError(t, reconciler.Client.Update(context.TODO(), snapshot))
+		result, rolledBack, err = reconciler.ensureImageRollbackBeforePodCreation()
+
+		require.NoError(t, err)
+		assert.False(t, result.Requeue)
+		assert.False(t, rolledBack)
+		assert.Equal(t, v1alpha2.ImageRollbackAvailable, jenkins.Status.ImageRollback.Phase)
+		require.NotNil(t, jenkins.Status.ImageRollback.ExpirationTime)
+	})
+	snapshotting := func() *v1alpha2.ImageRollbackStatus {
+		return &v1alpha2.ImageRollbackStatus{
+			Phase:              v1alpha2.ImageRollbackSnapshotting,
+			PreviousImage:      previousImage,
+			Image:              image,
+			VolumeSnapshotName: "jenkins-operator-home-jenkins-rollback",
+		}
+	}
+	t.Run("snapshot not taken in time", func(t *testing.T) {
+		jenkins := newJenkins(snapshotting())
+		snapshot := resources.NewJenkinsHomeVolumeSnapshot(resources.NewResourceObjectMeta(jenkins), jenkins)
+		snapshot.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-imageRollbackSnapshotTimeout - time.Minute)))
+		require.NoError(t, controllerutil.SetControllerReference(jenkins, snapshot, scheme.Scheme))
+		reconciler := newReconciler(jenkins, snapshot)
+		recorder := &fakeRecorder{}
+		reconciler.Configuration.Events = recorder
+
+		result, rolledBack, err := reconciler.ensureImageRollbackBeforePodCreation()
+
+		require.NoError(t, err)
+		assert.False(t, result.Requeue)
+		assert.False(t, rolledBack)
+		assert.Nil(t, jenkins.Status.ImageRollback)
+		assert.Nil(t, getSnapshot(t, reconciler))
+		require.Len(t, recorder.events, 1)
+		assert.Equal(t, k8sevent.TypeWarning, recorder.events[0].eventType)
+		assert.Equal(t, imageRollbackSkippedEventReason, recorder.events[0].reason)
+	})
+	t.Run("snapshot failed", func(t *testing.T) {
+		jenkins := newJenkins(snapshotting())
+		snapshot := resources.NewJenkinsHomeVolumeSnapshot(resources.NewResourceObjectMeta(jenkins), jenkins)
+		snapshot.SetCreationTimestamp(metav1.Now())
+		require.NoError(t, unstructured.SetNestedField(snapshot.Object, "no snapshot class", "status", "error", "message"))
+		reconciler := newReconciler(jenkins, snapshot)
+
+		result, _, err := reconciler.ensureImageRollbackBeforePodCreation()
+
+		require.NoError(t, err)
+		assert.False(t, result.Requeue)
+		assert.Nil(t, jenkins.Status.ImageRollback)
+	})
+	t.Run("VolumeSnapshot CRD not installed", func(t *testing.T) {
+		jenkins := newJenkins(snapshotting())
+		reconciler := newReconciler(jenkins)
+		reconciler.Client = &noVolumeSnapshotClient{Client: reconciler.Client}
+		recorder := &fakeRecorder{}
+		reconciler.Configuration.Events = recorder
+
+		result, rolledBack, err := reconciler.ensureImageRollbackBeforePodCreation()
+
+		require.NoError(t, err)
+		assert.False(t, result.Requeue)
+		assert.False(t, rolledBack)
+		assert.Nil(t, jenkins.Status.ImageRollback)
+		require.Len(t, recorder.events, 1)
+		assert.Contains(t, recorder.events[0].message, "VolumeSnapshot CRD isn't installed")
+	})
+	t.Run("revert", func(t *testing.T) {
+		jenkins := newJenkins(available())
+		jenkins.Spec.Master.Containers[0].Image = previousImage
+		reconciler := newReconciler(jenkins)
+
+		require.NoError(t, reconciler.prepareImageRollback(newPod(image)))
+
+		assert.Equal(t, v1alpha2.ImageRollbackRollingBack, jenkins.Status.ImageRollback.Phase)
+	})
+	t.Run("revert after window", func(t *testing.T) {
+		status := available()
+		status.ExpirationTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		jenkins := newJenkins(status)
+		jenkins.Spec.Master.Containers[0].Image = previousImage
+		reconciler := newReconciler(jenkins)
+
+		require.NoError(t, reconciler.prepareImageRollback(newPod(image)))
+
+		assert.Equal(t, v1alpha2.ImageRollbackSnapshotting, jenkins.Status.ImageRollback.Phase)
+		assert.Equal(t, image, jenkins.Status.ImageRollback.PreviousImage)
+	})
+	t.Run("recreate claim from snapshot", func(t *testing.T) {
+		status := available()
+		status.Phase = v1alpha2.ImageRollbackRollingBack
+		jenkins := newJenkins(status)
+		claim := resources.NewJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins), jenkins)
+		reconciler := newReconciler(jenkins, claim)
+
+		result, rolledBack, err := reconciler.ensureImageRollbackBeforePodCreation()
+
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+		assert.False(t, rolledBack)
+		err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Namespace: defaultNamespace, Name: claim.Name}, &corev1.PersistentVolumeClaim{})
+		assert.True(t, apierrors.IsNotFound(err))
+
+		require.NoError(t, reconciler.ensureJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins)))
+		restored := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, reconciler.Client.Get(context.TODO(), types.NamespacedName{Namespace: defaultNamespace, Name: claim.Name}, restored))
+		require.NotNil(t, restored.Spec.DataSource)
+		assert.Equal(t, resources.VolumeSnapshotKind, restored.Spec.DataSource.Kind)
+		assert.Equal(t, status.VolumeSnapshotName, restored.Spec.DataSource.Name)
+
+		result, rolledBack, err = reconciler.ensureImageRollbackBeforePodCreation()
+
+		require.NoError(t, err)
+		assert.False(t, result.Requeue)
+		assert.True(t, rolledBack)
+		assert.Equal(t, v1alpha2.ImageRollbackRolledBack, jenkins.Status.ImageRollback.Phase)
+	})
+	t.Run("expiration", func(t *testing.T) {
+		status := available()
+		status.ExpirationTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		jenkins := newJenkins(status)
+		reconciler := newReconciler(jenkins)
+		snapshot := resources.NewJenkinsHomeVolumeSnapshot(resources.NewResourceObjectMeta(jenkins), jenkins)
+		require.NoError(t, reconciler.CreateResource(snapshot))
+
+		require.NoError(t, reconciler.ensureImageRollbackExpiration())
+
+		assert.Nil(t, jenkins.Status.ImageRollback)
+		assert.Nil(t, getSnapshot(t, reconciler))
+	})
+}
+
+// noVolumeSnapshotClient behaves like the cluster without VolumeSnapshot CRD
+type noVolumeSnapshotClient struct {
+	k8sclient.Client
+}
+
+func (c *noVolumeSnapshotClient) Get(ctx context.Context, key k8sclient.ObjectKey, obj k8sclient.Object) error {
+	if obj.GetObjectKind().GroupVersionKind().Kind == resources.VolumeSnapshotKind {
+		return &meta.NoKindMatchError{GroupKind: obj.GetObjectKind().GroupVersionKind().GroupKind()}
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *noVolumeSnapshotClient) Create(ctx context.Context, obj k8sclient.Object, opts ...k8sclient.CreateOption) error {
+	if obj.GetObjectKind().GroupVersionKind().Kind == resources.VolumeSnapshotKind {
+		return &meta.NoKindMatchError{GroupKind: obj.GetObjectKind().GroupVersionKind().GroupKind()}
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func TestValidateImageRollback(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"jenkins.io/use-deployment": "true"}},
+		Spec:       v1alpha2.JenkinsSpec{Master: v1alpha2.JenkinsMaster{ImageRollback: &v1alpha2.ImageRollback{}}},
+	}
+
+	assert.Equal(t, []string{
+		"spec.master.imageRollback requires spec.master.jenkinsHomePersistentVolumeClaim",
+		"spec.master.imageRollback.window must be greater than zero",
+		"spec.master.imageRollback can't be used when Jenkins master is managed by Deployment",
+	}, validateImageRollback(jenkins))
+}
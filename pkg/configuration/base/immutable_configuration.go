@@ -0,0 +1,160 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const configurationVersionCreatedEventReason k8sevent.Reason = "ConfigurationVersionCreated"
+
+// ensureConfigurationVersion creates the immutable version of the resource unless it already exists, records it as the
+// current version in status and deletes the oldest versions over spec.immutableConfiguration.historyLimit. The
+// existing versions are never updated.
+func (r *JenkinsBaseConfigurationReconciler) ensureConfigurationVersion(versioned client.Object, list client.ObjectList, version string,
+	current func(*v1alpha2.ConfigurationVersions) *string) error {
+	jenkins := r.Configuration.Jenkins
+	found, ok := versioned.DeepCopyObject().(client.Object)
+	if !ok {
+		return stackerr.Errorf("%T is not a client.Object", versioned)
+	}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: versioned.GetName(), Namespace: versioned.GetNamespace()}, found)
+	if apierrors.IsNotFound(err) {
+		if err = r.CreateResource(versioned); err != nil {
+			return stackerr.WithStack(err)
+		}
+		message := fmt.Sprintf("Created %s version '%s'", version, versioned.GetName())
+		r.logger.Info(message)
+		if r.Configuration.Events != nil {
+			r.Configuration.Events.Emit(jenkins, k8sevent.TypeNormal, configurationVersionCreatedEventReason, message)
+		}
+	} else if err != nil {
+		return stackerr.WithStack(err)
+	}
+
+	if jenkins.Status.ConfigurationVersions == nil {
+		jenkins.Status.ConfigurationVersions = &v1alpha2.ConfigurationVersions{}
+	}
+	if name := current(jenkins.Status.ConfigurationVersions); *name != versioned.GetName() {
+		*name = versioned.GetName()
+		if err = r.Client.Status().Update(context.TODO(), jenkins); err != nil {
+			return stackerr.WithStack(err)
+		}
+	}
+
+	return r.pruneConfigurationVersions(list, version, versioned.GetName())
+}
+
+// pruneConfigurationVersions deletes the oldest versions of the resource over spec.immutableConfiguration.historyLimit,
+// the current version and the versions mounted in the running Jenkins master pod are always kept
+func (r *JenkinsBaseConfigurationReconciler) pruneConfigurationVersions(list client.ObjectList, version, current string) error {
+	jenkins := r.Configuration.Jenkins
+	mounted, err := r.getMountedConfigurationVersions()
+	if err != nil {
+		return err
+	}
+	err = r.Client.List(context.TODO(), list, client.InNamespace(jenkins.Namespace),
+		client.MatchingLabels(resources.BuildConfigurationVersionLabels(jenkins, version)))
+	if err != nil {
+		return stackerr.WithStack(err)
+	}
+	items, err := apimeta.ExtractList(list)
+	if err != nil {
+		return stackerr.WithStack(err)
+	}
+
+	var versions []client.Object
+	for _, item := range items {
+		object, ok := item.(client.Object)
+		if ok && object.GetName() != current && !mounted[object.GetName()] {
+			versions = append(versions, object)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		iCreated, jCreated := versions[i].GetCreationTimestamp(), versions[j].GetCreationTimestamp()
+		if !iCreated.Equal(&jCreated) {
+			return jCreated.Before(&iCreated)
+		}
+		return versions[i].GetName() > versions[j].GetName()
+	})
+
+	// the current version counts towards the limit, the mounted versions are kept over it
+	limit := resources.GetImmutableConfigurationHistoryLimit(jenkins) - 1
+	for i := limit; i < len(versions); i++ {
+		r.logger.Info(fmt.Sprintf("Deleting %s version '%s' over the history limit", version, versions[i].GetName()))
+		if err = r.Client.Delete(context.TODO(), versions[i]); err != nil && !apierrors.IsNotFound(err) {
+			return stackerr.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// getMountedConfigurationVersions returns names of the ConfigMaps and Secrets mounted in the Jenkins master pod, the pod
+// keeps the previous versions until it's recreated
+func (r *JenkinsBaseConfigurationReconciler) getMountedConfigurationVersions() (map[string]bool, error) {
+	mounted := map[string]bool{}
+	pod, err := r.Configuration.GetJenkinsMasterPod()
+	if apierrors.IsNotFound(err) {
+		return mounted, nil
+	} else if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+
+	for _, volume := range pod.Spec.Volumes {
+		if volume.ConfigMap != nil {
+			mounted[volume.ConfigMap.Name] = true
+		}
+		if volume.Secret != nil {
+			mounted[volume.Secret.SecretName] = true
+		}
+		if volume.Projected == nil {
+			continue
+		}
+		for _, source := range volume.Projected.Sources {
+			if source.ConfigMap != nil {
+				mounted[source.ConfigMap.Name] = true
+			}
+			if source.Secret != nil {
+				mounted[source.Secret.Name] = true
+			}
+		}
+	}
+	return mounted, nil
+}
+
+// createOperatorCredentialsSecretVersion creates the immutable version of the operator credentials, the user and the
+// password of the current credentials are kept, so the version changes only when the credentials are first versioned
+func (r *JenkinsBaseConfigurationReconciler) createOperatorCredentialsSecretVersion(meta metav1.ObjectMeta) error {
+	jenkins := r.Configuration.Jenkins
+	secret := resources.NewOperatorCredentialsSecret(meta, jenkins)
+	found := &corev1.Secret{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: resources.GetCurrentOperatorCredentialsSecretName(jenkins), Namespace: jenkins.Namespace}, found)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return stackerr.WithStack(err)
+	}
+	userName := found.Data[resources.OperatorCredentialsSecretUserNameKey]
+	password := found.Data[resources.OperatorCredentialsSecretPasswordKey]
+	if err == nil && userName != nil && password != nil {
+		secret.Data = map[string][]byte{
+			resources.OperatorCredentialsSecretUserNameKey: userName,
+			resources.OperatorCredentialsSecretPasswordKey: password,
+		}
+	}
+
+	return r.ensureConfigurationVersion(resources.NewSecretVersion(jenkins, secret, resources.OperatorCredentialsVersion),
+		&corev1.SecretList{}, resources.OperatorCredentialsVersion, func(versions *v1alpha2.ConfigurationVersions) *string {
+			return &versions.OperatorCredentialsSecret
+		})
+}
@@ -0,0 +1,163 @@
+package base
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEnsureConfigMapVersion(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: defaultNamespace},
+		Spec:       v1alpha2.JenkinsSpec{ImmutableConfiguration: &v1alpha2.ImmutableConfiguration{HistoryLimit: 2}},
+	}
+	// an old version created before the ones created by the test
+	oldConfigMap := resources.NewConfigMapVersion(jenkins, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins-operator-scripts-jenkins", Namespace: defaultNamespace},
+		Data:       map[string]string{"init.sh": "old"},
+	}, resources.ScriptsConfigurationVersion)
+	oldConfigMap.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	recorder := &fakeRecorder{}
+	r := New(configuration.Configuration{
+		Client:  fake.NewClientBuilder().WithObjects(jenkins, oldConfigMap).Build(),
+		Jenkins: jenkins,
+		Scheme:  scheme.Scheme,
+		Events:  recorder,
+	}, client.JenkinsAPIConnectionSettings{})
+	newConfigMap := func(script string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "jenkins-operator-scripts-jenkins", Namespace: defaultNamespace},
+			Data:       map[string]string{"init.sh": script},
+		}
+	}
+	ensureVersion := func(t *testing.T, configMap *corev1.ConfigMap) string {
+		err := r.ensureConfigMapVersion(configMap, resources.ScriptsConfigurationVersion, func(versions *v1alpha2.ConfigurationVersions) *string {
+			return &versions.ScriptsConfigMap
+		})
+		require.NoError(t, err)
+		require.NotNil(t, jenkins.Status.ConfigurationVersions)
+		return jenkins.Status.ConfigurationVersions.ScriptsConfigMap
+	}
+	isFound := func(t *testing.T, name string) bool {
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: defaultNamespace}, &corev1.ConfigMap{})
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	first := ensureVersion(t, newConfigMap("first"))
+
+	assert.NotEqual(t, "jenkins-operator-scripts-jenkins", first)
+	configMap := &corev1.ConfigMap{}
+	require.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: first, Namespace: defaultNamespace}, configMap))
+	assert.Equal(t, map[string]string{"init.sh": "first"}, configMap.Data)
+	require.NotNil(t, configMap.Immutable)
+	assert.True(t, *configMap.Immutable)
+	assert.Equal(t, resources.ScriptsConfigurationVersion, configMap.Labels["configuration-version"])
+	assert.Equal(t, first, resources.GetCurrentScriptsConfigMapName(jenkins))
+	assert.False(t, isFound(t, "jenkins-operator-scripts-jenkins"))
+	assert.True(t, isFound(t, oldConfigMap.Name))
+	require.Len(t, recorder.events, 1)
+	assert.Equal(t, k8sevent.Reason("ConfigurationVersionCreated"), recorder.events[0].reason)
+
+	t.Run("same content", func(t *testing.T) {
+		assert.Equal(t, first, ensureVersion(t, newConfigMap("first")))
+		assert.Len(t, recorder.events, 1)
+	})
+	var second string
+	t.Run("changed content", func(t *testing.T) {
+		// the fake client doesn't set the creation timestamp
+		configMap.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+		require.NoError(t, r.Client.Update(context.TODO(), configMap))
+
+		second = ensureVersion(t, newConfigMap("second"))
+
+		assert.NotEqual(t, first, second)
+		assert.True(t, isFound(t, first))
+		assert.True(t, isFound(t, second))
+		assert.False(t, isFound(t, oldConfigMap.Name))
+	})
+	t.Run("rollback to the previous content", func(t *testing.T) {
+		assert.Equal(t, first, ensureVersion(t, newConfigMap("first")))
+		assert.True(t, isFound(t, first))
+	})
+	t.Run("version mounted in Jenkins master pod is kept", func(t *testing.T) {
+		jenkins.Spec.ImmutableConfiguration.HistoryLimit = 1
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: resources.GetJenkinsMasterPodName(jenkins), Namespace: defaultNamespace},
+			Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+				Name: "scripts",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: second}},
+				},
+			}}},
+		}
+		require.NoError(t, r.Client.Create(context.TODO(), pod))
+
+		third := ensureVersion(t, newConfigMap("third"))
+
+		assert.True(t, isFound(t, third))
+		assert.True(t, isFound(t, second))
+		assert.False(t, isFound(t, first))
+	})
+}
+
+func TestCreateOperatorCredentialsSecretVersion(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: defaultNamespace},
+		Spec:       v1alpha2.JenkinsSpec{ImmutableConfiguration: &v1alpha2.ImmutableConfiguration{}},
+	}
+	// the credentials created before the immutable mode has been enabled
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: resources.GetOperatorCredentialsSecretName(jenkins), Namespace: defaultNamespace},
+		Data: map[string][]byte{
+			resources.OperatorCredentialsSecretUserNameKey: []byte(resources.OperatorUserName),
+			resources.OperatorCredentialsSecretPasswordKey: []byte("password"),
+			resources.OperatorCredentialsSecretTokenKey:    []byte("token"),
+		},
+	}
+	r := New(configuration.Configuration{
+		Client:  fake.NewClientBuilder().WithObjects(jenkins, secret).Build(),
+		Jenkins: jenkins,
+		Scheme:  scheme.Scheme,
+	}, client.JenkinsAPIConnectionSettings{})
+
+	require.NoError(t, r.createOperatorCredentialsSecret(resources.NewResourceObjectMeta(jenkins)))
+
+	name := resources.GetCurrentOperatorCredentialsSecretName(jenkins)
+	assert.NotEqual(t, secret.Name, name)
+	versioned := &corev1.Secret{}
+	require.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: defaultNamespace}, versioned))
+	assert.Equal(t, map[string][]byte{
+		resources.OperatorCredentialsSecretUserNameKey: []byte(resources.OperatorUserName),
+		resources.OperatorCredentialsSecretPasswordKey: []byte("password"),
+	}, versioned.Data)
+
+	t.Run("credentials are kept", func(t *testing.T) {
+		require.NoError(t, r.createOperatorCredentialsSecret(resources.NewResourceObjectMeta(jenkins)))
+
+		assert.Equal(t, name, jenkins.Status.ConfigurationVersions.OperatorCredentialsSecret)
+	})
+}
@@ -0,0 +1,339 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/constants"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	stackerr "github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	jenkinsHomeMigrationFailedEventReason k8sevent.Reason = "JenkinsHomeMigrationFailed"
+
+	// jenkinsHomeMigrationCheckInterval is the requeue delay while Jenkins home is copied to the new claim
+	jenkinsHomeMigrationCheckInterval = 10 * time.Second
+)
+
+// startJenkinsHomeVolumeMigration starts the migration of Jenkins home when the storage class in spec differs from
+// the storage class of Jenkins home PersistentVolumeClaim, it returns true while the migration is in progress.
+// Jenkins master pod is stopped by the reconcile loop and the data is copied before the pod is created again.
+func (r *JenkinsBaseConfigurationReconciler) startJenkinsHomeVolumeMigration(claim corev1.PersistentVolumeClaim) (bool, error) {
+	jenkins := r.Configuration.Jenkins
+	storageClassName := jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim.StorageClassName
+	if storageClassName == nil || (claim.Spec.StorageClassName != nil && *claim.Spec.StorageClassName == *storageClassName) {
+		return false, nil
+	}
+
+	job, err := r.getJenkinsHomeMigrationJob()
+	if err != nil {
+		return false, err
+	}
+	migration := jenkins.Status.JenkinsHomeVolumeMigration
+	if migration != nil && migration.StorageClassName == *storageClassName {
+		switch migration.Phase {
+		case v1alpha2.VolumeMigrationCopying:
+			return true, nil
+		case v1alpha2.VolumeMigrationFailed:
+			if job != nil {
+				// the failed Job is kept for troubleshooting, the migration is retried once it's deleted
+				return false, nil
+			}
+		}
+	}
+	if job != nil {
+		if err = r.deleteJenkinsHomeMigrationJob(job); err != nil {
+			return false, err
+		}
+	}
+
+	jenkins.Status.JenkinsHomeVolumeMigration = &v1alpha2.VolumeMigration{
+		Phase:            v1alpha2.VolumeMigrationCopying,
+		StorageClassName: *storageClassName,
+		SourceClaimName:  claim.Name,
+		TargetClaimName:  resources.GetJenkinsHomeMigrationPersistentVolumeClaimName(jenkins, *storageClassName),
+		StartTime:        metav1.Now(),
+	}
+	currentStorageClassName := "default"
+	if claim.Spec.StorageClassName != nil {
+		currentStorageClassName = *claim.Spec.StorageClassName
+	}
+	r.logger.Info(fmt.Sprintf("Migrating Jenkins home from storage class '%s' to '%s', Jenkins master pod will be stopped",
+		currentStorageClassName, *storageClassName))
+	return true, stackerr.WithStack(r.Client.Status().Update(context.TODO(), jenkins))
+}
+
+// copyJenkinsHomeBeforePodCreation creates Jenkins home PersistentVolumeClaim of the new storage class and copies
+// the data by a Job after Jenkins master pod has been deleted, the pod is created with the new claim once the Job
+// succeeds and with the old one if the Job fails
+func (r *JenkinsBaseConfigurationReconciler) copyJenkinsHomeBeforePodCreation() (reconcile.Result, error) {
+	jenkins := r.Configuration.Jenkins
+	migration := jenkins.Status.JenkinsHomeVolumeMigration
+	if jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim == nil || migration == nil || migration.Phase != v1alpha2.VolumeMigrationCopying {
+		return reconcile.Result{}, nil
+	}
+
+	claim := &corev1.PersistentVolumeClaim{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: migration.TargetClaimName, Namespace: jenkins.Namespace}, claim)
+	if apierrors.IsNotFound(err) {
+		claim = resources.NewJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins), jenkins)
+		claim.Name = migration.TargetClaimName
+		claim.Spec.StorageClassName = &migration.StorageClassName
+		r.logger.Info(fmt.Sprintf("Creating Jenkins home PersistentVolumeClaim '%s' of storage class '%s'", claim.Name, migration.StorageClassName))
+		return reconcile.Result{Requeue: true}, stackerr.WithStack(r.CreateResource(claim))
+	} else if err != nil {
+		return reconcile.Result{}, stackerr.WithStack(err)
+	}
+
+	job, err := r.getJenkinsHomeMigrationJob()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if job == nil {
+		job = resources.NewJenkinsHomeMigrationJob(resources.NewResourceObjectMeta(jenkins), jenkins, *migration)
+		r.logger.Info(fmt.Sprintf("Copying Jenkins home from PersistentVolumeClaim '%s' to '%s'", migration.SourceClaimName, migration.TargetClaimName))
+		return reconcile.Result{Requeue: true, RequeueAfter: jenkinsHomeMigrationCheckInterval}, stackerr.WithStack(r.CreateResource(job))
+	}
+
+	if job.Status.Succeeded > 0 {
+		if err = r.deleteJenkinsHomeMigrationJob(job); err != nil {
+			return reconcile.Result{}, err
+		}
+		// the labels are the durable record of the claim with Jenkins home, status can be lost
+		if err = r.setJenkinsHomeClaimState(claim, resources.JenkinsHomeActive); err != nil {
+			return reconcile.Result{}, err
+		}
+		source := &corev1.PersistentVolumeClaim{}
+		err = r.Client.Get(context.TODO(), types.NamespacedName{Name: migration.SourceClaimName, Namespace: jenkins.Namespace}, source)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, stackerr.WithStack(err)
+		} else if err == nil {
+			if err = r.setJenkinsHomeClaimState(source, resources.JenkinsHomeRetired); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		jenkins.Status.JenkinsHomePersistentVolumeClaimName = migration.TargetClaimName
+		migration.Phase = v1alpha2.VolumeMigrationSwitched
+		r.logger.Info(fmt.Sprintf("Jenkins home has been copied to PersistentVolumeClaim '%s', Jenkins master pod will use it", migration.TargetClaimName))
+		return reconcile.Result{}, stackerr.WithStack(r.Client.Status().Update(context.TODO(), jenkins))
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return reconcile.Result{}, r.failJenkinsHomeVolumeMigration(job, condition.Message)
+		}
+	}
+	return reconcile.Result{Requeue: true, RequeueAfter: jenkinsHomeMigrationCheckInterval}, nil
+}
+
+// failJenkinsHomeVolumeMigration deletes the partially copied claim, Jenkins master pod keeps using the old one
+func (r *JenkinsBaseConfigurationReconciler) failJenkinsHomeVolumeMigration(job *batchv1.Job, cause string) error {
+	jenkins := r.Configuration.Jenkins
+	migration := jenkins.Status.JenkinsHomeVolumeMigration
+	claim := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: migration.TargetClaimName, Namespace: jenkins.Namespace}}
+	if err := r.Client.Delete(context.TODO(), claim); err != nil && !apierrors.IsNotFound(err) {
+		return stackerr.WithStack(err)
+	}
+
+	migration.Phase = v1alpha2.VolumeMigrationFailed
+	migration.Message = fmt.Sprintf("Job '%s' failed: %s", job.Name, cause)
+	if err := r.Client.Status().Update(context.TODO(), jenkins); err != nil {
+		return stackerr.WithStack(err)
+	}
+	message := fmt.Sprintf("Jenkins home migration to storage class '%s' failed, Jenkins master pod keeps using PersistentVolumeClaim '%s': %s",
+		migration.StorageClassName, migration.SourceClaimName, migration.Message)
+	r.logger.Info(message)
+	if r.Configuration.Events != nil {
+		r.Configuration.Events.Emit(jenkins, k8sevent.TypeWarning, jenkinsHomeMigrationFailedEventReason, message)
+	}
+	if r.Notifications != nil {
+		*r.Notifications <- event.Event{
+			Jenkins: *jenkins,
+			Phase:   event.PhaseBase,
+			Level:   v1alpha2.NotificationLevelWarning,
+			Reason:  reason.NewVolumeMigrated(reason.OperatorSource, []string{message}),
+		}
+	}
+	return nil
+}
+
+// reconcileJenkinsHomeVolumeMigration deletes the Jenkins home PersistentVolumeClaims retired by the migrations once
+// Jenkins is ready with the new claim and spec.master.jenkinsHomePersistentVolumeClaim.previousClaimRetention has
+// elapsed since then. The retirement time is recorded on the claim in the first reconcile loop which sees Jenkins
+// ready, so the retired claim is never deleted by the same loop.
+func (r *JenkinsBaseConfigurationReconciler) reconcileJenkinsHomeVolumeMigration() error {
+	jenkins := r.Configuration.Jenkins
+	if jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim == nil {
+		return nil
+	}
+
+	claims := &corev1.PersistentVolumeClaimList{}
+	err := r.Client.List(context.TODO(), claims, client.InNamespace(jenkins.Namespace),
+		client.MatchingLabels(resources.BuildJenkinsHomeLabels(jenkins, resources.JenkinsHomeRetired)))
+	if err != nil {
+		return stackerr.WithStack(err)
+	}
+	retention := resources.GetJenkinsHomePreviousClaimRetention(jenkins)
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		if claim.Name == resources.GetJenkinsHomePersistentVolumeClaimName(jenkins) {
+			continue
+		}
+		retiredAt, err := time.Parse(time.RFC3339, claim.Annotations[resources.JenkinsHomeRetiredAtAnnotation])
+		if err != nil {
+			claim.Annotations = resources.MergeMaps(claim.Annotations, map[string]string{
+				resources.JenkinsHomeRetiredAtAnnotation: time.Now().UTC().Format(time.RFC3339),
+			})
+			if err = r.Client.Update(context.TODO(), claim); err != nil {
+				return stackerr.WithStack(err)
+			}
+			continue
+		}
+		if retention == 0 || time.Since(retiredAt) < retention {
+			continue
+		}
+		r.logger.Info(fmt.Sprintf("Deleting previous Jenkins home PersistentVolumeClaim '%s' retired at %s", claim.Name, retiredAt.Format(time.RFC3339)))
+		if err = r.Client.Delete(context.TODO(), claim); err != nil && !apierrors.IsNotFound(err) {
+			return stackerr.WithStack(err)
+		}
+	}
+
+	return r.completeJenkinsHomeVolumeMigration()
+}
+
+// completeJenkinsHomeVolumeMigration marks the migration as completed once the claim Jenkins home has been migrated
+// from is deleted
+func (r *JenkinsBaseConfigurationReconciler) completeJenkinsHomeVolumeMigration() error {
+	jenkins := r.Configuration.Jenkins
+	migration := jenkins.Status.JenkinsHomeVolumeMigration
+	if migration == nil || migration.Phase != v1alpha2.VolumeMigrationSwitched {
+		return nil
+	}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: migration.SourceClaimName, Namespace: jenkins.Namespace}, &corev1.PersistentVolumeClaim{})
+	if err == nil {
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return stackerr.WithStack(err)
+	}
+
+	now := metav1.Now()
+	migration.Phase = v1alpha2.VolumeMigrationCompleted
+	migration.CompletionTime = &now
+	if err := r.Client.Status().Update(context.TODO(), jenkins); err != nil {
+		return stackerr.WithStack(err)
+	}
+	message := fmt.Sprintf("Jenkins home has been migrated to storage class '%s'", migration.StorageClassName)
+	r.logger.Info(message)
+	if r.Notifications != nil {
+		*r.Notifications <- event.Event{
+			Jenkins: *jenkins,
+			Phase:   event.PhaseBase,
+			Level:   v1alpha2.NotificationLevelInfo,
+			Reason:  reason.NewVolumeMigrated(reason.OperatorSource, []string{message}),
+		}
+	}
+	return nil
+}
+
+// setJenkinsHomeClaimState labels Jenkins home PersistentVolumeClaim as active or retired
+func (r *JenkinsBaseConfigurationReconciler) setJenkinsHomeClaimState(claim *corev1.PersistentVolumeClaim, state string) error {
+	if claim.Labels[constants.LabelJenkinsHomeKey] == state {
+		return nil
+	}
+	claim.Labels = resources.MergeMaps(claim.Labels, map[string]string{constants.LabelJenkinsHomeKey: state})
+	return stackerr.WithStack(r.Client.Update(context.TODO(), claim))
+}
+
+// restoreJenkinsHomePersistentVolumeClaimName restores the name of Jenkins home PersistentVolumeClaim in status from
+// the label of the claim Jenkins home has been migrated to when the status has been lost, e.g. the Jenkins CR has been
+// recreated or restored from a backup, so Jenkins isn't started with a new empty claim
+func (r *JenkinsBaseConfigurationReconciler) restoreJenkinsHomePersistentVolumeClaimName() error {
+	jenkins := r.Configuration.Jenkins
+	if len(jenkins.Status.JenkinsHomePersistentVolumeClaimName) > 0 {
+		return nil
+	}
+
+	claims := &corev1.PersistentVolumeClaimList{}
+	err := r.Client.List(context.TODO(), claims, client.InNamespace(jenkins.Namespace),
+		client.MatchingLabels(resources.BuildJenkinsHomeLabels(jenkins, resources.JenkinsHomeActive)))
+	if err != nil {
+		return stackerr.WithStack(err)
+	}
+	var active *corev1.PersistentVolumeClaim
+	for i := range claims.Items {
+		if active == nil || active.CreationTimestamp.Before(&claims.Items[i].CreationTimestamp) {
+			active = &claims.Items[i]
+		}
+	}
+	if active == nil {
+		// the claims migrated before they have been labeled are found by the name derived from the storage class
+		var err error
+		if active, err = r.getJenkinsHomeMigratedPersistentVolumeClaim(); err != nil || active == nil {
+			return err
+		}
+	}
+	if active.Name == resources.GetJenkinsHomePersistentVolumeClaimName(jenkins) {
+		return nil
+	}
+
+	r.logger.Info(fmt.Sprintf("Jenkins home PersistentVolumeClaim '%s' is restored from the claim", active.Name))
+	jenkins.Status.JenkinsHomePersistentVolumeClaimName = active.Name
+	return stackerr.WithStack(r.Client.Status().Update(context.TODO(), jenkins))
+}
+
+// getJenkinsHomeMigratedPersistentVolumeClaim returns the claim of spec storage class created by the migration if
+// the claim created with Jenkins CR doesn't exist anymore
+func (r *JenkinsBaseConfigurationReconciler) getJenkinsHomeMigratedPersistentVolumeClaim() (*corev1.PersistentVolumeClaim, error) {
+	jenkins := r.Configuration.Jenkins
+	storageClassName := jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim.StorageClassName
+	if storageClassName == nil {
+		return nil, nil
+	}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: resources.GetJenkinsHomePersistentVolumeClaimName(jenkins), Namespace: jenkins.Namespace}, &corev1.PersistentVolumeClaim{})
+	if err == nil {
+		return nil, nil
+	} else if !apierrors.IsNotFound(err) {
+		return nil, stackerr.WithStack(err)
+	}
+
+	claim := &corev1.PersistentVolumeClaim{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: resources.GetJenkinsHomeMigrationPersistentVolumeClaimName(jenkins, *storageClassName), Namespace: jenkins.Namespace}, claim)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	return claim, nil
+}
+
+func (r *JenkinsBaseConfigurationReconciler) getJenkinsHomeMigrationJob() (*batchv1.Job, error) {
+	job := &batchv1.Job{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: resources.GetJenkinsHomeMigrationJobName(r.Configuration.Jenkins), Namespace: r.Configuration.Jenkins.Namespace}, job)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	return job, nil
+}
+
+func (r *JenkinsBaseConfigurationReconciler) deleteJenkinsHomeMigrationJob(job *batchv1.Job) error {
+	err := r.Client.Delete(context.TODO(), job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !apierrors.IsNotFound(err) {
+		return stackerr.WithStack(err)
+	}
+	return nil
+}
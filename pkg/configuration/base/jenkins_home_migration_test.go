@@ -0,0 +1,239 @@
+package base
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestJenkinsHomeVolumeMigration(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	standardStorageClassName, fastStorageClassName := "standard", "fast"
+	newJenkins := func() *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: defaultNamespace},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					JenkinsHomePersistentVolumeClaim: &v1alpha2.JenkinsHomePersistentVolumeClaim{
+						Size:             resource.MustParse("10Gi"),
+						StorageClassName: &fastStorageClassName,
+					},
+				},
+			},
+		}
+	}
+	newReconciler := func(jenkins *v1alpha2.Jenkins, recorder *fakeRecorder) (*JenkinsBaseConfigurationReconciler, chan event.Event) {
+		claim := resources.NewJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins), jenkins)
+		claim.Spec.StorageClassName = &standardStorageClassName
+		notifications := make(chan event.Event, 1)
+		return New(configuration.Configuration{
+			Client:        fake.NewClientBuilder().WithObjects(jenkins, claim).Build(),
+			Jenkins:       jenkins,
+			Scheme:        scheme.Scheme,
+			Notifications: &notifications,
+			Events:        recorder,
+		}, client.JenkinsAPIConnectionSettings{}), notifications
+	}
+	getJob := func(t *testing.T, reconciler *JenkinsBaseConfigurationReconciler) *batchv1.Job {
+		job, err := reconciler.getJenkinsHomeMigrationJob()
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		return job
+	}
+	isClaimFound := func(t *testing.T, reconciler *JenkinsBaseConfigurationReconciler, name string) bool {
+		err := reconciler.Client.Get(context.TODO(), types.NamespacedName{Namespace: defaultNamespace, Name: name}, &corev1.PersistentVolumeClaim{})
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+	getClaim := func(t *testing.T, reconciler *JenkinsBaseConfigurationReconciler, name string) *corev1.PersistentVolumeClaim {
+		claim := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, reconciler.Client.Get(context.TODO(), types.NamespacedName{Namespace: defaultNamespace, Name: name}, claim))
+		return claim
+	}
+	startAndCopy := func(t *testing.T, reconciler *JenkinsBaseConfigurationReconciler) {
+		jenkins := reconciler.Configuration.Jenkins
+		require.NoError(t, reconciler.ensureJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins)))
+		require.NotNil(t, jenkins.Status.JenkinsHomeVolumeMigration)
+		assert.Equal(t, v1alpha2.VolumeMigrationCopying, jenkins.Status.JenkinsHomeVolumeMigration.Phase)
+
+		result, err := reconciler.copyJenkinsHomeBeforePodCreation()
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+		assert.True(t, isClaimFound(t, reconciler, "jenkins-operator-home-jenkins-fast"))
+
+		result, err = reconciler.copyJenkinsHomeBeforePodCreation()
+		require.NoError(t, err)
+		assert.Equal(t, jenkinsHomeMigrationCheckInterval, result.RequeueAfter)
+		job := getJob(t, reconciler)
+		assert.Equal(t, "jenkins-operator-home-jenkins", job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		assert.Equal(t, "jenkins-operator-home-jenkins-fast", job.Spec.Template.Spec.Volumes[1].PersistentVolumeClaim.ClaimName)
+	}
+
+	t.Run("migrated", func(t *testing.T) {
+		jenkins := newJenkins()
+		reconciler, notifications := newReconciler(jenkins, &fakeRecorder{})
+		startAndCopy(t, reconciler)
+		job := getJob(t, reconciler)
+		job.Status.Succeeded = 1
+		require.NoError(t, reconciler.Client.Update(context.TODO(), job))
+
+		result, err := reconciler.copyJenkinsHomeBeforePodCreation()
+
+		require.NoError(t, err)
+		assert.False(t, result.Requeue)
+		assert.Equal(t, v1alpha2.VolumeMigrationSwitched, jenkins.Status.JenkinsHomeVolumeMigration.Phase)
+		assert.Equal(t, "jenkins-operator-home-jenkins-fast", resources.GetJenkinsHomePersistentVolumeClaimName(jenkins))
+		job, err = reconciler.getJenkinsHomeMigrationJob()
+		require.NoError(t, err)
+		assert.Nil(t, job)
+
+		assert.Equal(t, resources.JenkinsHomeActive, getClaim(t, reconciler, "jenkins-operator-home-jenkins-fast").Labels["jenkins-home"])
+		assert.Equal(t, resources.JenkinsHomeRetired, getClaim(t, reconciler, "jenkins-operator-home-jenkins").Labels["jenkins-home"])
+
+		// the previous claim is kept when Jenkins becomes ready
+		require.NoError(t, reconciler.reconcileJenkinsHomeVolumeMigration())
+
+		assert.Equal(t, v1alpha2.VolumeMigrationSwitched, jenkins.Status.JenkinsHomeVolumeMigration.Phase)
+		previous := getClaim(t, reconciler, "jenkins-operator-home-jenkins")
+		assert.NotEmpty(t, previous.Annotations[resources.JenkinsHomeRetiredAtAnnotation])
+		assert.Len(t, notifications, 0)
+
+		require.NoError(t, reconciler.reconcileJenkinsHomeVolumeMigration())
+		assert.True(t, isClaimFound(t, reconciler, "jenkins-operator-home-jenkins"))
+
+		// the previous claim is deleted once the retention elapses
+		previous.Annotations[resources.JenkinsHomeRetiredAtAnnotation] = time.Now().Add(-25 * time.Hour).UTC().Format(time.RFC3339)
+		require.NoError(t, reconciler.Client.Update(context.TODO(), previous))
+
+		require.NoError(t, reconciler.reconcileJenkinsHomeVolumeMigration())
+
+		assert.Equal(t, v1alpha2.VolumeMigrationCompleted, jenkins.Status.JenkinsHomeVolumeMigration.Phase)
+		assert.NotNil(t, jenkins.Status.JenkinsHomeVolumeMigration.CompletionTime)
+		assert.False(t, isClaimFound(t, reconciler, "jenkins-operator-home-jenkins"))
+		notification := <-notifications
+		assert.Equal(t, []string{"Jenkins home has been migrated to storage class 'fast'"}, notification.Reason.Short())
+
+		require.NoError(t, reconciler.ensureJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins)))
+		assert.Equal(t, v1alpha2.VolumeMigrationCompleted, jenkins.Status.JenkinsHomeVolumeMigration.Phase)
+	})
+	t.Run("previous claim retained until deleted manually", func(t *testing.T) {
+		jenkins := newJenkins()
+		jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim.PreviousClaimRetention = &metav1.Duration{}
+		reconciler, _ := newReconciler(jenkins, &fakeRecorder{})
+		startAndCopy(t, reconciler)
+		job := getJob(t, reconciler)
+		job.Status.Succeeded = 1
+		require.NoError(t, reconciler.Client.Update(context.TODO(), job))
+		_, err := reconciler.copyJenkinsHomeBeforePodCreation()
+		require.NoError(t, err)
+		require.NoError(t, reconciler.reconcileJenkinsHomeVolumeMigration())
+		previous := getClaim(t, reconciler, "jenkins-operator-home-jenkins")
+		previous.Annotations[resources.JenkinsHomeRetiredAtAnnotation] = time.Now().Add(-365 * 24 * time.Hour).UTC().Format(time.RFC3339)
+		require.NoError(t, reconciler.Client.Update(context.TODO(), previous))
+
+		require.NoError(t, reconciler.reconcileJenkinsHomeVolumeMigration())
+
+		assert.True(t, isClaimFound(t, reconciler, "jenkins-operator-home-jenkins"))
+		assert.Equal(t, v1alpha2.VolumeMigrationSwitched, jenkins.Status.JenkinsHomeVolumeMigration.Phase)
+	})
+	t.Run("status lost after migration", func(t *testing.T) {
+		jenkins := newJenkins()
+		reconciler, _ := newReconciler(jenkins, &fakeRecorder{})
+		startAndCopy(t, reconciler)
+		job := getJob(t, reconciler)
+		job.Status.Succeeded = 1
+		require.NoError(t, reconciler.Client.Update(context.TODO(), job))
+		_, err := reconciler.copyJenkinsHomeBeforePodCreation()
+		require.NoError(t, err)
+		jenkins.Status = v1alpha2.JenkinsStatus{}
+		require.NoError(t, reconciler.Client.Status().Update(context.TODO(), jenkins))
+
+		require.NoError(t, reconciler.ensureJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins)))
+
+		assert.Equal(t, "jenkins-operator-home-jenkins-fast", resources.GetJenkinsHomePersistentVolumeClaimName(jenkins))
+		assert.Nil(t, jenkins.Status.JenkinsHomeVolumeMigration)
+		assert.True(t, isClaimFound(t, reconciler, "jenkins-operator-home-jenkins"))
+	})
+	t.Run("status lost after the previous claim has been deleted", func(t *testing.T) {
+		jenkins := newJenkins()
+		reconciler, _ := newReconciler(jenkins, &fakeRecorder{})
+		// the claim migrated before the claims have been labeled
+		migrated := resources.NewJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins), jenkins)
+		migrated.Name = "jenkins-operator-home-jenkins-fast"
+		require.NoError(t, reconciler.Client.Create(context.TODO(), migrated))
+		require.NoError(t, reconciler.Client.Delete(context.TODO(), getClaim(t, reconciler, "jenkins-operator-home-jenkins")))
+
+		require.NoError(t, reconciler.ensureJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins)))
+
+		assert.Equal(t, "jenkins-operator-home-jenkins-fast", resources.GetJenkinsHomePersistentVolumeClaimName(jenkins))
+		assert.False(t, isClaimFound(t, reconciler, "jenkins-operator-home-jenkins"))
+	})
+	t.Run("failed without notifications", func(t *testing.T) {
+		jenkins := newJenkins()
+		reconciler, _ := newReconciler(jenkins, &fakeRecorder{})
+		reconciler.Configuration.Notifications = nil
+		startAndCopy(t, reconciler)
+		job := getJob(t, reconciler)
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+		require.NoError(t, reconciler.Client.Update(context.TODO(), job))
+
+		_, err := reconciler.copyJenkinsHomeBeforePodCreation()
+
+		require.NoError(t, err)
+		assert.Equal(t, v1alpha2.VolumeMigrationFailed, jenkins.Status.JenkinsHomeVolumeMigration.Phase)
+	})
+	t.Run("failed", func(t *testing.T) {
+		jenkins := newJenkins()
+		recorder := &fakeRecorder{}
+		reconciler, notifications := newReconciler(jenkins, recorder)
+		startAndCopy(t, reconciler)
+		job := getJob(t, reconciler)
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "Job has reached the specified backoff limit"}}
+		require.NoError(t, reconciler.Client.Update(context.TODO(), job))
+
+		result, err := reconciler.copyJenkinsHomeBeforePodCreation()
+
+		require.NoError(t, err)
+		assert.False(t, result.Requeue)
+		migration := jenkins.Status.JenkinsHomeVolumeMigration
+		assert.Equal(t, v1alpha2.VolumeMigrationFailed, migration.Phase)
+		assert.Equal(t, "Job 'jenkins-operator-home-migration-jenkins' failed: Job has reached the specified backoff limit", migration.Message)
+		assert.Equal(t, "jenkins-operator-home-jenkins", resources.GetJenkinsHomePersistentVolumeClaimName(jenkins))
+		assert.False(t, isClaimFound(t, reconciler, "jenkins-operator-home-jenkins-fast"))
+		require.Len(t, recorder.events, 1)
+		assert.Equal(t, k8sevent.TypeWarning, recorder.events[0].eventType)
+		assert.Len(t, notifications, 1)
+
+		require.NoError(t, reconciler.ensureJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins)))
+		assert.Equal(t, v1alpha2.VolumeMigrationFailed, jenkins.Status.JenkinsHomeVolumeMigration.Phase)
+
+		// the migration is retried once the failed Job is deleted
+		require.NoError(t, reconciler.Client.Delete(context.TODO(), job))
+		require.NoError(t, reconciler.ensureJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins)))
+		assert.Equal(t, v1alpha2.VolumeMigrationCopying, jenkins.Status.JenkinsHomeVolumeMigration.Phase)
+		assert.Empty(t, jenkins.Status.JenkinsHomeVolumeMigration.Message)
+	})
+}
@@ -0,0 +1,143 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// jenkinsHomeFileSystemResizeTimeout is how long the file system of expanded Jenkins home volume can wait for
+	// the online resize, Jenkins master pod is restarted afterwards because the CSI driver supports only offline resize
+	jenkinsHomeFileSystemResizeTimeout = 2 * time.Minute
+	// jenkinsHomeFileSystemResizeCheckInterval is the requeue delay while the file system resize is pending
+	jenkinsHomeFileSystemResizeCheckInterval = 15 * time.Second
+)
+
+// ensureJenkinsHomePersistentVolumeClaim creates Jenkins home PersistentVolumeClaim, requests its expansion
+// when the size in spec grows and starts the migration to another storage class when the storage class changes
+func (r *JenkinsBaseConfigurationReconciler) ensureJenkinsHomePersistentVolumeClaim(meta metav1.ObjectMeta) error {
+	config := r.Configuration.Jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim
+	if config == nil {
+		return nil
+	}
+	if err := r.restoreJenkinsHomePersistentVolumeClaimName(); err != nil {
+		return err
+	}
+
+	claim := &corev1.PersistentVolumeClaim{}
+	name := resources.GetJenkinsHomePersistentVolumeClaimName(r.Configuration.Jenkins)
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: r.Configuration.Jenkins.Namespace}, claim)
+	if err != nil && apierrors.IsNotFound(err) {
+		claim = resources.NewJenkinsHomePersistentVolumeClaim(meta, r.Configuration.Jenkins)
+		claim.Spec.DataSource = jenkinsHomeVolumeSnapshotDataSource(r.Configuration.Jenkins)
+		return stackerr.WithStack(r.CreateResource(claim))
+	} else if err != nil {
+		return stackerr.WithStack(err)
+	}
+
+	if migrating, err := r.startJenkinsHomeVolumeMigration(*claim); err != nil || migrating {
+		return err
+	}
+
+	requested := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+	if config.Size.Cmp(requested) <= 0 {
+		return nil // shrinking is rejected by the validation
+	}
+	if claim.Spec.Resources.Requests == nil {
+		claim.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	claim.Spec.Resources.Requests[corev1.ResourceStorage] = config.Size
+	if err = r.Client.Update(context.TODO(), claim); err != nil {
+		return stackerr.WithStack(err)
+	}
+	r.logger.Info(fmt.Sprintf("Expanding Jenkins home PersistentVolumeClaim '%s' from %s to %s", name, requested.String(), config.Size.String()))
+
+	resize := &v1alpha2.VolumeResize{
+		Phase:         v1alpha2.VolumeResizeResizing,
+		RequestedSize: config.Size.DeepCopy(),
+		StartTime:     metav1.Now(),
+	}
+	if capacity, found := claim.Status.Capacity[corev1.ResourceStorage]; found {
+		resize.Capacity = &capacity
+	}
+	r.Configuration.Jenkins.Status.JenkinsHomeVolumeResize = resize
+	return stackerr.WithStack(r.Client.Status().Update(context.TODO(), r.Configuration.Jenkins))
+}
+
+// reconcileJenkinsHomeVolumeResize surfaces the progress of Jenkins home volume expansion in status, the reconcile loop
+// waits while the file system resize is pending and Jenkins master pod is restarted when the resize isn't done online
+func (r *JenkinsBaseConfigurationReconciler) reconcileJenkinsHomeVolumeResize() (reconcile.Result, error) {
+	jenkins := r.Configuration.Jenkins
+	resize := jenkins.Status.JenkinsHomeVolumeResize
+	if jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim == nil || resize == nil || resize.Phase == v1alpha2.VolumeResizeCompleted {
+		return reconcile.Result{}, nil
+	}
+
+	claim := &corev1.PersistentVolumeClaim{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: resources.GetJenkinsHomePersistentVolumeClaimName(jenkins), Namespace: jenkins.Namespace}, claim)
+	if err != nil {
+		return reconcile.Result{}, stackerr.WithStack(err)
+	}
+
+	phase := v1alpha2.VolumeResizeResizing
+	var fileSystemResizePendingSince time.Time
+	for _, condition := range claim.Status.Conditions {
+		if condition.Type == corev1.PersistentVolumeClaimFileSystemResizePending && condition.Status == corev1.ConditionTrue {
+			phase = v1alpha2.VolumeResizeFileSystemResizePending
+			fileSystemResizePendingSince = condition.LastTransitionTime.Time
+		}
+	}
+	capacity, found := claim.Status.Capacity[corev1.ResourceStorage]
+	if found && phase == v1alpha2.VolumeResizeResizing && capacity.Cmp(resize.RequestedSize) >= 0 {
+		phase = v1alpha2.VolumeResizeCompleted
+	}
+
+	if phase != resize.Phase || (found && (resize.Capacity == nil || resize.Capacity.Cmp(capacity) != 0)) {
+		resize.Phase = phase
+		if found {
+			resize.Capacity = &capacity
+		}
+		if phase == v1alpha2.VolumeResizeCompleted {
+			now := metav1.Now()
+			resize.CompletionTime = &now
+		}
+		if err = r.Client.Status().Update(context.TODO(), jenkins); err != nil {
+			return reconcile.Result{}, stackerr.WithStack(err)
+		}
+		r.logger.Info(fmt.Sprintf("Jenkins home volume resize phase: %s", phase))
+		if phase == v1alpha2.VolumeResizeCompleted {
+			message := fmt.Sprintf("Jenkins home volume has been expanded to %s", capacity.String())
+			*r.Notifications <- event.Event{
+				Jenkins: *jenkins,
+				Phase:   event.PhaseBase,
+				Level:   v1alpha2.NotificationLevelInfo,
+				Reason:  reason.NewVolumeResized(reason.OperatorSource, []string{message}),
+			}
+		}
+	}
+
+	if phase != v1alpha2.VolumeResizeFileSystemResizePending || resize.PodRestarted {
+		return reconcile.Result{}, nil
+	}
+	if time.Since(fileSystemResizePendingSince) < jenkinsHomeFileSystemResizeTimeout {
+		return reconcile.Result{Requeue: true, RequeueAfter: jenkinsHomeFileSystemResizeCheckInterval}, nil
+	}
+
+	resize.PodRestarted = true
+	message := "Jenkins home volume file system resize requires Jenkins master pod restart"
+	r.logger.Info(message)
+	return reconcile.Result{Requeue: true}, r.Configuration.RestartJenkinsMasterPod(reason.NewPodRestart(reason.OperatorSource, []string{message}))
+}
@@ -0,0 +1,224 @@
+package base
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestJenkinsHomeVolumeResize(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	newJenkins := func(size string) *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: defaultNamespace},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					JenkinsHomePersistentVolumeClaim: &v1alpha2.JenkinsHomePersistentVolumeClaim{Size: resource.MustParse(size)},
+				},
+			},
+		}
+	}
+	newClaim := func(jenkins *v1alpha2.Jenkins, requested, capacity string, conditions ...corev1.PersistentVolumeClaimCondition) *corev1.PersistentVolumeClaim {
+		claim := resources.NewJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins), jenkins)
+		claim.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse(requested)
+		claim.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)}
+		claim.Status.Conditions = conditions
+		return claim
+	}
+	newReconciler := func(jenkins *v1alpha2.Jenkins, objects ...*corev1.PersistentVolumeClaim) (*JenkinsBaseConfigurationReconciler, chan event.Event) {
+		clientBuilder := fake.NewClientBuilder().WithObjects(jenkins)
+		for _, object := range objects {
+			clientBuilder.WithObjects(object)
+		}
+		notifications := make(chan event.Event, 1)
+		return New(configuration.Configuration{
+			Client:        clientBuilder.Build(),
+			Jenkins:       jenkins,
+			Scheme:        scheme.Scheme,
+			Notifications: &notifications,
+		}, client.JenkinsAPIConnectionSettings{}), notifications
+	}
+	getClaim := func(t *testing.T, reconciler *JenkinsBaseConfigurationReconciler) *corev1.PersistentVolumeClaim {
+		claim := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, reconciler.Client.Get(context.TODO(), types.NamespacedName{
+			Namespace: defaultNamespace,
+			Name:      resources.GetJenkinsHomePersistentVolumeClaimName(reconciler.Configuration.Jenkins),
+		}, claim))
+		return claim
+	}
+
+	t.Run("claim not configured", func(t *testing.T) {
+		jenkins := newJenkins("10Gi")
+		jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim = nil
+		reconciler, _ := newReconciler(jenkins)
+
+		err := reconciler.ensureJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins))
+
+		require.NoError(t, err)
+		err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Namespace: defaultNamespace, Name: resources.GetJenkinsHomePersistentVolumeClaimName(jenkins)}, &corev1.PersistentVolumeClaim{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+	t.Run("create claim", func(t *testing.T) {
+		jenkins := newJenkins("10Gi")
+		reconciler, _ := newReconciler(jenkins)
+
+		err := reconciler.ensureJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins))
+
+		require.NoError(t, err)
+		requested := getClaim(t, reconciler).Spec.Resources.Requests[corev1.ResourceStorage]
+		assert.Equal(t, "10Gi", requested.String())
+		assert.Nil(t, jenkins.Status.JenkinsHomeVolumeResize)
+	})
+	t.Run("expand claim", func(t *testing.T) {
+		jenkins := newJenkins("20Gi")
+		reconciler, _ := newReconciler(jenkins, newClaim(jenkins, "10Gi", "10Gi"))
+
+		err := reconciler.ensureJenkinsHomePersistentVolumeClaim(resources.NewResourceObjectMeta(jenkins))
+
+		require.NoError(t, err)
+		requested := getClaim(t, reconciler).Spec.Resources.Requests[corev1.ResourceStorage]
+		assert.Equal(t, "20Gi", requested.String())
+		resize := jenkins.Status.JenkinsHomeVolumeResize
+		require.NotNil(t, resize)
+		assert.Equal(t, v1alpha2.VolumeResizeResizing, resize.Phase)
+		assert.Equal(t, "20Gi", resize.RequestedSize.String())
+		assert.Equal(t, "10Gi", resize.Capacity.String())
+	})
+	t.Run("file system resize pending", func(t *testing.T) {
+		jenkins := newJenkins("20Gi")
+		jenkins.Status.JenkinsHomeVolumeResize = &v1alpha2.VolumeResize{Phase: v1alpha2.VolumeResizeResizing, RequestedSize: resource.MustParse("20Gi")}
+		reconciler, _ := newReconciler(jenkins, newClaim(jenkins, "20Gi", "10Gi", corev1.PersistentVolumeClaimCondition{
+			Type:               corev1.PersistentVolumeClaimFileSystemResizePending,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.Now(),
+		}))
+
+		result, err := reconciler.reconcileJenkinsHomeVolumeResize()
+
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+		assert.Equal(t, jenkinsHomeFileSystemResizeCheckInterval, result.RequeueAfter)
+		assert.Equal(t, v1alpha2.VolumeResizeFileSystemResizePending, jenkins.Status.JenkinsHomeVolumeResize.Phase)
+	})
+	t.Run("restart pod when file system resize is not done online", func(t *testing.T) {
+		jenkins := newJenkins("20Gi")
+		jenkins.Status.JenkinsHomeVolumeResize = &v1alpha2.VolumeResize{Phase: v1alpha2.VolumeResizeFileSystemResizePending, RequestedSize: resource.MustParse("20Gi")}
+		reconciler, notifications := newReconciler(jenkins, newClaim(jenkins, "20Gi", "10Gi", corev1.PersistentVolumeClaimCondition{
+			Type:               corev1.PersistentVolumeClaimFileSystemResizePending,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-jenkinsHomeFileSystemResizeTimeout)),
+		}))
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: resources.GetJenkinsMasterPodName(jenkins), Namespace: defaultNamespace}}
+		require.NoError(t, reconciler.Client.Create(context.TODO(), pod))
+
+		result, err := reconciler.reconcileJenkinsHomeVolumeResize()
+
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+		assert.True(t, jenkins.Status.JenkinsHomeVolumeResize.PodRestarted)
+		err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Namespace: defaultNamespace, Name: pod.Name}, &corev1.Pod{})
+		assert.True(t, apierrors.IsNotFound(err))
+		notification := <-notifications
+		assert.Equal(t, []string{"Jenkins master pod restarted by operator: Jenkins home volume file system resize requires Jenkins master pod restart"}, notification.Reason.Short())
+	})
+	t.Run("completed", func(t *testing.T) {
+		jenkins := newJenkins("20Gi")
+		jenkins.Status.JenkinsHomeVolumeResize = &v1alpha2.VolumeResize{Phase: v1alpha2.VolumeResizeFileSystemResizePending, RequestedSize: resource.MustParse("20Gi")}
+		reconciler, notifications := newReconciler(jenkins, newClaim(jenkins, "20Gi", "20Gi"))
+
+		result, err := reconciler.reconcileJenkinsHomeVolumeResize()
+
+		require.NoError(t, err)
+		assert.False(t, result.Requeue)
+		resize := jenkins.Status.JenkinsHomeVolumeResize
+		assert.Equal(t, v1alpha2.VolumeResizeCompleted, resize.Phase)
+		assert.Equal(t, "20Gi", resize.Capacity.String())
+		assert.NotNil(t, resize.CompletionTime)
+		notification := <-notifications
+		assert.Equal(t, []string{"Jenkins home volume has been expanded to 20Gi"}, notification.Reason.Short())
+	})
+}
+
+func TestValidateJenkinsHomePersistentVolumeClaim(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: defaultNamespace}}
+	storageClassName := "standard"
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: resources.GetJenkinsHomePersistentVolumeClaimName(jenkins), Namespace: defaultNamespace},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		},
+	}
+	reconciler := New(configuration.Configuration{
+		Client:  fake.NewClientBuilder().WithObjects(claim).Build(),
+		Jenkins: jenkins,
+	}, client.JenkinsAPIConnectionSettings{})
+
+	t.Run("not set", func(t *testing.T) {
+		got, err := reconciler.validateJenkinsHomePersistentVolumeClaim(nil)
+
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+	t.Run("grow", func(t *testing.T) {
+		got, err := reconciler.validateJenkinsHomePersistentVolumeClaim(&v1alpha2.JenkinsHomePersistentVolumeClaim{
+			Size:             resource.MustParse("20Gi"),
+			StorageClassName: &storageClassName,
+		})
+
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+	t.Run("shrink and change storage class", func(t *testing.T) {
+		otherStorageClassName := "fast"
+
+		got, err := reconciler.validateJenkinsHomePersistentVolumeClaim(&v1alpha2.JenkinsHomePersistentVolumeClaim{
+			Size:             resource.MustParse("5Gi"),
+			StorageClassName: &otherStorageClassName,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"spec.master.jenkinsHomePersistentVolumeClaim.size can't be decreased from 10Gi to 5Gi",
+		}, got)
+	})
+	t.Run("change storage class of Deployment", func(t *testing.T) {
+		otherStorageClassName := "fast"
+		jenkins.Annotations = map[string]string{"jenkins.io/use-deployment": "true"}
+		defer func() { jenkins.Annotations = nil }()
+
+		got, err := reconciler.validateJenkinsHomePersistentVolumeClaim(&v1alpha2.JenkinsHomePersistentVolumeClaim{
+			Size:             resource.MustParse("10Gi"),
+			StorageClassName: &otherStorageClassName,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"spec.master.jenkinsHomePersistentVolumeClaim.storageClassName can't be changed when Jenkins master is managed by Deployment",
+		}, got)
+	})
+	t.Run("zero size", func(t *testing.T) {
+		got, err := reconciler.validateJenkinsHomePersistentVolumeClaim(&v1alpha2.JenkinsHomePersistentVolumeClaim{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"spec.master.jenkinsHomePersistentVolumeClaim.size must be greater than zero"}, got)
+	})
+}
@@ -8,31 +8,24 @@ import (
 
 	stackerr "github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
-func (r *JenkinsBaseConfigurationReconciler) addLabelForWatchesResources(customization v1alpha2.Customization) error {
-	labelsForWatchedResources := resources.BuildLabelsForWatchedResources(*r.Configuration.Jenkins)
+// watchedResourcesFieldManager is the server-side apply field manager owning the labels of watched resources
+const watchedResourcesFieldManager = "jenkins-operator-watched-resources"
 
+func (r *JenkinsBaseConfigurationReconciler) addLabelForWatchesResources(customization v1alpha2.Customization) error {
+	var objects []client.Object
 	if len(customization.Secret.Name) > 0 {
 		secret := &corev1.Secret{}
 		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: customization.Secret.Name, Namespace: r.Configuration.Jenkins.Namespace}, secret)
 		if err != nil {
 			return stackerr.WithStack(err)
 		}
-
-		if !resources.VerifyIfLabelsAreSet(secret, labelsForWatchedResources) {
-			if len(secret.ObjectMeta.Labels) == 0 {
-				secret.ObjectMeta.Labels = map[string]string{}
-			}
-			for key, value := range labelsForWatchedResources {
-				secret.ObjectMeta.Labels[key] = value
-			}
-
-			if err = r.Client.Update(context.TODO(), secret); err != nil {
-				return stackerr.WithStack(r.Client.Update(context.TODO(), secret))
-			}
-		}
+		objects = append(objects, secret)
 	}
 
 	for _, configMapRef := range customization.Configurations {
@@ -41,19 +34,52 @@ func (r *JenkinsBaseConfigurationReconciler) addLabelForWatchesResources(customi
 		if err != nil {
 			return stackerr.WithStack(err)
 		}
+		objects = append(objects, configMap)
+	}
+
+	return r.addLabelsForWatchedResources(objects...)
+}
+
+// addLabelForWatchedValues sets the labels of watched resources on the spec.values ConfigMap, so the groovy scripts and
+// the base configuration are rendered again when the values change
+func (r *JenkinsBaseConfigurationReconciler) addLabelForWatchedValues() error {
+	values := r.Configuration.Jenkins.Spec.Values
+	if values == nil {
+		return nil
+	}
+	configMap := &corev1.ConfigMap{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: values.Name, Namespace: r.Configuration.Jenkins.Namespace}, configMap)
+	if err != nil {
+		return stackerr.WithStack(err)
+	}
+	return r.addLabelsForWatchedResources(configMap)
+}
+
+// addLabelsForWatchedResources sets the labels of watched resources on the objects which don't have them yet. Only
+// the labels are sent with server-side apply, so the change doesn't conflict with concurrent reconcile loops or
+// changes made by users, and the objects already labelled are skipped to not generate update events which would
+// trigger the reconcile loop again.
+func (r *JenkinsBaseConfigurationReconciler) addLabelsForWatchedResources(objects ...client.Object) error {
+	labelsForWatchedResources := resources.BuildLabelsForWatchedResources(*r.Configuration.Jenkins)
+	for _, object := range objects {
+		if resources.VerifyIfLabelsAreSet(object, labelsForWatchedResources) {
+			continue
+		}
 
-		if !resources.VerifyIfLabelsAreSet(configMap, labelsForWatchedResources) {
-			if len(configMap.ObjectMeta.Labels) == 0 {
-				configMap.ObjectMeta.Labels = map[string]string{}
-			}
-			for key, value := range labelsForWatchedResources {
-				configMap.ObjectMeta.Labels[key] = value
-			}
-
-			if err = r.Client.Update(context.TODO(), configMap); err != nil {
-				return stackerr.WithStack(r.Client.Update(context.TODO(), configMap))
-			}
+		gvk, err := apiutil.GVKForObject(object, r.Client.Scheme())
+		if err != nil {
+			return stackerr.WithStack(err)
+		}
+		patch := &unstructured.Unstructured{}
+		patch.SetGroupVersionKind(gvk)
+		patch.SetNamespace(object.GetNamespace())
+		patch.SetName(object.GetName())
+		patch.SetLabels(labelsForWatchedResources)
+		err = r.Client.Patch(context.TODO(), patch, client.Apply, client.FieldOwner(watchedResourcesFieldManager), client.ForceOwnership)
+		if err != nil {
+			return stackerr.WithStack(err)
 		}
+		object.SetLabels(resources.MergeMaps(object.GetLabels(), labelsForWatchedResources))
 	}
 	return nil
 }
@@ -0,0 +1,106 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// applyPatchClient emulates server-side apply patches which aren't supported by the fake client, the object is
+// created when it doesn't exist, otherwise the patch is sent as a merge patch
+type applyPatchClient struct {
+	client.Client
+	patches int
+}
+
+func (c *applyPatchClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.patches++
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	err = c.Client.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, current)
+	if apierrors.IsNotFound(err) {
+		return c.Client.Create(ctx, obj)
+	} else if err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, client.RawPatch(types.MergePatchType, data), opts...)
+}
+
+func TestAddLabelForWatchesResources(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"}}
+	customization := v1alpha2.Customization{
+		Secret:         v1alpha2.SecretRef{Name: "groovy-secret"},
+		Configurations: []v1alpha2.ConfigMapRef{{Name: "groovy-scripts"}},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "groovy-secret", Namespace: "default", Labels: map[string]string{"team": "a"}},
+		Data:       map[string][]byte{"TOKEN": []byte("token")},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "groovy-scripts", Namespace: "default"},
+		Data:       map[string]string{"1-script.groovy": "println 'hello'"},
+	}
+	k8sClient := &applyPatchClient{Client: fake.NewClientBuilder().WithObjects(secret, configMap).Build()}
+	reconciler := New(configuration.Configuration{Client: k8sClient, Jenkins: jenkins}, jenkinsclient.JenkinsAPIConnectionSettings{})
+
+	err := reconciler.addLabelForWatchesResources(customization)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, k8sClient.patches)
+	watchedLabels := resources.BuildLabelsForWatchedResources(*jenkins)
+	actualSecret := &corev1.Secret{}
+	require.NoError(t, k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: secret.Name}, actualSecret))
+	assert.True(t, resources.VerifyIfLabelsAreSet(actualSecret, watchedLabels))
+	assert.Equal(t, "a", actualSecret.Labels["team"])
+	assert.Equal(t, secret.Data, actualSecret.Data)
+	actualConfigMap := &corev1.ConfigMap{}
+	require.NoError(t, k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: configMap.Name}, actualConfigMap))
+	assert.True(t, resources.VerifyIfLabelsAreSet(actualConfigMap, watchedLabels))
+
+	err = reconciler.addLabelForWatchesResources(customization)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, k8sClient.patches)
+}
+
+func TestAddLabelForWatchedValues(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"}}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "values", Namespace: "default"},
+		Data:       map[string]string{"numExecutors": "2"},
+	}
+	k8sClient := &applyPatchClient{Client: fake.NewClientBuilder().WithObjects(configMap).Build()}
+	reconciler := New(configuration.Configuration{Client: k8sClient, Jenkins: jenkins}, jenkinsclient.JenkinsAPIConnectionSettings{})
+
+	require.NoError(t, reconciler.addLabelForWatchedValues())
+	assert.Equal(t, 0, k8sClient.patches)
+
+	jenkins.Spec.Values = &v1alpha2.ConfigMapRef{Name: configMap.Name}
+	require.NoError(t, reconciler.addLabelForWatchedValues())
+
+	assert.Equal(t, 1, k8sClient.patches)
+	actualConfigMap := &corev1.ConfigMap{}
+	require.NoError(t, k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: configMap.Name}, actualConfigMap))
+	assert.True(t, resources.VerifyIfLabelsAreSet(actualConfigMap, resources.BuildLabelsForWatchedResources(*jenkins)))
+}
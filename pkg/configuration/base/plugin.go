@@ -1,21 +1,67 @@
 package base
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
 	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
 
 	"github.com/bndr/gojenkins"
 	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/metrics"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
 	"github.com/maximba/kubernetes-operator/pkg/plugins"
+	"github.com/maximba/kubernetes-operator/pkg/updates"
 	stackerr "github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func (r *JenkinsBaseConfigurationReconciler) verifyPlugins(jenkinsClient jenkinsclient.Jenkins) (bool, error) {
+const (
+	// pluginUpgradeRetryDelay is the delay before the plugin upgrade which hasn't fixed the installed plugins is
+	// installed again, it doubles with every attempt
+	pluginUpgradeRetryDelay = 10 * time.Minute
+	// pluginUpgradeMaxRetryDelay is the maximum delay between attempts of the same plugin upgrade
+	pluginUpgradeMaxRetryDelay = 24 * time.Hour
+)
+
+// pluginDrift is a plugin which installed version doesn't match the spec
+type pluginDrift struct {
+	name string
+	// required is the version from the spec or the plugins lock, empty when the plugin isn't locked
+	required string
+	// installed is the version installed in Jenkins, empty when the plugin isn't installed
+	installed string
+	// target is the version the plugin is upgraded to according to the plugin upgrade policy
+	target string
+}
+
+func (d pluginDrift) String() string {
+	switch {
+	case len(d.installed) == 0:
+		return fmt.Sprintf("'%s' is missing, required '%s'", d.name, d.target)
+	case len(d.required) == 0:
+		return fmt.Sprintf("'%s:%s' is not locked", d.name, d.installed)
+	default:
+		return fmt.Sprintf("'%s' version '%s', required '%s'", d.name, d.installed, d.target)
+	}
+}
+
+// isUpgrade returns true if the drift can be fixed by upgrading the installed plugin by the update center API
+func (d pluginDrift) isUpgrade() bool {
+	return len(d.installed) > 0 && d.target != d.required && updates.CompareVersions(d.target, d.installed) > 0
+}
+
+func (r *JenkinsBaseConfigurationReconciler) verifyPlugins(jenkinsClient jenkinsclient.Jenkins) ([]pluginDrift, error) {
 	allPluginsInJenkins, err := jenkinsClient.GetPlugins(fetchAllPlugins)
 	if err != nil {
-		return false, stackerr.WithStack(err)
+		return nil, stackerr.WithStack(err)
 	}
 
 	var installedPlugins []string
@@ -26,23 +72,244 @@ func (r *JenkinsBaseConfigurationReconciler) verifyPlugins(jenkinsClient jenkins
 	}
 	r.logger.V(log.VDebug).Info(fmt.Sprintf("Installed plugins '%+v'", installedPlugins))
 
-	status := true
+	var drifts []pluginDrift
+	policy := r.Configuration.Jenkins.Spec.Master.PluginUpgradePolicy
+	availableUpdates := getAvailablePluginUpdates(r.Configuration.Jenkins)
 	allRequiredPlugins := [][]v1alpha2.Plugin{r.Configuration.Jenkins.Spec.Master.BasePlugins, r.Configuration.Jenkins.Spec.Master.Plugins}
 	for _, requiredPlugins := range allRequiredPlugins {
 		for _, plugin := range requiredPlugins {
-			if _, ok := isPluginInstalled(allPluginsInJenkins, plugin); !ok {
+			target := getPluginUpgradeTarget(policy, plugin.Version, availableUpdates[plugin.Name])
+			found, ok := isPluginInstalled(allPluginsInJenkins, plugin)
+			if !ok {
 				r.logger.V(log.VWarn).Info(fmt.Sprintf("Missing plugin '%s'", plugin))
-				status = false
+				drifts = append(drifts, pluginDrift{name: plugin.Name, required: plugin.Version, target: target})
 				continue
 			}
-			if found, ok := isPluginVersionCompatible(allPluginsInJenkins, plugin); !ok {
+			if !isPluginVersionAccepted(policy, plugin.Version, target, found.Version) {
 				r.logger.V(log.VWarn).Info(fmt.Sprintf("Incompatible plugin '%s' version, actual '%+v'", plugin, found.Version))
-				status = false
+				drifts = append(drifts, pluginDrift{name: plugin.Name, required: plugin.Version, installed: found.Version, target: target})
 			}
 		}
 	}
 
-	return status, nil
+	lockedPlugins := resources.GetLockedPlugins(r.Configuration.Jenkins)
+	if len(lockedPlugins) > 0 {
+		locked := map[string]bool{}
+		for _, plugin := range lockedPlugins {
+			locked[plugin.Name] = true
+			if found, ok := isPluginVersionCompatible(allPluginsInJenkins, plugin); !ok || !isValidPlugin(found) {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Locked plugin '%s' is not installed, actual '%+v'", plugin, found.Version))
+				drifts = append(drifts, pluginDrift{name: plugin.Name, required: plugin.Version, installed: found.Version, target: plugin.Version})
+			}
+		}
+		for _, jenkinsPlugin := range allPluginsInJenkins.Raw.Plugins {
+			if isValidPlugin(jenkinsPlugin) && !locked[jenkinsPlugin.ShortName] {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugin '%s:%s' is not locked", jenkinsPlugin.ShortName, jenkinsPlugin.Version))
+				drifts = append(drifts, pluginDrift{name: jenkinsPlugin.ShortName, installed: jenkinsPlugin.Version})
+			}
+		}
+	}
+
+	metrics.SetPluginMismatches(r.Configuration.Jenkins, len(drifts))
+	return drifts, nil
+}
+
+// upgradePlugins installs the target versions of the drifted plugins by the update center API and safely restarts
+// Jenkins, it returns false when the same upgrade has already been attempted and it isn't time to retry it yet
+func (r *JenkinsBaseConfigurationReconciler) upgradePlugins(jenkinsClient jenkinsclient.Jenkins, drifts []pluginDrift) (bool, error) {
+	status := &r.Configuration.Jenkins.Status
+	upgrades := getPluginUpgrades(drifts)
+	attempts := 0
+	if reflect.DeepEqual(upgrades, status.PluginUpgrades) {
+		if retryTime := getPluginUpgradeRetryTime(*status); time.Now().Before(retryTime) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugins upgrade '%s' has already been attempted, retrying at %s",
+				strings.Join(upgrades, ", "), retryTime.Format(time.RFC3339)))
+			return false, nil
+		}
+		attempts = status.PluginUpgradeAttempts
+	}
+
+	for _, drift := range drifts {
+		r.logger.Info(fmt.Sprintf("Upgrading plugin '%s' from '%s' to '%s'", drift.name, drift.installed, drift.target))
+		if err := jenkinsClient.InstallPlugin(drift.name, drift.target); err != nil {
+			return false, stackerr.Wrapf(err, "failed to upgrade plugin '%s'", drift.name)
+		}
+	}
+	now := metav1.Now()
+	status.PluginUpgrades = upgrades
+	status.PluginUpgradeAttempts = attempts + 1
+	status.PluginUpgradeTime = &now
+	if err := r.Client.Status().Update(context.TODO(), r.Configuration.Jenkins); err != nil {
+		return false, stackerr.WithStack(err)
+	}
+	r.logger.Info("Plugins have been upgraded, restarting Jenkins safely")
+	return true, stackerr.WithStack(jenkinsClient.SafeRestart())
+}
+
+// getPluginUpgrades returns the target versions of the drifted plugins in name:version format
+func getPluginUpgrades(drifts []pluginDrift) []string {
+	var upgrades []string
+	for _, drift := range drifts {
+		upgrades = append(upgrades, plugins.Plugin{Name: drift.name, Version: drift.target}.String())
+	}
+	sort.Strings(upgrades)
+	return upgrades
+}
+
+// getPluginUpgradeRetryTime returns the time the attempted plugin upgrade is installed again, the delay doubles with
+// every attempt up to pluginUpgradeMaxRetryDelay
+func getPluginUpgradeRetryTime(status v1alpha2.JenkinsStatus) time.Time {
+	if status.PluginUpgradeTime == nil {
+		return time.Time{}
+	}
+	delay := pluginUpgradeRetryDelay
+	for i := 1; i < status.PluginUpgradeAttempts && delay < pluginUpgradeMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > pluginUpgradeMaxRetryDelay {
+		delay = pluginUpgradeMaxRetryDelay
+	}
+	return status.PluginUpgradeTime.Add(delay)
+}
+
+// newPluginsRestartReason returns the reason of Jenkins master pod restart listing the changed plugins,
+// the verbose messages contain installed and required versions
+func newPluginsRestartReason(drifts []pluginDrift) *reason.PodRestart {
+	short := fmt.Sprintf("Some plugins have changed: %s", strings.Join(pluginDriftNames(drifts), ", "))
+	return reason.NewPodRestart(reason.OperatorSource, []string{short}, pluginDriftMessages(drifts)...)
+}
+
+func pluginDriftMessages(drifts []pluginDrift) []string {
+	var messages []string
+	for _, drift := range drifts {
+		messages = append(messages, fmt.Sprintf("Plugin %s", drift))
+	}
+	return messages
+}
+
+func pluginDriftNames(drifts []pluginDrift) []string {
+	var names []string
+	for _, drift := range drifts {
+		names = append(names, drift.name)
+	}
+	return names
+}
+
+// isPluginsUpgrade returns true if all drifted plugins can be upgraded without Jenkins master pod restart
+func isPluginsUpgrade(drifts []pluginDrift) bool {
+	for _, drift := range drifts {
+		if !drift.isUpgrade() {
+			return false
+		}
+	}
+	return len(drifts) > 0
+}
+
+// clearPluginUpgrades forgets the attempted plugins upgrade when the installed plugins match the spec, so the upgrade
+// is applied again e.g. after Jenkins master pod restart
+func (r *JenkinsBaseConfigurationReconciler) clearPluginUpgrades() error {
+	status := &r.Configuration.Jenkins.Status
+	if len(status.PluginUpgrades) == 0 && status.PluginUpgradeAttempts == 0 && status.PluginUpgradeTime == nil {
+		return nil
+	}
+	status.PluginUpgrades = nil
+	status.PluginUpgradeAttempts = 0
+	status.PluginUpgradeTime = nil
+	return stackerr.WithStack(r.Client.Status().Update(context.TODO(), r.Configuration.Jenkins))
+}
+
+// getAvailablePluginUpdates returns the latest plugin versions reported in status.availableUpdates by plugin name
+func getAvailablePluginUpdates(jenkins *v1alpha2.Jenkins) map[string]string {
+	available := map[string]string{}
+	if jenkins.Status.AvailableUpdates == nil {
+		return available
+	}
+	for _, nameWithVersion := range jenkins.Status.AvailableUpdates.Plugins {
+		if plugin, err := plugins.New(nameWithVersion); err == nil {
+			available[plugin.Name] = plugin.Version
+		}
+	}
+	return available
+}
+
+// getPluginUpgradeTarget returns the version the plugin is upgraded to, the latest version is used when it's
+// allowed by the policy
+func getPluginUpgradeTarget(policy v1alpha2.PluginUpgradePolicy, required, latest string) string {
+	if len(latest) > 0 && isPluginUpgradeAllowed(policy, required, latest) {
+		return latest
+	}
+	return required
+}
+
+// isPluginVersionAccepted returns true if the installed version is the target one or it's newer and allowed by the policy
+func isPluginVersionAccepted(policy v1alpha2.PluginUpgradePolicy, required, target, installed string) bool {
+	if installed == target {
+		return true
+	}
+	return updates.CompareVersions(installed, target) > 0 && isPluginUpgradeAllowed(policy, required, installed)
+}
+
+// isPluginUpgradeAllowed returns true if the policy allows upgrading the plugin from the required version to the newer one
+func isPluginUpgradeAllowed(policy v1alpha2.PluginUpgradePolicy, required, newer string) bool {
+	if updates.CompareVersions(newer, required) <= 0 {
+		return false
+	}
+	switch policy {
+	case v1alpha2.LatestPluginUpgradePolicy:
+		return true
+	case v1alpha2.PatchOnlyPluginUpgradePolicy:
+		requiredParts, newerParts := strings.Split(required, "."), strings.Split(newer, ".")
+		if len(requiredParts) != len(newerParts) {
+			return false
+		}
+		last := len(requiredParts) - 1
+		return reflect.DeepEqual(requiredParts[:last], newerParts[:last])
+	default:
+		return false
+	}
+}
+
+// setPluginsVerifiedCondition records the result of plugins verification in status conditions
+func (r *JenkinsBaseConfigurationReconciler) setPluginsVerifiedCondition(drifts []pluginDrift) error {
+	status, conditionReason, message := metav1.ConditionTrue, configuration.ConditionReasonPluginsMatch, "Installed plugins match the spec"
+	jenkinsStatus := r.Configuration.Jenkins.Status
+	if len(drifts) > 0 && isPluginsUpgrade(drifts) && reflect.DeepEqual(getPluginUpgrades(drifts), jenkinsStatus.PluginUpgrades) {
+		status, conditionReason, message = metav1.ConditionFalse, configuration.ConditionReasonPluginsUpgradeFailed,
+			fmt.Sprintf("Plugins upgrade has been attempted %d times, next attempt at %s: %s", jenkinsStatus.PluginUpgradeAttempts,
+				getPluginUpgradeRetryTime(jenkinsStatus).Format(time.RFC3339), strings.Join(pluginDriftMessages(drifts), ", "))
+	} else if len(drifts) > 0 {
+		status, conditionReason, message = metav1.ConditionFalse, configuration.ConditionReasonPluginsChanged,
+			fmt.Sprintf("Some plugins have changed: %s", strings.Join(pluginDriftNames(drifts), ", "))
+	}
+	if !configuration.SetCondition(r.Configuration.Jenkins, v1alpha2.ConditionPluginsVerified, status, conditionReason, message) {
+		return nil
+	}
+	return stackerr.WithStack(r.Client.Status().Update(context.TODO(), r.Configuration.Jenkins))
+}
+
+// ensurePluginsLock records all installed plugins including dependencies in the status when the lock is missing
+// or it has been recorded for different plugins in the spec
+func (r *JenkinsBaseConfigurationReconciler) ensurePluginsLock(jenkinsClient jenkinsclient.Jenkins) error {
+	specHash := resources.GetPluginsSpecHash(r.Configuration.Jenkins)
+	if lock := r.Configuration.Jenkins.Status.PluginsLock; lock != nil && lock.SpecHash == specHash {
+		return nil
+	}
+
+	allPluginsInJenkins, err := jenkinsClient.GetPlugins(fetchAllPlugins)
+	if err != nil {
+		return stackerr.WithStack(err)
+	}
+	lockedPlugins := []string{}
+	for _, jenkinsPlugin := range allPluginsInJenkins.Raw.Plugins {
+		if isValidPlugin(jenkinsPlugin) {
+			lockedPlugins = append(lockedPlugins, plugins.Plugin{Name: jenkinsPlugin.ShortName, Version: jenkinsPlugin.Version}.String())
+		}
+	}
+	sort.Strings(lockedPlugins)
+
+	r.Configuration.Jenkins.Status.PluginsLock = &v1alpha2.PluginsLock{SpecHash: specHash, Plugins: lockedPlugins}
+	r.logger.Info(fmt.Sprintf("Plugins lock recorded with %d plugins", len(lockedPlugins)))
+	return stackerr.WithStack(r.Client.Status().Update(context.TODO(), r.Configuration.Jenkins))
 }
 
 func isPluginVersionCompatible(plugins *gojenkins.Plugins, plugin v1alpha2.Plugin) (gojenkins.Plugin, bool) {
@@ -6,6 +6,7 @@ import (
 	"reflect"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/backuprestore"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
 	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
@@ -19,19 +20,51 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-func (r *JenkinsBaseConfigurationReconciler) checkForPodRecreation(currentJenkinsMasterPod corev1.Pod, userAndPasswordHash string) reason.Reason {
-	var messages []string
-	var verbose []string
-
-	if currentJenkinsMasterPod.Status.Phase == corev1.PodFailed ||
-		currentJenkinsMasterPod.Status.Phase == corev1.PodSucceeded ||
-		currentJenkinsMasterPod.Status.Phase == corev1.PodUnknown {
-		//TODO add Jenkins last 10 line logs
-		messages = append(messages, fmt.Sprintf("Invalid Jenkins pod phase '%s'", currentJenkinsMasterPod.Status.Phase))
-		verbose = append(verbose, fmt.Sprintf("Invalid Jenkins pod phase '%+v'", currentJenkinsMasterPod.Status))
+func (r *JenkinsBaseConfigurationReconciler) checkForPodRecreation(currentJenkinsMasterPod corev1.Pod, userAndPasswordHash, envSourcesHash string) reason.Reason {
+	if isPodPhaseInvalid(currentJenkinsMasterPod) {
+		messages := []string{fmt.Sprintf("Invalid Jenkins pod phase '%s'", currentJenkinsMasterPod.Status.Phase)}
+		verbose := []string{fmt.Sprintf("Invalid Jenkins pod phase '%+v'", currentJenkinsMasterPod.Status)}
+		if message := r.saveCrashLogs(currentJenkinsMasterPod); len(message) > 0 {
+			messages = append(messages, message)
+			verbose = append(verbose, message)
+		}
 		return reason.NewPodRestart(reason.KubernetesSource, messages, verbose...)
 	}
 
+	// the operator recreates the pod to apply Jenkins CR, so the differences are attributed to the operator
+	messages, verbose := r.comparePod(currentJenkinsMasterPod, userAndPasswordHash, envSourcesHash)
+	return reason.NewPodRestart(reason.OperatorSource, messages, verbose...)
+}
+
+// PodDrift returns the differences between Jenkins master pod and the pod required by Jenkins CR which would make
+// the operator restart the pod. Unlike the reconcile loop it doesn't save crash logs of the failed pod.
+func (r *JenkinsBaseConfigurationReconciler) PodDrift(currentJenkinsMasterPod corev1.Pod) ([]string, error) {
+	if isPodPhaseInvalid(currentJenkinsMasterPod) {
+		return []string{fmt.Sprintf("Invalid Jenkins pod phase '%+v'", currentJenkinsMasterPod.Status)}, nil
+	}
+	userAndPasswordHash, err := r.calculateUserAndPasswordHash()
+	if err != nil {
+		return nil, err
+	}
+	envSourcesHash, err := r.calculateEnvSourcesHash()
+	if err != nil {
+		return nil, err
+	}
+	_, verbose := r.comparePod(currentJenkinsMasterPod, userAndPasswordHash, envSourcesHash)
+	return verbose, nil
+}
+
+func isPodPhaseInvalid(pod corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodFailed ||
+		pod.Status.Phase == corev1.PodSucceeded ||
+		pod.Status.Phase == corev1.PodUnknown
+}
+
+// comparePod returns the differences between Jenkins master pod and Jenkins CR
+func (r *JenkinsBaseConfigurationReconciler) comparePod(currentJenkinsMasterPod corev1.Pod, userAndPasswordHash, envSourcesHash string) ([]string, []string) {
+	var messages []string
+	var verbose []string
+
 	userAndPasswordHashIsDifferent := userAndPasswordHash != r.Configuration.Jenkins.Status.UserAndPasswordHash
 	userAndPasswordHashStatusNotEmpty := r.Configuration.Jenkins.Status.UserAndPasswordHash != ""
 
@@ -40,11 +73,23 @@ func (r *JenkinsBaseConfigurationReconciler) checkForPodRecreation(currentJenkin
 		verbose = append(verbose, "User or password have changed, recreating pod")
 	}
 
-	if r.Configuration.Jenkins.Spec.Restore.RecoveryOnce != 0 && r.Configuration.Jenkins.Status.RestoredBackup != 0 {
+	if envSourcesHash != currentJenkinsMasterPod.Annotations[envSourcesHashAnnotation] {
+		messages = append(messages, "Environment variables sources have changed")
+		verbose = append(verbose, "Secrets or ConfigMaps referenced by env or envFrom of Jenkins master containers have changed, recreating pod")
+	}
+
+	if r.Configuration.Jenkins.Spec.Restore.RecoveryOnce != 0 && !r.Configuration.Jenkins.Spec.Restore.DryRun &&
+		r.Configuration.Jenkins.Status.RestoredBackup != 0 {
 		messages = append(messages, "spec.restore.recoveryOnce is set")
 		verbose = append(verbose, "spec.restore.recoveryOnce is set, recreating pod")
 	}
 
+	if migration := r.Configuration.Jenkins.Status.JenkinsHomeVolumeMigration; migration != nil && migration.Phase == v1alpha2.VolumeMigrationCopying {
+		messages = append(messages, "Jenkins home is migrated to another storage class")
+		verbose = append(verbose, fmt.Sprintf("Jenkins home is migrated from PersistentVolumeClaim '%s' to '%s' of storage class '%s', recreating pod",
+			migration.SourceClaimName, migration.TargetClaimName, migration.StorageClassName))
+	}
+
 	if version.Version != r.Configuration.Jenkins.Status.OperatorVersion {
 		messages = append(messages, "Jenkins Operator version has changed")
 		verbose = append(verbose, fmt.Sprintf("Jenkins Operator version has changed, actual '%+v' new '%+v'",
@@ -76,16 +121,18 @@ func (r *JenkinsBaseConfigurationReconciler) checkForPodRecreation(currentJenkin
 			currentJenkinsMasterPod.Spec.NodeSelector, r.Configuration.Jenkins.Spec.Master.NodeSelector))
 	}
 
-	if !compareMap(r.Configuration.Jenkins.Spec.Master.Labels, currentJenkinsMasterPod.Labels) {
+	jenkinsPodLabels := resources.GetJenkinsMasterPodLabels(*r.Configuration.Jenkins)
+	if !compareMap(jenkinsPodLabels, currentJenkinsMasterPod.Labels) {
 		messages = append(messages, "Jenkins pod labels have changed")
 		verbose = append(verbose, fmt.Sprintf("Jenkins pod labels have changed, actual '%+v' required '%+v'",
-			currentJenkinsMasterPod.Labels, r.Configuration.Jenkins.Spec.Master.Labels))
+			currentJenkinsMasterPod.Labels, jenkinsPodLabels))
 	}
 
-	if !compareMap(r.Configuration.Jenkins.Spec.Master.Annotations, currentJenkinsMasterPod.ObjectMeta.Annotations) {
+	jenkinsPodAnnotations := resources.MergeMaps(r.Configuration.Jenkins.Spec.CommonAnnotations, r.Configuration.Jenkins.Spec.Master.Annotations)
+	if !compareMap(jenkinsPodAnnotations, currentJenkinsMasterPod.ObjectMeta.Annotations) {
 		messages = append(messages, "Jenkins pod annotations have changed")
 		verbose = append(verbose, fmt.Sprintf("Jenkins pod annotations have changed, actual '%+v' required '%+v'",
-			currentJenkinsMasterPod.ObjectMeta.Annotations, r.Configuration.Jenkins.Spec.Master.Annotations))
+			currentJenkinsMasterPod.ObjectMeta.Annotations, jenkinsPodAnnotations))
 	}
 
 	if !r.compareVolumes(currentJenkinsMasterPod) {
@@ -100,6 +147,13 @@ func (r *JenkinsBaseConfigurationReconciler) checkForPodRecreation(currentJenkin
 			len(currentJenkinsMasterPod.Spec.Containers), len(r.Configuration.Jenkins.Spec.Master.Containers)))
 	}
 
+	expectedInitContainers := resources.NewJenkinsMasterInitContainers(r.Configuration.Jenkins)
+	if len(expectedInitContainers) != len(currentJenkinsMasterPod.Spec.InitContainers) {
+		messages = append(messages, "Jenkins amount of init containers has changed")
+		verbose = append(verbose, fmt.Sprintf("Jenkins amount of init containers has changed, actual '%+v' required '%+v'",
+			len(currentJenkinsMasterPod.Spec.InitContainers), len(expectedInitContainers)))
+	}
+
 	if r.Configuration.Jenkins.Spec.Master.PriorityClassName != currentJenkinsMasterPod.Spec.PriorityClassName {
 		messages = append(messages, "Jenkins priorityClassName has changed")
 		verbose = append(verbose, fmt.Sprintf("Jenkins priorityClassName has changed, actual '%+v' required '%+v'",
@@ -143,7 +197,27 @@ func (r *JenkinsBaseConfigurationReconciler) checkForPodRecreation(currentJenkin
 		verbose = append(verbose, verboseMessages...)
 	}
 
-	return reason.NewPodRestart(reason.OperatorSource, messages, verbose...)
+	for _, actualContainer := range currentJenkinsMasterPod.Spec.InitContainers {
+		var expectedContainer *corev1.Container
+		for i, initContainer := range expectedInitContainers {
+			if initContainer.Name == actualContainer.Name {
+				expectedContainer = &expectedInitContainers[i]
+			}
+		}
+
+		if expectedContainer == nil {
+			messages = append(messages, fmt.Sprintf("Init container '%s' not found in pod", actualContainer.Name))
+			verbose = append(verbose, fmt.Sprintf("Init container '%+v' not found in pod", actualContainer))
+			continue
+		}
+
+		containerMessages, verboseMessages := r.compareContainers(*expectedContainer, actualContainer)
+
+		messages = append(messages, containerMessages...)
+		verbose = append(verbose, verboseMessages...)
+	}
+
+	return messages, verbose
 }
 
 func (r *JenkinsBaseConfigurationReconciler) ensureJenkinsMasterPod(meta metav1.ObjectMeta) (reconcile.Result, error) {
@@ -151,11 +225,31 @@ func (r *JenkinsBaseConfigurationReconciler) ensureJenkinsMasterPod(meta metav1.
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	envSourcesHash, err := r.calculateEnvSourcesHash()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 
 	// Check if this Pod already exists
 	currentJenkinsMasterPod, err := r.Configuration.GetJenkinsMasterPod()
 	if err != nil && apierrors.IsNotFound(err) {
+		result, rolledBack, err := r.ensureImageRollbackBeforePodCreation()
+		if err != nil || result.Requeue {
+			return result, err
+		}
+		result, err = r.copyJenkinsHomeBeforePodCreation()
+		if err != nil || result.Requeue {
+			return result, err
+		}
+
 		jenkinsMasterPod := resources.NewJenkinsMasterPod(meta, r.Configuration.Jenkins)
+		if len(envSourcesHash) > 0 {
+			jenkinsMasterPod.Annotations = resources.MergeMaps(jenkinsMasterPod.Annotations, map[string]string{envSourcesHashAnnotation: envSourcesHash})
+		}
+		result, err = r.ensureResourceQuotaHeadroom(*jenkinsMasterPod)
+		if err != nil || result.Requeue {
+			return result, err
+		}
 		*r.Notifications <- event.Event{
 			Jenkins: *r.Configuration.Jenkins,
 			Phase:   event.PhaseBase,
@@ -168,13 +262,10 @@ func (r *JenkinsBaseConfigurationReconciler) ensureJenkinsMasterPod(meta metav1.
 			return reconcile.Result{}, stackerr.WithStack(err)
 		}
 
-		now := metav1.Now()
-		r.Configuration.Jenkins.Status = v1alpha2.JenkinsStatus{
-			OperatorVersion:     version.Version,
-			ProvisionStartTime:  &now,
-			LastBackup:          r.Configuration.Jenkins.Status.LastBackup,
-			PendingBackup:       r.Configuration.Jenkins.Status.LastBackup,
-			UserAndPasswordHash: userAndPasswordHash,
+		configuration.ResetJenkinsMasterPodStatus(r.Configuration.Jenkins, userAndPasswordHash)
+		if rolledBack {
+			// Jenkins home has been recreated from the snapshot, restoring the backup would overwrite it
+			r.Configuration.Jenkins.Status.RestoredBackup = r.Configuration.Jenkins.Status.LastBackup
 		}
 		return reconcile.Result{Requeue: true}, r.Client.Status().Update(context.TODO(), r.Configuration.Jenkins)
 	} else if err != nil && !apierrors.IsNotFound(err) {
@@ -203,15 +294,20 @@ func (r *JenkinsBaseConfigurationReconciler) ensureJenkinsMasterPod(meta metav1.
 	}
 
 	if !r.IsJenkinsTerminating(*currentJenkinsMasterPod) {
-		restartReason := r.checkForPodRecreation(*currentJenkinsMasterPod, userAndPasswordHash)
+		restartReason := r.checkForPodRecreation(*currentJenkinsMasterPod, userAndPasswordHash, envSourcesHash)
 		if restartReason.HasMessages() {
 			for _, msg := range restartReason.Verbose() {
 				r.logger.Info(msg)
 			}
 
-			return reconcile.Result{Requeue: true}, r.Configuration.RestartJenkinsMasterPod(restartReason)
+			if err = r.prepareImageRollback(*currentJenkinsMasterPod); err != nil {
+				return reconcile.Result{}, err
+			}
+			// the restart is batched with other changes found later in the reconcile loop, e.g. changed plugins
+			restarted, err := r.Configuration.RequestJenkinsMasterPodRestart(restartReason)
+			return reconcile.Result{Requeue: restarted}, err
 		}
 	}
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{}, r.ensureImageRollbackExpiration()
 }
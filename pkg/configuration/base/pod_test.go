@@ -0,0 +1,483 @@
+package base
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+	"github.com/maximba/kubernetes-operator/version"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestCheckForPodRecreation(t *testing.T) {
+	log.SetupLogger(true)
+	jenkinsWithCommonMeta := func() *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			Spec: v1alpha2.JenkinsSpec{
+				CommonLabels:      map[string]string{"team": "ci"},
+				CommonAnnotations: map[string]string{"cost-center": "42"},
+				Master: v1alpha2.JenkinsMaster{
+					Containers: []v1alpha2.Container{{Name: resources.JenkinsMasterContainerName, ReadinessProbe: &corev1.Probe{}}},
+				},
+			},
+		}
+	}
+
+	t.Run("common labels and annotations are set", func(t *testing.T) {
+		jenkins := jenkinsWithCommonMeta()
+		reconciler := New(configuration.Configuration{Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+		pod := resources.NewJenkinsMasterPod(resources.NewResourceObjectMeta(jenkins), jenkins)
+
+		restartReason := reconciler.checkForPodRecreation(*pod, "", "")
+
+		assert.NotContains(t, restartReason.Short(), "Jenkins pod labels have changed")
+		assert.NotContains(t, restartReason.Short(), "Jenkins pod annotations have changed")
+	})
+	t.Run("common label has been added", func(t *testing.T) {
+		jenkins := jenkinsWithCommonMeta()
+		reconciler := New(configuration.Configuration{Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+		pod := resources.NewJenkinsMasterPod(resources.NewResourceObjectMeta(jenkins), jenkins)
+		jenkins.Spec.CommonLabels["env"] = "prod"
+
+		restartReason := reconciler.checkForPodRecreation(*pod, "", "")
+
+		assert.Contains(t, restartReason.Short(), "Jenkins pod labels have changed")
+		assert.NotContains(t, restartReason.Short(), "Jenkins pod annotations have changed")
+	})
+	t.Run("common annotation has changed", func(t *testing.T) {
+		jenkins := jenkinsWithCommonMeta()
+		reconciler := New(configuration.Configuration{Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+		pod := resources.NewJenkinsMasterPod(resources.NewResourceObjectMeta(jenkins), jenkins)
+		jenkins.Spec.CommonAnnotations["cost-center"] = "43"
+
+		restartReason := reconciler.checkForPodRecreation(*pod, "", "")
+
+		assert.Contains(t, restartReason.Short(), "Jenkins pod annotations have changed")
+		assert.NotContains(t, restartReason.Short(), "Jenkins pod labels have changed")
+	})
+	t.Run("init container has been added", func(t *testing.T) {
+		jenkins := jenkinsWithCommonMeta()
+		reconciler := New(configuration.Configuration{Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+		pod := resources.NewJenkinsMasterPod(resources.NewResourceObjectMeta(jenkins), jenkins)
+		jenkins.Spec.Master.InitContainers = []v1alpha2.Container{{Name: "seed-home", Image: "busybox:1.35"}}
+
+		restartReason := reconciler.checkForPodRecreation(*pod, "", "")
+
+		assert.Contains(t, restartReason.Short(), "Jenkins amount of init containers has changed")
+	})
+	t.Run("init container image has changed", func(t *testing.T) {
+		jenkins := jenkinsWithCommonMeta()
+		jenkins.Spec.Master.InitContainers = []v1alpha2.Container{{Name: "seed-home", Image: "busybox:1.35"}}
+		reconciler := New(configuration.Configuration{Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+		pod := resources.NewJenkinsMasterPod(resources.NewResourceObjectMeta(jenkins), jenkins)
+		jenkins.Spec.Master.InitContainers[0].Image = "busybox:1.36"
+
+		restartReason := reconciler.checkForPodRecreation(*pod, "", "")
+
+		assert.Contains(t, restartReason.Short(), "Image has changed")
+		assert.NotContains(t, restartReason.Short(), "Jenkins amount of init containers has changed")
+	})
+	t.Run("restart source", func(t *testing.T) {
+		jenkins := jenkinsWithCommonMeta()
+		jenkins.Status = v1alpha2.JenkinsStatus{OperatorVersion: version.Version, UserAndPasswordHash: "hash"}
+		pod := resources.NewJenkinsMasterPod(resources.NewResourceObjectMeta(jenkins), jenkins)
+		jenkins.Spec.CommonLabels["env"] = "prod"
+		restartReason := New(configuration.Configuration{Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{}).checkForPodRecreation(*pod, "hash", "")
+		assert.Equal(t, reason.OperatorSource, restartReason.Source())
+
+		pod.Status.Phase = corev1.PodFailed
+		restartReason = New(configuration.Configuration{Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{}).checkForPodRecreation(*pod, "hash", "")
+		assert.Equal(t, reason.KubernetesSource, restartReason.Source())
+	})
+	t.Run("drift of failed pod", func(t *testing.T) {
+		jenkins := jenkinsWithCommonMeta()
+		pod := resources.NewJenkinsMasterPod(resources.NewResourceObjectMeta(jenkins), jenkins)
+		pod.Status.Phase = corev1.PodFailed
+
+		drift, err := New(configuration.Configuration{Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{}).PodDrift(*pod)
+
+		assert.NoError(t, err)
+		require.Len(t, drift, 1)
+		assert.Contains(t, drift[0], "Invalid Jenkins pod phase")
+	})
+}
+
+func TestDiagnoseJenkinsMasterPod(t *testing.T) {
+	log.SetupLogger(true)
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"}}
+
+	t.Run("no issues", func(t *testing.T) {
+		reconciler := New(configuration.Configuration{Client: fake.NewClientBuilder().Build(), Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+
+		causes, err := reconciler.diagnoseJenkinsMasterPod(corev1.Pod{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, causes)
+	})
+	t.Run("unschedulable and image pull back off", func(t *testing.T) {
+		reconciler := New(configuration.Configuration{Client: fake.NewClientBuilder().Build(), Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+		pod := corev1.Pod{
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{
+						Type:    corev1.PodScheduled,
+						Status:  corev1.ConditionFalse,
+						Reason:  corev1.PodReasonUnschedulable,
+						Message: "0/3 nodes are available: 3 Insufficient memory.",
+					},
+				},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:  resources.JenkinsMasterContainerName,
+						Image: "jenkins/jenkins:missing",
+						State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}},
+					},
+					{
+						Name:  "sidecar",
+						State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+					},
+				},
+			},
+		}
+
+		causes, err := reconciler.diagnoseJenkinsMasterPod(pod)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"pod is unschedulable: 0/3 nodes are available: 3 Insufficient memory.",
+			"container 'jenkins-master' can't pull image 'jenkins/jenkins:missing': Back-off pulling image",
+		}, causes)
+	})
+	t.Run("persistent volume claims", func(t *testing.T) {
+		fakeClient := fake.NewClientBuilder().Build()
+		reconciler := New(configuration.Configuration{Client: fakeClient, Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+		pendingClaim := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		}
+		boundClaim := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "bound", Namespace: "default"},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+		}
+		assert.NoError(t, fakeClient.Create(context.TODO(), pendingClaim))
+		assert.NoError(t, fakeClient.Create(context.TODO(), boundClaim))
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+			Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{
+					{Name: "pending", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "pending"}}},
+					{Name: "bound", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "bound"}}},
+					{Name: "missing", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "missing"}}},
+				},
+			},
+		}
+
+		causes, err := reconciler.diagnoseJenkinsMasterPod(pod)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"persistent volume claim 'pending' is pending",
+			"persistent volume claim 'missing' not found",
+		}, causes)
+	})
+}
+
+func TestDetectJenkinsMasterPodStartingIssues(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	provisionStartTime := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: defaultNamespace},
+		Status:     v1alpha2.JenkinsStatus{ProvisionStartTime: &provisionStartTime},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: resources.GetJenkinsMasterPodName(jenkins), Namespace: defaultNamespace}}
+	pod.Status = corev1.PodStatus{
+		Phase: corev1.PodPending,
+		Conditions: []corev1.PodCondition{{
+			Type:    corev1.PodScheduled,
+			Status:  corev1.ConditionFalse,
+			Reason:  corev1.PodReasonUnschedulable,
+			Message: "0/3 nodes are available: 3 Insufficient memory.",
+		}},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(jenkins, pod).Build()
+	notifications := make(chan event.Event, 2)
+	recorder := &fakeRecorder{}
+	reconciler := New(configuration.Configuration{
+		Client:        fakeClient,
+		Jenkins:       jenkins,
+		Notifications: &notifications,
+		Events:        recorder,
+	}, client.JenkinsAPIConnectionSettings{})
+	diagnosis := "Jenkins master pod has been pending for more than 2m0s: pod is unschedulable: 0/3 nodes are available: 3 Insufficient memory."
+
+	t.Run("pending timeout", func(t *testing.T) {
+		result, stop, err := reconciler.detectJenkinsMasterPodStartingIssues()
+
+		require.NoError(t, err)
+		assert.True(t, stop)
+		assert.Equal(t, podStartingBackoffBase, result.RequeueAfter)
+		assert.Equal(t, diagnosis, jenkins.Status.PodStartingDiagnosis)
+		require.NotNil(t, jenkins.Status.BackoffUntil)
+		require.Len(t, recorder.events, 1)
+		assert.Equal(t, k8sevent.TypeWarning, recorder.events[0].eventType)
+		assert.Equal(t, podPendingEventReason, recorder.events[0].reason)
+		assert.Equal(t, diagnosis+", the pod will be recreated in 1m0s", recorder.events[0].message)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, []string{diagnosis}, (<-notifications).Reason.Short())
+	})
+	t.Run("backoff in progress", func(t *testing.T) {
+		result, stop, err := reconciler.detectJenkinsMasterPodStartingIssues()
+
+		require.NoError(t, err)
+		assert.True(t, stop)
+		assert.True(t, result.RequeueAfter > 0 && result.RequeueAfter <= podStartingBackoffBase)
+		assert.Len(t, recorder.events, 1)
+		assert.Len(t, notifications, 0)
+	})
+	t.Run("backoff elapsed", func(t *testing.T) {
+		backoffUntil := metav1.NewTime(time.Now().Add(-time.Second))
+		jenkins.Status.BackoffUntil = &backoffUntil
+
+		result, stop, err := reconciler.detectJenkinsMasterPodStartingIssues()
+
+		require.NoError(t, err)
+		assert.True(t, stop)
+		assert.True(t, result.Requeue)
+		assert.Equal(t, 1, jenkins.Status.PodStartingRetries)
+		assert.Nil(t, jenkins.Status.BackoffUntil)
+		require.Len(t, recorder.events, 2)
+		assert.Equal(t, podRecreatedEventReason, recorder.events[1].reason)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, reason.OperatorSource, (<-notifications).Reason.Source())
+		err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}, &corev1.Pod{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+	t.Run("pod started", func(t *testing.T) {
+		pod.ResourceVersion = ""
+		pod.Status.Phase = corev1.PodRunning
+		require.NoError(t, fakeClient.Create(context.TODO(), pod))
+
+		_, stop, err := reconciler.detectJenkinsMasterPodStartingIssues()
+
+		require.NoError(t, err)
+		assert.False(t, stop)
+		assert.Equal(t, 0, jenkins.Status.PodStartingRetries)
+	})
+}
+
+func TestPodStartingBackoff(t *testing.T) {
+	assert.Equal(t, time.Minute, podStartingBackoff(0))
+	assert.Equal(t, 2*time.Minute, podStartingBackoff(1))
+	assert.Equal(t, 16*time.Minute, podStartingBackoff(4))
+	assert.Equal(t, podStartingBackoffMax, podStartingBackoff(5))
+	assert.Equal(t, podStartingBackoffMax, podStartingBackoff(100))
+}
+
+func TestFilterEvents(t *testing.T) {
+	provisionStartTime := metav1.NewTime(time.Now().Add(-time.Minute))
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "jenkins-example", Namespace: "default"}}
+	newEvent := func(eventType, reason, message string, lastTimestamp time.Time) corev1.Event {
+		return corev1.Event{
+			ObjectMeta:    metav1.ObjectMeta{Name: pod.Name + "." + reason},
+			Type:          eventType,
+			Reason:        reason,
+			Message:       message,
+			LastTimestamp: metav1.NewTime(lastTimestamp),
+		}
+	}
+	events := corev1.EventList{Items: []corev1.Event{
+		newEvent(corev1.EventTypeWarning, "FailedScheduling", "0/3 nodes are available: 3 Insufficient memory.", time.Now()),
+		newEvent(corev1.EventTypeWarning, "FailedMount", "MountVolume.SetUp failed for volume \"jenkins-home\"", time.Now()),
+		newEvent(corev1.EventTypeNormal, "Scheduled", "Successfully assigned default/jenkins-example", time.Now()),
+		newEvent(corev1.EventTypeWarning, "FailedScheduling", "0/3 nodes are available: 3 node(s) had taint.", time.Now().Add(-5*time.Minute)),
+	}}
+	newReconciler := func(filter *v1alpha2.PodEventsFilter) *JenkinsBaseConfigurationReconciler {
+		jenkins := &v1alpha2.Jenkins{
+			Spec:   v1alpha2.JenkinsSpec{Master: v1alpha2.JenkinsMaster{PodEventsFilter: filter}},
+			Status: v1alpha2.JenkinsStatus{ProvisionStartTime: &provisionStartTime},
+		}
+		return New(configuration.Configuration{Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+	}
+
+	t.Run("default", func(t *testing.T) {
+		got, schedulingFailures := newReconciler(nil).filterEvents(events, pod)
+
+		assert.Equal(t, []string{
+			"Reason: FailedScheduling Message: 0/3 nodes are available: 3 Insufficient memory. Subobject: ",
+			"Reason: FailedMount Message: MountVolume.SetUp failed for volume \"jenkins-home\" Subobject: ",
+		}, got)
+		assert.Equal(t, []string{"0/3 nodes are available: 3 Insufficient memory."}, schedulingFailures)
+	})
+	t.Run("lookback window and reasons", func(t *testing.T) {
+		got, schedulingFailures := newReconciler(&v1alpha2.PodEventsFilter{
+			LookbackWindow: &metav1.Duration{Duration: 10 * time.Minute},
+			Reasons:        []string{"FailedScheduling"},
+		}).filterEvents(events, pod)
+
+		assert.Len(t, got, 2)
+		assert.Equal(t, []string{"0/3 nodes are available: 3 Insufficient memory.", "0/3 nodes are available: 3 node(s) had taint."}, schedulingFailures)
+	})
+	t.Run("types and message regex", func(t *testing.T) {
+		got, schedulingFailures := newReconciler(&v1alpha2.PodEventsFilter{
+			Types:        []string{corev1.EventTypeNormal, corev1.EventTypeWarning},
+			MessageRegex: "default/jenkins|jenkins-home",
+		}).filterEvents(events, pod)
+
+		assert.Equal(t, []string{
+			"Reason: FailedMount Message: MountVolume.SetUp failed for volume \"jenkins-home\" Subobject: ",
+			"Reason: Scheduled Message: Successfully assigned default/jenkins-example Subobject: ",
+		}, got)
+		assert.Empty(t, schedulingFailures)
+	})
+}
+
+func TestCalculateEnvSourcesHash(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			Master: v1alpha2.JenkinsMaster{
+				Containers: []v1alpha2.Container{{
+					Name:           resources.JenkinsMasterContainerName,
+					ReadinessProbe: &corev1.Probe{},
+					EnvFrom: []corev1.EnvFromSource{
+						{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "jenkins-env"}}},
+					},
+					Env: []corev1.EnvVar{{
+						Name: "LOG_LEVEL",
+						ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "jenkins-settings"},
+							Key:                  "logLevel",
+						}},
+					}},
+				}},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins-env", Namespace: "default"},
+		Data:       map[string][]byte{"GITHUB_TOKEN": []byte("token")},
+	}
+
+	t.Run("no sources", func(t *testing.T) {
+		reconciler := New(configuration.Configuration{Client: fake.NewClientBuilder().Build(), Jenkins: &v1alpha2.Jenkins{}}, client.JenkinsAPIConnectionSettings{})
+
+		hash, err := reconciler.calculateEnvSourcesHash()
+
+		assert.NoError(t, err)
+		assert.Empty(t, hash)
+	})
+	t.Run("data changed", func(t *testing.T) {
+		fakeClient := &applyPatchClient{Client: fake.NewClientBuilder().WithObjects(secret.DeepCopy()).Build()}
+		reconciler := New(configuration.Configuration{Client: fakeClient, Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+
+		hash, err := reconciler.calculateEnvSourcesHash()
+		assert.NoError(t, err)
+		assert.NotEmpty(t, hash)
+
+		watched := &corev1.Secret{}
+		assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: secret.Name}, watched))
+		assert.True(t, resources.VerifyIfLabelsAreSet(watched, resources.BuildLabelsForWatchedResources(*jenkins)))
+
+		watched.Data["GITHUB_TOKEN"] = []byte("rotated")
+		assert.NoError(t, fakeClient.Update(context.TODO(), watched))
+		changedHash, err := reconciler.calculateEnvSourcesHash()
+		assert.NoError(t, err)
+		assert.NotEqual(t, hash, changedHash)
+
+		pod := resources.NewJenkinsMasterPod(resources.NewResourceObjectMeta(jenkins), jenkins)
+		pod.Annotations = resources.MergeMaps(pod.Annotations, map[string]string{envSourcesHashAnnotation: hash})
+		restartReason := reconciler.checkForPodRecreation(*pod, "", changedHash)
+		assert.Contains(t, restartReason.Short(), "Environment variables sources have changed")
+	})
+}
+
+func TestJenkinsMasterRecreationKeepsStatus(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	newJenkins := func() *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: defaultNamespace, Generation: 3},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{Containers: []v1alpha2.Container{{
+					Name:           resources.JenkinsMasterContainerName,
+					Image:          "jenkins/jenkins:broken",
+					ReadinessProbe: &corev1.Probe{},
+				}}},
+			},
+			Status: v1alpha2.JenkinsStatus{
+				LastKnownGoodSpec:       &runtime.RawExtension{Raw: []byte(`{"master":{}}`)},
+				LastKnownGoodGeneration: 2,
+				Degraded:                true,
+				DegradedReason:          "rolled back",
+				JenkinsHomeVolumeResize: &v1alpha2.VolumeResize{Phase: v1alpha2.VolumeResizeFileSystemResizePending, PodRestarted: true},
+				PluginsLock:             &v1alpha2.PluginsLock{SpecHash: "hash", Plugins: []string{"git:4.7.1"}},
+				AvailableUpdates:        &v1alpha2.AvailableUpdates{Core: "2.303"},
+				PodStartingRetries:      2,
+			},
+		}
+	}
+	recreate := func(t *testing.T, ensure func(*JenkinsBaseConfigurationReconciler, metav1.ObjectMeta) (reconcile.Result, error)) *v1alpha2.Jenkins {
+		jenkins := newJenkins()
+		credentials := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: resources.GetOperatorCredentialsSecretName(jenkins), Namespace: defaultNamespace}}
+		notifications := make(chan event.Event, 1)
+		r := New(configuration.Configuration{
+			Client:        fake.NewClientBuilder().WithObjects(jenkins, credentials).Build(),
+			Jenkins:       jenkins,
+			Scheme:        scheme.Scheme,
+			Notifications: &notifications,
+		}, client.JenkinsAPIConnectionSettings{})
+
+		result, err := ensure(r, resources.NewResourceObjectMeta(jenkins))
+
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+		updated := &v1alpha2.Jenkins{}
+		require.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: jenkins.Name, Namespace: defaultNamespace}, updated))
+		return updated
+	}
+
+	for name, ensure := range map[string]func(*JenkinsBaseConfigurationReconciler, metav1.ObjectMeta) (reconcile.Result, error){
+		"pod":        (*JenkinsBaseConfigurationReconciler).ensureJenkinsMasterPod,
+		"deployment": (*JenkinsBaseConfigurationReconciler).ensureJenkinsDeployment,
+	} {
+		t.Run(name, func(t *testing.T) {
+			jenkins := recreate(t, ensure)
+
+			assert.NotNil(t, jenkins.Status.ProvisionStartTime)
+			// the spec of the crash looping pod is still rolled back
+			require.NotNil(t, jenkins.Status.LastKnownGoodSpec)
+			assert.JSONEq(t, `{"master":{}}`, string(jenkins.Status.LastKnownGoodSpec.Raw))
+			assert.Less(t, jenkins.Status.LastKnownGoodGeneration, jenkins.Generation)
+			assert.True(t, jenkins.Status.Degraded)
+			assert.Equal(t, "rolled back", jenkins.Status.DegradedReason)
+			// the pod restarted to finish the file system resize isn't restarted again
+			require.NotNil(t, jenkins.Status.JenkinsHomeVolumeResize)
+			assert.True(t, jenkins.Status.JenkinsHomeVolumeResize.PodRestarted)
+			assert.Equal(t, &v1alpha2.PluginsLock{SpecHash: "hash", Plugins: []string{"git:4.7.1"}}, jenkins.Status.PluginsLock)
+			// the same updates aren't notified again
+			assert.Equal(t, &v1alpha2.AvailableUpdates{Core: "2.303"}, jenkins.Status.AvailableUpdates)
+			// the backoff of the pod which can't start keeps growing
+			assert.Equal(t, 2, jenkins.Status.PodStartingRetries)
+		})
+	}
+}
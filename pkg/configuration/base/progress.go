@@ -0,0 +1,49 @@
+package base
+
+import (
+	"fmt"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/groovy"
+)
+
+const (
+	groovyScriptAppliedEventReason k8sevent.Reason = "GroovyScriptApplied"
+	groovyScriptFailedEventReason  k8sevent.Reason = "GroovyScriptFailed"
+)
+
+// setBaseConfigurationProgress records the progress of base configuration groovy scripts in status and emits
+// an event for the executed script, the status is updated together with the applied scripts. The output of the script
+// is recorded only when spec.groovyScriptOutput is enabled.
+func (r *JenkinsBaseConfigurationReconciler) setBaseConfigurationProgress(progress groovy.Progress) {
+	var output string
+	if r.Configuration.Jenkins.Spec.GroovyScriptOutput {
+		output = groovy.TailOutput(progress.Logs)
+	}
+	status := &v1alpha2.ConfigurationProgress{
+		ConfigurationType: progress.ConfigurationType,
+		Applied:           progress.Applied,
+		Total:             progress.Total,
+		LastScript:        fmt.Sprintf("%s/%s", progress.Source, progress.Name),
+		LastScriptOutput:  output,
+	}
+	if progress.Err != nil {
+		status.LastScriptError = progress.Err.Error()
+	}
+	r.Configuration.Jenkins.Status.BaseConfigurationProgress = status
+
+	if r.Configuration.Events == nil {
+		return
+	}
+	message := fmt.Sprintf("%s groovy script '%s' applied (%d/%d)", status.ConfigurationType, status.LastScript, status.Applied, status.Total)
+	eventType, eventReason := k8sevent.TypeNormal, groovyScriptAppliedEventReason
+	if progress.Err != nil {
+		message = fmt.Sprintf("%s groovy script '%s' failed: %s", status.ConfigurationType, status.LastScript, status.LastScriptError)
+		eventType, eventReason = k8sevent.TypeWarning, groovyScriptFailedEventReason
+	}
+	if len(output) > 0 {
+		message = fmt.Sprintf("%s, output:\n%s", message, output)
+	}
+	r.Configuration.Events.Emit(r.Configuration.Jenkins, eventType, eventReason, message)
+}
@@ -0,0 +1,89 @@
+package base
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/groovy"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type recordedEvent struct {
+	eventType k8sevent.Type
+	reason    k8sevent.Reason
+	message   string
+}
+
+type fakeRecorder struct {
+	events []recordedEvent
+}
+
+func (r *fakeRecorder) Emit(_ runtime.Object, eventType k8sevent.Type, reason k8sevent.Reason, message string) {
+	r.events = append(r.events, recordedEvent{eventType: eventType, reason: reason, message: message})
+}
+
+func (r *fakeRecorder) Emitf(object runtime.Object, eventType k8sevent.Type, reason k8sevent.Reason, format string, args ...interface{}) {
+	r.Emit(object, eventType, reason, fmt.Sprintf(format, args...))
+}
+
+func TestSetBaseConfigurationProgress(t *testing.T) {
+	log.SetupLogger(true)
+	jenkins := &v1alpha2.Jenkins{}
+	recorder := &fakeRecorder{}
+	r := JenkinsBaseConfigurationReconciler{
+		logger:        log.Log,
+		Configuration: configuration.Configuration{Jenkins: jenkins, Events: recorder},
+	}
+
+	t.Run("applied", func(t *testing.T) {
+		r.setBaseConfigurationProgress(groovy.Progress{
+			ConfigurationType: "base-groovy", Source: "jenkins-operator-base-configuration-example", Name: "1-basic-settings.groovy",
+			Logs: "Configured\n", Applied: 1, Total: 9,
+		})
+
+		assert.Equal(t, &v1alpha2.ConfigurationProgress{
+			ConfigurationType: "base-groovy",
+			Applied:           1,
+			Total:             9,
+			LastScript:        "jenkins-operator-base-configuration-example/1-basic-settings.groovy",
+		}, jenkins.Status.BaseConfigurationProgress)
+		require.Len(t, recorder.events, 1)
+		assert.Equal(t, recordedEvent{
+			eventType: k8sevent.TypeNormal,
+			reason:    groovyScriptAppliedEventReason,
+			message:   "base-groovy groovy script 'jenkins-operator-base-configuration-example/1-basic-settings.groovy' applied (1/9)",
+		}, recorder.events[0])
+	})
+	t.Run("failed", func(t *testing.T) {
+		r.setBaseConfigurationProgress(groovy.Progress{
+			ConfigurationType: "base-user-groovy", Source: "scripts", Name: "clouds.groovy",
+			Err: &jenkinsclient.GroovyScriptExecutionFailed{}, Applied: 0, Total: 1,
+		})
+
+		assert.Equal(t, "script execution failed", jenkins.Status.BaseConfigurationProgress.LastScriptError)
+		require.Len(t, recorder.events, 2)
+		assert.Equal(t, k8sevent.TypeWarning, recorder.events[1].eventType)
+		assert.Equal(t, "base-user-groovy groovy script 'scripts/clouds.groovy' failed: script execution failed", recorder.events[1].message)
+	})
+	t.Run("output recorded", func(t *testing.T) {
+		jenkins.Spec.GroovyScriptOutput = true
+		defer func() { jenkins.Spec.GroovyScriptOutput = false }()
+
+		r.setBaseConfigurationProgress(groovy.Progress{
+			ConfigurationType: "base-groovy", Source: "jenkins-operator-base-configuration-example", Name: "2-csrf.groovy",
+			Logs: "Configured\n", Applied: 2, Total: 9,
+		})
+
+		assert.Equal(t, "Configured", jenkins.Status.BaseConfigurationProgress.LastScriptOutput)
+		require.Len(t, recorder.events, 3)
+		assert.Equal(t, "base-groovy groovy script 'jenkins-operator-base-configuration-example/2-csrf.groovy' applied (2/9), output:\nConfigured", recorder.events[2].message)
+	})
+}
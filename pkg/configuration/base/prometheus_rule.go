@@ -0,0 +1,134 @@
+package base
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/backuprestore"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/metrics"
+
+	stackerr "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	prometheusRuleAPIVersion = "monitoring.coreos.com/v1"
+	prometheusRuleKind       = "PrometheusRule"
+
+	defaultBackupStaleAfter     = 24 * time.Hour
+	defaultNotReadyFor          = 15 * time.Minute
+	defaultRestartStormRestarts = 3
+	defaultRestartStormWindow   = time.Hour
+)
+
+// ensurePrometheusRule applies PrometheusRule with alerts of the Jenkins instance configured in spec.prometheusRule
+// and deletes it when the configuration is removed
+func (r *JenkinsBaseConfigurationReconciler) ensurePrometheusRule() error {
+	jenkins := r.Configuration.Jenkins
+	if jenkins.Spec.PrometheusRule == nil {
+		err := r.deleteExtraResource(v1alpha2.ExtraResourceReference{
+			APIVersion: prometheusRuleAPIVersion,
+			Kind:       prometheusRuleKind,
+			Name:       resources.GetResourceName(jenkins),
+		})
+		if meta.IsNoMatchError(stackerr.Cause(err)) {
+			return nil
+		}
+		return err
+	}
+
+	err := r.applyExtraResource(newPrometheusRule(jenkins))
+	if meta.IsNoMatchError(stackerr.Cause(err)) {
+		r.logger.V(log.VWarn).Info("spec.prometheusRule is set but PrometheusRule CRD of Prometheus Operator is not installed")
+		return nil
+	}
+	return err
+}
+
+// newPrometheusRule builds PrometheusRule with alerts on the metrics exported from Jenkins CR status
+func newPrometheusRule(jenkins *v1alpha2.Jenkins) *unstructured.Unstructured {
+	config := jenkins.Spec.PrometheusRule
+	selector := fmt.Sprintf(`{namespace="%s",name="%s"}`, jenkins.Namespace, jenkins.Name)
+	instance := fmt.Sprintf("Jenkins '%s/%s'", jenkins.Namespace, jenkins.Name)
+
+	notReadyFor := durationOrDefault(config.NotReadyFor, defaultNotReadyFor)
+	restartStormRestarts := config.RestartStormRestarts
+	if restartStormRestarts == 0 {
+		restartStormRestarts = defaultRestartStormRestarts
+	}
+	restartStormWindow := durationOrDefault(config.RestartStormWindow, defaultRestartStormWindow)
+
+	rules := []interface{}{
+		newAlertingRule(config, "JenkinsNotReady",
+			fmt.Sprintf("%s%s == 0", metrics.JenkinsReadyMetricName, selector),
+			notReadyFor,
+			fmt.Sprintf("%s is not ready for more than %s", instance, notReadyFor)),
+		newAlertingRule(config, "JenkinsRestartStorm",
+			fmt.Sprintf("sum(delta(%s%s[%s])) >= %d", metrics.JenkinsMasterPodRestartsMetricName, selector, prometheusDuration(restartStormWindow), restartStormRestarts),
+			0,
+			fmt.Sprintf("Jenkins master pod of %s has been restarted at least %d times in %s", instance, restartStormRestarts, restartStormWindow)),
+	}
+	if backuprestore.IsBackupTriggerConfigured(jenkins) {
+		backupStaleAfter := durationOrDefault(config.BackupStaleAfter, defaultBackupStaleAfter)
+		rules = append(rules, newAlertingRule(config, "JenkinsBackupStale",
+			fmt.Sprintf("changes(%s%s[%s]) == 0", metrics.JenkinsLastBackupMetricName, selector, prometheusDuration(backupStaleAfter)),
+			0,
+			fmt.Sprintf("%s has no new backup for more than %s", instance, backupStaleAfter)))
+	}
+
+	object := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"groups": []interface{}{
+				map[string]interface{}{
+					"name":  fmt.Sprintf("jenkins-%s-%s", jenkins.Namespace, jenkins.Name),
+					"rules": rules,
+				},
+			},
+		},
+	}}
+	object.SetAPIVersion(prometheusRuleAPIVersion)
+	object.SetKind(prometheusRuleKind)
+	object.SetName(resources.GetResourceName(jenkins))
+	object.SetNamespace(jenkins.Namespace)
+	object.SetLabels(resources.MergeMaps(config.Labels))
+	object.SetAnnotations(resources.MergeMaps(jenkins.Spec.CommonAnnotations))
+	return object
+}
+
+func newAlertingRule(config *v1alpha2.PrometheusRule, alert, expr string, forDuration time.Duration, description string) map[string]interface{} {
+	rule := map[string]interface{}{
+		"alert": alert,
+		"expr":  expr,
+		"annotations": map[string]interface{}{
+			"description": description,
+		},
+	}
+	if forDuration > 0 {
+		rule["for"] = prometheusDuration(forDuration)
+	}
+	if len(config.AlertLabels) > 0 {
+		labels := map[string]interface{}{}
+		for key, value := range config.AlertLabels {
+			labels[key] = value
+		}
+		rule["labels"] = labels
+	}
+	return rule
+}
+
+func durationOrDefault(duration *metav1.Duration, defaultDuration time.Duration) time.Duration {
+	if duration == nil || duration.Duration <= 0 {
+		return defaultDuration
+	}
+	return duration.Duration
+}
+
+// prometheusDuration formats the duration in seconds, Prometheus doesn't accept Go duration format e.g. 1h0m0s
+func prometheusDuration(duration time.Duration) string {
+	return fmt.Sprintf("%ds", int64(duration.Seconds()))
+}
@@ -0,0 +1,107 @@
+package base
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewPrometheusRule(t *testing.T) {
+	newJenkins := func() *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "ci"},
+			Spec: v1alpha2.JenkinsSpec{
+				PrometheusRule: &v1alpha2.PrometheusRule{
+					Labels:      map[string]string{"prometheus": "main"},
+					AlertLabels: map[string]string{"team": "ci"},
+				},
+			},
+		}
+	}
+	rulesOf := func(t *testing.T, object *unstructured.Unstructured) []interface{} {
+		groups, found, err := unstructured.NestedSlice(object.Object, "spec", "groups")
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Len(t, groups, 1)
+		return groups[0].(map[string]interface{})["rules"].([]interface{})
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		object := newPrometheusRule(newJenkins())
+
+		assert.Equal(t, "monitoring.coreos.com/v1", object.GetAPIVersion())
+		assert.Equal(t, "PrometheusRule", object.GetKind())
+		assert.Equal(t, "jenkins-operator-example", object.GetName())
+		assert.Equal(t, map[string]string{"prometheus": "main"}, object.GetLabels())
+		rules := rulesOf(t, object)
+		require.Len(t, rules, 2)
+		assert.Equal(t, map[string]interface{}{
+			"alert":       "JenkinsNotReady",
+			"expr":        `jenkins_operator_jenkins_ready{namespace="ci",name="example"} == 0`,
+			"for":         "900s",
+			"labels":      map[string]interface{}{"team": "ci"},
+			"annotations": map[string]interface{}{"description": "Jenkins 'ci/example' is not ready for more than 15m0s"},
+		}, rules[0])
+		assert.Equal(t, `sum(delta(jenkins_operator_jenkins_master_pod_restarts{namespace="ci",name="example"}[3600s])) >= 3`, rules[1].(map[string]interface{})["expr"])
+	})
+	t.Run("backup configured", func(t *testing.T) {
+		jenkins := newJenkins()
+		jenkins.Spec.Backup = v1alpha2.Backup{ContainerName: "backup", Interval: 30}
+		jenkins.Spec.PrometheusRule.BackupStaleAfter = &metav1.Duration{Duration: 6 * time.Hour}
+		jenkins.Spec.PrometheusRule.RestartStormRestarts = 5
+		jenkins.Spec.PrometheusRule.RestartStormWindow = &metav1.Duration{Duration: 10 * time.Minute}
+
+		rules := rulesOf(t, newPrometheusRule(jenkins))
+
+		require.Len(t, rules, 3)
+		assert.Equal(t, `sum(delta(jenkins_operator_jenkins_master_pod_restarts{namespace="ci",name="example"}[600s])) >= 5`, rules[1].(map[string]interface{})["expr"])
+		assert.Equal(t, "JenkinsBackupStale", rules[2].(map[string]interface{})["alert"])
+		assert.Equal(t, `changes(jenkins_operator_jenkins_last_backup{namespace="ci",name="example"}[21600s]) == 0`, rules[2].(map[string]interface{})["expr"])
+	})
+}
+
+func TestEnsurePrometheusRule(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+		Spec:       v1alpha2.JenkinsSpec{PrometheusRule: &v1alpha2.PrometheusRule{}},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(jenkins).Build()
+	reconciler := New(configuration.Configuration{Client: fakeClient, Jenkins: jenkins, Scheme: scheme.Scheme}, client.JenkinsAPIConnectionSettings{})
+	key := types.NamespacedName{Namespace: "default", Name: "jenkins-operator-example"}
+	newObject := func() *unstructured.Unstructured {
+		object := &unstructured.Unstructured{}
+		object.SetAPIVersion(prometheusRuleAPIVersion)
+		object.SetKind(prometheusRuleKind)
+		return object
+	}
+
+	err := reconciler.ensurePrometheusRule()
+
+	require.NoError(t, err)
+	object := newObject()
+	require.NoError(t, fakeClient.Get(context.TODO(), key, object))
+	assert.True(t, metav1.IsControlledBy(object, jenkins))
+	assert.Equal(t, "example", object.GetLabels()["jenkins-cr"])
+
+	jenkins.Spec.PrometheusRule = nil
+
+	err = reconciler.ensurePrometheusRule()
+
+	require.NoError(t, err)
+	assert.Error(t, fakeClient.Get(context.TODO(), key, newObject()))
+}
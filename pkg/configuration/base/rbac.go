@@ -3,6 +3,7 @@ package base
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
@@ -11,6 +12,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -20,7 +22,7 @@ func (r *JenkinsBaseConfigurationReconciler) createRBAC(meta metav1.ObjectMeta)
 		return err
 	}
 
-	role := resources.NewRole(meta)
+	role := resources.NewRole(meta, r.Configuration.Jenkins)
 	err = r.CreateOrUpdateResource(role)
 	if err != nil {
 		return stackerr.WithStack(err)
@@ -31,6 +33,8 @@ func (r *JenkinsBaseConfigurationReconciler) createRBAC(meta metav1.ObjectMeta)
 		Kind:     "Role",
 		Name:     meta.Name,
 	})
+	roleBinding.ObjectMeta.Labels = meta.Labels
+	roleBinding.ObjectMeta.Annotations = meta.Annotations
 	err = r.CreateOrUpdateResource(roleBinding)
 	if err != nil {
 		return stackerr.WithStack(err)
@@ -44,12 +48,22 @@ func (r *JenkinsBaseConfigurationReconciler) ensureExtraRBAC(meta metav1.ObjectM
 	var name string
 	for _, roleRef := range r.Configuration.Jenkins.Spec.Roles {
 		name = getExtraRoleBindingName(meta.Name, roleRef)
-		roleBinding := resources.NewRoleBinding(name, meta.Namespace, meta.Name, roleRef)
-		err := r.Client.Create(context.TODO(), roleBinding)
-		if err != nil && errors.IsAlreadyExists(err) {
+		found := &rbacv1.RoleBinding{}
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: meta.Namespace}, found)
+		if err == nil {
+			if err = r.ensureRoleBindingMeta(meta, found); err != nil {
+				return err
+			}
 			continue
+		} else if !errors.IsNotFound(err) {
+			return stackerr.WithStack(err)
 		}
-		if err != nil {
+
+		roleBinding := resources.NewRoleBinding(name, meta.Namespace, meta.Name, roleRef)
+		roleBinding.ObjectMeta.Labels = meta.Labels
+		roleBinding.ObjectMeta.Annotations = meta.Annotations
+		err = r.Client.Create(context.TODO(), roleBinding)
+		if err != nil && !errors.IsAlreadyExists(err) {
 			return stackerr.WithStack(err)
 		}
 	}
@@ -84,6 +98,17 @@ func (r *JenkinsBaseConfigurationReconciler) ensureExtraRBAC(meta metav1.ObjectM
 	return nil
 }
 
+// ensureRoleBindingMeta keeps labels and annotations of already existing extra role binding up to date
+func (r *JenkinsBaseConfigurationReconciler) ensureRoleBindingMeta(meta metav1.ObjectMeta, roleBinding *rbacv1.RoleBinding) error {
+	if reflect.DeepEqual(roleBinding.Labels, meta.Labels) && reflect.DeepEqual(roleBinding.Annotations, meta.Annotations) {
+		return nil
+	}
+
+	roleBinding.Labels = meta.Labels
+	roleBinding.Annotations = meta.Annotations
+	return stackerr.WithStack(r.Client.Update(context.TODO(), roleBinding))
+}
+
 func getExtraRoleBindingName(serviceAccountName string, roleRef rbacv1.RoleRef) string {
 	var typeName string
 	if roleRef.Kind == "ClusterRole" {
@@ -0,0 +1,87 @@
+package base
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const readinessGateHTTPTimeout = 5 * time.Second
+
+// readinessGateHTTPClient doesn't verify certificates, HTTPS endpoints of sidecars usually have self-signed ones
+var readinessGateHTTPClient = &http.Client{
+	Timeout: readinessGateHTTPTimeout,
+	Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	},
+}
+
+// waitForReadinessGates verifies spec.master.readinessGates in order and requeues until all of them pass. The gates
+// are verified once per Jenkins master pod, the status is reset when the pod is recreated, so the gates have already
+// passed for the running pod when the base configuration has been completed.
+func (r *JenkinsBaseConfigurationReconciler) waitForReadinessGates(jenkinsClient jenkinsclient.Jenkins) (reconcile.Result, error) {
+	gates := r.Configuration.Jenkins.Spec.Master.ReadinessGates
+	if len(gates) == 0 || r.Configuration.Jenkins.Status.BaseConfigurationCompletedTime != nil {
+		return reconcile.Result{}, nil
+	}
+
+	jenkinsMasterPod, err := r.Configuration.GetJenkinsMasterPod()
+	if err != nil {
+		return reconcile.Result{}, stackerr.WithStack(err)
+	}
+
+	for _, gate := range gates {
+		var gateErr error
+		if gate.HTTPGet != nil {
+			gateErr = checkHTTPReadinessGate(readinessGateHTTPClient, jenkinsMasterPod.Status.PodIP, *gate.HTTPGet)
+		} else {
+			gateErr = checkPluginsReadinessGate(jenkinsClient, gate.Plugins)
+		}
+		if gateErr != nil {
+			r.logger.Info(fmt.Sprintf("Readiness gate '%s' not passed: %s", gate.Name, gateErr))
+			return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 5}, nil
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+func checkHTTPReadinessGate(httpClient *http.Client, podIP string, httpGet v1alpha2.ReadinessGateHTTPGet) error {
+	scheme := "http"
+	if httpGet.Scheme == corev1.URISchemeHTTPS {
+		scheme = "https"
+	}
+	url := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(podIP, strconv.Itoa(int(httpGet.Port))), httpGet.Path)
+	response, err := httpClient.Get(url)
+	if err != nil {
+		return stackerr.WithStack(err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusBadRequest {
+		return stackerr.Errorf("GET %s responded with status code %d", url, response.StatusCode)
+	}
+	return nil
+}
+
+func checkPluginsReadinessGate(jenkinsClient jenkinsclient.Jenkins, pluginNames []string) error {
+	allPluginsInJenkins, err := jenkinsClient.GetPlugins(fetchAllPlugins)
+	if err != nil {
+		return stackerr.WithStack(err)
+	}
+	for _, name := range pluginNames {
+		plugin := allPluginsInJenkins.Contains(name)
+		if plugin == nil || !isValidPlugin(*plugin) {
+			return stackerr.Errorf("plugin '%s' is not installed or not active", name)
+		}
+	}
+	return nil
+}
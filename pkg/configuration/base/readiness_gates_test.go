@@ -0,0 +1,110 @@
+package base
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/bndr/gojenkins"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckHTTPReadinessGate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ready" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	host, portValue, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portValue)
+	require.NoError(t, err)
+
+	t.Run("ready", func(t *testing.T) {
+		err := checkHTTPReadinessGate(server.Client(), host, v1alpha2.ReadinessGateHTTPGet{Path: "/ready", Port: int32(port)})
+
+		assert.NoError(t, err)
+	})
+	t.Run("not ready", func(t *testing.T) {
+		err := checkHTTPReadinessGate(server.Client(), host, v1alpha2.ReadinessGateHTTPGet{Path: "/init", Port: int32(port)})
+
+		assert.EqualError(t, err, "GET http://"+server.Listener.Addr().String()+"/init responded with status code 503")
+	})
+}
+
+func TestWaitForReadinessGates(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			Master: v1alpha2.JenkinsMaster{
+				ReadinessGates: []v1alpha2.ReadinessGate{{Name: "plugins", Plugins: []string{"plugin-name1"}}},
+			},
+		},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "jenkins-example", Namespace: "default"}}
+	newReconciler := func() *JenkinsBaseConfigurationReconciler {
+		return &JenkinsBaseConfigurationReconciler{
+			logger: log.Log,
+			Configuration: configuration.Configuration{
+				Client:  fake.NewClientBuilder().WithObjects(pod).Build(),
+				Jenkins: jenkins,
+			},
+		}
+	}
+
+	t.Run("plugin not active", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().GetPlugins(fetchAllPlugins).Return(&gojenkins.Plugins{Raw: &gojenkins.PluginResponse{
+			Plugins: []gojenkins.Plugin{{ShortName: "plugin-name1", Active: false, Enabled: true}},
+		}}, nil)
+
+		result, err := newReconciler().waitForReadinessGates(jenkinsClient)
+
+		assert.NoError(t, err)
+		assert.True(t, result.Requeue)
+	})
+	t.Run("plugin active", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().GetPlugins(fetchAllPlugins).Return(&gojenkins.Plugins{Raw: &gojenkins.PluginResponse{
+			Plugins: []gojenkins.Plugin{{ShortName: "plugin-name1", Active: true, Enabled: true}},
+		}}, nil)
+
+		result, err := newReconciler().waitForReadinessGates(jenkinsClient)
+
+		assert.NoError(t, err)
+		assert.False(t, result.Requeue)
+	})
+	t.Run("not verified again for the configured pod", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		reconciler := newReconciler()
+		now := metav1.Now()
+		reconciler.Configuration.Jenkins = jenkins.DeepCopy()
+		reconciler.Configuration.Jenkins.Status.BaseConfigurationCompletedTime = &now
+
+		result, err := reconciler.waitForReadinessGates(jenkinsClient)
+
+		assert.NoError(t, err)
+		assert.False(t, result.Requeue)
+	})
+}
@@ -2,21 +2,32 @@ package base
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
 	"github.com/maximba/kubernetes-operator/pkg/client"
 	"github.com/maximba/kubernetes-operator/pkg/configuration"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
 	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
 
 	"github.com/bndr/gojenkins"
 	"github.com/golang/mock/gomock"
+	routev1 "github.com/openshift/api/route/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -184,7 +195,7 @@ func TestJenkinsBaseConfigurationReconciler_verifyPlugins(t *testing.T) {
 		got, err := r.verifyPlugins(jenkinsClient)
 
 		assert.NoError(t, err)
-		assert.True(t, got)
+		assert.Empty(t, got)
 	})
 	t.Run("happy, not empty base and user plugins", func(t *testing.T) {
 		jenkins := &v1alpha2.Jenkins{
@@ -229,7 +240,7 @@ func TestJenkinsBaseConfigurationReconciler_verifyPlugins(t *testing.T) {
 		got, err := r.verifyPlugins(jenkinsClient)
 
 		assert.NoError(t, err)
-		assert.True(t, got)
+		assert.Empty(t, got)
 	})
 	t.Run("happy, not empty base and empty user plugins", func(t *testing.T) {
 		jenkins := &v1alpha2.Jenkins{
@@ -266,7 +277,7 @@ func TestJenkinsBaseConfigurationReconciler_verifyPlugins(t *testing.T) {
 		got, err := r.verifyPlugins(jenkinsClient)
 
 		assert.NoError(t, err)
-		assert.True(t, got)
+		assert.Empty(t, got)
 	})
 	t.Run("happy, empty base and not empty user plugins", func(t *testing.T) {
 		jenkins := &v1alpha2.Jenkins{
@@ -303,7 +314,7 @@ func TestJenkinsBaseConfigurationReconciler_verifyPlugins(t *testing.T) {
 		got, err := r.verifyPlugins(jenkinsClient)
 
 		assert.NoError(t, err)
-		assert.True(t, got)
+		assert.Empty(t, got)
 	})
 	t.Run("happy, plugin version matter for base plugins", func(t *testing.T) {
 		jenkins := &v1alpha2.Jenkins{
@@ -340,7 +351,7 @@ func TestJenkinsBaseConfigurationReconciler_verifyPlugins(t *testing.T) {
 		got, err := r.verifyPlugins(jenkinsClient)
 
 		assert.NoError(t, err)
-		assert.False(t, got)
+		assert.Len(t, got, 1)
 	})
 	t.Run("plugin version matter for user plugins", func(t *testing.T) {
 		jenkins := &v1alpha2.Jenkins{
@@ -377,7 +388,7 @@ func TestJenkinsBaseConfigurationReconciler_verifyPlugins(t *testing.T) {
 		got, err := r.verifyPlugins(jenkinsClient)
 
 		assert.NoError(t, err)
-		assert.False(t, got)
+		assert.Len(t, got, 1)
 	})
 	t.Run("missing base plugin", func(t *testing.T) {
 		jenkins := &v1alpha2.Jenkins{
@@ -406,7 +417,7 @@ func TestJenkinsBaseConfigurationReconciler_verifyPlugins(t *testing.T) {
 		got, err := r.verifyPlugins(jenkinsClient)
 
 		assert.NoError(t, err)
-		assert.False(t, got)
+		assert.Len(t, got, 1)
 	})
 	t.Run("missing user plugin", func(t *testing.T) {
 		jenkins := &v1alpha2.Jenkins{
@@ -435,10 +446,232 @@ func TestJenkinsBaseConfigurationReconciler_verifyPlugins(t *testing.T) {
 		got, err := r.verifyPlugins(jenkinsClient)
 
 		assert.NoError(t, err)
-		assert.False(t, got)
+		assert.Len(t, got, 1)
+	})
+	t.Run("locked plugins", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					Plugins:     []v1alpha2.Plugin{{Name: "plugin-name", Version: "0.0.1"}},
+					LockPlugins: true,
+				},
+			},
+		}
+		jenkins.Status.PluginsLock = &v1alpha2.PluginsLock{
+			SpecHash: resources.GetPluginsSpecHash(jenkins),
+			Plugins:  []string{"dependency:1.0.0", "plugin-name:0.0.1"},
+		}
+		r := JenkinsBaseConfigurationReconciler{
+			logger: log.Log,
+			Configuration: configuration.Configuration{
+				Jenkins: jenkins,
+			},
+		}
+		for name, installed := range map[string][]gojenkins.Plugin{
+			"happy": {
+				{ShortName: "plugin-name", Active: true, Enabled: true, Version: "0.0.1"},
+				{ShortName: "dependency", Active: true, Enabled: true, Version: "1.0.0"},
+			},
+			"different dependency version": {
+				{ShortName: "plugin-name", Active: true, Enabled: true, Version: "0.0.1"},
+				{ShortName: "dependency", Active: true, Enabled: true, Version: "1.1.0"},
+			},
+			"not locked plugin": {
+				{ShortName: "plugin-name", Active: true, Enabled: true, Version: "0.0.1"},
+				{ShortName: "dependency", Active: true, Enabled: true, Version: "1.0.0"},
+				{ShortName: "extra", Active: true, Enabled: true, Version: "2.0.0"},
+			},
+		} {
+			ctrl := gomock.NewController(t)
+			jenkinsClient := client.NewMockJenkins(ctrl)
+			jenkinsClient.EXPECT().GetPlugins(fetchAllPlugins).Return(&gojenkins.Plugins{Raw: &gojenkins.PluginResponse{Plugins: installed}}, nil)
+
+			got, err := r.verifyPlugins(jenkinsClient)
+
+			assert.NoError(t, err)
+			assert.Equal(t, name == "happy", len(got) == 0, name)
+			ctrl.Finish()
+		}
+	})
+}
+
+func TestJenkinsBaseConfigurationReconciler_ensurePluginsLock(t *testing.T) {
+	log.SetupLogger(true)
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			Master: v1alpha2.JenkinsMaster{
+				Plugins: []v1alpha2.Plugin{{Name: "plugin-name", Version: "0.0.1"}},
+			},
+		},
+	}
+	assert.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	fakeClient := fake.NewClientBuilder().WithObjects(jenkins).Build()
+	r := JenkinsBaseConfigurationReconciler{
+		logger: log.Log,
+		Configuration: configuration.Configuration{
+			Client:  fakeClient,
+			Jenkins: jenkins,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	jenkinsClient := client.NewMockJenkins(ctrl)
+	jenkinsClient.EXPECT().GetPlugins(fetchAllPlugins).Return(&gojenkins.Plugins{Raw: &gojenkins.PluginResponse{Plugins: []gojenkins.Plugin{
+		{ShortName: "plugin-name", Active: true, Enabled: true, Version: "0.0.1"},
+		{ShortName: "dependency", Active: true, Enabled: true, Version: "1.0.0"},
+		{ShortName: "disabled", Active: false, Enabled: false, Version: "1.0.0"},
+	}}}, nil).Times(1)
+
+	err := r.ensurePluginsLock(jenkinsClient)
+	assert.NoError(t, err)
+	// lock is up to date so Jenkins API is not called again
+	err = r.ensurePluginsLock(jenkinsClient)
+	assert.NoError(t, err)
+
+	actual := &v1alpha2.Jenkins{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: jenkins.Name, Namespace: jenkins.Namespace}, actual))
+	assert.Equal(t, &v1alpha2.PluginsLock{
+		SpecHash: resources.GetPluginsSpecHash(jenkins),
+		Plugins:  []string{"dependency:1.0.0", "plugin-name:0.0.1"},
+	}, actual.Status.PluginsLock)
+}
+
+func TestJenkinsBaseConfigurationReconciler_upgradePlugins(t *testing.T) {
+	log.SetupLogger(true)
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			Master: v1alpha2.JenkinsMaster{
+				Plugins:             []v1alpha2.Plugin{{Name: "git", Version: "4.11.3"}, {Name: "job-dsl", Version: "1.78.1"}},
+				PluginUpgradePolicy: v1alpha2.PatchOnlyPluginUpgradePolicy,
+			},
+		},
+		Status: v1alpha2.JenkinsStatus{
+			AvailableUpdates: &v1alpha2.AvailableUpdates{Plugins: []string{"git:4.11.5", "job-dsl:1.79"}},
+		},
+	}
+	assert.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	fakeClient := fake.NewClientBuilder().WithObjects(jenkins).Build()
+	r := JenkinsBaseConfigurationReconciler{
+		logger: log.Log,
+		Configuration: configuration.Configuration{
+			Client:  fakeClient,
+			Jenkins: jenkins,
+		},
+	}
+	installed := func(gitVersion string) *gojenkins.Plugins {
+		return &gojenkins.Plugins{Raw: &gojenkins.PluginResponse{Plugins: []gojenkins.Plugin{
+			{ShortName: "git", Active: true, Enabled: true, Version: gitVersion},
+			{ShortName: "job-dsl", Active: true, Enabled: true, Version: "1.78.1"},
+		}}}
+	}
+
+	t.Run("patch upgrade", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().GetPlugins(fetchAllPlugins).Return(installed("4.11.3"), nil)
+		jenkinsClient.EXPECT().InstallPlugin("git", "4.11.5").Return(nil)
+		jenkinsClient.EXPECT().SafeRestart().Return(nil)
+
+		drifts, err := r.verifyPlugins(jenkinsClient)
+		require.NoError(t, err)
+		require.Equal(t, []pluginDrift{{name: "git", required: "4.11.3", installed: "4.11.3", target: "4.11.5"}}, drifts)
+		require.True(t, isPluginsUpgrade(drifts))
+		upgraded, err := r.upgradePlugins(jenkinsClient, drifts)
+
+		assert.NoError(t, err)
+		assert.True(t, upgraded)
+		assert.Equal(t, []string{"git:4.11.5"}, jenkins.Status.PluginUpgrades)
+		assert.Equal(t, 1, jenkins.Status.PluginUpgradeAttempts)
+		require.NotNil(t, jenkins.Status.PluginUpgradeTime)
+	})
+	t.Run("upgrade is not attempted again before retry time", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		drifts := []pluginDrift{{name: "git", required: "4.11.3", installed: "4.11.3", target: "4.11.5"}}
+
+		upgraded, err := r.upgradePlugins(jenkinsClient, drifts)
+
+		assert.NoError(t, err)
+		assert.False(t, upgraded)
+		require.NoError(t, r.setPluginsVerifiedCondition(drifts))
+		condition := meta.FindStatusCondition(jenkins.Status.Conditions, v1alpha2.ConditionPluginsVerified)
+		require.NotNil(t, condition)
+		assert.Equal(t, configuration.ConditionReasonPluginsUpgradeFailed, condition.Reason)
+		assert.Contains(t, condition.Message, "Plugin 'git' version '4.11.3', required '4.11.5'")
+	})
+	t.Run("upgrade is retried with backoff", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().InstallPlugin("git", "4.11.5").Return(nil)
+		jenkinsClient.EXPECT().SafeRestart().Return(nil)
+		attemptTime := metav1.NewTime(time.Now().Add(-pluginUpgradeRetryDelay - time.Minute))
+		jenkins.Status.PluginUpgradeTime = &attemptTime
+
+		upgraded, err := r.upgradePlugins(jenkinsClient, []pluginDrift{{name: "git", required: "4.11.3", installed: "4.11.3", target: "4.11.5"}})
+
+		assert.NoError(t, err)
+		assert.True(t, upgraded)
+		assert.Equal(t, 2, jenkins.Status.PluginUpgradeAttempts)
+		assert.Equal(t, jenkins.Status.PluginUpgradeTime.Add(2*pluginUpgradeRetryDelay), getPluginUpgradeRetryTime(jenkins.Status))
+	})
+	t.Run("newer installed version is accepted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().GetPlugins(fetchAllPlugins).Return(installed("4.11.6"), nil)
+
+		drifts, err := r.verifyPlugins(jenkinsClient)
+
+		assert.NoError(t, err)
+		assert.Empty(t, drifts)
+	})
+	t.Run("downgrade requires restart", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().GetPlugins(fetchAllPlugins).Return(installed("4.12.0"), nil)
+
+		drifts, err := r.verifyPlugins(jenkinsClient)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []pluginDrift{{name: "git", required: "4.11.3", installed: "4.12.0", target: "4.11.5"}}, drifts)
+		assert.False(t, isPluginsUpgrade(drifts))
 	})
 }
 
+func TestNewPluginsRestartReason(t *testing.T) {
+	drifts := []pluginDrift{
+		{name: "git", required: "4.11.3", installed: "4.12.0", target: "4.11.3"},
+		{name: "job-dsl", required: "1.78.1", target: "1.78.1"},
+		{name: "extra", installed: "2.0.0"},
+	}
+
+	restartReason := newPluginsRestartReason(drifts)
+
+	assert.Equal(t, []string{"Jenkins master pod restarted by operator: Some plugins have changed: git, job-dsl, extra"}, restartReason.Short())
+	assert.Equal(t, []string{
+		"Jenkins master pod restarted by operator:",
+		"Plugin 'git' version '4.12.0', required '4.11.3'",
+		"Plugin 'job-dsl' is missing, required '1.78.1'",
+		"Plugin 'extra:2.0.0' is not locked",
+	}, restartReason.Verbose())
+}
+
+func TestIsPluginUpgradeAllowed(t *testing.T) {
+	assert.False(t, isPluginUpgradeAllowed(v1alpha2.PinnedPluginUpgradePolicy, "4.11.3", "4.11.5"))
+	assert.False(t, isPluginUpgradeAllowed("", "4.11.3", "4.11.5"))
+	assert.True(t, isPluginUpgradeAllowed(v1alpha2.PatchOnlyPluginUpgradePolicy, "4.11.3", "4.11.5"))
+	assert.False(t, isPluginUpgradeAllowed(v1alpha2.PatchOnlyPluginUpgradePolicy, "4.11.3", "4.12.0"))
+	assert.False(t, isPluginUpgradeAllowed(v1alpha2.PatchOnlyPluginUpgradePolicy, "4.11.3", "4.11.2"))
+	assert.True(t, isPluginUpgradeAllowed(v1alpha2.LatestPluginUpgradePolicy, "4.11.3", "5.0"))
+	assert.False(t, isPluginUpgradeAllowed(v1alpha2.LatestPluginUpgradePolicy, "4.11.3", "4.11.3"))
+}
+
 func Test_compareEnv(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		var expected []corev1.EnvVar
@@ -520,6 +753,62 @@ func Test_compareEnv(t *testing.T) {
 	})
 }
 
+func TestCompareContainersAPIServerDefaults(t *testing.T) {
+	expected := corev1.Container{
+		Name:           "sidecar",
+		Ports:          []corev1.ContainerPort{{Name: "http", ContainerPort: 8081}},
+		ReadinessProbe: &corev1.Probe{Handler: corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/ready"}}},
+	}
+	actual := *expected.DeepCopy()
+	actual.ImagePullPolicy = corev1.PullIfNotPresent
+	actual.Ports[0].Protocol = corev1.ProtocolTCP
+	actual.ReadinessProbe = &corev1.Probe{
+		Handler:          corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Scheme: corev1.URISchemeHTTP}},
+		TimeoutSeconds:   1,
+		PeriodSeconds:    10,
+		SuccessThreshold: 1,
+		FailureThreshold: 3,
+	}
+	r := &JenkinsBaseConfigurationReconciler{}
+
+	t.Run("defaulted fields", func(t *testing.T) {
+		messages, _ := r.compareContainers(expected, actual)
+
+		assert.Empty(t, messages)
+	})
+	t.Run("changed probe", func(t *testing.T) {
+		changed := *actual.DeepCopy()
+		changed.ReadinessProbe.FailureThreshold = 12
+
+		messages, _ := r.compareContainers(expected, changed)
+
+		assert.Equal(t, []string{"Readiness probe has changed"}, messages)
+	})
+	t.Run("defaulted lifecycle", func(t *testing.T) {
+		withLifecycle := *expected.DeepCopy()
+		withLifecycle.Lifecycle = &corev1.Lifecycle{
+			PostStart: &corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"/warm-up.sh"}}},
+			PreStop:   &corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/drain"}},
+		}
+		defaulted := *actual.DeepCopy()
+		defaulted.Lifecycle = withLifecycle.Lifecycle.DeepCopy()
+		defaulted.Lifecycle.PreStop.HTTPGet.Scheme = corev1.URISchemeHTTP
+
+		messages, _ := r.compareContainers(withLifecycle, defaulted)
+
+		assert.Empty(t, messages)
+		assert.Empty(t, withLifecycle.Lifecycle.PreStop.HTTPGet.Scheme)
+	})
+	t.Run("changed lifecycle", func(t *testing.T) {
+		withLifecycle := *expected.DeepCopy()
+		withLifecycle.Lifecycle = &corev1.Lifecycle{PreStop: &corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"/drain.sh"}}}}
+
+		messages, _ := r.compareContainers(withLifecycle, actual)
+
+		assert.Equal(t, []string{"Lifecycle has changed"}, messages)
+	})
+}
+
 func TestCompareMap(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		expectedAnnotations := map[string]string{}
@@ -619,7 +908,7 @@ func TestEnsureExtraRBAC(t *testing.T) {
 
 	t.Run("empty", func(t *testing.T) {
 		// given
-		fakeClient := fake.NewClientBuilder().Build()
+		fakeClient := &applyPatchClient{Client: fake.NewClientBuilder().Build()}
 		err := v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme)
 		assert.NoError(t, err)
 
@@ -655,7 +944,7 @@ func TestEnsureExtraRBAC(t *testing.T) {
 	clusterRoleKind := "ClusterRole"
 	t.Run("one extra", func(t *testing.T) {
 		// given
-		fakeClient := fake.NewClientBuilder().Build()
+		fakeClient := &applyPatchClient{Client: fake.NewClientBuilder().Build()}
 		err := v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme)
 		assert.NoError(t, err)
 
@@ -696,7 +985,7 @@ func TestEnsureExtraRBAC(t *testing.T) {
 	})
 	t.Run("two extra", func(t *testing.T) {
 		// given
-		fakeClient := fake.NewClientBuilder().Build()
+		fakeClient := &applyPatchClient{Client: fake.NewClientBuilder().Build()}
 		err := v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme)
 		assert.NoError(t, err)
 
@@ -744,7 +1033,7 @@ func TestEnsureExtraRBAC(t *testing.T) {
 	})
 	t.Run("delete one extra", func(t *testing.T) {
 		// given
-		fakeClient := fake.NewClientBuilder().Build()
+		fakeClient := &applyPatchClient{Client: fake.NewClientBuilder().Build()}
 		err := v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme)
 		assert.NoError(t, err)
 
@@ -807,6 +1096,340 @@ func TestEnsureExtraRBAC(t *testing.T) {
 	})
 }
 
+func TestEnsureExtraRBACCommonMeta(t *testing.T) {
+	t.Run("existing extra role binding gets common labels and annotations", func(t *testing.T) {
+		// given
+		fakeClient := &applyPatchClient{Client: fake.NewClientBuilder().Build()}
+		err := v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme)
+		assert.NoError(t, err)
+
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: "default",
+			},
+			Spec: v1alpha2.JenkinsSpec{
+				Roles: []rbacv1.RoleRef{
+					{
+						APIGroup: "rbac.authorization.k8s.io",
+						Kind:     "ClusterRole",
+						Name:     "edit",
+					},
+				},
+			},
+		}
+		config := configuration.Configuration{
+			Client:  fakeClient,
+			Jenkins: jenkins,
+			Scheme:  scheme.Scheme,
+		}
+		reconciler := New(config, client.JenkinsAPIConnectionSettings{})
+		err = reconciler.ensureExtraRBAC(resources.NewResourceObjectMeta(jenkins))
+		assert.NoError(t, err)
+
+		// when
+		jenkins.Spec.CommonLabels = map[string]string{"team": "ci"}
+		jenkins.Spec.CommonAnnotations = map[string]string{"cost-center": "42"}
+		metaObject := resources.NewResourceObjectMeta(jenkins)
+		err = reconciler.ensureExtraRBAC(metaObject)
+		assert.NoError(t, err)
+
+		// then
+		roleBinding := &rbacv1.RoleBinding{}
+		name := getExtraRoleBindingName(metaObject.Name, jenkins.Spec.Roles[0])
+		err = fakeClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: metaObject.Namespace}, roleBinding)
+		assert.NoError(t, err)
+		assert.Equal(t, "ci", roleBinding.Labels["team"])
+		assert.Equal(t, map[string]string{"cost-center": "42"}, roleBinding.Annotations)
+	})
+}
+
+func TestCreateService(t *testing.T) {
+	t.Run("selector uses only operator labels", func(t *testing.T) {
+		// given
+		fakeClient := &applyPatchClient{Client: fake.NewClientBuilder().Build()}
+		err := v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme)
+		assert.NoError(t, err)
+
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: "default",
+			},
+			Spec: v1alpha2.JenkinsSpec{
+				CommonLabels:      map[string]string{"team": "ci"},
+				CommonAnnotations: map[string]string{"cost-center": "42"},
+				Service: v1alpha2.Service{
+					Port:        8080,
+					Annotations: map[string]string{"service": "annotation"},
+				},
+			},
+		}
+		config := configuration.Configuration{
+			Client:  fakeClient,
+			Jenkins: jenkins,
+			Scheme:  scheme.Scheme,
+		}
+		reconciler := New(config, client.JenkinsAPIConnectionSettings{})
+		metaObject := resources.NewResourceObjectMeta(jenkins)
+		name := resources.GetJenkinsHTTPServiceName(jenkins)
+
+		// when
+		err = reconciler.createService(metaObject, name, jenkins.Spec.Service, 8080)
+		assert.NoError(t, err)
+
+		// then
+		service := &corev1.Service{}
+		err = fakeClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: metaObject.Namespace}, service)
+		assert.NoError(t, err)
+		assert.Equal(t, resources.BuildResourceLabels(jenkins), service.Spec.Selector)
+		assert.Equal(t, "ci", service.Labels["team"])
+		assert.Equal(t, map[string]string{"cost-center": "42", "service": "annotation"}, service.Annotations)
+	})
+	t.Run("keeps annotations set by other controllers", func(t *testing.T) {
+		// given
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: "default",
+			},
+			Spec: v1alpha2.JenkinsSpec{
+				Service: v1alpha2.Service{
+					Port:        8080,
+					Annotations: map[string]string{"service": "annotation"},
+				},
+			},
+		}
+		name := resources.GetJenkinsHTTPServiceName(jenkins)
+		existing := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: map[string]string{"external-dns.alpha.kubernetes.io/hostname": "jenkins.example.com"},
+			},
+		}
+		fakeClient := &applyPatchClient{Client: fake.NewClientBuilder().WithObjects(existing).Build()}
+		config := configuration.Configuration{
+			Client:  fakeClient,
+			Jenkins: jenkins,
+			Scheme:  scheme.Scheme,
+		}
+		reconciler := New(config, client.JenkinsAPIConnectionSettings{})
+
+		// when
+		err := reconciler.createService(resources.NewResourceObjectMeta(jenkins), name, jenkins.Spec.Service, 8080)
+		assert.NoError(t, err)
+
+		// then
+		service := &corev1.Service{}
+		err = fakeClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "default"}, service)
+		assert.NoError(t, err)
+		assert.Equal(t, "jenkins.example.com", service.Annotations["external-dns.alpha.kubernetes.io/hostname"])
+		assert.Equal(t, "annotation", service.Annotations["service"])
+		assert.Equal(t, int32(8080), service.Spec.Ports[0].Port)
+	})
+}
+
+func TestCreateServiceAdoptionPolicy(t *testing.T) {
+	newJenkins := func(policy v1alpha2.AdoptionPolicy) *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: "default",
+				UID:       "jenkins-uid",
+			},
+			Spec: v1alpha2.JenkinsSpec{
+				AdoptionPolicy: policy,
+				Service:        v1alpha2.Service{Port: 8080},
+			},
+		}
+	}
+	newExisting := func(jenkins *v1alpha2.Jenkins) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        resources.GetJenkinsHTTPServiceName(jenkins),
+				Namespace:   "default",
+				Annotations: map[string]string{"created-by": "helm"},
+			},
+			Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}},
+		}
+	}
+	createService := func(jenkins *v1alpha2.Jenkins, fakeClient k8sclient.Client, notifications *chan event.Event) error {
+		config := configuration.Configuration{
+			Client:        fakeClient,
+			Jenkins:       jenkins,
+			Scheme:        scheme.Scheme,
+			Notifications: notifications,
+		}
+		reconciler := New(config, client.JenkinsAPIConnectionSettings{})
+		return reconciler.createService(resources.NewResourceObjectMeta(jenkins), resources.GetJenkinsHTTPServiceName(jenkins), jenkins.Spec.Service, 8080)
+	}
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+
+	t.Run("adopts pre-existing service", func(t *testing.T) {
+		jenkins := newJenkins(v1alpha2.AdoptAdoptionPolicy)
+		existing := newExisting(jenkins)
+		fakeClient := &applyPatchClient{Client: fake.NewClientBuilder().WithObjects(existing).Build()}
+		notifications := make(chan event.Event, 1)
+
+		err := createService(jenkins, fakeClient, &notifications)
+
+		require.NoError(t, err)
+		service := &corev1.Service{}
+		require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: existing.Name, Namespace: "default"}, service))
+		assert.True(t, metav1.IsControlledBy(service, jenkins))
+		assert.Equal(t, int32(8080), service.Spec.Ports[0].Port)
+		require.Len(t, notifications, 1)
+		notification := <-notifications
+		assert.Equal(t, v1alpha2.NotificationLevelInfo, notification.Level)
+		assert.Equal(t, []string{"Adopted pre-existing Service 'jenkins-operator-http-example'"}, notification.Reason.Short())
+	})
+	t.Run("doesn't notify about already owned service", func(t *testing.T) {
+		jenkins := newJenkins("")
+		existing := newExisting(jenkins)
+		fakeClient := &applyPatchClient{Client: fake.NewClientBuilder().WithObjects(existing).Build()}
+		notifications := make(chan event.Event, 2)
+
+		require.NoError(t, createService(jenkins, fakeClient, &notifications))
+		require.NoError(t, createService(jenkins, fakeClient, &notifications))
+
+		assert.Len(t, notifications, 1)
+	})
+	t.Run("fails on pre-existing service", func(t *testing.T) {
+		jenkins := newJenkins(v1alpha2.FailAdoptionPolicy)
+		existing := newExisting(jenkins)
+		fakeClient := &applyPatchClient{Client: fake.NewClientBuilder().WithObjects(existing).Build()}
+
+		err := createService(jenkins, fakeClient, nil)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "spec.adoptionPolicy")
+		assert.Equal(t, 0, fakeClient.patches)
+	})
+	t.Run("ignores pre-existing service", func(t *testing.T) {
+		jenkins := newJenkins(v1alpha2.IgnoreAdoptionPolicy)
+		existing := newExisting(jenkins)
+		fakeClient := &applyPatchClient{Client: fake.NewClientBuilder().WithObjects(existing).Build()}
+
+		err := createService(jenkins, fakeClient, nil)
+
+		require.NoError(t, err)
+		service := &corev1.Service{}
+		require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: existing.Name, Namespace: "default"}, service))
+		assert.Empty(t, service.OwnerReferences)
+		assert.Equal(t, int32(80), service.Spec.Ports[0].Port)
+	})
+	t.Run("fails on service controlled by other controller", func(t *testing.T) {
+		jenkins := newJenkins(v1alpha2.AdoptAdoptionPolicy)
+		existing := newExisting(jenkins)
+		controller := true
+		existing.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "other", UID: "other-uid", Controller: &controller}}
+		fakeClient := &applyPatchClient{Client: fake.NewClientBuilder().WithObjects(existing).Build()}
+
+		err := createService(jenkins, fakeClient, nil)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is controlled by Deployment 'other'")
+	})
+}
+
+func TestEnsureBaseGroovyScripts(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			BaseGroovyScripts: v1alpha2.GroovyScripts{
+				Customization: v1alpha2.Customization{
+					Secret:         v1alpha2.SecretRef{Name: "cloud-credentials"},
+					Configurations: []v1alpha2.ConfigMapRef{{Name: "external-cloud"}},
+				},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloud-credentials", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("s3cr3t")},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "external-cloud", Namespace: "default"},
+		Data:       map[string]string{"cloud.groovy": "println secrets['token'].length()"},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(jenkins, secret, configMap).Build()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	jenkinsClient := client.NewMockJenkins(ctrl)
+	reconciler := New(configuration.Configuration{Client: fakeClient, Jenkins: jenkins, Scheme: scheme.Scheme}, client.JenkinsAPIConnectionSettings{})
+
+	t.Run("synchronizes secret", func(t *testing.T) {
+		jenkinsClient.EXPECT().ExecuteScript(gomock.Any()).DoAndReturn(func(script string) (string, error) {
+			assert.Contains(t, script, resources.BaseGroovyScriptsSecretVolumePath)
+			return "", nil
+		})
+
+		result, err := reconciler.ensureBaseGroovyScripts(jenkinsClient)
+
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+	})
+	t.Run("applies script with secrets loader", func(t *testing.T) {
+		jenkinsClient.EXPECT().ExecuteScript(gomock.Any()).DoAndReturn(func(script string) (string, error) {
+			assert.Contains(t, script, fmt.Sprintf("def secretsPath = '%s'", resources.BaseGroovyScriptsSecretVolumePath))
+			assert.Contains(t, script, "println secrets['token'].length()")
+			return "", nil
+		})
+
+		result, err := reconciler.ensureBaseGroovyScripts(jenkinsClient)
+
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+
+		result, err = reconciler.ensureBaseGroovyScripts(jenkinsClient)
+
+		require.NoError(t, err)
+		assert.False(t, result.Requeue)
+		assert.Len(t, jenkins.Status.AppliedGroovyScripts, 2)
+	})
+}
+
+func TestCreateServiceAccount(t *testing.T) {
+	t.Run("common annotations are merged with service account annotations", func(t *testing.T) {
+		// given
+		fakeClient := &applyPatchClient{Client: fake.NewClientBuilder().Build()}
+		err := v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme)
+		assert.NoError(t, err)
+
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: "default",
+			},
+			Spec: v1alpha2.JenkinsSpec{
+				CommonAnnotations: map[string]string{"cost-center": "42", "shared": "common"},
+				ServiceAccount: v1alpha2.ServiceAccount{
+					Annotations: map[string]string{"shared": "service-account"},
+				},
+			},
+		}
+		config := configuration.Configuration{
+			Client:  fakeClient,
+			Jenkins: jenkins,
+			Scheme:  scheme.Scheme,
+		}
+		reconciler := New(config, client.JenkinsAPIConnectionSettings{})
+		metaObject := resources.NewResourceObjectMeta(jenkins)
+
+		// when
+		err = reconciler.createServiceAccount(metaObject)
+		assert.NoError(t, err)
+
+		// then
+		serviceAccount := &corev1.ServiceAccount{}
+		err = fakeClient.Get(context.TODO(), types.NamespacedName{Name: metaObject.Name, Namespace: metaObject.Namespace}, serviceAccount)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"cost-center": "42", "shared": "service-account"}, serviceAccount.Annotations)
+	})
+}
+
 func TestCompareContainerResources(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		var expected corev1.ResourceRequirements
@@ -993,4 +1616,160 @@ func TestCompareContainerResources(t *testing.T) {
 
 		assert.False(t, got)
 	})
+	t.Run("ephemeral storage the same values", func(t *testing.T) {
+		actual := corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceEphemeralStorage: resource.MustParse("1Gi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceEphemeralStorage: resource.MustParse("2Gi"),
+			},
+		}
+		expected := actual
+
+		got := compareContainerResources(expected, actual)
+
+		assert.True(t, got)
+	})
+	t.Run("limit ephemeral storage different values", func(t *testing.T) {
+		expected := corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceEphemeralStorage: resource.MustParse("2Gi"),
+			},
+		}
+		actual := corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceEphemeralStorage: resource.MustParse("1Gi"),
+			},
+		}
+
+		got := compareContainerResources(expected, actual)
+
+		assert.False(t, got)
+	})
+	t.Run("request CPU the same value in different format", func(t *testing.T) {
+		expected := corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("0.5"),
+			},
+		}
+		actual := corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("500m"),
+			},
+		}
+
+		got := compareContainerResources(expected, actual)
+
+		assert.True(t, got)
+	})
+}
+
+func TestEnsureExtraResources(t *testing.T) {
+	log.SetupLogger(true)
+	assert.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	manifests := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "extra-resources", Namespace: "default"},
+		Data: map[string]string{
+			"config-maps.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: first\ndata:\n  key: value\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: second\n",
+		},
+	}
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			ExtraResources: []v1alpha2.ExtraResource{
+				{Manifest: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"custom"},"spec":{"ports":[{"port":8080}]}}`)}},
+				{ConfigMapRef: &v1alpha2.ConfigMapRef{Name: manifests.Name}},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(jenkins, manifests).Build()
+	reconciler := New(configuration.Configuration{Client: fakeClient, Jenkins: jenkins, Scheme: scheme.Scheme}, client.JenkinsAPIConnectionSettings{})
+
+	err := reconciler.ensureExtraResources()
+
+	require.NoError(t, err)
+	assert.Equal(t, []v1alpha2.ExtraResourceReference{
+		{APIVersion: "v1", Kind: "Service", Name: "custom"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "first"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "second"},
+	}, jenkins.Status.AppliedExtraResources)
+	service := &corev1.Service{}
+	require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "custom"}, service))
+	assert.True(t, metav1.IsControlledBy(service, jenkins))
+	assert.Equal(t, "example", service.Labels["jenkins-cr"])
+	first := &corev1.ConfigMap{}
+	require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "first"}, first))
+	assert.Equal(t, map[string]string{"key": "value"}, first.Data)
+
+	jenkins.Spec.ExtraResources = jenkins.Spec.ExtraResources[:1]
+	jenkins.Spec.ExtraResources[0].Manifest.Raw = []byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"custom"},"spec":{"ports":[{"port":9090}]}}`)
+
+	err = reconciler.ensureExtraResources()
+
+	require.NoError(t, err)
+	assert.Equal(t, []v1alpha2.ExtraResourceReference{{APIVersion: "v1", Kind: "Service", Name: "custom"}}, jenkins.Status.AppliedExtraResources)
+	require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "custom"}, service))
+	assert.Equal(t, int32(9090), service.Spec.Ports[0].Port)
+	err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "first"}, first)
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestEnsureExtraResourcesRejectedKind(t *testing.T) {
+	log.SetupLogger(true)
+	assert.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	manifests := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "extra-resources", Namespace: "default"},
+		Data: map[string]string{
+			"rbac.yaml": "apiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRoleBinding\nmetadata:\n  name: escalate\nroleRef:\n  apiGroup: rbac.authorization.k8s.io\n  kind: ClusterRole\n  name: cluster-admin\n",
+		},
+	}
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			ExtraResources: []v1alpha2.ExtraResource{{ConfigMapRef: &v1alpha2.ConfigMapRef{Name: manifests.Name}}},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(jenkins, manifests).Build()
+	reconciler := New(configuration.Configuration{Client: fakeClient, Jenkins: jenkins, Scheme: scheme.Scheme}, client.JenkinsAPIConnectionSettings{})
+
+	err := reconciler.ensureExtraResources()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "RBAC kind 'ClusterRoleBinding.rbac.authorization.k8s.io' can't be applied")
+	assert.Empty(t, jenkins.Status.AppliedExtraResources)
+	err = fakeClient.Get(context.TODO(), types.NamespacedName{Name: "escalate"}, &rbacv1.ClusterRoleBinding{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestNewRouteApplyObject(t *testing.T) {
+	route := routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins-jenkins", Namespace: "default"},
+		Spec: routev1.RouteSpec{
+			To:   routev1.RouteTargetReference{Kind: resources.ServiceKind, Name: "jenkins-operator-http-jenkins"},
+			Port: &routev1.RoutePort{TargetPort: intstr.FromInt(8080)},
+		},
+	}
+
+	t.Run("the host generated by the router is kept", func(t *testing.T) {
+		object, err := newRouteApplyObject(route)
+
+		require.NoError(t, err)
+		assert.Equal(t, "route.openshift.io/v1, Kind=Route", object.GroupVersionKind().String())
+		_, found, _ := unstructured.NestedFieldNoCopy(object.Object, "spec", "host")
+		assert.False(t, found)
+		_, found, _ = unstructured.NestedFieldNoCopy(object.Object, "spec", "to", "weight")
+		assert.False(t, found)
+		name, _, _ := unstructured.NestedString(object.Object, "spec", "to", "name")
+		assert.Equal(t, "jenkins-operator-http-jenkins", name)
+	})
+	t.Run("host", func(t *testing.T) {
+		route.Spec.Host = "jenkins.example.com"
+
+		object, err := newRouteApplyObject(route)
+
+		require.NoError(t, err)
+		host, _, _ := unstructured.NestedString(object.Object, "spec", "host")
+		assert.Equal(t, "jenkins.example.com", host)
+	})
 }
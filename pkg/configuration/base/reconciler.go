@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 	"time"
 
@@ -14,8 +15,10 @@ import (
 	"github.com/maximba/kubernetes-operator/pkg/configuration"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
 	"github.com/maximba/kubernetes-operator/pkg/constants"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
 	"github.com/maximba/kubernetes-operator/pkg/groovy"
 	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
 	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
 
 	"github.com/go-logr/logr"
@@ -30,6 +33,17 @@ import (
 
 const (
 	fetchAllPlugins = 1
+
+	defaultPodPendingTimeout = 2 * time.Minute
+	// podStartingBackoffBase is the delay before the Jenkins master pod pending for longer than the pending timeout is
+	// recreated for the first time, it doubles with every recreation up to podStartingBackoffMax
+	podStartingBackoffBase = time.Minute
+	podStartingBackoffMax  = 30 * time.Minute
+
+	failedSchedulingEventReason = "FailedScheduling"
+
+	podPendingEventReason   k8sevent.Reason = "JenkinsMasterPodPending"
+	podRecreatedEventReason k8sevent.Reason = "JenkinsMasterPodRecreated"
 )
 
 // ReconcileJenkinsBaseConfiguration defines values required for Jenkins base configuration.
@@ -43,30 +57,53 @@ type JenkinsBaseConfigurationReconciler struct {
 func New(config configuration.Configuration, jenkinsAPIConnectionSettings jenkinsclient.JenkinsAPIConnectionSettings) *JenkinsBaseConfigurationReconciler {
 	return &JenkinsBaseConfigurationReconciler{
 		Configuration:                config,
-		logger:                       log.Log.WithValues("cr", config.Jenkins.Name),
+		logger:                       log.Log.WithValues("cr", config.Jenkins.Name, "namespace", config.Jenkins.Namespace),
 		jenkinsAPIConnectionSettings: jenkinsAPIConnectionSettings,
 	}
 }
 
-// Reconcile takes care of base configuration.
+// Reconcile takes care of base configuration. Jenkins master pod is restarted at most once per reconcile loop, all
+// changes which require restart found in the loop are applied by the same restart.
 func (r *JenkinsBaseConfigurationReconciler) Reconcile() (reconcile.Result, jenkinsclient.Jenkins, error) {
+	result, jenkinsClient, err := r.reconcileBaseConfiguration()
+	restarted, restartErr := r.Configuration.RestartRequestedJenkinsMasterPod()
+	if restartErr != nil {
+		return reconcile.Result{}, nil, restartErr
+	}
+	if restarted {
+		// errors of the replaced pod don't matter anymore
+		return reconcile.Result{Requeue: true}, nil, nil
+	}
+	return result, jenkinsClient, err
+}
+
+func (r *JenkinsBaseConfigurationReconciler) reconcileBaseConfiguration() (reconcile.Result, jenkinsclient.Jenkins, error) {
 	metaObject := resources.NewResourceObjectMeta(r.Configuration.Jenkins)
 
+	if r.Configuration.Jenkins.Spec.ExternalJenkins != nil {
+		return r.reconcileExternalJenkins(metaObject)
+	}
+
 	// Create Necessary Resources
+	start := time.Now()
 	err := r.ensureResourcesRequiredForJenkinsPod(metaObject)
+	r.Timer.Record(configuration.ResourcesEnsurePhase, start)
 	if err != nil {
 		return reconcile.Result{}, nil, err
 	}
 	r.logger.V(log.VDebug).Info("Kubernetes resources are present")
 
+	start = time.Now()
 	if useDeploymentForJenkinsMaster(r.Configuration.Jenkins) {
 		result, err := r.ensureJenkinsDeployment(metaObject)
 		if err != nil {
 			return reconcile.Result{}, nil, err
 		}
 		if result.Requeue {
+			r.Timer.WaitForPod(start)
 			return result, nil, nil
 		}
+		r.Timer.PodReady(start)
 		r.logger.V(log.VDebug).Info("Jenkins Deployment is present")
 
 		return result, nil, err
@@ -77,16 +114,25 @@ func (r *JenkinsBaseConfigurationReconciler) Reconcile() (reconcile.Result, jenk
 		return reconcile.Result{}, nil, err
 	}
 	if result.Requeue {
+		r.Timer.WaitForPod(start)
 		return result, nil, nil
 	}
 	r.logger.V(log.VDebug).Info("Jenkins master pod is present")
 
-	stopReconcileLoop, err := r.detectJenkinsMasterPodStartingIssues()
+	result, stopReconcileLoop, err := r.detectJenkinsMasterPodStartingIssues()
 	if err != nil {
 		return reconcile.Result{}, nil, err
 	}
 	if stopReconcileLoop {
-		return reconcile.Result{Requeue: false}, nil, nil
+		return result, nil, nil
+	}
+
+	result, err = r.reconcileJenkinsHomeVolumeResize()
+	if err != nil {
+		return reconcile.Result{}, nil, err
+	}
+	if result.Requeue {
+		return result, nil, nil
 	}
 
 	result, err = r.waitForJenkins()
@@ -94,33 +140,74 @@ func (r *JenkinsBaseConfigurationReconciler) Reconcile() (reconcile.Result, jenk
 		return reconcile.Result{}, nil, err
 	}
 	if result.Requeue {
+		r.Timer.WaitForPod(start)
 		return result, nil, nil
 	}
+	r.Timer.PodReady(start)
 	r.logger.V(log.VDebug).Info("Jenkins master pod is ready")
 
+	if err = r.reconcileJenkinsHomeVolumeMigration(); err != nil {
+		return reconcile.Result{}, nil, err
+	}
+
 	jenkinsClient, err := r.Configuration.GetJenkinsClient()
 	if err != nil {
 		return reconcile.Result{}, nil, err
 	}
 	r.logger.V(log.VDebug).Info("Jenkins API client set")
 
-	ok, err := r.verifyPlugins(jenkinsClient)
+	result, err = r.waitForReadinessGates(jenkinsClient)
 	if err != nil {
 		return reconcile.Result{}, nil, err
 	}
-	if !ok {
-		//TODO add what plugins have been changed
-		message := "Some plugins have changed, restarting Jenkins"
-		r.logger.Info(message)
+	if result.Requeue {
+		return result, nil, nil
+	}
 
-		restartReason := reason.NewPodRestart(
-			reason.OperatorSource,
-			[]string{message},
-		)
-		return reconcile.Result{Requeue: true}, nil, r.Configuration.RestartJenkinsMasterPod(restartReason)
+	if r.Configuration.Jenkins.Spec.Master.SkipBaseConfiguration {
+		r.logger.V(log.VDebug).Info("Base configuration is disabled, skipping plugins verification and base groovy scripts")
+		return reconcile.Result{}, jenkinsClient, nil
 	}
 
+	start = time.Now()
+	drifts, err := r.verifyPlugins(jenkinsClient)
+	r.Timer.Record(configuration.PluginsVerifyPhase, start)
+	if err != nil {
+		return reconcile.Result{}, nil, err
+	}
+	if err = r.setPluginsVerifiedCondition(drifts); err != nil {
+		return reconcile.Result{}, nil, err
+	}
+	if len(drifts) == 0 {
+		if err = r.clearPluginUpgrades(); err != nil {
+			return reconcile.Result{}, nil, err
+		}
+	} else if isPluginsUpgrade(drifts) {
+		if upgraded, err := r.upgradePlugins(jenkinsClient, drifts); err != nil || upgraded {
+			return reconcile.Result{Requeue: true}, nil, err
+		}
+	} else {
+		r.logger.Info(fmt.Sprintf("Some plugins have changed, restarting Jenkins: %s", strings.Join(pluginDriftMessages(drifts), ", ")))
+		restartReason := newPluginsRestartReason(drifts)
+		if restarted, err := r.Configuration.RequestJenkinsMasterPodRestart(restartReason); err != nil || restarted {
+			return reconcile.Result{Requeue: true}, nil, err
+		}
+	}
+	if r.Configuration.PendingRestart.Requested() {
+		// don't configure Jenkins which is going to be restarted
+		return reconcile.Result{Requeue: true}, nil, nil
+	}
+
+	if err = r.ensurePluginsLock(jenkinsClient); err != nil {
+		return reconcile.Result{}, nil, err
+	}
+	if err = r.ensureSeedJobPlugins(jenkinsClient); err != nil {
+		return reconcile.Result{}, nil, err
+	}
+
+	start = time.Now()
 	result, err = r.ensureBaseConfiguration(jenkinsClient)
+	r.Timer.Record(configuration.BaseGroovyScriptsPhase, start)
 
 	return result, jenkinsClient, err
 }
@@ -155,6 +242,16 @@ func (r *JenkinsBaseConfigurationReconciler) ensureResourcesRequiredForJenkinsPo
 	}
 	r.logger.V(log.VDebug).Info("Base configuration config map is present")
 
+	if err := r.ensureJenkinsHomePersistentVolumeClaim(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Jenkins home persistent volume claim is present")
+
+	if err := r.addLabelForWatchesResources(r.Configuration.Jenkins.Spec.BaseGroovyScripts.Customization); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("BaseGroovyScripts Secret and ConfigMap added watched labels")
+
 	if err := r.addLabelForWatchesResources(r.Configuration.Jenkins.Spec.GroovyScripts.Customization); err != nil {
 		return err
 	}
@@ -165,6 +262,11 @@ func (r *JenkinsBaseConfigurationReconciler) ensureResourcesRequiredForJenkinsPo
 	}
 	r.logger.V(log.VDebug).Info("ConfigurationAsCode Secret and ConfigMap added watched labels")
 
+	if err := r.addLabelForWatchedValues(); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Values ConfigMap added watched labels")
+
 	if err := r.createRBAC(metaObject); err != nil {
 		return err
 	}
@@ -181,10 +283,18 @@ func (r *JenkinsBaseConfigurationReconciler) ensureResourcesRequiredForJenkinsPo
 	}
 	r.logger.V(log.VDebug).Info("Jenkins HTTP Service is present")
 
-	if err := r.createService(metaObject, resources.GetJenkinsSlavesServiceName(r.Configuration.Jenkins), r.Configuration.Jenkins.Spec.SlaveService, constants.DefaultSlavePortInt32); err != nil {
-		return err
+	slaveServiceName := resources.GetJenkinsSlavesServiceName(r.Configuration.Jenkins)
+	if r.Configuration.Jenkins.Spec.Master.DisableKubernetesCloud {
+		if err := r.deleteService(slaveServiceName); err != nil {
+			return err
+		}
+		r.logger.V(log.VDebug).Info("Jenkins slave Service is absent, Kubernetes cloud is disabled")
+	} else {
+		if err := r.createService(metaObject, slaveServiceName, r.Configuration.Jenkins.Spec.SlaveService, resources.GetJenkinsAgentPort(r.Configuration.Jenkins)); err != nil {
+			return err
+		}
+		r.logger.V(log.VDebug).Info("Jenkins slave Service is present")
 	}
-	r.logger.V(log.VDebug).Info("Jenkins slave Service is present")
 
 	if resources.IsRouteAPIAvailable(&r.ClientSet) {
 		r.logger.V(log.VDebug).Info("Route API is available. Now creating route.")
@@ -194,10 +304,24 @@ func (r *JenkinsBaseConfigurationReconciler) ensureResourcesRequiredForJenkinsPo
 		r.logger.V(log.VDebug).Info("Jenkins Route is present")
 	}
 
+	if err := r.ensureExtraResources(); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Extra resources are present")
+
+	if err := r.ensurePrometheusRule(); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("PrometheusRule is present")
+
 	return nil
 }
 
 func (r *JenkinsBaseConfigurationReconciler) createOperatorCredentialsSecret(meta metav1.ObjectMeta) error {
+	if resources.IsImmutableConfiguration(r.Configuration.Jenkins) {
+		return r.createOperatorCredentialsSecretVersion(meta)
+	}
+
 	found := &corev1.Secret{}
 	err := r.Configuration.Client.Get(context.TODO(), types.NamespacedName{Name: resources.GetOperatorCredentialsSecretName(r.Configuration.Jenkins), Namespace: r.Configuration.Jenkins.ObjectMeta.Namespace}, found)
 
@@ -216,7 +340,7 @@ func (r *JenkinsBaseConfigurationReconciler) createOperatorCredentialsSecret(met
 
 func (r *JenkinsBaseConfigurationReconciler) calculateUserAndPasswordHash() (string, error) {
 	credentialsSecret := &corev1.Secret{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: resources.GetOperatorCredentialsSecretName(r.Configuration.Jenkins), Namespace: r.Configuration.Jenkins.ObjectMeta.Namespace}, credentialsSecret)
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: resources.GetCurrentOperatorCredentialsSecretName(r.Configuration.Jenkins), Namespace: r.Configuration.Jenkins.ObjectMeta.Namespace}, credentialsSecret)
 	if err != nil {
 		return "", stackerr.WithStack(err)
 	}
@@ -311,55 +435,224 @@ func (r *JenkinsBaseConfigurationReconciler) compareVolumes(actualPod corev1.Pod
 	)
 }
 
-func (r *JenkinsBaseConfigurationReconciler) detectJenkinsMasterPodStartingIssues() (stopReconcileLoop bool, err error) {
+// detectJenkinsMasterPodStartingIssues reports why the Jenkins master pod has been pending for longer than the pending
+// timeout and recreates the pod with exponential backoff, the reconcile loop doesn't continue until the pod starts
+func (r *JenkinsBaseConfigurationReconciler) detectJenkinsMasterPodStartingIssues() (result reconcile.Result, stopReconcileLoop bool, err error) {
 	jenkinsMasterPod, err := r.Configuration.GetJenkinsMasterPod()
 	if err != nil {
-		return false, err
+		return reconcile.Result{}, false, err
 	}
 
-	if r.Configuration.Jenkins.Status.ProvisionStartTime == nil {
-		return true, nil
+	status := &r.Configuration.Jenkins.Status
+	if status.ProvisionStartTime == nil {
+		return reconcile.Result{}, true, nil
 	}
 
-	if jenkinsMasterPod.Status.Phase == corev1.PodPending {
-		timeout := r.Configuration.Jenkins.Status.ProvisionStartTime.Add(time.Minute * 2).UTC()
-		now := time.Now().UTC()
-		if now.After(timeout) {
-			events := &corev1.EventList{}
-			err = r.Client.List(context.TODO(), events, client.InNamespace(r.Configuration.Jenkins.Namespace))
-			if err != nil {
-				return false, stackerr.WithStack(err)
-			}
+	if jenkinsMasterPod.Status.Phase != corev1.PodPending {
+		if status.PodStartingRetries == 0 && status.BackoffUntil == nil {
+			return reconcile.Result{}, false, nil
+		}
+		r.logger.Info(fmt.Sprintf("Jenkins master pod has left Pending phase after %d retries", status.PodStartingRetries))
+		status.PodStartingRetries = 0
+		status.BackoffUntil = nil
+		return reconcile.Result{}, false, stackerr.WithStack(r.Client.Status().Update(context.TODO(), r.Configuration.Jenkins))
+	}
 
-			filteredEvents := r.filterEvents(*events, *jenkinsMasterPod)
+	pendingTimeout := defaultPodPendingTimeout
+	if r.Configuration.Jenkins.Spec.Master.PodPendingTimeout != nil {
+		pendingTimeout = r.Configuration.Jenkins.Spec.Master.PodPendingTimeout.Duration
+	}
+	timeout := status.ProvisionStartTime.Add(pendingTimeout).UTC()
+	now := time.Now().UTC()
+	if !now.After(timeout) {
+		return reconcile.Result{}, false, nil
+	}
 
-			if len(filteredEvents) == 0 {
-				return false, nil
-			}
+	if status.BackoffUntil != nil {
+		if now.Before(status.BackoffUntil.UTC()) {
+			return reconcile.Result{Requeue: true, RequeueAfter: status.BackoffUntil.Sub(now)}, true, nil
+		}
+		return reconcile.Result{Requeue: true}, true, r.recreatePendingJenkinsMasterPod(pendingTimeout)
+	}
+
+	events := &corev1.EventList{}
+	err = r.Client.List(context.TODO(), events, client.InNamespace(r.Configuration.Jenkins.Namespace))
+	if err != nil {
+		return reconcile.Result{}, false, stackerr.WithStack(err)
+	}
 
-			r.logger.Info(fmt.Sprintf("Jenkins master pod starting timeout, events '%+v'", filteredEvents))
-			return true, nil
+	filteredEvents, schedulingFailures := r.filterEvents(*events, *jenkinsMasterPod)
+
+	causes, err := r.diagnoseJenkinsMasterPod(*jenkinsMasterPod)
+	if err != nil {
+		return reconcile.Result{}, false, err
+	}
+	for _, message := range schedulingFailures {
+		cause := fmt.Sprintf("pod is unschedulable: %s", message)
+		if !containsString(causes, cause) {
+			causes = append(causes, cause)
 		}
 	}
 
-	return false, nil
+	if len(filteredEvents) == 0 && len(causes) == 0 {
+		return reconcile.Result{}, false, nil
+	}
+
+	r.logger.Info(fmt.Sprintf("Jenkins master pod starting timeout, events '%+v'", filteredEvents))
+	if len(causes) == 0 {
+		causes = []string{"unknown cause, check Jenkins master pod events"}
+	}
+	diagnosis := fmt.Sprintf("Jenkins master pod has been pending for more than %s: %s", pendingTimeout, strings.Join(causes, ", "))
+	diagnosisChanged := status.PodStartingDiagnosis != diagnosis
+
+	backoff := podStartingBackoff(status.PodStartingRetries)
+	backoffUntil := metav1.NewTime(now.Add(backoff))
+	status.BackoffUntil = &backoffUntil
+	status.PodStartingDiagnosis = diagnosis
+	if err = r.Client.Status().Update(context.TODO(), r.Configuration.Jenkins); err != nil {
+		return reconcile.Result{}, false, stackerr.WithStack(err)
+	}
+	r.logger.Info(fmt.Sprintf("%s, the pod will be recreated in %s", diagnosis, backoff))
+	if r.Configuration.Events != nil {
+		r.Configuration.Events.Emit(r.Configuration.Jenkins, k8sevent.TypeWarning, podPendingEventReason,
+			fmt.Sprintf("%s, the pod will be recreated in %s", diagnosis, backoff))
+	}
+	if diagnosisChanged {
+		*r.Notifications <- event.Event{
+			Jenkins: *r.Configuration.Jenkins,
+			Phase:   event.PhaseBase,
+			Level:   v1alpha2.NotificationLevelWarning,
+			Reason:  reason.NewPodStartingFailed(reason.KubernetesSource, []string{diagnosis}, append([]string{diagnosis}, filteredEvents...)...),
+		}
+	}
+	return reconcile.Result{Requeue: true, RequeueAfter: backoff}, true, nil
 }
 
-func (r *JenkinsBaseConfigurationReconciler) filterEvents(source corev1.EventList, jenkinsMasterPod corev1.Pod) []string {
-	events := []string{}
-	for _, eventItem := range source.Items {
-		if r.Configuration.Jenkins.Status.ProvisionStartTime.UTC().After(eventItem.LastTimestamp.UTC()) {
+// recreatePendingJenkinsMasterPod deletes the Jenkins master pod once the backoff has elapsed, the new pod gets
+// the whole pending timeout again and the retry is kept in status
+func (r *JenkinsBaseConfigurationReconciler) recreatePendingJenkinsMasterPod(pendingTimeout time.Duration) error {
+	status := &r.Configuration.Jenkins.Status
+	status.PodStartingRetries++
+	status.BackoffUntil = nil
+	message := fmt.Sprintf("Jenkins master pod has been pending for more than %s, recreating it (retry %d)", pendingTimeout, status.PodStartingRetries)
+	r.logger.Info(message)
+	if r.Configuration.Events != nil {
+		r.Configuration.Events.Emit(r.Configuration.Jenkins, k8sevent.TypeNormal, podRecreatedEventReason, message)
+	}
+	return r.Configuration.RestartJenkinsMasterPod(reason.NewPodRestart(reason.OperatorSource, []string{message}, status.PodStartingDiagnosis))
+}
+
+// podStartingBackoff returns the delay before the pending Jenkins master pod is recreated
+func podStartingBackoff(retries int) time.Duration {
+	backoff := podStartingBackoffBase
+	for i := 0; i < retries && backoff < podStartingBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > podStartingBackoffMax {
+		return podStartingBackoffMax
+	}
+	return backoff
+}
+
+// diagnoseJenkinsMasterPod classifies common causes why the Jenkins master pod is stuck in Pending phase.
+func (r *JenkinsBaseConfigurationReconciler) diagnoseJenkinsMasterPod(jenkinsMasterPod corev1.Pod) ([]string, error) {
+	var causes []string
+
+	for _, condition := range jenkinsMasterPod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse && condition.Reason == corev1.PodReasonUnschedulable {
+			causes = append(causes, fmt.Sprintf("pod is unschedulable: %s", condition.Message))
+		}
+	}
+
+	containerStatuses := append([]corev1.ContainerStatus{}, jenkinsMasterPod.Status.InitContainerStatuses...)
+	containerStatuses = append(containerStatuses, jenkinsMasterPod.Status.ContainerStatuses...)
+	for _, containerStatus := range containerStatuses {
+		if containerStatus.State.Waiting == nil {
 			continue
 		}
-		if eventItem.Type == corev1.EventTypeNormal {
+		switch containerStatus.State.Waiting.Reason {
+		case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+			causes = append(causes, fmt.Sprintf("container '%s' can't pull image '%s': %s",
+				containerStatus.Name, containerStatus.Image, containerStatus.State.Waiting.Message))
+		}
+	}
+
+	for _, volume := range jenkinsMasterPod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		claim := &corev1.PersistentVolumeClaim{}
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: jenkinsMasterPod.Namespace, Name: volume.PersistentVolumeClaim.ClaimName}, claim)
+		if err != nil && apierrors.IsNotFound(err) {
+			causes = append(causes, fmt.Sprintf("persistent volume claim '%s' not found", volume.PersistentVolumeClaim.ClaimName))
 			continue
+		} else if err != nil {
+			return nil, stackerr.WithStack(err)
+		}
+		if claim.Status.Phase == corev1.ClaimPending {
+			causes = append(causes, fmt.Sprintf("persistent volume claim '%s' is pending", claim.Name))
 		}
+	}
+
+	return causes, nil
+}
+
+// filterEvents returns events of Jenkins master pod selected by spec.master.podEventsFilter and messages of
+// FailedScheduling events, which are reported as causes of the pod starting failure
+func (r *JenkinsBaseConfigurationReconciler) filterEvents(source corev1.EventList, jenkinsMasterPod corev1.Pod) (events []string, schedulingFailures []string) {
+	filter := r.Configuration.Jenkins.Spec.Master.PodEventsFilter
+	if filter == nil {
+		filter = &v1alpha2.PodEventsFilter{}
+	}
+	since := r.Configuration.Jenkins.Status.ProvisionStartTime.UTC()
+	if filter.LookbackWindow != nil {
+		since = since.Add(-filter.LookbackWindow.Duration)
+	}
+	var messageRegex *regexp.Regexp
+	if len(filter.MessageRegex) > 0 {
+		// the regular expression is validated before
+		messageRegex, _ = regexp.Compile(filter.MessageRegex)
+	}
+
+	events = []string{}
+	for _, eventItem := range source.Items {
 		if !strings.HasPrefix(eventItem.ObjectMeta.Name, jenkinsMasterPod.Name) {
 			continue
 		}
-		events = append(events, fmt.Sprintf("Message: %s Subobject: %s", eventItem.Message, eventItem.InvolvedObject.FieldPath))
+		lastTimestamp := eventItem.LastTimestamp.Time
+		if lastTimestamp.IsZero() {
+			lastTimestamp = eventItem.EventTime.Time
+		}
+		if since.After(lastTimestamp.UTC()) {
+			continue
+		}
+		if len(filter.Types) > 0 && !containsString(filter.Types, eventItem.Type) {
+			continue
+		}
+		if len(filter.Types) == 0 && eventItem.Type == corev1.EventTypeNormal {
+			continue
+		}
+		if len(filter.Reasons) > 0 && !containsString(filter.Reasons, eventItem.Reason) {
+			continue
+		}
+		if messageRegex != nil && !messageRegex.MatchString(eventItem.Message) {
+			continue
+		}
+		if eventItem.Reason == failedSchedulingEventReason && !containsString(schedulingFailures, eventItem.Message) {
+			schedulingFailures = append(schedulingFailures, eventItem.Message)
+		}
+		events = append(events, fmt.Sprintf("Reason: %s Message: %s Subobject: %s", eventItem.Reason, eventItem.Message, eventItem.InvolvedObject.FieldPath))
 	}
-	return events
+	return events, schedulingFailures
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *JenkinsBaseConfigurationReconciler) waitForJenkins() (reconcile.Result, error) {
@@ -384,9 +677,13 @@ func (r *JenkinsBaseConfigurationReconciler) waitForJenkins() (reconcile.Result,
 			message := fmt.Sprintf("Container '%s' is terminated, status '%+v'", containerStatus.Name, containerStatus)
 			r.logger.Info(message)
 
+			messages := []string{message}
+			if crashLogsMessage := r.saveCrashLogs(*jenkinsMasterPod); len(crashLogsMessage) > 0 {
+				messages = append(messages, crashLogsMessage)
+			}
 			restartReason := reason.NewPodRestart(
 				reason.KubernetesSource,
-				[]string{message},
+				messages,
 			)
 			return reconcile.Result{Requeue: true}, r.Configuration.RestartJenkinsMasterPod(restartReason)
 		}
@@ -407,14 +704,41 @@ func (r *JenkinsBaseConfigurationReconciler) ensureBaseConfiguration(jenkinsClie
 	customization := v1alpha2.GroovyScripts{
 		Customization: v1alpha2.Customization{
 			Secret:         v1alpha2.SecretRef{Name: ""},
-			Configurations: []v1alpha2.ConfigMapRef{{Name: resources.GetBaseConfigurationConfigMapName(r.Configuration.Jenkins)}},
+			Configurations: []v1alpha2.ConfigMapRef{{Name: resources.GetCurrentBaseConfigurationConfigMapName(r.Configuration.Jenkins)}},
 		},
 	}
-	groovyClient := groovy.New(jenkinsClient, r.Client, r.Configuration.Jenkins, "base-groovy", customization.Customization)
+	groovyClient := groovy.New(jenkinsClient, r.Client, r.Configuration.Jenkins, "base-groovy", customization.Customization).
+		WithProgress(r.setBaseConfigurationProgress)
 	requeue, err := groovyClient.Ensure(func(name string) bool {
 		return strings.HasSuffix(name, ".groovy")
 	}, func(groovyScript string) string {
 		return groovyScript
 	})
+	if err != nil || requeue {
+		return reconcile.Result{Requeue: requeue}, err
+	}
+
+	return r.ensureBaseGroovyScripts(jenkinsClient)
+}
+
+// ensureBaseGroovyScripts applies user groovy scripts of the base configuration phase, the secret is loaded into
+// the scripts the same way as for user configuration groovy scripts
+func (r *JenkinsBaseConfigurationReconciler) ensureBaseGroovyScripts(jenkinsClient jenkinsclient.Jenkins) (reconcile.Result, error) {
+	baseGroovyScripts := r.Configuration.Jenkins.Spec.BaseGroovyScripts
+	if len(baseGroovyScripts.Configurations) == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	groovyClient := groovy.New(jenkinsClient, r.Client, r.Configuration.Jenkins, "base-user-groovy", baseGroovyScripts.Customization).
+		Parallel(baseGroovyScripts.Parallelism, baseGroovyScripts.Dependencies).
+		WithClusterDomain(r.Configuration.KubernetesClusterDomain).
+		WithProgress(r.setBaseConfigurationProgress)
+	requeue, err := groovyClient.WaitForSecretSynchronization(resources.BaseGroovyScriptsSecretVolumePath)
+	if err != nil || requeue {
+		return reconcile.Result{Requeue: requeue}, err
+	}
+	requeue, err = groovyClient.Ensure(func(name string) bool {
+		return strings.HasSuffix(name, ".groovy")
+	}, groovy.AddSecretsLoaderToGroovyScript(resources.BaseGroovyScriptsSecretVolumePath))
 	return reconcile.Result{Requeue: requeue}, err
 }
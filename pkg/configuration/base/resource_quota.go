@@ -0,0 +1,142 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	resourceQuotaExceededEventReason k8sevent.Reason = "ResourceQuotaExceeded"
+
+	// resourceQuotaRequeueDelay is how often the ResourceQuotas are checked again, they aren't watched by the operator
+	resourceQuotaRequeueDelay = 30 * time.Second
+)
+
+// ensureResourceQuotaHeadroom verifies the ResourceQuotas in the namespace have room for the Jenkins master pod before
+// it's created, otherwise the pod would be rejected or stay in Pending phase until the pending timeout
+func (r *JenkinsBaseConfigurationReconciler) ensureResourceQuotaHeadroom(pod corev1.Pod) (reconcile.Result, error) {
+	jenkins := r.Configuration.Jenkins
+	quotas := &corev1.ResourceQuotaList{}
+	if err := r.Client.List(context.TODO(), quotas, client.InNamespace(pod.Namespace)); err != nil {
+		return reconcile.Result{}, stackerr.WithStack(err)
+	}
+
+	shortages := getResourceQuotaShortages(pod, quotas.Items)
+	if len(shortages) == 0 {
+		if len(quotas.Items) > 0 || meta.FindStatusCondition(jenkins.Status.Conditions, v1alpha2.ConditionResourceQuotaSufficient) != nil {
+			// the status is updated together with the created pod
+			configuration.SetCondition(jenkins, v1alpha2.ConditionResourceQuotaSufficient, metav1.ConditionTrue,
+				configuration.ConditionReasonResourceQuotaSufficient, "ResourceQuotas have room for Jenkins master pod")
+		}
+		return reconcile.Result{}, nil
+	}
+
+	message := fmt.Sprintf("Jenkins master pod %s", strings.Join(shortages, ", "))
+	r.logger.Info(fmt.Sprintf("%s, the pod won't be created until the quota has room for it", message))
+	if !configuration.SetCondition(jenkins, v1alpha2.ConditionResourceQuotaSufficient, metav1.ConditionFalse,
+		configuration.ConditionReasonResourceQuotaExceeded, message) {
+		return reconcile.Result{Requeue: true, RequeueAfter: resourceQuotaRequeueDelay}, nil
+	}
+	if err := r.Client.Status().Update(context.TODO(), jenkins); err != nil {
+		return reconcile.Result{}, stackerr.WithStack(err)
+	}
+	if r.Configuration.Events != nil {
+		r.Configuration.Events.Emit(jenkins, k8sevent.TypeWarning, resourceQuotaExceededEventReason, message)
+	}
+	*r.Notifications <- event.Event{
+		Jenkins: *jenkins,
+		Phase:   event.PhaseBase,
+		Level:   v1alpha2.NotificationLevelWarning,
+		Reason:  reason.NewPodStartingFailed(reason.KubernetesSource, []string{message}),
+	}
+	return reconcile.Result{Requeue: true, RequeueAfter: resourceQuotaRequeueDelay}, nil
+}
+
+// getResourceQuotaShortages returns the resources the pod needs over the free room of the ResourceQuotas, e.g.
+// "needs 2Gi of requests.memory, ResourceQuota 'compute' has 512Mi free"
+func getResourceQuotaShortages(pod corev1.Pod, quotas []corev1.ResourceQuota) []string {
+	usage := resources.GetPodQuotaUsage(pod)
+	var shortages []string
+	for _, quota := range quotas {
+		if !resourceQuotaMatchesPod(quota, pod) {
+			continue
+		}
+		var names []string
+		for name := range quota.Status.Hard {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			needed, found := usage[corev1.ResourceName(name)]
+			if !found {
+				continue
+			}
+			free := quota.Status.Hard[corev1.ResourceName(name)].DeepCopy()
+			free.Sub(quota.Status.Used[corev1.ResourceName(name)])
+			if free.Sign() < 0 {
+				free = *resource.NewQuantity(0, free.Format)
+			}
+			if needed.Cmp(free) > 0 {
+				shortages = append(shortages, fmt.Sprintf("needs %s of %s, ResourceQuota '%s' has %s free",
+					needed.String(), name, quota.Name, free.String()))
+			}
+		}
+	}
+	return shortages
+}
+
+// resourceQuotaMatchesPod returns true if the ResourceQuota counts the pod, the quotas with a scope selector
+// (e.g. priority class) aren't checked and left to the quota admission
+func resourceQuotaMatchesPod(quota corev1.ResourceQuota, pod corev1.Pod) bool {
+	if quota.Spec.ScopeSelector != nil {
+		return false
+	}
+	containers := append([]corev1.Container{}, pod.Spec.InitContainers...)
+	bestEffort := true
+	for _, container := range append(containers, pod.Spec.Containers...) {
+		if len(container.Resources.Requests) > 0 || len(container.Resources.Limits) > 0 {
+			bestEffort = false
+		}
+	}
+	for _, scope := range quota.Spec.Scopes {
+		switch scope {
+		case corev1.ResourceQuotaScopeTerminating:
+			if pod.Spec.ActiveDeadlineSeconds == nil {
+				return false
+			}
+		case corev1.ResourceQuotaScopeNotTerminating:
+			if pod.Spec.ActiveDeadlineSeconds != nil {
+				return false
+			}
+		case corev1.ResourceQuotaScopeBestEffort:
+			if !bestEffort {
+				return false
+			}
+		case corev1.ResourceQuotaScopeNotBestEffort:
+			if bestEffort {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEnsureResourceQuotaHeadroom(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins-example", Namespace: "default"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:      resources.JenkinsMasterContainerName,
+			Resources: resources.NewResourceRequirements("1", "2Gi", "1500m", "3Gi"),
+		}}},
+	}
+	newQuota := func(name string, hard, used corev1.ResourceList) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+		}
+	}
+	newReconciler := func(jenkins *v1alpha2.Jenkins, recorder *fakeRecorder, quotas ...*corev1.ResourceQuota) (*JenkinsBaseConfigurationReconciler, chan event.Event) {
+		fakeClientBuilder := fake.NewClientBuilder().WithObjects(jenkins)
+		for _, quota := range quotas {
+			fakeClientBuilder = fakeClientBuilder.WithObjects(quota)
+		}
+		notifications := make(chan event.Event, 1)
+		return New(configuration.Configuration{
+			Client:        fakeClientBuilder.Build(),
+			Jenkins:       jenkins,
+			Notifications: &notifications,
+			Events:        recorder,
+		}, client.JenkinsAPIConnectionSettings{}), notifications
+	}
+
+	t.Run("no quotas", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"}}
+		reconciler, _ := newReconciler(jenkins, &fakeRecorder{})
+
+		result, err := reconciler.ensureResourceQuotaHeadroom(pod)
+
+		require.NoError(t, err)
+		assert.False(t, result.Requeue)
+		assert.Empty(t, jenkins.Status.Conditions)
+	})
+	t.Run("quota exceeded", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"}}
+		recorder := &fakeRecorder{}
+		quota := newQuota("compute",
+			corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("4Gi"), corev1.ResourceRequestsCPU: resource.MustParse("4")},
+			corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("3584Mi"), corev1.ResourceRequestsCPU: resource.MustParse("1")})
+		reconciler, notifications := newReconciler(jenkins, recorder, quota)
+
+		result, err := reconciler.ensureResourceQuotaHeadroom(pod)
+
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+		assert.Equal(t, resourceQuotaRequeueDelay, result.RequeueAfter)
+		message := "Jenkins master pod needs 2Gi of requests.memory, ResourceQuota 'compute' has 512Mi free"
+		condition := meta.FindStatusCondition(jenkins.Status.Conditions, v1alpha2.ConditionResourceQuotaSufficient)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, configuration.ConditionReasonResourceQuotaExceeded, condition.Reason)
+		assert.Equal(t, message, condition.Message)
+		assert.Equal(t, []recordedEvent{{eventType: k8sevent.TypeWarning, reason: resourceQuotaExceededEventReason, message: message}}, recorder.events)
+		assert.Len(t, notifications, 1)
+
+		result, err = reconciler.ensureResourceQuotaHeadroom(pod)
+
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+		assert.Len(t, recorder.events, 1)
+		assert.Len(t, notifications, 1)
+	})
+	t.Run("quota has room", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"}}
+		configuration.SetCondition(jenkins, v1alpha2.ConditionResourceQuotaSufficient, metav1.ConditionFalse,
+			configuration.ConditionReasonResourceQuotaExceeded, "Jenkins master pod needs 2Gi of requests.memory")
+		quota := newQuota("compute",
+			corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("4Gi"), corev1.ResourcePods: resource.MustParse("10")},
+			corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("1Gi"), corev1.ResourcePods: resource.MustParse("2")})
+		reconciler, _ := newReconciler(jenkins, &fakeRecorder{}, quota)
+
+		result, err := reconciler.ensureResourceQuotaHeadroom(pod)
+
+		require.NoError(t, err)
+		assert.False(t, result.Requeue)
+		assert.True(t, meta.IsStatusConditionTrue(jenkins.Status.Conditions, v1alpha2.ConditionResourceQuotaSufficient))
+	})
+}
+
+func TestResourceQuotaMatchesPod(t *testing.T) {
+	pod := corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+		Resources: resources.NewResourceRequirements("1", "2Gi", "1500m", "3Gi"),
+	}}}}
+
+	assert.True(t, resourceQuotaMatchesPod(corev1.ResourceQuota{}, pod))
+	assert.True(t, resourceQuotaMatchesPod(corev1.ResourceQuota{Spec: corev1.ResourceQuotaSpec{
+		Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeNotTerminating, corev1.ResourceQuotaScopeNotBestEffort},
+	}}, pod))
+	assert.False(t, resourceQuotaMatchesPod(corev1.ResourceQuota{Spec: corev1.ResourceQuotaSpec{
+		Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort},
+	}}, pod))
+	assert.False(t, resourceQuotaMatchesPod(corev1.ResourceQuota{Spec: corev1.ResourceQuotaSpec{
+		Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeTerminating},
+	}}, pod))
+	assert.False(t, resourceQuotaMatchesPod(corev1.ResourceQuota{Spec: corev1.ResourceQuotaSpec{
+		ScopeSelector: &corev1.ScopeSelector{},
+	}}, pod))
+}
@@ -1,11 +1,17 @@
 package resources
 
 import (
+	"encoding/base64"
 	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/internal/render"
 	"github.com/maximba/kubernetes-operator/pkg/constants"
 
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -18,6 +24,10 @@ const (
 	configureKubernetesPluginGroovyScriptName   = "5-configure-kubernetes-plugin.groovy"
 	configureViewsGroovyScriptName              = "6-configure-views.groovy"
 	disableJobDslScriptApprovalGroovyScriptName = "7-disable-job-dsl-script-approval.groovy"
+	configureFoldersAndViewsGroovyScriptName    = "8-configure-folders-and-views.groovy"
+	configureBuildRetentionGroovyScriptName     = "9-configure-build-retention.groovy"
+	configureUpdateCenterGroovyScriptName       = "10-configure-update-center.groovy"
+	configureAgentListenerGroovyScriptName      = "11-configure-agent-listener.groovy"
 )
 
 const basicSettingsFmt = `
@@ -107,16 +117,16 @@ import org.csanchez.jenkins.plugins.kubernetes.KubernetesCloud
 
 def jenkins = Jenkins.getInstance()
 
-def kubernetes = Jenkins.instance.clouds.getByName("kubernetes")
+def kubernetes = Jenkins.instance.clouds.getByName("%[1]s")
 def add = false
 if (kubernetes == null) {
     add = true
-	kubernetes = new KubernetesCloud("kubernetes")
+	kubernetes = new KubernetesCloud("%[1]s")
 }
-kubernetes.setServerUrl("https://kubernetes.default.svc.%s:443")
-kubernetes.setNamespace("%s")
-kubernetes.setJenkinsUrl("%s")
-kubernetes.setJenkinsTunnel("%s")
+kubernetes.setServerUrl("https://kubernetes.default.svc.%[2]s:443")
+kubernetes.setNamespace("%[3]s")
+kubernetes.setJenkinsUrl("%[4]s")
+kubernetes.setJenkinsTunnel("%[5]s")
 kubernetes.setRetentionTimeout(15)
 if (add) {
 	jenkins.clouds.add(kubernetes)
@@ -125,26 +135,26 @@ if (add) {
 jenkins.save()
 `
 
-const configureViews = `
+const configureViewsFmt = `
 import hudson.model.ListView
 import jenkins.model.Jenkins
 
 def Jenkins jenkins = Jenkins.getInstance()
 
-def seedViewName = 'seed-jobs'
-def nonSeedViewName = 'non-seed-jobs'
-
-if (jenkins.getView(seedViewName) == null) {
-    def seedView = new ListView(seedViewName)
-    seedView.setIncludeRegex('.*` + constants.SeedJobSuffix + `.*')
-    jenkins.addView(seedView)
+def ensureDefaultView(Jenkins jenkins, String name, String includeRegex) {
+    def view = jenkins.getView(name)
+    if (view == null) {
+        view = new ListView(name)
+        jenkins.addView(view)
+    } else if (!(view instanceof ListView)) {
+        println("View '${name}' already exists and it isn't a list view")
+        return
+    }
+    view.setIncludeRegex(includeRegex)
 }
 
-if (jenkins.getView(nonSeedViewName) == null) {
-    def nonSeedView = new ListView(nonSeedViewName)
-    nonSeedView.setIncludeRegex('((?!seed)(?!jenkins).)*')
-    jenkins.addView(nonSeedView)
-}
+ensureDefaultView(jenkins, new String("%s".decodeBase64(), "UTF-8"), new String("%s".decodeBase64(), "UTF-8"))
+ensureDefaultView(jenkins, new String("%s".decodeBase64(), "UTF-8"), new String("%s".decodeBase64(), "UTF-8"))
 
 jenkins.save()
 `
@@ -159,14 +169,206 @@ GlobalConfiguration.all().get(GlobalJobDslSecurityConfiguration.class).useScript
 GlobalConfiguration.all().get(GlobalJobDslSecurityConfiguration.class).save()
 `
 
+const configureBuildRetentionFmt = `
+import hudson.tasks.LogRotator
+import jenkins.model.GlobalBuildDiscarderConfiguration
+import jenkins.model.SimpleGlobalBuildDiscarderStrategy
+
+def configuration = GlobalBuildDiscarderConfiguration.get()
+def discarders = configuration.getConfiguredBuildDiscarders()
+discarders.removeAll { it instanceof SimpleGlobalBuildDiscarderStrategy }
+// daysToKeep, numToKeep, artifactDaysToKeep, artifactNumToKeep, -1 means no limit
+discarders.add(new SimpleGlobalBuildDiscarderStrategy(new LogRotator(%d, %d, %d, %d)))
+configuration.save()
+`
+
+const configureAgentListenerFmt = `
+import jenkins.model.Jenkins
+
+def jenkins = Jenkins.instance
+// -1 disables the port, 0 means random port
+if (jenkins.getSlaveAgentPort() != %d) {
+    jenkins.setSlaveAgentPort(%d)
+}
+%s
+jenkins.save()
+`
+
+// buildConfigureAgentListenerGroovyScript returns groovy script which sets the inbound TCP agent port and protocols
+func buildConfigureAgentListenerGroovyScript(jenkins *v1alpha2.Jenkins) string {
+	var port int
+	switch jenkins.Spec.Master.AgentListener.PortPolicy {
+	case v1alpha2.DisabledAgentPortPolicy:
+		port = -1
+	case v1alpha2.RandomAgentPortPolicy:
+		port = 0
+	default:
+		port = int(GetJenkinsAgentPort(jenkins))
+	}
+
+	protocols := ""
+	if len(jenkins.Spec.Master.AgentListener.Protocols) > 0 {
+		quoted := make([]string, 0, len(jenkins.Spec.Master.AgentListener.Protocols))
+		for _, protocol := range jenkins.Spec.Master.AgentListener.Protocols {
+			quoted = append(quoted, "'"+groovyStringReplacer.Replace(protocol)+"'")
+		}
+		protocols = fmt.Sprintf("jenkins.setAgentProtocols(new HashSet<String>([%s]))", strings.Join(quoted, ", "))
+	}
+	return fmt.Sprintf(configureAgentListenerFmt, port, port, protocols)
+}
+
+// logRotatorValue converts build retention value to LogRotator one where -1 means no limit
+func logRotatorValue(value int) int {
+	if value <= 0 {
+		return -1
+	}
+	return value
+}
+
+var groovyStringReplacer = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+var configureFoldersAndViewsTemplate = template.Must(template.New(configureFoldersAndViewsGroovyScriptName).Funcs(template.FuncMap{
+	"quote": func(value string) string {
+		return "'" + groovyStringReplacer.Replace(value) + "'"
+	},
+}).Parse(`
+import com.cloudbees.hudson.plugins.folder.Folder
+import hudson.model.ListView
+import jenkins.model.Jenkins
+
+def ensureFolder(Jenkins jenkins, String name, String description) {
+    def folder = jenkins.getItem(name)
+    if (folder == null) {
+        folder = jenkins.createProject(Folder, name)
+    } else if (!(folder instanceof Folder)) {
+        println("Item '${name}' already exists and it isn't a folder")
+        return
+    }
+    if (folder.getDescription() != description) {
+        folder.setDescription(description)
+    }
+}
+
+def ensureView(Jenkins jenkins, String name, String description, String includeRegex) {
+    def view = jenkins.getView(name)
+    if (view == null) {
+        view = new ListView(name)
+        jenkins.addView(view)
+    } else if (!(view instanceof ListView)) {
+        println("View '${name}' already exists and it isn't a list view")
+        return
+    }
+    view.setDescription(description)
+    view.setIncludeRegex(includeRegex)
+}
+
+def jenkins = Jenkins.getInstance()
+{{- range .Folders }}
+ensureFolder(jenkins, {{ quote .Name }}, {{ quote .Description }})
+{{- end }}
+{{- range .Views }}
+ensureView(jenkins, {{ quote .Name }}, {{ quote .Description }}, {{ quote .IncludeRegex }})
+{{- end }}
+jenkins.save()
+`))
+
+func buildConfigureFoldersAndViewsGroovyScript(jobs v1alpha2.Jobs) (string, error) {
+	return render.Render(configureFoldersAndViewsTemplate, jobs)
+}
+
+// Keys of spec.values ConfigMap overriding the defaults of the base configuration
+const (
+	// NumExecutorsValue is the number of executors of Jenkins master
+	NumExecutorsValue = "numExecutors"
+	// KubernetesCloudNameValue is the name of Kubernetes cloud configured by the operator
+	KubernetesCloudNameValue = "kubernetesCloudName"
+	// SeedJobsViewRegexValue is the regex of jobs included in 'seed-jobs' view
+	SeedJobsViewRegexValue = "seedJobsViewRegex"
+	// NonSeedJobsViewRegexValue is the regex of jobs included in 'non-seed-jobs' view
+	NonSeedJobsViewRegexValue = "nonSeedJobsViewRegex"
+)
+
+const (
+	defaultKubernetesCloudName  = "kubernetes"
+	defaultSeedJobsViewName     = "seed-jobs"
+	defaultSeedJobsViewRegex    = ".*" + constants.SeedJobSuffix + ".*"
+	defaultNonSeedJobsViewName  = "non-seed-jobs"
+	defaultNonSeedJobsViewRegex = "((?!seed)(?!jenkins).)*"
+)
+
+// GetNumExecutors returns the number of executors of Jenkins master set in spec.values or the default one
+func GetNumExecutors(values map[string]string) (int, error) {
+	value, found := values[NumExecutorsValue]
+	if !found {
+		return constants.DefaultAmountOfExecutors, nil
+	}
+	numExecutors, err := strconv.Atoi(value)
+	if err != nil || numExecutors < 0 {
+		return 0, errors.Errorf("spec.values key '%s' must be a non-negative number, got '%s'", NumExecutorsValue, value)
+	}
+	return numExecutors, nil
+}
+
+func valueOrDefault(values map[string]string, key, defaultValue string) string {
+	if value, found := values[key]; found && len(value) > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// GetDefaultViews returns the views of seed jobs and non-seed jobs created by the base configuration, settings of
+// spec.jobs.defaultViews take precedence over spec.values and the defaults
+func GetDefaultViews(defaultViews *v1alpha2.DefaultViews, values map[string]string) (seedJobs, nonSeedJobs v1alpha2.DefaultView) {
+	seedJobs = v1alpha2.DefaultView{
+		Name:         defaultSeedJobsViewName,
+		IncludeRegex: valueOrDefault(values, SeedJobsViewRegexValue, defaultSeedJobsViewRegex),
+	}
+	nonSeedJobs = v1alpha2.DefaultView{
+		Name:         defaultNonSeedJobsViewName,
+		IncludeRegex: valueOrDefault(values, NonSeedJobsViewRegexValue, defaultNonSeedJobsViewRegex),
+	}
+	if defaultViews == nil {
+		return seedJobs, nonSeedJobs
+	}
+	return overrideDefaultView(seedJobs, defaultViews.SeedJobs), overrideDefaultView(nonSeedJobs, defaultViews.NonSeedJobs)
+}
+
+func overrideDefaultView(view v1alpha2.DefaultView, override *v1alpha2.DefaultView) v1alpha2.DefaultView {
+	if override == nil {
+		return view
+	}
+	if len(override.Name) > 0 {
+		view.Name = override.Name
+	}
+	if len(override.IncludeRegex) > 0 {
+		view.IncludeRegex = override.IncludeRegex
+	}
+	return view
+}
+
+func buildConfigureViewsGroovyScript(defaultViews *v1alpha2.DefaultViews, values map[string]string) string {
+	seedJobs, nonSeedJobs := GetDefaultViews(defaultViews, values)
+	return fmt.Sprintf(configureViewsFmt,
+		base64.StdEncoding.EncodeToString([]byte(seedJobs.Name)),
+		base64.StdEncoding.EncodeToString([]byte(seedJobs.IncludeRegex)),
+		base64.StdEncoding.EncodeToString([]byte(nonSeedJobs.Name)),
+		base64.StdEncoding.EncodeToString([]byte(nonSeedJobs.IncludeRegex)),
+	)
+}
+
 // GetBaseConfigurationConfigMapName returns name of Kubernetes config map used to base configuration.
 func GetBaseConfigurationConfigMapName(jenkins *v1alpha2.Jenkins) string {
 	return fmt.Sprintf("%s-base-configuration-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
 }
 
-// NewBaseConfigurationConfigMap builds Kubernetes config map used to base configuration.
-func NewBaseConfigurationConfigMap(meta metav1.ObjectMeta, jenkins *v1alpha2.Jenkins, kubernetesClusterDomain string) (*corev1.ConfigMap, error) {
+// NewBaseConfigurationConfigMap builds Kubernetes config map used to base configuration, the values of spec.values
+// ConfigMap override the defaults of the base configuration.
+func NewBaseConfigurationConfigMap(meta metav1.ObjectMeta, jenkins *v1alpha2.Jenkins, kubernetesClusterDomain string, values map[string]string) (*corev1.ConfigMap, error) {
 	meta.Name = GetBaseConfigurationConfigMapName(jenkins)
+	numExecutors, err := GetNumExecutors(values)
+	if err != nil {
+		return nil, err
+	}
 	clusterDomain, err := getClusterDomain(kubernetesClusterDomain)
 	if err != nil {
 		return nil, err
@@ -184,23 +386,49 @@ func NewBaseConfigurationConfigMap(meta metav1.ObjectMeta, jenkins *v1alpha2.Jen
 		suffix = prefix
 	}
 	groovyScriptsMap := map[string]string{
-		basicSettingsGroovyScriptName:           fmt.Sprintf(basicSettingsFmt, constants.DefaultAmountOfExecutors),
+		basicSettingsGroovyScriptName:           fmt.Sprintf(basicSettingsFmt, numExecutors),
 		enableCSRFGroovyScriptName:              enableCSRF,
 		disableUsageStatsGroovyScriptName:       disableUsageStats,
 		disableInsecureFeaturesGroovyScriptName: disableInsecureFeatures,
 		configureKubernetesPluginGroovyScriptName: fmt.Sprintf(configureKubernetesPluginFmt,
+			valueOrDefault(values, KubernetesCloudNameValue, defaultKubernetesCloudName),
 			clusterDomain,
 			jenkins.ObjectMeta.Namespace,
 			fmt.Sprintf("http://%s:%d%s", jenkinsServiceFQDN, jenkins.Spec.Service.Port, suffix),
 			fmt.Sprintf("%s:%d", jenkinsSlavesServiceFQDN, jenkins.Spec.SlaveService.Port),
 		),
-		configureViewsGroovyScriptName:              configureViews,
 		disableJobDslScriptApprovalGroovyScriptName: disableJobDSLScriptApproval,
 	}
 
 	if jenkins.Spec.Master.DisableCSRFProtection {
 		delete(groovyScriptsMap, enableCSRFGroovyScriptName)
 	}
+	if jenkins.Spec.Master.DisableKubernetesCloud || jenkins.Spec.ExternalJenkins != nil {
+		delete(groovyScriptsMap, configureKubernetesPluginGroovyScriptName)
+	}
+	if defaultViews := jenkins.Spec.Jobs.DefaultViews; defaultViews == nil || !defaultViews.Disabled {
+		groovyScriptsMap[configureViewsGroovyScriptName] = buildConfigureViewsGroovyScript(defaultViews, values)
+	}
+	if retention := jenkins.Spec.Master.BuildRetention; retention != nil {
+		groovyScriptsMap[configureBuildRetentionGroovyScriptName] = fmt.Sprintf(configureBuildRetentionFmt,
+			logRotatorValue(retention.DaysToKeep),
+			logRotatorValue(retention.NumToKeep),
+			logRotatorValue(retention.ArtifactDaysToKeep),
+			logRotatorValue(retention.ArtifactNumToKeep),
+		)
+	}
+	if jenkins.Spec.Master.UpdateCenter != nil {
+		groovyScriptsMap[configureUpdateCenterGroovyScriptName] = buildConfigureUpdateCenterGroovyScript(jenkins.Spec.Master.UpdateCenter)
+	}
+	if jenkins.Spec.Master.AgentListener != nil {
+		groovyScriptsMap[configureAgentListenerGroovyScriptName] = buildConfigureAgentListenerGroovyScript(jenkins)
+	}
+	if len(jenkins.Spec.Jobs.Folders) > 0 || len(jenkins.Spec.Jobs.Views) > 0 {
+		groovyScriptsMap[configureFoldersAndViewsGroovyScriptName], err = buildConfigureFoldersAndViewsGroovyScript(jenkins.Spec.Jobs)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return &corev1.ConfigMap{
 		TypeMeta:   buildConfigMapTypeMeta(),
 		ObjectMeta: meta,
@@ -0,0 +1,149 @@
+package resources
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewBaseConfigurationConfigMap(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			Master: v1alpha2.JenkinsMaster{
+				Containers: []v1alpha2.Container{{Name: JenkinsMasterContainerName}},
+			},
+			Service:      v1alpha2.Service{Port: 8080},
+			SlaveService: v1alpha2.Service{Port: 50000},
+		},
+	}
+
+	t.Run("without folders and views", func(t *testing.T) {
+		configMap, err := NewBaseConfigurationConfigMap(metav1.ObjectMeta{}, jenkins, "cluster.local", nil)
+
+		require.NoError(t, err)
+		assert.NotContains(t, configMap.Data, configureFoldersAndViewsGroovyScriptName)
+		assert.NotContains(t, configMap.Data, configureBuildRetentionGroovyScriptName)
+	})
+	t.Run("with disabled Kubernetes cloud", func(t *testing.T) {
+		jenkinsWithoutCloud := jenkins.DeepCopy()
+		jenkinsWithoutCloud.Spec.Master.DisableKubernetesCloud = true
+
+		configMap, err := NewBaseConfigurationConfigMap(metav1.ObjectMeta{}, jenkinsWithoutCloud, "cluster.local", nil)
+
+		require.NoError(t, err)
+		assert.NotContains(t, configMap.Data, configureKubernetesPluginGroovyScriptName)
+		assert.Contains(t, configMap.Data, basicSettingsGroovyScriptName)
+	})
+	t.Run("with external Jenkins", func(t *testing.T) {
+		externalJenkins := jenkins.DeepCopy()
+		externalJenkins.Spec.ExternalJenkins = &v1alpha2.ExternalJenkins{URL: "https://jenkins.example.com/"}
+
+		configMap, err := NewBaseConfigurationConfigMap(metav1.ObjectMeta{}, externalJenkins, "cluster.local", nil)
+
+		require.NoError(t, err)
+		assert.NotContains(t, configMap.Data, configureKubernetesPluginGroovyScriptName)
+		assert.Contains(t, configMap.Data, basicSettingsGroovyScriptName)
+	})
+	t.Run("with build retention", func(t *testing.T) {
+		jenkinsWithRetention := jenkins.DeepCopy()
+		jenkinsWithRetention.Spec.Master.BuildRetention = &v1alpha2.BuildRetention{DaysToKeep: 30, ArtifactNumToKeep: 5}
+
+		configMap, err := NewBaseConfigurationConfigMap(metav1.ObjectMeta{}, jenkinsWithRetention, "cluster.local", nil)
+
+		require.NoError(t, err)
+		assert.Contains(t, configMap.Data[configureBuildRetentionGroovyScriptName], "new LogRotator(30, -1, -1, 5)")
+	})
+	t.Run("with fixed agent port and protocols", func(t *testing.T) {
+		jenkinsWithListener := jenkins.DeepCopy()
+		jenkinsWithListener.Spec.Master.AgentListener = &v1alpha2.AgentListener{Port: 50001, Protocols: []string{"JNLP4-connect", "Ping"}}
+
+		configMap, err := NewBaseConfigurationConfigMap(metav1.ObjectMeta{}, jenkinsWithListener, "cluster.local", nil)
+
+		require.NoError(t, err)
+		script := configMap.Data[configureAgentListenerGroovyScriptName]
+		assert.Contains(t, script, "jenkins.setSlaveAgentPort(50001)")
+		assert.Contains(t, script, "jenkins.setAgentProtocols(new HashSet<String>(['JNLP4-connect', 'Ping']))")
+		assert.Equal(t, int32(50001), GetJenkinsAgentPort(jenkinsWithListener))
+	})
+	t.Run("with disabled agent port", func(t *testing.T) {
+		jenkinsWithListener := jenkins.DeepCopy()
+		jenkinsWithListener.Spec.Master.AgentListener = &v1alpha2.AgentListener{PortPolicy: v1alpha2.DisabledAgentPortPolicy}
+
+		configMap, err := NewBaseConfigurationConfigMap(metav1.ObjectMeta{}, jenkinsWithListener, "cluster.local", nil)
+
+		require.NoError(t, err)
+		script := configMap.Data[configureAgentListenerGroovyScriptName]
+		assert.Contains(t, script, "jenkins.setSlaveAgentPort(-1)")
+		assert.NotContains(t, script, "setAgentProtocols")
+	})
+	t.Run("with folders and views", func(t *testing.T) {
+		jenkinsWithJobs := jenkins.DeepCopy()
+		jenkinsWithJobs.Spec.Jobs = v1alpha2.Jobs{
+			Folders: []v1alpha2.Folder{{Name: "team-a", Description: "Team A's jobs"}},
+			Views:   []v1alpha2.View{{Name: "team-a", IncludeRegex: `team-a\..*`}},
+		}
+
+		configMap, err := NewBaseConfigurationConfigMap(metav1.ObjectMeta{}, jenkinsWithJobs, "cluster.local", nil)
+
+		require.NoError(t, err)
+		script := configMap.Data[configureFoldersAndViewsGroovyScriptName]
+		assert.Contains(t, script, `ensureFolder(jenkins, 'team-a', 'Team A\'s jobs')`)
+		assert.Contains(t, script, `ensureView(jenkins, 'team-a', '', 'team-a\\..*')`)
+	})
+	t.Run("with values", func(t *testing.T) {
+		values := map[string]string{
+			NumExecutorsValue:        "2",
+			KubernetesCloudNameValue: "in-cluster",
+			SeedJobsViewRegexValue:   "seed-.*",
+			"unrelated":              "value",
+		}
+
+		configMap, err := NewBaseConfigurationConfigMap(metav1.ObjectMeta{}, jenkins, "cluster.local", values)
+
+		require.NoError(t, err)
+		assert.Contains(t, configMap.Data[basicSettingsGroovyScriptName], "jenkins.setNumExecutors(2)")
+		assert.Contains(t, configMap.Data[configureKubernetesPluginGroovyScriptName], `new KubernetesCloud("in-cluster")`)
+		assert.Contains(t, configMap.Data[configureKubernetesPluginGroovyScriptName], `kubernetes.setNamespace("default")`)
+		assert.Contains(t, configMap.Data[configureViewsGroovyScriptName], base64.StdEncoding.EncodeToString([]byte("seed-.*")))
+		assert.Contains(t, configMap.Data[configureViewsGroovyScriptName], base64.StdEncoding.EncodeToString([]byte(defaultNonSeedJobsViewRegex)))
+	})
+	t.Run("with default views", func(t *testing.T) {
+		jenkinsWithViews := jenkins.DeepCopy()
+		jenkinsWithViews.Spec.Jobs.DefaultViews = &v1alpha2.DefaultViews{
+			SeedJobs:    &v1alpha2.DefaultView{Name: "seeds"},
+			NonSeedJobs: &v1alpha2.DefaultView{IncludeRegex: "team-.*"},
+		}
+		values := map[string]string{SeedJobsViewRegexValue: "seed-.*", NonSeedJobsViewRegexValue: "other-.*"}
+
+		configMap, err := NewBaseConfigurationConfigMap(metav1.ObjectMeta{}, jenkinsWithViews, "cluster.local", values)
+
+		require.NoError(t, err)
+		script := configMap.Data[configureViewsGroovyScriptName]
+		encode := func(value string) string {
+			return base64.StdEncoding.EncodeToString([]byte(value))
+		}
+		assert.Contains(t, script, fmt.Sprintf(`ensureDefaultView(jenkins, new String("%s".decodeBase64(), "UTF-8"), new String("%s".decodeBase64(), "UTF-8"))`, encode("seeds"), encode("seed-.*")))
+		assert.Contains(t, script, fmt.Sprintf(`ensureDefaultView(jenkins, new String("%s".decodeBase64(), "UTF-8"), new String("%s".decodeBase64(), "UTF-8"))`, encode(defaultNonSeedJobsViewName), encode("team-.*")))
+	})
+	t.Run("with disabled default views", func(t *testing.T) {
+		jenkinsWithoutViews := jenkins.DeepCopy()
+		jenkinsWithoutViews.Spec.Jobs.DefaultViews = &v1alpha2.DefaultViews{Disabled: true}
+
+		configMap, err := NewBaseConfigurationConfigMap(metav1.ObjectMeta{}, jenkinsWithoutViews, "cluster.local", nil)
+
+		require.NoError(t, err)
+		assert.NotContains(t, configMap.Data, configureViewsGroovyScriptName)
+	})
+	t.Run("with invalid number of executors", func(t *testing.T) {
+		_, err := NewBaseConfigurationConfigMap(metav1.ObjectMeta{}, jenkins, "cluster.local", map[string]string{NumExecutorsValue: "many"})
+
+		assert.EqualError(t, err, "spec.values key 'numExecutors' must be a non-negative number, got 'many'")
+	})
+}
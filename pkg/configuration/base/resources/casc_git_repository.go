@@ -0,0 +1,51 @@
+package resources
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/constants"
+)
+
+const (
+	// DefaultConfigurationAsCodeGitBranch is the pulled branch when spec.configurationAsCode.gitRepository.branch isn't set
+	DefaultConfigurationAsCodeGitBranch = "master"
+	// DefaultConfigurationAsCodeGitSyncInterval is how often the Git repository is pulled when
+	// spec.configurationAsCode.gitRepository.syncInterval isn't set
+	DefaultConfigurationAsCodeGitSyncInterval = 5 * time.Minute
+)
+
+// GetConfigurationAsCodeGitRepositoryConfigMapName returns name of the ConfigMap with Configuration as Code YAML files
+// pulled from spec.configurationAsCode.gitRepository
+func GetConfigurationAsCodeGitRepositoryConfigMapName(jenkins *v1alpha2.Jenkins) string {
+	return fmt.Sprintf("%s-casc-git-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
+}
+
+// GetConfigurationAsCodeGitBranch returns the pulled branch of the Git repository
+func GetConfigurationAsCodeGitBranch(repository v1alpha2.ConfigurationAsCodeGitRepository) string {
+	if len(repository.Branch) == 0 {
+		return DefaultConfigurationAsCodeGitBranch
+	}
+	return repository.Branch
+}
+
+// GetConfigurationAsCodeGitSyncInterval returns how often the Git repository is pulled
+func GetConfigurationAsCodeGitSyncInterval(repository v1alpha2.ConfigurationAsCodeGitRepository) time.Duration {
+	if repository.SyncInterval == nil {
+		return DefaultConfigurationAsCodeGitSyncInterval
+	}
+	return repository.SyncInterval.Duration
+}
+
+// GetConfigurationAsCodeCustomization returns the Configuration as Code customization applied to Jenkins, the
+// ConfigMap pulled from spec.configurationAsCode.gitRepository is appended to the configurations once it's pulled
+func GetConfigurationAsCodeCustomization(jenkins *v1alpha2.Jenkins) v1alpha2.Customization {
+	customization := *jenkins.Spec.ConfigurationAsCode.Customization.DeepCopy()
+	status := jenkins.Status.ConfigurationAsCodeGitRepository
+	if jenkins.Spec.ConfigurationAsCode.GitRepository != nil && status != nil && len(status.Revision) > 0 {
+		customization.Configurations = append(customization.Configurations,
+			v1alpha2.ConfigMapRef{Name: GetConfigurationAsCodeGitRepositoryConfigMapName(jenkins)})
+	}
+	return customization
+}
@@ -0,0 +1,65 @@
+package resources
+
+import (
+	"fmt"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/constants"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// CrashLogsDirectory is the directory in Jenkins home where JVM writes fatal error files and GC log
+	CrashLogsDirectory = "crash-logs"
+	// CrashLogsPodAnnotation is the annotation of crash logs config map with the name of the crashed pod
+	CrashLogsPodAnnotation = "jenkins.io/crashed-pod"
+
+	defaultCrashLogsTailLines = 500
+)
+
+// GetCrashLogsConfigMapName returns name of Kubernetes config map with crash logs of Jenkins master pod
+func GetCrashLogsConfigMapName(jenkins *v1alpha2.Jenkins) string {
+	return fmt.Sprintf("%s-crash-logs-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
+}
+
+// GetCrashLogsPath returns the crash logs directory in Jenkins master container
+func GetCrashLogsPath(jenkins *v1alpha2.Jenkins) string {
+	return fmt.Sprintf("%s/%s", GetJenkinsHomePath(jenkins), CrashLogsDirectory)
+}
+
+// GetCrashLogsTailLines returns the number of lines collected from each crash log
+func GetCrashLogsTailLines(jenkins *v1alpha2.Jenkins) int64 {
+	if jenkins.Spec.Master.CrashLogs == nil || jenkins.Spec.Master.CrashLogs.TailLines <= 0 {
+		return defaultCrashLogsTailLines
+	}
+	return jenkins.Spec.Master.CrashLogs.TailLines
+}
+
+// getCrashLogsJavaOpts returns Java options writing JVM fatal error files and optionally GC log to the crash logs
+// directory, the files are kept in Jenkins home when the container terminates
+func getCrashLogsJavaOpts(jenkins *v1alpha2.Jenkins) string {
+	crashLogs := jenkins.Spec.Master.CrashLogs
+	if crashLogs == nil {
+		return ""
+	}
+	crashLogsPath := GetCrashLogsPath(jenkins)
+	opts := fmt.Sprintf("-XX:ErrorFile=%s/hs_err_pid%%p.log", crashLogsPath)
+	if crashLogs.GCLogs {
+		opts = fmt.Sprintf("%s -Xloggc:%s/gc.log", opts, crashLogsPath)
+	}
+	return opts
+}
+
+// NewCrashLogsConfigMap builds Kubernetes config map with crash logs of Jenkins master pod
+func NewCrashLogsConfigMap(meta metav1.ObjectMeta, jenkins *v1alpha2.Jenkins, podName string, logs map[string]string) *corev1.ConfigMap {
+	meta.Name = GetCrashLogsConfigMapName(jenkins)
+	meta.Annotations = MergeMaps(meta.Annotations, map[string]string{CrashLogsPodAnnotation: podName})
+
+	return &corev1.ConfigMap{
+		TypeMeta:   buildConfigMapTypeMeta(),
+		ObjectMeta: meta,
+		Data:       logs,
+	}
+}
@@ -0,0 +1,59 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetCrashLogsJavaOpts(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{}
+
+		assert.Equal(t, "", getCrashLogsJavaOpts(jenkins))
+	})
+	t.Run("fatal error file", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{Spec: v1alpha2.JenkinsSpec{Master: v1alpha2.JenkinsMaster{
+			Containers: []v1alpha2.Container{{Name: JenkinsMasterContainerName}},
+			CrashLogs:  &v1alpha2.CrashLogs{},
+		}}}
+
+		assert.Equal(t, "-XX:ErrorFile=/var/lib/jenkins/crash-logs/hs_err_pid%p.log", getCrashLogsJavaOpts(jenkins))
+	})
+	t.Run("fatal error file and GC log", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{Spec: v1alpha2.JenkinsSpec{Master: v1alpha2.JenkinsMaster{
+			Containers: []v1alpha2.Container{{Name: JenkinsMasterContainerName}},
+			CrashLogs:  &v1alpha2.CrashLogs{GCLogs: true},
+		}}}
+
+		assert.Equal(t, "-XX:ErrorFile=/var/lib/jenkins/crash-logs/hs_err_pid%p.log -Xloggc:/var/lib/jenkins/crash-logs/gc.log",
+			getCrashLogsJavaOpts(jenkins))
+	})
+}
+
+func TestGetCrashLogsTailLines(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{Spec: v1alpha2.JenkinsSpec{Master: v1alpha2.JenkinsMaster{
+		Containers: []v1alpha2.Container{{Name: JenkinsMasterContainerName}},
+		CrashLogs:  &v1alpha2.CrashLogs{},
+	}}}
+	assert.Equal(t, int64(defaultCrashLogsTailLines), GetCrashLogsTailLines(jenkins))
+
+	jenkins.Spec.Master.CrashLogs.TailLines = 100
+	assert.Equal(t, int64(100), GetCrashLogsTailLines(jenkins))
+}
+
+func TestNewCrashLogsConfigMap(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+	logs := map[string]string{"jenkins-master.log": "java.lang.OutOfMemoryError: Java heap space"}
+
+	configMap := NewCrashLogsConfigMap(NewResourceObjectMeta(jenkins), jenkins, "jenkins-jenkins", logs)
+
+	require.NotNil(t, configMap)
+	assert.Equal(t, "jenkins-operator-crash-logs-jenkins", configMap.Name)
+	assert.Equal(t, "jenkins-jenkins", configMap.Annotations[CrashLogsPodAnnotation])
+	assert.Equal(t, logs, configMap.Data)
+}
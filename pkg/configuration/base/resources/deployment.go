@@ -14,14 +14,15 @@ import (
 // NewJenkinsMasterPod builds Jenkins Master Kubernetes Pod resource.
 func NewJenkinsDeployment(objectMeta metav1.ObjectMeta, jenkins *v1alpha2.Jenkins) *appsv1.Deployment {
 	serviceAccountName := objectMeta.Name
-	objectMeta.Annotations = jenkins.Spec.Master.Annotations
+	objectMeta.Annotations = MergeMaps(jenkins.Spec.CommonAnnotations, jenkins.Spec.Master.Annotations)
 	objectMeta.Name = GetJenkinsDeploymentName(jenkins)
-	selector := &metav1.LabelSelector{MatchLabels: objectMeta.Labels}
+	selector := &metav1.LabelSelector{MatchLabels: BuildResourceLabels(jenkins)}
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      objectMeta.Name,
-			Namespace: objectMeta.Namespace,
-			Labels:    objectMeta.Labels,
+			Name:        objectMeta.Name,
+			Namespace:   objectMeta.Namespace,
+			Labels:      objectMeta.Labels,
+			Annotations: MergeMaps(jenkins.Spec.CommonAnnotations),
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: pointer.Int32Ptr(1),
@@ -31,6 +32,7 @@ func NewJenkinsDeployment(objectMeta metav1.ObjectMeta, jenkins *v1alpha2.Jenkin
 				Spec: corev1.PodSpec{
 					ServiceAccountName: serviceAccountName,
 					NodeSelector:       jenkins.Spec.Master.NodeSelector,
+					InitContainers:     NewJenkinsMasterInitContainers(jenkins),
 					Containers:         newContainers(jenkins),
 					Volumes:            append(GetJenkinsMasterPodBaseVolumes(jenkins), jenkins.Spec.Master.Volumes...),
 					SecurityContext:    jenkins.Spec.Master.SecurityContext,
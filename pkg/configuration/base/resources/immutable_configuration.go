@@ -0,0 +1,130 @@
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/constants"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DefaultImmutableConfigurationHistoryLimit is the number of kept versions when
+	// spec.immutableConfiguration.historyLimit isn't set
+	DefaultImmutableConfigurationHistoryLimit = 10
+
+	// ScriptsConfigurationVersion is the label value of the scripts ConfigMap versions
+	ScriptsConfigurationVersion = "scripts"
+	// InitConfigurationVersion is the label value of the init configuration ConfigMap versions
+	InitConfigurationVersion = "init-configuration"
+	// BaseConfigurationVersion is the label value of the base configuration ConfigMap versions
+	BaseConfigurationVersion = "base-configuration"
+	// OperatorCredentialsVersion is the label value of the operator credentials Secret versions
+	OperatorCredentialsVersion = "operator-credentials"
+
+	configurationVersionHashLength = 10
+)
+
+// IsImmutableConfiguration returns true if the resources managed by the operator are versioned instead of updated
+func IsImmutableConfiguration(jenkins *v1alpha2.Jenkins) bool {
+	return jenkins.Spec.ImmutableConfiguration != nil
+}
+
+// GetImmutableConfigurationHistoryLimit returns the number of kept versions of every resource
+func GetImmutableConfigurationHistoryLimit(jenkins *v1alpha2.Jenkins) int {
+	if jenkins.Spec.ImmutableConfiguration == nil || jenkins.Spec.ImmutableConfiguration.HistoryLimit < 1 {
+		return DefaultImmutableConfigurationHistoryLimit
+	}
+	return jenkins.Spec.ImmutableConfiguration.HistoryLimit
+}
+
+// BuildConfigurationVersionLabels returns labels of all versions of the given kind of resource
+func BuildConfigurationVersionLabels(jenkins *v1alpha2.Jenkins, version string) map[string]string {
+	return MergeMaps(BuildResourceLabels(jenkins), map[string]string{constants.LabelConfigurationVersionKey: version})
+}
+
+// NewConfigMapVersion returns the immutable copy of the ConfigMap, the name is suffixed with the hash of the data
+// so the same content is always stored in the same version
+func NewConfigMapVersion(jenkins *v1alpha2.Jenkins, configMap *corev1.ConfigMap, version string) *corev1.ConfigMap {
+	data := map[string][]byte{}
+	for key, value := range configMap.Data {
+		data[key] = []byte(value)
+	}
+	immutable := true
+	versioned := configMap.DeepCopy()
+	versioned.Name = getConfigurationVersionName(configMap.Name, data)
+	versioned.Labels = MergeMaps(configMap.Labels, BuildConfigurationVersionLabels(jenkins, version))
+	versioned.Immutable = &immutable
+	return versioned
+}
+
+// NewSecretVersion returns the immutable copy of the Secret, the name is suffixed with the hash of the data
+// so the same content is always stored in the same version
+func NewSecretVersion(jenkins *v1alpha2.Jenkins, secret *corev1.Secret, version string) *corev1.Secret {
+	immutable := true
+	versioned := secret.DeepCopy()
+	versioned.Name = getConfigurationVersionName(secret.Name, secret.Data)
+	versioned.Labels = MergeMaps(secret.Labels, BuildConfigurationVersionLabels(jenkins, version))
+	versioned.Immutable = &immutable
+	return versioned
+}
+
+func getConfigurationVersionName(name string, data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write([]byte{0})
+		hash.Write(data[key])
+		hash.Write([]byte{0})
+	}
+	return fmt.Sprintf("%s-%s", name, hex.EncodeToString(hash.Sum(nil))[:configurationVersionHashLength])
+}
+
+// GetCurrentScriptsConfigMapName returns name of the scripts ConfigMap mounted in Jenkins master pod
+func GetCurrentScriptsConfigMapName(jenkins *v1alpha2.Jenkins) string {
+	return getCurrentConfigurationVersion(jenkins, getScriptsConfigMapName(jenkins), func(versions v1alpha2.ConfigurationVersions) string {
+		return versions.ScriptsConfigMap
+	})
+}
+
+// GetCurrentInitConfigurationConfigMapName returns name of the init configuration ConfigMap mounted in Jenkins master pod
+func GetCurrentInitConfigurationConfigMapName(jenkins *v1alpha2.Jenkins) string {
+	return getCurrentConfigurationVersion(jenkins, GetInitConfigurationConfigMapName(jenkins), func(versions v1alpha2.ConfigurationVersions) string {
+		return versions.InitConfigurationConfigMap
+	})
+}
+
+// GetCurrentBaseConfigurationConfigMapName returns name of the base configuration ConfigMap applied to Jenkins
+func GetCurrentBaseConfigurationConfigMapName(jenkins *v1alpha2.Jenkins) string {
+	return getCurrentConfigurationVersion(jenkins, GetBaseConfigurationConfigMapName(jenkins), func(versions v1alpha2.ConfigurationVersions) string {
+		return versions.BaseConfigurationConfigMap
+	})
+}
+
+// GetCurrentOperatorCredentialsSecretName returns name of the operator credentials Secret used by Jenkins master pod
+// and the operator
+func GetCurrentOperatorCredentialsSecretName(jenkins *v1alpha2.Jenkins) string {
+	return getCurrentConfigurationVersion(jenkins, GetOperatorCredentialsSecretName(jenkins), func(versions v1alpha2.ConfigurationVersions) string {
+		return versions.OperatorCredentialsSecret
+	})
+}
+
+func getCurrentConfigurationVersion(jenkins *v1alpha2.Jenkins, name string, version func(v1alpha2.ConfigurationVersions) string) string {
+	if !IsImmutableConfiguration(jenkins) || jenkins.Status.ConfigurationVersions == nil {
+		return name
+	}
+	if versioned := version(*jenkins.Status.ConfigurationVersions); len(versioned) > 0 {
+		return versioned
+	}
+	return name
+}
@@ -12,7 +12,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-const createOperatorUserFileName = "createOperatorUser.groovy"
+const (
+	createOperatorUserFileName = "createOperatorUser.groovy"
+	quietDownFileName          = "quietDown.groovy"
+
+	// QuietDownAnnotation is the annotation of Jenkins CR which Jenkins master is started in the quiet-down mode, no
+	// builds are started by SCM triggers nor cron until the quiet-down mode is cancelled in Jenkins
+	QuietDownAnnotation = "jenkins.io/quiet-down"
+)
 
 var createOperatorUserGroovyFmtTemplate = template.Must(template.New(createOperatorUserFileName).Parse(`
 import hudson.security.*
@@ -50,7 +57,7 @@ func buildCreateJenkinsOperatorUserGroovyScript(jenkins *v1alpha2.Jenkins) (*str
 		OperatorCredentialsPath:     jenkinsOperatorCredentialsVolumePath,
 		OperatorUserNameFile:        OperatorCredentialsSecretUserNameKey,
 		OperatorPasswordFile:        OperatorCredentialsSecretPasswordKey,
-		OperatorUserCreatedFilePath: getJenkinsHomePath(jenkins) + "/operatorUserCreated",
+		OperatorUserCreatedFilePath: GetJenkinsHomePath(jenkins) + "/operatorUserCreated",
 	}
 
 	output, err := render.Render(createOperatorUserGroovyFmtTemplate, data)
@@ -61,6 +68,32 @@ func buildCreateJenkinsOperatorUserGroovyScript(jenkins *v1alpha2.Jenkins) (*str
 	return &output, nil
 }
 
+var quietDownGroovyFmtTemplate = template.Must(template.New(quietDownFileName).Parse(`
+{{- if .Enable }}
+jenkins.model.Jenkins.getInstance().doQuietDown()
+{{- end }}
+`))
+
+func buildQuietDownGroovyScript(jenkins *v1alpha2.Jenkins) (*string, error) {
+	data := struct {
+		Enable bool
+	}{
+		Enable: IsQuietDown(jenkins),
+	}
+
+	output, err := render.Render(quietDownGroovyFmtTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+// IsQuietDown returns true if Jenkins master of the Jenkins CR is started in the quiet-down mode
+func IsQuietDown(jenkins *v1alpha2.Jenkins) bool {
+	return jenkins.Annotations[QuietDownAnnotation] == "true"
+}
+
 // GetInitConfigurationConfigMapName returns name of Kubernetes config map used to init configuration
 func GetInitConfigurationConfigMapName(jenkins *v1alpha2.Jenkins) string {
 	return fmt.Sprintf("%s-init-configuration-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
@@ -74,12 +107,17 @@ func NewInitConfigurationConfigMap(meta metav1.ObjectMeta, jenkins *v1alpha2.Jen
 	if err != nil {
 		return nil, err
 	}
+	quietDownGroovy, err := buildQuietDownGroovyScript(jenkins)
+	if err != nil {
+		return nil, err
+	}
 
 	return &corev1.ConfigMap{
 		TypeMeta:   buildConfigMapTypeMeta(),
 		ObjectMeta: meta,
 		Data: map[string]string{
 			createOperatorUserFileName: *createJenkinsOperatorUserGroovy,
+			quietDownFileName:          *quietDownGroovy,
 		},
 	}, nil
 }
@@ -0,0 +1,34 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewInitConfigurationConfigMap(t *testing.T) {
+	spec := v1alpha2.JenkinsSpec{Master: v1alpha2.JenkinsMaster{Containers: []v1alpha2.Container{{Name: JenkinsMasterContainerName}}}}
+	t.Run("quiet-down mode is disabled by default", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins"}, Spec: spec}
+
+		configMap, err := NewInitConfigurationConfigMap(metav1.ObjectMeta{}, jenkins)
+
+		require.NoError(t, err)
+		assert.NotContains(t, configMap.Data[quietDownFileName], "doQuietDown")
+	})
+	t.Run("quiet-down mode", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{
+			Name:        "jenkins",
+			Annotations: map[string]string{QuietDownAnnotation: "true"},
+		}, Spec: spec}
+
+		configMap, err := NewInitConfigurationConfigMap(metav1.ObjectMeta{}, jenkins)
+
+		require.NoError(t, err)
+		assert.Contains(t, configMap.Data[quietDownFileName], "jenkins.model.Jenkins.getInstance().doQuietDown()")
+	})
+}
@@ -12,9 +12,10 @@ import (
 // NewResourceObjectMeta builds ObjectMeta for all Kubernetes resources created by operator
 func NewResourceObjectMeta(jenkins *v1alpha2.Jenkins) metav1.ObjectMeta {
 	return metav1.ObjectMeta{
-		Name:      GetResourceName(jenkins),
-		Namespace: jenkins.ObjectMeta.Namespace,
-		Labels:    BuildResourceLabels(jenkins),
+		Name:        GetResourceName(jenkins),
+		Namespace:   jenkins.ObjectMeta.Namespace,
+		Labels:      MergeMaps(jenkins.Spec.CommonLabels, BuildResourceLabels(jenkins)),
+		Annotations: MergeMaps(jenkins.Spec.CommonAnnotations),
 	}
 }
 
@@ -42,6 +43,20 @@ func GetResourceName(jenkins *v1alpha2.Jenkins) string {
 	return fmt.Sprintf("%s-%s", constants.LabelAppValue, jenkins.ObjectMeta.Name)
 }
 
+// MergeMaps returns a new map with entries from all given maps, values from later maps override earlier ones
+func MergeMaps(maps ...map[string]string) map[string]string {
+	var merged map[string]string
+	for _, m := range maps {
+		for key, value := range m {
+			if merged == nil {
+				merged = map[string]string{}
+			}
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
 // VerifyIfLabelsAreSet check is selected labels are set for specific resource
 func VerifyIfLabelsAreSet(object metav1.Object, requiredLabels map[string]string) bool {
 	for key, value := range requiredLabels {
@@ -0,0 +1,40 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/constants"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewResourceObjectMeta(t *testing.T) {
+	t.Run("without common labels and annotations", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"}}
+
+		meta := NewResourceObjectMeta(jenkins)
+
+		assert.Equal(t, "jenkins-operator-example", meta.Name)
+		assert.Equal(t, "default", meta.Namespace)
+		assert.Equal(t, BuildResourceLabels(jenkins), meta.Labels)
+		assert.Nil(t, meta.Annotations)
+	})
+	t.Run("with common labels and annotations", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			Spec: v1alpha2.JenkinsSpec{
+				CommonLabels:      map[string]string{"team": "ci", constants.LabelAppKey: "overridden"},
+				CommonAnnotations: map[string]string{"cost-center": "42"},
+			},
+		}
+
+		meta := NewResourceObjectMeta(jenkins)
+
+		assert.Equal(t, "ci", meta.Labels["team"])
+		assert.Equal(t, constants.LabelAppValue, meta.Labels[constants.LabelAppKey])
+		assert.Equal(t, "example", meta.Labels[constants.LabelJenkinsCRKey])
+		assert.Equal(t, map[string]string{"cost-center": "42"}, meta.Annotations)
+	})
+}
@@ -0,0 +1,90 @@
+package resources
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/constants"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// JenkinsHomeActive is the value of jenkins-home label of the claim Jenkins home has been migrated to
+	JenkinsHomeActive = "active"
+	// JenkinsHomeRetired is the value of jenkins-home label of the claim Jenkins home has been migrated from
+	JenkinsHomeRetired = "retired"
+	// JenkinsHomeRetiredAtAnnotation is the time Jenkins home has been migrated from the retired claim
+	JenkinsHomeRetiredAtAnnotation = "jenkins.io/jenkins-home-retired-at"
+
+	// DefaultJenkinsHomePreviousClaimRetention is how long the retired claim is kept when
+	// spec.master.jenkinsHomePersistentVolumeClaim.previousClaimRetention isn't set
+	DefaultJenkinsHomePreviousClaimRetention = 24 * time.Hour
+)
+
+// GetJenkinsHomePersistentVolumeClaimName returns name of Jenkins home PersistentVolumeClaim, it's the claim created
+// by the latest migration to another storage class if Jenkins home has been migrated
+func GetJenkinsHomePersistentVolumeClaimName(jenkins *v1alpha2.Jenkins) string {
+	if len(jenkins.Status.JenkinsHomePersistentVolumeClaimName) > 0 {
+		return jenkins.Status.JenkinsHomePersistentVolumeClaimName
+	}
+	return fmt.Sprintf("%s-home-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
+}
+
+// GetJenkinsHomeMigrationPersistentVolumeClaimName returns name of Jenkins home PersistentVolumeClaim of the storage
+// class Jenkins home is migrated to
+func GetJenkinsHomeMigrationPersistentVolumeClaimName(jenkins *v1alpha2.Jenkins, storageClassName string) string {
+	return fmt.Sprintf("%s-home-%s-%s", constants.OperatorName, jenkins.ObjectMeta.Name, storageClassName)
+}
+
+// BuildJenkinsHomeLabels returns labels of Jenkins home PersistentVolumeClaims in the given state of the migration
+func BuildJenkinsHomeLabels(jenkins *v1alpha2.Jenkins, state string) map[string]string {
+	return MergeMaps(BuildResourceLabels(jenkins), map[string]string{constants.LabelJenkinsHomeKey: state})
+}
+
+// GetJenkinsHomePreviousClaimRetention returns how long the claim Jenkins home has been migrated from is kept, zero
+// means the claim is kept until it's deleted manually
+func GetJenkinsHomePreviousClaimRetention(jenkins *v1alpha2.Jenkins) time.Duration {
+	claim := jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim
+	if claim == nil || claim.PreviousClaimRetention == nil {
+		return DefaultJenkinsHomePreviousClaimRetention
+	}
+	return claim.PreviousClaimRetention.Duration
+}
+
+// NewJenkinsHomePersistentVolumeClaim builds PersistentVolumeClaim used as Jenkins home
+func NewJenkinsHomePersistentVolumeClaim(meta metav1.ObjectMeta, jenkins *v1alpha2.Jenkins) *corev1.PersistentVolumeClaim {
+	meta.Name = GetJenkinsHomePersistentVolumeClaimName(jenkins)
+	claim := jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim
+	return &corev1.PersistentVolumeClaim{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PersistentVolumeClaim",
+			APIVersion: "v1",
+		},
+		ObjectMeta: meta,
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: claim.StorageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: claim.Size,
+				},
+			},
+		},
+	}
+}
+
+func newJenkinsHomeVolumeSource(jenkins *v1alpha2.Jenkins) corev1.VolumeSource {
+	if jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim == nil {
+		return corev1.VolumeSource{
+			EmptyDir: newEmptyDirVolumeSource(jenkins),
+		}
+	}
+	return corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: GetJenkinsHomePersistentVolumeClaimName(jenkins),
+		},
+	}
+}
@@ -33,9 +33,17 @@ const (
 	// GroovyScriptsSecretVolumePath is a path where are groovy scripts used to configure Jenkins
 	// This script is provided by user
 	GroovyScriptsSecretVolumePath = jenkinsPath + "/groovy-scripts-secrets"
+	// BaseGroovyScriptsSecretVolumePath is a path where is the secret of groovy scripts applied in the base
+	// configuration phase, the scripts are provided by user
+	BaseGroovyScriptsSecretVolumePath = jenkinsPath + "/base-groovy-scripts-secrets"
 	// ConfigurationAsCodeSecretVolumePath is a path where are CasC configs used to configure Jenkins
 	// This script is provided by user
 	ConfigurationAsCodeSecretVolumePath = jenkinsPath + "/configuration-as-code-secrets"
+	// ConfigurationAsCodeConfigurationsVolumePath is a path where are CasC ConfigMaps projected into a single
+	// directory when they are hot reloaded
+	ConfigurationAsCodeConfigurationsVolumePath = jenkinsPath + "/configuration-as-code"
+
+	configurationAsCodeConfigurationsVolumeName = "casc-configurations"
 
 	httpPortName  = "http"
 	slavePortName = "slavelistener"
@@ -63,7 +71,7 @@ func GetJenkinsMasterContainerBaseEnvs(jenkins *v1alpha2.Jenkins) []corev1.EnvVa
 	envVars := []corev1.EnvVar{
 		{
 			Name:  "COPY_REFERENCE_FILE_LOG",
-			Value: fmt.Sprintf("%s/%s", getJenkinsHomePath(jenkins), "copy_reference_file.log"),
+			Value: fmt.Sprintf("%s/%s", GetJenkinsHomePath(jenkins), "copy_reference_file.log"),
 		},
 	}
 
@@ -73,12 +81,25 @@ func GetJenkinsMasterContainerBaseEnvs(jenkins *v1alpha2.Jenkins) []corev1.EnvVa
 			Value: ConfigurationAsCodeSecretVolumePath,
 		})
 	}
+	if IsConfigurationAsCodeHotReload(jenkins) {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "CASC_JENKINS_CONFIG",
+			Value: ConfigurationAsCodeConfigurationsVolumePath,
+		})
+	}
 
 	return envVars
 }
 
-// getJenkinsHomePath fetches the Home Path for Jenkins
-func getJenkinsHomePath(jenkins *v1alpha2.Jenkins) string {
+// IsConfigurationAsCodeHotReload returns true if the Configuration as Code ConfigMaps are mounted into Jenkins master
+// pod and reloaded by the Configuration as Code reload API
+func IsConfigurationAsCodeHotReload(jenkins *v1alpha2.Jenkins) bool {
+	return jenkins.Spec.ConfigurationAsCode.ReloadStrategy == v1alpha2.HotReloadReloadStrategy &&
+		(len(jenkins.Spec.ConfigurationAsCode.Configurations) > 0 || jenkins.Spec.ConfigurationAsCode.GitRepository != nil)
+}
+
+// GetJenkinsHomePath fetches the Home Path for Jenkins
+func GetJenkinsHomePath(jenkins *v1alpha2.Jenkins) string {
 	defaultJenkinsHomePath := "/var/lib/jenkins"
 	for _, envVar := range jenkins.Spec.Master.Containers[0].Env {
 		if envVar.Name == "JENKINS_HOME" {
@@ -95,10 +116,8 @@ func GetJenkinsMasterPodBaseVolumes(jenkins *v1alpha2.Jenkins) []corev1.Volume {
 	var scriptsVolumeDefaultMode int32 = 0777
 	volumes := []corev1.Volume{
 		{
-			Name: JenkinsHomeVolumeName,
-			VolumeSource: corev1.VolumeSource{
-				EmptyDir: &corev1.EmptyDirVolumeSource{},
-			},
+			Name:         JenkinsHomeVolumeName,
+			VolumeSource: newJenkinsHomeVolumeSource(jenkins),
 		},
 		{
 			Name: jenkinsScriptsVolumeName,
@@ -106,7 +125,7 @@ func GetJenkinsMasterPodBaseVolumes(jenkins *v1alpha2.Jenkins) []corev1.Volume {
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					DefaultMode: &scriptsVolumeDefaultMode,
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: getScriptsConfigMapName(jenkins),
+						Name: GetCurrentScriptsConfigMapName(jenkins),
 					},
 				},
 			},
@@ -117,7 +136,7 @@ func GetJenkinsMasterPodBaseVolumes(jenkins *v1alpha2.Jenkins) []corev1.Volume {
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					DefaultMode: &configMapVolumeSourceDefaultMode,
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: GetInitConfigurationConfigMapName(jenkins),
+						Name: GetCurrentInitConfigurationConfigMapName(jenkins),
 					},
 				},
 			},
@@ -127,12 +146,23 @@ func GetJenkinsMasterPodBaseVolumes(jenkins *v1alpha2.Jenkins) []corev1.Volume {
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
 					DefaultMode: &secretVolumeSourceDefaultMode,
-					SecretName:  GetOperatorCredentialsSecretName(jenkins),
+					SecretName:  GetCurrentOperatorCredentialsSecretName(jenkins),
 				},
 			},
 		},
 	}
 
+	if len(jenkins.Spec.BaseGroovyScripts.Secret.Name) > 0 {
+		volumes = append(volumes, corev1.Volume{
+			Name: getBaseGroovyScriptsSecretVolumeName(jenkins),
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					DefaultMode: &secretVolumeSourceDefaultMode,
+					SecretName:  jenkins.Spec.BaseGroovyScripts.Secret.Name,
+				},
+			},
+		})
+	}
 	if len(jenkins.Spec.GroovyScripts.Secret.Name) > 0 {
 		volumes = append(volumes, corev1.Volume{
 			Name: getGroovyScriptsSecretVolumeName(jenkins),
@@ -155,10 +185,53 @@ func GetJenkinsMasterPodBaseVolumes(jenkins *v1alpha2.Jenkins) []corev1.Volume {
 			},
 		})
 	}
+	if IsConfigurationAsCodeHotReload(jenkins) {
+		var sources []corev1.VolumeProjection
+		for _, configMapRef := range jenkins.Spec.ConfigurationAsCode.Configurations {
+			sources = append(sources, corev1.VolumeProjection{
+				ConfigMap: &corev1.ConfigMapProjection{
+					LocalObjectReference: corev1.LocalObjectReference{Name: configMapRef.Name},
+				},
+			})
+		}
+		if jenkins.Spec.ConfigurationAsCode.GitRepository != nil {
+			// the ConfigMap is created by the operator after the first pull from Jenkins master pod
+			optional := true
+			sources = append(sources, corev1.VolumeProjection{
+				ConfigMap: &corev1.ConfigMapProjection{
+					LocalObjectReference: corev1.LocalObjectReference{Name: GetConfigurationAsCodeGitRepositoryConfigMapName(jenkins)},
+					Optional:             &optional,
+				},
+			})
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: configurationAsCodeConfigurationsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					DefaultMode: &configMapVolumeSourceDefaultMode,
+					Sources:     sources,
+				},
+			},
+		})
+	}
+	volumes = append(volumes, getTruststoreVolumes(jenkins)...)
+	volumes = append(volumes, getKeystoreVolumes(jenkins)...)
 
 	return volumes
 }
 
+// newEmptyDirVolumeSource returns emptyDir volume source for volumes created by the operator
+func newEmptyDirVolumeSource(jenkins *v1alpha2.Jenkins) *corev1.EmptyDirVolumeSource {
+	if jenkins.Spec.Master.EmptyDir == nil {
+		return &corev1.EmptyDirVolumeSource{}
+	}
+	return jenkins.Spec.Master.EmptyDir.DeepCopy()
+}
+
+func getBaseGroovyScriptsSecretVolumeName(jenkins *v1alpha2.Jenkins) string {
+	return "bgs-" + jenkins.Spec.BaseGroovyScripts.Secret.Name
+}
+
 func getGroovyScriptsSecretVolumeName(jenkins *v1alpha2.Jenkins) string {
 	return "gs-" + jenkins.Spec.GroovyScripts.Secret.Name
 }
@@ -172,7 +245,7 @@ func GetJenkinsMasterContainerBaseVolumeMounts(jenkins *v1alpha2.Jenkins) []core
 	volumeMounts := []corev1.VolumeMount{
 		{
 			Name:      JenkinsHomeVolumeName,
-			MountPath: getJenkinsHomePath(jenkins),
+			MountPath: GetJenkinsHomePath(jenkins),
 			ReadOnly:  false,
 		},
 		{
@@ -192,6 +265,13 @@ func GetJenkinsMasterContainerBaseVolumeMounts(jenkins *v1alpha2.Jenkins) []core
 		},
 	}
 
+	if len(jenkins.Spec.BaseGroovyScripts.Secret.Name) > 0 {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      getBaseGroovyScriptsSecretVolumeName(jenkins),
+			MountPath: BaseGroovyScriptsSecretVolumePath,
+			ReadOnly:  true,
+		})
+	}
 	if len(jenkins.Spec.GroovyScripts.Secret.Name) > 0 {
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      getGroovyScriptsSecretVolumeName(jenkins),
@@ -206,6 +286,19 @@ func GetJenkinsMasterContainerBaseVolumeMounts(jenkins *v1alpha2.Jenkins) []core
 			ReadOnly:  true,
 		})
 	}
+	if IsConfigurationAsCodeHotReload(jenkins) {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      configurationAsCodeConfigurationsVolumeName,
+			MountPath: ConfigurationAsCodeConfigurationsVolumePath,
+			ReadOnly:  true,
+		})
+	}
+	if len(jenkins.Spec.Master.TrustedCertificates) > 0 {
+		volumeMounts = append(volumeMounts, getTruststoreVolumeMount())
+	}
+	if isUpdateCenterClientCertificateSet(jenkins) {
+		volumeMounts = append(volumeMounts, getKeystoreVolumeMount())
+	}
 
 	return volumeMounts
 }
@@ -219,7 +312,7 @@ func NewJenkinsMasterContainer(jenkins *v1alpha2.Jenkins) corev1.Container {
 
 	jenkinsHomeEnvVar := corev1.EnvVar{
 		Name:  "JENKINS_HOME",
-		Value: getJenkinsHomePath(jenkins),
+		Value: GetJenkinsHomePath(jenkins),
 	}
 
 	jenkinsHomeEnvVarExists := false
@@ -234,6 +327,23 @@ func NewJenkinsMasterContainer(jenkins *v1alpha2.Jenkins) corev1.Container {
 		envs = append(envs, jenkinsHomeEnvVar)
 	}
 
+	if len(jenkins.Spec.Master.TrustedCertificates) > 0 {
+		envs = appendJavaOpts(envs, GetTruststoreJavaOpts())
+	}
+	if updateCenter := jenkins.Spec.Master.UpdateCenter; updateCenter != nil {
+		envs = append(envs, corev1.EnvVar{Name: updateCenterEnvName, Value: updateCenter.URL})
+		if updateCenter.ClientCertificate != nil {
+			envs = appendJavaOpts(envs, GetKeystoreJavaOpts())
+		}
+	}
+
+	if heapOpts := GetHeapSizingJavaOpts(jenkins); len(heapOpts) > 0 {
+		envs = appendJavaOpts(envs, heapOpts)
+	}
+	if crashLogsOpts := getCrashLogsJavaOpts(jenkins); len(crashLogsOpts) > 0 {
+		envs = appendJavaOpts(envs, crashLogsOpts)
+	}
+
 	if jenkins.Spec.Master.Containers[0].ReadinessProbe.HTTPGet != nil {
 		setLivenessAndReadinessPath(jenkins)
 	}
@@ -245,6 +355,7 @@ func NewJenkinsMasterContainer(jenkins *v1alpha2.Jenkins) corev1.Container {
 		Command:         jenkinsContainer.Command,
 		LivenessProbe:   jenkinsContainer.LivenessProbe,
 		ReadinessProbe:  jenkinsContainer.ReadinessProbe,
+		Lifecycle:       jenkinsContainer.Lifecycle,
 		Ports: []corev1.ContainerPort{
 			{
 				Name:          httpPortName,
@@ -253,7 +364,7 @@ func NewJenkinsMasterContainer(jenkins *v1alpha2.Jenkins) corev1.Container {
 			},
 			{
 				Name:          slavePortName,
-				ContainerPort: constants.DefaultSlavePortInt32,
+				ContainerPort: GetJenkinsAgentPort(jenkins),
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
@@ -269,6 +380,13 @@ func setLivenessAndReadinessPath(jenkins *v1alpha2.Jenkins) {
 	ReadinessProbePath := jenkins.Spec.Master.Containers[0].ReadinessProbe.HTTPGet.Path
 	LivenessProbePath := jenkins.Spec.Master.Containers[0].ReadinessProbe.HTTPGet.Path
 
+	if healthCheckPath := jenkins.Spec.Master.HealthCheckPath; len(healthCheckPath) > 0 {
+		healthCheckPath = GetJenkinsOpts(*jenkins)["prefix"] + healthCheckPath
+		jenkins.Spec.Master.Containers[0].ReadinessProbe.HTTPGet.Path = healthCheckPath
+		jenkins.Spec.Master.Containers[0].LivenessProbe.HTTPGet.Path = healthCheckPath
+		return
+	}
+
 	if prefix, ok := GetJenkinsOpts(*jenkins)["prefix"]; ok {
 		if !strings.HasPrefix(ReadinessProbePath, prefix) {
 			jenkins.Spec.Master.Containers[0].ReadinessProbe.HTTPGet.Path = prefix + httpGetPath
@@ -343,6 +461,63 @@ func newContainers(jenkins *v1alpha2.Jenkins) (containers []corev1.Container) {
 	return
 }
 
+// NewJenkinsMasterInitContainers returns init containers of Jenkins master pod
+func NewJenkinsMasterInitContainers(jenkins *v1alpha2.Jenkins) (containers []corev1.Container) {
+	if len(jenkins.Spec.Master.TrustedCertificates) > 0 {
+		containers = append(containers, NewTruststoreInitContainer(jenkins))
+	}
+	if isUpdateCenterClientCertificateSet(jenkins) {
+		containers = append(containers, NewKeystoreInitContainer(jenkins))
+	}
+
+	for _, container := range jenkins.Spec.Master.InitContainers {
+		containers = append(containers, ConvertJenkinsContainerToKubernetesContainer(container))
+	}
+
+	return
+}
+
+// appendJavaOpts appends Java options to JAVA_OPTS environment variable, the variable is added when it's not set
+func appendJavaOpts(envs []corev1.EnvVar, opts string) []corev1.EnvVar {
+	for index, env := range envs {
+		if env.Name == constants.JavaOpsVariableName {
+			envs[index].Value = fmt.Sprintf("%s %s", env.Value, opts)
+			return envs
+		}
+	}
+
+	return append(envs, corev1.EnvVar{
+		Name:  constants.JavaOpsVariableName,
+		Value: opts,
+	})
+}
+
+// GetHeapSizingJavaOpts returns -Xmx and -Xms Java options computed from the memory of Jenkins master container
+// as configured in spec.master.heapSizing, it returns an empty string when the heap sizing or the memory is not set
+func GetHeapSizingJavaOpts(jenkins *v1alpha2.Jenkins) string {
+	heapSizing := jenkins.Spec.Master.HeapSizing
+	if heapSizing == nil || len(jenkins.Spec.Master.Containers) == 0 {
+		return ""
+	}
+	resources := jenkins.Spec.Master.Containers[0].Resources
+	memory := resources.Limits.Memory().Value()
+	if memory == 0 {
+		memory = resources.Requests.Memory().Value()
+	}
+	if memory == 0 {
+		return ""
+	}
+
+	initialPercentage := heapSizing.InitialPercentage
+	if initialPercentage == 0 {
+		initialPercentage = heapSizing.MaxPercentage
+	}
+	toMebibytes := func(percentage int32) int64 {
+		return memory * int64(percentage) / 100 / (1024 * 1024)
+	}
+	return fmt.Sprintf("-Xmx%dm -Xms%dm", toMebibytes(heapSizing.MaxPercentage), toMebibytes(initialPercentage))
+}
+
 // GetJenkinsMasterPodName returns Jenkins pod name for given CR
 func GetJenkinsMasterPodName(jenkins *v1alpha2.Jenkins) string {
 	return fmt.Sprintf("jenkins-%s", jenkins.Name)
@@ -350,22 +525,13 @@ func GetJenkinsMasterPodName(jenkins *v1alpha2.Jenkins) string {
 
 // GetJenkinsMasterPodLabels returns Jenkins pod labels for given CR
 func GetJenkinsMasterPodLabels(jenkins v1alpha2.Jenkins) map[string]string {
-	var labels map[string]string
-	if jenkins.Spec.Master.Labels == nil {
-		labels = map[string]string{}
-	} else {
-		labels = jenkins.Spec.Master.Labels
-	}
-	for key, value := range BuildResourceLabels(&jenkins) {
-		labels[key] = value
-	}
-	return labels
+	return MergeMaps(jenkins.Spec.CommonLabels, jenkins.Spec.Master.Labels, BuildResourceLabels(&jenkins))
 }
 
 // NewJenkinsMasterPod builds Jenkins Master Kubernetes Pod resource
 func NewJenkinsMasterPod(objectMeta metav1.ObjectMeta, jenkins *v1alpha2.Jenkins) *corev1.Pod {
 	serviceAccountName := objectMeta.Name
-	objectMeta.Annotations = jenkins.Spec.Master.Annotations
+	objectMeta.Annotations = MergeMaps(jenkins.Spec.CommonAnnotations, jenkins.Spec.Master.Annotations)
 	objectMeta.Name = GetJenkinsMasterPodName(jenkins)
 	objectMeta.Labels = GetJenkinsMasterPodLabels(*jenkins)
 
@@ -376,6 +542,7 @@ func NewJenkinsMasterPod(objectMeta metav1.ObjectMeta, jenkins *v1alpha2.Jenkins
 			ServiceAccountName: serviceAccountName,
 			RestartPolicy:      corev1.RestartPolicyNever,
 			NodeSelector:       jenkins.Spec.Master.NodeSelector,
+			InitContainers:     NewJenkinsMasterInitContainers(jenkins),
 			Containers:         newContainers(jenkins),
 			Volumes:            append(GetJenkinsMasterPodBaseVolumes(jenkins), jenkins.Spec.Master.Volumes...),
 			SecurityContext:    jenkins.Spec.Master.SecurityContext,
@@ -386,3 +553,11 @@ func NewJenkinsMasterPod(objectMeta metav1.ObjectMeta, jenkins *v1alpha2.Jenkins
 		},
 	}
 }
+
+// GetJenkinsAgentPort returns the inbound TCP agent port of Jenkins master container
+func GetJenkinsAgentPort(jenkins *v1alpha2.Jenkins) int32 {
+	if listener := jenkins.Spec.Master.AgentListener; listener != nil && listener.Port > 0 {
+		return listener.Port
+	}
+	return constants.DefaultSlavePortInt32
+}
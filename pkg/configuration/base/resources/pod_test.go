@@ -4,8 +4,12 @@ import (
 	"testing"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/constants"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestGetJenkinsMasterPodBaseVolumes(t *testing.T) {
@@ -156,3 +160,368 @@ func checkSecretVolumesPresence(jenkins *v1alpha2.Jenkins) (groovyExists bool, c
 	}
 	return groovyExists, cascExists
 }
+
+func TestGetJenkinsMasterPodLabels(t *testing.T) {
+	t.Run("merges common, master and resource labels", func(t *testing.T) {
+		masterLabels := map[string]string{"team": "platform"}
+		jenkins := v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example"},
+			Spec: v1alpha2.JenkinsSpec{
+				CommonLabels: map[string]string{"team": "ci", "env": "prod"},
+				Master:       v1alpha2.JenkinsMaster{Labels: masterLabels},
+			},
+		}
+
+		labels := GetJenkinsMasterPodLabels(jenkins)
+
+		assert.Equal(t, "platform", labels["team"])
+		assert.Equal(t, "prod", labels["env"])
+		assert.Equal(t, constants.LabelAppValue, labels[constants.LabelAppKey])
+		assert.Equal(t, map[string]string{"team": "platform"}, masterLabels)
+	})
+}
+
+func TestNewJenkinsMasterPod(t *testing.T) {
+	t.Run("init containers", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					Containers: []v1alpha2.Container{{Name: JenkinsMasterContainerName, ReadinessProbe: &corev1.Probe{}}},
+					InitContainers: []v1alpha2.Container{
+						{Name: "seed-home", Image: "busybox:1.35", Command: []string{"cp", "-r", "/seed/.", "/var/jenkins/home"}},
+					},
+				},
+			},
+		}
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Len(t, pod.Spec.InitContainers, 1)
+		assert.Equal(t, "seed-home", pod.Spec.InitContainers[0].Name)
+		assert.Equal(t, "busybox:1.35", pod.Spec.InitContainers[0].Image)
+		assert.Equal(t, []string{"cp", "-r", "/seed/.", "/var/jenkins/home"}, pod.Spec.InitContainers[0].Command)
+		assert.Len(t, pod.Spec.Containers, 1)
+	})
+	t.Run("trusted certificates", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					Containers: []v1alpha2.Container{{
+						Name:           JenkinsMasterContainerName,
+						Image:          "jenkins/jenkins:lts",
+						ReadinessProbe: &corev1.Probe{},
+						Env:            []corev1.EnvVar{{Name: constants.JavaOpsVariableName, Value: "-Xmx1g"}},
+					}},
+					InitContainers:      []v1alpha2.Container{{Name: "seed-home", Image: "busybox:1.35"}},
+					TrustedCertificates: []v1alpha2.ConfigMapRef{{Name: "internal-ca"}, {Name: "partner-ca"}},
+				},
+			},
+		}
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Len(t, pod.Spec.InitContainers, 2)
+		truststoreContainer := pod.Spec.InitContainers[0]
+		assert.Equal(t, TruststoreInitContainerName, truststoreContainer.Name)
+		assert.Equal(t, "jenkins/jenkins:lts", truststoreContainer.Image)
+		assert.Len(t, truststoreContainer.VolumeMounts, 3)
+		assert.Equal(t, "seed-home", pod.Spec.InitContainers[1].Name)
+
+		var trustedCertificatesConfigMaps []string
+		for _, volume := range pod.Spec.Volumes {
+			if volume.ConfigMap != nil && volume.Name != jenkinsScriptsVolumeName && volume.Name != jenkinsInitConfigurationVolumeName {
+				trustedCertificatesConfigMaps = append(trustedCertificatesConfigMaps, volume.ConfigMap.Name)
+			}
+		}
+		assert.Equal(t, []string{"internal-ca", "partner-ca"}, trustedCertificatesConfigMaps)
+
+		masterContainer := pod.Spec.Containers[0]
+		assert.Contains(t, masterContainer.Env, corev1.EnvVar{Name: constants.JavaOpsVariableName, Value: "-Xmx1g " + GetTruststoreJavaOpts()})
+		assert.Contains(t, masterContainer.VolumeMounts, getTruststoreVolumeMount())
+		assert.Equal(t, "-Xmx1g", jenkins.Spec.Master.Containers[0].Env[0].Value)
+	})
+	t.Run("update center with client certificate", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					Containers: []v1alpha2.Container{{
+						Name:           JenkinsMasterContainerName,
+						Image:          "jenkins/jenkins:lts",
+						ReadinessProbe: &corev1.Probe{},
+					}},
+					UpdateCenter: &v1alpha2.UpdateCenter{
+						URL:               "https://updates.example.com/update-center.json",
+						ClientCertificate: &v1alpha2.SecretRef{Name: "uc-client"},
+					},
+				},
+			},
+		}
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Len(t, pod.Spec.InitContainers, 1)
+		assert.Equal(t, KeystoreInitContainerName, pod.Spec.InitContainers[0].Name)
+		var clientCertificateSecret string
+		for _, volume := range pod.Spec.Volumes {
+			if volume.Name == clientCertificateVolumeName {
+				clientCertificateSecret = volume.Secret.SecretName
+			}
+		}
+		assert.Equal(t, "uc-client", clientCertificateSecret)
+
+		masterContainer := pod.Spec.Containers[0]
+		assert.Contains(t, masterContainer.Env, corev1.EnvVar{Name: updateCenterEnvName, Value: "https://updates.example.com/update-center.json"})
+		assert.Contains(t, masterContainer.Env, corev1.EnvVar{Name: constants.JavaOpsVariableName, Value: GetKeystoreJavaOpts()})
+		assert.Contains(t, masterContainer.VolumeMounts, getKeystoreVolumeMount())
+	})
+	t.Run("lifecycle hooks", func(t *testing.T) {
+		preStop := &corev1.Lifecycle{PreStop: &corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"/drain.sh"}}}}
+		postStart := &corev1.Lifecycle{PostStart: &corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"/warm-up.sh"}}}}
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					Containers: []v1alpha2.Container{
+						{Name: JenkinsMasterContainerName, ReadinessProbe: &corev1.Probe{}, Lifecycle: preStop},
+						{Name: "sidecar", Image: "busybox:1.35", Lifecycle: postStart},
+					},
+				},
+			},
+		}
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, preStop, pod.Spec.Containers[0].Lifecycle)
+		assert.Equal(t, postStart, pod.Spec.Containers[1].Lifecycle)
+	})
+	t.Run("configuration as code hot reload", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					Containers: []v1alpha2.Container{{Name: JenkinsMasterContainerName, ReadinessProbe: &corev1.Probe{}}},
+				},
+				ConfigurationAsCode: v1alpha2.ConfigurationAsCode{
+					Customization: v1alpha2.Customization{
+						Configurations: []v1alpha2.ConfigMapRef{{Name: "casc-base"}, {Name: "casc-team"}},
+					},
+					ReloadStrategy: v1alpha2.HotReloadReloadStrategy,
+				},
+			},
+		}
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins)
+
+		var configMaps []string
+		for _, volume := range pod.Spec.Volumes {
+			if volume.Name == configurationAsCodeConfigurationsVolumeName {
+				for _, source := range volume.Projected.Sources {
+					configMaps = append(configMaps, source.ConfigMap.Name)
+				}
+			}
+		}
+		assert.Equal(t, []string{"casc-base", "casc-team"}, configMaps)
+		masterContainer := pod.Spec.Containers[0]
+		assert.Contains(t, masterContainer.Env, corev1.EnvVar{Name: "CASC_JENKINS_CONFIG", Value: ConfigurationAsCodeConfigurationsVolumePath})
+		assert.Contains(t, masterContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      configurationAsCodeConfigurationsVolumeName,
+			MountPath: ConfigurationAsCodeConfigurationsVolumePath,
+			ReadOnly:  true,
+		})
+
+		jenkins.Spec.ConfigurationAsCode.ReloadStrategy = v1alpha2.RestartReloadStrategy
+		pod = NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.NotContains(t, pod.Spec.Containers[0].Env, corev1.EnvVar{Name: "CASC_JENKINS_CONFIG", Value: ConfigurationAsCodeConfigurationsVolumePath})
+	})
+	t.Run("configuration as code hot reload from git repository", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					Containers: []v1alpha2.Container{{Name: JenkinsMasterContainerName, ReadinessProbe: &corev1.Probe{}}},
+				},
+				ConfigurationAsCode: v1alpha2.ConfigurationAsCode{
+					ReloadStrategy: v1alpha2.HotReloadReloadStrategy,
+					GitRepository:  &v1alpha2.ConfigurationAsCodeGitRepository{URL: "https://github.com/example/jenkins-config.git"},
+				},
+			},
+		}
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins)
+
+		var sources []corev1.VolumeProjection
+		for _, volume := range pod.Spec.Volumes {
+			if volume.Name == configurationAsCodeConfigurationsVolumeName {
+				sources = volume.Projected.Sources
+			}
+		}
+		if assert.Len(t, sources, 1) {
+			assert.Equal(t, "jenkins-operator-casc-git-example", sources[0].ConfigMap.Name)
+			assert.True(t, *sources[0].ConfigMap.Optional)
+		}
+	})
+}
+
+func TestGetJenkinsMasterPodBaseVolumesImmutableConfiguration(t *testing.T) {
+	getVolumes := func(jenkins *v1alpha2.Jenkins) map[string]string {
+		names := map[string]string{}
+		for _, volume := range GetJenkinsMasterPodBaseVolumes(jenkins) {
+			if volume.ConfigMap != nil {
+				names[volume.Name] = volume.ConfigMap.Name
+			} else if volume.Secret != nil {
+				names[volume.Name] = volume.Secret.SecretName
+			}
+		}
+		return names
+	}
+	versions := &v1alpha2.ConfigurationVersions{
+		ScriptsConfigMap:           "jenkins-operator-scripts-example-0123456789",
+		InitConfigurationConfigMap: "jenkins-operator-init-configuration-example-0123456789",
+		OperatorCredentialsSecret:  "jenkins-operator-credentials-example-0123456789",
+	}
+
+	t.Run("immutable mode", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example"},
+			Spec:       v1alpha2.JenkinsSpec{ImmutableConfiguration: &v1alpha2.ImmutableConfiguration{}},
+			Status:     v1alpha2.JenkinsStatus{ConfigurationVersions: versions},
+		}
+
+		volumes := getVolumes(jenkins)
+
+		assert.Equal(t, versions.ScriptsConfigMap, volumes[jenkinsScriptsVolumeName])
+		assert.Equal(t, versions.InitConfigurationConfigMap, volumes[jenkinsInitConfigurationVolumeName])
+		assert.Equal(t, versions.OperatorCredentialsSecret, volumes[jenkinsOperatorCredentialsVolumeName])
+	})
+	t.Run("immutable mode disabled", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example"},
+			Status:     v1alpha2.JenkinsStatus{ConfigurationVersions: versions},
+		}
+
+		volumes := getVolumes(jenkins)
+
+		assert.Equal(t, "jenkins-operator-scripts-example", volumes[jenkinsScriptsVolumeName])
+		assert.Equal(t, "jenkins-operator-init-configuration-example", volumes[jenkinsInitConfigurationVolumeName])
+		assert.Equal(t, "jenkins-operator-credentials-example", volumes[jenkinsOperatorCredentialsVolumeName])
+	})
+}
+
+func TestGetJenkinsMasterPodBaseVolumesEmptyDir(t *testing.T) {
+	t.Run("default emptyDir", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "example"}}
+
+		volumes := GetJenkinsMasterPodBaseVolumes(jenkins)
+
+		assert.Equal(t, JenkinsHomeVolumeName, volumes[0].Name)
+		assert.Equal(t, &corev1.EmptyDirVolumeSource{}, volumes[0].EmptyDir)
+	})
+	t.Run("custom medium and size limit", func(t *testing.T) {
+		sizeLimit := resource.MustParse("4Gi")
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example"},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					EmptyDir:            &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory, SizeLimit: &sizeLimit},
+					TrustedCertificates: []v1alpha2.ConfigMapRef{{Name: "internal-ca"}},
+				},
+			},
+		}
+
+		volumes := GetJenkinsMasterPodBaseVolumes(jenkins)
+
+		for _, volume := range volumes {
+			if volume.Name != JenkinsHomeVolumeName && volume.Name != truststoreVolumeName {
+				continue
+			}
+			assert.Equal(t, corev1.StorageMediumMemory, volume.EmptyDir.Medium)
+			assert.Equal(t, "4Gi", volume.EmptyDir.SizeLimit.String())
+			assert.NotSame(t, jenkins.Spec.Master.EmptyDir, volume.EmptyDir)
+		}
+	})
+	t.Run("persistent volume claim", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example"},
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					JenkinsHomePersistentVolumeClaim: &v1alpha2.JenkinsHomePersistentVolumeClaim{Size: resource.MustParse("10Gi")},
+				},
+			},
+		}
+
+		volumes := GetJenkinsMasterPodBaseVolumes(jenkins)
+
+		assert.Equal(t, JenkinsHomeVolumeName, volumes[0].Name)
+		assert.Nil(t, volumes[0].EmptyDir)
+		assert.Equal(t, &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "jenkins-operator-home-example"}, volumes[0].PersistentVolumeClaim)
+	})
+}
+
+func TestNewJenkinsDeployment(t *testing.T) {
+	t.Run("selector uses only operator labels", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			Spec: v1alpha2.JenkinsSpec{
+				CommonLabels:      map[string]string{"team": "ci"},
+				CommonAnnotations: map[string]string{"cost-center": "42"},
+				Master: v1alpha2.JenkinsMaster{
+					Annotations: map[string]string{"master": "annotation"},
+					Containers:  []v1alpha2.Container{{Name: JenkinsMasterContainerName, ReadinessProbe: &corev1.Probe{}}},
+				},
+			},
+		}
+
+		deployment := NewJenkinsDeployment(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, BuildResourceLabels(jenkins), deployment.Spec.Selector.MatchLabels)
+		assert.Equal(t, "ci", deployment.Labels["team"])
+		assert.Equal(t, map[string]string{"cost-center": "42"}, deployment.Annotations)
+		assert.Equal(t, "ci", deployment.Spec.Template.Labels["team"])
+		assert.Equal(t, map[string]string{"cost-center": "42", "master": "annotation"}, deployment.Spec.Template.Annotations)
+	})
+}
+
+func TestGetHeapSizingJavaOpts(t *testing.T) {
+	jenkinsWithResources := func(heapSizing *v1alpha2.HeapSizing, resources corev1.ResourceRequirements) *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					HeapSizing: heapSizing,
+					Containers: []v1alpha2.Container{{Name: JenkinsMasterContainerName, Resources: resources}},
+				},
+			},
+		}
+	}
+
+	t.Run("not set", func(t *testing.T) {
+		jenkins := jenkinsWithResources(nil, corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+		})
+
+		assert.Equal(t, "", GetHeapSizingJavaOpts(jenkins))
+	})
+	t.Run("memory limit", func(t *testing.T) {
+		jenkins := jenkinsWithResources(&v1alpha2.HeapSizing{MaxPercentage: 75, InitialPercentage: 50}, corev1.ResourceRequirements{
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		})
+
+		assert.Equal(t, "-Xmx1536m -Xms1024m", GetHeapSizingJavaOpts(jenkins))
+	})
+	t.Run("memory request and default initial percentage", func(t *testing.T) {
+		jenkins := jenkinsWithResources(&v1alpha2.HeapSizing{MaxPercentage: 50}, corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		})
+
+		assert.Equal(t, "-Xmx512m -Xms512m", GetHeapSizingJavaOpts(jenkins))
+	})
+	t.Run("no memory", func(t *testing.T) {
+		jenkins := jenkinsWithResources(&v1alpha2.HeapSizing{MaxPercentage: 50}, corev1.ResourceRequirements{})
+
+		assert.Equal(t, "", GetHeapSizingJavaOpts(jenkins))
+	})
+}
@@ -1,6 +1,8 @@
 package resources
 
 import (
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
 	v1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -21,9 +23,21 @@ const (
 	BuildAPIGroup = "build.openshift.io"
 )
 
-// NewRole returns rbac role for jenkins master
-func NewRole(meta metav1.ObjectMeta) *v1.Role {
+// kubernetesCloudResources are the resources which Jenkins needs access to only to run agents with kubernetes plugin
+var kubernetesCloudResources = map[string]bool{
+	"pods":             true,
+	"pods/exec":        true,
+	"pods/portforward": true,
+	"pods/log":         true,
+	"events":           true,
+}
+
+// NewRole returns rbac role for jenkins master, access to pods is not granted when Kubernetes cloud is disabled
+func NewRole(meta metav1.ObjectMeta, jenkins *v1alpha2.Jenkins) *v1.Role {
 	rules := NewDefaultPolicyRules()
+	if jenkins.Spec.Master.DisableKubernetesCloud {
+		rules = withoutKubernetesCloudPolicyRules(rules)
+	}
 	return &v1.Role{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Role",
@@ -78,6 +92,17 @@ func NewDefaultPolicyRules() []v1.PolicyRule {
 	return rules
 }
 
+func withoutKubernetesCloudPolicyRules(rules []v1.PolicyRule) []v1.PolicyRule {
+	var filtered []v1.PolicyRule
+	for _, rule := range rules {
+		if rule.APIGroups[0] == EmptyAPIGroup && kubernetesCloudResources[rule.Resources[0]] {
+			continue
+		}
+		filtered = append(filtered, rule)
+	}
+	return filtered
+}
+
 // NewPolicyRule returns a policyRule allowing verbs on resources
 func NewPolicyRule(apiGroup string, resource string, verbs []string) v1.PolicyRule {
 	rule := v1.PolicyRule{
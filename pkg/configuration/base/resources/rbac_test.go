@@ -0,0 +1,30 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewRole(t *testing.T) {
+	resourcesOf := func(jenkins *v1alpha2.Jenkins) []string {
+		var resources []string
+		for _, rule := range NewRole(metav1.ObjectMeta{Name: "jenkins"}, jenkins).Rules {
+			resources = append(resources, rule.Resources...)
+		}
+		return resources
+	}
+
+	t.Run("Kubernetes cloud", func(t *testing.T) {
+		assert.Equal(t, []string{"pods/portforward", "pods", "pods/exec", "configmaps", "pods/log", "secrets", "events",
+			"imagestreams", "buildconfigs", "builds"}, resourcesOf(&v1alpha2.Jenkins{}))
+	})
+	t.Run("disabled Kubernetes cloud", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{Spec: v1alpha2.JenkinsSpec{Master: v1alpha2.JenkinsMaster{DisableKubernetesCloud: true}}}
+
+		assert.Equal(t, []string{"configmaps", "secrets", "imagestreams", "buildconfigs", "builds"}, resourcesOf(jenkins))
+	})
+}
@@ -17,3 +17,116 @@ func NewResourceRequirements(cpuRequest, memoryRequest, cpuLimit, memoryLimit st
 		},
 	}
 }
+
+// FitResourceRequirementsToLimitRanges adjusts default resource requirements to the container limits of LimitRanges
+// in the namespace, otherwise the pod would be rejected. The defaults of LimitRange are preferred, the requests and
+// limits are then clamped to min and max and the limit to request ratio is kept.
+func FitResourceRequirementsToLimitRanges(requirements corev1.ResourceRequirements, limitRanges []corev1.LimitRange) corev1.ResourceRequirements {
+	fitted := *requirements.DeepCopy()
+	if fitted.Requests == nil {
+		fitted.Requests = corev1.ResourceList{}
+	}
+	if fitted.Limits == nil {
+		fitted.Limits = corev1.ResourceList{}
+	}
+
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			for name, quantity := range item.Default {
+				fitted.Limits[name] = quantity.DeepCopy()
+			}
+			for name, quantity := range item.DefaultRequest {
+				fitted.Requests[name] = quantity.DeepCopy()
+			}
+			for name, max := range item.Max {
+				clampQuantity(fitted.Limits, name, func(q resource.Quantity) bool { return q.Cmp(max) > 0 }, max)
+				clampQuantity(fitted.Requests, name, func(q resource.Quantity) bool { return q.Cmp(max) > 0 }, max)
+			}
+			for name, min := range item.Min {
+				clampQuantity(fitted.Limits, name, func(q resource.Quantity) bool { return q.Cmp(min) < 0 }, min)
+				clampQuantity(fitted.Requests, name, func(q resource.Quantity) bool { return q.Cmp(min) < 0 }, min)
+			}
+			for name, ratio := range item.MaxLimitRequestRatio {
+				limit, limitSet := fitted.Limits[name]
+				request, requestSet := fitted.Requests[name]
+				if !limitSet || !requestSet || ratio.MilliValue() <= 0 {
+					continue
+				}
+				minRequest := resource.NewMilliQuantity(limit.MilliValue()*1000/ratio.MilliValue(), limit.Format)
+				if request.Cmp(*minRequest) < 0 {
+					fitted.Requests[name] = *minRequest
+				}
+			}
+		}
+	}
+
+	for name, request := range fitted.Requests {
+		if limit, found := fitted.Limits[name]; found && request.Cmp(limit) > 0 {
+			fitted.Requests[name] = limit.DeepCopy()
+		}
+	}
+	return fitted
+}
+
+func clampQuantity(list corev1.ResourceList, name corev1.ResourceName, outOfRange func(resource.Quantity) bool, bound resource.Quantity) {
+	if quantity, found := list[name]; found && outOfRange(quantity) {
+		list[name] = bound.DeepCopy()
+	}
+}
+
+// GetPodQuotaUsage returns the ResourceQuota usage of the pod the way the quota admission counts it. A container
+// without a request uses its limit as the request, the containers run together so their resources are summed and
+// the init containers run one by one before them so the pod needs at least the largest init container.
+func GetPodQuotaUsage(pod corev1.Pod) corev1.ResourceList {
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		containerRequests, containerLimits := getContainerQuotaUsage(container)
+		addResourceList(requests, containerRequests)
+		addResourceList(limits, containerLimits)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		containerRequests, containerLimits := getContainerQuotaUsage(container)
+		maxResourceList(requests, containerRequests)
+		maxResourceList(limits, containerLimits)
+	}
+
+	usage := corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")}
+	for name, quantity := range requests {
+		usage[name] = quantity.DeepCopy()
+		usage[corev1.ResourceName("requests."+string(name))] = quantity.DeepCopy()
+	}
+	for name, quantity := range limits {
+		usage[corev1.ResourceName("limits."+string(name))] = quantity.DeepCopy()
+	}
+	return usage
+}
+
+func getContainerQuotaUsage(container corev1.Container) (requests, limits corev1.ResourceList) {
+	requests = corev1.ResourceList{}
+	for name, quantity := range container.Resources.Limits {
+		requests[name] = quantity.DeepCopy()
+	}
+	for name, quantity := range container.Resources.Requests {
+		requests[name] = quantity.DeepCopy()
+	}
+	return requests, container.Resources.Limits
+}
+
+func addResourceList(list, toAdd corev1.ResourceList) {
+	for name, quantity := range toAdd {
+		sum := list[name]
+		sum.Add(quantity)
+		list[name] = sum
+	}
+}
+
+func maxResourceList(list, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if current, found := list[name]; !found || quantity.Cmp(current) > 0 {
+			list[name] = quantity.DeepCopy()
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 var jenkins = v1alpha2.Jenkins{
@@ -226,4 +227,129 @@ func TestSetLivenessAndReadinessPath(t *testing.T) {
 		assert.Equal(t, "/login", jenkins.Spec.Master.Containers[0].ReadinessProbe.HTTPGet.Path)
 		assert.Equal(t, "/login", jenkins.Spec.Master.Containers[0].LivenessProbe.HTTPGet.Path)
 	})
+
+	t.Run("health check path with JENKINS_OPTS prefix", func(t *testing.T) {
+		jenkins.Spec.Master.HealthCheckPath = "/instance-identity/"
+		jenkins.Spec.Master.Containers[0].Env =
+			[]corev1.EnvVar{
+				{Name: "JENKINS_OPTS", Value: "--prefix=/jenkins"},
+			}
+
+		jenkins.Spec.Master.Containers[0].ReadinessProbe =
+			&corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path: "/jenkins/login",
+					},
+				},
+			}
+
+		jenkins.Spec.Master.Containers[0].LivenessProbe =
+			&corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path: "/jenkins/login",
+					},
+				},
+			}
+
+		setLivenessAndReadinessPath(&jenkins)
+
+		assert.Equal(t, "/jenkins/instance-identity/", jenkins.Spec.Master.Containers[0].ReadinessProbe.HTTPGet.Path)
+		assert.Equal(t, "/jenkins/instance-identity/", jenkins.Spec.Master.Containers[0].LivenessProbe.HTTPGet.Path)
+	})
+
+	t.Run("health check path without JENKINS_OPTS prefix", func(t *testing.T) {
+		jenkins.Spec.Master.HealthCheckPath = "/instance-identity/"
+		jenkins.Spec.Master.Containers[0].Env = []corev1.EnvVar{}
+
+		jenkins.Spec.Master.Containers[0].ReadinessProbe =
+			&corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path: "/login",
+					},
+				},
+			}
+
+		jenkins.Spec.Master.Containers[0].LivenessProbe =
+			&corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path: "/login",
+					},
+				},
+			}
+
+		setLivenessAndReadinessPath(&jenkins)
+
+		assert.Equal(t, "/instance-identity/", jenkins.Spec.Master.Containers[0].ReadinessProbe.HTTPGet.Path)
+		assert.Equal(t, "/instance-identity/", jenkins.Spec.Master.Containers[0].LivenessProbe.HTTPGet.Path)
+	})
+}
+
+func TestFitResourceRequirementsToLimitRanges(t *testing.T) {
+	defaults := NewResourceRequirements("1", "500Mi", "1500m", "3Gi")
+
+	t.Run("no limit ranges", func(t *testing.T) {
+		assert.Equal(t, defaults, FitResourceRequirementsToLimitRanges(defaults, nil))
+	})
+	t.Run("clamped to min and max", func(t *testing.T) {
+		limitRanges := []corev1.LimitRange{{Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{
+			{Type: corev1.LimitTypePod, Max: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}},
+			{
+				Type: corev1.LimitTypeContainer,
+				Max:  corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi"), corev1.ResourceCPU: resource.MustParse("800m")},
+				Min:  corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+		}}}}
+
+		fitted := FitResourceRequirementsToLimitRanges(defaults, limitRanges)
+
+		assert.Equal(t, "800m", fitted.Requests.Cpu().String())
+		assert.Equal(t, "800m", fitted.Limits.Cpu().String())
+		assert.Equal(t, "1Gi", fitted.Requests.Memory().String())
+		assert.Equal(t, "2Gi", fitted.Limits.Memory().String())
+	})
+	t.Run("limit range defaults and ratio", func(t *testing.T) {
+		limitRanges := []corev1.LimitRange{{Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+			Type:                 corev1.LimitTypeContainer,
+			Default:              corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			MaxLimitRequestRatio: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("2")},
+		}}}}}
+
+		fitted := FitResourceRequirementsToLimitRanges(defaults, limitRanges)
+
+		assert.Equal(t, "1", fitted.Requests.Cpu().String())
+		assert.Equal(t, "2", fitted.Limits.Cpu().String())
+		assert.Equal(t, "1536Mi", fitted.Requests.Memory().String())
+	})
+}
+
+func TestGetPodQuotaUsage(t *testing.T) {
+	pod := corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			{Name: "init", Resources: NewResourceRequirements("2", "100Mi", "2", "100Mi")},
+		},
+		Containers: []corev1.Container{
+			{Name: JenkinsMasterContainerName, Resources: NewResourceRequirements("1", "500Mi", "1500m", "3Gi")},
+			{Name: "sidecar", Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("100Mi")},
+			}},
+		},
+	}}
+
+	usage := GetPodQuotaUsage(pod)
+
+	assert.Equal(t, "1", usage.Pods().String())
+	assert.Equal(t, "2", usage.Cpu().String())
+	assert.Equal(t, "600Mi", usage.Memory().String())
+	requestsCPU := usage[corev1.ResourceRequestsCPU]
+	assert.Equal(t, "2", requestsCPU.String())
+	requestsMemory := usage[corev1.ResourceRequestsMemory]
+	assert.Equal(t, "600Mi", requestsMemory.String())
+	limitsCPU := usage[corev1.ResourceLimitsCPU]
+	assert.Equal(t, "2", limitsCPU.String())
+	limitsMemory := usage[corev1.ResourceLimitsMemory]
+	assert.Equal(t, "3172Mi", limitsMemory.String())
 }
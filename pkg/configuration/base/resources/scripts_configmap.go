@@ -1,7 +1,10 @@
 package resources
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
@@ -336,10 +339,30 @@ cp -n {{ .InitConfigurationPath }}/*.groovy {{ .JenkinsHomePath }}/init.groovy.d
 mkdir -p {{ .JenkinsHomePath }}/scripts
 cp {{ .JenkinsScriptsVolumePath }}/*.sh {{ .JenkinsHomePath }}/scripts
 chmod +x {{ .JenkinsHomePath }}/scripts/*.sh
+{{- if .CrashLogsPath }}
+
+mkdir -p {{ .CrashLogsPath }}
+{{- end }}
 
 {{- $jenkinsHomePath := .JenkinsHomePath }}
 {{- $installPluginsCommand := .InstallPluginsCommand }}
 
+{{- if .SkipPlugins }}
+
+echo "Skipping installation of plugins, base configuration is disabled"
+{{- else if .LockedPlugins }}
+
+echo "Installing locked plugins - begin"
+cat > {{ .JenkinsHomePath }}/locked-plugins.txt << EOF
+{{ range $index, $plugin := .LockedPlugins }}
+{{ $plugin.Name }}:{{ $plugin.Version }}{{if $plugin.DownloadURL}}:{{ $plugin.DownloadURL }}{{end}}
+{{ end }}
+EOF
+
+{{ $installPluginsCommand }} --verbose -f {{ .JenkinsHomePath }}/locked-plugins.txt
+echo "Installing locked plugins - end"
+{{- else }}
+
 echo "Installing plugins required by Operator - begin"
 cat > {{ .JenkinsHomePath }}/base-plugins.txt << EOF
 {{ range $index, $plugin := .BasePlugins }}
@@ -359,6 +382,7 @@ EOF
 
 {{ $installPluginsCommand }} --verbose -f {{ .JenkinsHomePath }}/user-plugins.txt
 echo "Installing plugins required by user - end"
+{{- end }}
 `))
 
 func buildConfigMapTypeMeta() metav1.TypeMeta {
@@ -376,14 +400,22 @@ func buildInitBashScript(jenkins *v1alpha2.Jenkins) (*string, error) {
 		JenkinsScriptsVolumePath string
 		BasePlugins              []v1alpha2.Plugin
 		UserPlugins              []v1alpha2.Plugin
+		LockedPlugins            []v1alpha2.Plugin
+		SkipPlugins              bool
+		CrashLogsPath            string
 	}{
-		JenkinsHomePath:          getJenkinsHomePath(jenkins),
+		JenkinsHomePath:          GetJenkinsHomePath(jenkins),
 		InitConfigurationPath:    jenkinsInitConfigurationVolumePath,
 		BasePlugins:              jenkins.Spec.Master.BasePlugins,
 		UserPlugins:              jenkins.Spec.Master.Plugins,
+		LockedPlugins:            GetLockedPlugins(jenkins),
+		SkipPlugins:              jenkins.Spec.Master.SkipBaseConfiguration,
 		InstallPluginsCommand:    installPluginsCommand,
 		JenkinsScriptsVolumePath: JenkinsScriptsVolumePath,
 	}
+	if jenkins.Spec.Master.CrashLogs != nil {
+		data.CrashLogsPath = GetCrashLogsPath(jenkins)
+	}
 
 	output, err := render.Render(initBashTemplate, data)
 	if err != nil {
@@ -393,6 +425,38 @@ func buildInitBashScript(jenkins *v1alpha2.Jenkins) (*string, error) {
 	return &output, nil
 }
 
+// GetPluginsSpecHash returns hash of base and user plugins used to check if the plugins lock is up to date
+func GetPluginsSpecHash(jenkins *v1alpha2.Jenkins) string {
+	hash := sha256.New()
+	for _, plugin := range append(append([]v1alpha2.Plugin{}, jenkins.Spec.Master.BasePlugins...), jenkins.Spec.Master.Plugins...) {
+		_, _ = hash.Write([]byte(fmt.Sprintf("%s:%s:%s\n", plugin.Name, plugin.Version, plugin.DownloadURL)))
+	}
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil))
+}
+
+// GetLockedPlugins returns plugins from the plugins lock when locking is enabled and the lock is up to date,
+// download URLs are taken from the spec
+func GetLockedPlugins(jenkins *v1alpha2.Jenkins) []v1alpha2.Plugin {
+	lock := jenkins.Status.PluginsLock
+	if !jenkins.Spec.Master.LockPlugins || lock == nil || lock.SpecHash != GetPluginsSpecHash(jenkins) {
+		return nil
+	}
+
+	downloadURLs := map[string]string{}
+	for _, plugin := range append(append([]v1alpha2.Plugin{}, jenkins.Spec.Master.BasePlugins...), jenkins.Spec.Master.Plugins...) {
+		downloadURLs[plugin.Name] = plugin.DownloadURL
+	}
+	var lockedPlugins []v1alpha2.Plugin
+	for _, nameWithVersion := range lock.Plugins {
+		parts := strings.SplitN(nameWithVersion, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		lockedPlugins = append(lockedPlugins, v1alpha2.Plugin{Name: parts[0], Version: parts[1], DownloadURL: downloadURLs[parts[0]]})
+	}
+	return lockedPlugins
+}
+
 func getScriptsConfigMapName(jenkins *v1alpha2.Jenkins) string {
 	return fmt.Sprintf("%s-scripts-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
 }
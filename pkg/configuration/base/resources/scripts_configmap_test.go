@@ -0,0 +1,101 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLockedPlugins(t *testing.T) {
+	newJenkins := func() *v1alpha2.Jenkins {
+		jenkins := &v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					Containers:  []v1alpha2.Container{{Name: JenkinsMasterContainerName}},
+					BasePlugins: []v1alpha2.Plugin{{Name: "kubernetes", Version: "1.0.0"}},
+					Plugins:     []v1alpha2.Plugin{{Name: "custom", Version: "0.1.0", DownloadURL: "https://example.com/custom.hpi"}},
+					LockPlugins: true,
+				},
+			},
+		}
+		jenkins.Status.PluginsLock = &v1alpha2.PluginsLock{
+			SpecHash: GetPluginsSpecHash(jenkins),
+			Plugins:  []string{"custom:0.1.0", "dependency:2.0.0", "kubernetes:1.0.0"},
+		}
+		return jenkins
+	}
+
+	t.Run("locked", func(t *testing.T) {
+		jenkins := newJenkins()
+
+		got := GetLockedPlugins(jenkins)
+
+		assert.Equal(t, []v1alpha2.Plugin{
+			{Name: "custom", Version: "0.1.0", DownloadURL: "https://example.com/custom.hpi"},
+			{Name: "dependency", Version: "2.0.0"},
+			{Name: "kubernetes", Version: "1.0.0"},
+		}, got)
+
+		script, err := buildInitBashScript(jenkins)
+		require.NoError(t, err)
+		assert.Contains(t, *script, "dependency:2.0.0\n")
+		assert.Contains(t, *script, "locked-plugins.txt")
+		assert.NotContains(t, *script, "user-plugins.txt")
+	})
+	t.Run("locking disabled", func(t *testing.T) {
+		jenkins := newJenkins()
+		jenkins.Spec.Master.LockPlugins = false
+
+		assert.Nil(t, GetLockedPlugins(jenkins))
+	})
+	t.Run("plugins in spec changed", func(t *testing.T) {
+		jenkins := newJenkins()
+		jenkins.Spec.Master.Plugins[0].Version = "0.2.0"
+
+		assert.Nil(t, GetLockedPlugins(jenkins))
+
+		script, err := buildInitBashScript(jenkins)
+		require.NoError(t, err)
+		assert.Contains(t, *script, "custom:0.2.0:https://example.com/custom.hpi\n")
+		assert.NotContains(t, *script, "locked-plugins.txt")
+	})
+}
+
+func TestBuildInitBashScript(t *testing.T) {
+	t.Run("base configuration skipped", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					Containers:            []v1alpha2.Container{{Name: JenkinsMasterContainerName}},
+					BasePlugins:           []v1alpha2.Plugin{{Name: "kubernetes", Version: "1.0.0"}},
+					SkipBaseConfiguration: true,
+				},
+			},
+		}
+
+		script, err := buildInitBashScript(jenkins)
+
+		require.NoError(t, err)
+		assert.Contains(t, *script, "init.groovy.d")
+		assert.NotContains(t, *script, installPluginsCommand)
+		assert.NotContains(t, *script, "kubernetes:1.0.0")
+	})
+	t.Run("crash logs directory", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					Containers: []v1alpha2.Container{{Name: JenkinsMasterContainerName}},
+					CrashLogs:  &v1alpha2.CrashLogs{},
+				},
+			},
+		}
+
+		script, err := buildInitBashScript(jenkins)
+
+		require.NoError(t, err)
+		assert.Contains(t, *script, "mkdir -p /var/lib/jenkins/crash-logs\n")
+	})
+}
@@ -68,6 +68,16 @@ func GetJenkinsSlavesServiceFQDN(jenkins *v1alpha2.Jenkins, kubernetesClusterDom
 	return fmt.Sprintf("%s-slave-%s.%s.svc.%s", constants.OperatorName, jenkins.ObjectMeta.Name, jenkins.ObjectMeta.Namespace, clusterDomain), nil
 }
 
+// GetServiceFQDN returns FQDN of Kubernetes service in the namespace
+func GetServiceFQDN(name, namespace, kubernetesClusterDomain string) (string, error) {
+	clusterDomain, err := getClusterDomain(kubernetesClusterDomain)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s.%s.svc.%s", name, namespace, clusterDomain), nil
+}
+
 // GetClusterDomain returns Kubernetes cluster domain, default to "cluster.local"
 func getClusterDomain(kubernetesClusterDomain string) (string, error) {
 	isRunningInCluster, err := IsRunningInCluster()
@@ -0,0 +1,124 @@
+package resources
+
+import (
+	"fmt"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// TruststoreInitContainerName is the name of init container which builds Java truststore for Jenkins master
+	TruststoreInitContainerName = "truststore"
+
+	truststoreVolumeName          = "truststore"
+	truststoreVolumePath          = jenkinsPath + "/truststore"
+	truststoreFile                = truststoreVolumePath + "/cacerts"
+	truststorePassword            = "changeit"
+	trustedCertificatesVolumePath = jenkinsPath + "/trusted-certificates"
+)
+
+// builds truststore from the JVM default one and imports all PEM certificates from trusted certificates ConfigMaps
+const truststoreBashScript = `set -e
+
+cacerts="${JAVA_HOME}/lib/security/cacerts"
+if [ ! -f "${cacerts}" ]; then
+  cacerts="${JAVA_HOME}/jre/lib/security/cacerts"
+fi
+cp "${cacerts}" %[1]s
+chmod 644 %[1]s
+
+workdir=$(mktemp -d %[2]s/certs.XXXXXX)
+for file in %[3]s/*/*; do
+  [ -f "${file}" ] || continue
+  name="$(basename "$(dirname "${file}")")-$(basename "${file}")"
+  awk -v prefix="${workdir}/${name}-" '/-----BEGIN CERTIFICATE-----/{n++} n>0{print > (prefix n ".pem")}' "${file}"
+done
+for cert in "${workdir}"/*.pem; do
+  [ -f "${cert}" ] || continue
+  echo "Importing ${cert}"
+  keytool -importcert -noprompt -keystore %[1]s -storepass %[4]s -alias "$(basename "${cert}" .pem)" -file "${cert}"
+done
+rm -rf "${workdir}"
+`
+
+func getTrustedCertificatesVolumeName(index int) string {
+	return fmt.Sprintf("trusted-certificates-%d", index)
+}
+
+func getTruststoreVolumes(jenkins *v1alpha2.Jenkins) []corev1.Volume {
+	if len(jenkins.Spec.Master.TrustedCertificates) == 0 {
+		return nil
+	}
+
+	configMapVolumeSourceDefaultMode := corev1.ConfigMapVolumeSourceDefaultMode
+	volumes := []corev1.Volume{
+		{
+			Name: truststoreVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: newEmptyDirVolumeSource(jenkins),
+			},
+		},
+	}
+	for index, configMap := range jenkins.Spec.Master.TrustedCertificates {
+		volumes = append(volumes, corev1.Volume{
+			Name: getTrustedCertificatesVolumeName(index),
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					DefaultMode: &configMapVolumeSourceDefaultMode,
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: configMap.Name,
+					},
+				},
+			},
+		})
+	}
+
+	return volumes
+}
+
+func getTruststoreVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      truststoreVolumeName,
+		MountPath: truststoreVolumePath,
+		ReadOnly:  true,
+	}
+}
+
+// GetTruststoreJavaOpts returns Java options which make JVM use the truststore built by the operator
+func GetTruststoreJavaOpts() string {
+	return fmt.Sprintf("-Djavax.net.ssl.trustStore=%s -Djavax.net.ssl.trustStorePassword=%s", truststoreFile, truststorePassword)
+}
+
+// NewTruststoreInitContainer returns init container which builds Java truststore with trusted certificates
+func NewTruststoreInitContainer(jenkins *v1alpha2.Jenkins) corev1.Container {
+	jenkinsContainer := jenkins.Spec.Master.Containers[0]
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      truststoreVolumeName,
+			MountPath: truststoreVolumePath,
+		},
+	}
+	for index := range jenkins.Spec.Master.TrustedCertificates {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      getTrustedCertificatesVolumeName(index),
+			MountPath: fmt.Sprintf("%s/%d", trustedCertificatesVolumePath, index),
+			ReadOnly:  true,
+		})
+	}
+
+	return corev1.Container{
+		Name:            TruststoreInitContainerName,
+		Image:           jenkinsContainer.Image,
+		ImagePullPolicy: jenkinsContainer.ImagePullPolicy,
+		Command: []string{
+			"bash",
+			"-c",
+			fmt.Sprintf(truststoreBashScript, truststoreFile, truststoreVolumePath, trustedCertificatesVolumePath, truststorePassword),
+		},
+		SecurityContext: jenkinsContainer.SecurityContext,
+		VolumeMounts:    volumeMounts,
+	}
+}
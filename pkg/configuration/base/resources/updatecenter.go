@@ -0,0 +1,119 @@
+package resources
+
+import (
+	"fmt"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// KeystoreInitContainerName is the name of init container which builds Java keystore with update center client certificate
+	KeystoreInitContainerName = "keystore"
+
+	updateCenterEnvName         = "JENKINS_UC"
+	keystoreVolumeName          = "keystore"
+	keystoreVolumePath          = jenkinsPath + "/keystore"
+	keystoreFile                = keystoreVolumePath + "/keystore.p12"
+	keystorePassword            = "changeit"
+	clientCertificateVolumeName = "update-center-client-certificate"
+	clientCertificateVolumePath = jenkinsPath + "/update-center-client-certificate"
+)
+
+// builds PKCS12 keystore from PEM encoded client certificate and private key
+const keystoreBashScript = `set -e
+
+openssl pkcs12 -export -in %[2]s/%[3]s -inkey %[2]s/%[4]s -name update-center -out %[1]s -passout pass:%[5]s
+chmod 644 %[1]s
+`
+
+const configureUpdateCenterFmt = `
+import hudson.model.UpdateCenter
+import hudson.model.UpdateSite
+import jenkins.model.Jenkins
+
+def updateCenter = Jenkins.instance.getUpdateCenter()
+def site = updateCenter.getSite(UpdateCenter.ID_DEFAULT)
+if (site == null || site.getUrl() != %[1]s) {
+    updateCenter.getSites().removeAll { it.getId() == UpdateCenter.ID_DEFAULT }
+    updateCenter.getSites().add(new UpdateSite(UpdateCenter.ID_DEFAULT, %[1]s))
+    updateCenter.save()
+    updateCenter.updateAllSites()
+}
+`
+
+func isUpdateCenterClientCertificateSet(jenkins *v1alpha2.Jenkins) bool {
+	return jenkins.Spec.Master.UpdateCenter != nil && jenkins.Spec.Master.UpdateCenter.ClientCertificate != nil
+}
+
+func getKeystoreVolumes(jenkins *v1alpha2.Jenkins) []corev1.Volume {
+	if !isUpdateCenterClientCertificateSet(jenkins) {
+		return nil
+	}
+
+	secretVolumeSourceDefaultMode := corev1.SecretVolumeSourceDefaultMode
+	return []corev1.Volume{
+		{
+			Name: keystoreVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: newEmptyDirVolumeSource(jenkins),
+			},
+		},
+		{
+			Name: clientCertificateVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					DefaultMode: &secretVolumeSourceDefaultMode,
+					SecretName:  jenkins.Spec.Master.UpdateCenter.ClientCertificate.Name,
+				},
+			},
+		},
+	}
+}
+
+func getKeystoreVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      keystoreVolumeName,
+		MountPath: keystoreVolumePath,
+		ReadOnly:  true,
+	}
+}
+
+// GetKeystoreJavaOpts returns Java options which make JVM use the update center client certificate for TLS connections
+func GetKeystoreJavaOpts() string {
+	return fmt.Sprintf("-Djavax.net.ssl.keyStore=%s -Djavax.net.ssl.keyStorePassword=%s -Djavax.net.ssl.keyStoreType=PKCS12",
+		keystoreFile, keystorePassword)
+}
+
+// NewKeystoreInitContainer returns init container which builds Java keystore with update center client certificate
+func NewKeystoreInitContainer(jenkins *v1alpha2.Jenkins) corev1.Container {
+	jenkinsContainer := jenkins.Spec.Master.Containers[0]
+
+	return corev1.Container{
+		Name:            KeystoreInitContainerName,
+		Image:           jenkinsContainer.Image,
+		ImagePullPolicy: jenkinsContainer.ImagePullPolicy,
+		Command: []string{
+			"bash",
+			"-c",
+			fmt.Sprintf(keystoreBashScript, keystoreFile, clientCertificateVolumePath, corev1.TLSCertKey, corev1.TLSPrivateKeyKey, keystorePassword),
+		},
+		SecurityContext: jenkinsContainer.SecurityContext,
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      keystoreVolumeName,
+				MountPath: keystoreVolumePath,
+			},
+			{
+				Name:      clientCertificateVolumeName,
+				MountPath: clientCertificateVolumePath,
+				ReadOnly:  true,
+			},
+		},
+	}
+}
+
+func buildConfigureUpdateCenterGroovyScript(updateCenter *v1alpha2.UpdateCenter) string {
+	return fmt.Sprintf(configureUpdateCenterFmt, "'"+groovyStringReplacer.Replace(updateCenter.URL)+"'")
+}
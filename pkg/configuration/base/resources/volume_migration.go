@@ -0,0 +1,86 @@
+package resources
+
+import (
+	"fmt"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/constants"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultJenkinsHomeMigrationImage is the image of Jenkins home migration Job when
+	// spec.master.jenkinsHomePersistentVolumeClaim.migrationImage isn't set
+	DefaultJenkinsHomeMigrationImage = "busybox:1.35"
+
+	jenkinsHomeMigrationSourcePath = "/source"
+	jenkinsHomeMigrationTargetPath = "/target"
+)
+
+// jenkinsHomeMigrationJobBackoffLimit is the number of retries of the copy before the migration fails
+var jenkinsHomeMigrationJobBackoffLimit int32 = 2
+
+// GetJenkinsHomeMigrationJobName returns name of the Job which copies Jenkins home to the claim of another storage class
+func GetJenkinsHomeMigrationJobName(jenkins *v1alpha2.Jenkins) string {
+	return fmt.Sprintf("%s-home-migration-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
+}
+
+// NewJenkinsHomeMigrationJob builds the Job which copies Jenkins home from the source to the target claim of
+// the migration, the files are copied as root to keep their owners
+func NewJenkinsHomeMigrationJob(meta metav1.ObjectMeta, jenkins *v1alpha2.Jenkins, migration v1alpha2.VolumeMigration) *batchv1.Job {
+	meta.Name = GetJenkinsHomeMigrationJobName(jenkins)
+	image := DefaultJenkinsHomeMigrationImage
+	if config := jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim; config != nil && len(config.MigrationImage) > 0 {
+		image = config.MigrationImage
+	}
+	script := fmt.Sprintf("if command -v rsync > /dev/null; then rsync -aH --delete %s/ %s/; else cp -a %s/. %s/; fi",
+		jenkinsHomeMigrationSourcePath, jenkinsHomeMigrationTargetPath, jenkinsHomeMigrationSourcePath, jenkinsHomeMigrationTargetPath)
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: meta,
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &jenkinsHomeMigrationJobBackoffLimit,
+			// the pod isn't labeled like Jenkins master pod, it mustn't be selected by Jenkins Services
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyNever,
+					NodeSelector:     jenkins.Spec.Master.NodeSelector,
+					Tolerations:      jenkins.Spec.Master.Tolerations,
+					ImagePullSecrets: jenkins.Spec.Master.ImagePullSecrets,
+					Containers: []corev1.Container{
+						{
+							Name:    "migration",
+							Image:   image,
+							Command: []string{"sh", "-c", script},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "source", MountPath: jenkinsHomeMigrationSourcePath, ReadOnly: true},
+								{Name: "target", MountPath: jenkinsHomeMigrationTargetPath},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "source",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: migration.SourceClaimName, ReadOnly: true},
+							},
+						},
+						{
+							Name: "target",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: migration.TargetClaimName},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package resources
+
+import (
+	"fmt"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/constants"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// VolumeSnapshotAPIGroup is the API group of CSI volume snapshots
+	VolumeSnapshotAPIGroup = "snapshot.storage.k8s.io"
+	// VolumeSnapshotAPIVersion is the API version of CSI volume snapshots
+	VolumeSnapshotAPIVersion = VolumeSnapshotAPIGroup + "/v1"
+	// VolumeSnapshotKind is the kind of CSI volume snapshots
+	VolumeSnapshotKind = "VolumeSnapshot"
+)
+
+// GetJenkinsHomeVolumeSnapshotName returns name of Jenkins home VolumeSnapshot taken before Jenkins master image upgrade
+func GetJenkinsHomeVolumeSnapshotName(jenkins *v1alpha2.Jenkins) string {
+	return fmt.Sprintf("%s-home-%s-rollback", constants.OperatorName, jenkins.ObjectMeta.Name)
+}
+
+// NewJenkinsHomeVolumeSnapshot builds VolumeSnapshot of Jenkins home PersistentVolumeClaim
+func NewJenkinsHomeVolumeSnapshot(meta metav1.ObjectMeta, jenkins *v1alpha2.Jenkins) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": GetJenkinsHomePersistentVolumeClaimName(jenkins),
+		},
+	}
+	if className := jenkins.Spec.Master.ImageRollback.VolumeSnapshotClassName; className != nil {
+		spec["volumeSnapshotClassName"] = *className
+	}
+
+	snapshot := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	snapshot.SetAPIVersion(VolumeSnapshotAPIVersion)
+	snapshot.SetKind(VolumeSnapshotKind)
+	snapshot.SetName(GetJenkinsHomeVolumeSnapshotName(jenkins))
+	snapshot.SetNamespace(meta.Namespace)
+	snapshot.SetLabels(meta.Labels)
+	snapshot.SetAnnotations(meta.Annotations)
+	return snapshot
+}
+
+// NewJenkinsHomeVolumeSnapshotDataSource returns data source of PersistentVolumeClaim restored from the VolumeSnapshot
+func NewJenkinsHomeVolumeSnapshotDataSource(name string) *corev1.TypedLocalObjectReference {
+	apiGroup := VolumeSnapshotAPIGroup
+	return &corev1.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     VolumeSnapshotKind,
+		Name:     name,
+	}
+}
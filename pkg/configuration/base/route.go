@@ -1,7 +1,6 @@
 package base
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
@@ -9,23 +8,22 @@ import (
 
 	routev1 "github.com/openshift/api/route/v1"
 	stackerr "github.com/pkg/errors"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-// createRoute takes the ServiceName and Creates the Route based on it
+// createRoute takes the ServiceName and applies the Route based on it, the host generated by the router is kept
 func (r *JenkinsBaseConfigurationReconciler) createRoute(meta metav1.ObjectMeta, serviceName string, config *v1alpha2.Jenkins) error {
-	route := routev1.Route{}
-	name := fmt.Sprintf("jenkins-%s", config.ObjectMeta.Name)
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: meta.Namespace}, &route)
-	if err != nil && apierrors.IsNotFound(err) {
-		port := &routev1.RoutePort{
-			TargetPort: intstr.FromString(""),
-		}
-
-		routeSpec := routev1.RouteSpec{
+	route := routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("jenkins-%s", config.ObjectMeta.Name),
+			Namespace:   meta.Namespace,
+			Labels:      meta.Labels, // make sure that user won't break route by hand
+			Annotations: meta.Annotations,
+		},
+		Spec: routev1.RouteSpec{
 			TLS: &routev1.TLSConfig{
 				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
 				Termination:                   routev1.TLSTerminationEdge,
@@ -34,25 +32,34 @@ func (r *JenkinsBaseConfigurationReconciler) createRoute(meta metav1.ObjectMeta,
 				Kind: resources.ServiceKind,
 				Name: serviceName,
 			},
-			Port: port,
-		}
-		actual := routev1.Route{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      name,
-				Namespace: meta.Namespace,
-				Labels:    meta.Labels,
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromString(""),
 			},
-			Spec: routeSpec,
-		}
-		route = resources.UpdateRoute(actual, config)
-		if err = r.CreateResource(&route); err != nil {
-			return stackerr.WithStack(err)
-		}
-	} else if err != nil {
-		return stackerr.WithStack(err)
+		},
 	}
-
-	route.ObjectMeta.Labels = meta.Labels // make sure that user won't break service by hand
 	route = resources.UpdateRoute(route, config)
-	return stackerr.WithStack(r.UpdateResource(&route))
+	object, err := newRouteApplyObject(route)
+	if err != nil {
+		return err
+	}
+	return stackerr.WithStack(r.CreateOrUpdateResource(object))
+}
+
+// newRouteApplyObject returns the route to apply without the empty host and weight, they aren't omitted from the typed
+// route when they're empty, so applying it would take the host generated by the router over and reset it
+func newRouteApplyObject(route routev1.Route) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&route)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	object := &unstructured.Unstructured{Object: content}
+	object.SetGroupVersionKind(routev1.SchemeGroupVersion.WithKind("Route"))
+	if len(route.Spec.Host) == 0 {
+		unstructured.RemoveNestedField(object.Object, "spec", "host")
+	}
+	if route.Spec.To.Weight == nil {
+		unstructured.RemoveNestedField(object.Object, "spec", "to", "weight")
+	}
+	unstructured.RemoveNestedField(object.Object, "status")
+	return object, nil
 }
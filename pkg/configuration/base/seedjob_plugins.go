@@ -0,0 +1,62 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/plugins"
+
+	"github.com/bndr/gojenkins"
+	stackerr "github.com/pkg/errors"
+)
+
+const seedJobPluginsChangedEventReason k8sevent.Reason = "SeedJobPluginsChanged"
+
+// seedJobPluginNames are the plugins which generate the seed jobs and the jobs defined in the seed job repositories
+var seedJobPluginNames = map[string]bool{"git": true, "job-dsl": true}
+
+// ensureSeedJobPlugins records the installed versions of the plugins which generate the seed jobs, the jobs generated
+// by the previous versions may be invalid so the seed jobs are applied again by the user configuration when they change
+func (r *JenkinsBaseConfigurationReconciler) ensureSeedJobPlugins(jenkinsClient jenkinsclient.Jenkins) error {
+	jenkins := r.Configuration.Jenkins
+	if len(jenkins.Spec.SeedJobs) == 0 {
+		return nil
+	}
+
+	allPluginsInJenkins, err := jenkinsClient.GetPlugins(fetchAllPlugins)
+	if err != nil {
+		return stackerr.WithStack(err)
+	}
+	installed := getSeedJobPlugins(allPluginsInJenkins)
+	previous := jenkins.Status.SeedJobPlugins
+	if strings.Join(installed, ",") == strings.Join(previous, ",") {
+		return nil
+	}
+
+	if len(previous) > 0 {
+		message := fmt.Sprintf("Seed job plugins have changed from '%s' to '%s', applying seed jobs again",
+			strings.Join(previous, ", "), strings.Join(installed, ", "))
+		r.logger.Info(message)
+		if r.Configuration.Events != nil {
+			r.Configuration.Events.Emit(jenkins, k8sevent.TypeNormal, seedJobPluginsChangedEventReason, message)
+		}
+	}
+	jenkins.Status.SeedJobPlugins = installed
+	return stackerr.WithStack(r.Client.Status().Update(context.TODO(), jenkins))
+}
+
+// getSeedJobPlugins returns the sorted installed seed job plugins in name:version format
+func getSeedJobPlugins(allPluginsInJenkins *gojenkins.Plugins) []string {
+	var installed []string
+	for _, jenkinsPlugin := range allPluginsInJenkins.Raw.Plugins {
+		if seedJobPluginNames[jenkinsPlugin.ShortName] && isValidPlugin(jenkinsPlugin) {
+			installed = append(installed, plugins.Plugin{Name: jenkinsPlugin.ShortName, Version: jenkinsPlugin.Version}.String())
+		}
+	}
+	sort.Strings(installed)
+	return installed
+}
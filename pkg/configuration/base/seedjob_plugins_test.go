@@ -0,0 +1,100 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/bndr/gojenkins"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestJenkinsBaseConfigurationReconciler_ensureSeedJobPlugins(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	pluginsInJenkins := func(jobDslVersion string) *gojenkins.Plugins {
+		return &gojenkins.Plugins{Raw: &gojenkins.PluginResponse{Plugins: []gojenkins.Plugin{
+			{ShortName: "job-dsl", Version: jobDslVersion, Active: true, Enabled: true},
+			{ShortName: "git", Version: "4.10.0", Active: true, Enabled: true},
+			{ShortName: "kubernetes", Version: "1.31.1", Active: true, Enabled: true},
+		}}}
+	}
+	newReconciler := func(jenkins *v1alpha2.Jenkins, recorder *fakeRecorder) JenkinsBaseConfigurationReconciler {
+		return JenkinsBaseConfigurationReconciler{
+			logger: log.Log,
+			Configuration: configuration.Configuration{
+				Client:  fake.NewClientBuilder().WithObjects(jenkins).Build(),
+				Jenkins: jenkins,
+				Events:  recorder,
+			},
+		}
+	}
+
+	t.Run("no seed jobs", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+		r := newReconciler(jenkins, &fakeRecorder{})
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		err := r.ensureSeedJobPlugins(client.NewMockJenkins(ctrl))
+
+		require.NoError(t, err)
+		assert.Empty(t, jenkins.Status.SeedJobPlugins)
+	})
+	t.Run("record plugins", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+			Spec:       v1alpha2.JenkinsSpec{SeedJobs: []v1alpha2.SeedJob{{ID: "jenkins-operator"}}},
+		}
+		recorder := &fakeRecorder{}
+		r := newReconciler(jenkins, recorder)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().GetPlugins(fetchAllPlugins).Return(pluginsInJenkins("1.81"), nil)
+
+		err := r.ensureSeedJobPlugins(jenkinsClient)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"git:4.10.0", "job-dsl:1.81"}, jenkins.Status.SeedJobPlugins)
+		assert.Empty(t, recorder.events)
+	})
+	t.Run("plugins changed", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+			Spec:       v1alpha2.JenkinsSpec{SeedJobs: []v1alpha2.SeedJob{{ID: "jenkins-operator"}}},
+			Status:     v1alpha2.JenkinsStatus{SeedJobPlugins: []string{"git:4.10.0", "job-dsl:1.81"}},
+		}
+		recorder := &fakeRecorder{}
+		r := newReconciler(jenkins, recorder)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().GetPlugins(fetchAllPlugins).Return(pluginsInJenkins("1.81"), nil)
+		jenkinsClient.EXPECT().GetPlugins(fetchAllPlugins).Return(pluginsInJenkins("1.82"), nil)
+
+		err := r.ensureSeedJobPlugins(jenkinsClient)
+		require.NoError(t, err)
+		assert.Empty(t, recorder.events)
+
+		err = r.ensureSeedJobPlugins(jenkinsClient)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"git:4.10.0", "job-dsl:1.82"}, jenkins.Status.SeedJobPlugins)
+		require.Len(t, recorder.events, 1)
+		assert.Equal(t, recordedEvent{
+			eventType: k8sevent.TypeNormal,
+			reason:    seedJobPluginsChangedEventReason,
+			message:   "Seed job plugins have changed from 'git:4.10.0, job-dsl:1.81' to 'git:4.10.0, job-dsl:1.82', applying seed jobs again",
+		}, recorder.events[0])
+	})
+}
@@ -2,6 +2,7 @@ package base
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
@@ -13,28 +14,42 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
-func (r *JenkinsBaseConfigurationReconciler) createService(meta metav1.ObjectMeta, name string, config v1alpha2.Service, targetPort int32) error {
-	service := corev1.Service{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: meta.Namespace}, &service)
+// deleteService deletes the service if it's owned by the Jenkins CR
+func (r *JenkinsBaseConfigurationReconciler) deleteService(name string) error {
+	service := &corev1.Service{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.Configuration.Jenkins.Namespace, Name: name}, service)
 	if err != nil && apierrors.IsNotFound(err) {
-		service = resources.UpdateService(corev1.Service{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      name,
-				Namespace: meta.Namespace,
-				Labels:    meta.Labels,
-			},
-			Spec: corev1.ServiceSpec{
-				Selector: meta.Labels,
-			},
-		}, config, targetPort)
-		if err = r.CreateResource(&service); err != nil {
-			return stackerr.WithStack(err)
-		}
+		return nil
 	} else if err != nil {
 		return stackerr.WithStack(err)
 	}
+	if !metav1.IsControlledBy(service, r.Configuration.Jenkins) {
+		return nil
+	}
 
-	service.Spec.Selector = meta.Labels // make sure that user won't break service by hand
-	service = resources.UpdateService(service, config, targetPort)
-	return stackerr.WithStack(r.UpdateResource(&service))
+	r.logger.Info(fmt.Sprintf("Deleting Service '%s'", name))
+	err = r.Client.Delete(context.TODO(), service)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return stackerr.WithStack(err)
+	}
+	return nil
+}
+
+// createService applies the service, fields not set by the operator, e.g. annotations added by external-dns or
+// the allocated cluster IP and node port, are kept
+func (r *JenkinsBaseConfigurationReconciler) createService(meta metav1.ObjectMeta, name string, config v1alpha2.Service, targetPort int32) error {
+	service := resources.UpdateService(corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: meta.Namespace,
+			Labels:    resources.MergeMaps(meta.Labels),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: resources.BuildResourceLabels(r.Configuration.Jenkins), // make sure that user won't break service by hand
+		},
+	}, config, targetPort)
+	// protocol is a key of the ports list, it has to be set explicitly in server-side apply
+	service.Spec.Ports[0].Protocol = corev1.ProtocolTCP
+	service.ObjectMeta.Annotations = resources.MergeMaps(meta.Annotations, service.ObjectMeta.Annotations)
+	return stackerr.WithStack(r.CreateOrUpdateResource(&service))
 }
@@ -3,10 +3,14 @@ package base
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
 	"github.com/maximba/kubernetes-operator/pkg/constants"
 	"github.com/maximba/kubernetes-operator/pkg/plugins"
@@ -15,11 +19,13 @@ import (
 	stackerr "github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
 var (
-	dockerImageRegexp = regexp.MustCompile(`^` + docker.TagRegexp.String() + `$`)
+	dockerImageRegexp         = regexp.MustCompile(`^` + docker.TagRegexp.String() + `$`)
+	kubernetesCloudNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
 )
 
 // Validate validates Jenkins CR Spec.master section
@@ -36,6 +42,12 @@ func (r *JenkinsBaseConfigurationReconciler) Validate(jenkins *v1alpha2.Jenkins)
 		messages = append(messages, msg...)
 	}
 
+	if msg, err := r.validateJenkinsHomePersistentVolumeClaim(jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim); err != nil {
+		return nil, err
+	} else if len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
 	for _, container := range jenkins.Spec.Master.Containers {
 		if msg := r.validateContainer(container); len(msg) > 0 {
 			for _, m := range msg {
@@ -44,14 +56,105 @@ func (r *JenkinsBaseConfigurationReconciler) Validate(jenkins *v1alpha2.Jenkins)
 		}
 	}
 
-	if msg := r.validatePlugins(plugins.BasePlugins(), jenkins.Spec.Master.BasePlugins, jenkins.Spec.Master.Plugins); len(msg) > 0 {
+	for _, container := range jenkins.Spec.Master.InitContainers {
+		if msg := r.validateContainer(container); len(msg) > 0 {
+			for _, m := range msg {
+				messages = append(messages, fmt.Sprintf("Init container `%s` - %s", container.Name, m))
+			}
+		}
+	}
+
+	if err := r.checkImageArchitectures(jenkins); err != nil {
+		return nil, err
+	}
+
+	for index, trustedCertificates := range jenkins.Spec.Master.TrustedCertificates {
+		if len(trustedCertificates.Name) == 0 {
+			messages = append(messages, fmt.Sprintf("spec.master.trustedCertificates[%d].name is not set", index))
+		}
+	}
+
+	if msg, err := r.validateUpdateCenter(jenkins.Spec.Master.UpdateCenter); err != nil {
+		return nil, err
+	} else if len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
+	if msg := validateBuildRetention(jenkins.Spec.Master.BuildRetention); len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
+	if msg := validateAgentListener(jenkins.Spec); len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
+	if jenkins.Spec.Master.DisableKubernetesCloud && len(jenkins.Spec.SeedJobs) > 0 {
+		messages = append(messages, "spec.master.disableKubernetesCloud can't be used with spec.seedJobs, the seed job agent connects through the slave service")
+	}
+
+	if msg := validateImageRollback(jenkins); len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
+	if msg := validateReadinessGates(jenkins.Spec.Master.ReadinessGates); len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
+	if path := jenkins.Spec.Master.HealthCheckPath; len(path) > 0 && !strings.HasPrefix(path, "/") {
+		messages = append(messages, fmt.Sprintf("spec.master.healthCheckPath '%s' must start with '/'", path))
+	}
+
+	if msg := validateHeapSizing(jenkins); len(msg) > 0 {
 		messages = append(messages, msg...)
 	}
 
+	if msg := validatePodPendingTimeout(jenkins.Spec.Master.PodPendingTimeout); len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
+	if msg := validatePodEventsFilter(jenkins.Spec.Master.PodEventsFilter); len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
+	if msg := v1alpha2.ValidateMutuallyExclusiveFeatures(jenkins.Spec); len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
+	if msg := validateExternalJenkins(jenkins); len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
+	if msg := validateExtraResources(jenkins); len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
+	if msg := validatePrometheusRule(jenkins.Spec.PrometheusRule); len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
+	if msg := validateJobs(jenkins.Spec.Jobs); len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
+	if !jenkins.Spec.Master.SkipBaseConfiguration {
+		if msg := r.validatePlugins(plugins.BasePlugins(), jenkins.Spec.Master.BasePlugins, jenkins.Spec.Master.Plugins); len(msg) > 0 {
+			messages = append(messages, msg...)
+		}
+		if jenkins.Spec.Master.LockPlugins && len(jenkins.Spec.Master.PluginUpgradePolicy) > 0 &&
+			jenkins.Spec.Master.PluginUpgradePolicy != v1alpha2.PinnedPluginUpgradePolicy {
+			messages = append(messages, "spec.master.pluginUpgradePolicy must be Pinned when spec.master.lockPlugins is enabled")
+		}
+	}
+
 	if msg := r.validateJenkinsMasterPodEnvs(); len(msg) > 0 {
 		messages = append(messages, msg...)
 	}
 
+	if msg, err := r.validateCustomization(r.Configuration.Jenkins.Spec.BaseGroovyScripts.Customization, "spec.baseGroovyScripts"); err != nil {
+		return nil, err
+	} else if len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
 	if msg, err := r.validateCustomization(r.Configuration.Jenkins.Spec.GroovyScripts.Customization, "spec.groovyScripts"); err != nil {
 		return nil, err
 	} else if len(msg) > 0 {
@@ -62,14 +165,345 @@ func (r *JenkinsBaseConfigurationReconciler) Validate(jenkins *v1alpha2.Jenkins)
 	} else if len(msg) > 0 {
 		messages = append(messages, msg...)
 	}
+	if msg, err := r.validateConfigurationAsCodeHotReload(); err != nil {
+		return nil, err
+	} else if len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+	if msg, err := r.validateConfigurationAsCodeGitRepository(); err != nil {
+		return nil, err
+	} else if len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
+	if msg, err := r.validateValues(jenkins.Spec.Values); err != nil {
+		return nil, err
+	} else if len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
 
 	if jenkins.Spec.JenkinsAPISettings.AuthorizationStrategy != v1alpha2.CreateUserAuthorizationStrategy && jenkins.Spec.JenkinsAPISettings.AuthorizationStrategy != v1alpha2.ServiceAccountAuthorizationStrategy {
 		messages = append(messages, fmt.Sprintf("unrecognized '%s' spec.jenkinsAPISettings.authorizationStrategy", jenkins.Spec.JenkinsAPISettings.AuthorizationStrategy))
 	}
+	if msg := validateJenkinsAPIConnectionSettings(jenkins.Spec.JenkinsAPISettings); len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+	if msg, err := r.validateAuthProxy(jenkins.Spec.JenkinsAPISettings.AuthProxy); err != nil {
+		return nil, err
+	} else if len(msg) > 0 {
+		messages = append(messages, msg...)
+	}
+
+	return messages, nil
+}
+
+func (r *JenkinsBaseConfigurationReconciler) validateUpdateCenter(updateCenter *v1alpha2.UpdateCenter) ([]string, error) {
+	if updateCenter == nil {
+		return nil, nil
+	}
+
+	var messages []string
+	if parsedURL, err := url.Parse(updateCenter.URL); err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		messages = append(messages, fmt.Sprintf("spec.master.updateCenter.url '%s' must be a valid HTTP(S) URL", updateCenter.URL))
+	}
+
+	if updateCenter.ClientCertificate == nil {
+		return messages, nil
+	}
+	secret := &corev1.Secret{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: updateCenter.ClientCertificate.Name, Namespace: r.Configuration.Jenkins.ObjectMeta.Namespace}, secret)
+	if err != nil && apierrors.IsNotFound(err) {
+		return append(messages, fmt.Sprintf("Secret '%s' defined in spec.master.updateCenter.clientCertificate not found", updateCenter.ClientCertificate.Name)), nil
+	} else if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+		if len(secret.Data[key]) == 0 {
+			messages = append(messages, fmt.Sprintf("Secret '%s' defined in spec.master.updateCenter.clientCertificate must contain '%s'", updateCenter.ClientCertificate.Name, key))
+		}
+	}
 
 	return messages, nil
 }
 
+func validateJenkinsAPIConnectionSettings(settings v1alpha2.JenkinsAPISettings) []string {
+	if settings.Hostname == "" && settings.Port == 0 && !settings.UseNodePort {
+		return nil
+	}
+	connectionSettings := jenkinsclient.JenkinsAPIConnectionSettings{Hostname: settings.Hostname, Port: settings.Port, UseNodePort: settings.UseNodePort}
+	if err := connectionSettings.Validate(); err != nil {
+		return []string{fmt.Sprintf("spec.jenkinsAPISettings: %s", err)}
+	}
+	return nil
+}
+
+func (r *JenkinsBaseConfigurationReconciler) validateAuthProxy(authProxy *v1alpha2.AuthProxy) ([]string, error) {
+	if authProxy == nil {
+		return nil, nil
+	}
+
+	var messages []string
+	if authProxy.BypassPort < 0 || authProxy.BypassPort > 65535 {
+		messages = append(messages, fmt.Sprintf("spec.jenkinsAPISettings.authProxy.bypassPort '%d' is not a valid port", authProxy.BypassPort))
+	}
+	if len(authProxy.HeaderName) == 0 && authProxy.HeaderValueSecretKeySelector == nil {
+		if authProxy.BypassPort == 0 {
+			messages = append(messages, "spec.jenkinsAPISettings.authProxy requires headerName and headerValueSecretKeySelector or bypassPort to be set")
+		}
+		return messages, nil
+	}
+	if len(authProxy.HeaderName) == 0 || authProxy.HeaderValueSecretKeySelector == nil {
+		return append(messages, "spec.jenkinsAPISettings.authProxy requires both headerName and headerValueSecretKeySelector to be set"), nil
+	}
+	if strings.EqualFold(authProxy.HeaderName, "Authorization") {
+		messages = append(messages, "spec.jenkinsAPISettings.authProxy.headerName can't be 'Authorization', it's used to authenticate in Jenkins")
+	}
+
+	selector := authProxy.HeaderValueSecretKeySelector
+	secret := &corev1.Secret{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: selector.Name, Namespace: r.Configuration.Jenkins.ObjectMeta.Namespace}, secret)
+	if err != nil && apierrors.IsNotFound(err) {
+		return append(messages, fmt.Sprintf("Secret '%s' defined in spec.jenkinsAPISettings.authProxy.headerValueSecretKeySelector not found", selector.Name)), nil
+	} else if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	if len(secret.Data[selector.Key]) == 0 {
+		messages = append(messages, fmt.Sprintf("Secret '%s' defined in spec.jenkinsAPISettings.authProxy.headerValueSecretKeySelector must contain '%s'", selector.Name, selector.Key))
+	}
+
+	return messages, nil
+}
+
+// insecureAgentProtocols are the agent protocols disabled by the base configuration
+var insecureAgentProtocols = map[string]bool{"JNLP-connect": true, "JNLP2-connect": true, "JNLP3-connect": true, "CLI-connect": true}
+
+func validateAgentListener(spec v1alpha2.JenkinsSpec) []string {
+	listener := spec.Master.AgentListener
+	if listener == nil {
+		return nil
+	}
+
+	var messages []string
+	if len(spec.SeedJobs) > 0 && len(listener.PortPolicy) > 0 && listener.PortPolicy != v1alpha2.FixedAgentPortPolicy {
+		messages = append(messages, fmt.Sprintf("spec.master.agentListener.portPolicy must be '%s' when spec.seedJobs are set, the seed job agent connects through the slave service", v1alpha2.FixedAgentPortPolicy))
+	}
+	if listener.Port != 0 && len(listener.PortPolicy) > 0 && listener.PortPolicy != v1alpha2.FixedAgentPortPolicy {
+		messages = append(messages, fmt.Sprintf("spec.master.agentListener.port can be set only with '%s' port policy", v1alpha2.FixedAgentPortPolicy))
+	}
+	if listener.Port < 0 || listener.Port > 65535 {
+		messages = append(messages, fmt.Sprintf("spec.master.agentListener.port '%d' is out of range", listener.Port))
+	}
+	if listener.Port == constants.DefaultHTTPPortInt32 {
+		messages = append(messages, fmt.Sprintf("spec.master.agentListener.port can't be the Jenkins HTTP port %d", constants.DefaultHTTPPortInt32))
+	}
+	for _, protocol := range listener.Protocols {
+		if insecureAgentProtocols[protocol] {
+			messages = append(messages, fmt.Sprintf("spec.master.agentListener.protocols: insecure protocol '%s' can't be enabled", protocol))
+		}
+	}
+	return messages
+}
+
+func validateReadinessGates(gates []v1alpha2.ReadinessGate) []string {
+	var messages []string
+	names := map[string]bool{}
+	for i, gate := range gates {
+		if len(gate.Name) == 0 {
+			messages = append(messages, fmt.Sprintf("spec.master.readinessGates[%d].name is empty", i))
+		} else if names[gate.Name] {
+			messages = append(messages, fmt.Sprintf("spec.master.readinessGates: duplicated name '%s'", gate.Name))
+		}
+		names[gate.Name] = true
+
+		if (gate.HTTPGet == nil) == (len(gate.Plugins) == 0) {
+			messages = append(messages, fmt.Sprintf("spec.master.readinessGates[%d]: exactly one of httpGet and plugins has to be set", i))
+			continue
+		}
+		if gate.HTTPGet != nil {
+			if gate.HTTPGet.Port <= 0 || gate.HTTPGet.Port > 65535 {
+				messages = append(messages, fmt.Sprintf("spec.master.readinessGates[%d].httpGet.port '%d' is out of range", i, gate.HTTPGet.Port))
+			}
+			if scheme := gate.HTTPGet.Scheme; len(scheme) > 0 && scheme != corev1.URISchemeHTTP && scheme != corev1.URISchemeHTTPS {
+				messages = append(messages, fmt.Sprintf("spec.master.readinessGates[%d].httpGet.scheme '%s' is invalid, allowed are HTTP and HTTPS", i, scheme))
+			}
+		}
+	}
+	return messages
+}
+
+func validateBuildRetention(retention *v1alpha2.BuildRetention) []string {
+	if retention == nil {
+		return nil
+	}
+
+	var messages []string
+	values := []struct {
+		name  string
+		value int
+	}{
+		{"daysToKeep", retention.DaysToKeep},
+		{"numToKeep", retention.NumToKeep},
+		{"artifactDaysToKeep", retention.ArtifactDaysToKeep},
+		{"artifactNumToKeep", retention.ArtifactNumToKeep},
+	}
+	limited := false
+	for _, v := range values {
+		if v.value < 0 {
+			messages = append(messages, fmt.Sprintf("spec.master.buildRetention.%s can't be negative", v.name))
+		} else if v.value > 0 {
+			limited = true
+		}
+	}
+	if len(messages) == 0 && !limited {
+		messages = append(messages, "spec.master.buildRetention requires at least one limit to be set")
+	}
+
+	return messages
+}
+
+func validateHeapSizing(jenkins *v1alpha2.Jenkins) []string {
+	heapSizing := jenkins.Spec.Master.HeapSizing
+	if heapSizing == nil {
+		return nil
+	}
+
+	var messages []string
+	if heapSizing.MaxPercentage < 1 || heapSizing.MaxPercentage > 100 {
+		messages = append(messages, "spec.master.heapSizing.maxPercentage must be between 1 and 100")
+	}
+	if heapSizing.InitialPercentage < 0 || heapSizing.InitialPercentage > heapSizing.MaxPercentage {
+		messages = append(messages, "spec.master.heapSizing.initialPercentage must be between 0 and maxPercentage")
+	}
+	if len(jenkins.Spec.Master.Containers) > 0 {
+		resources := jenkins.Spec.Master.Containers[0].Resources
+		if resources.Limits.Memory().IsZero() && resources.Requests.Memory().IsZero() {
+			messages = append(messages, "spec.master.heapSizing requires memory limit or request of Jenkins master container")
+		}
+	}
+	return messages
+}
+
+// validatePodPendingTimeout rejects a zero timeout which would stop the reconcile loop as soon as the pod is pending
+func validatePodPendingTimeout(timeout *metav1.Duration) []string {
+	if timeout == nil || timeout.Duration > 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("spec.master.podPendingTimeout must be greater than zero, got '%s'", timeout.Duration)}
+}
+
+func validatePodEventsFilter(filter *v1alpha2.PodEventsFilter) []string {
+	if filter == nil {
+		return nil
+	}
+
+	var messages []string
+	if filter.LookbackWindow != nil && filter.LookbackWindow.Duration < 0 {
+		messages = append(messages, "spec.master.podEventsFilter.lookbackWindow can't be negative")
+	}
+	if len(filter.MessageRegex) > 0 {
+		if _, err := regexp.Compile(filter.MessageRegex); err != nil {
+			messages = append(messages, fmt.Sprintf("spec.master.podEventsFilter.messageRegex is invalid: %s", err))
+		}
+	}
+	return messages
+}
+
+func validatePrometheusRule(rule *v1alpha2.PrometheusRule) []string {
+	if rule == nil {
+		return nil
+	}
+
+	var messages []string
+	durations := []struct {
+		name     string
+		duration *metav1.Duration
+	}{
+		{"backupStaleAfter", rule.BackupStaleAfter},
+		{"notReadyFor", rule.NotReadyFor},
+		{"restartStormWindow", rule.RestartStormWindow},
+	}
+	for _, d := range durations {
+		if d.duration != nil && d.duration.Duration < time.Second {
+			messages = append(messages, fmt.Sprintf("spec.prometheusRule.%s must be at least 1s, got '%s'", d.name, d.duration.Duration))
+		}
+	}
+	if rule.RestartStormRestarts < 0 {
+		messages = append(messages, "spec.prometheusRule.restartStormRestarts can't be negative")
+	}
+	return messages
+}
+
+func validateExtraResources(jenkins *v1alpha2.Jenkins) []string {
+	var messages []string
+	for index, extraResource := range jenkins.Spec.ExtraResources {
+		if (extraResource.Manifest == nil) == (extraResource.ConfigMapRef == nil) {
+			messages = append(messages, fmt.Sprintf("spec.extraResources[%d] requires exactly one of manifest and configMapRef to be set", index))
+			continue
+		}
+		if extraResource.ConfigMapRef != nil && len(extraResource.ConfigMapRef.Name) == 0 {
+			messages = append(messages, fmt.Sprintf("spec.extraResources[%d].configMapRef.name is not set", index))
+		}
+		if extraResource.Manifest == nil {
+			continue
+		}
+		objects, err := decodeExtraResources(string(extraResource.Manifest.Raw))
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("spec.extraResources[%d].manifest is invalid: %s", index, err))
+			continue
+		}
+		for _, object := range objects {
+			if namespace := object.GetNamespace(); len(namespace) > 0 && namespace != jenkins.Namespace {
+				messages = append(messages, fmt.Sprintf("spec.extraResources[%d].manifest namespace '%s' differs from Jenkins CR namespace", index, namespace))
+			}
+			if err := validateExtraResourceKind(object); err != nil {
+				messages = append(messages, fmt.Sprintf("spec.extraResources[%d].manifest %s", index, err))
+			}
+		}
+	}
+	return messages
+}
+
+func validateJobs(jobs v1alpha2.Jobs) []string {
+	var messages []string
+	folders := map[string]bool{}
+	for index, folder := range jobs.Folders {
+		if len(folder.Name) == 0 {
+			messages = append(messages, fmt.Sprintf("spec.jobs.folders[%d].name is not set", index))
+		} else if strings.ContainsAny(folder.Name, "/\\") {
+			messages = append(messages, fmt.Sprintf("spec.jobs.folders[%d].name '%s' can't contain '/' or '\\'", index, folder.Name))
+		} else if folders[folder.Name] {
+			messages = append(messages, fmt.Sprintf("spec.jobs.folders[%d].name '%s' is duplicated", index, folder.Name))
+		}
+		folders[folder.Name] = true
+	}
+
+	views := map[string]bool{}
+	for index, view := range jobs.Views {
+		if len(view.Name) == 0 {
+			messages = append(messages, fmt.Sprintf("spec.jobs.views[%d].name is not set", index))
+		} else if views[view.Name] {
+			messages = append(messages, fmt.Sprintf("spec.jobs.views[%d].name '%s' is duplicated", index, view.Name))
+		}
+		views[view.Name] = true
+	}
+
+	if jobs.DefaultViews != nil && !jobs.DefaultViews.Disabled {
+		seedJobs, nonSeedJobs := resources.GetDefaultViews(jobs.DefaultViews, nil)
+		names := []string{seedJobs.Name, nonSeedJobs.Name}
+		if seedJobs.Name == nonSeedJobs.Name {
+			messages = append(messages, fmt.Sprintf("spec.jobs.defaultViews seedJobs and nonSeedJobs views have the same name '%s'", seedJobs.Name))
+			names = names[:1]
+		}
+		for _, name := range names {
+			if views[name] {
+				messages = append(messages, fmt.Sprintf("spec.jobs.defaultViews view '%s' is also defined in spec.jobs.views", name))
+			}
+		}
+	}
+
+	return messages
+}
+
 func (r *JenkinsBaseConfigurationReconciler) validateJenkinsMasterContainerCommand() []string {
 	masterContainer := r.Configuration.GetJenkinsMasterContainer()
 	if masterContainer == nil {
@@ -218,6 +652,52 @@ func (r *JenkinsBaseConfigurationReconciler) validateSecretVolume(volume corev1.
 	return messages, nil
 }
 
+func (r *JenkinsBaseConfigurationReconciler) validateJenkinsHomePersistentVolumeClaim(config *v1alpha2.JenkinsHomePersistentVolumeClaim) ([]string, error) {
+	if config == nil {
+		return nil, nil
+	}
+	if config.Size.Sign() <= 0 {
+		return []string{"spec.master.jenkinsHomePersistentVolumeClaim.size must be greater than zero"}, nil
+	}
+
+	claim := &corev1.PersistentVolumeClaim{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: resources.GetJenkinsHomePersistentVolumeClaimName(r.Configuration.Jenkins), Namespace: r.Configuration.Jenkins.ObjectMeta.Namespace}, claim)
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+
+	var messages []string
+	if requested := claim.Spec.Resources.Requests[corev1.ResourceStorage]; config.Size.Cmp(requested) < 0 {
+		messages = append(messages, fmt.Sprintf("spec.master.jenkinsHomePersistentVolumeClaim.size can't be decreased from %s to %s", requested.String(), config.Size.String()))
+	}
+	storageClassChanged := config.StorageClassName != nil && (claim.Spec.StorageClassName == nil || *claim.Spec.StorageClassName != *config.StorageClassName)
+	if storageClassChanged && useDeploymentForJenkinsMaster(r.Configuration.Jenkins) {
+		messages = append(messages, "spec.master.jenkinsHomePersistentVolumeClaim.storageClassName can't be changed when Jenkins master is managed by Deployment")
+	}
+	return messages, nil
+}
+
+func validateImageRollback(jenkins *v1alpha2.Jenkins) []string {
+	config := jenkins.Spec.Master.ImageRollback
+	if config == nil {
+		return nil
+	}
+
+	var messages []string
+	if jenkins.Spec.Master.JenkinsHomePersistentVolumeClaim == nil {
+		messages = append(messages, "spec.master.imageRollback requires spec.master.jenkinsHomePersistentVolumeClaim")
+	}
+	if config.Window.Duration <= 0 {
+		messages = append(messages, "spec.master.imageRollback.window must be greater than zero")
+	}
+	if useDeploymentForJenkinsMaster(jenkins) {
+		messages = append(messages, "spec.master.imageRollback can't be used when Jenkins master is managed by Deployment")
+	}
+	return messages
+}
+
 func (r *JenkinsBaseConfigurationReconciler) validateReservedVolumes() []string {
 	var messages []string
 
@@ -377,7 +857,9 @@ func (r *JenkinsBaseConfigurationReconciler) validateCustomization(customization
 	if len(customization.Secret.Name) == 0 && len(customization.Configurations) == 0 {
 		return nil, nil
 	}
-	if len(customization.Secret.Name) > 0 && len(customization.Configurations) == 0 {
+	// the Configuration as Code Secret can be used by the YAML files pulled from the Git repository only
+	gitRepository := name == "spec.configurationAsCode" && r.Configuration.Jenkins.Spec.ConfigurationAsCode.GitRepository != nil
+	if len(customization.Secret.Name) > 0 && len(customization.Configurations) == 0 && !gitRepository {
 		messages = append(messages, fmt.Sprintf("%s.secret.name is set but %s.configurations is empty", name, name))
 	}
 
@@ -408,3 +890,118 @@ func (r *JenkinsBaseConfigurationReconciler) validateCustomization(customization
 
 	return messages, nil
 }
+
+// validateConfigurationAsCodeHotReload verifies the Configuration as Code ConfigMaps can be projected into a single
+// directory of Jenkins master pod and read by Jenkins as they are
+func (r *JenkinsBaseConfigurationReconciler) validateConfigurationAsCodeHotReload() ([]string, error) {
+	if !resources.IsConfigurationAsCodeHotReload(r.Configuration.Jenkins) {
+		return nil, nil
+	}
+
+	var messages []string
+	if r.Configuration.Jenkins.Spec.ConfigurationAsCode.Templating {
+		messages = append(messages, fmt.Sprintf("spec.configurationAsCode.templating can't be used with spec.configurationAsCode.reloadStrategy '%s', the mounted ConfigMaps aren't rendered", v1alpha2.HotReloadReloadStrategy))
+	}
+	keys := map[string]string{}
+	for _, configMapRef := range resources.GetConfigurationAsCodeCustomization(r.Configuration.Jenkins).Configurations {
+		configMap := &corev1.ConfigMap{}
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: configMapRef.Name, Namespace: r.Configuration.Jenkins.ObjectMeta.Namespace}, configMap)
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, stackerr.WithStack(err)
+		}
+		for key := range configMap.Data {
+			if otherConfigMap, ok := keys[key]; ok && otherConfigMap != configMap.Name {
+				messages = append(messages, fmt.Sprintf("Key '%s' is in ConfigMaps '%s' and '%s' configured in spec.configurationAsCode.configurations, the keys must be unique with spec.configurationAsCode.reloadStrategy '%s'",
+					key, otherConfigMap, configMap.Name, v1alpha2.HotReloadReloadStrategy))
+				continue
+			}
+			keys[key] = configMap.Name
+		}
+	}
+	sort.Strings(messages)
+	return messages, nil
+}
+
+// validateConfigurationAsCodeGitRepository verifies the Git repository with Configuration as Code can be pulled in
+// Jenkins master pod
+func (r *JenkinsBaseConfigurationReconciler) validateConfigurationAsCodeGitRepository() ([]string, error) {
+	jenkins := r.Configuration.Jenkins
+	repository := jenkins.Spec.ConfigurationAsCode.GitRepository
+	if repository == nil {
+		return nil, nil
+	}
+
+	var messages []string
+	if len(repository.URL) == 0 {
+		messages = append(messages, "spec.configurationAsCode.gitRepository.url is empty")
+	} else if repositoryURL, err := url.Parse(repository.URL); err != nil || (repositoryURL.Scheme != "https" && repositoryURL.Scheme != "http") {
+		messages = append(messages, fmt.Sprintf("spec.configurationAsCode.gitRepository.url '%s' must be an HTTP(S) URL", repository.URL))
+	}
+	if strings.HasPrefix(repository.Path, "/") {
+		messages = append(messages, fmt.Sprintf("spec.configurationAsCode.gitRepository.path '%s' must be relative to the root directory of the repository", repository.Path))
+	}
+	for _, element := range strings.Split(repository.Path, "/") {
+		if element == ".." {
+			messages = append(messages, fmt.Sprintf("spec.configurationAsCode.gitRepository.path '%s' can't point outside of the repository", repository.Path))
+			break
+		}
+	}
+	if repository.SyncInterval != nil && repository.SyncInterval.Duration < time.Minute {
+		messages = append(messages, fmt.Sprintf("spec.configurationAsCode.gitRepository.syncInterval must be at least 1m, got '%s'", repository.SyncInterval.Duration))
+	}
+	if useDeploymentForJenkinsMaster(jenkins) {
+		messages = append(messages, "spec.configurationAsCode.gitRepository can't be used when Jenkins master is managed by Deployment")
+	}
+
+	if repository.CredentialsSecret != nil {
+		secret := &corev1.Secret{}
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: repository.CredentialsSecret.Name, Namespace: jenkins.ObjectMeta.Namespace}, secret)
+		if apierrors.IsNotFound(err) {
+			messages = append(messages, fmt.Sprintf("Secret '%s' configured in spec.configurationAsCode.gitRepository.credentialsSecret not found", repository.CredentialsSecret.Name))
+		} else if err != nil {
+			return nil, stackerr.WithStack(err)
+		} else {
+			for _, key := range []string{"username", "password"} {
+				if len(secret.Data[key]) == 0 {
+					messages = append(messages, fmt.Sprintf("Secret '%s' configured in spec.configurationAsCode.gitRepository.credentialsSecret has no '%s' key", secret.Name, key))
+				}
+			}
+		}
+	}
+	return messages, nil
+}
+
+func (r *JenkinsBaseConfigurationReconciler) validateValues(values *v1alpha2.ConfigMapRef) ([]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+	if len(values.Name) == 0 {
+		return []string{"spec.values.name is empty"}, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: values.Name, Namespace: r.Configuration.Jenkins.ObjectMeta.Namespace}, configMap)
+	if err != nil && apierrors.IsNotFound(err) {
+		return []string{fmt.Sprintf("ConfigMap '%s' configured in spec.values not found", values.Name)}, nil
+	} else if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+
+	var messages []string
+	if _, err := resources.GetNumExecutors(configMap.Data); err != nil {
+		messages = append(messages, err.Error())
+	}
+	if name, found := configMap.Data[resources.KubernetesCloudNameValue]; found && !kubernetesCloudNameRegexp.MatchString(name) {
+		messages = append(messages, fmt.Sprintf("spec.values key '%s' must match '%s', got '%s'", resources.KubernetesCloudNameValue, kubernetesCloudNameRegexp, name))
+	}
+	for _, key := range []string{resources.SeedJobsViewRegexValue, resources.NonSeedJobsViewRegexValue} {
+		if expression, found := configMap.Data[key]; found {
+			if _, err := regexp.Compile(expression); err != nil {
+				messages = append(messages, fmt.Sprintf("spec.values key '%s' is not a valid regular expression: %s", key, err))
+			}
+		}
+	}
+	return messages, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
 	"github.com/maximba/kubernetes-operator/pkg/client"
@@ -16,7 +17,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -944,3 +947,692 @@ func TestValidateJenkinsMasterContainerCommand(t *testing.T) {
 		assert.Len(t, got, 1)
 	})
 }
+
+func TestValidateJobs(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		jobs := v1alpha2.Jobs{
+			Folders: []v1alpha2.Folder{{Name: "team-a"}, {Name: "team-b", Description: "Team B"}},
+			Views:   []v1alpha2.View{{Name: "team-a", IncludeRegex: "team-a/.*"}},
+		}
+
+		got := validateJobs(jobs)
+
+		assert.Len(t, got, 0)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		jobs := v1alpha2.Jobs{
+			Folders: []v1alpha2.Folder{{Name: ""}, {Name: "team/a"}, {Name: "team-b"}, {Name: "team-b"}},
+			Views:   []v1alpha2.View{{Name: ""}, {Name: "all-teams"}, {Name: "all-teams"}},
+		}
+
+		got := validateJobs(jobs)
+
+		assert.Equal(t, []string{
+			"spec.jobs.folders[0].name is not set",
+			"spec.jobs.folders[1].name 'team/a' can't contain '/' or '\\'",
+			"spec.jobs.folders[3].name 'team-b' is duplicated",
+			"spec.jobs.views[0].name is not set",
+			"spec.jobs.views[2].name 'all-teams' is duplicated",
+		}, got)
+	})
+	t.Run("invalid default views", func(t *testing.T) {
+		jobs := v1alpha2.Jobs{
+			Views: []v1alpha2.View{{Name: "jobs"}},
+			DefaultViews: &v1alpha2.DefaultViews{
+				SeedJobs:    &v1alpha2.DefaultView{Name: "jobs"},
+				NonSeedJobs: &v1alpha2.DefaultView{Name: "jobs"},
+			},
+		}
+
+		got := validateJobs(jobs)
+
+		assert.Equal(t, []string{
+			"spec.jobs.defaultViews seedJobs and nonSeedJobs views have the same name 'jobs'",
+			"spec.jobs.defaultViews view 'jobs' is also defined in spec.jobs.views",
+		}, got)
+	})
+	t.Run("disabled default views", func(t *testing.T) {
+		jobs := v1alpha2.Jobs{
+			Views:        []v1alpha2.View{{Name: "seed-jobs"}},
+			DefaultViews: &v1alpha2.DefaultViews{Disabled: true},
+		}
+
+		got := validateJobs(jobs)
+
+		assert.Len(t, got, 0)
+	})
+}
+
+func TestValidateBuildRetention(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		assert.Len(t, validateBuildRetention(nil), 0)
+	})
+	t.Run("valid", func(t *testing.T) {
+		got := validateBuildRetention(&v1alpha2.BuildRetention{DaysToKeep: 30, ArtifactNumToKeep: 5})
+
+		assert.Len(t, got, 0)
+	})
+	t.Run("negative value", func(t *testing.T) {
+		got := validateBuildRetention(&v1alpha2.BuildRetention{DaysToKeep: 30, NumToKeep: -1})
+
+		assert.Equal(t, []string{"spec.master.buildRetention.numToKeep can't be negative"}, got)
+	})
+	t.Run("without limits", func(t *testing.T) {
+		got := validateBuildRetention(&v1alpha2.BuildRetention{})
+
+		assert.Equal(t, []string{"spec.master.buildRetention requires at least one limit to be set"}, got)
+	})
+}
+
+func TestValidateAgentListener(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		assert.Len(t, validateAgentListener(v1alpha2.JenkinsSpec{}), 0)
+	})
+	t.Run("valid", func(t *testing.T) {
+		spec := v1alpha2.JenkinsSpec{Master: v1alpha2.JenkinsMaster{AgentListener: &v1alpha2.AgentListener{
+			PortPolicy: v1alpha2.FixedAgentPortPolicy,
+			Port:       50001,
+			Protocols:  []string{"JNLP4-connect", "Ping"},
+		}}}
+
+		assert.Len(t, validateAgentListener(spec), 0)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		spec := v1alpha2.JenkinsSpec{
+			Master: v1alpha2.JenkinsMaster{AgentListener: &v1alpha2.AgentListener{
+				PortPolicy: v1alpha2.DisabledAgentPortPolicy,
+				Port:       8080,
+				Protocols:  []string{"JNLP4-connect", "JNLP2-connect"},
+			}},
+			SeedJobs: []v1alpha2.SeedJob{{ID: "jenkins-operator"}},
+		}
+
+		assert.Equal(t, []string{
+			"spec.master.agentListener.portPolicy must be 'Fixed' when spec.seedJobs are set, the seed job agent connects through the slave service",
+			"spec.master.agentListener.port can be set only with 'Fixed' port policy",
+			"spec.master.agentListener.port can't be the Jenkins HTTP port 8080",
+			"spec.master.agentListener.protocols: insecure protocol 'JNLP2-connect' can't be enabled",
+		}, validateAgentListener(spec))
+	})
+}
+
+func TestValidateReadinessGates(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		gates := []v1alpha2.ReadinessGate{
+			{Name: "sidecar", HTTPGet: &v1alpha2.ReadinessGateHTTPGet{Path: "/ready", Port: 8081, Scheme: corev1.URISchemeHTTPS}},
+			{Name: "plugins", Plugins: []string{"kubernetes"}},
+		}
+
+		assert.Len(t, validateReadinessGates(gates), 0)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		gates := []v1alpha2.ReadinessGate{
+			{Name: "sidecar", HTTPGet: &v1alpha2.ReadinessGateHTTPGet{Port: 0, Scheme: "TCP"}},
+			{Name: "sidecar", Plugins: []string{"kubernetes"}},
+			{HTTPGet: &v1alpha2.ReadinessGateHTTPGet{Port: 8081}, Plugins: []string{"kubernetes"}},
+		}
+
+		assert.Equal(t, []string{
+			"spec.master.readinessGates[0].httpGet.port '0' is out of range",
+			"spec.master.readinessGates[0].httpGet.scheme 'TCP' is invalid, allowed are HTTP and HTTPS",
+			"spec.master.readinessGates: duplicated name 'sidecar'",
+			"spec.master.readinessGates[2].name is empty",
+			"spec.master.readinessGates[2]: exactly one of httpGet and plugins has to be set",
+		}, validateReadinessGates(gates))
+	})
+}
+
+func TestValidateJenkinsAPIConnectionSettings(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		assert.Len(t, validateJenkinsAPIConnectionSettings(v1alpha2.JenkinsAPISettings{}), 0)
+	})
+	t.Run("valid", func(t *testing.T) {
+		got := validateJenkinsAPIConnectionSettings(v1alpha2.JenkinsAPISettings{Hostname: "jenkins.example.com", Port: 8080})
+
+		assert.Len(t, got, 0)
+	})
+	t.Run("port and nodePort", func(t *testing.T) {
+		got := validateJenkinsAPIConnectionSettings(v1alpha2.JenkinsAPISettings{Hostname: "jenkins.example.com", Port: 8080, UseNodePort: true})
+
+		assert.Equal(t, []string{"spec.jenkinsAPISettings: can't use service port and nodePort both. Please use port or nodePort"}, got)
+	})
+	t.Run("without hostname", func(t *testing.T) {
+		got := validateJenkinsAPIConnectionSettings(v1alpha2.JenkinsAPISettings{Port: 8080})
+
+		assert.Equal(t, []string{"spec.jenkinsAPISettings: empty hostname is now allowed. Please provide hostname"}, got)
+	})
+}
+
+func TestValidateUpdateCenter(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+
+	t.Run("not set", func(t *testing.T) {
+		baseReconcileLoop := New(configuration.Configuration{Client: fake.NewClientBuilder().Build(), Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+
+		got, err := baseReconcileLoop.validateUpdateCenter(nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 0)
+	})
+	t.Run("valid with client certificate", func(t *testing.T) {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "uc-client", Namespace: jenkins.Namespace},
+			Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+		}
+		fakeClient := fake.NewClientBuilder().WithObjects(secret).Build()
+		baseReconcileLoop := New(configuration.Configuration{Client: fakeClient, Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+
+		got, err := baseReconcileLoop.validateUpdateCenter(&v1alpha2.UpdateCenter{
+			URL:               "https://updates.example.com/update-center.json",
+			ClientCertificate: &v1alpha2.SecretRef{Name: secret.Name},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 0)
+	})
+	t.Run("invalid URL and secret without private key", func(t *testing.T) {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "uc-client", Namespace: jenkins.Namespace},
+			Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert")},
+		}
+		fakeClient := fake.NewClientBuilder().WithObjects(secret).Build()
+		baseReconcileLoop := New(configuration.Configuration{Client: fakeClient, Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+
+		got, err := baseReconcileLoop.validateUpdateCenter(&v1alpha2.UpdateCenter{
+			URL:               "updates.example.com",
+			ClientCertificate: &v1alpha2.SecretRef{Name: secret.Name},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"spec.master.updateCenter.url 'updates.example.com' must be a valid HTTP(S) URL",
+			"Secret 'uc-client' defined in spec.master.updateCenter.clientCertificate must contain 'tls.key'",
+		}, got)
+	})
+	t.Run("missing secret", func(t *testing.T) {
+		baseReconcileLoop := New(configuration.Configuration{Client: fake.NewClientBuilder().Build(), Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+
+		got, err := baseReconcileLoop.validateUpdateCenter(&v1alpha2.UpdateCenter{
+			URL:               "https://updates.example.com/update-center.json",
+			ClientCertificate: &v1alpha2.SecretRef{Name: "uc-client"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Secret 'uc-client' defined in spec.master.updateCenter.clientCertificate not found"}, got)
+	})
+}
+
+func TestValidateAuthProxy(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "oauth-proxy", Namespace: jenkins.Namespace},
+		Data:       map[string][]byte{"token": []byte("access-token")},
+	}
+
+	t.Run("not set", func(t *testing.T) {
+		baseReconcileLoop := New(configuration.Configuration{Client: fake.NewClientBuilder().Build(), Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+
+		got, err := baseReconcileLoop.validateAuthProxy(nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 0)
+	})
+	t.Run("valid header", func(t *testing.T) {
+		baseReconcileLoop := New(configuration.Configuration{Client: fake.NewClientBuilder().WithObjects(secret).Build(), Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+
+		got, err := baseReconcileLoop.validateAuthProxy(&v1alpha2.AuthProxy{
+			HeaderName: "X-Forwarded-Access-Token",
+			HeaderValueSecretKeySelector: &v1alpha2.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+				Key:                  "token",
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 0)
+	})
+	t.Run("valid bypass port", func(t *testing.T) {
+		baseReconcileLoop := New(configuration.Configuration{Client: fake.NewClientBuilder().Build(), Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+
+		got, err := baseReconcileLoop.validateAuthProxy(&v1alpha2.AuthProxy{BypassPort: 8081})
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 0)
+	})
+	t.Run("empty", func(t *testing.T) {
+		baseReconcileLoop := New(configuration.Configuration{Client: fake.NewClientBuilder().Build(), Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+
+		got, err := baseReconcileLoop.validateAuthProxy(&v1alpha2.AuthProxy{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"spec.jenkinsAPISettings.authProxy requires headerName and headerValueSecretKeySelector or bypassPort to be set"}, got)
+	})
+	t.Run("Authorization header and missing key", func(t *testing.T) {
+		baseReconcileLoop := New(configuration.Configuration{Client: fake.NewClientBuilder().WithObjects(secret).Build(), Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+
+		got, err := baseReconcileLoop.validateAuthProxy(&v1alpha2.AuthProxy{
+			HeaderName: "authorization",
+			HeaderValueSecretKeySelector: &v1alpha2.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+				Key:                  "missing",
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"spec.jenkinsAPISettings.authProxy.headerName can't be 'Authorization', it's used to authenticate in Jenkins",
+			"Secret 'oauth-proxy' defined in spec.jenkinsAPISettings.authProxy.headerValueSecretKeySelector must contain 'missing'",
+		}, got)
+	})
+	t.Run("missing secret", func(t *testing.T) {
+		baseReconcileLoop := New(configuration.Configuration{Client: fake.NewClientBuilder().Build(), Jenkins: jenkins}, client.JenkinsAPIConnectionSettings{})
+
+		got, err := baseReconcileLoop.validateAuthProxy(&v1alpha2.AuthProxy{
+			HeaderName: "X-Forwarded-Access-Token",
+			HeaderValueSecretKeySelector: &v1alpha2.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+				Key:                  "token",
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Secret 'oauth-proxy' defined in spec.jenkinsAPISettings.authProxy.headerValueSecretKeySelector not found"}, got)
+	})
+}
+
+func TestValidateHeapSizing(t *testing.T) {
+	jenkinsWithMemory := func(heapSizing *v1alpha2.HeapSizing, memory string) *v1alpha2.Jenkins {
+		container := v1alpha2.Container{Name: resources.JenkinsMasterContainerName}
+		if len(memory) > 0 {
+			container.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)}
+		}
+		return &v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				Master: v1alpha2.JenkinsMaster{
+					HeapSizing: heapSizing,
+					Containers: []v1alpha2.Container{container},
+				},
+			},
+		}
+	}
+
+	t.Run("not set", func(t *testing.T) {
+		got := validateHeapSizing(jenkinsWithMemory(nil, ""))
+
+		assert.Len(t, got, 0)
+	})
+	t.Run("valid", func(t *testing.T) {
+		got := validateHeapSizing(jenkinsWithMemory(&v1alpha2.HeapSizing{MaxPercentage: 75, InitialPercentage: 50}, "2Gi"))
+
+		assert.Len(t, got, 0)
+	})
+	t.Run("invalid percentages", func(t *testing.T) {
+		got := validateHeapSizing(jenkinsWithMemory(&v1alpha2.HeapSizing{MaxPercentage: 101, InitialPercentage: 102}, "2Gi"))
+
+		assert.Equal(t, []string{
+			"spec.master.heapSizing.maxPercentage must be between 1 and 100",
+			"spec.master.heapSizing.initialPercentage must be between 0 and maxPercentage",
+		}, got)
+	})
+	t.Run("missing memory", func(t *testing.T) {
+		got := validateHeapSizing(jenkinsWithMemory(&v1alpha2.HeapSizing{MaxPercentage: 75}, ""))
+
+		assert.Equal(t, []string{"spec.master.heapSizing requires memory limit or request of Jenkins master container"}, got)
+	})
+}
+
+func TestValidatePrometheusRule(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		assert.Len(t, validatePrometheusRule(nil), 0)
+	})
+	t.Run("valid", func(t *testing.T) {
+		got := validatePrometheusRule(&v1alpha2.PrometheusRule{
+			NotReadyFor:          &metav1.Duration{Duration: 5 * time.Minute},
+			RestartStormRestarts: 2,
+		})
+
+		assert.Len(t, got, 0)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		got := validatePrometheusRule(&v1alpha2.PrometheusRule{
+			BackupStaleAfter:     &metav1.Duration{Duration: -time.Hour},
+			RestartStormWindow:   &metav1.Duration{Duration: time.Millisecond},
+			RestartStormRestarts: -1,
+		})
+
+		assert.Equal(t, []string{
+			"spec.prometheusRule.backupStaleAfter must be at least 1s, got '-1h0m0s'",
+			"spec.prometheusRule.restartStormWindow must be at least 1s, got '1ms'",
+			"spec.prometheusRule.restartStormRestarts can't be negative",
+		}, got)
+	})
+}
+
+func TestValidatePodPendingTimeout(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		assert.Len(t, validatePodPendingTimeout(nil), 0)
+	})
+	t.Run("valid", func(t *testing.T) {
+		assert.Len(t, validatePodPendingTimeout(&metav1.Duration{Duration: 10 * time.Minute}), 0)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		assert.Equal(t, []string{"spec.master.podPendingTimeout must be greater than zero, got '0s'"},
+			validatePodPendingTimeout(&metav1.Duration{}))
+		assert.Equal(t, []string{"spec.master.podPendingTimeout must be greater than zero, got '-1m0s'"},
+			validatePodPendingTimeout(&metav1.Duration{Duration: -time.Minute}))
+	})
+}
+
+func TestValidatePodEventsFilter(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		got := validatePodEventsFilter(&v1alpha2.PodEventsFilter{
+			LookbackWindow: &metav1.Duration{Duration: time.Minute},
+			MessageRegex:   "Insufficient (cpu|memory)",
+		})
+
+		assert.Len(t, got, 0)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		got := validatePodEventsFilter(&v1alpha2.PodEventsFilter{
+			LookbackWindow: &metav1.Duration{Duration: -time.Minute},
+			MessageRegex:   "Insufficient (cpu",
+		})
+
+		assert.Equal(t, []string{
+			"spec.master.podEventsFilter.lookbackWindow can't be negative",
+			"spec.master.podEventsFilter.messageRegex is invalid: error parsing regexp: missing closing ): `Insufficient (cpu`",
+		}, got)
+	})
+}
+
+func TestValidateExtraResources(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace},
+			Spec: v1alpha2.JenkinsSpec{
+				ExtraResources: []v1alpha2.ExtraResource{
+					{Manifest: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"custom","namespace":"default"}}`)}},
+					{ConfigMapRef: &v1alpha2.ConfigMapRef{Name: "extra-resources"}},
+				},
+			},
+		}
+
+		got := validateExtraResources(jenkins)
+
+		assert.Len(t, got, 0)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace},
+			Spec: v1alpha2.JenkinsSpec{
+				ExtraResources: []v1alpha2.ExtraResource{
+					{},
+					{Manifest: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Service"}`)}},
+					{Manifest: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"custom","namespace":"other"}}`)}},
+					{ConfigMapRef: &v1alpha2.ConfigMapRef{}},
+				},
+			},
+		}
+
+		got := validateExtraResources(jenkins)
+
+		assert.Equal(t, []string{
+			"spec.extraResources[0] requires exactly one of manifest and configMapRef to be set",
+			"spec.extraResources[1].manifest is invalid: apiVersion, kind and metadata.name are required",
+			"spec.extraResources[2].manifest namespace 'other' differs from Jenkins CR namespace",
+			"spec.extraResources[3].configMapRef.name is not set",
+		}, got)
+	})
+	t.Run("rejected kinds", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace},
+			Spec: v1alpha2.JenkinsSpec{
+				ExtraResources: []v1alpha2.ExtraResource{
+					{Manifest: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"RoleBinding","metadata":{"name":"admin"}}`)}},
+					{Manifest: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRoleBinding","metadata":{"name":"cluster-admin"}}`)}},
+					{Manifest: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"other"}}`)}},
+					{Manifest: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"miner"}}`)}},
+					{Manifest: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"token"}}`)}},
+					{Manifest: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"networking.k8s.io/v1","kind":"NetworkPolicy","metadata":{"name":"jenkins"}}`)}},
+				},
+			},
+		}
+
+		got := validateExtraResources(jenkins)
+
+		require.Len(t, got, 5)
+		assert.Equal(t, "spec.extraResources[0].manifest RBAC kind 'RoleBinding.rbac.authorization.k8s.io' can't be applied", got[0])
+		assert.Equal(t, "spec.extraResources[1].manifest RBAC kind 'ClusterRoleBinding.rbac.authorization.k8s.io' can't be applied", got[1])
+		assert.Contains(t, got[2], "spec.extraResources[2].manifest kind 'Namespace' isn't allowed")
+		assert.Contains(t, got[3], "spec.extraResources[3].manifest kind 'Deployment.apps' isn't allowed")
+		assert.Contains(t, got[4], "spec.extraResources[4].manifest kind 'Secret' isn't allowed")
+	})
+}
+
+func TestValidateValues(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace}}
+	newReconciler := func(configMaps ...*corev1.ConfigMap) *JenkinsBaseConfigurationReconciler {
+		clientBuilder := fake.NewClientBuilder()
+		for _, configMap := range configMaps {
+			clientBuilder.WithObjects(configMap)
+		}
+		return New(configuration.Configuration{
+			Jenkins: jenkins,
+			Client:  clientBuilder.Build(),
+		}, client.JenkinsAPIConnectionSettings{})
+	}
+
+	t.Run("not set", func(t *testing.T) {
+		got, err := newReconciler().validateValues(nil)
+
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+	t.Run("ConfigMap not found", func(t *testing.T) {
+		got, err := newReconciler().validateValues(&v1alpha2.ConfigMapRef{Name: "values"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ConfigMap 'values' configured in spec.values not found"}, got)
+	})
+	t.Run("valid", func(t *testing.T) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "values", Namespace: defaultNamespace},
+			Data: map[string]string{
+				resources.NumExecutorsValue:        "2",
+				resources.KubernetesCloudNameValue: "in-cluster",
+				resources.SeedJobsViewRegexValue:   "seed-.*",
+			},
+		}
+
+		got, err := newReconciler(configMap).validateValues(&v1alpha2.ConfigMapRef{Name: "values"})
+
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "values", Namespace: defaultNamespace},
+			Data: map[string]string{
+				resources.NumExecutorsValue:         "-1",
+				resources.KubernetesCloudNameValue:  "in cluster",
+				resources.NonSeedJobsViewRegexValue: "(",
+			},
+		}
+
+		got, err := newReconciler(configMap).validateValues(&v1alpha2.ConfigMapRef{Name: "values"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"spec.values key 'numExecutors' must be a non-negative number, got '-1'",
+			"spec.values key 'kubernetesCloudName' must match '^[a-zA-Z0-9._-]+$', got 'in cluster'",
+			"spec.values key 'nonSeedJobsViewRegex' is not a valid regular expression: error parsing regexp: missing closing ): `(`",
+		}, got)
+	})
+}
+
+func TestValidateExternalJenkins(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		assert.Nil(t, validateExternalJenkins(&v1alpha2.Jenkins{}))
+	})
+	t.Run("valid", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				ExternalJenkins: &v1alpha2.ExternalJenkins{
+					URL:               "https://jenkins.example.com/",
+					CredentialsSecret: v1alpha2.SecretRef{Name: "jenkins-credentials"},
+				},
+				ConfigurationAsCode: v1alpha2.ConfigurationAsCode{Customization: v1alpha2.Customization{
+					Configurations: []v1alpha2.ConfigMapRef{{Name: "casc"}},
+				}},
+			},
+		}
+
+		assert.Nil(t, validateExternalJenkins(jenkins))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				ExternalJenkins:    &v1alpha2.ExternalJenkins{URL: "jenkins.example.com"},
+				JenkinsAPISettings: v1alpha2.JenkinsAPISettings{AuthorizationStrategy: v1alpha2.ServiceAccountAuthorizationStrategy},
+				GroovyScripts:      v1alpha2.GroovyScripts{Customization: v1alpha2.Customization{Secret: v1alpha2.SecretRef{Name: "groovy"}}},
+				ConfigurationAsCode: v1alpha2.ConfigurationAsCode{
+					Customization:  v1alpha2.Customization{Secret: v1alpha2.SecretRef{Name: "casc"}},
+					ReloadStrategy: v1alpha2.HotReloadReloadStrategy,
+				},
+				Backup: v1alpha2.Backup{ContainerName: "backup"},
+			},
+		}
+
+		got := validateExternalJenkins(jenkins)
+
+		assert.Equal(t, []string{
+			"spec.externalJenkins.url 'jenkins.example.com' must be absolute http or https URL",
+			"spec.externalJenkins.credentialsSecret.name is not set",
+			"spec.jenkinsAPISettings.authorizationStrategy 'serviceAccount' can't be used with spec.externalJenkins",
+			"spec.groovyScripts.secret can't be used with spec.externalJenkins, the secret can't be mounted into external Jenkins",
+			"spec.configurationAsCode.secret can't be used with spec.externalJenkins, the secret can't be mounted into external Jenkins",
+			"spec.configurationAsCode.reloadStrategy can't be used with spec.externalJenkins, it requires Jenkins master pod",
+			"spec.backup and spec.restore can't be used with spec.externalJenkins, they are executed in Jenkins master pod",
+		}, got)
+	})
+}
+
+func TestValidateConfigurationAsCodeHotReload(t *testing.T) {
+	newJenkins := func(reloadStrategy v1alpha2.ReloadStrategy, templating bool) *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace},
+			Spec: v1alpha2.JenkinsSpec{ConfigurationAsCode: v1alpha2.ConfigurationAsCode{
+				Customization: v1alpha2.Customization{
+					Configurations: []v1alpha2.ConfigMapRef{{Name: "casc-base"}, {Name: "casc-team"}},
+					Templating:     templating,
+				},
+				ReloadStrategy: reloadStrategy,
+			}},
+		}
+	}
+	baseConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "casc-base", Namespace: defaultNamespace},
+		Data:       map[string]string{"jenkins.yaml": "", "security.yaml": ""},
+	}
+	newReconciler := func(jenkins *v1alpha2.Jenkins, teamConfigMapData map[string]string) *JenkinsBaseConfigurationReconciler {
+		teamConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "casc-team", Namespace: defaultNamespace},
+			Data:       teamConfigMapData,
+		}
+		return New(configuration.Configuration{
+			Jenkins: jenkins,
+			Client:  fake.NewClientBuilder().WithObjects(baseConfigMap, teamConfigMap).Build(),
+		}, client.JenkinsAPIConnectionSettings{})
+	}
+
+	t.Run("restart", func(t *testing.T) {
+		r := newReconciler(newJenkins(v1alpha2.RestartReloadStrategy, true), map[string]string{"jenkins.yaml": ""})
+
+		got, err := r.validateConfigurationAsCodeHotReload()
+
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+	t.Run("valid", func(t *testing.T) {
+		r := newReconciler(newJenkins(v1alpha2.HotReloadReloadStrategy, false), map[string]string{"team.yaml": ""})
+
+		got, err := r.validateConfigurationAsCodeHotReload()
+
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+	t.Run("templating and duplicated keys", func(t *testing.T) {
+		r := newReconciler(newJenkins(v1alpha2.HotReloadReloadStrategy, true), map[string]string{"jenkins.yaml": "", "team.yaml": ""})
+
+		got, err := r.validateConfigurationAsCodeHotReload()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"Key 'jenkins.yaml' is in ConfigMaps 'casc-base' and 'casc-team' configured in spec.configurationAsCode.configurations, the keys must be unique with spec.configurationAsCode.reloadStrategy 'HotReload'",
+			"spec.configurationAsCode.templating can't be used with spec.configurationAsCode.reloadStrategy 'HotReload', the mounted ConfigMaps aren't rendered",
+		}, got)
+	})
+}
+
+func TestValidateConfigurationAsCodeGitRepository(t *testing.T) {
+	newJenkins := func(repository *v1alpha2.ConfigurationAsCodeGitRepository) *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace},
+			Spec: v1alpha2.JenkinsSpec{ConfigurationAsCode: v1alpha2.ConfigurationAsCode{
+				GitRepository: repository,
+			}},
+		}
+	}
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "git-credentials", Namespace: defaultNamespace},
+		Data:       map[string][]byte{"username": []byte("jenkins")},
+	}
+	newReconciler := func(jenkins *v1alpha2.Jenkins) *JenkinsBaseConfigurationReconciler {
+		return New(configuration.Configuration{
+			Jenkins: jenkins,
+			Client:  fake.NewClientBuilder().WithObjects(credentials).Build(),
+		}, client.JenkinsAPIConnectionSettings{})
+	}
+
+	t.Run("not set", func(t *testing.T) {
+		got, err := newReconciler(newJenkins(nil)).validateConfigurationAsCodeGitRepository()
+
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+	t.Run("valid", func(t *testing.T) {
+		got, err := newReconciler(newJenkins(&v1alpha2.ConfigurationAsCodeGitRepository{
+			URL:          "https://github.com/example/jenkins-config.git",
+			Path:         "jenkins/casc",
+			SyncInterval: &metav1.Duration{Duration: 10 * time.Minute},
+		})).validateConfigurationAsCodeGitRepository()
+
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		got, err := newReconciler(newJenkins(&v1alpha2.ConfigurationAsCodeGitRepository{
+			URL:               "git@github.com:example/jenkins-config.git",
+			Path:              "../casc",
+			SyncInterval:      &metav1.Duration{Duration: 10 * time.Second},
+			CredentialsSecret: &v1alpha2.SecretRef{Name: "git-credentials"},
+		})).validateConfigurationAsCodeGitRepository()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"spec.configurationAsCode.gitRepository.url 'git@github.com:example/jenkins-config.git' must be an HTTP(S) URL",
+			"spec.configurationAsCode.gitRepository.path '../casc' can't point outside of the repository",
+			"spec.configurationAsCode.gitRepository.syncInterval must be at least 1m, got '10s'",
+			"Secret 'git-credentials' configured in spec.configurationAsCode.gitRepository.credentialsSecret has no 'password' key",
+		}, got)
+	})
+	t.Run("credentials secret not found", func(t *testing.T) {
+		got, err := newReconciler(newJenkins(&v1alpha2.ConfigurationAsCodeGitRepository{
+			URL:               "https://github.com/example/jenkins-config.git",
+			CredentialsSecret: &v1alpha2.SecretRef{Name: "missing"},
+		})).validateConfigurationAsCodeGitRepository()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Secret 'missing' configured in spec.configurationAsCode.gitRepository.credentialsSecret not found"}, got)
+	})
+}
@@ -0,0 +1,60 @@
+package configuration
+
+import (
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons of Jenkins CR status conditions
+const (
+	ConditionReasonJenkinsMasterPodCreated = "JenkinsMasterPodCreated"
+	ConditionReasonCompleted               = "Completed"
+	ConditionReasonReadinessCheckSucceeded = "ReadinessCheckSucceeded"
+	ConditionReasonReadinessCheckFailed    = "ReadinessCheckFailed"
+	ConditionReasonPluginsMatch            = "PluginsMatch"
+	ConditionReasonPluginsChanged          = "PluginsChanged"
+	ConditionReasonPluginsUpgradeFailed    = "PluginsUpgradeFailed"
+	ConditionReasonBackupSucceeded         = "BackupSucceeded"
+	ConditionReasonBackupFailed            = "BackupFailed"
+	ConditionReasonResourceQuotaSufficient = "ResourceQuotaSufficient"
+	ConditionReasonResourceQuotaExceeded   = "ResourceQuotaExceeded"
+	ConditionReasonReconcileStalled        = "ReconcileStalled"
+)
+
+// configurationConditions are reset when Jenkins master pod is recreated
+var configurationConditions = []string{
+	v1alpha2.ConditionReady,
+	v1alpha2.ConditionBaseConfigurationCompleted,
+	v1alpha2.ConditionUserConfigurationCompleted,
+	v1alpha2.ConditionPluginsVerified,
+}
+
+// SetCondition sets the condition in Jenkins CR status, it returns true if the condition has changed and the status
+// has to be updated
+func SetCondition(jenkins *v1alpha2.Jenkins, conditionType string, status metav1.ConditionStatus, reason, message string) bool {
+	current := meta.FindStatusCondition(jenkins.Status.Conditions, conditionType)
+	if current != nil && current.Status == status && current.Reason == reason && current.Message == message &&
+		current.ObservedGeneration == jenkins.Generation {
+		return false
+	}
+	meta.SetStatusCondition(&jenkins.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: jenkins.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	return true
+}
+
+// ResetConditions keeps the given conditions in the status of recreated Jenkins master pod, the conditions of
+// the configuration phases are set to false until the phases are completed again
+func ResetConditions(jenkins *v1alpha2.Jenkins, conditions []metav1.Condition) {
+	jenkins.Status.Conditions = conditions
+	for _, conditionType := range configurationConditions {
+		SetCondition(jenkins, conditionType, metav1.ConditionFalse, ConditionReasonJenkinsMasterPodCreated,
+			"Jenkins master pod has been created")
+	}
+}
@@ -0,0 +1,59 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetCondition(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+
+	t.Run("new condition", func(t *testing.T) {
+		assert.True(t, SetCondition(jenkins, v1alpha2.ConditionReady, metav1.ConditionTrue, ConditionReasonReadinessCheckSucceeded, "ready"))
+
+		condition := meta.FindStatusCondition(jenkins.Status.Conditions, v1alpha2.ConditionReady)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, int64(1), condition.ObservedGeneration)
+		assert.False(t, condition.LastTransitionTime.IsZero())
+	})
+	t.Run("unchanged condition", func(t *testing.T) {
+		assert.False(t, SetCondition(jenkins, v1alpha2.ConditionReady, metav1.ConditionTrue, ConditionReasonReadinessCheckSucceeded, "ready"))
+	})
+	t.Run("new generation", func(t *testing.T) {
+		jenkins.Generation = 2
+
+		assert.True(t, SetCondition(jenkins, v1alpha2.ConditionReady, metav1.ConditionTrue, ConditionReasonReadinessCheckSucceeded, "ready"))
+		assert.Equal(t, int64(2), meta.FindStatusCondition(jenkins.Status.Conditions, v1alpha2.ConditionReady).ObservedGeneration)
+	})
+	t.Run("changed status", func(t *testing.T) {
+		assert.True(t, SetCondition(jenkins, v1alpha2.ConditionReady, metav1.ConditionFalse, ConditionReasonReadinessCheckFailed, "not ready"))
+		assert.True(t, meta.IsStatusConditionFalse(jenkins.Status.Conditions, v1alpha2.ConditionReady))
+		assert.Len(t, jenkins.Status.Conditions, 1)
+	})
+}
+
+func TestResetConditions(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{}
+	SetCondition(jenkins, v1alpha2.ConditionReady, metav1.ConditionTrue, ConditionReasonReadinessCheckSucceeded, "ready")
+	SetCondition(jenkins, v1alpha2.ConditionBackupHealthy, metav1.ConditionTrue, ConditionReasonBackupSucceeded, "backup")
+	conditions := jenkins.Status.Conditions
+	jenkins.Status = v1alpha2.JenkinsStatus{}
+
+	ResetConditions(jenkins, conditions)
+
+	assert.True(t, meta.IsStatusConditionTrue(jenkins.Status.Conditions, v1alpha2.ConditionBackupHealthy))
+	for _, conditionType := range []string{v1alpha2.ConditionReady, v1alpha2.ConditionBaseConfigurationCompleted,
+		v1alpha2.ConditionUserConfigurationCompleted, v1alpha2.ConditionPluginsVerified} {
+		condition := meta.FindStatusCondition(jenkins.Status.Conditions, conditionType)
+		require.NotNil(t, condition, conditionType)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, ConditionReasonJenkinsMasterPodCreated, condition.Reason)
+	}
+}
@@ -3,11 +3,16 @@ package configuration
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
 	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
 	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
 	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
 
@@ -17,15 +22,22 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/httpstream"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// FieldManager is the server-side apply field manager of the resources managed by the operator
+const FieldManager = "jenkins-operator"
+
 // Configuration holds required for Jenkins configuration.
 type Configuration struct {
 	Client                       client.Client
@@ -36,29 +48,62 @@ type Configuration struct {
 	Config                       *rest.Config
 	JenkinsAPIConnectionSettings jenkinsclient.JenkinsAPIConnectionSettings
 	KubernetesClusterDomain      string
+	Timer                        *ReconcileTimer
+	PendingRestart               *PendingRestart
+	// JenkinsClientOptions are applied to all Jenkins API clients, e.g. to record metrics of the requests
+	JenkinsClientOptions []jenkinsclient.Option
+	// Events records Kubernetes events of the Jenkins CR which aren't sent as notifications, it's optional
+	Events k8sevent.Recorder
 }
 
-// RestartJenkinsMasterPod terminate Jenkins master pod and notifies about it.
-func (c *Configuration) RestartJenkinsMasterPod(reason reason.Reason) error {
+// RestartJenkinsMasterPod terminate Jenkins master pod and notifies about it. The restart is counted in
+// status.podRestarts by the reason source, restarts caused by the platform are notified as warnings. The reasons of
+// pending requested restart are applied by this restart too.
+func (c *Configuration) RestartJenkinsMasterPod(restartReason reason.Reason) error {
 	currentJenkinsMasterPod, err := c.GetJenkinsMasterPod()
 	if err != nil {
 		return err
 	}
+	restartReason = c.PendingRestart.take(restartReason)
 
 	if c.IsJenkinsTerminating(*currentJenkinsMasterPod) {
 		return nil
 	}
 
+	countPodRestart(&c.Jenkins.Status, restartReason.Source())
+	if err = c.Client.Status().Update(context.TODO(), c.Jenkins); err != nil {
+		return stackerr.WithStack(err)
+	}
+
+	level := v1alpha2.NotificationLevelInfo
+	if restartReason.Source() == reason.KubernetesSource {
+		level = v1alpha2.NotificationLevelWarning
+	}
 	*c.Notifications <- event.Event{
 		Jenkins: *c.Jenkins,
 		Phase:   event.PhaseBase,
-		Level:   v1alpha2.NotificationLevelInfo,
-		Reason:  reason,
+		Level:   level,
+		Reason:  restartReason,
 	}
 
 	return stackerr.WithStack(c.Client.Delete(context.TODO(), currentJenkinsMasterPod))
 }
 
+func countPodRestart(status *v1alpha2.JenkinsStatus, source reason.Source) {
+	if status.PodRestarts == nil {
+		status.PodRestarts = &v1alpha2.PodRestarts{}
+	}
+	switch source {
+	case reason.OperatorSource:
+		status.PodRestarts.Operator++
+	case reason.KubernetesSource:
+		status.PodRestarts.Kubernetes++
+	}
+	now := metav1.Now()
+	status.PodRestarts.LastSource = string(source)
+	status.PodRestarts.LastTime = &now
+}
+
 // GetJenkinsMasterPod gets the jenkins master pod.
 func (c *Configuration) GetJenkinsMasterPod() (*corev1.Pod, error) {
 	jenkinsMasterPodName := resources.GetJenkinsMasterPodName(c.Jenkins)
@@ -114,7 +159,11 @@ func (c *Configuration) UpdateResource(obj metav1.Object) error {
 	return c.Client.Update(context.TODO(), clientObj) // don't wrap error
 }
 
-// CreateOrUpdateResource is creating or updating kubernetes resource and references it to Jenkins CR.
+// CreateOrUpdateResource is creating or updating kubernetes resource with server-side apply and references it to
+// Jenkins CR. The operator owns only the fields it sets, fields set by other controllers, e.g. service annotations
+// added by external-dns, are kept and conflicts with them are returned instead of being overwritten. The first apply
+// of an existing resource, e.g. created by an older operator version, takes over the fields set by the operator. The
+// apply is skipped when neither the resource nor the object applied the last time have changed since then.
 func (c *Configuration) CreateOrUpdateResource(obj metav1.Object) error {
 	clientObj, ok := obj.(client.Object)
 	if !ok {
@@ -124,18 +173,150 @@ func (c *Configuration) CreateOrUpdateResource(obj metav1.Object) error {
 	// set Jenkins instance as the owner and controller, don't check error(can be already set)
 	_ = controllerutil.SetControllerReference(c.Jenkins, obj, c.Scheme)
 
-	err := c.Client.Create(context.TODO(), clientObj)
-	if err != nil && errors.IsAlreadyExists(err) {
-		return c.UpdateResource(obj)
-	} else if err != nil && !errors.IsAlreadyExists(err) {
+	gvk, err := apiutil.GVKForObject(clientObj, c.Client.Scheme())
+	if err != nil {
 		return stackerr.WithStack(err)
 	}
+	clientObj.GetObjectKind().SetGroupVersionKind(gvk)
+	clientObj.SetResourceVersion("")
+	clientObj.SetManagedFields(nil)
+
+	hash, err := objectHash(clientObj)
+	if err != nil {
+		return err
+	}
+	existing, adopt, err := c.checkAdoption(clientObj, gvk)
+	if err != nil || !adopt {
+		return err
+	}
+	// the steady state reconcile loop doesn't send anything to the API server
+	if existing != nil && appliedResources.isApplied(c.Jenkins, gvk, existing, hash) {
+		return nil
+	}
 
+	options := []client.PatchOption{client.FieldOwner(FieldManager)}
+	if existing != nil && !isAppliedBy(existing, FieldManager) {
+		// the resource has been created or updated by the operator before it switched to server-side apply or it's
+		// being adopted, the first apply takes the fields over from the previous managers
+		options = append(options, client.ForceOwnership)
+	}
+	err = c.Client.Patch(context.TODO(), clientObj, client.Apply, options...)
+	if err != nil {
+		appliedResources.delete(c.Jenkins, gvk, clientObj)
+		if errors.IsConflict(err) {
+			return stackerr.Wrapf(err, "%s '%s' has fields managed by other controllers which conflict with the operator", gvk.Kind, obj.GetName())
+		}
+		return stackerr.WithStack(err)
+	}
+	appliedResources.set(c.Jenkins, gvk, clientObj, hash)
 	return nil
 }
 
+// isAppliedBy returns true if the field manager has applied the resource
+func isAppliedBy(obj client.Object, fieldManager string) bool {
+	for _, managedFields := range obj.GetManagedFields() {
+		if managedFields.Manager == fieldManager && managedFields.Operation == metav1.ManagedFieldsOperationApply {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAdoption applies spec.adoptionPolicy when the resource already exists but isn't controlled by the Jenkins CR,
+// false is returned when the resource has to be left untouched. The existing resource is returned if it's found.
+func (c *Configuration) checkAdoption(obj client.Object, gvk schema.GroupVersionKind) (client.Object, bool, error) {
+	existing, err := c.Client.Scheme().New(gvk)
+	if err != nil {
+		return nil, false, stackerr.WithStack(err)
+	}
+	existingObj, ok := existing.(client.Object)
+	if !ok {
+		return nil, false, stackerr.Errorf("is not a %T a client.Object", existing)
+	}
+	err = c.Client.Get(context.TODO(), types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, existingObj)
+	if err != nil && errors.IsNotFound(err) {
+		return nil, true, nil
+	} else if err != nil {
+		return nil, false, stackerr.WithStack(err)
+	}
+	if metav1.IsControlledBy(existingObj, c.Jenkins) {
+		return existingObj, true, nil
+	}
+	if owner := metav1.GetControllerOf(existingObj); owner != nil {
+		return nil, false, stackerr.Errorf("%s '%s' already exists and is controlled by %s '%s'", gvk.Kind, obj.GetName(), owner.Kind, owner.Name)
+	}
+
+	switch c.Jenkins.Spec.AdoptionPolicy {
+	case v1alpha2.IgnoreAdoptionPolicy:
+		return nil, false, nil
+	case v1alpha2.FailAdoptionPolicy:
+		return nil, false, stackerr.Errorf("%s '%s' already exists and isn't owned by the Jenkins CR, set spec.adoptionPolicy to Adopt to take it over", gvk.Kind, obj.GetName())
+	}
+
+	message := fmt.Sprintf("Adopted pre-existing %s '%s'", gvk.Kind, obj.GetName())
+	if c.Notifications != nil {
+		*c.Notifications <- event.Event{
+			Jenkins: *c.Jenkins,
+			Phase:   event.PhaseBase,
+			Level:   v1alpha2.NotificationLevelInfo,
+			Reason:  reason.NewResourceAdopted(reason.OperatorSource, []string{message}),
+		}
+	}
+	return existingObj, true, nil
+}
+
 // Exec executes command in the given pod and it's container.
 func (c *Configuration) Exec(podName, containerName string, command []string) (stdout, stderr bytes.Buffer, err error) {
+	return c.ExecContext(context.Background(), podName, containerName, command)
+}
+
+// ExecContext executes command in the given pod and it's container, the exec connection is closed when the context
+// is done. The command may keep running in the container after the connection is closed.
+func (c *Configuration) ExecContext(ctx context.Context, podName, containerName string, command []string) (stdout, stderr bytes.Buffer, err error) {
+	exec, err := c.newExecutor(ctx, podName, containerName, command, false)
+	if err != nil {
+		return stdout, stderr, err
+	}
+
+	err = exec.Stream(remotecommand.StreamOptions{
+		Stdin:  nil,
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Tty:    false,
+	})
+	if ctx.Err() != nil {
+		return stdout, stderr, stackerr.Wrapf(ctx.Err(), "pod exec of '%s' cancelled: stdout '%s' stderr '%s'", strings.Join(command, " "),
+			stdout.String(), stderr.String())
+	}
+	if err != nil {
+		return stdout, stderr, stackerr.Wrapf(err, "pod exec error operation on stream: stdout '%s' stderr '%s'", stdout.String(), stderr.String())
+	}
+
+	return
+}
+
+// ExecStream executes command in the given pod and it's container, stdin is streamed to the command and the command
+// stdout is streamed to stdout. It's used to transfer data which doesn't fit into memory e.g. backups.
+func (c *Configuration) ExecStream(podName, containerName string, command []string, stdin io.Reader, stdout io.Writer) (stderr bytes.Buffer, err error) {
+	exec, err := c.newExecutor(context.Background(), podName, containerName, command, stdin != nil)
+	if err != nil {
+		return stderr, err
+	}
+
+	err = exec.Stream(remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: &stderr,
+		Tty:    false,
+	})
+	if err != nil {
+		return stderr, stackerr.Wrapf(err, "pod exec error operation on stream: stderr '%s'", stderr.String())
+	}
+
+	return
+}
+
+func (c *Configuration) newExecutor(ctx context.Context, podName, containerName string, command []string, stdin bool) (remotecommand.Executor, error) {
 	req := c.ClientSet.CoreV1().RESTClient().Post().
 		Resource("pods").
 		Name(podName).
@@ -144,28 +325,55 @@ func (c *Configuration) Exec(podName, containerName string, command []string) (s
 	req.VersionedParams(&corev1.PodExecOptions{
 		Command:   command,
 		Container: containerName,
-		Stdin:     false,
+		Stdin:     stdin,
 		Stdout:    true,
 		Stderr:    true,
 		TTY:       false,
 	}, scheme.ParameterCodec)
 
-	exec, err := remotecommand.NewSPDYExecutor(c.Config, "POST", req.URL())
+	transport, upgrader, err := spdy.RoundTripperFor(c.Config)
+	if err != nil {
+		return nil, stackerr.Wrap(err, "pod exec error while creating Executor")
+	}
+	exec, err := remotecommand.NewSPDYExecutorForTransports(transport, &cancelableUpgrader{Upgrader: upgrader, ctx: ctx}, "POST", req.URL())
 	if err != nil {
-		return stdout, stderr, stackerr.Wrap(err, "pod exec error while creating Executor")
+		return nil, stackerr.Wrap(err, "pod exec error while creating Executor")
 	}
+	return exec, nil
+}
 
-	err = exec.Stream(remotecommand.StreamOptions{
-		Stdin:  nil,
-		Stdout: &stdout,
-		Stderr: &stderr,
-		Tty:    false,
-	})
+// cancelableUpgrader closes the exec connection when the context is done, the executor doesn't support cancellation
+type cancelableUpgrader struct {
+	spdy.Upgrader
+	ctx context.Context
+}
+
+// NewConnection implements spdy.Upgrader
+func (u *cancelableUpgrader) NewConnection(resp *http.Response) (httpstream.Connection, error) {
+	connection, err := u.Upgrader.NewConnection(resp)
 	if err != nil {
-		return stdout, stderr, stackerr.Wrapf(err, "pod exec error operation on stream: stdout '%s' stderr '%s'", stdout.String(), stderr.String())
+		return nil, err
 	}
+	go func() {
+		select {
+		case <-u.ctx.Done():
+			_ = connection.Close()
+		case <-connection.CloseChan():
+		}
+	}()
+	return connection, nil
+}
 
-	return
+// GetContainerLogs returns the last lines of logs of the given pod container.
+func (c *Configuration) GetContainerLogs(podName, containerName string, tailLines int64) (string, error) {
+	logs, err := c.ClientSet.CoreV1().Pods(c.Jenkins.Namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container: containerName,
+		TailLines: &tailLines,
+	}).DoRaw(context.TODO())
+	if err != nil {
+		return "", stackerr.WithStack(err)
+	}
+	return string(logs), nil
 }
 
 // GetJenkinsMasterContainer returns the Jenkins master container from the CR.
@@ -179,6 +387,9 @@ func (c *Configuration) GetJenkinsMasterContainer() *v1alpha2.Container {
 
 // GetJenkinsClient gets jenkins client from a configuration.
 func (c *Configuration) GetJenkinsClient() (jenkinsclient.Jenkins, error) {
+	if c.Jenkins.Spec.ExternalJenkins != nil {
+		return c.GetExternalJenkinsClient()
+	}
 	switch c.Jenkins.Spec.JenkinsAPISettings.AuthorizationStrategy {
 	case v1alpha2.ServiceAccountAuthorizationStrategy:
 		return c.GetJenkinsClientFromServiceAccount()
@@ -189,6 +400,20 @@ func (c *Configuration) GetJenkinsClient() (jenkinsclient.Jenkins, error) {
 	}
 }
 
+// GetJenkinsAPIConnectionSettings returns Jenkins API connection settings of the Jenkins CR, the operator settings
+// are overridden by spec.jenkinsAPISettings when the hostname, port or useNodePort is set there.
+func GetJenkinsAPIConnectionSettings(settings jenkinsclient.JenkinsAPIConnectionSettings, jenkins *v1alpha2.Jenkins) jenkinsclient.JenkinsAPIConnectionSettings {
+	apiSettings := jenkins.Spec.JenkinsAPISettings
+	if apiSettings.Hostname == "" && apiSettings.Port == 0 && !apiSettings.UseNodePort {
+		return settings
+	}
+	return jenkinsclient.JenkinsAPIConnectionSettings{
+		Hostname:    apiSettings.Hostname,
+		Port:        apiSettings.Port,
+		UseNodePort: apiSettings.UseNodePort,
+	}
+}
+
 func (c *Configuration) getJenkinsAPIUrl() (string, error) {
 	var service corev1.Service
 
@@ -200,13 +425,61 @@ func (c *Configuration) getJenkinsAPIUrl() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	jenkinsURL := c.JenkinsAPIConnectionSettings.BuildJenkinsAPIUrl(service.Name, service.Namespace, service.Spec.Ports[0].Port, service.Spec.Ports[0].NodePort)
+	settings := GetJenkinsAPIConnectionSettings(c.JenkinsAPIConnectionSettings, c.Jenkins)
+	targetPort := service.Spec.Ports[0].TargetPort.IntValue()
+	authProxy := c.Jenkins.Spec.JenkinsAPISettings.AuthProxy
+	bypassAuthProxy := authProxy != nil && authProxy.BypassPort > 0
+	if bypassAuthProxy {
+		targetPort = int(authProxy.BypassPort)
+	}
+
+	var jenkinsURL string
+	switch {
+	case settings.UsePortForward:
+		jenkinsURL, err = c.getPortForwardJenkinsAPIUrl(targetPort)
+		if err != nil {
+			return "", err
+		}
+	case bypassAuthProxy:
+		pod, err := c.GetJenkinsMasterPod()
+		if err != nil {
+			return "", err
+		}
+		if len(pod.Status.PodIP) == 0 {
+			return "", stackerr.Errorf("Jenkins master pod '%s' has no IP assigned yet", pod.Name)
+		}
+		jenkinsURL = fmt.Sprintf("http://%s:%d", pod.Status.PodIP, targetPort)
+	default:
+		jenkinsURL = settings.BuildJenkinsAPIUrl(service.Name, service.Namespace, service.Spec.Ports[0].Port, service.Spec.Ports[0].NodePort)
+	}
 	if prefix, ok := resources.GetJenkinsOpts(*c.Jenkins)["prefix"]; ok {
 		jenkinsURL += prefix
 	}
 	return jenkinsURL, nil
 }
 
+// getJenkinsClientOptions returns Jenkins API client options of the configuration and the ones required by the auth
+// proxy in front of Jenkins
+func (c *Configuration) getJenkinsClientOptions() ([]jenkinsclient.Option, error) {
+	options := append([]jenkinsclient.Option{}, c.JenkinsClientOptions...)
+	authProxy := c.Jenkins.Spec.JenkinsAPISettings.AuthProxy
+	if authProxy == nil || authProxy.HeaderValueSecretKeySelector == nil {
+		return options, nil
+	}
+
+	selector := authProxy.HeaderValueSecretKeySelector
+	secret := &corev1.Secret{}
+	err := c.Client.Get(context.TODO(), types.NamespacedName{Name: selector.Name, Namespace: c.Jenkins.ObjectMeta.Namespace}, secret)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	value, found := secret.Data[selector.Key]
+	if !found {
+		return nil, stackerr.Errorf("secret '%s' doesn't contain '%s' key", selector.Name, selector.Key)
+	}
+	return append(options, jenkinsclient.WithHeader(authProxy.HeaderName, strings.TrimSpace(string(value)))), nil
+}
+
 // GetJenkinsClientFromServiceAccount gets jenkins client from a serviceAccount.
 func (c *Configuration) GetJenkinsClientFromServiceAccount() (jenkinsclient.Jenkins, error) {
 	jenkinsAPIUrl, err := c.getJenkinsAPIUrl()
@@ -214,13 +487,40 @@ func (c *Configuration) GetJenkinsClientFromServiceAccount() (jenkinsclient.Jenk
 		return nil, err
 	}
 
+	options, err := c.getJenkinsClientOptions()
+	if err != nil {
+		return nil, err
+	}
+
 	podName := resources.GetJenkinsMasterPodName(c.Jenkins)
 	token, _, err := c.Exec(podName, resources.JenkinsMasterContainerName, []string{"cat", "/var/run/secrets/kubernetes.io/serviceaccount/token"})
 	if err != nil {
 		return nil, err
 	}
 
-	return jenkinsclient.NewBearerTokenAuthorization(jenkinsAPIUrl, token.String())
+	return jenkinsclient.NewBearerTokenAuthorization(jenkinsAPIUrl, token.String(), options...)
+}
+
+// GetExternalJenkinsClient gets jenkins client of the Jenkins which isn't created by the operator, the user and the
+// password are read from spec.externalJenkins.credentialsSecret.
+func (c *Configuration) GetExternalJenkinsClient() (jenkinsclient.Jenkins, error) {
+	externalJenkins := c.Jenkins.Spec.ExternalJenkins
+	options, err := c.getJenkinsClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	credentialsSecret := &corev1.Secret{}
+	err = c.Client.Get(context.TODO(), types.NamespacedName{Name: externalJenkins.CredentialsSecret.Name, Namespace: c.Jenkins.ObjectMeta.Namespace}, credentialsSecret)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	userName := credentialsSecret.Data[resources.OperatorCredentialsSecretUserNameKey]
+	password := credentialsSecret.Data[resources.OperatorCredentialsSecretPasswordKey]
+	if len(userName) == 0 || len(password) == 0 {
+		return nil, stackerr.Errorf("secret '%s' must contain '%s' and '%s' keys", credentialsSecret.Name,
+			resources.OperatorCredentialsSecretUserNameKey, resources.OperatorCredentialsSecretPasswordKey)
+	}
+	return jenkinsclient.NewUserAndPasswordAuthorization(externalJenkins.URL, string(userName), string(password), options...)
 }
 
 // GetJenkinsClientFromSecret gets jenkins client from a secret.
@@ -229,11 +529,23 @@ func (c *Configuration) GetJenkinsClientFromSecret() (jenkinsclient.Jenkins, err
 	if err != nil {
 		return nil, err
 	}
+	options, err := c.getJenkinsClientOptions()
+	if err != nil {
+		return nil, err
+	}
 	credentialsSecret := &corev1.Secret{}
-	err = c.Client.Get(context.TODO(), types.NamespacedName{Name: resources.GetOperatorCredentialsSecretName(c.Jenkins), Namespace: c.Jenkins.ObjectMeta.Namespace}, credentialsSecret)
+	err = c.Client.Get(context.TODO(), types.NamespacedName{Name: resources.GetCurrentOperatorCredentialsSecretName(c.Jenkins), Namespace: c.Jenkins.ObjectMeta.Namespace}, credentialsSecret)
 	if err != nil {
 		return nil, stackerr.WithStack(err)
 	}
+	if resources.IsImmutableConfiguration(c.Jenkins) {
+		// the versions of the credentials secret are immutable, the token can't be stored in them
+		return jenkinsclient.NewUserAndPasswordAuthorization(
+			jenkinsURL,
+			string(credentialsSecret.Data[resources.OperatorCredentialsSecretUserNameKey]),
+			string(credentialsSecret.Data[resources.OperatorCredentialsSecretPasswordKey]),
+			options...)
+	}
 	currentJenkinsMasterPod, err := c.GetJenkinsMasterPod()
 	if err != nil {
 		return nil, err
@@ -254,7 +566,8 @@ func (c *Configuration) GetJenkinsClientFromSecret() (jenkinsclient.Jenkins, err
 		jenkinsClient, err := jenkinsclient.NewUserAndPasswordAuthorization(
 			jenkinsURL,
 			userName,
-			string(credentialsSecret.Data[resources.OperatorCredentialsSecretPasswordKey]))
+			string(credentialsSecret.Data[resources.OperatorCredentialsSecretPasswordKey]),
+			options...)
 		if err != nil {
 			return nil, err
 		}
@@ -275,5 +588,6 @@ func (c *Configuration) GetJenkinsClientFromSecret() (jenkinsclient.Jenkins, err
 	return jenkinsclient.NewUserAndPasswordAuthorization(
 		jenkinsURL,
 		string(credentialsSecret.Data[resources.OperatorCredentialsSecretUserNameKey]),
-		string(credentialsSecret.Data[resources.OperatorCredentialsSecretTokenKey]))
+		string(credentialsSecret.Data[resources.OperatorCredentialsSecretTokenKey]),
+		options...)
 }
@@ -0,0 +1,188 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfiguration_RestartJenkinsMasterPod(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: resources.GetJenkinsMasterPodName(jenkins), Namespace: "default"}}
+	notifications := make(chan event.Event, 1)
+	config := Configuration{
+		Client:        fake.NewClientBuilder().WithObjects(jenkins, pod).Build(),
+		Jenkins:       jenkins,
+		Notifications: &notifications,
+	}
+
+	err := config.RestartJenkinsMasterPod(reason.NewPodRestart(reason.KubernetesSource, []string{"Invalid Jenkins pod phase 'Failed'"}))
+
+	require.NoError(t, err)
+	notification := <-notifications
+	assert.Equal(t, v1alpha2.NotificationLevelWarning, notification.Level)
+	actual := &v1alpha2.Jenkins{}
+	require.NoError(t, config.Client.Get(context.TODO(), types.NamespacedName{Name: "jenkins", Namespace: "default"}, actual))
+	require.NotNil(t, actual.Status.PodRestarts)
+	assert.Equal(t, uint64(1), actual.Status.PodRestarts.Kubernetes)
+	assert.Equal(t, uint64(0), actual.Status.PodRestarts.Operator)
+	assert.Equal(t, "kubernetes", actual.Status.PodRestarts.LastSource)
+	assert.NotNil(t, actual.Status.PodRestarts.LastTime)
+	err = config.Client.Get(context.TODO(), types.NamespacedName{Name: pod.Name, Namespace: "default"}, &corev1.Pod{})
+	assert.Error(t, err)
+}
+
+// applyCountingClient counts server-side applies, they are emulated with create or update because the fake client
+// doesn't support them
+type applyCountingClient struct {
+	client.Client
+	applies int
+	forced  int
+}
+
+func (c *applyCountingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	c.applies++
+	options := &client.PatchOptions{}
+	options.ApplyOptions(opts)
+	if options.Force != nil && *options.Force {
+		c.forced++
+	}
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: options.FieldManager, Operation: metav1.ManagedFieldsOperationApply}})
+	existing := obj.DeepCopyObject().(client.Object)
+	err := c.Client.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, existing)
+	if apierrors.IsNotFound(err) {
+		return c.Client.Create(ctx, obj)
+	} else if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return c.Client.Update(ctx, obj)
+}
+
+func TestConfiguration_CreateOrUpdateResource(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default", UID: "d5b2a3f4"}}
+	newConfigMap := func(value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "jenkins-operator-scripts", Namespace: "default"},
+			Data:       map[string]string{"init.sh": value},
+		}
+	}
+	k8sClient := &applyCountingClient{Client: fake.NewClientBuilder().WithObjects(jenkins).Build()}
+	config := Configuration{Client: k8sClient, Jenkins: jenkins, Scheme: scheme.Scheme}
+
+	t.Run("create", func(t *testing.T) {
+		require.NoError(t, config.CreateOrUpdateResource(newConfigMap("echo 1")))
+
+		assert.Equal(t, 1, k8sClient.applies)
+	})
+	t.Run("unchanged", func(t *testing.T) {
+		require.NoError(t, config.CreateOrUpdateResource(newConfigMap("echo 1")))
+
+		assert.Equal(t, 1, k8sClient.applies)
+	})
+	t.Run("changed spec", func(t *testing.T) {
+		require.NoError(t, config.CreateOrUpdateResource(newConfigMap("echo 2")))
+
+		assert.Equal(t, 2, k8sClient.applies)
+	})
+	t.Run("changed by user", func(t *testing.T) {
+		configMap := &corev1.ConfigMap{}
+		require.NoError(t, k8sClient.Get(context.TODO(), types.NamespacedName{Name: "jenkins-operator-scripts", Namespace: "default"}, configMap))
+		configMap.Data["init.sh"] = "exit 1"
+		require.NoError(t, k8sClient.Update(context.TODO(), configMap))
+
+		require.NoError(t, config.CreateOrUpdateResource(newConfigMap("echo 2")))
+
+		assert.Equal(t, 3, k8sClient.applies)
+		assert.Equal(t, 0, k8sClient.forced)
+		require.NoError(t, k8sClient.Get(context.TODO(), types.NamespacedName{Name: "jenkins-operator-scripts", Namespace: "default"}, configMap))
+		assert.Equal(t, "echo 2", configMap.Data["init.sh"])
+	})
+	t.Run("created before server-side apply", func(t *testing.T) {
+		configMap := newConfigMap("echo 1")
+		configMap.Name = "jenkins-operator-init"
+		configMap.OwnerReferences = []metav1.OwnerReference{{APIVersion: "jenkins.io/v1alpha2", Kind: "Jenkins", Name: "jenkins",
+			UID: jenkins.UID, Controller: &[]bool{true}[0]}}
+		require.NoError(t, k8sClient.Create(context.TODO(), configMap))
+		configMap = newConfigMap("echo 2")
+		configMap.Name = "jenkins-operator-init"
+
+		require.NoError(t, config.CreateOrUpdateResource(configMap))
+
+		assert.Equal(t, 1, k8sClient.forced)
+	})
+	t.Run("forgotten when Jenkins CR is deleted", func(t *testing.T) {
+		applies := k8sClient.applies
+		require.NoError(t, config.CreateOrUpdateResource(newConfigMap("echo 2")))
+		require.Equal(t, applies, k8sClient.applies)
+
+		ForgetAppliedResources(types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name})
+
+		assert.NotContains(t, appliedResources.resources, types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name})
+		require.NoError(t, config.CreateOrUpdateResource(newConfigMap("echo 2")))
+		assert.Equal(t, applies+1, k8sClient.applies)
+	})
+}
+
+func TestConfiguration_GetExternalJenkinsClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "admin" || password != "api-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"mode":"NORMAL"}`)
+	}))
+	defer server.Close()
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			ExternalJenkins: &v1alpha2.ExternalJenkins{URL: server.URL + "/", CredentialsSecret: v1alpha2.SecretRef{Name: "jenkins-credentials"}},
+		},
+	}
+	newConfiguration := func(data map[string][]byte) Configuration {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "jenkins-credentials", Namespace: "default"}, Data: data}
+		return Configuration{Client: fake.NewClientBuilder().WithObjects(secret).Build(), Jenkins: jenkins}
+	}
+
+	t.Run("valid credentials", func(t *testing.T) {
+		config := newConfiguration(map[string][]byte{
+			resources.OperatorCredentialsSecretUserNameKey: []byte("admin"),
+			resources.OperatorCredentialsSecretPasswordKey: []byte("api-token"),
+		})
+
+		jenkinsClient, err := config.GetJenkinsClient()
+
+		require.NoError(t, err)
+		assert.NotNil(t, jenkinsClient)
+	})
+	t.Run("missing password", func(t *testing.T) {
+		config := newConfiguration(map[string][]byte{resources.OperatorCredentialsSecretUserNameKey: []byte("admin")})
+
+		_, err := config.GetJenkinsClient()
+
+		assert.EqualError(t, err, "secret 'jenkins-credentials' must contain 'user' and 'password' keys")
+	})
+}
@@ -0,0 +1,105 @@
+package configuration
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+const portForwardReadyTimeout = 30 * time.Second
+
+// portForward is a local port forwarded through the Kubernetes API server to the Jenkins master pod
+type portForward struct {
+	podUID    types.UID
+	localPort uint16
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// portForwards holds port-forwards of Jenkins CRs keyed by namespace/name, they are shared by all reconcile loops
+var portForwards = struct {
+	sync.Mutex
+	forwards map[string]*portForward
+}{forwards: map[string]*portForward{}}
+
+// getPortForwardJenkinsAPIUrl returns Jenkins API URL on the local port forwarded to the Jenkins master pod,
+// the port-forward is reused until the pod changes or the connection is lost
+func (c *Configuration) getPortForwardJenkinsAPIUrl(targetPort int) (string, error) {
+	pod, err := c.GetJenkinsMasterPod()
+	if err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("%s/%s", c.Jenkins.Namespace, c.Jenkins.Name)
+
+	portForwards.Lock()
+	defer portForwards.Unlock()
+	if forward, found := portForwards.forwards[key]; found {
+		select {
+		case <-forward.done:
+		default:
+			if forward.podUID == pod.UID {
+				return fmt.Sprintf("http://127.0.0.1:%d", forward.localPort), nil
+			}
+			close(forward.stop)
+		}
+		delete(portForwards.forwards, key)
+	}
+
+	forward, err := c.startPortForward(pod, targetPort)
+	if err != nil {
+		return "", err
+	}
+	portForwards.forwards[key] = forward
+	return fmt.Sprintf("http://127.0.0.1:%d", forward.localPort), nil
+}
+
+func (c *Configuration) startPortForward(pod *corev1.Pod, targetPort int) (*portForward, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(c.Config)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	req := c.ClientSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	forward := &portForward{podUID: pod.UID, stop: make(chan struct{}), done: make(chan struct{})}
+	ready := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", targetPort)},
+		forward.stop, ready, ioutil.Discard, ioutil.Discard)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- forwarder.ForwardPorts()
+		close(forward.done)
+	}()
+
+	select {
+	case <-ready:
+	case err := <-errs:
+		return nil, stackerr.Wrapf(err, "failed to port-forward Jenkins master pod '%s'", pod.Name)
+	case <-time.After(portForwardReadyTimeout):
+		close(forward.stop)
+		return nil, stackerr.Errorf("timed out waiting for port-forward of Jenkins master pod '%s'", pod.Name)
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		close(forward.stop)
+		return nil, stackerr.WithStack(err)
+	}
+	forward.localPort = ports[0].Local
+	return forward, nil
+}
@@ -0,0 +1,76 @@
+package configuration
+
+import (
+	"reflect"
+
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+)
+
+// PendingRestart accumulates the reasons of Jenkins master pod restart found in a reconcile loop, so a single edit
+// changing e.g. the image and plugins restarts Jenkins once. All methods are no-op on nil pending restart.
+type PendingRestart struct {
+	reasons []reason.Reason
+}
+
+// NewPendingRestart returns an empty restart accumulator.
+func NewPendingRestart() *PendingRestart {
+	return &PendingRestart{}
+}
+
+// Add records the restart reason, the same reason added twice is listed once.
+func (p *PendingRestart) Add(restartReason reason.Reason) {
+	if p == nil || !restartReason.HasMessages() {
+		return
+	}
+	for _, pending := range p.reasons {
+		if reflect.DeepEqual(pending.Short(), restartReason.Short()) {
+			return
+		}
+	}
+	p.reasons = append(p.reasons, restartReason)
+}
+
+// Requested returns true if any restart reason has been recorded.
+func (p *PendingRestart) Requested() bool {
+	return p != nil && len(p.reasons) > 0
+}
+
+// take returns a reason listing all recorded reasons together with the given ones and clears the accumulator.
+func (p *PendingRestart) take(restartReasons ...reason.Reason) reason.Reason {
+	batch := &PendingRestart{}
+	if p != nil {
+		batch.reasons, p.reasons = p.reasons, nil
+	}
+	for _, restartReason := range restartReasons {
+		batch.Add(restartReason)
+	}
+	switch {
+	case len(batch.reasons) == 1:
+		return batch.reasons[0]
+	case len(batch.reasons) == 0 && len(restartReasons) > 0:
+		return restartReasons[0]
+	default:
+		return reason.NewPodRestartBatch(batch.reasons...)
+	}
+}
+
+// RequestJenkinsMasterPodRestart records the reason of Jenkins master pod restart to restart the pod once with all
+// reasons found in the reconcile loop by RestartRequestedJenkinsMasterPod. The pod is restarted immediately if the
+// reason comes from Kubernetes e.g. the pod failed, or there is no pending restart accumulator. It returns true if
+// the pod has been restarted.
+func (c *Configuration) RequestJenkinsMasterPodRestart(restartReason reason.Reason) (bool, error) {
+	if c.PendingRestart == nil || restartReason.Source() == reason.KubernetesSource {
+		return true, c.RestartJenkinsMasterPod(restartReason)
+	}
+	c.PendingRestart.Add(restartReason)
+	return false, nil
+}
+
+// RestartRequestedJenkinsMasterPod restarts Jenkins master pod once if any restart has been requested, the
+// notification lists all requested reasons. It returns true if the pod has been restarted.
+func (c *Configuration) RestartRequestedJenkinsMasterPod() (bool, error) {
+	if !c.PendingRestart.Requested() {
+		return false, nil
+	}
+	return true, c.RestartJenkinsMasterPod(c.PendingRestart.take())
+}
@@ -0,0 +1,100 @@
+package configuration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfiguration_RequestJenkinsMasterPodRestart(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	newConfiguration := func(pendingRestart *PendingRestart) (Configuration, chan event.Event) {
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: resources.GetJenkinsMasterPodName(jenkins), Namespace: "default"}}
+		notifications := make(chan event.Event, 2)
+		return Configuration{
+			Client:         fake.NewClientBuilder().WithObjects(jenkins, pod).Build(),
+			Jenkins:        jenkins,
+			Notifications:  &notifications,
+			PendingRestart: pendingRestart,
+		}, notifications
+	}
+	imageChanged := reason.NewPodRestart(reason.OperatorSource, []string{"Jenkins image has changed to 'jenkins/jenkins:lts'"})
+	pluginsChanged := reason.NewPodRestart(reason.OperatorSource, []string{"Some plugins have changed, restarting Jenkins"})
+
+	t.Run("batched restart", func(t *testing.T) {
+		config, notifications := newConfiguration(NewPendingRestart())
+
+		restarted, err := config.RequestJenkinsMasterPodRestart(imageChanged)
+		require.NoError(t, err)
+		assert.False(t, restarted)
+		restarted, err = config.RequestJenkinsMasterPodRestart(pluginsChanged)
+		require.NoError(t, err)
+		assert.False(t, restarted)
+		restarted, err = config.RequestJenkinsMasterPodRestart(imageChanged)
+		require.NoError(t, err)
+		assert.False(t, restarted)
+
+		restarted, err = config.RestartRequestedJenkinsMasterPod()
+
+		require.NoError(t, err)
+		assert.True(t, restarted)
+		require.Len(t, notifications, 1)
+		notification := <-notifications
+		assert.Equal(t, reason.OperatorSource, notification.Reason.Source())
+		assert.Equal(t, []string{
+			"Jenkins master pod restarted by operator:",
+			"Jenkins image has changed to 'jenkins/jenkins:lts'",
+			"Some plugins have changed, restarting Jenkins",
+		}, notification.Reason.Short())
+		actual := &v1alpha2.Jenkins{}
+		require.NoError(t, config.Client.Get(context.TODO(), types.NamespacedName{Name: "jenkins", Namespace: "default"}, actual))
+		assert.Equal(t, uint64(1), actual.Status.PodRestarts.Operator)
+		assert.Equal(t, uint64(0), actual.Status.PodRestarts.Kubernetes)
+
+		restarted, err = config.RestartRequestedJenkinsMasterPod()
+
+		require.NoError(t, err)
+		assert.False(t, restarted)
+	})
+	t.Run("kubernetes reason restarts immediately", func(t *testing.T) {
+		config, notifications := newConfiguration(NewPendingRestart())
+		_, err := config.RequestJenkinsMasterPodRestart(pluginsChanged)
+		require.NoError(t, err)
+
+		restarted, err := config.RequestJenkinsMasterPodRestart(reason.NewPodRestart(reason.KubernetesSource, []string{"Invalid Jenkins pod phase 'Failed'"}))
+
+		require.NoError(t, err)
+		assert.True(t, restarted)
+		notification := <-notifications
+		assert.Equal(t, reason.KubernetesSource, notification.Reason.Source())
+		assert.Equal(t, []string{
+			"Jenkins master pod restarted by kubernetes:",
+			"Some plugins have changed, restarting Jenkins",
+			"Invalid Jenkins pod phase 'Failed'",
+		}, notification.Reason.Short())
+		assert.False(t, config.PendingRestart.Requested())
+	})
+	t.Run("without pending restart", func(t *testing.T) {
+		config, notifications := newConfiguration(nil)
+
+		restarted, err := config.RequestJenkinsMasterPodRestart(pluginsChanged)
+
+		require.NoError(t, err)
+		assert.True(t, restarted)
+		notification := <-notifications
+		assert.Equal(t, pluginsChanged.Short(), notification.Reason.Short())
+	})
+}
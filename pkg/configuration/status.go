@@ -0,0 +1,40 @@
+package configuration
+
+import (
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/version"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResetJenkinsMasterPodStatus resets the status of Jenkins CR when Jenkins master pod is recreated. Only the fields
+// describing the previous pod and the configuration applied to it are reset, so the configuration phases run again
+// for the new pod. All other fields e.g. backups, restore rehearsals or applied extra resources are kept.
+func ResetJenkinsMasterPodStatus(jenkins *v1alpha2.Jenkins, userAndPasswordHash string) {
+	status := &jenkins.Status
+	now := metav1.Now()
+	status.OperatorVersion = version.Version
+	status.ProvisionStartTime = &now
+	status.UserAndPasswordHash = userAndPasswordHash
+
+	status.BaseConfigurationCompletedTime = nil
+	status.BaseConfigurationProgress = nil
+	status.UserConfigurationCompletedTime = nil
+	status.RestoredBackup = 0
+	status.PendingBackup = status.LastBackup
+	status.BackupDoneBeforePodDeletion = false
+	status.CreatedSeedJobs = nil
+	status.SeedJobPlugins = nil
+	status.AppliedGroovyScripts = nil
+	status.GroovyScriptResults = nil
+	status.PodStartingDiagnosis = ""
+	status.BackoffUntil = nil
+	status.PluginUpgrades = nil
+	status.PluginUpgradeAttempts = 0
+	status.PluginUpgradeTime = nil
+	status.Ready = false
+	status.JenkinsVersion = ""
+	status.ReadinessCheckMessage = ""
+
+	ResetConditions(jenkins, status.Conditions)
+}
@@ -0,0 +1,70 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/version"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResetJenkinsMasterPodStatus(t *testing.T) {
+	now := metav1.Now()
+	jenkins := &v1alpha2.Jenkins{Status: v1alpha2.JenkinsStatus{
+		OperatorVersion:                "v0.0.1",
+		UserAndPasswordHash:            "old",
+		BaseConfigurationCompletedTime: &now,
+		UserConfigurationCompletedTime: &now,
+		RestoredBackup:                 3,
+		LastBackup:                     5,
+		PendingBackup:                  6,
+		CreatedSeedJobs:                []string{"jenkins-operator"},
+		AppliedGroovyScripts:           []v1alpha2.AppliedGroovyScript{{Name: "script.groovy"}},
+		PluginUpgrades:                 []string{"git:4.0.0"},
+		Ready:                          true,
+		JenkinsVersion:                 "2.319.1",
+		// the fields below describe the operator actions which aren't bound to the pod
+		PodRestarts:           &v1alpha2.PodRestarts{Operator: 1},
+		BackupEstimate:        &v1alpha2.BackupEstimate{SizeBytes: 1024, EstimatedTime: now},
+		RestoreDryRun:         &v1alpha2.RestoreDryRunStatus{BackupNumber: 5, Time: now, Jobs: []string{"job"}},
+		BackupCommands:        []v1alpha2.BackupCommandStatus{{Action: "backup", Outcome: v1alpha2.BackupCommandSucceeded, StartTime: now}},
+		RestoreRehearsal:      &v1alpha2.RestoreRehearsalStatus{BackupNumber: 5, StartTime: now},
+		BackupDestinations:    []v1alpha2.BackupDestinationStatus{{Name: "s3", LastBackup: 4, Error: "access denied"}},
+		AppliedExtraResources: []v1alpha2.ExtraResourceReference{{APIVersion: "networking.k8s.io/v1", Kind: "Ingress", Name: "jenkins"}},
+	}}
+	kept := map[string]func(status v1alpha2.JenkinsStatus) interface{}{
+		"podRestarts":           func(status v1alpha2.JenkinsStatus) interface{} { return status.PodRestarts },
+		"backupEstimate":        func(status v1alpha2.JenkinsStatus) interface{} { return status.BackupEstimate },
+		"restoreDryRun":         func(status v1alpha2.JenkinsStatus) interface{} { return status.RestoreDryRun },
+		"backupCommands":        func(status v1alpha2.JenkinsStatus) interface{} { return status.BackupCommands },
+		"restoreRehearsal":      func(status v1alpha2.JenkinsStatus) interface{} { return status.RestoreRehearsal },
+		"backupDestinations":    func(status v1alpha2.JenkinsStatus) interface{} { return status.BackupDestinations },
+		"appliedExtraResources": func(status v1alpha2.JenkinsStatus) interface{} { return status.AppliedExtraResources },
+	}
+	before := jenkins.Status.DeepCopy()
+
+	ResetJenkinsMasterPodStatus(jenkins, "new")
+
+	status := jenkins.Status
+	assert.Equal(t, version.Version, status.OperatorVersion)
+	assert.Equal(t, "new", status.UserAndPasswordHash)
+	assert.NotNil(t, status.ProvisionStartTime)
+	assert.Nil(t, status.BaseConfigurationCompletedTime)
+	assert.Nil(t, status.UserConfigurationCompletedTime)
+	assert.Zero(t, status.RestoredBackup)
+	assert.Equal(t, uint64(5), status.LastBackup)
+	assert.Equal(t, uint64(5), status.PendingBackup)
+	assert.Empty(t, status.CreatedSeedJobs)
+	assert.Empty(t, status.AppliedGroovyScripts)
+	assert.Empty(t, status.PluginUpgrades)
+	assert.False(t, status.Ready)
+	assert.Empty(t, status.JenkinsVersion)
+	assert.True(t, meta.IsStatusConditionFalse(status.Conditions, v1alpha2.ConditionBaseConfigurationCompleted))
+	for name, field := range kept {
+		assert.NotEmpty(t, field(*before), name)
+		assert.Equal(t, field(*before), field(status), name)
+	}
+}
@@ -0,0 +1,135 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReconcilePhase is a phase of the reconcile run which duration is recorded in status.reconcileTimings.
+type ReconcilePhase string
+
+const (
+	// ResourcesEnsurePhase ensures Kubernetes resources required by Jenkins master pod
+	ResourcesEnsurePhase ReconcilePhase = "resourcesEnsure"
+	// PluginsVerifyPhase verifies installed plugins
+	PluginsVerifyPhase ReconcilePhase = "pluginsVerify"
+	// BaseGroovyScriptsPhase applies base configuration groovy scripts
+	BaseGroovyScriptsPhase ReconcilePhase = "baseGroovyScripts"
+	// UserGroovyScriptsPhase applies user groovy scripts and Configuration as Code
+	UserGroovyScriptsPhase ReconcilePhase = "userGroovyScripts"
+)
+
+// ReconcileTimer records time spent in the phases of a reconcile run. The run may span multiple requeued reconcile
+// loops, e.g. while waiting for Jenkins master pod. All methods are no-op on nil timer.
+type ReconcileTimer struct {
+	startTime    time.Time
+	loops        int
+	podWaitStart time.Time
+	timings      v1alpha2.ReconcileTimings
+}
+
+// NewReconcileTimer starts a new reconcile run.
+func NewReconcileTimer() *ReconcileTimer {
+	return &ReconcileTimer{startTime: time.Now()}
+}
+
+// StartLoop counts the reconcile loops of the run.
+func (t *ReconcileTimer) StartLoop() {
+	if t == nil {
+		return
+	}
+	t.loops++
+}
+
+// Significant returns true if the run spanned multiple reconcile loops or took at least a minute. Short steady-state
+// runs are not worth recording, they would hide the timings of the last provisioning run.
+func (t *ReconcileTimer) Significant() bool {
+	if t == nil {
+		return false
+	}
+	return t.loops > 1 || time.Since(t.startTime) >= time.Minute
+}
+
+// Record adds the time elapsed since start to the phase duration.
+func (t *ReconcileTimer) Record(phase ReconcilePhase, start time.Time) {
+	if t == nil {
+		return
+	}
+	var duration *metav1.Duration
+	switch phase {
+	case ResourcesEnsurePhase:
+		duration = &t.timings.ResourcesEnsure
+	case PluginsVerifyPhase:
+		duration = &t.timings.PluginsVerify
+	case BaseGroovyScriptsPhase:
+		duration = &t.timings.BaseGroovyScripts
+	case UserGroovyScriptsPhase:
+		duration = &t.timings.UserGroovyScripts
+	default:
+		return
+	}
+	duration.Duration += time.Since(start)
+}
+
+// WaitForPod marks the run as waiting for Jenkins master pod since start unless it's already waiting.
+func (t *ReconcileTimer) WaitForPod(start time.Time) {
+	if t == nil || !t.podWaitStart.IsZero() {
+		return
+	}
+	t.podWaitStart = start
+}
+
+// PodReady records the time spent on waiting for Jenkins master pod, including the previous requeued loops.
+func (t *ReconcileTimer) PodReady(start time.Time) {
+	if t == nil {
+		return
+	}
+	if !t.podWaitStart.IsZero() {
+		start = t.podWaitStart
+		t.podWaitStart = time.Time{}
+	}
+	t.timings.PodWait.Duration += time.Since(start)
+}
+
+// Timings returns the phase durations rounded to seconds and the total time of the run.
+func (t *ReconcileTimer) Timings() v1alpha2.ReconcileTimings {
+	if t == nil {
+		return v1alpha2.ReconcileTimings{}
+	}
+	timings := t.timings
+	timings.Total.Duration = time.Since(t.startTime)
+	for _, duration := range reconcileTimingsDurations(&timings) {
+		duration.Duration = duration.Round(time.Second)
+	}
+	return timings
+}
+
+// ReconcileTimingsChanged returns true if any of the durations differs by more than a second, small fluctuations
+// are ignored to not update the status on every reconcile loop.
+func ReconcileTimingsChanged(current *v1alpha2.ReconcileTimings, timings v1alpha2.ReconcileTimings) bool {
+	if current == nil {
+		return true
+	}
+	currentDurations := reconcileTimingsDurations(current)
+	for i, duration := range reconcileTimingsDurations(&timings) {
+		diff := duration.Duration - currentDurations[i].Duration
+		if diff > time.Second || diff < -time.Second {
+			return true
+		}
+	}
+	return false
+}
+
+func reconcileTimingsDurations(timings *v1alpha2.ReconcileTimings) []*metav1.Duration {
+	return []*metav1.Duration{
+		&timings.ResourcesEnsure,
+		&timings.PodWait,
+		&timings.PluginsVerify,
+		&timings.BaseGroovyScripts,
+		&timings.UserGroovyScripts,
+		&timings.Total,
+	}
+}
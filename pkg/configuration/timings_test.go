@@ -0,0 +1,73 @@
+package configuration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileTimer(t *testing.T) {
+	t.Run("nil timer", func(t *testing.T) {
+		var timer *ReconcileTimer
+
+		timer.StartLoop()
+		timer.Record(ResourcesEnsurePhase, time.Now())
+		timer.WaitForPod(time.Now())
+		timer.PodReady(time.Now())
+
+		assert.False(t, timer.Significant())
+		assert.Equal(t, v1alpha2.ReconcileTimings{}, timer.Timings())
+	})
+	t.Run("records phases across reconcile loops", func(t *testing.T) {
+		timer := NewReconcileTimer()
+		timer.StartLoop()
+		timer.Record(ResourcesEnsurePhase, time.Now().Add(-2*time.Second))
+		timer.WaitForPod(time.Now().Add(-90 * time.Second))
+		timer.StartLoop()
+		timer.WaitForPod(time.Now().Add(-30 * time.Second))
+		timer.PodReady(time.Now())
+		timer.Record(UserGroovyScriptsPhase, time.Now().Add(-3*time.Second))
+		timer.Record(UserGroovyScriptsPhase, time.Now().Add(-4*time.Second))
+
+		timings := timer.Timings()
+
+		assert.True(t, timer.Significant())
+		assert.Equal(t, 2*time.Second, timings.ResourcesEnsure.Duration)
+		assert.Equal(t, 90*time.Second, timings.PodWait.Duration)
+		assert.Equal(t, 7*time.Second, timings.UserGroovyScripts.Duration)
+		assert.Equal(t, time.Duration(0), timings.BaseGroovyScripts.Duration)
+	})
+	t.Run("single short loop", func(t *testing.T) {
+		timer := NewReconcileTimer()
+		timer.StartLoop()
+
+		assert.False(t, timer.Significant())
+	})
+}
+
+func TestReconcileTimingsChanged(t *testing.T) {
+	current := &v1alpha2.ReconcileTimings{
+		PodWait: metav1.Duration{Duration: 90 * time.Second},
+		Total:   metav1.Duration{Duration: 100 * time.Second},
+	}
+
+	t.Run("not recorded yet", func(t *testing.T) {
+		assert.True(t, ReconcileTimingsChanged(nil, v1alpha2.ReconcileTimings{}))
+	})
+	t.Run("small fluctuation", func(t *testing.T) {
+		timings := *current
+		timings.Total.Duration = 101 * time.Second
+
+		assert.False(t, ReconcileTimingsChanged(current, timings))
+	})
+	t.Run("changed", func(t *testing.T) {
+		timings := *current
+		timings.PodWait.Duration = 30 * time.Second
+
+		assert.True(t, ReconcileTimingsChanged(current, timings))
+	})
+}
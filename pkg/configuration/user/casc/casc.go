@@ -6,42 +6,104 @@ import (
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
 	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
 	"github.com/maximba/kubernetes-operator/pkg/groovy"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
 
-	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-const groovyUtf8MaxStringLength = 65535
+const (
+	groovyUtf8MaxStringLength = 65535
+
+	// configurationsName is the name of the ConfigMaps and the Secret applied as a whole recorded in status
+	configurationsName = "configurations"
+)
 
 // ConfigurationAsCode defines client for configurationAsCode
 type ConfigurationAsCode interface {
 	Ensure(jenkins *v1alpha2.Jenkins) (requeue bool, err error)
+	SyncGitRepository(jenkins *v1alpha2.Jenkins) (reconcile.Result, error)
 }
 
 type configurationAsCode struct {
-	groovyClient *groovy.Groovy
+	configuration.Configuration
+	jenkinsClient jenkinsclient.Jenkins
+	groovyClient  *groovy.Groovy
+	logger        logr.Logger
+
+	// pullGitRepository returns the commit and the YAML files of spec.configurationAsCode.gitRepository
+	pullGitRepository func(jenkins *v1alpha2.Jenkins, repository v1alpha2.ConfigurationAsCodeGitRepository) (string, map[string]string, error)
 }
 
 // New creates new instance of ConfigurationAsCode
-func New(jenkinsClient jenkinsclient.Jenkins, k8sClient k8s.Client, jenkins *v1alpha2.Jenkins) ConfigurationAsCode {
-	return &configurationAsCode{
-		groovyClient: groovy.New(jenkinsClient, k8sClient, jenkins, "user-casc", jenkins.Spec.ConfigurationAsCode.Customization),
+func New(jenkinsClient jenkinsclient.Jenkins, config configuration.Configuration) ConfigurationAsCode {
+	c := &configurationAsCode{
+		Configuration: config,
+		jenkinsClient: jenkinsClient,
+		groovyClient: groovy.New(jenkinsClient, config.Client, config.Jenkins, "user-casc", resources.GetConfigurationAsCodeCustomization(config.Jenkins)).
+			WithClusterDomain(config.KubernetesClusterDomain),
+		logger: log.Log.WithValues("cr", config.Jenkins.Name, "namespace", config.Jenkins.Namespace),
 	}
+	c.pullGitRepository = c.pullGitRepositoryInJenkinsMasterPod
+	return c
 }
 
 // Ensure configures Jenkins with help Configuration as a code plugin
-func (c *configurationAsCode) Ensure(_ *v1alpha2.Jenkins) (requeue bool, err error) {
+func (c *configurationAsCode) Ensure(jenkins *v1alpha2.Jenkins) (requeue bool, err error) {
 	requeue, err = c.groovyClient.WaitForSecretSynchronization(resources.ConfigurationAsCodeSecretVolumePath)
 	if err != nil || requeue {
 		return requeue, err
 	}
 
-	return c.groovyClient.Ensure(func(name string) bool {
+	if resources.IsConfigurationAsCodeHotReload(jenkins) {
+		return c.ensureHotReload()
+	}
+
+	var hash string
+	if jenkins.Spec.ConfigurationAsCode.ReloadStrategy == v1alpha2.RestartReloadStrategy {
+		hash, err = c.groovyClient.CustomizationHash()
+		if err != nil {
+			return true, err
+		}
+		if c.groovyClient.IsChanged(groovy.ConfigMapsSource, configurationsName, hash) {
+			message := "Configuration as Code has changed, restarting Jenkins master pod"
+			c.logger.Info(message)
+			return true, c.RestartJenkinsMasterPod(reason.NewPodRestart(reason.OperatorSource, []string{message}))
+		}
+	}
+
+	requeue, err = c.groovyClient.Ensure(func(name string) bool {
 		return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")
 	}, func(groovyScript string) string {
 		return fmt.Sprintf(applyConfigurationAsCodeGroovyScriptFmt, prepareScript(groovyScript))
 	})
+	if err != nil || requeue || len(hash) == 0 {
+		return requeue, err
+	}
+	// the applied ConfigMaps are recorded to restart Jenkins master pod when they change
+	return false, c.groovyClient.SetApplied(groovy.ConfigMapsSource, configurationsName, hash)
+}
+
+// ensureHotReload waits until the ConfigMaps mounted into Jenkins master pod are synchronized and reloads
+// the Configuration as Code when the ConfigMaps or the Secret have changed
+func (c *configurationAsCode) ensureHotReload() (requeue bool, err error) {
+	requeue, err = c.groovyClient.WaitForConfigMapsSynchronization(resources.ConfigurationAsCodeConfigurationsVolumePath)
+	if err != nil || requeue {
+		return requeue, err
+	}
+
+	hash, err := c.groovyClient.CustomizationHash()
+	if err != nil {
+		return true, err
+	}
+	return c.groovyClient.EnsureSingleFunc(groovy.ConfigMapsSource, configurationsName, hash, func() (string, error) {
+		c.logger.Info("Reloading Configuration as Code")
+		return "", c.jenkinsClient.ReloadConfigurationAsCode()
+	})
 }
 
 const applyConfigurationAsCodeGroovyScriptFmt = `
@@ -4,7 +4,18 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestSplitToLongScript(t *testing.T) {
@@ -26,3 +37,38 @@ func TestSplitToLongScript(t *testing.T) {
 		assert.Equal(t, tooLongString, combinedString, "Initial divided string is not the same as combined")
 	})
 }
+
+func TestConfigurationAsCode_EnsureHotReload(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{ConfigurationAsCode: v1alpha2.ConfigurationAsCode{
+			Customization:  v1alpha2.Customization{Configurations: []v1alpha2.ConfigMapRef{{Name: "casc"}}},
+			ReloadStrategy: v1alpha2.HotReloadReloadStrategy,
+		}},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "casc", Namespace: "default"},
+		Data:       map[string]string{"jenkins.yaml": "jenkins:\n  systemMessage: hot reload"},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(jenkins, configMap).Build()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+	cascClient := New(jenkinsClient, configuration.Configuration{Client: fakeClient, Jenkins: jenkins})
+
+	jenkinsClient.EXPECT().ExecuteScript(gomock.Any()).Return("", nil)
+	requeue, err := cascClient.Ensure(jenkins)
+	require.NoError(t, err)
+	assert.True(t, requeue)
+
+	jenkinsClient.EXPECT().ReloadConfigurationAsCode().Return(nil)
+	requeue, err = cascClient.Ensure(jenkins)
+	require.NoError(t, err)
+	assert.True(t, requeue)
+
+	requeue, err = cascClient.Ensure(jenkins)
+	require.NoError(t, err)
+	assert.False(t, requeue)
+}
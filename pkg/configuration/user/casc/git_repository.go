@@ -0,0 +1,221 @@
+package casc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	gitRepositorySyncFailedEventReason k8sevent.Reason = "ConfigurationAsCodeGitSyncFailed"
+
+	// gitRepositoryUsernameSecretKey is the username data key in spec.configurationAsCode.gitRepository.credentialsSecret
+	gitRepositoryUsernameSecretKey = "username"
+	// gitRepositoryPasswordSecretKey is the password data key in spec.configurationAsCode.gitRepository.credentialsSecret
+	gitRepositoryPasswordSecretKey = "password"
+
+	// maxGitRepositoryConfigMapSize is the maximum size of the YAML files stored in the managed ConfigMap
+	maxGitRepositoryConfigMapSize = 1024 * 1024
+)
+
+// pullGitRepositoryScript clones the branch ($2) of the Git repository ($1) into a temporary directory of Jenkins master
+// container and prints the commit followed by the name and the content of every YAML file in the path ($3), all
+// terminated by NUL characters. The files are read from the Git objects instead of a checkout so symbolic links
+// can't point outside of the repository, only regular files are printed. The credentials are read from stdin to
+// keep them out of the process list.
+const pullGitRepositoryScript = `set -e
+IFS= read -r GIT_USERNAME || true
+IFS= read -r GIT_PASSWORD || true
+export GIT_USERNAME GIT_PASSWORD GIT_TERMINAL_PROMPT=0
+dir=$(mktemp -d)
+trap 'rm -rf "$dir"' EXIT
+git -c credential.helper='!f() { echo "username=$GIT_USERNAME"; echo "password=$GIT_PASSWORD"; }; f' \
+    clone --quiet --no-checkout --depth 1 --branch "$2" -- "$1" "$dir/repository"
+cd "$dir/repository"
+printf '%s\0' "$(git rev-parse HEAD)"
+if [ "$(git cat-file -t "HEAD:$3" 2> /dev/null)" != "tree" ]; then
+    echo "path '$3' is not a directory in the repository" >&2
+    exit 1
+fi
+git ls-tree "HEAD:$3" > "$dir/files"
+while read -r mode type object file; do
+    case "$file" in
+        *.yaml|*.yml) ;;
+        *) continue ;;
+    esac
+    if [ "$type" = "blob" ] && { [ "$mode" = "100644" ] || [ "$mode" = "100755" ]; }; then
+        printf '%s\0' "$file"
+        git cat-file blob "$object"
+        printf '\0'
+    fi
+done < "$dir/files"
+`
+
+// SyncGitRepository pulls the YAML files from spec.configurationAsCode.gitRepository into the managed ConfigMap when
+// the sync interval has elapsed or the repository has changed, the returned result requeues the next pull
+func (c *configurationAsCode) SyncGitRepository(jenkins *v1alpha2.Jenkins) (reconcile.Result, error) {
+	repository := jenkins.Spec.ConfigurationAsCode.GitRepository
+	if repository == nil {
+		if jenkins.Status.ConfigurationAsCodeGitRepository == nil {
+			return reconcile.Result{}, nil
+		}
+		jenkins.Status.ConfigurationAsCodeGitRepository = nil
+		return reconcile.Result{}, stackerr.WithStack(c.Client.Status().Update(context.TODO(), jenkins))
+	}
+
+	branch := resources.GetConfigurationAsCodeGitBranch(*repository)
+	interval := resources.GetConfigurationAsCodeGitSyncInterval(*repository)
+	status := jenkins.Status.ConfigurationAsCodeGitRepository
+	sameRepository := status != nil && status.URL == repository.URL && status.Branch == branch && status.Path == repository.Path
+	if sameRepository && status.LastSyncTime != nil {
+		if wait := time.Until(status.LastSyncTime.Add(interval)); wait > 0 {
+			return reconcile.Result{RequeueAfter: wait}, nil
+		}
+	}
+
+	now := metav1.Now()
+	newStatus := &v1alpha2.GitRepositoryStatus{URL: repository.URL, Branch: branch, Path: repository.Path, LastSyncTime: &now}
+	if sameRepository {
+		newStatus.Revision = status.Revision
+	}
+	revision, files, err := c.pullGitRepository(jenkins, *repository)
+	if err != nil {
+		newStatus.Message = err.Error()
+		return reconcile.Result{RequeueAfter: interval}, c.failGitRepositorySync(jenkins, status, newStatus)
+	}
+	if err = c.ensureGitRepositoryConfigMap(jenkins, files); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	firstPull := len(newStatus.Revision) == 0
+	if newStatus.Revision != revision {
+		c.logger.Info(fmt.Sprintf("Pulled Configuration as Code from Git repository '%s' branch '%s' revision '%s'", repository.URL, branch, revision))
+	}
+	newStatus.Revision = revision
+	jenkins.Status.ConfigurationAsCodeGitRepository = newStatus
+	if err = c.Client.Status().Update(context.TODO(), jenkins); err != nil {
+		return reconcile.Result{}, stackerr.WithStack(err)
+	}
+	if firstPull {
+		// the managed ConfigMap is applied with the other configurations in the next reconcile loop
+		return reconcile.Result{Requeue: true}, nil
+	}
+	return reconcile.Result{RequeueAfter: interval}, nil
+}
+
+// failGitRepositorySync records the failed pull in status, the YAML files of the previous revision are still applied
+func (c *configurationAsCode) failGitRepositorySync(jenkins *v1alpha2.Jenkins, previous, status *v1alpha2.GitRepositoryStatus) error {
+	jenkins.Status.ConfigurationAsCodeGitRepository = status
+	if err := c.Client.Status().Update(context.TODO(), jenkins); err != nil {
+		return stackerr.WithStack(err)
+	}
+	if previous != nil && previous.Message == status.Message {
+		return nil
+	}
+
+	message := fmt.Sprintf("Pulling Configuration as Code from Git repository '%s' branch '%s' failed", status.URL, status.Branch)
+	c.logger.Info(fmt.Sprintf("%s: %s", message, status.Message))
+	if c.Configuration.Events != nil {
+		c.Configuration.Events.Emit(jenkins, k8sevent.TypeWarning, gitRepositorySyncFailedEventReason, fmt.Sprintf("%s: %s", message, status.Message))
+	}
+	if c.Notifications != nil {
+		*c.Notifications <- event.Event{
+			Jenkins: *jenkins,
+			Phase:   event.PhaseUser,
+			Level:   v1alpha2.NotificationLevelWarning,
+			Reason:  reason.NewUserConfigurationFailed(reason.OperatorSource, []string{message}, fmt.Sprintf("%s: %s", message, status.Message)),
+		}
+	}
+	return nil
+}
+
+// pullGitRepositoryInJenkinsMasterPod executes pullGitRepositoryScript in Jenkins master container, the image of
+// Jenkins has git installed
+func (c *configurationAsCode) pullGitRepositoryInJenkinsMasterPod(jenkins *v1alpha2.Jenkins, repository v1alpha2.ConfigurationAsCodeGitRepository) (string, map[string]string, error) {
+	var username, password string
+	if repository.CredentialsSecret != nil {
+		secret := &corev1.Secret{}
+		err := c.Client.Get(context.TODO(), types.NamespacedName{Name: repository.CredentialsSecret.Name, Namespace: jenkins.Namespace}, secret)
+		if err != nil {
+			return "", nil, stackerr.WithStack(err)
+		}
+		username = string(secret.Data[gitRepositoryUsernameSecretKey])
+		password = string(secret.Data[gitRepositoryPasswordSecretKey])
+		// the credentials are passed line by line to the script and to git credential helper
+		for key, value := range map[string]string{gitRepositoryUsernameSecretKey: username, gitRepositoryPasswordSecretKey: password} {
+			if strings.ContainsAny(value, "\r\n") {
+				return "", nil, stackerr.Errorf("data '%s' in secret '%s' can't contain line breaks", key, repository.CredentialsSecret.Name)
+			}
+		}
+	}
+
+	command := []string{"sh", "-c", pullGitRepositoryScript, "pull-git-repository",
+		repository.URL, resources.GetConfigurationAsCodeGitBranch(repository), repository.Path}
+	stdin := strings.NewReader(fmt.Sprintf("%s\n%s\n", username, password))
+	stdout := &bytes.Buffer{}
+	stderr, err := c.ExecStream(resources.GetJenkinsMasterPodName(jenkins), resources.JenkinsMasterContainerName, command, stdin, stdout)
+	if err != nil && stderr.Len() > 0 {
+		return "", nil, stackerr.Errorf("git clone failed: %s", strings.TrimSpace(stderr.String()))
+	} else if err != nil {
+		return "", nil, err
+	}
+	return parseGitRepositoryFiles(stdout.String())
+}
+
+// parseGitRepositoryFiles parses the output of pullGitRepositoryScript into the commit and the YAML files
+func parseGitRepositoryFiles(output string) (string, map[string]string, error) {
+	fields := strings.Split(output, "\x00")
+	if len(fields) < 2 || len(fields)%2 != 0 || fields[len(fields)-1] != "" || len(fields[0]) == 0 {
+		return "", nil, stackerr.New("unexpected output of git clone")
+	}
+
+	files := map[string]string{}
+	size := 0
+	for i := 1; i < len(fields)-1; i += 2 {
+		name, content := fields[i], fields[i+1]
+		if errs := validation.IsConfigMapKey(name); len(errs) > 0 {
+			return "", nil, stackerr.Errorf("file '%s' can't be stored in ConfigMap: %s", name, strings.Join(errs, ", "))
+		}
+		size += len(name) + len(content)
+		files[name] = content
+	}
+	if size > maxGitRepositoryConfigMapSize {
+		return "", nil, stackerr.Errorf("YAML files have %d bytes, ConfigMap can hold at most %d bytes", size, maxGitRepositoryConfigMapSize)
+	}
+	return fields[0], files, nil
+}
+
+// ensureGitRepositoryConfigMap stores the pulled YAML files in the managed ConfigMap
+func (c *configurationAsCode) ensureGitRepositoryConfigMap(jenkins *v1alpha2.Jenkins, files map[string]string) error {
+	meta := resources.NewResourceObjectMeta(jenkins)
+	meta.Name = resources.GetConfigurationAsCodeGitRepositoryConfigMapName(jenkins)
+	configMap := &corev1.ConfigMap{}
+	err := c.Client.Get(context.TODO(), types.NamespacedName{Name: meta.Name, Namespace: meta.Namespace}, configMap)
+	if apierrors.IsNotFound(err) {
+		return stackerr.WithStack(c.CreateResource(&corev1.ConfigMap{ObjectMeta: meta, Data: files}))
+	} else if err != nil {
+		return stackerr.WithStack(err)
+	}
+	if reflect.DeepEqual(configMap.Data, files) {
+		return nil
+	}
+	configMap.Data = files
+	return stackerr.WithStack(c.UpdateResource(configMap))
+}
@@ -0,0 +1,216 @@
+package casc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseGitRepositoryFiles(t *testing.T) {
+	t.Run("files", func(t *testing.T) {
+		revision, files, err := parseGitRepositoryFiles("abc123\x00jenkins.yaml\x00jenkins:\n  systemMessage: git\n\x00tools.yml\x00tool: {}\n\x00")
+
+		require.NoError(t, err)
+		assert.Equal(t, "abc123", revision)
+		assert.Equal(t, map[string]string{"jenkins.yaml": "jenkins:\n  systemMessage: git\n", "tools.yml": "tool: {}\n"}, files)
+	})
+	t.Run("no files", func(t *testing.T) {
+		revision, files, err := parseGitRepositoryFiles("abc123\x00")
+
+		require.NoError(t, err)
+		assert.Equal(t, "abc123", revision)
+		assert.Empty(t, files)
+	})
+	t.Run("truncated output", func(t *testing.T) {
+		_, _, err := parseGitRepositoryFiles("abc123\x00jenkins.yaml\x00jenkins:")
+
+		assert.EqualError(t, err, "unexpected output of git clone")
+	})
+	t.Run("invalid ConfigMap key", func(t *testing.T) {
+		_, _, err := parseGitRepositoryFiles("abc123\x00my jenkins.yaml\x00jenkins: {}\x00")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "file 'my jenkins.yaml' can't be stored in ConfigMap")
+	})
+}
+
+func TestConfigurationAsCode_SyncGitRepository(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{ConfigurationAsCode: v1alpha2.ConfigurationAsCode{
+			GitRepository: &v1alpha2.ConfigurationAsCodeGitRepository{URL: "https://git.example.com/jenkins-config.git", Path: "casc"},
+		}},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(jenkins).Build()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	notifications := make(chan event.Event, 1)
+	cascClient := New(jenkinsclient.NewMockJenkins(ctrl), configuration.Configuration{
+		Client:        fakeClient,
+		Jenkins:       jenkins,
+		Scheme:        scheme.Scheme,
+		Notifications: &notifications,
+	}).(*configurationAsCode)
+	pulls := 0
+	revision, files, pullErr := "abc123", map[string]string{"jenkins.yaml": "jenkins:\n  systemMessage: git"}, error(nil)
+	cascClient.pullGitRepository = func(_ *v1alpha2.Jenkins, repository v1alpha2.ConfigurationAsCodeGitRepository) (string, map[string]string, error) {
+		assert.Equal(t, "casc", repository.Path)
+		pulls++
+		return revision, files, pullErr
+	}
+	getConfigMap := func(t *testing.T) *corev1.ConfigMap {
+		configMap := &corev1.ConfigMap{}
+		err := fakeClient.Get(context.TODO(), types.NamespacedName{Name: "jenkins-operator-casc-git-jenkins", Namespace: "default"}, configMap)
+		require.NoError(t, err)
+		return configMap
+	}
+	expireSyncInterval := func() {
+		lastSyncTime := metav1.NewTime(time.Now().Add(-resources.DefaultConfigurationAsCodeGitSyncInterval))
+		jenkins.Status.ConfigurationAsCodeGitRepository.LastSyncTime = &lastSyncTime
+	}
+
+	// the first pull
+	result, err := cascClient.SyncGitRepository(jenkins)
+
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.Equal(t, files, getConfigMap(t).Data)
+	status := jenkins.Status.ConfigurationAsCodeGitRepository
+	require.NotNil(t, status)
+	assert.Equal(t, "abc123", status.Revision)
+	assert.Equal(t, "master", status.Branch)
+	assert.Equal(t, []v1alpha2.ConfigMapRef{{Name: "jenkins-operator-casc-git-jenkins"}}, resources.GetConfigurationAsCodeCustomization(jenkins).Configurations)
+
+	// the repository isn't pulled until the sync interval elapses
+	result, err = cascClient.SyncGitRepository(jenkins)
+
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+	assert.True(t, result.RequeueAfter > 0 && result.RequeueAfter <= resources.DefaultConfigurationAsCodeGitSyncInterval)
+	assert.Equal(t, 1, pulls)
+
+	// a new revision
+	expireSyncInterval()
+	revision, files = "def456", map[string]string{"jenkins.yaml": "jenkins:\n  systemMessage: updated"}
+
+	result, err = cascClient.SyncGitRepository(jenkins)
+
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+	assert.Equal(t, resources.DefaultConfigurationAsCodeGitSyncInterval, result.RequeueAfter)
+	assert.Equal(t, files, getConfigMap(t).Data)
+	assert.Equal(t, "def456", jenkins.Status.ConfigurationAsCodeGitRepository.Revision)
+
+	// a failed pull keeps the previous revision
+	expireSyncInterval()
+	pullErr = errors.New("git clone failed: fatal: Authentication failed")
+
+	result, err = cascClient.SyncGitRepository(jenkins)
+
+	require.NoError(t, err)
+	assert.Equal(t, resources.DefaultConfigurationAsCodeGitSyncInterval, result.RequeueAfter)
+	assert.Equal(t, "def456", jenkins.Status.ConfigurationAsCodeGitRepository.Revision)
+	assert.Equal(t, "git clone failed: fatal: Authentication failed", jenkins.Status.ConfigurationAsCodeGitRepository.Message)
+	assert.Equal(t, files, getConfigMap(t).Data)
+	require.Len(t, notifications, 1)
+	notification := <-notifications
+	assert.Equal(t, []string{"Pulling Configuration as Code from Git repository 'https://git.example.com/jenkins-config.git' branch 'master' failed"}, notification.Reason.Short())
+
+	// the same failure isn't notified again
+	expireSyncInterval()
+	_, err = cascClient.SyncGitRepository(jenkins)
+
+	require.NoError(t, err)
+	assert.Len(t, notifications, 0)
+	assert.Equal(t, 4, pulls)
+}
+
+func TestPullGitRepositoryScript(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repository := t.TempDir()
+	git := func(args ...string) {
+		command := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		command.Dir = repository
+		output, err := command.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	git("init", "--quiet", "--initial-branch", "main")
+	require.NoError(t, os.Mkdir(filepath.Join(repository, "casc"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(repository, "casc", "jenkins.yaml"), []byte("jenkins: {}\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(repository, "casc", "README.md"), []byte("docs\n"), 0644))
+	require.NoError(t, os.Symlink("/etc/passwd", filepath.Join(repository, "casc", "passwd.yaml")))
+	git("add", "--all")
+	git("commit", "--quiet", "--message", "casc")
+	run := func(path string) (string, error) {
+		command := exec.Command("sh", "-c", pullGitRepositoryScript, "pull-git-repository", "file://"+repository, "main", path)
+		command.Stdin = strings.NewReader("\n\n")
+		stderr := &strings.Builder{}
+		command.Stderr = stderr
+		output, err := command.Output()
+		if err != nil {
+			return "", fmt.Errorf("%s: %s", err, stderr)
+		}
+		return string(output), nil
+	}
+
+	t.Run("regular YAML files", func(t *testing.T) {
+		output, err := run("casc")
+
+		require.NoError(t, err)
+		revision, files, err := parseGitRepositoryFiles(output)
+		require.NoError(t, err)
+		assert.Len(t, revision, 40)
+		assert.Equal(t, map[string]string{"jenkins.yaml": "jenkins: {}\n"}, files)
+	})
+	t.Run("missing path", func(t *testing.T) {
+		_, err := run("missing")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestConfigurationAsCode_pullGitRepositoryInJenkinsMasterPod(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "git-credentials", Namespace: "default"},
+		Data:       map[string][]byte{"username": []byte("user"), "password": []byte("password\nprotocol=http")},
+	}
+	cascClient := &configurationAsCode{Configuration: configuration.Configuration{
+		Client:  fake.NewClientBuilder().WithObjects(secret).Build(),
+		Jenkins: jenkins,
+	}}
+
+	_, _, err := cascClient.pullGitRepositoryInJenkinsMasterPod(jenkins, v1alpha2.ConfigurationAsCodeGitRepository{
+		URL:               "https://git.example.com/jenkins-config.git",
+		CredentialsSecret: &v1alpha2.SecretRef{Name: "git-credentials"},
+	})
+
+	assert.EqualError(t, err, "data 'password' in secret 'git-credentials' can't contain line breaks")
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"strings"
+	"time"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
 	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
@@ -35,13 +36,15 @@ func New(configuration configuration.Configuration, jenkinsClient jenkinsclient.
 	return &reconcileUserConfiguration{
 		Configuration: configuration,
 		jenkinsClient: jenkinsClient,
-		logger:        log.Log.WithValues("cr", configuration.Jenkins.Name),
+		logger:        log.Log.WithValues("cr", configuration.Jenkins.Name, "namespace", configuration.Jenkins.Namespace),
 	}
 }
 
 // ReconcileCasc is a reconcile loop for casc.
 func (r *reconcileUserConfiguration) ReconcileCasc() (reconcile.Result, error) {
+	start := time.Now()
 	result, err := r.ensureCasc(r.jenkinsClient)
+	r.Timer.Record(configuration.UserGroovyScriptsPhase, start)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -49,7 +52,7 @@ func (r *reconcileUserConfiguration) ReconcileCasc() (reconcile.Result, error) {
 		return result, nil
 	}
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: result.RequeueAfter}, nil
 }
 
 // Reconcile it's a main reconciliation loop for user supplied configuration
@@ -67,10 +70,16 @@ func (r *reconcileUserConfiguration) ReconcileOthers() (reconcile.Result, error)
 	if err := backupAndRestore.Restore(r.jenkinsClient); err != nil {
 		return reconcile.Result{}, err
 	}
+	if err := backupAndRestore.RestoreDryRun(); err != nil {
+		return reconcile.Result{}, err
+	}
 
 	if err := backupAndRestore.Backup(false); err != nil {
 		return reconcile.Result{}, err
 	}
+	if err := backupAndRestore.EstimateBackup(); err != nil {
+		return reconcile.Result{}, err
+	}
 	if err := backupAndRestore.EnsureBackupTrigger(); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -91,7 +100,14 @@ func (r *reconcileUserConfiguration) ensureSeedJobs() (reconcile.Result, error)
 }
 
 func (r *reconcileUserConfiguration) ensureCasc(jenkinsClient jenkinsclient.Jenkins) (reconcile.Result, error) {
-	configurationAsCodeClient := casc.New(jenkinsClient, r.Client, r.Configuration.Jenkins)
+	configurationAsCodeClient := casc.New(jenkinsClient, r.Configuration)
+	gitRepositoryResult, err := configurationAsCodeClient.SyncGitRepository(r.Configuration.Jenkins)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if gitRepositoryResult.Requeue {
+		return gitRepositoryResult, nil
+	}
 	requeue, err := configurationAsCodeClient.Ensure(r.Configuration.Jenkins)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -100,7 +116,10 @@ func (r *reconcileUserConfiguration) ensureCasc(jenkinsClient jenkinsclient.Jenk
 		return reconcile.Result{Requeue: true}, nil
 	}
 
-	groovyClient := groovy.New(jenkinsClient, r.Client, r.Configuration.Jenkins, "user-groovy", r.Configuration.Jenkins.Spec.GroovyScripts.Customization)
+	groovyScripts := r.Configuration.Jenkins.Spec.GroovyScripts
+	groovyClient := groovy.New(jenkinsClient, r.Client, r.Configuration.Jenkins, "user-groovy", groovyScripts.Customization).
+		Parallel(groovyScripts.Parallelism, groovyScripts.Dependencies).
+		WithClusterDomain(r.Configuration.KubernetesClusterDomain)
 	requeue, err = groovyClient.WaitForSecretSynchronization(resources.GroovyScriptsSecretVolumePath)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -118,5 +137,6 @@ func (r *reconcileUserConfiguration) ensureCasc(jenkinsClient jenkinsclient.Jenk
 		return reconcile.Result{Requeue: true}, nil
 	}
 
-	return reconcile.Result{}, nil
+	// the Git repository with Configuration as Code is pulled again after the sync interval
+	return reconcile.Result{RequeueAfter: gitRepositoryResult.RequeueAfter}, nil
 }
@@ -0,0 +1,186 @@
+package seedjobs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+
+	stackerr "github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// agentSecretKey is the key of the seed job agent inbound secret in the Kubernetes Secret managed by the operator
+	agentSecretKey = "secret"
+	// minAgentSecretRotationInterval prevents rotations faster than the previous agent can be retired
+	minAgentSecretRotationInterval = time.Hour
+
+	agentRemovedOutput = "removed"
+	agentBusyOutput    = "busy"
+)
+
+// retireAgentGroovyScript marks the agent with the previous secret temporarily offline, so it doesn't accept new
+// builds, and removes it from Jenkins once its executors are idle
+const retireAgentGroovyScript = `
+import hudson.slaves.OfflineCause
+import jenkins.model.Jenkins
+
+def computer = Jenkins.instance.getComputer('%s')
+if (computer == null || computer.getNode() == null) {
+    print '` + agentRemovedOutput + `'
+    return
+}
+if (!computer.isTemporarilyOffline()) {
+    computer.setTemporarilyOffline(true, new OfflineCause.ByCLI('The seed job agent secret has been rotated'))
+}
+if (computer.countBusy() == 0) {
+    Jenkins.instance.removeNode(computer.getNode())
+    print '` + agentRemovedOutput + `'
+} else {
+    print '` + agentBusyOutput + `'
+}
+`
+
+// validateAgentSecret verifies the seed job agent secret rotation settings
+func validateAgentSecret(agentSecret *v1alpha2.SeedJobAgentSecret) []string {
+	if agentSecret == nil || agentSecret.RotationInterval == nil {
+		return nil
+	}
+	if agentSecret.RotationInterval.Duration < minAgentSecretRotationInterval {
+		return []string{fmt.Sprintf("spec.seedJobAgentSecret.rotationInterval must be at least %s, got '%s'",
+			minAgentSecretRotationInterval, agentSecret.RotationInterval.Duration)}
+	}
+	return nil
+}
+
+// agentNodeName returns the Jenkins node name of the current seed job agent
+func agentNodeName(jenkins v1alpha2.Jenkins) string {
+	if jenkins.Status.SeedJobAgent != nil && len(jenkins.Status.SeedJobAgent.NodeName) > 0 {
+		return jenkins.Status.SeedJobAgent.NodeName
+	}
+	return AgentName
+}
+
+// rotateAgentSecret registers the seed job agent under a new name when the rotation interval has elapsed, Jenkins
+// derives the inbound secret from the node name. The previous agent is retired by retireAgent.
+func (s *seedJobs) rotateAgentSecret(jenkins *v1alpha2.Jenkins) error {
+	agentSecret := jenkins.Spec.SeedJobAgentSecret
+	if agentSecret == nil || agentSecret.RotationInterval == nil {
+		return nil
+	}
+
+	now := metav1.Now()
+	status := jenkins.Status.SeedJobAgent
+	if status == nil || status.SecretRotationTime == nil {
+		// the age of the secret issued before the rotation has been enabled is unknown
+		jenkins.Status.SeedJobAgent = &v1alpha2.SeedJobAgentStatus{NodeName: agentNodeName(*jenkins), SecretRotationTime: &now}
+		return stackerr.WithStack(s.Client.Status().Update(context.TODO(), jenkins))
+	}
+	if now.Before(&metav1.Time{Time: status.SecretRotationTime.Add(agentSecret.RotationInterval.Duration)}) {
+		return nil
+	}
+	if len(status.RetiringNodeName) > 0 {
+		s.logger.Info(fmt.Sprintf("Seed job agent secret rotation is postponed until agent '%s' is retired", status.RetiringNodeName))
+		return nil
+	}
+
+	nodeName := fmt.Sprintf("%s-%d", AgentName, now.Unix())
+	s.logger.Info(fmt.Sprintf("Rotating seed job agent secret, agent '%s' is replaced by '%s'", status.NodeName, nodeName))
+	jenkins.Status.SeedJobAgent = &v1alpha2.SeedJobAgentStatus{
+		NodeName:           nodeName,
+		SecretRotationTime: &now,
+		RetiringNodeName:   status.NodeName,
+	}
+	return stackerr.WithStack(s.Client.Status().Update(context.TODO(), jenkins))
+}
+
+// retireAgent removes the seed job agent with the previous secret once its running builds finish, it doesn't block
+// the reconcile loop while the agent is busy
+func (s *seedJobs) retireAgent(jenkins *v1alpha2.Jenkins) error {
+	status := jenkins.Status.SeedJobAgent
+	if status == nil || len(status.RetiringNodeName) == 0 {
+		return nil
+	}
+
+	output, err := s.jenkinsClient.ExecuteScript(fmt.Sprintf(retireAgentGroovyScript, status.RetiringNodeName))
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(output) != agentRemovedOutput {
+		s.logger.Info(fmt.Sprintf("Waiting for running builds of seed job agent '%s' with the previous secret", status.RetiringNodeName))
+		return nil
+	}
+
+	if err = s.deleteAgentResources(*jenkins, status.RetiringNodeName); err != nil {
+		return err
+	}
+	s.logger.Info(fmt.Sprintf("Seed job agent '%s' with the previous secret has been removed", status.RetiringNodeName))
+	status.RetiringNodeName = ""
+	return stackerr.WithStack(s.Client.Status().Update(context.TODO(), jenkins))
+}
+
+// deleteAgentResources deletes the Deployment and the managed Secret of the seed job agent
+func (s *seedJobs) deleteAgentResources(jenkins v1alpha2.Jenkins, nodeName string) error {
+	objectMeta := metav1.ObjectMeta{Namespace: jenkins.Namespace, Name: agentDeploymentName(jenkins, nodeName)}
+	if err := s.Client.Delete(context.TODO(), &appsv1.Deployment{ObjectMeta: objectMeta}); err != nil && !apierrors.IsNotFound(err) {
+		return stackerr.WithStack(err)
+	}
+	objectMeta.Name = agentSecretName(jenkins, nodeName)
+	if err := s.Client.Delete(context.TODO(), &corev1.Secret{ObjectMeta: objectMeta}); err != nil && !apierrors.IsNotFound(err) {
+		return stackerr.WithStack(err)
+	}
+	return nil
+}
+
+func agentSecretName(jenkins v1alpha2.Jenkins, nodeName string) string {
+	return fmt.Sprintf("%s-secret", agentDeploymentName(jenkins, nodeName))
+}
+
+func isAgentSecretManaged(jenkins v1alpha2.Jenkins) bool {
+	return jenkins.Spec.SeedJobAgentSecret != nil && jenkins.Spec.SeedJobAgentSecret.RequireManagedSecret
+}
+
+// ensureAgentSecret stores the inbound secret of the seed job agent in the Kubernetes Secret referenced by the agent pod
+func (s *seedJobs) ensureAgentSecret(jenkins *v1alpha2.Jenkins, namespace, nodeName, agentSecret string) error {
+	secret := &corev1.Secret{}
+	name := agentSecretName(*jenkins, nodeName)
+	err := s.Client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, secret)
+	if apierrors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				Labels:      resources.MergeMaps(jenkins.Spec.CommonLabels),
+				Annotations: resources.MergeMaps(jenkins.Spec.CommonAnnotations),
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						BlockOwnerDeletion: &[]bool{true}[0],
+						Controller:         &[]bool{true}[0],
+						Kind:               jenkins.Kind,
+						Name:               jenkins.Name,
+						APIVersion:         jenkins.APIVersion,
+						UID:                jenkins.UID,
+					},
+				},
+			},
+			Data: map[string][]byte{agentSecretKey: []byte(agentSecret)},
+		}
+		return stackerr.WithStack(s.Client.Create(context.TODO(), secret))
+	} else if err != nil {
+		return stackerr.WithStack(err)
+	}
+
+	if string(secret.Data[agentSecretKey]) == agentSecret {
+		return nil
+	}
+	secret.Data = map[string][]byte{agentSecretKey: []byte(agentSecret)}
+	return stackerr.WithStack(s.Client.Update(context.TODO(), secret))
+}
@@ -0,0 +1,195 @@
+package seedjobs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateAgentSecret(t *testing.T) {
+	t.Run("rotation disabled", func(t *testing.T) {
+		assert.Empty(t, validateAgentSecret(&v1alpha2.SeedJobAgentSecret{RequireManagedSecret: true}))
+	})
+	t.Run("valid rotation interval", func(t *testing.T) {
+		assert.Empty(t, validateAgentSecret(&v1alpha2.SeedJobAgentSecret{RotationInterval: &metav1.Duration{Duration: 24 * time.Hour}}))
+	})
+	t.Run("too short rotation interval", func(t *testing.T) {
+		got := validateAgentSecret(&v1alpha2.SeedJobAgentSecret{RotationInterval: &metav1.Duration{Duration: time.Minute}})
+
+		assert.Equal(t, []string{"spec.seedJobAgentSecret.rotationInterval must be at least 1h0m0s, got '1m0s'"}, got)
+	})
+}
+
+func TestRotateAgentSecret(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	newSeedJobs := func(t *testing.T, jenkins *v1alpha2.Jenkins) *seedJobs {
+		fakeClient := fake.NewClientBuilder().WithObjects(jenkins).Build()
+		return New(nil, configuration.Configuration{Client: fakeClient, Jenkins: jenkins}).(*seedJobs)
+	}
+	rotatingJenkins := func() *v1alpha2.Jenkins {
+		jenkins := jenkinsCustomResource()
+		jenkins.Spec.SeedJobAgentSecret = &v1alpha2.SeedJobAgentSecret{RotationInterval: &metav1.Duration{Duration: 24 * time.Hour}}
+		return jenkins
+	}
+
+	t.Run("rotation disabled", func(t *testing.T) {
+		jenkins := jenkinsCustomResource()
+
+		err := newSeedJobs(t, jenkins).rotateAgentSecret(jenkins)
+
+		require.NoError(t, err)
+		assert.Nil(t, jenkins.Status.SeedJobAgent)
+	})
+	t.Run("rotation enabled for existing agent", func(t *testing.T) {
+		jenkins := rotatingJenkins()
+
+		err := newSeedJobs(t, jenkins).rotateAgentSecret(jenkins)
+
+		require.NoError(t, err)
+		require.NotNil(t, jenkins.Status.SeedJobAgent)
+		assert.Equal(t, AgentName, jenkins.Status.SeedJobAgent.NodeName)
+		assert.NotNil(t, jenkins.Status.SeedJobAgent.SecretRotationTime)
+		assert.Empty(t, jenkins.Status.SeedJobAgent.RetiringNodeName)
+	})
+	t.Run("rotation interval not elapsed", func(t *testing.T) {
+		jenkins := rotatingJenkins()
+		rotationTime := metav1.NewTime(time.Now().Add(-time.Hour))
+		jenkins.Status.SeedJobAgent = &v1alpha2.SeedJobAgentStatus{NodeName: AgentName, SecretRotationTime: &rotationTime}
+
+		err := newSeedJobs(t, jenkins).rotateAgentSecret(jenkins)
+
+		require.NoError(t, err)
+		assert.Equal(t, AgentName, jenkins.Status.SeedJobAgent.NodeName)
+		assert.Empty(t, jenkins.Status.SeedJobAgent.RetiringNodeName)
+	})
+	t.Run("rotation interval elapsed", func(t *testing.T) {
+		jenkins := rotatingJenkins()
+		rotationTime := metav1.NewTime(time.Now().Add(-25 * time.Hour))
+		jenkins.Status.SeedJobAgent = &v1alpha2.SeedJobAgentStatus{NodeName: AgentName, SecretRotationTime: &rotationTime}
+
+		err := newSeedJobs(t, jenkins).rotateAgentSecret(jenkins)
+
+		require.NoError(t, err)
+		status := jenkins.Status.SeedJobAgent
+		assert.Equal(t, fmt.Sprintf("%s-%d", AgentName, status.SecretRotationTime.Unix()), status.NodeName)
+		assert.Equal(t, AgentName, status.RetiringNodeName)
+	})
+	t.Run("previous agent not retired yet", func(t *testing.T) {
+		jenkins := rotatingJenkins()
+		rotationTime := metav1.NewTime(time.Now().Add(-25 * time.Hour))
+		jenkins.Status.SeedJobAgent = &v1alpha2.SeedJobAgentStatus{
+			NodeName:           "seed-job-agent-1",
+			SecretRotationTime: &rotationTime,
+			RetiringNodeName:   AgentName,
+		}
+
+		err := newSeedJobs(t, jenkins).rotateAgentSecret(jenkins)
+
+		require.NoError(t, err)
+		assert.Equal(t, "seed-job-agent-1", jenkins.Status.SeedJobAgent.NodeName)
+		assert.Equal(t, AgentName, jenkins.Status.SeedJobAgent.RetiringNodeName)
+	})
+}
+
+func TestRetireAgent(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	retiringNodeName := AgentName
+	setup := func(t *testing.T, output string) (*v1alpha2.Jenkins, *seedJobs) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		jenkins := jenkinsCustomResource()
+		rotationTime := metav1.Now()
+		jenkins.Status.SeedJobAgent = &v1alpha2.SeedJobAgentStatus{
+			NodeName:           "seed-job-agent-1",
+			SecretRotationTime: &rotationTime,
+			RetiringNodeName:   retiringNodeName,
+		}
+		fakeClient := fake.NewClientBuilder().WithObjects(
+			jenkins,
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: agentDeploymentName(*jenkins, retiringNodeName), Namespace: jenkins.Namespace}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: agentSecretName(*jenkins, retiringNodeName), Namespace: jenkins.Namespace}},
+		).Build()
+		jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(fmt.Sprintf(retireAgentGroovyScript, retiringNodeName)).Return(output, nil)
+
+		return jenkins, New(jenkinsClient, configuration.Configuration{Client: fakeClient, Jenkins: jenkins}).(*seedJobs)
+	}
+
+	t.Run("previous agent is busy", func(t *testing.T) {
+		jenkins, seedJobsClient := setup(t, agentBusyOutput)
+
+		err := seedJobsClient.retireAgent(jenkins)
+
+		require.NoError(t, err)
+		assert.Equal(t, retiringNodeName, jenkins.Status.SeedJobAgent.RetiringNodeName)
+		err = seedJobsClient.Client.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: agentDeploymentName(*jenkins, retiringNodeName)}, &appsv1.Deployment{})
+		assert.NoError(t, err)
+	})
+	t.Run("previous agent is removed", func(t *testing.T) {
+		jenkins, seedJobsClient := setup(t, agentRemovedOutput)
+
+		err := seedJobsClient.retireAgent(jenkins)
+
+		require.NoError(t, err)
+		assert.Empty(t, jenkins.Status.SeedJobAgent.RetiringNodeName)
+		assert.Equal(t, "seed-job-agent-1", jenkins.Status.SeedJobAgent.NodeName)
+		err = seedJobsClient.Client.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: agentDeploymentName(*jenkins, retiringNodeName)}, &appsv1.Deployment{})
+		assert.True(t, errors.IsNotFound(err), "Agent deployment hasn't been deleted")
+		err = seedJobsClient.Client.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: agentSecretName(*jenkins, retiringNodeName)}, &corev1.Secret{})
+		assert.True(t, errors.IsNotFound(err), "Agent secret hasn't been deleted")
+	})
+}
+
+func TestEnsureAgentSecret(t *testing.T) {
+	jenkins := jenkinsCustomResource()
+	jenkins.Spec.SeedJobAgentSecret = &v1alpha2.SeedJobAgentSecret{RequireManagedSecret: true}
+	fakeClient := fake.NewClientBuilder().Build()
+	seedJobsClient := New(nil, configuration.Configuration{Client: fakeClient, Jenkins: jenkins}).(*seedJobs)
+	name := types.NamespacedName{Namespace: jenkins.Namespace, Name: agentSecretName(*jenkins, AgentName)}
+
+	t.Run("create", func(t *testing.T) {
+		err := seedJobsClient.ensureAgentSecret(jenkins, jenkins.Namespace, AgentName, agentSecret)
+
+		require.NoError(t, err)
+		secret := &corev1.Secret{}
+		require.NoError(t, fakeClient.Get(context.TODO(), name, secret))
+		assert.Equal(t, agentSecret, string(secret.Data[agentSecretKey]))
+	})
+	t.Run("update", func(t *testing.T) {
+		err := seedJobsClient.ensureAgentSecret(jenkins, jenkins.Namespace, AgentName, "new-secret")
+
+		require.NoError(t, err)
+		secret := &corev1.Secret{}
+		require.NoError(t, fakeClient.Get(context.TODO(), name, secret))
+		assert.Equal(t, "new-secret", string(secret.Data[agentSecretKey]))
+	})
+	t.Run("agent references managed secret", func(t *testing.T) {
+		deployment, err := agentDeployment(jenkins, jenkins.Namespace, AgentName, agentSecret, "cluster.local")
+
+		require.NoError(t, err)
+		env := deployment.Spec.Template.Spec.Containers[0].Env
+		require.Equal(t, "JENKINS_SECRET", env[1].Name)
+		assert.Empty(t, env[1].Value)
+		assert.Equal(t, &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name.Name},
+			Key:                  agentSecretKey,
+		}, env[1].ValueFrom.SecretKeyRef)
+	})
+}
@@ -0,0 +1,273 @@
+package seedjobs
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/constants"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultDeepValidationImage is the default image with git used to validate seed job repository access
+	defaultDeepValidationImage = "alpine/git:v2.30.2"
+
+	deepValidationLabelName          = "jenkins.io/seed-job-validation"
+	deepValidationSeedJobAnnotation  = "jenkins.io/seed-job-id"
+	deepValidationHashAnnotation     = "jenkins.io/seed-job-validation-hash"
+	deepValidationContainerName      = "git"
+	deepValidationCredentialVolume   = "credential"
+	deepValidationCredentialPath     = "/var/run/secrets/seed-job-credential"
+	deepValidationTimeoutSeconds     = int64(120)
+	deepValidationUsernameEnvName    = "GIT_USERNAME"
+	deepValidationPasswordEnvName    = "GIT_PASSWORD"
+	deepValidationCredentialHelper   = `credential.helper=!f() { echo "username=$GIT_USERNAME"; echo "password=$GIT_PASSWORD"; }; f`
+	deepValidationPrivateKeyFileMode = int32(0400)
+	// deepValidationBranchNotFoundExitCode is returned by git ls-remote --exit-code when no matching refs are found
+	deepValidationBranchNotFoundExitCode = 2
+	// deepValidationRetryInterval is the time after which the failed validation pod is deleted and the validation is
+	// run again, so a fixed repository or a temporary network failure isn't reported forever
+	deepValidationRetryInterval = 5 * time.Minute
+)
+
+// validateDeepValidationSpec verifies if the seed job repository access can be validated by git ls-remote
+func validateDeepValidationSpec(seedJob v1alpha2.SeedJob) []string {
+	var messages []string
+	if seedJob.BranchSource != nil {
+		messages = append(messages, fmt.Sprintf("seedJob `%s` deep validation can't be used with branch source", seedJob.ID))
+	}
+	if seedJob.JenkinsCredentialType == v1alpha2.GithubAppCredentialType {
+		messages = append(messages, fmt.Sprintf("seedJob `%s` deep validation can't be used with '%s' credential type",
+			seedJob.ID, v1alpha2.GithubAppCredentialType))
+	}
+	return messages
+}
+
+// validateRepositoryAccess runs git ls-remote with the seed job credential in a short-lived pod, the pod is recreated
+// when the repository or the credential changes. The validation in progress doesn't block the seed job, only the
+// failed one is reported until it's retried.
+func (s *seedJobs) validateRepositoryAccess(jenkins v1alpha2.Jenkins, seedJob v1alpha2.SeedJob) ([]string, error) {
+	secret := &corev1.Secret{}
+	if seedJob.JenkinsCredentialType != v1alpha2.NoJenkinsCredentialCredentialType {
+		err := s.Client.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: seedJob.CredentialID}, secret)
+		if err != nil {
+			return nil, stackerr.WithStack(err)
+		}
+	}
+	hash := deepValidationHash(seedJob, secret)
+	name := deepValidationPodName(jenkins, seedJob.ID)
+
+	pod := &corev1.Pod{}
+	err := s.Client.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: name}, pod)
+	if apierrors.IsNotFound(err) {
+		s.logger.Info(fmt.Sprintf("Validating seed job '%s' repository access by pod '%s'", seedJob.ID, name))
+		err = s.CreateResource(deepValidationPod(jenkins, seedJob, name, hash))
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return nil, stackerr.WithStack(err)
+		}
+		return nil, nil
+	} else if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+
+	if pod.Annotations[deepValidationHashAnnotation] != hash {
+		// the repository or the credential has changed, the pod is created again in the next reconcile loop
+		if pod.DeletionTimestamp == nil {
+			if err = s.Client.Delete(context.TODO(), pod); err != nil && !apierrors.IsNotFound(err) {
+				return nil, stackerr.WithStack(err)
+			}
+		}
+		return nil, nil
+	}
+	if pod.Status.Phase != corev1.PodFailed {
+		return nil, nil
+	}
+	if pod.DeletionTimestamp == nil && time.Since(deepValidationFinishedAt(*pod)) > deepValidationRetryInterval {
+		s.logger.Info(fmt.Sprintf("Retrying failed seed job '%s' repository access validation", seedJob.ID))
+		if err = s.Client.Delete(context.TODO(), pod); err != nil && !apierrors.IsNotFound(err) {
+			return nil, stackerr.WithStack(err)
+		}
+	}
+	return []string{fmt.Sprintf("seedJob `%s` git ls-remote of '%s' branch '%s' failed: %s",
+		seedJob.ID, seedJob.RepositoryURL, seedJob.RepositoryBranch, deepValidationFailure(*pod))}, nil
+}
+
+// cleanupDeepValidationPods deletes validation pods of seed jobs which have been removed or don't use deep validation
+func (s *seedJobs) cleanupDeepValidationPods(jenkins v1alpha2.Jenkins) error {
+	validated := map[string]bool{}
+	for _, seedJob := range jenkins.Spec.SeedJobs {
+		if seedJob.DeepValidation != nil {
+			validated[deepValidationPodName(jenkins, seedJob.ID)] = true
+		}
+	}
+
+	pods := &corev1.PodList{}
+	err := s.Client.List(context.TODO(), pods, client.InNamespace(jenkins.Namespace), client.MatchingLabels(deepValidationLabels(jenkins)))
+	if err != nil {
+		return stackerr.WithStack(err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if validated[pod.Name] || pod.DeletionTimestamp != nil {
+			continue
+		}
+		s.logger.Info(fmt.Sprintf("Deleting seed job validation pod '%s'", pod.Name))
+		if err = s.Client.Delete(context.TODO(), pod); err != nil && !apierrors.IsNotFound(err) {
+			return stackerr.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func deepValidationPodName(jenkins v1alpha2.Jenkins, seedJobID string) string {
+	// seed job ID isn't a valid Kubernetes name
+	idHash := sha256.Sum256([]byte(seedJobID))
+	return fmt.Sprintf("seed-job-validation-%s-%x", jenkins.Name, idHash[:5])
+}
+
+// deepValidationLabels mustn't match the selectors of Jenkins master services
+func deepValidationLabels(jenkins v1alpha2.Jenkins) map[string]string {
+	return map[string]string{
+		constants.LabelJenkinsCRKey: jenkins.Name,
+		deepValidationLabelName:     "true",
+	}
+}
+
+// deepValidationHash returns the hash of everything the result of the validation depends on
+func deepValidationHash(seedJob v1alpha2.SeedJob, secret *corev1.Secret) string {
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%s\n%s\n%s\n%s\n", deepValidationImage(seedJob), seedJob.RepositoryURL, seedJob.RepositoryBranch,
+		seedJob.JenkinsCredentialType)
+	var keys []string
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(hash, "%s=%x\n", key, secret.Data[key])
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+func deepValidationImage(seedJob v1alpha2.SeedJob) string {
+	if len(seedJob.DeepValidation.Image) > 0 {
+		return seedJob.DeepValidation.Image
+	}
+	return defaultDeepValidationImage
+}
+
+// deepValidationPod builds the pod which runs git ls-remote with the seed job credential, the credential is passed
+// by secret references only
+func deepValidationPod(jenkins v1alpha2.Jenkins, seedJob v1alpha2.SeedJob, name, hash string) *corev1.Pod {
+	lsRemote := []string{"ls-remote", "--exit-code", "--heads", "--", seedJob.RepositoryURL, seedJob.RepositoryBranch}
+	command := append([]string{"git"}, lsRemote...)
+	env := []corev1.EnvVar{{Name: "GIT_TERMINAL_PROMPT", Value: "0"}}
+	var volumes []corev1.Volume
+	var volumeMounts []corev1.VolumeMount
+
+	switch seedJob.JenkinsCredentialType {
+	case v1alpha2.BasicSSHCredentialType:
+		mode := deepValidationPrivateKeyFileMode
+		volumes = append(volumes, corev1.Volume{
+			Name: deepValidationCredentialVolume,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: seedJob.CredentialID,
+					Items:      []corev1.KeyToPath{{Key: PrivateKeySecretKey, Path: PrivateKeySecretKey, Mode: &mode}},
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: deepValidationCredentialVolume, MountPath: deepValidationCredentialPath, ReadOnly: true})
+		env = append(env, corev1.EnvVar{
+			Name: "GIT_SSH_COMMAND",
+			Value: fmt.Sprintf("ssh -i %s/%s -o IdentitiesOnly=yes -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null",
+				deepValidationCredentialPath, PrivateKeySecretKey),
+		})
+	case v1alpha2.UsernamePasswordCredentialType:
+		env = append(env,
+			corev1.EnvVar{Name: deepValidationUsernameEnvName, ValueFrom: secretKeyRef(seedJob.CredentialID, UsernameSecretKey)},
+			corev1.EnvVar{Name: deepValidationPasswordEnvName, ValueFrom: secretKeyRef(seedJob.CredentialID, PasswordSecretKey)},
+		)
+		command = append([]string{"git", "-c", deepValidationCredentialHelper}, lsRemote...)
+	}
+
+	activeDeadlineSeconds := deepValidationTimeoutSeconds
+	automountServiceAccountToken := false
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   jenkins.Namespace,
+			Labels:      deepValidationLabels(jenkins),
+			Annotations: map[string]string{deepValidationSeedJobAnnotation: seedJob.ID, deepValidationHashAnnotation: hash},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:                corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds:        &activeDeadlineSeconds,
+			AutomountServiceAccountToken: &automountServiceAccountToken,
+			NodeSelector:                 jenkins.Spec.Master.NodeSelector,
+			Tolerations:                  jenkins.Spec.Master.Tolerations,
+			ImagePullSecrets:             jenkins.Spec.Master.ImagePullSecrets,
+			Containers: []corev1.Container{
+				{
+					Name:                     deepValidationContainerName,
+					Image:                    deepValidationImage(seedJob),
+					Command:                  command,
+					Env:                      env,
+					VolumeMounts:             volumeMounts,
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+				},
+			},
+			Volumes: volumes,
+		},
+	}
+}
+
+func secretKeyRef(name, key string) *corev1.EnvVarSource {
+	return &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key},
+	}
+}
+
+// deepValidationFailure returns the output of the failed git ls-remote or the reason the pod failed
+func deepValidationFailure(pod corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		terminated := status.State.Terminated
+		if terminated == nil {
+			continue
+		}
+		if terminated.ExitCode == deepValidationBranchNotFoundExitCode {
+			return "branch not found"
+		}
+		if len(terminated.Message) > 0 {
+			return strings.TrimSpace(terminated.Message)
+		}
+	}
+	if len(pod.Status.Message) > 0 {
+		return pod.Status.Message
+	}
+	return pod.Status.Reason
+}
+
+// deepValidationFinishedAt returns the time the validation pod has failed
+func deepValidationFinishedAt(pod corev1.Pod) time.Time {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil && !status.State.Terminated.FinishedAt.IsZero() {
+			return status.State.Terminated.FinishedAt.Time
+		}
+	}
+	if pod.Status.StartTime != nil {
+		return pod.Status.StartTime.Time
+	}
+	return pod.CreationTimestamp.Time
+}
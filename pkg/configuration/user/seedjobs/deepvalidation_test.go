@@ -0,0 +1,162 @@
+package seedjobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateSeedJobs_DeepValidation(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	seedJob := v1alpha2.SeedJob{
+		ID:                    "jenkins-operator",
+		CredentialID:          "deploy-keys",
+		JenkinsCredentialType: v1alpha2.UsernamePasswordCredentialType,
+		Targets:               "cicd/jobs/*.jenkins",
+		RepositoryBranch:      "master",
+		RepositoryURL:         "https://github.com/maximba/kubernetes-operator.git",
+		DeepValidation:        &v1alpha2.SeedJobDeepValidation{},
+	}
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+		Spec:       v1alpha2.JenkinsSpec{SeedJobs: []v1alpha2.SeedJob{seedJob}},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "deploy-keys", Namespace: "default"},
+		Data:       map[string][]byte{UsernameSecretKey: []byte("user"), PasswordSecretKey: []byte("token")},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(jenkins, secret).Build()
+	seedJobs := New(nil, configuration.Configuration{Client: fakeClient, Jenkins: jenkins, Scheme: scheme.Scheme})
+	podName := types.NamespacedName{Namespace: "default", Name: deepValidationPodName(*jenkins, seedJob.ID)}
+	getPod := func(t *testing.T) *corev1.Pod {
+		pod := &corev1.Pod{}
+		require.NoError(t, fakeClient.Get(context.TODO(), podName, pod))
+		return pod
+	}
+
+	t.Run("validation pod created", func(t *testing.T) {
+		messages, err := seedJobs.ValidateSeedJobs(*jenkins)
+
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+		pod := getPod(t)
+		assert.True(t, metav1.IsControlledBy(pod, jenkins))
+		container := pod.Spec.Containers[0]
+		assert.Equal(t, defaultDeepValidationImage, container.Image)
+		assert.Equal(t, []string{"git", "-c", deepValidationCredentialHelper, "ls-remote", "--exit-code", "--heads", "--", seedJob.RepositoryURL, "master"},
+			container.Command)
+		require.Len(t, container.Env, 3)
+		assert.Equal(t, PasswordSecretKey, container.Env[2].ValueFrom.SecretKeyRef.Key)
+	})
+	t.Run("validation failed", func(t *testing.T) {
+		pod := getPod(t)
+		pod.Status.Phase = corev1.PodFailed
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode:   128,
+				Message:    "fatal: Authentication failed\n",
+				FinishedAt: metav1.Now(),
+			}},
+		}}
+		require.NoError(t, fakeClient.Update(context.TODO(), pod))
+
+		messages, err := seedJobs.ValidateSeedJobs(*jenkins)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"seedJob `jenkins-operator` git ls-remote of 'https://github.com/maximba/kubernetes-operator.git' " +
+			"branch 'master' failed: fatal: Authentication failed"}, messages)
+		assert.Equal(t, corev1.PodFailed, getPod(t).Status.Phase)
+	})
+	t.Run("failed validation retried", func(t *testing.T) {
+		pod := getPod(t)
+		pod.Status.ContainerStatuses[0].State.Terminated.FinishedAt = metav1.NewTime(time.Now().Add(-deepValidationRetryInterval - time.Minute))
+		require.NoError(t, fakeClient.Update(context.TODO(), pod))
+
+		messages, err := seedJobs.ValidateSeedJobs(*jenkins)
+
+		require.NoError(t, err)
+		assert.Len(t, messages, 1)
+		err = fakeClient.Get(context.TODO(), podName, &corev1.Pod{})
+		assert.True(t, apierrors.IsNotFound(err))
+
+		messages, err = seedJobs.ValidateSeedJobs(*jenkins)
+
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+		assert.Equal(t, corev1.PodPhase(""), getPod(t).Status.Phase)
+	})
+	t.Run("credential changed", func(t *testing.T) {
+		secret.Data[PasswordSecretKey] = []byte("new-token")
+		require.NoError(t, fakeClient.Update(context.TODO(), secret))
+
+		messages, err := seedJobs.ValidateSeedJobs(*jenkins)
+
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+		err = fakeClient.Get(context.TODO(), podName, &corev1.Pod{})
+		assert.True(t, apierrors.IsNotFound(err))
+
+		_, err = seedJobs.ValidateSeedJobs(*jenkins)
+
+		require.NoError(t, err)
+		assert.Equal(t, corev1.PodPhase(""), getPod(t).Status.Phase)
+	})
+	t.Run("deep validation disabled", func(t *testing.T) {
+		jenkins.Spec.SeedJobs[0].DeepValidation = nil
+
+		_, err := seedJobs.ValidateSeedJobs(*jenkins)
+
+		require.NoError(t, err)
+		err = fakeClient.Get(context.TODO(), podName, &corev1.Pod{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+	t.Run("branch source", func(t *testing.T) {
+		invalid := seedJob
+		invalid.JenkinsCredentialType = v1alpha2.GithubAppCredentialType
+		invalid.BranchSource = &v1alpha2.SeedJobBranchSource{}
+
+		assert.Equal(t, []string{
+			"seedJob `jenkins-operator` deep validation can't be used with branch source",
+			"seedJob `jenkins-operator` deep validation can't be used with 'githubApp' credential type",
+		}, validateDeepValidationSpec(invalid))
+	})
+}
+
+func TestDeepValidationPod_SSH(t *testing.T) {
+	jenkins := v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+	seedJob := v1alpha2.SeedJob{
+		ID:                    "jenkins-operator",
+		CredentialID:          "deploy-keys",
+		JenkinsCredentialType: v1alpha2.BasicSSHCredentialType,
+		RepositoryBranch:      "master",
+		RepositoryURL:         "git@github.com:maximba/kubernetes-operator.git",
+		DeepValidation:        &v1alpha2.SeedJobDeepValidation{Image: "registry.local/git:latest"},
+	}
+
+	pod := deepValidationPod(jenkins, seedJob, "seed-job-validation", "hash")
+
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, "registry.local/git:latest", container.Image)
+	assert.Equal(t, []string{"git", "ls-remote", "--exit-code", "--heads", "--", seedJob.RepositoryURL, "master"}, container.Command)
+	assert.Contains(t, container.Env, corev1.EnvVar{
+		Name:  "GIT_SSH_COMMAND",
+		Value: "ssh -i /var/run/secrets/seed-job-credential/privateKey -o IdentitiesOnly=yes -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null",
+	})
+	require.Len(t, pod.Spec.Volumes, 1)
+	assert.Equal(t, "deploy-keys", pod.Spec.Volumes[0].Secret.SecretName)
+	assert.NotContains(t, pod.Labels, "app")
+}
@@ -0,0 +1,171 @@
+package seedjobs
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ephemeralCredentialsChecksumAnnotation restarts the seed job agent pod when the ephemeral credentials change,
+	// the private keys are copied by the init container only once per pod
+	ephemeralCredentialsChecksumAnnotation = "jenkins.io/seed-job-credentials-checksum"
+
+	ephemeralCredentialSecretsVolumeName = "credential-secrets"
+	ephemeralCredentialSecretsVolumePath = "/var/run/secrets/seed-jobs"
+
+	ephemeralCredentialsVolumeName = "credentials"
+	ephemeralCredentialsVolumePath = "/home/jenkins/.credentials"
+
+	ephemeralCredentialsInitContainerName = "seed-job-credentials"
+)
+
+// IsEphemeralCredential returns true when the seed job credential is mounted into the seed job agent instead of
+// being created in Jenkins
+func IsEphemeralCredential(seedJob v1alpha2.SeedJob) bool {
+	return seedJob.EphemeralCredential && isSecretCredential(seedJob)
+}
+
+// ephemeralCredentialSeedJobs returns the seed jobs which credentials are mounted into the seed job agent
+func ephemeralCredentialSeedJobs(jenkins v1alpha2.Jenkins) []v1alpha2.SeedJob {
+	var seedJobs []v1alpha2.SeedJob
+	for _, seedJob := range jenkins.Spec.SeedJobs {
+		if IsEphemeralCredential(seedJob) {
+			seedJobs = append(seedJobs, seedJob)
+		}
+	}
+	return seedJobs
+}
+
+// ephemeralCredentialsChecksum returns the checksum of the ephemeral credentials, it's empty when no seed job uses them
+func (s *seedJobs) ephemeralCredentialsChecksum(jenkins v1alpha2.Jenkins) (string, error) {
+	seedJobs := ephemeralCredentialSeedJobs(jenkins)
+	if len(seedJobs) == 0 {
+		return "", nil
+	}
+	hash := sha256.New()
+	for _, seedJob := range seedJobs {
+		value, err := s.credentialValue(jenkins.Namespace, seedJob)
+		if err != nil {
+			return "", err
+		}
+		_, _ = hash.Write([]byte(seedJob.ID + value))
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// gitConfigSubsectionReplacer escapes the characters which end or escape quoted git config subsection name
+var gitConfigSubsectionReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// ephemeralCredentialsGitConfig returns git configuration of the seed job agent, SSH private keys are passed to ssh
+// command together with the known hosts from the credential Secrets and username/password credentials are returned
+// by credential helpers scoped to the repository URLs
+func ephemeralCredentialsGitConfig(seedJobs []v1alpha2.SeedJob) string {
+	var identities []string
+	var knownHosts []string
+	var helpers []string
+	for _, seedJob := range seedJobs {
+		if seedJob.JenkinsCredentialType == v1alpha2.BasicSSHCredentialType {
+			identities = append(identities, fmt.Sprintf("-i %s/%s", ephemeralCredentialsVolumePath, seedJob.ID))
+			knownHosts = append(knownHosts, fmt.Sprintf("%s/%s/%s", ephemeralCredentialSecretsVolumePath, seedJob.ID, KnownHostsSecretKey))
+			continue
+		}
+		secretPath := fmt.Sprintf("%s/%s", ephemeralCredentialSecretsVolumePath, seedJob.ID)
+		helpers = append(helpers, fmt.Sprintf("[credential \"%s\"]\n\thelper = \"!f() { echo username=$(cat %s/%s); echo password=$(cat %s/%s); }; f\"\n",
+			gitConfigSubsectionReplacer.Replace(seedJob.RepositoryURL), secretPath, UsernameSecretKey, secretPath, PasswordSecretKey))
+	}
+
+	config := ""
+	if len(identities) > 0 {
+		// the known hosts are read from the Secret volume, so they are refreshed without restarting the agent
+		config = fmt.Sprintf("[core]\n\tsshCommand = ssh -o IdentitiesOnly=yes -o StrictHostKeyChecking=yes -o 'UserKnownHostsFile=%s' %s\n",
+			strings.Join(knownHosts, " "), strings.Join(identities, " "))
+	}
+	return config + strings.Join(helpers, "")
+}
+
+// ephemeralCredentialsInitScript copies SSH private keys to the memory-backed volume readable only by the agent user,
+// ssh refuses keys from the Secret volume owned by root, and writes the git configuration
+func ephemeralCredentialsInitScript(seedJobs []v1alpha2.SeedJob) string {
+	script := []string{
+		"set -e",
+		fmt.Sprintf("mkdir -p %s/git", ephemeralCredentialsVolumePath),
+		fmt.Sprintf(`printf '%%s' "$SEED_JOB_GIT_CONFIG" > %s/git/config`, ephemeralCredentialsVolumePath),
+	}
+	for _, seedJob := range seedJobs {
+		if seedJob.JenkinsCredentialType == v1alpha2.BasicSSHCredentialType {
+			key := fmt.Sprintf("%s/%s", ephemeralCredentialsVolumePath, seedJob.ID)
+			script = append(script,
+				fmt.Sprintf("cp %s/%s/%s %s", ephemeralCredentialSecretsVolumePath, seedJob.ID, PrivateKeySecretKey, key),
+				fmt.Sprintf("chmod 400 %s", key))
+		}
+	}
+	return strings.Join(script, "\n")
+}
+
+// ephemeralCredentialSecretsVolume returns the projected volume with the credential Secrets of the seed jobs,
+// the keys are placed in the directories named after the seed jobs
+func ephemeralCredentialSecretsVolume(seedJobs []v1alpha2.SeedJob) corev1.Volume {
+	var sources []corev1.VolumeProjection
+	for _, seedJob := range seedJobs {
+		keys := []string{UsernameSecretKey, PasswordSecretKey}
+		if seedJob.JenkinsCredentialType == v1alpha2.BasicSSHCredentialType {
+			keys = []string{PrivateKeySecretKey, KnownHostsSecretKey}
+		}
+		var items []corev1.KeyToPath
+		for _, key := range keys {
+			items = append(items, corev1.KeyToPath{Key: key, Path: fmt.Sprintf("%s/%s", seedJob.ID, key)})
+		}
+		sources = append(sources, corev1.VolumeProjection{
+			Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: seedJob.CredentialID},
+				Items:                items,
+			},
+		})
+	}
+	return corev1.Volume{
+		Name: ephemeralCredentialSecretsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{Sources: sources},
+		},
+	}
+}
+
+// ephemeralCredentialsAgentSpec returns the init container, volumes, volume mounts and environment variables which
+// make the ephemeral credentials available to git in the seed job agent
+func ephemeralCredentialsAgentSpec(jenkins v1alpha2.Jenkins, agentImage string) ([]corev1.Container, []corev1.Volume, []corev1.VolumeMount, []corev1.EnvVar) {
+	seedJobs := ephemeralCredentialSeedJobs(jenkins)
+	if len(seedJobs) == 0 {
+		return nil, nil, nil, nil
+	}
+
+	volumeMounts := []corev1.VolumeMount{
+		{Name: ephemeralCredentialSecretsVolumeName, MountPath: ephemeralCredentialSecretsVolumePath, ReadOnly: true},
+		{Name: ephemeralCredentialsVolumeName, MountPath: ephemeralCredentialsVolumePath},
+	}
+	initContainers := []corev1.Container{
+		{
+			Name:         ephemeralCredentialsInitContainerName,
+			Image:        agentImage,
+			Command:      []string{"sh", "-c", ephemeralCredentialsInitScript(seedJobs)},
+			Env:          []corev1.EnvVar{{Name: "SEED_JOB_GIT_CONFIG", Value: ephemeralCredentialsGitConfig(seedJobs)}},
+			VolumeMounts: volumeMounts,
+		},
+	}
+	volumes := []corev1.Volume{
+		ephemeralCredentialSecretsVolume(seedJobs),
+		{
+			Name: ephemeralCredentialsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory},
+			},
+		},
+	}
+	// git reads $XDG_CONFIG_HOME/git/config in addition to the global configuration
+	env := []corev1.EnvVar{{Name: "XDG_CONFIG_HOME", Value: ephemeralCredentialsVolumePath}}
+	return initContainers, volumes, volumeMounts, env
+}
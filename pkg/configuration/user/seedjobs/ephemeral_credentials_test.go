@@ -0,0 +1,139 @@
+package seedjobs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func ephemeralCredentialsJenkins() *v1alpha2.Jenkins {
+	jenkins := jenkinsCustomResource()
+	jenkins.Spec.SeedJobs = append(jenkins.Spec.SeedJobs,
+		v1alpha2.SeedJob{
+			ID:                    "ssh",
+			CredentialID:          "ssh-key",
+			JenkinsCredentialType: v1alpha2.BasicSSHCredentialType,
+			EphemeralCredential:   true,
+			RepositoryURL:         "git@github.com:maximba/kubernetes-operator.git",
+		},
+		v1alpha2.SeedJob{
+			ID:                    "https",
+			CredentialID:          "https-password",
+			JenkinsCredentialType: v1alpha2.UsernamePasswordCredentialType,
+			EphemeralCredential:   true,
+			RepositoryURL:         "https://git.example.com/team/jobs.git",
+		},
+	)
+	return jenkins
+}
+
+func TestEphemeralCredentialsAgentSpec(t *testing.T) {
+	t.Run("no ephemeral credentials", func(t *testing.T) {
+		deployment, err := agentDeployment(jenkinsCustomResource(), "default", AgentName, agentSecret, "cluster.local")
+
+		require.NoError(t, err)
+		assert.Empty(t, deployment.Spec.Template.Spec.InitContainers)
+		assert.Len(t, deployment.Spec.Template.Spec.Volumes, 2)
+	})
+	t.Run("ephemeral credentials", func(t *testing.T) {
+		deployment, err := agentDeployment(ephemeralCredentialsJenkins(), "default", AgentName, agentSecret, "cluster.local")
+
+		require.NoError(t, err)
+		podSpec := deployment.Spec.Template.Spec
+		require.Len(t, podSpec.InitContainers, 1)
+		initContainer := podSpec.InitContainers[0]
+		assert.Equal(t, ephemeralCredentialsInitContainerName, initContainer.Name)
+		assert.Equal(t, podSpec.Containers[0].Image, initContainer.Image)
+		assert.Contains(t, initContainer.Command[2], "cp /var/run/secrets/seed-jobs/ssh/privateKey /home/jenkins/.credentials/ssh")
+		assert.NotContains(t, initContainer.Command[2], "https")
+
+		require.Len(t, podSpec.Volumes, 4)
+		secretsVolume := podSpec.Volumes[2]
+		require.Len(t, secretsVolume.Projected.Sources, 2)
+		assert.Equal(t, "ssh-key", secretsVolume.Projected.Sources[0].Secret.Name)
+		assert.Equal(t, []corev1.KeyToPath{
+			{Key: PrivateKeySecretKey, Path: "ssh/privateKey"},
+			{Key: KnownHostsSecretKey, Path: "ssh/knownHosts"},
+		}, secretsVolume.Projected.Sources[0].Secret.Items)
+		assert.Equal(t, []corev1.KeyToPath{
+			{Key: UsernameSecretKey, Path: "https/username"},
+			{Key: PasswordSecretKey, Path: "https/password"},
+		}, secretsVolume.Projected.Sources[1].Secret.Items)
+		assert.Equal(t, corev1.StorageMediumMemory, podSpec.Volumes[3].EmptyDir.Medium)
+
+		assert.Len(t, podSpec.Containers[0].VolumeMounts, 4)
+		assert.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: "XDG_CONFIG_HOME", Value: ephemeralCredentialsVolumePath})
+	})
+}
+
+func TestEphemeralCredentialsGitConfig(t *testing.T) {
+	config := ephemeralCredentialsGitConfig(ephemeralCredentialSeedJobs(*ephemeralCredentialsJenkins()))
+
+	assert.Equal(t, "[core]\n"+
+		"\tsshCommand = ssh -o IdentitiesOnly=yes -o StrictHostKeyChecking=yes -o 'UserKnownHostsFile=/var/run/secrets/seed-jobs/ssh/knownHosts' -i /home/jenkins/.credentials/ssh\n"+
+		"[credential \"https://git.example.com/team/jobs.git\"]\n"+
+		"\thelper = \"!f() { echo username=$(cat /var/run/secrets/seed-jobs/https/username); echo password=$(cat /var/run/secrets/seed-jobs/https/password); }; f\"\n",
+		config)
+
+	t.Run("quoted repository URL", func(t *testing.T) {
+		seedJobs := []v1alpha2.SeedJob{{
+			ID:                    "https",
+			JenkinsCredentialType: v1alpha2.UsernamePasswordCredentialType,
+			RepositoryURL:         `https://git.example.com/team/"jobs\.git`,
+		}}
+
+		config := ephemeralCredentialsGitConfig(seedJobs)
+
+		assert.True(t, strings.HasPrefix(config, `[credential "https://git.example.com/team/\"jobs\\.git"]`+"\n"))
+	})
+}
+
+func TestEphemeralCredentialsChecksum(t *testing.T) {
+	jenkins := ephemeralCredentialsJenkins()
+	sshSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ssh-key", Namespace: jenkins.Namespace},
+		Data:       map[string][]byte{UsernameSecretKey: []byte("git"), PrivateKeySecretKey: []byte("key")},
+	}
+	httpsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "https-password", Namespace: jenkins.Namespace},
+		Data:       map[string][]byte{UsernameSecretKey: []byte("user"), PasswordSecretKey: []byte("password")},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(sshSecret, httpsSecret).Build()
+	seedJobsClient := New(nil, configuration.Configuration{Client: fakeClient, Jenkins: jenkins}).(*seedJobs)
+
+	checksum, err := seedJobsClient.ephemeralCredentialsChecksum(*jenkins)
+	require.NoError(t, err)
+	assert.NotEmpty(t, checksum)
+
+	httpsSecret.Data[PasswordSecretKey] = []byte("rotated")
+	fakeClient = fake.NewClientBuilder().WithObjects(sshSecret, httpsSecret).Build()
+	seedJobsClient = New(nil, configuration.Configuration{Client: fakeClient, Jenkins: jenkins}).(*seedJobs)
+
+	rotatedChecksum, err := seedJobsClient.ephemeralCredentialsChecksum(*jenkins)
+	require.NoError(t, err)
+	assert.NotEqual(t, checksum, rotatedChecksum)
+
+	checksum, err = seedJobsClient.ephemeralCredentialsChecksum(*jenkinsCustomResource())
+	require.NoError(t, err)
+	assert.Empty(t, checksum)
+}
+
+func TestSeedJobCreatingGroovyScriptWithEphemeralCredential(t *testing.T) {
+	seedJob := ephemeralCredentialsJenkins().Spec.SeedJobs[2]
+	secret := &corev1.Secret{Data: map[string][]byte{UsernameSecretKey: []byte("user"), PasswordSecretKey: []byte("password")}}
+
+	script, err := seedJobCreatingGroovyScript(seedJob, secret, false)
+
+	require.NoError(t, err)
+	assert.Contains(t, script, `GitSCM.createRepoList("https://git.example.com/team/jobs.git", "")`)
+	assert.NotContains(t, script, "UsernamePasswordCredentialsImpl(")
+	assert.NotContains(t, script, "https-password")
+}
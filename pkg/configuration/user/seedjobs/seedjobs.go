@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"reflect"
+	"strings"
 	"text/template"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
@@ -35,8 +36,17 @@ const (
 	PasswordSecretKey = "password"
 	// PrivateKeySecretKey is private key data key in Kubernetes secret used to create Jenkins SSH credential
 	PrivateKeySecretKey = "privateKey"
+	// KnownHostsSecretKey is SSH known hosts data key in Kubernetes secret used by the ephemeral SSH credential, the
+	// host keys of the repository servers are verified against it
+	KnownHostsSecretKey = "knownHosts"
 
 	AppIDSecretKey = "appId"
+	// OwnerSecretKey is optional GitHub organization or user data key in Kubernetes secret used to create Jenkins
+	// GitHub App credential, it's required when the app is installed in multiple organizations
+	OwnerSecretKey = "owner"
+	// InstallationIDSecretKey is optional GitHub App installation ID data key in Kubernetes secret used to create
+	// Jenkins GitHub App credential
+	InstallationIDSecretKey = "installationId"
 
 	// JenkinsCredentialTypeLabelName is label for kubernetes-credentials-provider-plugin which determine Jenkins
 	// credential type
@@ -45,11 +55,13 @@ const (
 	// AgentName is the name of seed job agent
 	AgentName = "seed-job-agent"
 
-	// DefaultAgentImage is the default image used for the seed-job agent
-	defaultAgentImage = "jenkins/inbound-agent:4.10-3"
-
 	creatingGroovyScriptName = "seed-job-groovy-script.groovy"
 
+	branchSourceGroovyScriptName = "seed-job-branch-source-groovy-script.groovy"
+
+	defaultBranchSourceScriptPath   = "Jenkinsfile"
+	defaultBranchSourceScanInterval = "1d"
+
 	homeVolumeName = "home"
 	homeVolumePath = "/home/jenkins/agent"
 
@@ -73,12 +85,29 @@ import com.cloudbees.plugins.credentials.SystemCredentialsProvider;
 import jenkins.model.JenkinsLocationConfiguration;
 import org.jenkinsci.plugins.workflow.job.WorkflowJob;
 import org.jenkinsci.plugins.workflow.cps.CpsScmFlowDefinition;
+{{ if .Folder }}
+import com.cloudbees.hudson.plugins.folder.Folder;
+{{ end }}
+{{ if .CredentialFolder }}
+import com.cloudbees.hudson.plugins.folder.properties.FolderCredentialsProvider.FolderCredentialsProperty;
+import com.cloudbees.plugins.credentials.domains.DomainCredentials;
+{{ end }}
+{{ if .InlineCredential }}
+{{ if .PrivateKey }}
+import com.cloudbees.jenkins.plugins.sshcredentials.impl.BasicSSHUserPrivateKey;
+{{ else }}
+import com.cloudbees.plugins.credentials.impl.UsernamePasswordCredentialsImpl;
+{{ end }}
+{{ end }}
 {{ if .GitHubPushTrigger }}
 import com.cloudbees.jenkins.GitHubPushTrigger;
 {{ end }}
 {{ if .BitbucketPushTrigger }}
 import com.cloudbees.jenkins.plugins.BitBucketTrigger;
 {{ end }}
+{{ if .GitLabPushTrigger }}
+import com.dabsquared.gitlabjenkins.GitLabPushTrigger;
+{{ end }}
 import hudson.model.FreeStyleProject;
 import hudson.model.labels.LabelAtom;
 import hudson.plugins.git.BranchSpec;
@@ -94,9 +123,53 @@ import javaposse.jobdsl.plugin.RemovedViewAction;
 import static com.google.common.collect.Lists.newArrayList;
 
 Jenkins jenkins = Jenkins.instance
+def parent = jenkins
+
+{{ if .Folder }}
+def folderName = new String("{{ .Folder }}".decodeBase64(), "UTF-8")
+def folder = jenkins.getItem(folderName)
+if (folder == null) {
+        folder = jenkins.createProject(Folder, folderName)
+}
+parent = folder
+{{ end }}
+{{ if .CredentialFolder }}
+def folderCredentials = folder.getProperties().get(FolderCredentialsProperty)
+if (folderCredentials == null) {
+        folderCredentials = new FolderCredentialsProperty(new DomainCredentials[0])
+        folder.addProperty(folderCredentials)
+}
+{{ end }}
+{{ if .InlineCredential }}
+{{ if .PrivateKey }}
+def credential = new BasicSSHUserPrivateKey(
+        CredentialsScope.GLOBAL,
+        "{{ .CredentialID }}",
+        new String("{{ .Username }}".decodeBase64(), "UTF-8"),
+        new BasicSSHUserPrivateKey.DirectEntryPrivateKeySource(new String("{{ .PrivateKey }}".decodeBase64(), "UTF-8")),
+        null,
+        "Seed job {{ .ID }} credential"
+)
+{{ else }}
+def credential = new UsernamePasswordCredentialsImpl(
+        CredentialsScope.GLOBAL,
+        "{{ .CredentialID }}",
+        "Seed job {{ .ID }} credential",
+        new String("{{ .Username }}".decodeBase64(), "UTF-8"),
+        new String("{{ .Password }}".decodeBase64(), "UTF-8")
+)
+{{ end }}
+def credentialsStore = {{ if .CredentialFolder }}folderCredentials.getStore(){{ else }}SystemCredentialsProvider.getInstance().getStore(){{ end }}
+def existingCredential = credentialsStore.getCredentials(Domain.global()).find { it.id == "{{ .CredentialID }}" }
+if (existingCredential == null) {
+        credentialsStore.addCredentials(Domain.global(), credential)
+} else {
+        credentialsStore.updateCredentials(Domain.global(), existingCredential, credential)
+}
+{{ end }}
 
 def jobDslSeedName = "{{ .ID }}-{{ .SeedJobSuffix }}";
-def jobRef = jenkins.getItem(jobDslSeedName)
+def jobRef = parent.getItem(jobDslSeedName)
 
 def repoList = GitSCM.createRepoList("{{ .RepositoryURL }}", "{{ .CredentialID }}")
 def gitExtensions = [
@@ -125,13 +198,18 @@ executeDslScripts.setUnstableOnDeprecation({{ .UnstableOnDeprecation }})
 executeDslScripts.setIgnoreMissingFiles({{ .IgnoreMissingFiles }})
 
 if (jobRef == null) {
-        jobRef = jenkins.createProject(FreeStyleProject, jobDslSeedName)
+        jobRef = parent.createProject(FreeStyleProject, jobDslSeedName)
 }
 
 jobRef.getBuildersList().clear()
 jobRef.getBuildersList().add(executeDslScripts)
 jobRef.setDisplayName("Seed Job from {{ .ID }}")
 jobRef.setScm(scm)
+{{ if .CustomWorkspace }}
+jobRef.setCustomWorkspace("{{ .CustomWorkspace }}")
+{{ else }}
+jobRef.setCustomWorkspace(null)
+{{ end }}
 
 {{ if .PollSCM }}
 jobRef.addTrigger(new SCMTrigger("{{ .PollSCM }}"))
@@ -145,6 +223,14 @@ jobRef.addTrigger(new GitHubPushTrigger())
 jobRef.addTrigger(new BitBucketTrigger())
 {{ end }}
 
+{{ if .GitLabPushTrigger }}
+def gitLabPushTrigger = new GitLabPushTrigger()
+gitLabPushTrigger.setTriggerOnPush(true)
+gitLabPushTrigger.setBranchFilterType("NameBasedFilter")
+gitLabPushTrigger.setIncludeBranchesSpec("{{ .RepositoryBranch }}")
+jobRef.addTrigger(gitLabPushTrigger)
+{{ end }}
+
 {{ if .BuildPeriodically }}
 jobRef.addTrigger(new TimerTrigger("{{ .BuildPeriodically }}"))
 {{ end}}
@@ -152,6 +238,85 @@ jobRef.setAssignedLabel(new LabelAtom("{{ .AgentName }}"))
 jenkins.getQueue().schedule(jobRef)
 `))
 
+var branchSourceGroovyScriptTemplate = template.Must(template.New(branchSourceGroovyScriptName).Parse(`
+import com.cloudbees.hudson.plugins.folder.computed.PeriodicFolderTrigger;
+import jenkins.model.Jenkins;
+
+Jenkins jenkins = Jenkins.instance
+def parent = jenkins
+{{ if .Folder }}
+def folderName = new String("{{ .Folder }}".decodeBase64(), "UTF-8")
+def folder = jenkins.getItem(folderName)
+if (folder == null) {
+        folder = jenkins.createProject(com.cloudbees.hudson.plugins.folder.Folder, folderName)
+}
+parent = folder
+{{ end }}
+
+def traits = []
+{{ if .GitHub }}
+{{ if .Discovery.Branches }}
+traits.add(new org.jenkinsci.plugins.github_branch_source.BranchDiscoveryTrait({{ if or .Discovery.PullRequestsFromOrigin .Discovery.PullRequestsFromForks }}1{{ else }}3{{ end }}))
+{{ end }}
+{{ if .Discovery.PullRequestsFromOrigin }}
+traits.add(new org.jenkinsci.plugins.github_branch_source.OriginPullRequestDiscoveryTrait(1))
+{{ end }}
+{{ if .Discovery.PullRequestsFromForks }}
+traits.add(new org.jenkinsci.plugins.github_branch_source.ForkPullRequestDiscoveryTrait(1, new org.jenkinsci.plugins.github_branch_source.ForkPullRequestDiscoveryTrait.TrustPermission()))
+{{ end }}
+{{ if .Discovery.Tags }}
+traits.add(new org.jenkinsci.plugins.github_branch_source.TagDiscoveryTrait())
+{{ end }}
+{{ else }}
+{{ if .Discovery.Branches }}
+traits.add(new jenkins.plugins.git.traits.BranchDiscoveryTrait())
+{{ end }}
+{{ if .Discovery.Tags }}
+traits.add(new jenkins.plugins.git.traits.TagDiscoveryTrait())
+{{ end }}
+{{ end }}
+
+def jobRef = parent.getItem("{{ .ID }}")
+{{ if eq .Kind "GitHubOrganization" }}
+{{ if .RepositoryPattern }}
+traits.add(new jenkins.scm.impl.trait.RegexSCMSourceFilterTrait(new String("{{ .RepositoryPattern }}".decodeBase64(), "UTF-8")))
+{{ end }}
+if (jobRef == null) {
+        jobRef = parent.createProject(jenkins.branch.OrganizationFolder, "{{ .ID }}")
+}
+def navigator = new org.jenkinsci.plugins.github_branch_source.GitHubSCMNavigator(new String("{{ .Owner }}".decodeBase64(), "UTF-8"))
+navigator.setCredentialsId("{{ .CredentialID }}")
+navigator.setTraits(traits)
+jobRef.getNavigators().replace(navigator)
+def projectFactory = new org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProjectFactory()
+projectFactory.setScriptPath(new String("{{ .ScriptPath }}".decodeBase64(), "UTF-8"))
+jobRef.getProjectFactories().replace(projectFactory)
+{{ else }}
+if (jobRef == null) {
+        jobRef = parent.createProject(org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject, "{{ .ID }}")
+}
+{{ if .GitHub }}
+def source = new org.jenkinsci.plugins.github_branch_source.GitHubSCMSource(new String("{{ .Owner }}".decodeBase64(), "UTF-8"), new String("{{ .Repository }}".decodeBase64(), "UTF-8"))
+{{ else }}
+def source = new jenkins.plugins.git.GitSCMSource("{{ .RepositoryURL }}")
+{{ end }}
+source.setId("{{ .ID }}")
+source.setCredentialsId("{{ .CredentialID }}")
+source.setTraits(traits)
+jobRef.getSourcesList().clear()
+jobRef.getSourcesList().add(new jenkins.branch.BranchSource(source))
+def projectFactory = new org.jenkinsci.plugins.workflow.multibranch.WorkflowBranchProjectFactory()
+projectFactory.setScriptPath(new String("{{ .ScriptPath }}".decodeBase64(), "UTF-8"))
+jobRef.setProjectFactory(projectFactory)
+{{ end }}
+
+jobRef.setDisplayName("{{ .ID }}")
+jobRef.setDescription(new String("{{ .Description }}".decodeBase64(), "UTF-8"))
+jobRef.addTrigger(new PeriodicFolderTrigger("{{ .ScanInterval }}"))
+jobRef.save()
+jobRef.scheduleBuild()
+`))
+
 // SeedJobs defines client interface to SeedJobs
 type SeedJobs interface {
 	EnsureSeedJobs(jenkins *v1alpha2.Jenkins) (done bool, err error)
@@ -165,6 +330,7 @@ type SeedJobs interface {
 	ValidateSeedJobs(jenkins v1alpha2.Jenkins) ([]string, error)
 	validateGitHubPushTrigger(jenkins v1alpha2.Jenkins) []string
 	validateBitbucketPushTrigger(jenkins v1alpha2.Jenkins) []string
+	validateGitLabPushTrigger(jenkins v1alpha2.Jenkins) []string
 	validateIfIDIsUnique(seedJobs []v1alpha2.SeedJob) []string
 }
 
@@ -179,7 +345,7 @@ func New(jenkinsClient jenkinsclient.Jenkins, config configuration.Configuration
 	return &seedJobs{
 		Configuration: config,
 		jenkinsClient: jenkinsClient,
-		logger:        log.Log.WithValues("cr", config.Jenkins.Name),
+		logger:        log.Log.WithValues("cr", config.Jenkins.Name, "namespace", config.Jenkins.Namespace),
 	}
 }
 
@@ -197,12 +363,22 @@ func (s *seedJobs) EnsureSeedJobs(jenkins *v1alpha2.Jenkins) (done bool, err err
 	}
 
 	if len(jenkins.Spec.SeedJobs) > 0 {
-		err := s.createAgent(s.jenkinsClient, s.Client, jenkins, jenkins.Namespace, AgentName)
+		if err = s.rotateAgentSecret(jenkins); err != nil {
+			return false, err
+		}
+
+		nodeName := agentNodeName(*jenkins)
+		err := s.createAgent(s.jenkinsClient, s.Client, jenkins, jenkins.Namespace, nodeName)
 		if err != nil {
 			return false, err
 		}
 
-		requeue, err := s.waitForSeedJobAgent(AgentName)
+		// the previous agent is retired before waiting for the new one, they may share the workspace cache
+		if err = s.retireAgent(jenkins); err != nil {
+			return false, err
+		}
+
+		requeue, err := s.waitForSeedJobAgent(nodeName)
 		if err != nil {
 			return false, err
 		}
@@ -210,15 +386,13 @@ func (s *seedJobs) EnsureSeedJobs(jenkins *v1alpha2.Jenkins) (done bool, err err
 			return false, nil
 		}
 	} else if len(jenkins.Spec.SeedJobs) == 0 {
-		err := s.Client.Delete(context.TODO(), &appsv1.Deployment{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: jenkins.Namespace,
-				Name:      agentDeploymentName(*jenkins, AgentName),
-			},
-		})
-
-		if err != nil && !apierrors.IsNotFound(err) {
-			return false, stackerr.WithStack(err)
+		if err = s.deleteAgentResources(*jenkins, agentNodeName(*jenkins)); err != nil {
+			return false, err
+		}
+		if jenkins.Status.SeedJobAgent != nil && len(jenkins.Status.SeedJobAgent.RetiringNodeName) > 0 {
+			if err = s.deleteAgentResources(*jenkins, jenkins.Status.SeedJobAgent.RetiringNodeName); err != nil {
+				return false, err
+			}
 		}
 	}
 
@@ -237,6 +411,7 @@ func (s *seedJobs) EnsureSeedJobs(jenkins *v1alpha2.Jenkins) (done bool, err err
 	seedJobIDs := s.getAllSeedJobIDs(*jenkins)
 	if !reflect.DeepEqual(seedJobIDs, jenkins.Status.CreatedSeedJobs) {
 		jenkins.Status.CreatedSeedJobs = seedJobIDs
+		jenkins.Status.SeedJobs = Summary(*jenkins)
 		return false, stackerr.WithStack(s.Client.Status().Update(context.TODO(), jenkins))
 	}
 
@@ -264,13 +439,38 @@ func (s *seedJobs) waitForSeedJobAgent(agentName string) (requeue bool, err erro
 // createJob is responsible for creating jenkins job which configures jenkins seed jobs and deploy keys
 func (s *seedJobs) createJobs(jenkins *v1alpha2.Jenkins) (requeue bool, err error) {
 	groovyClient := groovy.New(s.jenkinsClient, s.Client, jenkins, "seed-jobs", jenkins.Spec.GroovyScripts.Customization)
+	err = groovyClient.PruneResults(func(source, _ string) bool {
+		for _, seedJob := range jenkins.Spec.SeedJobs {
+			if seedJob.ID == source {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return true, err
+	}
 	for _, seedJob := range jenkins.Spec.SeedJobs {
 		credentialValue, err := s.credentialValue(jenkins.Namespace, seedJob)
 		if err != nil {
 			return true, err
 		}
 
-		groovyScript, err := seedJobCreatingGroovyScript(seedJob)
+		var secret *corev1.Secret
+		if !IsEphemeralCredential(seedJob) && (seedJob.CredentialFolder != "" || (jenkins.Spec.ExternalJenkins != nil && isSecretCredential(seedJob))) {
+			secret = &corev1.Secret{}
+			namespaceName := types.NamespacedName{Namespace: jenkins.Namespace, Name: seedJob.CredentialID}
+			if err = s.Client.Get(context.TODO(), namespaceName, secret); err != nil {
+				return true, stackerr.WithStack(err)
+			}
+		}
+
+		var groovyScript string
+		if seedJob.BranchSource != nil {
+			groovyScript, err = branchSourceCreatingGroovyScript(seedJob)
+		} else {
+			groovyScript, err = seedJobCreatingGroovyScript(seedJob, secret, jenkins.Spec.SeedJobAgentWorkspaceCache != nil)
+		}
 		if err != nil {
 			return true, err
 		}
@@ -284,6 +484,11 @@ func (s *seedJobs) createJobs(jenkins *v1alpha2.Jenkins) (requeue bool, err erro
 		if err != nil {
 			return true, err
 		}
+		// the jobs generated by the previous versions of the seed job plugins may be invalid
+		_, err = hash.Write([]byte(strings.Join(jenkins.Status.SeedJobPlugins, ",")))
+		if err != nil {
+			return true, err
+		}
 
 		requeue, err := groovyClient.EnsureSingle(seedJob.ID, fmt.Sprintf("%s.groovy", seedJob.ID), base64.URLEncoding.EncodeToString(hash.Sum(nil)), groovyScript)
 		if err != nil {
@@ -298,14 +503,32 @@ func (s *seedJobs) createJobs(jenkins *v1alpha2.Jenkins) (requeue bool, err erro
 	return false, nil
 }
 
+// JobFolder returns the Jenkins folder where the seed job is placed, it's empty when the seed job is created in
+// Jenkins root
+func JobFolder(seedJob v1alpha2.SeedJob) string {
+	if len(seedJob.Folder) > 0 {
+		return seedJob.Folder
+	}
+	return seedJob.CredentialFolder
+}
+
+// isSecretCredential returns true when the seed job credential is read from Kubernetes secret
+func isSecretCredential(seedJob v1alpha2.SeedJob) bool {
+	return seedJob.JenkinsCredentialType == v1alpha2.BasicSSHCredentialType || seedJob.JenkinsCredentialType == v1alpha2.UsernamePasswordCredentialType
+}
+
 // ensureLabelsForSecrets adds labels to Kubernetes secrets where are Jenkins credentials used for seed jobs,
 // thanks to them kubernetes-credentials-provider-plugin will create Jenkins credentials in Jenkins and
-// Operator will able to watch any changes made to them
+// Operator will able to watch any changes made to them. Credentials of seed jobs scoped to a folder or configured
+// in external Jenkins are created by the seed job groovy script, so the credential type label is not set to keep
+// them out of the global store, ephemeral credentials are mounted into the seed job agent and not created in Jenkins
 func (s *seedJobs) ensureLabelsForSecrets(jenkins v1alpha2.Jenkins) error {
 	for _, seedJob := range jenkins.Spec.SeedJobs {
-		if seedJob.JenkinsCredentialType == v1alpha2.BasicSSHCredentialType || seedJob.JenkinsCredentialType == v1alpha2.UsernamePasswordCredentialType {
+		if isSecretCredential(seedJob) {
 			requiredLabels := resources.BuildLabelsForWatchedResources(jenkins)
-			requiredLabels[JenkinsCredentialTypeLabelName] = string(seedJob.JenkinsCredentialType)
+			if seedJob.CredentialFolder == "" && jenkins.Spec.ExternalJenkins == nil && !IsEphemeralCredential(seedJob) {
+				requiredLabels[JenkinsCredentialTypeLabelName] = string(seedJob.JenkinsCredentialType)
+			}
 
 			secret := &corev1.Secret{}
 			namespaceName := types.NamespacedName{Namespace: jenkins.ObjectMeta.Namespace, Name: seedJob.CredentialID}
@@ -314,7 +537,9 @@ func (s *seedJobs) ensureLabelsForSecrets(jenkins v1alpha2.Jenkins) error {
 				return stackerr.WithStack(err)
 			}
 
-			if !resources.VerifyIfLabelsAreSet(secret, requiredLabels) {
+			_, hasCredentialTypeLabel := secret.ObjectMeta.Labels[JenkinsCredentialTypeLabelName]
+			_, credentialTypeLabelRequired := requiredLabels[JenkinsCredentialTypeLabelName]
+			if !resources.VerifyIfLabelsAreSet(secret, requiredLabels) || (!credentialTypeLabelRequired && hasCredentialTypeLabel) {
 				secret.ObjectMeta.Labels = requiredLabels
 				if err = s.Client.Update(context.TODO(), secret); err != nil {
 					return stackerr.WithStack(err)
@@ -327,7 +552,7 @@ func (s *seedJobs) ensureLabelsForSecrets(jenkins v1alpha2.Jenkins) error {
 }
 
 func (s *seedJobs) credentialValue(namespace string, seedJob v1alpha2.SeedJob) (string, error) {
-	if seedJob.JenkinsCredentialType == v1alpha2.BasicSSHCredentialType || seedJob.JenkinsCredentialType == v1alpha2.UsernamePasswordCredentialType {
+	if isSecretCredential(seedJob) {
 		secret := &corev1.Secret{}
 		namespaceName := types.NamespacedName{Namespace: namespace, Name: seedJob.CredentialID}
 		err := s.Client.Get(context.TODO(), namespaceName, secret)
@@ -351,7 +576,30 @@ func (s *seedJobs) getAllSeedJobIDs(jenkins v1alpha2.Jenkins) []string {
 	return ids
 }
 
+// Summary returns the number of created seed jobs out of the seed jobs defined in spec, e.g. 2/3, or empty string
+// when there are no seed jobs
+func Summary(jenkins v1alpha2.Jenkins) string {
+	if len(jenkins.Spec.SeedJobs) == 0 {
+		return ""
+	}
+	created := 0
+	for _, seedJob := range jenkins.Spec.SeedJobs {
+		for _, createdSeedJob := range jenkins.Status.CreatedSeedJobs {
+			if seedJob.ID == createdSeedJob {
+				created++
+				break
+			}
+		}
+	}
+	return fmt.Sprintf("%d/%d", created, len(jenkins.Spec.SeedJobs))
+}
+
+// isRecreatePodNeeded returns true when a created seed job has been deleted from spec, Jenkins master pod is recreated
+// to remove its jobs. External Jenkins isn't restarted by the operator so the jobs are kept there.
 func (s *seedJobs) isRecreatePodNeeded(jenkins v1alpha2.Jenkins) bool {
+	if jenkins.Spec.ExternalJenkins != nil {
+		return false
+	}
 	for _, createdSeedJob := range jenkins.Status.CreatedSeedJobs {
 		found := false
 		for _, seedJob := range jenkins.Spec.SeedJobs {
@@ -373,7 +621,7 @@ func (s *seedJobs) createAgent(jenkinsClient jenkinsclient.Jenkins, k8sClient cl
 
 	// Create node if not exists
 	if err != nil && err.Error() == "No node found" {
-		_, err = jenkinsClient.CreateNode(agentName, 5, "The jenkins-operator generated agent", "/home/jenkins", agentName)
+		_, err = jenkinsClient.CreateNode(agentName, 5, "The jenkins-operator generated agent", "/home/jenkins", AgentName)
 		if err != nil {
 			return stackerr.WithStack(err)
 		}
@@ -386,10 +634,31 @@ func (s *seedJobs) createAgent(jenkinsClient jenkinsclient.Jenkins, k8sClient cl
 		return err
 	}
 
+	if isAgentSecretManaged(*jenkinsManifest) {
+		if err = s.ensureAgentSecret(jenkinsManifest, namespace, agentName, secret); err != nil {
+			return err
+		}
+	}
+
+	if jenkinsManifest.Spec.SeedJobAgentWorkspaceCache != nil {
+		err = k8sClient.Create(context.TODO(), agentWorkspaceCache(jenkinsManifest, namespace, AgentName))
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return stackerr.WithStack(err)
+		}
+	}
+
 	deployment, err := agentDeployment(jenkinsManifest, namespace, agentName, secret, s.KubernetesClusterDomain)
 	if err != nil {
 		return err
 	}
+	checksum, err := s.ephemeralCredentialsChecksum(*jenkinsManifest)
+	if err != nil {
+		return err
+	}
+	if len(checksum) > 0 {
+		deployment.Spec.Template.Annotations = resources.MergeMaps(deployment.Spec.Template.Annotations,
+			map[string]string{ephemeralCredentialsChecksumAnnotation: checksum})
+	}
 
 	err = k8sClient.Create(context.TODO(), deployment)
 	if apierrors.IsAlreadyExists(err) {
@@ -408,6 +677,66 @@ func agentDeploymentName(jenkins v1alpha2.Jenkins, agentName string) string {
 	return fmt.Sprintf("%s-%s", agentName, jenkins.Name)
 }
 
+func agentWorkspaceCacheName(jenkins v1alpha2.Jenkins, agentName string) string {
+	return fmt.Sprintf("%s-workspace", agentDeploymentName(jenkins, agentName))
+}
+
+// agentWorkspaceCache returns PersistentVolumeClaim used as seed job agent workspace, it's created once
+// because most of its spec is immutable and it's kept when the agent is renamed by the secret rotation
+func agentWorkspaceCache(jenkins *v1alpha2.Jenkins, namespace string, agentName string) *corev1.PersistentVolumeClaim {
+	cache := jenkins.Spec.SeedJobAgentWorkspaceCache
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        agentWorkspaceCacheName(*jenkins, agentName),
+			Namespace:   namespace,
+			Labels:      resources.MergeMaps(jenkins.Spec.CommonLabels),
+			Annotations: resources.MergeMaps(jenkins.Spec.CommonAnnotations),
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					BlockOwnerDeletion: &[]bool{true}[0],
+					Controller:         &[]bool{true}[0],
+					Kind:               jenkins.Kind,
+					Name:               jenkins.Name,
+					APIVersion:         jenkins.APIVersion,
+					UID:                jenkins.UID,
+				},
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: cache.StorageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: cache.Size,
+				},
+			},
+		},
+	}
+}
+
+// agentWorkspaceVolumeSource returns volume source of seed job agent workspace
+func agentWorkspaceVolumeSource(jenkins *v1alpha2.Jenkins, agentName string) corev1.VolumeSource {
+	if jenkins.Spec.SeedJobAgentWorkspaceCache == nil {
+		return corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		}
+	}
+	return corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: agentWorkspaceCacheName(*jenkins, agentName),
+		},
+	}
+}
+
+// agentDeploymentStrategy returns strategy of seed job agent deployment, the workspace cache can be mounted
+// only by single pod so the old pod has to be terminated first
+func agentDeploymentStrategy(jenkins *v1alpha2.Jenkins) appsv1.DeploymentStrategy {
+	if jenkins.Spec.SeedJobAgentWorkspaceCache == nil {
+		return appsv1.DeploymentStrategy{}
+	}
+	return appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+}
+
 func agentDeployment(jenkins *v1alpha2.Jenkins, namespace string, agentName string, secret string, kubernetesDomainName string) (*appsv1.Deployment, error) {
 	jenkinsSlavesServiceFQDN, err := resources.GetJenkinsSlavesServiceFQDN(jenkins, kubernetesDomainName)
 	if err != nil {
@@ -418,19 +747,71 @@ func agentDeployment(jenkins *v1alpha2.Jenkins, namespace string, agentName stri
 		return nil, err
 	}
 
-	agentImage := jenkins.Spec.SeedJobAgentImage
-	if jenkins.Spec.SeedJobAgentImage == "" {
-		agentImage = defaultAgentImage
+	agentTemplate := jenkins.Spec.SeedJobAgentTemplate
+	if agentTemplate == nil {
+		agentTemplate = &v1alpha2.SeedJobAgentTemplate{}
+	}
+
+	agentImage := agentTemplate.Image
+	if agentImage == "" {
+		agentImage = jenkins.Spec.SeedJobAgentImage
+	}
+	if agentImage == "" {
+		agentImage = constants.DefaultSeedJobAgentImage
+	}
+
+	nodeSelector := agentTemplate.NodeSelector
+	if len(nodeSelector) == 0 {
+		nodeSelector = jenkins.Spec.Master.NodeSelector
+	}
+	tolerations := agentTemplate.Tolerations
+	if len(tolerations) == 0 {
+		tolerations = jenkins.Spec.Master.Tolerations
 	}
 
 	suffix := ""
 	if prefix, ok := resources.GetJenkinsOpts(*jenkins)["prefix"]; ok {
 		suffix = prefix
 	}
+	env := []corev1.EnvVar{
+		{
+			Name: "JENKINS_TUNNEL",
+			Value: fmt.Sprintf("%s:%d",
+				jenkinsSlavesServiceFQDN,
+				jenkins.Spec.SlaveService.Port),
+		},
+		agentSecretEnvVar(*jenkins, agentName, secret),
+		{
+			Name:  "JENKINS_AGENT_NAME",
+			Value: agentName,
+		},
+		{
+			Name: "JENKINS_URL",
+			Value: fmt.Sprintf("http://%s:%d%s",
+				jenkinsHTTPServiceFQDN,
+				jenkins.Spec.Service.Port,
+				suffix),
+		},
+		{
+			Name:  "JENKINS_AGENT_WORKDIR",
+			Value: homeVolumePath,
+		},
+	}
+	if jenkins.Spec.ExternalJenkins != nil {
+		env = externalJenkinsAgentEnv(env, jenkins.Spec.ExternalJenkins.URL)
+	}
+	if agentTemplate.JavaOpts != "" {
+		env = append(env, corev1.EnvVar{Name: "JAVA_OPTS", Value: agentTemplate.JavaOpts})
+	}
+	credentialsInitContainers, credentialsVolumes, credentialsVolumeMounts, credentialsEnv := ephemeralCredentialsAgentSpec(*jenkins, agentImage)
+	env = append(env, credentialsEnv...)
+
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      agentDeploymentName(*jenkins, agentName),
-			Namespace: namespace,
+			Name:        agentDeploymentName(*jenkins, agentName),
+			Namespace:   namespace,
+			Labels:      resources.MergeMaps(jenkins.Spec.CommonLabels),
+			Annotations: resources.MergeMaps(jenkins.Spec.CommonAnnotations),
 			OwnerReferences: []metav1.OwnerReference{
 				{
 					BlockOwnerDeletion: &[]bool{true}[0],
@@ -443,44 +824,22 @@ func agentDeployment(jenkins *v1alpha2.Jenkins, namespace string, agentName stri
 			},
 		},
 		Spec: appsv1.DeploymentSpec{
+			Strategy: agentDeploymentStrategy(jenkins),
 			Template: corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
-					NodeSelector:     jenkins.Spec.Master.NodeSelector,
-					Tolerations:      jenkins.Spec.Master.Tolerations,
-					ImagePullSecrets: jenkins.Spec.Master.ImagePullSecrets,
-					HostAliases:      jenkins.Spec.Master.HostAliases,
+					NodeSelector:      nodeSelector,
+					Tolerations:       tolerations,
+					ImagePullSecrets:  jenkins.Spec.Master.ImagePullSecrets,
+					HostAliases:       jenkins.Spec.Master.HostAliases,
+					PriorityClassName: jenkins.Spec.SeedJobAgentPriorityClassName,
+					InitContainers:    credentialsInitContainers,
 					Containers: []corev1.Container{
 						{
-							Name:  "jnlp",
-							Image: agentImage,
-							Env: []corev1.EnvVar{
-								{
-									Name: "JENKINS_TUNNEL",
-									Value: fmt.Sprintf("%s:%d",
-										jenkinsSlavesServiceFQDN,
-										jenkins.Spec.SlaveService.Port),
-								},
-								{
-									Name:  "JENKINS_SECRET",
-									Value: secret,
-								},
-								{
-									Name:  "JENKINS_AGENT_NAME",
-									Value: agentName,
-								},
-								{
-									Name: "JENKINS_URL",
-									Value: fmt.Sprintf("http://%s:%d%s",
-										jenkinsHTTPServiceFQDN,
-										jenkins.Spec.Service.Port,
-										suffix),
-								},
-								{
-									Name:  "JENKINS_AGENT_WORKDIR",
-									Value: homeVolumePath,
-								},
-							},
-							VolumeMounts: []corev1.VolumeMount{
+							Name:      "jnlp",
+							Image:     agentImage,
+							Env:       env,
+							Resources: agentTemplate.Resources,
+							VolumeMounts: append([]corev1.VolumeMount{
 								{
 									Name:      homeVolumeName,
 									MountPath: homeVolumePath,
@@ -489,10 +848,10 @@ func agentDeployment(jenkins *v1alpha2.Jenkins, namespace string, agentName stri
 									Name:      workspaceVolumeName,
 									MountPath: workspaceVolumePath,
 								},
-							},
+							}, credentialsVolumeMounts...),
 						},
 					},
-					Volumes: []corev1.Volume{
+					Volumes: append([]corev1.Volume{
 						{
 							Name: homeVolumeName,
 							VolumeSource: corev1.VolumeSource{
@@ -500,17 +859,16 @@ func agentDeployment(jenkins *v1alpha2.Jenkins, namespace string, agentName stri
 							},
 						},
 						{
-							Name: workspaceVolumeName,
-							VolumeSource: corev1.VolumeSource{
-								EmptyDir: &corev1.EmptyDirVolumeSource{},
-							},
+							Name:         workspaceVolumeName,
+							VolumeSource: agentWorkspaceVolumeSource(jenkins, AgentName),
 						},
-					},
+					}, credentialsVolumes...),
 				},
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
+					Labels: resources.MergeMaps(jenkins.Spec.CommonLabels, agentTemplate.Labels, map[string]string{
 						"app": fmt.Sprintf("%s-selector", agentName),
-					},
+					}),
+					Annotations: resources.MergeMaps(jenkins.Spec.CommonAnnotations),
 				},
 			},
 			Selector: &metav1.LabelSelector{
@@ -522,15 +880,72 @@ func agentDeployment(jenkins *v1alpha2.Jenkins, namespace string, agentName stri
 	}, nil
 }
 
-func seedJobCreatingGroovyScript(seedJob v1alpha2.SeedJob) (string, error) {
+// externalJenkinsAgentEnv points the seed job agent to external Jenkins, the agent discovers the inbound agent port
+// from Jenkins URL instead of the slave service
+func externalJenkinsAgentEnv(env []corev1.EnvVar, jenkinsURL string) []corev1.EnvVar {
+	var externalEnv []corev1.EnvVar
+	for _, envVar := range env {
+		switch envVar.Name {
+		case "JENKINS_TUNNEL":
+			continue
+		case "JENKINS_URL":
+			envVar.Value = jenkinsURL
+		}
+		externalEnv = append(externalEnv, envVar)
+	}
+	return externalEnv
+}
+
+// agentSecretEnvVar returns the inbound secret of seed job agent, it's referenced from the Secret managed by the operator
+// when spec.seedJobAgentSecret.requireManagedSecret is set
+func agentSecretEnvVar(jenkins v1alpha2.Jenkins, agentName string, secret string) corev1.EnvVar {
+	if !isAgentSecretManaged(jenkins) {
+		return corev1.EnvVar{Name: "JENKINS_SECRET", Value: secret}
+	}
+	return corev1.EnvVar{
+		Name: "JENKINS_SECRET",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: agentSecretName(jenkins, agentName)},
+				Key:                  agentSecretKey,
+			},
+		},
+	}
+}
+
+// encodeFolder returns the base64 encoded folder name which is decoded by the groovy script, it's empty when the seed
+// job isn't placed in a folder
+func encodeFolder(folder string) string {
+	if len(folder) == 0 {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString([]byte(folder))
+}
+
+// seedJobWorkspace returns seed job workspace in the workspace cache keyed by the repository URL
+func seedJobWorkspace(repositoryURL string) string {
+	hash := sha256.Sum256([]byte(repositoryURL))
+	return fmt.Sprintf("%s/cache/%x", workspaceVolumePath, hash[:8])
+}
+
+// seedJobCreatingGroovyScript returns groovy script which creates the seed job, the credential is created by the script
+// from the secret when it's set
+func seedJobCreatingGroovyScript(seedJob v1alpha2.SeedJob, secret *corev1.Secret, workspaceCache bool) (string, error) {
 	data := struct {
 		ID                    string
 		CredentialID          string
+		CredentialFolder      string
+		Folder                string
+		InlineCredential      bool
+		Username              string
+		Password              string
+		PrivateKey            string
 		Targets               string
 		RepositoryBranch      string
 		RepositoryURL         string
 		BitbucketPushTrigger  bool
 		GitHubPushTrigger     bool
+		GitLabPushTrigger     bool
 		BuildPeriodically     string
 		PollSCM               string
 		IgnoreMissingFiles    bool
@@ -539,14 +954,18 @@ func seedJobCreatingGroovyScript(seedJob v1alpha2.SeedJob) (string, error) {
 		UnstableOnDeprecation bool
 		SeedJobSuffix         string
 		AgentName             string
+		CustomWorkspace       string
 	}{
 		ID:                    seedJob.ID,
 		CredentialID:          seedJob.CredentialID,
+		CredentialFolder:      seedJob.CredentialFolder,
+		Folder:                encodeFolder(JobFolder(seedJob)),
 		Targets:               seedJob.Targets,
 		RepositoryBranch:      seedJob.RepositoryBranch,
 		RepositoryURL:         seedJob.RepositoryURL,
 		BitbucketPushTrigger:  seedJob.BitbucketPushTrigger,
 		GitHubPushTrigger:     seedJob.GitHubPushTrigger,
+		GitLabPushTrigger:     seedJob.GitLabPushTrigger,
 		BuildPeriodically:     seedJob.BuildPeriodically,
 		PollSCM:               seedJob.PollSCM,
 		IgnoreMissingFiles:    seedJob.IgnoreMissingFiles,
@@ -557,6 +976,24 @@ func seedJobCreatingGroovyScript(seedJob v1alpha2.SeedJob) (string, error) {
 		AgentName:             AgentName,
 	}
 
+	if workspaceCache {
+		data.CustomWorkspace = seedJobWorkspace(seedJob.RepositoryURL)
+	}
+	// git in the seed job agent is configured with the ephemeral credential
+	if IsEphemeralCredential(seedJob) {
+		data.CredentialID = ""
+		secret = nil
+	}
+	if secret != nil {
+		data.InlineCredential = true
+		data.Username = base64.StdEncoding.EncodeToString(secret.Data[UsernameSecretKey])
+		if seedJob.JenkinsCredentialType == v1alpha2.BasicSSHCredentialType {
+			data.PrivateKey = base64.StdEncoding.EncodeToString(secret.Data[PrivateKeySecretKey])
+		} else {
+			data.Password = base64.StdEncoding.EncodeToString(secret.Data[PasswordSecretKey])
+		}
+	}
+
 	output, err := render.Render(seedJobGroovyScriptTemplate, data)
 	if err != nil {
 		return "", err
@@ -564,3 +1001,52 @@ func seedJobCreatingGroovyScript(seedJob v1alpha2.SeedJob) (string, error) {
 
 	return output, nil
 }
+
+// branchSourceCreatingGroovyScript returns groovy script which creates multibranch pipeline or GitHub organization
+// folder defined by the seed job branch source and schedules the scan of branches
+func branchSourceCreatingGroovyScript(seedJob v1alpha2.SeedJob) (string, error) {
+	branchSource := seedJob.BranchSource
+	data := struct {
+		ID                string
+		Folder            string
+		Description       string
+		CredentialID      string
+		RepositoryURL     string
+		Kind              v1alpha2.SeedJobBranchSourceKind
+		GitHub            bool
+		Owner             string
+		Repository        string
+		RepositoryPattern string
+		ScriptPath        string
+		ScanInterval      string
+		Discovery         v1alpha2.SeedJobBranchDiscovery
+	}{
+		ID:                seedJob.ID,
+		Folder:            encodeFolder(JobFolder(seedJob)),
+		Description:       base64.StdEncoding.EncodeToString([]byte(seedJob.Description)),
+		CredentialID:      seedJob.CredentialID,
+		RepositoryURL:     seedJob.RepositoryURL,
+		Kind:              branchSource.Kind,
+		GitHub:            len(branchSource.Owner) > 0,
+		Owner:             base64.StdEncoding.EncodeToString([]byte(branchSource.Owner)),
+		Repository:        base64.StdEncoding.EncodeToString([]byte(branchSource.Repository)),
+		RepositoryPattern: base64.StdEncoding.EncodeToString([]byte(branchSource.RepositoryPattern)),
+		ScanInterval:      branchSource.ScanInterval,
+		Discovery:         branchSource.Discovery,
+	}
+
+	// the user defined strings are passed encoded so they can't break out of the groovy string literals
+	scriptPath := branchSource.ScriptPath
+	if len(scriptPath) == 0 {
+		scriptPath = defaultBranchSourceScriptPath
+	}
+	data.ScriptPath = base64.StdEncoding.EncodeToString([]byte(scriptPath))
+	if len(data.ScanInterval) == 0 {
+		data.ScanInterval = defaultBranchSourceScanInterval
+	}
+	if data.Discovery == (v1alpha2.SeedJobBranchDiscovery{}) {
+		data.Discovery.Branches = true
+	}
+
+	return render.Render(branchSourceGroovyScriptTemplate, data)
+}
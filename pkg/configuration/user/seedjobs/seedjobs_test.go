@@ -8,6 +8,7 @@ import (
 	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
 	"github.com/maximba/kubernetes-operator/pkg/configuration"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/constants"
 
 	"github.com/bndr/gojenkins"
 	"github.com/golang/mock/gomock"
@@ -94,7 +95,7 @@ func TestEnsureSeedJobs(t *testing.T) {
 			Jenkins:       jenkins,
 		}
 
-		seedJobCreatingScript, err := seedJobCreatingGroovyScript(jenkins.Spec.SeedJobs[0])
+		seedJobCreatingScript, err := seedJobCreatingGroovyScript(jenkins.Spec.SeedJobs[0], nil, false)
 		assert.NoError(t, err)
 
 		jenkinsClient.EXPECT().GetNode(AgentName).Return(nil, nil).AnyTimes()
@@ -164,6 +165,41 @@ func TestEnsureSeedJobs(t *testing.T) {
 	})
 }
 
+func TestSeedJobs_createJobs(t *testing.T) {
+	t.Run("apply seed jobs again when seed job plugins change", func(t *testing.T) {
+		// given
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		err := v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme)
+		assert.NoError(t, err)
+
+		jenkins := jenkinsCustomResource()
+		jenkins.Status.SeedJobPlugins = []string{"git:4.10.0", "job-dsl:1.81"}
+		fakeClient := fake.NewClientBuilder().WithObjects(jenkins).Build()
+		seedJobCreatingScript, err := seedJobCreatingGroovyScript(jenkins.Spec.SeedJobs[0], nil, false)
+		assert.NoError(t, err)
+
+		jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(seedJobCreatingScript).Return("", nil).Times(2)
+		seedJobsClient := New(jenkinsClient, configuration.Configuration{Client: fakeClient, Jenkins: jenkins}).(*seedJobs)
+
+		// when
+		requeue, err := seedJobsClient.createJobs(jenkins)
+		assert.NoError(t, err)
+		assert.True(t, requeue)
+		requeue, err = seedJobsClient.createJobs(jenkins)
+		assert.NoError(t, err)
+		assert.False(t, requeue)
+
+		jenkins.Status.SeedJobPlugins = []string{"git:4.10.0", "job-dsl:1.82"}
+		requeue, err = seedJobsClient.createJobs(jenkins)
+
+		// then
+		assert.NoError(t, err)
+		assert.True(t, requeue)
+	})
+}
+
 func TestCreateAgent(t *testing.T) {
 	t.Run("don't fail when deployment is already created", func(t *testing.T) {
 		// given
@@ -207,6 +243,149 @@ func TestCreateAgent(t *testing.T) {
 	})
 }
 
+func TestAgentDeployment(t *testing.T) {
+	t.Run("common labels and annotations are set", func(t *testing.T) {
+		// given
+		jenkins := jenkinsCustomResource()
+		jenkins.Spec.CommonLabels = map[string]string{"team": "ci"}
+		jenkins.Spec.CommonAnnotations = map[string]string{"cost-center": "42"}
+
+		// when
+		deployment, err := agentDeployment(jenkins, jenkins.Namespace, AgentName, agentSecret, "cluster.local")
+
+		// then
+		assert.NoError(t, err)
+		selector := map[string]string{"app": AgentName + "-selector"}
+		assert.Equal(t, jenkins.Spec.CommonLabels, deployment.Labels)
+		assert.Equal(t, jenkins.Spec.CommonAnnotations, deployment.Annotations)
+		assert.Equal(t, map[string]string{"app": AgentName + "-selector", "team": "ci"}, deployment.Spec.Template.Labels)
+		assert.Equal(t, jenkins.Spec.CommonAnnotations, deployment.Spec.Template.Annotations)
+		assert.Equal(t, selector, deployment.Spec.Selector.MatchLabels)
+
+		deployment.Labels["team"] = "changed"
+		assert.Equal(t, "ci", jenkins.Spec.CommonLabels["team"])
+	})
+	t.Run("external Jenkins", func(t *testing.T) {
+		// given
+		jenkins := jenkinsCustomResource()
+		jenkins.Spec.ExternalJenkins = &v1alpha2.ExternalJenkins{URL: "https://jenkins.example.com/"}
+
+		// when
+		deployment, err := agentDeployment(jenkins, jenkins.Namespace, AgentName, agentSecret, "cluster.local")
+
+		// then
+		assert.NoError(t, err)
+		env := map[string]string{}
+		for _, envVar := range deployment.Spec.Template.Spec.Containers[0].Env {
+			env[envVar.Name] = envVar.Value
+		}
+		assert.Equal(t, "https://jenkins.example.com/", env["JENKINS_URL"])
+		assert.NotContains(t, env, "JENKINS_TUNNEL")
+		assert.Equal(t, agentSecret, env["JENKINS_SECRET"])
+	})
+	t.Run("priority class name is set", func(t *testing.T) {
+		// given
+		jenkins := jenkinsCustomResource()
+		jenkins.Spec.Master.PriorityClassName = "master-priority"
+		jenkins.Spec.SeedJobAgentPriorityClassName = "agent-priority"
+
+		// when
+		deployment, err := agentDeployment(jenkins, jenkins.Namespace, AgentName, agentSecret, "cluster.local")
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, "agent-priority", deployment.Spec.Template.Spec.PriorityClassName)
+	})
+	t.Run("workspace cache", func(t *testing.T) {
+		// given
+		jenkins := jenkinsCustomResource()
+		jenkins.Spec.SeedJobAgentWorkspaceCache = &v1alpha2.SeedJobAgentWorkspaceCache{Size: resource.MustParse("1Gi")}
+
+		// when
+		deployment, err := agentDeployment(jenkins, jenkins.Namespace, AgentName, agentSecret, "cluster.local")
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, appsv1.RecreateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+		var workspace *corev1.Volume
+		for i, volume := range deployment.Spec.Template.Spec.Volumes {
+			if volume.Name == workspaceVolumeName {
+				workspace = &deployment.Spec.Template.Spec.Volumes[i]
+			}
+		}
+		if assert.NotNil(t, workspace) && assert.NotNil(t, workspace.PersistentVolumeClaim) {
+			assert.Equal(t, agentWorkspaceCacheName(*jenkins, AgentName), workspace.PersistentVolumeClaim.ClaimName)
+		}
+	})
+	t.Run("agent template", func(t *testing.T) {
+		// given
+		jenkins := jenkinsCustomResource()
+		jenkins.Spec.SeedJobAgentImage = "jenkins/inbound-agent:latest"
+		jenkins.Spec.Master.NodeSelector = map[string]string{"pool": "master"}
+		jenkins.Spec.SeedJobAgentTemplate = &v1alpha2.SeedJobAgentTemplate{
+			Image: "registry.example.com/inbound-agent:4.9-1",
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+			NodeSelector: map[string]string{"pool": "ci"},
+			Tolerations:  []corev1.Toleration{{Key: "ci", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+			JavaOpts:     "-Xmx512m",
+			Labels:       map[string]string{"team": "ci", "app": "overridden"},
+		}
+
+		// when
+		deployment, err := agentDeployment(jenkins, jenkins.Namespace, AgentName, agentSecret, "cluster.local")
+
+		// then
+		assert.NoError(t, err)
+		podSpec := deployment.Spec.Template.Spec
+		assert.Equal(t, map[string]string{"pool": "ci"}, podSpec.NodeSelector)
+		assert.Equal(t, jenkins.Spec.SeedJobAgentTemplate.Tolerations, podSpec.Tolerations)
+		assert.Equal(t, "registry.example.com/inbound-agent:4.9-1", podSpec.Containers[0].Image)
+		assert.Equal(t, jenkins.Spec.SeedJobAgentTemplate.Resources, podSpec.Containers[0].Resources)
+		assert.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: "JAVA_OPTS", Value: "-Xmx512m"})
+		assert.Equal(t, map[string]string{"app": AgentName + "-selector", "team": "ci"}, deployment.Spec.Template.Labels)
+	})
+	t.Run("without agent template", func(t *testing.T) {
+		// given
+		jenkins := jenkinsCustomResource()
+		jenkins.Spec.Master.NodeSelector = map[string]string{"pool": "master"}
+
+		// when
+		deployment, err := agentDeployment(jenkins, jenkins.Namespace, AgentName, agentSecret, "cluster.local")
+
+		// then
+		assert.NoError(t, err)
+		podSpec := deployment.Spec.Template.Spec
+		assert.Equal(t, map[string]string{"pool": "master"}, podSpec.NodeSelector)
+		assert.Equal(t, constants.DefaultSeedJobAgentImage, podSpec.Containers[0].Image)
+		for _, env := range podSpec.Containers[0].Env {
+			assert.NotEqual(t, "JAVA_OPTS", env.Name)
+		}
+	})
+}
+
+func TestAgentWorkspaceCache(t *testing.T) {
+	// given
+	storageClassName := "fast"
+	jenkins := jenkinsCustomResource()
+	jenkins.Spec.SeedJobAgentWorkspaceCache = &v1alpha2.SeedJobAgentWorkspaceCache{
+		Size:             resource.MustParse("5Gi"),
+		StorageClassName: &storageClassName,
+	}
+
+	// when
+	pvc := agentWorkspaceCache(jenkins, jenkins.Namespace, AgentName)
+
+	// then
+	assert.Equal(t, "seed-job-agent-jenkins-workspace", pvc.Name)
+	assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, pvc.Spec.AccessModes)
+	assert.Equal(t, &storageClassName, pvc.Spec.StorageClassName)
+	size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	assert.Equal(t, "5Gi", size.String())
+	assert.Len(t, pvc.OwnerReferences, 1)
+}
+
 func TestSeedJobs_isRecreatePodNeeded(t *testing.T) {
 	config := configuration.Configuration{
 		Client:        nil,
@@ -280,3 +459,255 @@ func TestSeedJobs_isRecreatePodNeeded(t *testing.T) {
 		assert.True(t, got)
 	})
 }
+
+func TestSeedJobCreatingGroovyScript(t *testing.T) {
+	seedJob := v1alpha2.SeedJob{
+		ID:                    "team-a",
+		CredentialID:          "team-a-deploy-key",
+		CredentialFolder:      "team-a",
+		JenkinsCredentialType: v1alpha2.UsernamePasswordCredentialType,
+		Targets:               "cicd/jobs/*.jenkins",
+		RepositoryBranch:      "master",
+		RepositoryURL:         "https://github.com/maximba/kubernetes-operator.git",
+	}
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			UsernameSecretKey: []byte("user"),
+			PasswordSecretKey: []byte(`pa"ss$word`),
+		},
+	}
+
+	t.Run("global credential", func(t *testing.T) {
+		globalSeedJob := seedJob
+		globalSeedJob.CredentialFolder = ""
+
+		script, err := seedJobCreatingGroovyScript(globalSeedJob, nil, false)
+
+		assert.NoError(t, err)
+		assert.NotContains(t, script, "FolderCredentialsProperty")
+		assert.Contains(t, script, "def parent = jenkins\n")
+	})
+	t.Run("workspace cache", func(t *testing.T) {
+		script, err := seedJobCreatingGroovyScript(seedJob, nil, true)
+
+		assert.NoError(t, err)
+		assert.Contains(t, script, `jobRef.setCustomWorkspace("`+seedJobWorkspace(seedJob.RepositoryURL)+`")`)
+		assert.NotEqual(t, seedJobWorkspace(seedJob.RepositoryURL), seedJobWorkspace("https://github.com/other/repo.git"))
+	})
+	t.Run("without workspace cache", func(t *testing.T) {
+		script, err := seedJobCreatingGroovyScript(seedJob, nil, false)
+
+		assert.NoError(t, err)
+		assert.Contains(t, script, "jobRef.setCustomWorkspace(null)")
+	})
+	t.Run("folder credential", func(t *testing.T) {
+		script, err := seedJobCreatingGroovyScript(seedJob, secret, false)
+
+		assert.NoError(t, err)
+		assert.Contains(t, script, `new String("dGVhbS1h".decodeBase64(), "UTF-8")`)
+		assert.Contains(t, script, `jenkins.createProject(Folder, folderName)`)
+		assert.Contains(t, script, "UsernamePasswordCredentialsImpl(")
+		assert.NotContains(t, script, "BasicSSHUserPrivateKey(")
+		assert.Contains(t, script, `"cGEic3Mkd29yZA==".decodeBase64()`)
+		assert.NotContains(t, script, `pa"ss$word`)
+		assert.Contains(t, script, "parent = folder")
+	})
+	t.Run("folder with global credential", func(t *testing.T) {
+		folderSeedJob := seedJob
+		folderSeedJob.CredentialFolder = ""
+		folderSeedJob.Folder = "team-b"
+
+		script, err := seedJobCreatingGroovyScript(folderSeedJob, nil, false)
+
+		assert.NoError(t, err)
+		assert.Contains(t, script, `new String("dGVhbS1i".decodeBase64(), "UTF-8")`)
+		assert.Contains(t, script, "parent = folder")
+		assert.Contains(t, script, "parent.createProject(FreeStyleProject, jobDslSeedName)")
+		assert.NotContains(t, script, "FolderCredentialsProperty")
+	})
+	t.Run("global credential of external Jenkins", func(t *testing.T) {
+		globalSeedJob := seedJob
+		globalSeedJob.CredentialFolder = ""
+
+		script, err := seedJobCreatingGroovyScript(globalSeedJob, secret, false)
+
+		assert.NoError(t, err)
+		assert.NotContains(t, script, "FolderCredentialsProperty")
+		assert.Contains(t, script, "UsernamePasswordCredentialsImpl(")
+		assert.Contains(t, script, "def credentialsStore = SystemCredentialsProvider.getInstance().getStore()")
+		assert.NotContains(t, script, "parent = folder")
+	})
+	t.Run("GitLab push trigger", func(t *testing.T) {
+		gitLabSeedJob := seedJob
+		gitLabSeedJob.GitLabPushTrigger = true
+
+		script, err := seedJobCreatingGroovyScript(gitLabSeedJob, nil, false)
+
+		assert.NoError(t, err)
+		assert.Contains(t, script, "import com.dabsquared.gitlabjenkins.GitLabPushTrigger;")
+		assert.Contains(t, script, `gitLabPushTrigger.setIncludeBranchesSpec("master")`)
+		assert.Contains(t, script, "jobRef.addTrigger(gitLabPushTrigger)")
+	})
+	t.Run("without GitLab push trigger", func(t *testing.T) {
+		script, err := seedJobCreatingGroovyScript(seedJob, nil, false)
+
+		assert.NoError(t, err)
+		assert.NotContains(t, script, "GitLabPushTrigger")
+	})
+}
+
+func TestBranchSourceCreatingGroovyScript(t *testing.T) {
+	t.Run("multibranch pipeline with Git branch source", func(t *testing.T) {
+		seedJob := v1alpha2.SeedJob{
+			ID:            "app",
+			CredentialID:  "app-deploy-key",
+			RepositoryURL: "https://git.example.com/team/app.git",
+			BranchSource:  &v1alpha2.SeedJobBranchSource{Kind: v1alpha2.MultibranchPipelineBranchSourceKind},
+		}
+
+		script, err := branchSourceCreatingGroovyScript(seedJob)
+
+		assert.NoError(t, err)
+		assert.Contains(t, script, "parent.createProject(org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject, \"app\")")
+		assert.NotContains(t, script, "parent = folder")
+		assert.Contains(t, script, `new jenkins.plugins.git.GitSCMSource("https://git.example.com/team/app.git")`)
+		assert.Contains(t, script, "new jenkins.plugins.git.traits.BranchDiscoveryTrait()")
+		assert.Contains(t, script, `projectFactory.setScriptPath(new String("SmVua2luc2ZpbGU=".decodeBase64(), "UTF-8"))`)
+		assert.Contains(t, script, `new PeriodicFolderTrigger("1d")`)
+		assert.NotContains(t, script, "github_branch_source")
+	})
+	t.Run("multibranch pipeline in folder", func(t *testing.T) {
+		seedJob := v1alpha2.SeedJob{
+			ID:            "app",
+			Folder:        "team-a",
+			RepositoryURL: "https://git.example.com/team/app.git",
+			BranchSource:  &v1alpha2.SeedJobBranchSource{Kind: v1alpha2.MultibranchPipelineBranchSourceKind},
+		}
+
+		script, err := branchSourceCreatingGroovyScript(seedJob)
+
+		assert.NoError(t, err)
+		assert.Contains(t, script, `new String("dGVhbS1h".decodeBase64(), "UTF-8")`)
+		assert.Contains(t, script, `jenkins.createProject(com.cloudbees.hudson.plugins.folder.Folder, folderName)`)
+		assert.Contains(t, script, "parent = folder")
+		assert.Contains(t, script, `def jobRef = parent.getItem("app")`)
+	})
+	t.Run("multibranch pipeline with GitHub branch source", func(t *testing.T) {
+		seedJob := v1alpha2.SeedJob{
+			ID:           "app",
+			CredentialID: "github-app",
+			BranchSource: &v1alpha2.SeedJobBranchSource{
+				Kind:         v1alpha2.MultibranchPipelineBranchSourceKind,
+				Owner:        "maximba",
+				Repository:   "app",
+				ScriptPath:   "ci/Jenkinsfile",
+				ScanInterval: "1h",
+				Discovery:    v1alpha2.SeedJobBranchDiscovery{Branches: true, PullRequestsFromOrigin: true, Tags: true},
+			},
+		}
+
+		script, err := branchSourceCreatingGroovyScript(seedJob)
+
+		assert.NoError(t, err)
+		assert.Contains(t, script, `GitHubSCMSource(new String("bWF4aW1iYQ==".decodeBase64(), "UTF-8"), new String("YXBw".decodeBase64(), "UTF-8"))`)
+		assert.Contains(t, script, "new org.jenkinsci.plugins.github_branch_source.BranchDiscoveryTrait(1)")
+		assert.Contains(t, script, "new org.jenkinsci.plugins.github_branch_source.OriginPullRequestDiscoveryTrait(1)")
+		assert.Contains(t, script, "new org.jenkinsci.plugins.github_branch_source.TagDiscoveryTrait()")
+		assert.NotContains(t, script, "ForkPullRequestDiscoveryTrait")
+		assert.Contains(t, script, `projectFactory.setScriptPath(new String("Y2kvSmVua2luc2ZpbGU=".decodeBase64(), "UTF-8"))`)
+		assert.Contains(t, script, `new PeriodicFolderTrigger("1h")`)
+	})
+	t.Run("user defined strings are encoded", func(t *testing.T) {
+		seedJob := v1alpha2.SeedJob{
+			ID:            "app",
+			Description:   `"); evil("${x}`,
+			RepositoryURL: "https://git.example.com/team/app.git",
+			BranchSource: &v1alpha2.SeedJobBranchSource{
+				Kind:       v1alpha2.MultibranchPipelineBranchSourceKind,
+				ScriptPath: `"); evil("${x}`,
+			},
+		}
+
+		script, err := branchSourceCreatingGroovyScript(seedJob)
+
+		assert.NoError(t, err)
+		assert.NotContains(t, script, "evil")
+		assert.Contains(t, script, `jobRef.setDescription(new String("Iik7IGV2aWwoIiR7eH0=".decodeBase64(), "UTF-8"))`)
+		assert.Contains(t, script, `projectFactory.setScriptPath(new String("Iik7IGV2aWwoIiR7eH0=".decodeBase64(), "UTF-8"))`)
+	})
+	t.Run("GitHub organization folder", func(t *testing.T) {
+		seedJob := v1alpha2.SeedJob{
+			ID:           "maximba",
+			CredentialID: "github-app",
+			BranchSource: &v1alpha2.SeedJobBranchSource{
+				Kind:              v1alpha2.GitHubOrganizationBranchSourceKind,
+				Owner:             "maximba",
+				RepositoryPattern: `app-\d+`,
+			},
+		}
+
+		script, err := branchSourceCreatingGroovyScript(seedJob)
+
+		assert.NoError(t, err)
+		assert.Contains(t, script, "parent.createProject(jenkins.branch.OrganizationFolder, \"maximba\")")
+		assert.Contains(t, script, `GitHubSCMNavigator(new String("bWF4aW1iYQ==".decodeBase64(), "UTF-8"))`)
+		assert.Contains(t, script, "new org.jenkinsci.plugins.github_branch_source.BranchDiscoveryTrait(3)")
+		assert.Contains(t, script, `RegexSCMSourceFilterTrait(new String("YXBwLVxkKw==".decodeBase64(), "UTF-8"))`)
+		assert.NotContains(t, script, "WorkflowMultiBranchProject,")
+	})
+}
+
+func TestEnsureLabelsForSecrets(t *testing.T) {
+	ctx := context.TODO()
+	jenkins := jenkinsCustomResource()
+	jenkins.Spec.SeedJobs[0].JenkinsCredentialType = v1alpha2.BasicSSHCredentialType
+	jenkins.Spec.SeedJobs[0].CredentialID = "deploy-key"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "deploy-key",
+			Namespace: jenkins.Namespace,
+		},
+	}
+
+	t.Run("global credential", func(t *testing.T) {
+		fakeClient := fake.NewClientBuilder().WithObjects(secret.DeepCopy()).Build()
+		seedJobClient := New(nil, configuration.Configuration{Client: fakeClient, Jenkins: jenkins})
+
+		err := seedJobClient.ensureLabelsForSecrets(*jenkins)
+
+		assert.NoError(t, err)
+		actual := &corev1.Secret{}
+		assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}, actual))
+		assert.Equal(t, string(v1alpha2.BasicSSHCredentialType), actual.Labels[JenkinsCredentialTypeLabelName])
+	})
+	t.Run("folder credential", func(t *testing.T) {
+		folderJenkins := jenkins.DeepCopy()
+		folderJenkins.Spec.SeedJobs[0].CredentialFolder = "team-a"
+		labeledSecret := secret.DeepCopy()
+		labeledSecret.Labels = resources.BuildLabelsForWatchedResources(*folderJenkins)
+		labeledSecret.Labels[JenkinsCredentialTypeLabelName] = string(v1alpha2.BasicSSHCredentialType)
+		fakeClient := fake.NewClientBuilder().WithObjects(labeledSecret).Build()
+		seedJobClient := New(nil, configuration.Configuration{Client: fakeClient, Jenkins: folderJenkins})
+
+		err := seedJobClient.ensureLabelsForSecrets(*folderJenkins)
+
+		assert.NoError(t, err)
+		actual := &corev1.Secret{}
+		assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}, actual))
+		assert.NotContains(t, actual.Labels, JenkinsCredentialTypeLabelName)
+		assert.Equal(t, resources.BuildLabelsForWatchedResources(*folderJenkins), actual.Labels)
+	})
+	t.Run("external Jenkins", func(t *testing.T) {
+		externalJenkins := jenkins.DeepCopy()
+		externalJenkins.Spec.ExternalJenkins = &v1alpha2.ExternalJenkins{URL: "https://jenkins.example.com/"}
+		fakeClient := fake.NewClientBuilder().WithObjects(secret.DeepCopy()).Build()
+		seedJobClient := New(nil, configuration.Configuration{Client: fakeClient, Jenkins: externalJenkins})
+
+		err := seedJobClient.ensureLabelsForSecrets(*externalJenkins)
+
+		assert.NoError(t, err)
+		actual := &corev1.Secret{}
+		assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}, actual))
+		assert.Equal(t, resources.BuildLabelsForWatchedResources(*externalJenkins), actual.Labels)
+	})
+}
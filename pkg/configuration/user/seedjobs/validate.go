@@ -3,9 +3,13 @@ package seedjobs
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/cron"
 
 	stackerr "github.com/pkg/errors"
 	"golang.org/x/crypto/ssh"
@@ -14,6 +18,18 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
+// githubOwnerRegex matches GitHub organization and user names
+var githubOwnerRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,37}[a-zA-Z0-9])?$`)
+
+// folderNameRegex matches Jenkins folder names which are passed to the groovy scripts creating the seed jobs
+var folderNameRegex = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// allowedScanIntervals are the intervals supported by the periodic folder trigger of multibranch pipelines
+var allowedScanIntervals = map[string]bool{
+	"1m": true, "2m": true, "5m": true, "10m": true, "15m": true, "20m": true, "25m": true, "30m": true,
+	"1h": true, "2h": true, "4h": true, "8h": true, "12h": true, "1d": true, "2d": true, "1w": true, "2w": true, "4w": true,
+}
+
 // ValidateSeedJobs verify seed jobs configuration
 func (s *seedJobs) ValidateSeedJobs(jenkins v1alpha2.Jenkins) ([]string, error) {
 	var messages []string
@@ -21,22 +37,28 @@ func (s *seedJobs) ValidateSeedJobs(jenkins v1alpha2.Jenkins) ([]string, error)
 	if msg := s.validateIfIDIsUnique(jenkins.Spec.SeedJobs); len(msg) > 0 {
 		messages = append(messages, msg...)
 	}
+	messages = append(messages, validateAgentSecret(jenkins.Spec.SeedJobAgentSecret)...)
 
 	for _, seedJob := range jenkins.Spec.SeedJobs {
+		seedJobMessages := len(messages)
 		if len(seedJob.ID) == 0 {
 			messages = append(messages, fmt.Sprintf("seedJob `%s` id can't be empty", seedJob.ID))
 		}
 
-		if len(seedJob.RepositoryBranch) == 0 {
-			messages = append(messages, fmt.Sprintf("seedJob `%s` repository branch can't be empty", seedJob.ID))
-		}
+		if seedJob.BranchSource != nil {
+			messages = append(messages, validateBranchSource(jenkins, seedJob)...)
+		} else {
+			if len(seedJob.RepositoryBranch) == 0 {
+				messages = append(messages, fmt.Sprintf("seedJob `%s` repository branch can't be empty", seedJob.ID))
+			}
 
-		if len(seedJob.RepositoryURL) == 0 {
-			messages = append(messages, fmt.Sprintf("seedJob `%s` repository URL branch can't be empty", seedJob.ID))
-		}
+			if len(seedJob.RepositoryURL) == 0 {
+				messages = append(messages, fmt.Sprintf("seedJob `%s` repository URL branch can't be empty", seedJob.ID))
+			}
 
-		if len(seedJob.Targets) == 0 {
-			messages = append(messages, fmt.Sprintf("seedJob `%s` targets can't be empty", seedJob.ID))
+			if len(seedJob.Targets) == 0 {
+				messages = append(messages, fmt.Sprintf("seedJob `%s` targets can't be empty", seedJob.ID))
+			}
 		}
 
 		if _, ok := v1alpha2.AllowedJenkinsCredentialMap[string(seedJob.JenkinsCredentialType)]; !ok {
@@ -48,6 +70,45 @@ func (s *seedJobs) ValidateSeedJobs(jenkins v1alpha2.Jenkins) ([]string, error)
 			messages = append(messages, fmt.Sprintf("seedJob `%s` credential ID can't be empty", seedJob.ID))
 		}
 
+		if len(seedJob.CredentialFolder) > 0 {
+			if seedJob.JenkinsCredentialType != v1alpha2.BasicSSHCredentialType &&
+				seedJob.JenkinsCredentialType != v1alpha2.UsernamePasswordCredentialType {
+				messages = append(messages, fmt.Sprintf("seedJob `%s` credential folder can be used only with '%s' or '%s' credential type",
+					seedJob.ID, v1alpha2.BasicSSHCredentialType, v1alpha2.UsernamePasswordCredentialType))
+			}
+			if !folderNameRegex.MatchString(seedJob.CredentialFolder) {
+				messages = append(messages, fmt.Sprintf("seedJob `%s` credential folder '%s' can contain only letters, digits, '.', '_' and '-'",
+					seedJob.ID, seedJob.CredentialFolder))
+			}
+		}
+
+		if seedJob.EphemeralCredential {
+			if !isSecretCredential(seedJob) {
+				messages = append(messages, fmt.Sprintf("seedJob `%s` ephemeral credential can be used only with '%s' or '%s' credential type",
+					seedJob.ID, v1alpha2.BasicSSHCredentialType, v1alpha2.UsernamePasswordCredentialType))
+			}
+			if len(seedJob.CredentialFolder) > 0 {
+				messages = append(messages, fmt.Sprintf("seedJob `%s` ephemeral credential can't be used together with credential folder", seedJob.ID))
+			}
+			if seedJob.BranchSource != nil {
+				messages = append(messages, fmt.Sprintf("seedJob `%s` ephemeral credential can't be used together with branchSource, it's scanned by Jenkins master", seedJob.ID))
+			}
+			// the repository URL is written to the git configuration of the seed job agent
+			if strings.IndexFunc(seedJob.RepositoryURL, unicode.IsControl) >= 0 {
+				messages = append(messages, fmt.Sprintf("seedJob `%s` repository URL can't contain control characters while using ephemeral credential", seedJob.ID))
+			}
+		}
+
+		if len(seedJob.Folder) > 0 {
+			if !folderNameRegex.MatchString(seedJob.Folder) {
+				messages = append(messages, fmt.Sprintf("seedJob `%s` folder '%s' can contain only letters, digits, '.', '_' and '-'",
+					seedJob.ID, seedJob.Folder))
+			}
+			if len(seedJob.CredentialFolder) > 0 && seedJob.CredentialFolder != seedJob.Folder {
+				messages = append(messages, fmt.Sprintf("seedJob `%s` folder '%s' doesn't match credential folder '%s'", seedJob.ID, seedJob.Folder, seedJob.CredentialFolder))
+			}
+		}
+
 		// validate repository url match private key
 		if strings.Contains(seedJob.RepositoryURL, "git@") && seedJob.JenkinsCredentialType == v1alpha2.NoJenkinsCredentialCredentialType {
 			messages = append(messages, fmt.Sprintf("seedJob `%s` Jenkins credential must be set while using ssh repository url", seedJob.ID))
@@ -71,6 +132,10 @@ func (s *seedJobs) ValidateSeedJobs(jenkins v1alpha2.Jenkins) ([]string, error)
 						messages = append(messages, fmt.Sprintf("seedJob `%s` %s", seedJob.ID, m))
 					}
 				}
+				if IsEphemeralCredential(seedJob) && len(secret.Data[KnownHostsSecretKey]) == 0 {
+					messages = append(messages, fmt.Sprintf("seedJob `%s` required data '%s' not found in secret '%s', it's required by ephemeral credential",
+						seedJob.ID, KnownHostsSecretKey, secret.ObjectMeta.Name))
+				}
 			}
 			if seedJob.JenkinsCredentialType == v1alpha2.UsernamePasswordCredentialType {
 				if msg := validateUsernamePasswordSecret(*secret); len(msg) > 0 {
@@ -88,6 +153,18 @@ func (s *seedJobs) ValidateSeedJobs(jenkins v1alpha2.Jenkins) ([]string, error)
 			}
 		}
 
+		if len(seedJob.PollSCM) > 0 {
+			if err := cron.Validate(seedJob.PollSCM); err != nil {
+				messages = append(messages, fmt.Sprintf("seedJob `%s` pollSCM: %s", seedJob.ID, err))
+			}
+		}
+
+		if len(seedJob.BuildPeriodically) > 0 {
+			if err := cron.Validate(seedJob.BuildPeriodically); err != nil {
+				messages = append(messages, fmt.Sprintf("seedJob `%s` buildPeriodically: %s", seedJob.ID, err))
+			}
+		}
+
 		if seedJob.GitHubPushTrigger {
 			if msg := s.validateGitHubPushTrigger(jenkins); len(msg) > 0 {
 				for _, m := range msg {
@@ -103,11 +180,104 @@ func (s *seedJobs) ValidateSeedJobs(jenkins v1alpha2.Jenkins) ([]string, error)
 				}
 			}
 		}
+
+		if seedJob.GitLabPushTrigger {
+			if msg := s.validateGitLabPushTrigger(jenkins); len(msg) > 0 {
+				for _, m := range msg {
+					messages = append(messages, fmt.Sprintf("seedJob `%s` %s", seedJob.ID, m))
+				}
+			}
+		}
+
+		if seedJob.DeepValidation != nil {
+			messages = append(messages, validateDeepValidationSpec(seedJob)...)
+			// repository access is verified only when the rest of seed job configuration is valid
+			if len(messages) == seedJobMessages {
+				msg, err := s.validateRepositoryAccess(jenkins, seedJob)
+				if err != nil {
+					return nil, err
+				}
+				messages = append(messages, msg...)
+			}
+		}
+	}
+
+	if err := s.cleanupDeepValidationPods(jenkins); err != nil {
+		return nil, err
 	}
 
 	return messages, nil
 }
 
+// validateBranchSource verifies the branch source of a seed job generating multibranch pipeline or GitHub
+// organization folder
+func validateBranchSource(jenkins v1alpha2.Jenkins, seedJob v1alpha2.SeedJob) []string {
+	var messages []string
+	branchSource := seedJob.BranchSource
+	github := len(branchSource.Owner) > 0
+
+	switch branchSource.Kind {
+	case v1alpha2.GitHubOrganizationBranchSourceKind:
+		if !github {
+			messages = append(messages, "branchSource owner can't be empty for GitHubOrganization")
+		}
+		if len(branchSource.Repository) > 0 {
+			messages = append(messages, "branchSource repository can't be set for GitHubOrganization, use repositoryPattern")
+		}
+		if len(branchSource.RepositoryPattern) > 0 {
+			if _, err := regexp.Compile(branchSource.RepositoryPattern); err != nil {
+				messages = append(messages, fmt.Sprintf("branchSource repositoryPattern is invalid: %s", err))
+			}
+		}
+	case v1alpha2.MultibranchPipelineBranchSourceKind:
+		if github && len(branchSource.Repository) == 0 {
+			messages = append(messages, "branchSource repository can't be empty when owner is set")
+		}
+		if !github && len(seedJob.RepositoryURL) == 0 {
+			messages = append(messages, "repository URL can't be empty when branchSource owner is not set")
+		}
+		if len(branchSource.RepositoryPattern) > 0 {
+			messages = append(messages, "branchSource repositoryPattern can be set only for GitHubOrganization")
+		}
+	default:
+		messages = append(messages, fmt.Sprintf("branchSource unknown kind '%s'", branchSource.Kind))
+	}
+
+	if github {
+		if !githubOwnerRegex.MatchString(branchSource.Owner) {
+			messages = append(messages, fmt.Sprintf("branchSource owner '%s' is not a valid GitHub organization or user name", branchSource.Owner))
+		}
+		if seedJob.JenkinsCredentialType == v1alpha2.BasicSSHCredentialType {
+			messages = append(messages, fmt.Sprintf("GitHub branch source can't use '%s' credential type", v1alpha2.BasicSSHCredentialType))
+		}
+	} else if branchSource.Discovery.PullRequestsFromOrigin || branchSource.Discovery.PullRequestsFromForks {
+		messages = append(messages, "pull requests can be discovered only by GitHub branch source, set branchSource owner")
+	}
+
+	if len(seedJob.CredentialFolder) > 0 {
+		messages = append(messages, "credential folder can't be used together with branchSource")
+	}
+
+	if len(branchSource.ScanInterval) > 0 && !allowedScanIntervals[branchSource.ScanInterval] {
+		messages = append(messages, fmt.Sprintf("branchSource scanInterval '%s' is not supported", branchSource.ScanInterval))
+	}
+
+	requiredPlugins := []string{"workflow-multibranch"}
+	if github {
+		requiredPlugins = append(requiredPlugins, "github-branch-source")
+	}
+	for _, plugin := range requiredPlugins {
+		if err := checkPluginExists(jenkins, plugin); err != nil {
+			messages = append(messages, fmt.Sprintf("branchSource cannot be used: %s", err))
+		}
+	}
+
+	for i, message := range messages {
+		messages[i] = fmt.Sprintf("seedJob `%s` %s", seedJob.ID, message)
+	}
+	return messages
+}
+
 func (s *seedJobs) validateGitHubPushTrigger(jenkins v1alpha2.Jenkins) []string {
 	var messages []string
 	if err := checkPluginExists(jenkins, "github"); err != nil {
@@ -124,6 +294,14 @@ func (s *seedJobs) validateBitbucketPushTrigger(jenkins v1alpha2.Jenkins) []stri
 	return messages
 }
 
+func (s *seedJobs) validateGitLabPushTrigger(jenkins v1alpha2.Jenkins) []string {
+	var messages []string
+	if err := checkPluginExists(jenkins, "gitlab-plugin"); err != nil {
+		return append(messages, fmt.Sprintf("gitlabPushTrigger cannot be enabled: %s", err))
+	}
+	return messages
+}
+
 func checkPluginExists(jenkins v1alpha2.Jenkins, name string) error {
 	exists := false
 	for _, plugin := range jenkins.Spec.Master.BasePlugins {
@@ -217,6 +395,14 @@ func validateGithubAppSecret(secret v1.Secret) []string {
 	if len(pkey) == 0 {
 		messages = append(messages, fmt.Sprintf("required data '%s' is empty in secret '%s'", PrivateKeySecretKey, secret.ObjectMeta.Name))
 	}
+	if owner, exists := secret.Data[OwnerSecretKey]; exists && !githubOwnerRegex.Match(owner) {
+		messages = append(messages, fmt.Sprintf("data '%s' in secret '%s' is not a valid GitHub organization or user name", OwnerSecretKey, secret.ObjectMeta.Name))
+	}
+	if installationID, exists := secret.Data[InstallationIDSecretKey]; exists {
+		if id, err := strconv.ParseInt(string(installationID), 10, 64); err != nil || id <= 0 {
+			messages = append(messages, fmt.Sprintf("data '%s' in secret '%s' must be a positive number", InstallationIDSecretKey, secret.ObjectMeta.Name))
+		}
+	}
 
 	return messages
 }
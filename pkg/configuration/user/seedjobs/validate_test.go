@@ -717,6 +717,96 @@ func TestValidateSeedJobs(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Nil(t, result)
 	})
+	t.Run("Valid with owner and installation ID", func(t *testing.T) {
+		jenkins := v1alpha2.Jenkins{
+			ObjectMeta: jenkinsObjectMeta,
+			Spec: v1alpha2.JenkinsSpec{
+				SeedJobs: []v1alpha2.SeedJob{
+					{
+						ID:                    "example",
+						CredentialID:          "deploy-keys",
+						JenkinsCredentialType: v1alpha2.GithubAppCredentialType,
+						Targets:               "cicd/jobs/*.jenkins",
+						RepositoryBranch:      "master",
+						RepositoryURL:         "https://github.com/maximba/kubernetes-operator.git",
+					},
+				},
+			},
+		}
+		secret := &corev1.Secret{
+			TypeMeta:   secretTypeMeta,
+			ObjectMeta: secretObjectMeta,
+			Data: map[string][]byte{
+				AppIDSecretKey:          []byte("some-id"),
+				PrivateKeySecretKey:     []byte("some-key"),
+				OwnerSecretKey:          []byte("maximba"),
+				InstallationIDSecretKey: []byte("12345"),
+			},
+		}
+		fakeClient := fake.NewClientBuilder().Build()
+		err := fakeClient.Create(context.TODO(), secret)
+		assert.NoError(t, err)
+
+		config := configuration.Configuration{
+			Client:        fakeClient,
+			ClientSet:     kubernetes.Clientset{},
+			Notifications: nil,
+			Jenkins:       &v1alpha2.Jenkins{},
+		}
+
+		seedJobs := New(nil, config)
+		result, err := seedJobs.ValidateSeedJobs(jenkins)
+
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+	t.Run("Invalid owner and installation ID", func(t *testing.T) {
+		jenkins := v1alpha2.Jenkins{
+			ObjectMeta: jenkinsObjectMeta,
+			Spec: v1alpha2.JenkinsSpec{
+				SeedJobs: []v1alpha2.SeedJob{
+					{
+						ID:                    "example",
+						CredentialID:          "deploy-keys",
+						JenkinsCredentialType: v1alpha2.GithubAppCredentialType,
+						Targets:               "cicd/jobs/*.jenkins",
+						RepositoryBranch:      "master",
+						RepositoryURL:         "https://github.com/maximba/kubernetes-operator.git",
+					},
+				},
+			},
+		}
+		secret := &corev1.Secret{
+			TypeMeta:   secretTypeMeta,
+			ObjectMeta: secretObjectMeta,
+			Data: map[string][]byte{
+				AppIDSecretKey:          []byte("some-id"),
+				PrivateKeySecretKey:     []byte("some-key"),
+				OwnerSecretKey:          []byte("-maximba/"),
+				InstallationIDSecretKey: []byte("abc"),
+			},
+		}
+		fakeClient := fake.NewClientBuilder().Build()
+		err := fakeClient.Create(context.TODO(), secret)
+		assert.NoError(t, err)
+
+		config := configuration.Configuration{
+			Client:        fakeClient,
+			ClientSet:     kubernetes.Clientset{},
+			Notifications: nil,
+			Jenkins:       &v1alpha2.Jenkins{},
+		}
+
+		seedJobs := New(nil, config)
+		result, err := seedJobs.ValidateSeedJobs(jenkins)
+
+		assert.NoError(t, err)
+
+		assert.Equal(t, result, []string{
+			"seedJob `example` data 'owner' in secret 'deploy-keys' is not a valid GitHub organization or user name",
+			"seedJob `example` data 'installationId' in secret 'deploy-keys' must be a positive number",
+		})
+	})
 	t.Run("Invalid with empty app id", func(t *testing.T) {
 		jenkins := v1alpha2.Jenkins{
 			ObjectMeta: jenkinsObjectMeta,
@@ -1056,6 +1146,334 @@ func TestValidateSeedJobs(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Nil(t, result)
 	})
+	t.Run("Invalid with set gitlabPushTrigger and not installed GitLab plugin", func(t *testing.T) {
+		jenkins := v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				SeedJobs: []v1alpha2.SeedJob{
+					{
+						ID:                    "example",
+						CredentialID:          "jenkins-operator-e2e",
+						JenkinsCredentialType: v1alpha2.NoJenkinsCredentialCredentialType,
+						Targets:               "cicd/jobs/*.jenkins",
+						RepositoryBranch:      "master",
+						RepositoryURL:         "https://gitlab.com/maximba/kubernetes-operator.git",
+						GitLabPushTrigger:     true,
+					},
+				},
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().Build()
+
+		config := configuration.Configuration{
+			Client:        fakeClient,
+			ClientSet:     kubernetes.Clientset{},
+			Notifications: nil,
+			Jenkins:       &v1alpha2.Jenkins{},
+		}
+
+		seedJobs := New(nil, config)
+		result, err := seedJobs.ValidateSeedJobs(jenkins)
+
+		assert.NoError(t, err)
+
+		assert.Equal(t, result, []string{"seedJob `example` gitlabPushTrigger cannot be enabled: `gitlab-plugin` plugin not installed"})
+	})
+	t.Run("Valid with set gitlabPushTrigger and installed GitLab plugin", func(t *testing.T) {
+		jenkins := v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				SeedJobs: []v1alpha2.SeedJob{
+					{
+						ID:                    "example",
+						CredentialID:          "jenkins-operator-e2e",
+						JenkinsCredentialType: v1alpha2.NoJenkinsCredentialCredentialType,
+						Targets:               "cicd/jobs/*.jenkins",
+						RepositoryBranch:      "master",
+						RepositoryURL:         "https://gitlab.com/maximba/kubernetes-operator.git",
+						GitLabPushTrigger:     true,
+					},
+				},
+				Master: v1alpha2.JenkinsMaster{
+					Plugins: []v1alpha2.Plugin{
+						{Name: "gitlab-plugin", Version: "latest"},
+					},
+				},
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().Build()
+
+		config := configuration.Configuration{
+			Client:        fakeClient,
+			ClientSet:     kubernetes.Clientset{},
+			Notifications: nil,
+			Jenkins:       &v1alpha2.Jenkins{},
+		}
+
+		seedJobs := New(nil, config)
+		result, err := seedJobs.ValidateSeedJobs(jenkins)
+
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+	t.Run("Invalid credential folder", func(t *testing.T) {
+		jenkins := v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				SeedJobs: []v1alpha2.SeedJob{
+					{
+						ID:                    "example",
+						JenkinsCredentialType: v1alpha2.NoJenkinsCredentialCredentialType,
+						CredentialFolder:      `team/a"${x}`,
+						Targets:               "cicd/jobs/*.jenkins",
+						RepositoryBranch:      "master",
+						RepositoryURL:         "https://github.com/maximba/kubernetes-operator.git",
+					},
+				},
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().Build()
+
+		config := configuration.Configuration{
+			Client:        fakeClient,
+			ClientSet:     kubernetes.Clientset{},
+			Notifications: nil,
+			Jenkins:       &v1alpha2.Jenkins{},
+		}
+
+		seedJobs := New(nil, config)
+		result, err := seedJobs.ValidateSeedJobs(jenkins)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"seedJob `example` credential folder can be used only with 'basicSSHUserPrivateKey' or 'usernamePassword' credential type",
+			"seedJob `example` credential folder 'team/a\"${x}' can contain only letters, digits, '.', '_' and '-'",
+		}, result)
+	})
+	t.Run("Invalid folder", func(t *testing.T) {
+		jenkins := v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				SeedJobs: []v1alpha2.SeedJob{
+					{
+						ID:                    "example",
+						JenkinsCredentialType: v1alpha2.NoJenkinsCredentialCredentialType,
+						CredentialFolder:      "team-a",
+						Folder:                `team/b"${x}`,
+						Targets:               "cicd/jobs/*.jenkins",
+						RepositoryBranch:      "master",
+						RepositoryURL:         "https://github.com/maximba/kubernetes-operator.git",
+					},
+				},
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().Build()
+
+		config := configuration.Configuration{
+			Client:        fakeClient,
+			ClientSet:     kubernetes.Clientset{},
+			Notifications: nil,
+			Jenkins:       &v1alpha2.Jenkins{},
+		}
+
+		seedJobs := New(nil, config)
+		result, err := seedJobs.ValidateSeedJobs(jenkins)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"seedJob `example` credential folder can be used only with 'basicSSHUserPrivateKey' or 'usernamePassword' credential type",
+			"seedJob `example` folder 'team/b\"${x}' can contain only letters, digits, '.', '_' and '-'",
+			"seedJob `example` folder 'team/b\"${x}' doesn't match credential folder 'team-a'",
+		}, result)
+	})
+	t.Run("Invalid ephemeral credential", func(t *testing.T) {
+		jenkins := v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				SeedJobs: []v1alpha2.SeedJob{
+					{
+						ID:                    "example",
+						JenkinsCredentialType: v1alpha2.NoJenkinsCredentialCredentialType,
+						EphemeralCredential:   true,
+						Targets:               "cicd/jobs/*.jenkins",
+						RepositoryBranch:      "master",
+						RepositoryURL:         "https://github.com/maximba/kubernetes-operator.git",
+					},
+				},
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().Build()
+
+		config := configuration.Configuration{
+			Client:        fakeClient,
+			ClientSet:     kubernetes.Clientset{},
+			Notifications: nil,
+			Jenkins:       &v1alpha2.Jenkins{},
+		}
+
+		seedJobs := New(nil, config)
+		result, err := seedJobs.ValidateSeedJobs(jenkins)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"seedJob `example` ephemeral credential can be used only with 'basicSSHUserPrivateKey' or 'usernamePassword' credential type",
+		}, result)
+	})
+	t.Run("Invalid ephemeral SSH credential without known hosts", func(t *testing.T) {
+		jenkins := v1alpha2.Jenkins{
+			ObjectMeta: jenkinsObjectMeta,
+			Spec: v1alpha2.JenkinsSpec{
+				SeedJobs: []v1alpha2.SeedJob{
+					{
+						ID:                    "example",
+						CredentialID:          "deploy-keys",
+						JenkinsCredentialType: v1alpha2.BasicSSHCredentialType,
+						EphemeralCredential:   true,
+						Targets:               "cicd/jobs/*.jenkins",
+						RepositoryBranch:      "master",
+						RepositoryURL:         "git@github.com:maximba/kubernetes-operator.git",
+					},
+				},
+			},
+		}
+		secret := &corev1.Secret{
+			TypeMeta:   secretTypeMeta,
+			ObjectMeta: secretObjectMeta,
+			Data: map[string][]byte{
+				UsernameSecretKey:   []byte("username"),
+				PrivateKeySecretKey: []byte(fakeEd25519PrivateKey),
+			},
+		}
+		fakeClient := fake.NewClientBuilder().WithObjects(secret).Build()
+
+		config := configuration.Configuration{
+			Client:        fakeClient,
+			ClientSet:     kubernetes.Clientset{},
+			Notifications: nil,
+			Jenkins:       &v1alpha2.Jenkins{},
+		}
+
+		seedJobs := New(nil, config)
+		result, err := seedJobs.ValidateSeedJobs(jenkins)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"seedJob `example` required data 'knownHosts' not found in secret 'deploy-keys', it's required by ephemeral credential",
+		}, result)
+
+		secret.Data[KnownHostsSecretKey] = []byte("github.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl")
+		config.Client = fake.NewClientBuilder().WithObjects(secret).Build()
+		result, err = New(nil, config).ValidateSeedJobs(jenkins)
+
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+	t.Run("Invalid ephemeral credential repository URL", func(t *testing.T) {
+		jenkins := v1alpha2.Jenkins{
+			ObjectMeta: jenkinsObjectMeta,
+			Spec: v1alpha2.JenkinsSpec{
+				SeedJobs: []v1alpha2.SeedJob{
+					{
+						ID:                    "example",
+						CredentialID:          "deploy-keys",
+						JenkinsCredentialType: v1alpha2.UsernamePasswordCredentialType,
+						EphemeralCredential:   true,
+						Targets:               "cicd/jobs/*.jenkins",
+						RepositoryBranch:      "master",
+						RepositoryURL:         "https://github.com/maximba/kubernetes-operator.git\"]\n\thelper = \"!f",
+					},
+				},
+			},
+		}
+		secret := &corev1.Secret{
+			TypeMeta:   secretTypeMeta,
+			ObjectMeta: secretObjectMeta,
+			Data: map[string][]byte{
+				UsernameSecretKey: []byte("username"),
+				PasswordSecretKey: []byte("password"),
+			},
+		}
+		fakeClient := fake.NewClientBuilder().WithObjects(secret).Build()
+
+		config := configuration.Configuration{
+			Client:        fakeClient,
+			ClientSet:     kubernetes.Clientset{},
+			Notifications: nil,
+			Jenkins:       &v1alpha2.Jenkins{},
+		}
+
+		seedJobs := New(nil, config)
+		result, err := seedJobs.ValidateSeedJobs(jenkins)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"seedJob `example` repository URL can't contain control characters while using ephemeral credential",
+		}, result)
+	})
+}
+
+func TestValidateBranchSource(t *testing.T) {
+	jenkins := v1alpha2.Jenkins{Spec: v1alpha2.JenkinsSpec{Master: v1alpha2.JenkinsMaster{Plugins: []v1alpha2.Plugin{
+		{Name: "workflow-multibranch", Version: "2.26"},
+		{Name: "github-branch-source", Version: "2.11.1"},
+	}}}}
+
+	t.Run("valid multibranch pipeline with Git branch source", func(t *testing.T) {
+		seedJob := v1alpha2.SeedJob{
+			ID:            "app",
+			RepositoryURL: "https://git.example.com/team/app.git",
+			BranchSource:  &v1alpha2.SeedJobBranchSource{Kind: v1alpha2.MultibranchPipelineBranchSourceKind, ScanInterval: "5m"},
+		}
+
+		assert.Empty(t, validateBranchSource(jenkins, seedJob))
+	})
+	t.Run("valid GitHub organization folder", func(t *testing.T) {
+		seedJob := v1alpha2.SeedJob{
+			ID:                    "maximba",
+			CredentialID:          "github-app",
+			JenkinsCredentialType: v1alpha2.GithubAppCredentialType,
+			BranchSource: &v1alpha2.SeedJobBranchSource{
+				Kind:      v1alpha2.GitHubOrganizationBranchSourceKind,
+				Owner:     "maximba",
+				Discovery: v1alpha2.SeedJobBranchDiscovery{PullRequestsFromForks: true},
+			},
+		}
+
+		assert.Empty(t, validateBranchSource(jenkins, seedJob))
+	})
+	t.Run("GitHub organization folder without owner", func(t *testing.T) {
+		seedJob := v1alpha2.SeedJob{
+			ID:           "org",
+			BranchSource: &v1alpha2.SeedJobBranchSource{Kind: v1alpha2.GitHubOrganizationBranchSourceKind, RepositoryPattern: "app-("},
+		}
+
+		messages := validateBranchSource(jenkins, seedJob)
+
+		assert.Len(t, messages, 2)
+		assert.Equal(t, "seedJob `org` branchSource owner can't be empty for GitHubOrganization", messages[0])
+		assert.Contains(t, messages[1], "seedJob `org` branchSource repositoryPattern is invalid")
+	})
+	t.Run("invalid multibranch pipeline", func(t *testing.T) {
+		seedJob := v1alpha2.SeedJob{
+			ID:               "app",
+			CredentialFolder: "team",
+			BranchSource: &v1alpha2.SeedJobBranchSource{
+				Kind:         v1alpha2.MultibranchPipelineBranchSourceKind,
+				ScanInterval: "3h",
+				Discovery:    v1alpha2.SeedJobBranchDiscovery{PullRequestsFromOrigin: true},
+			},
+		}
+
+		messages := validateBranchSource(v1alpha2.Jenkins{}, seedJob)
+
+		assert.Equal(t, []string{
+			"seedJob `app` repository URL can't be empty when branchSource owner is not set",
+			"seedJob `app` pull requests can be discovered only by GitHub branch source, set branchSource owner",
+			"seedJob `app` credential folder can't be used together with branchSource",
+			"seedJob `app` branchSource scanInterval '3h' is not supported",
+			"seedJob `app` branchSource cannot be used: `workflow-multibranch` plugin not installed",
+		}, messages)
+	})
 }
 
 func TestValidateIfIDIsUnique(t *testing.T) {
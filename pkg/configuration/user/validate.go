@@ -1,9 +1,18 @@
 package user
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/backuprestore"
 	"github.com/maximba/kubernetes-operator/pkg/configuration/user/seedjobs"
+	"github.com/maximba/kubernetes-operator/pkg/metrics"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // Validate validates Jenkins CR Spec section
@@ -13,6 +22,40 @@ func (r *reconcileUserConfiguration) Validate(jenkins *v1alpha2.Jenkins) ([]stri
 		return msg, nil
 	}
 
+	if msg, err := r.validateReadinessCheck(jenkins.Spec.ReadinessCheck); err != nil {
+		return nil, err
+	} else if len(msg) > 0 {
+		return msg, nil
+	}
+
 	seedJobs := seedjobs.New(r.jenkinsClient, r.Configuration)
-	return seedJobs.ValidateSeedJobs(*jenkins)
+	messages, err := seedJobs.ValidateSeedJobs(*jenkins)
+	if err != nil {
+		return nil, err
+	}
+	metrics.SetSeedJobValidationErrors(jenkins, len(messages))
+	return messages, nil
+}
+
+func (r *reconcileUserConfiguration) validateReadinessCheck(readinessCheck *v1alpha2.ReadinessCheck) ([]string, error) {
+	if readinessCheck == nil {
+		return nil, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.Configuration.Jenkins.Namespace, Name: readinessCheck.ConfigMapRef.Name}, configMap)
+	if err != nil && apierrors.IsNotFound(err) {
+		return []string{fmt.Sprintf("ConfigMap '%s' defined in spec.readinessCheck.configMap not found", readinessCheck.ConfigMapRef.Name)}, nil
+	} else if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+
+	if len(readinessCheck.Key) > 0 {
+		if _, found := configMap.Data[readinessCheck.Key]; !found {
+			return []string{fmt.Sprintf("ConfigMap '%s' defined in spec.readinessCheck.configMap must contain '%s'", configMap.Name, readinessCheck.Key)}, nil
+		}
+	} else if len(configMap.Data) != 1 {
+		return []string{fmt.Sprintf("ConfigMap '%s' defined in spec.readinessCheck.configMap must contain exactly one key when spec.readinessCheck.key is not set", configMap.Name)}, nil
+	}
+	return nil, nil
 }
@@ -0,0 +1,50 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateReadinessCheck(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "smoke-test", Namespace: jenkins.Namespace},
+		Data:       map[string]string{"smoke-test.groovy": "assert Jenkins.instance.pluginManager.failedPlugins.isEmpty()"},
+	}
+	newReconciler := func() *reconcileUserConfiguration {
+		config := configuration.Configuration{Client: fake.NewClientBuilder().WithObjects(configMap).Build(), Jenkins: jenkins}
+		return New(config, nil).(*reconcileUserConfiguration)
+	}
+
+	t.Run("not set", func(t *testing.T) {
+		got, err := newReconciler().validateReadinessCheck(nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 0)
+	})
+	t.Run("single key", func(t *testing.T) {
+		got, err := newReconciler().validateReadinessCheck(&v1alpha2.ReadinessCheck{ConfigMapRef: v1alpha2.ConfigMapRef{Name: "smoke-test"}})
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 0)
+	})
+	t.Run("missing key", func(t *testing.T) {
+		got, err := newReconciler().validateReadinessCheck(&v1alpha2.ReadinessCheck{ConfigMapRef: v1alpha2.ConfigMapRef{Name: "smoke-test"}, Key: "other.groovy"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ConfigMap 'smoke-test' defined in spec.readinessCheck.configMap must contain 'other.groovy'"}, got)
+	})
+	t.Run("missing ConfigMap", func(t *testing.T) {
+		got, err := newReconciler().validateReadinessCheck(&v1alpha2.ReadinessCheck{ConfigMapRef: v1alpha2.ConfigMapRef{Name: "missing"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ConfigMap 'missing' defined in spec.readinessCheck.configMap not found"}, got)
+	})
+}
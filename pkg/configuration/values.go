@@ -0,0 +1,25 @@
+package configuration
+
+import (
+	"context"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetValues returns the key/values of ConfigMap referenced by spec.values, it returns nil when spec.values is not set
+func GetValues(k8sClient client.Client, jenkins *v1alpha2.Jenkins) (map[string]string, error) {
+	if jenkins.Spec.Values == nil {
+		return nil, nil
+	}
+	configMap := &corev1.ConfigMap{}
+	err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Spec.Values.Name}, configMap)
+	if err != nil {
+		return nil, stackerr.Wrapf(err, "failed to get values ConfigMap '%s'", jenkins.Spec.Values.Name)
+	}
+	return configMap.Data, nil
+}
@@ -9,6 +9,8 @@ const (
 	SeedJobSuffix = "job-dsl-seed"
 	// DefaultJenkinsMasterImage is the default Jenkins master docker image
 	DefaultJenkinsMasterImage = "jenkins/jenkins:2.319.3-lts"
+	// DefaultSeedJobAgentImage is the default image used for the seed job agent
+	DefaultSeedJobAgentImage = "jenkins/inbound-agent:4.10-3"
 	// DefaultHTTPPortInt32 is the default Jenkins HTTP port
 	DefaultHTTPPortInt32 = int32(8080)
 	// DefaultSlavePortInt32 is the default Jenkins port for slaves
@@ -13,4 +13,11 @@ const (
 
 	// LabelJenkinsCRKey Kubernetes label name which contains Jenkins CR name
 	LabelJenkinsCRKey = "jenkins-cr"
+
+	// LabelJenkinsHomeKey Kubernetes label name which marks Jenkins home PersistentVolumeClaim as active or retired
+	// after the migration to another storage class
+	LabelJenkinsHomeKey = "jenkins-home"
+
+	// LabelConfigurationVersionKey Kubernetes label name which contains the kind of the immutable configuration version
+	LabelConfigurationVersionKey = "configuration-version"
 )
@@ -0,0 +1,212 @@
+// Package credentials reports differences between credentials present in Jenkins and the ones managed by the
+// operator, it helps audits of credentials created manually in Jenkins.
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/user/seedjobs"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/metrics"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const credentialsUsageChangedEventReason k8sevent.Reason = "CredentialsUsageChanged"
+
+// listCredentialsGroovyScript prints IDs of credentials from all credentials stores visible in Jenkins and folders
+const listCredentialsGroovyScript = `
+import com.cloudbees.plugins.credentials.CredentialsProvider
+import com.cloudbees.plugins.credentials.common.IdCredentials
+import hudson.model.ItemGroup
+import jenkins.model.Jenkins
+
+def ids = new TreeSet()
+def contexts = [Jenkins.get()] + Jenkins.get().getAllItems().findAll { it instanceof ItemGroup }
+contexts.each { context ->
+    CredentialsProvider.lookupStores(context).each { store ->
+        store.getDomains().each { domain ->
+            store.getCredentials(domain).findAll { it instanceof IdCredentials }.each { ids << it.id }
+        }
+    }
+}
+ids.each { println it }
+`
+
+// UsageReporter periodically compares credentials present in Jenkins with the credentials managed by the operator,
+// i.e. Kubernetes secrets exposed by kubernetes-credentials-provider plugin and seed job credentials. The numbers of
+// the differences are exported as metrics and an event with the credential IDs is emitted when the difference changes.
+type UsageReporter struct {
+	Client                       client.Client
+	ClientSet                    kubernetes.Clientset
+	Config                       rest.Config
+	Events                       k8sevent.Recorder
+	JenkinsAPIConnectionSettings jenkinsclient.JenkinsAPIConnectionSettings
+	KubernetesClusterDomain      string
+	Interval                     time.Duration
+
+	// reported are the last reported differences, they are kept in memory only, so they are reported again after
+	// the operator restart
+	reported map[types.NamespacedName]*Usage
+}
+
+// Usage defines differences between credentials present in Jenkins and the ones managed by the operator.
+type Usage struct {
+	// Unmanaged are IDs of credentials present in Jenkins which are not managed by the operator,
+	// e.g. created manually or by Configuration as Code
+	Unmanaged []string
+	// Missing are IDs of credentials managed by the operator which are not present in Jenkins
+	Missing []string
+}
+
+// Start reports credentials usage every interval until the context is done.
+func (r *UsageReporter) Start(ctx context.Context) error {
+	r.reported = map[types.NamespacedName]*Usage{}
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.reportAll(ctx)
+		}
+	}
+}
+
+func (r *UsageReporter) reportAll(ctx context.Context) {
+	jenkinsList := &v1alpha2.JenkinsList{}
+	if err := r.Client.List(ctx, jenkinsList); err != nil {
+		log.Log.V(log.VWarn).Info(fmt.Sprintf("Failed to list Jenkins CRs for credentials usage report: %s", err))
+		return
+	}
+
+	current := map[types.NamespacedName]bool{}
+	for i := range jenkinsList.Items {
+		jenkins := &jenkinsList.Items[i]
+		if jenkins.Status.UserConfigurationCompletedTime == nil {
+			continue
+		}
+		current[types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name}] = true
+		config := configuration.Configuration{
+			Client:                       r.Client,
+			ClientSet:                    r.ClientSet,
+			Config:                       &r.Config,
+			Jenkins:                      jenkins,
+			JenkinsAPIConnectionSettings: r.JenkinsAPIConnectionSettings,
+			KubernetesClusterDomain:      r.KubernetesClusterDomain,
+		}
+		jenkinsClient, err := config.GetJenkinsClient()
+		var usage *Usage
+		if err == nil {
+			usage, err = GetUsage(ctx, r.Client, jenkinsClient, jenkins)
+		}
+		if err != nil {
+			log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace).V(log.VDebug).Info(fmt.Sprintf("Failed to report credentials usage: %s", err))
+			continue
+		}
+		r.report(jenkins, usage)
+	}
+
+	for key := range r.reported {
+		if !current[key] {
+			delete(r.reported, key)
+		}
+	}
+}
+
+// report updates the metrics and emits the event when the difference has changed since the last report
+func (r *UsageReporter) report(jenkins *v1alpha2.Jenkins, usage *Usage) {
+	metrics.SetCredentialsUsageMetrics(jenkins, len(usage.Unmanaged), len(usage.Missing))
+
+	key := types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name}
+	previous, found := r.reported[key]
+	r.reported[key] = usage
+	if reflect.DeepEqual(previous, usage) || (!found && len(usage.Unmanaged) == 0 && len(usage.Missing) == 0) {
+		return
+	}
+
+	message := fmt.Sprintf("Credentials usage has changed, unmanaged '%s', missing '%s'",
+		strings.Join(usage.Unmanaged, ", "), strings.Join(usage.Missing, ", "))
+	log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace).Info(message)
+	if r.Events == nil {
+		return
+	}
+	eventType := k8sevent.TypeNormal
+	if len(usage.Unmanaged) > 0 || len(usage.Missing) > 0 {
+		eventType = k8sevent.TypeWarning
+	}
+	r.Events.Emit(jenkins, eventType, credentialsUsageChangedEventReason, message)
+}
+
+// GetUsage returns the difference between credentials present in Jenkins and the ones managed by the operator.
+func GetUsage(ctx context.Context, k8sClient client.Client, jenkinsClient jenkinsclient.Jenkins, jenkins *v1alpha2.Jenkins) (*Usage, error) {
+	output, err := jenkinsClient.ExecuteScript(listCredentialsGroovyScript)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	present := map[string]bool{}
+	for _, line := range strings.Split(output, "\n") {
+		if id := strings.TrimSpace(line); len(id) > 0 {
+			present[id] = true
+		}
+	}
+
+	managed, err := getManagedCredentials(ctx, k8sClient, jenkins)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Usage{
+		Unmanaged: difference(present, managed),
+		Missing:   difference(managed, present),
+	}, nil
+}
+
+// getManagedCredentials returns IDs of credentials managed by the operator
+func getManagedCredentials(ctx context.Context, k8sClient client.Client, jenkins *v1alpha2.Jenkins) (map[string]bool, error) {
+	managed := map[string]bool{}
+	for _, seedJob := range jenkins.Spec.SeedJobs {
+		// ephemeral credentials are mounted into the seed job agent and they are never present in Jenkins
+		if len(seedJob.CredentialID) > 0 && !seedjobs.IsEphemeralCredential(seedJob) {
+			managed[seedJob.CredentialID] = true
+		}
+	}
+
+	secrets := &corev1.SecretList{}
+	err := k8sClient.List(ctx, secrets, client.InNamespace(jenkins.Namespace), client.HasLabels{seedjobs.JenkinsCredentialTypeLabelName})
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	for _, secret := range secrets.Items {
+		managed[secret.Name] = true
+	}
+	return managed, nil
+}
+
+// difference returns sorted IDs present in the first set and not in the second one
+func difference(first, second map[string]bool) []string {
+	var ids []string
+	for id := range first {
+		if !second[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,123 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/user/seedjobs"
+	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
+	"github.com/maximba/kubernetes-operator/pkg/metrics"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetUsage(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	newJenkins := func() *v1alpha2.Jenkins {
+		return &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+			Spec: v1alpha2.JenkinsSpec{
+				SeedJobs: []v1alpha2.SeedJob{{ID: "jenkins-operator", CredentialID: "seed-job-credentials"}},
+			},
+		}
+	}
+	secrets := []*corev1.Secret{
+		{ObjectMeta: metav1.ObjectMeta{Name: "deploy-key", Namespace: "default", Labels: map[string]string{seedjobs.JenkinsCredentialTypeLabelName: "basicSSHUserPrivateKey"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "github-token", Namespace: "default", Labels: map[string]string{seedjobs.JenkinsCredentialTypeLabelName: "secretText"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "not-a-credential", Namespace: "default"}},
+	}
+
+	t.Run("reports differences", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkins := newJenkins()
+		k8sClient := fake.NewClientBuilder().WithObjects(jenkins, secrets[0], secrets[1], secrets[2]).Build()
+		jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(listCredentialsGroovyScript).Return("deploy-key\nmanual-token\nseed-job-credentials\n", nil)
+
+		usage, err := GetUsage(context.TODO(), k8sClient, jenkinsClient, jenkins)
+
+		require.NoError(t, err)
+		assert.Equal(t, &Usage{
+			Unmanaged: []string{"manual-token"},
+			Missing:   []string{"github-token"},
+		}, usage)
+	})
+	t.Run("no differences", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkins := newJenkins()
+		k8sClient := fake.NewClientBuilder().WithObjects(jenkins, secrets[0]).Build()
+		jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(listCredentialsGroovyScript).Return("deploy-key\nseed-job-credentials\n", nil)
+
+		usage, err := GetUsage(context.TODO(), k8sClient, jenkinsClient, jenkins)
+
+		require.NoError(t, err)
+		assert.Equal(t, &Usage{}, usage)
+	})
+}
+
+type recordedEvent struct {
+	eventType k8sevent.Type
+	message   string
+}
+
+type fakeRecorder struct {
+	events []recordedEvent
+}
+
+func (r *fakeRecorder) Emit(_ runtime.Object, eventType k8sevent.Type, _ k8sevent.Reason, message string) {
+	r.events = append(r.events, recordedEvent{eventType: eventType, message: message})
+}
+
+func (r *fakeRecorder) Emitf(object runtime.Object, eventType k8sevent.Type, reason k8sevent.Reason, format string, args ...interface{}) {
+	r.Emit(object, eventType, reason, fmt.Sprintf(format, args...))
+}
+
+func TestUsageReporter_report(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+	recorder := &fakeRecorder{}
+	reporter := &UsageReporter{Events: recorder, reported: map[types.NamespacedName]*Usage{}}
+
+	t.Run("no differences", func(t *testing.T) {
+		reporter.report(jenkins, &Usage{})
+
+		assert.Empty(t, recorder.events)
+		assert.Equal(t, 0.0, testutil.ToFloat64(metrics.JenkinsUnmanagedCredentials.WithLabelValues("default", "jenkins")))
+	})
+	t.Run("differences changed", func(t *testing.T) {
+		reporter.report(jenkins, &Usage{Unmanaged: []string{"manual-token"}})
+
+		require.Len(t, recorder.events, 1)
+		assert.Equal(t, recordedEvent{
+			eventType: k8sevent.TypeWarning,
+			message:   "Credentials usage has changed, unmanaged 'manual-token', missing ''",
+		}, recorder.events[0])
+		assert.Equal(t, 1.0, testutil.ToFloat64(metrics.JenkinsUnmanagedCredentials.WithLabelValues("default", "jenkins")))
+	})
+	t.Run("same differences", func(t *testing.T) {
+		reporter.report(jenkins, &Usage{Unmanaged: []string{"manual-token"}})
+
+		assert.Len(t, recorder.events, 1)
+	})
+	t.Run("differences resolved", func(t *testing.T) {
+		reporter.report(jenkins, &Usage{})
+
+		require.Len(t, recorder.events, 2)
+		assert.Equal(t, k8sevent.TypeNormal, recorder.events[1].eventType)
+		assert.Equal(t, 0.0, testutil.ToFloat64(metrics.JenkinsUnmanagedCredentials.WithLabelValues("default", "jenkins")))
+	})
+}
@@ -0,0 +1,233 @@
+// Package cron parses Jenkins cron specifications and calculates when they are due.
+package cron
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	stackerr "github.com/pkg/errors"
+)
+
+type fieldRange struct {
+	name     string
+	min, max int
+}
+
+var fieldRanges = []fieldRange{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+// aliases are expanded the same way as in Jenkins
+var aliases = map[string]string{
+	"@yearly":   "H H H H *",
+	"@annually": "H H H H *",
+	"@monthly":  "H H H * *",
+	"@weekly":   "H H * * H",
+	"@daily":    "H H * * *",
+	"@midnight": "H H(0-2) * * *",
+	"@hourly":   "H * * * *",
+}
+
+const timezonePrefix = "TZ="
+
+// maxLookahead limits the search of the next time a schedule is due, e.g. 0 0 31 2 * is never due
+const maxLookahead = 5 * 366 * 24 * time.Hour
+
+// Schedule is a parsed Jenkins cron specification.
+type Schedule struct {
+	location *time.Location
+	entries  []entry
+}
+
+// entry holds values allowed by a single schedule line, a bit is set for every allowed value of the field
+type entry struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+}
+
+// Validate checks if spec is a valid Jenkins cron specification, it accepts the H (hash) syntax, aliases like @daily,
+// a TZ= timezone line, comments and multiple schedules separated by new lines
+func Validate(spec string) error {
+	_, err := Parse(spec, "")
+	return err
+}
+
+// Parse parses Jenkins cron specification, seed is hashed to pick values of the H (hash) syntax like Jenkins
+// hashes job name, so schedules using it are spread evenly but stay stable for the same seed
+func Parse(spec, seed string) (*Schedule, error) {
+	schedule := &Schedule{location: time.Local}
+	for _, line := range strings.Split(spec, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, timezonePrefix) {
+			timezone := strings.TrimPrefix(line, timezonePrefix)
+			location, err := time.LoadLocation(timezone)
+			if err != nil || timezone == "" {
+				return nil, stackerr.Errorf("invalid timezone '%s'", timezone)
+			}
+			schedule.location = location
+			continue
+		}
+		parsed, err := parseLine(line, seed)
+		if err != nil {
+			return nil, stackerr.Wrapf(err, "invalid cron spec '%s'", line)
+		}
+		schedule.entries = append(schedule.entries, parsed)
+	}
+	return schedule, nil
+}
+
+// Next returns the first time after the given time when the schedule is due or zero time if it's never due
+func (s *Schedule) Next(after time.Time) time.Time {
+	var next time.Time
+	for _, e := range s.entries {
+		due := e.next(after.In(s.location))
+		if !due.IsZero() && (next.IsZero() || due.Before(next)) {
+			next = due
+		}
+	}
+	return next
+}
+
+// next returns the first minute after the given time allowed by all fields, day of month and day of week have to
+// match both like in Jenkins
+func (e entry) next(after time.Time) time.Time {
+	location := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxLookahead)
+	for t.Before(limit) {
+		switch {
+		case !isSet(e.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location)
+		case !isSet(e.dayOfMonth, t.Day()) || !isSet(e.dayOfWeek, int(t.Weekday())):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+		case !isSet(e.hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, location)
+		case !isSet(e.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func isSet(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0
+}
+
+func parseLine(line, seed string) (entry, error) {
+	if strings.HasPrefix(line, "@") {
+		expanded, found := aliases[line]
+		if !found {
+			return entry{}, stackerr.Errorf("unknown alias '%s'", line)
+		}
+		line = expanded
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) != len(fieldRanges) {
+		return entry{}, stackerr.Errorf("expected %d fields, got %d", len(fieldRanges), len(fields))
+	}
+	values := make([]uint64, len(fields))
+	for i, field := range fields {
+		for _, item := range strings.Split(field, ",") {
+			bits, err := parseItem(item, fieldRanges[i], hash(seed, i))
+			if err != nil {
+				return entry{}, stackerr.Wrapf(err, "%s field", fieldRanges[i].name)
+			}
+			values[i] |= bits
+		}
+	}
+	// 7 is Sunday like 0
+	if isSet(values[4], 7) {
+		values[4] |= 1
+	}
+	return entry{minute: values[0], hour: values[1], dayOfMonth: values[2], month: values[3], dayOfWeek: values[4]}, nil
+}
+
+func parseItem(item string, fieldRange fieldRange, hash int) (uint64, error) {
+	rangeExpr, step := item, 1
+	hasStep := false
+	if i := strings.Index(item, "/"); i >= 0 {
+		value, err := strconv.Atoi(item[i+1:])
+		if err != nil || value < 1 {
+			return 0, stackerr.Errorf("invalid step '%s'", item[i+1:])
+		}
+		rangeExpr, step, hasStep = item[:i], value, true
+	}
+
+	min, max := fieldRange.min, fieldRange.max
+	hashed := false
+	var err error
+	switch {
+	case rangeExpr == "*":
+	case rangeExpr == "H":
+		hashed = true
+	case strings.HasPrefix(rangeExpr, "H(") && strings.HasSuffix(rangeExpr, ")"):
+		hashed = true
+		min, max, err = parseRange(strings.TrimSuffix(strings.TrimPrefix(rangeExpr, "H("), ")"), fieldRange, true)
+	default:
+		min, max, err = parseRange(rangeExpr, fieldRange, !hasStep)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if hashed {
+		// day of week 7 and day of month above 28 aren't picked to not skip short months, like in Jenkins
+		if fieldRange.name == "day of week" && max == 7 && min < 7 {
+			max = 6
+		}
+		if fieldRange.name == "day of month" && max > 28 && min <= 28 {
+			max = 28
+		}
+		if !hasStep {
+			return 1 << uint(min+hash%(max-min+1)), nil
+		}
+		span := step
+		if span > max-min+1 {
+			span = max - min + 1
+		}
+		min += hash % span
+	}
+
+	var bits uint64
+	for value := min; value <= max; value += step {
+		bits |= 1 << uint(value)
+	}
+	return bits, nil
+}
+
+func parseRange(rangeExpr string, fieldRange fieldRange, singleValueAllowed bool) (min, max int, err error) {
+	bounds := strings.Split(rangeExpr, "-")
+	if len(bounds) > 2 || (len(bounds) == 1 && !singleValueAllowed) {
+		return 0, 0, stackerr.Errorf("invalid range '%s'", rangeExpr)
+	}
+	values := make([]int, len(bounds))
+	for i, bound := range bounds {
+		value, err := strconv.Atoi(bound)
+		if err != nil || value < fieldRange.min || value > fieldRange.max {
+			return 0, 0, stackerr.Errorf("value '%s' out of range %d-%d", bound, fieldRange.min, fieldRange.max)
+		}
+		values[i] = value
+	}
+	if len(values) == 2 && values[0] > values[1] {
+		return 0, 0, stackerr.Errorf("invalid range '%s'", rangeExpr)
+	}
+	return values[0], values[len(values)-1], nil
+}
+
+// hash returns a stable non-negative number for the seed and the field
+func hash(seed string, field int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(seed + "/" + strconv.Itoa(field)))
+	return int(h.Sum32() & 0x7fffffff)
+}
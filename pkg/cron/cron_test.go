@@ -0,0 +1,103 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		for _, spec := range []string{
+			"1 2 3 4 5",
+			"H/15 * * * *",
+			"H(0-29)/10 H(1-5) * * 1-5",
+			"*/5 0-23/2 1,15 * 0,7",
+			"@daily",
+			"@midnight",
+			"TZ=Europe/London\nH 2 * * *",
+			"# nightly\nH 2 * * *\nH 14 * * *",
+		} {
+			assert.NoError(t, Validate(spec), spec)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, spec := range []string{
+			"* * * *",
+			"60 * * * *",
+			"* 24 * * *",
+			"* * 0 * *",
+			"5-1 * * * *",
+			"5/10 * * * *",
+			"*/0 * * * *",
+			"H(0-60) * * * *",
+			"@every5m",
+			"TZ=Mars/Olympus\nH * * * *",
+		} {
+			assert.Error(t, Validate(spec), spec)
+		}
+	})
+}
+
+func TestSchedule_Next(t *testing.T) {
+	after := time.Date(2021, time.March, 10, 10, 30, 15, 0, time.UTC)
+
+	t.Run("fixed time", func(t *testing.T) {
+		schedule, err := Parse("TZ=UTC\n15 2 * * *", "")
+		require.NoError(t, err)
+
+		assert.Equal(t, time.Date(2021, time.March, 11, 2, 15, 0, 0, time.UTC), schedule.Next(after).UTC())
+	})
+	t.Run("step", func(t *testing.T) {
+		schedule, err := Parse("TZ=UTC\n*/20 * * * *", "")
+		require.NoError(t, err)
+
+		assert.Equal(t, time.Date(2021, time.March, 10, 10, 40, 0, 0, time.UTC), schedule.Next(after).UTC())
+	})
+	t.Run("day of week", func(t *testing.T) {
+		schedule, err := Parse("TZ=UTC\n0 0 * * 7", "")
+		require.NoError(t, err)
+
+		assert.Equal(t, time.Date(2021, time.March, 14, 0, 0, 0, 0, time.UTC), schedule.Next(after).UTC())
+	})
+	t.Run("earliest of multiple lines", func(t *testing.T) {
+		schedule, err := Parse("TZ=UTC\n0 12 * * *\n0 11 * * *", "")
+		require.NoError(t, err)
+
+		assert.Equal(t, time.Date(2021, time.March, 10, 11, 0, 0, 0, time.UTC), schedule.Next(after).UTC())
+	})
+	t.Run("timezone", func(t *testing.T) {
+		schedule, err := Parse("TZ=Europe/Warsaw\n0 12 * * *", "")
+		require.NoError(t, err)
+
+		assert.Equal(t, time.Date(2021, time.March, 10, 11, 0, 0, 0, time.UTC), schedule.Next(after).UTC())
+	})
+	t.Run("hash is stable for the same seed", func(t *testing.T) {
+		first, err := Parse("H H * * *", "namespace/jenkins")
+		require.NoError(t, err)
+		second, err := Parse("@daily", "namespace/jenkins")
+		require.NoError(t, err)
+
+		next := first.Next(after)
+		assert.False(t, next.IsZero())
+		assert.Equal(t, next, second.Next(after))
+		assert.Equal(t, next.Add(24*time.Hour), first.Next(next))
+	})
+	t.Run("hash within range", func(t *testing.T) {
+		for _, seed := range []string{"a", "b", "c", "d", "e"} {
+			schedule, err := Parse("TZ=UTC\nH(10-19) 3 H(29-31) * *", seed)
+			require.NoError(t, err)
+
+			next := schedule.Next(after).UTC()
+			assert.True(t, next.Minute() >= 10 && next.Minute() <= 19, next)
+		}
+	})
+	t.Run("never due", func(t *testing.T) {
+		schedule, err := Parse("0 0 31 2 *", "")
+		require.NoError(t, err)
+
+		assert.True(t, schedule.Next(after).IsZero())
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
 	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
@@ -15,18 +16,57 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	k8s "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// ConfigMapsSource is the source of the groovy scripts applied for all the customization ConfigMaps
+	ConfigMapsSource = "configmaps"
+
+	// MaxOutputLength limits the groovy script output kept in status and events, Kubernetes truncates
+	// event messages to 1024 characters
+	MaxOutputLength = 512
+)
+
 // Groovy defines API for groovy secrets execution via jenkins job
 type Groovy struct {
-	k8sClient         k8s.Client
-	logger            logr.Logger
-	jenkins           *v1alpha2.Jenkins
-	jenkinsClient     jenkinsclient.Jenkins
-	configurationType string
-	customization     v1alpha2.Customization
+	k8sClient               k8s.Client
+	logger                  logr.Logger
+	jenkins                 *v1alpha2.Jenkins
+	jenkinsClient           jenkinsclient.Jenkins
+	configurationType       string
+	customization           v1alpha2.Customization
+	parallelism             int
+	dependencies            map[string][]string
+	kubernetesClusterDomain string
+	progress                func(Progress)
+	applied                 int
+	total                   int
+}
+
+// Progress is the progress of applying groovy scripts by Ensure reported after every executed script
+type Progress struct {
+	ConfigurationType string
+	Source            string
+	Name              string
+	// Logs is the output of the executed groovy script
+	Logs string
+	// Err is the error of the failed groovy script
+	Err error
+	// Applied is the number of applied groovy scripts including the executed one when it hasn't failed
+	Applied int
+	// Total is the number of groovy scripts to apply
+	Total int
+}
+
+// pendingGroovyScript is a groovy script which has to be applied
+type pendingGroovyScript struct {
+	source string
+	name   string
+	hash   string
+	script string
 }
 
 // New creates new instance of Groovy
@@ -37,16 +77,71 @@ func New(jenkinsClient jenkinsclient.Jenkins, k8sClient k8s.Client, jenkins *v1a
 		jenkins:           jenkins,
 		configurationType: configurationType,
 		customization:     customization,
-		logger:            log.Log.WithValues("cr", jenkins.Name),
+		logger:            log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace),
 	}
 }
 
+// Parallel enables concurrent apply of up to parallelism groovy scripts in Ensure, the scripts with dependencies are
+// applied one by one after the scripts they depend on
+func (g *Groovy) Parallel(parallelism int, dependencies map[string][]string) *Groovy {
+	g.parallelism = parallelism
+	g.dependencies = dependencies
+	return g
+}
+
+// WithProgress sets the function called after every groovy script executed by Ensure
+func (g *Groovy) WithProgress(progress func(Progress)) *Groovy {
+	g.progress = progress
+	return g
+}
+
+func (g *Groovy) reportProgress(source, name, logs string, err error) {
+	if g.progress == nil || g.total == 0 {
+		return
+	}
+	if err == nil {
+		g.applied++
+	}
+	g.progress(Progress{
+		ConfigurationType: g.configurationType,
+		Source:            source,
+		Name:              name,
+		Logs:              logs,
+		Err:               err,
+		Applied:           g.applied,
+		Total:             g.total,
+	})
+}
+
 // EnsureSingle runs single groovy script
 func (g *Groovy) EnsureSingle(source, name, hash, groovyScript string) (requeue bool, err error) {
+	return g.EnsureSingleFunc(source, name, hash, func() (string, error) {
+		return g.execute(source, name, groovyScript)
+	})
+}
+
+// EnsureSingleFunc runs apply instead of a groovy script and records it in status like a groovy script
+func (g *Groovy) EnsureSingleFunc(source, name, hash string, apply func() (logs string, err error)) (requeue bool, err error) {
 	if g.isGroovyScriptAlreadyApplied(source, name, hash) {
 		return false, nil
 	}
 
+	logs, err := apply()
+	g.reportProgress(source, name, logs, err)
+	g.setGroovyScriptResult(source, name, hash, logs, err)
+	if err != nil {
+		// the result is recorded best effort, the script error is returned as is to be reported by the controller
+		if updateErr := g.k8sClient.Status().Update(context.TODO(), g.jenkins); updateErr != nil {
+			g.logger.V(log.VWarn).Info(fmt.Sprintf("Couldn't record %s Source '%s' Name '%s' groovy script result: %s", g.configurationType, source, name, updateErr))
+		}
+		return true, err
+	}
+	g.setGroovyScriptApplied(source, name, hash)
+
+	return true, g.k8sClient.Status().Update(context.TODO(), g.jenkins)
+}
+
+func (g *Groovy) execute(source, name, groovyScript string) (string, error) {
 	logs, err := g.jenkinsClient.ExecuteScript(groovyScript)
 	if err != nil {
 		if groovyErr, ok := err.(*jenkinsclient.GroovyScriptExecutionFailed); ok {
@@ -56,9 +151,12 @@ func (g *Groovy) EnsureSingle(source, name, hash, groovyScript string) (requeue
 			groovyErr.Logs = logs
 			g.logger.V(log.VWarn).Info(fmt.Sprintf("%s Source '%s' Name '%s' groovy script execution failed, logs :\n%s", g.configurationType, source, name, logs))
 		}
-		return true, err
+		return logs, err
 	}
+	return logs, nil
+}
 
+func (g *Groovy) setGroovyScriptApplied(source, name, hash string) {
 	var appliedGroovyScripts []v1alpha2.AppliedGroovyScript
 
 	for _, ags := range g.jenkins.Status.AppliedGroovyScripts {
@@ -76,8 +174,64 @@ func (g *Groovy) EnsureSingle(source, name, hash, groovyScript string) (requeue
 	})
 
 	g.jenkins.Status.AppliedGroovyScripts = appliedGroovyScripts
+}
 
-	return true, g.k8sClient.Status().Update(context.TODO(), g.jenkins)
+// setGroovyScriptResult records the result of the executed groovy script in status, the output of the failed script
+// is recorded only when spec.groovyScriptOutput is enabled
+func (g *Groovy) setGroovyScriptResult(source, name, hash, logs string, err error) {
+	result := v1alpha2.GroovyScriptResult{
+		ConfigurationType: g.configurationType,
+		Source:            source,
+		Name:              name,
+		Hash:              hash,
+		Succeeded:         err == nil,
+		Time:              metav1.Now(),
+	}
+	if err != nil {
+		result.Error = err.Error()
+		if output := TailOutput(logs); len(output) > 0 && g.jenkins.Spec.GroovyScriptOutput {
+			result.Error = fmt.Sprintf("%s, output:\n%s", result.Error, output)
+		}
+	}
+
+	for i, r := range g.jenkins.Status.GroovyScriptResults {
+		if r.ConfigurationType == g.configurationType && r.Source == source && r.Name == name {
+			g.jenkins.Status.GroovyScriptResults[i] = result
+			return
+		}
+	}
+	g.jenkins.Status.GroovyScriptResults = append(g.jenkins.Status.GroovyScriptResults, result)
+}
+
+// PruneResults removes from status the results of the groovy scripts of the configuration type which aren't
+// configured anymore, configured returns true for the scripts which are kept
+func (g *Groovy) PruneResults(configured func(source, name string) bool) error {
+	var results []v1alpha2.GroovyScriptResult
+	for _, result := range g.jenkins.Status.GroovyScriptResults {
+		if result.ConfigurationType == g.configurationType && !configured(result.Source, result.Name) {
+			continue
+		}
+		results = append(results, result)
+	}
+	if len(results) == len(g.jenkins.Status.GroovyScriptResults) {
+		return nil
+	}
+
+	g.jenkins.Status.GroovyScriptResults = results
+	return errors.WithStack(g.k8sClient.Status().Update(context.TODO(), g.jenkins))
+}
+
+// TailOutput returns the last lines of the groovy script output which fit into MaxOutputLength
+func TailOutput(logs string) string {
+	logs = strings.TrimSpace(logs)
+	if len(logs) <= MaxOutputLength {
+		return logs
+	}
+	logs = logs[len(logs)-MaxOutputLength:]
+	if index := strings.Index(logs, "\n"); index >= 0 && index < len(logs)-1 {
+		logs = logs[index+1:]
+	}
+	return logs
 }
 
 // WaitForSecretSynchronization runs groovy script which waits to synchronize secrets in pod by k8s
@@ -96,18 +250,84 @@ func (g *Groovy) WaitForSecretSynchronization(secretsPath string) (requeue bool,
 	for secretKey, secretValue := range secret.Data {
 		toCalculate[secretKey] = string(secretValue)
 	}
-	hash, err := g.calculateHash(toCalculate)
+	return g.waitForSynchronization(fmt.Sprintf("Secret '%s'", secret.Name), g.customization.Secret.Name, "synchronizing-secret.groovy", secretsPath, toCalculate)
+}
+
+// WaitForConfigMapsSynchronization runs groovy script which waits to synchronize the customization ConfigMaps
+// projected into a single directory in pod by k8s
+func (g *Groovy) WaitForConfigMapsSynchronization(configMapsPath string) (requeue bool, err error) {
+	toCalculate := map[string]string{}
+	for _, configMapRef := range g.customization.Configurations {
+		configMap := &corev1.ConfigMap{}
+		err = g.k8sClient.Get(context.TODO(), types.NamespacedName{Name: configMapRef.Name, Namespace: g.jenkins.ObjectMeta.Namespace}, configMap)
+		if err != nil {
+			return true, errors.WithStack(err)
+		}
+		for key, value := range configMap.Data {
+			toCalculate[key] = value
+		}
+	}
+	return g.waitForSynchronization("ConfigMaps", ConfigMapsSource, "synchronizing-configmaps.groovy", configMapsPath, toCalculate)
+}
+
+func (g *Groovy) waitForSynchronization(description, source, name, path string, data map[string]string) (requeue bool, err error) {
+	hash, err := g.calculateHash(data)
 	if err != nil {
 		return true, errors.WithStack(err)
 	}
 
-	name := "synchronizing-secret.groovy"
-	if g.isGroovyScriptAlreadyApplied(g.customization.Secret.Name, name, hash) {
+	if g.isGroovyScriptAlreadyApplied(source, name, hash) {
 		return false, nil
 	}
 
-	g.logger.Info(fmt.Sprintf("%s Secret '%s' running synchronization", g.configurationType, secret.Name))
-	return g.EnsureSingle(g.customization.Secret.Name, name, hash, fmt.Sprintf(synchronizeSecretsGroovyScriptFmt, secretsPath, hash))
+	g.logger.Info(fmt.Sprintf("%s %s running synchronization", g.configurationType, description))
+	return g.EnsureSingle(source, name, hash, fmt.Sprintf(synchronizeSecretsGroovyScriptFmt, path, hash))
+}
+
+// CustomizationHash returns the hash of the customization Secret and ConfigMaps
+func (g *Groovy) CustomizationHash() (string, error) {
+	toCalculate := map[string]string{}
+	if len(g.customization.Secret.Name) > 0 {
+		secret := &corev1.Secret{}
+		err := g.k8sClient.Get(context.TODO(), types.NamespacedName{Name: g.customization.Secret.Name, Namespace: g.jenkins.ObjectMeta.Namespace}, secret)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		for key, value := range secret.Data {
+			toCalculate["secret/"+key] = string(value)
+		}
+	}
+	for _, configMapRef := range g.customization.Configurations {
+		configMap := &corev1.ConfigMap{}
+		err := g.k8sClient.Get(context.TODO(), types.NamespacedName{Name: configMapRef.Name, Namespace: g.jenkins.ObjectMeta.Namespace}, configMap)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		for key, value := range configMap.Data {
+			toCalculate[configMap.Name+"/"+key] = value
+		}
+	}
+	return g.calculateHash(toCalculate)
+}
+
+// IsChanged returns true if the groovy script has been applied with a different hash
+func (g *Groovy) IsChanged(source, name, hash string) bool {
+	for _, appliedGroovyScript := range g.jenkins.Status.AppliedGroovyScripts {
+		if appliedGroovyScript.ConfigurationType == g.configurationType && appliedGroovyScript.Source == source &&
+			appliedGroovyScript.Name == name {
+			return appliedGroovyScript.Hash != hash
+		}
+	}
+	return false
+}
+
+// SetApplied records in status the groovy script applied without running it
+func (g *Groovy) SetApplied(source, name, hash string) error {
+	if g.isGroovyScriptAlreadyApplied(source, name, hash) {
+		return nil
+	}
+	g.setGroovyScriptApplied(source, name, hash)
+	return g.k8sClient.Status().Update(context.TODO(), g.jenkins)
 }
 
 // Ensure runs all groovy scripts configured in customization structure
@@ -120,6 +340,9 @@ func (g *Groovy) Ensure(filter func(name string) bool, updateGroovyScript func(g
 		}
 	}
 
+	var pending []pendingGroovyScript
+	configured := map[string]bool{}
+	g.applied, g.total = 0, 0
 	for _, configMapRef := range g.customization.Configurations {
 		configMap := &corev1.ConfigMap{}
 		err := g.k8sClient.Get(context.TODO(), types.NamespacedName{Name: configMapRef.Name, Namespace: g.jenkins.ObjectMeta.Namespace}, configMap)
@@ -134,29 +357,121 @@ func (g *Groovy) Ensure(filter func(name string) bool, updateGroovyScript func(g
 		sort.Strings(names)
 
 		for _, name := range names {
-			groovyScript := updateGroovyScript(configMap.Data[name])
 			if !filter(name) {
 				g.logger.V(log.VDebug).Info(fmt.Sprintf("Skipping %s ConfigMap '%s' name '%s'", g.configurationType, configMap.Name, name))
 				continue
 			}
+			script, err := g.renderTemplate(configMap.Name, name, configMap.Data[name])
+			if err != nil {
+				return true, err
+			}
+			groovyScript := updateGroovyScript(script)
 
 			hash, err := g.calculateCustomizationHash(*secret, name, groovyScript)
 			if err != nil {
 				return true, errors.WithStack(err)
 			}
+			g.total++
+			configured[configMap.Name+"/"+name] = true
 			if g.isGroovyScriptAlreadyApplied(configMap.Name, name, hash) {
+				g.applied++
 				continue
 			}
+			pending = append(pending, pendingGroovyScript{source: configMap.Name, name: name, hash: hash, script: groovyScript})
+		}
+	}
+	// the synchronization scripts are run for the Secret and the ConfigMaps, not for their entries
+	err = g.PruneResults(func(source, name string) bool {
+		return source == ConfigMapsSource || source == g.customization.Secret.Name || configured[source+"/"+name]
+	})
+	if err != nil {
+		return true, err
+	}
+	if len(pending) == 0 {
+		return false, nil
+	}
+	if g.parallelism > 1 {
+		return g.ensureParallel(pending)
+	}
+
+	// the scripts are applied one by one in the reconcile loops
+	script := pending[0]
+	g.logger.Info(fmt.Sprintf("%s ConfigMap '%s' name '%s' running groovy script", g.configurationType, script.source, script.name))
+	return g.EnsureSingle(script.source, script.name, script.hash, script.script)
+}
 
-			g.logger.Info(fmt.Sprintf("%s ConfigMap '%s' name '%s' running groovy script", g.configurationType, configMap.Name, name))
-			requeue, err := g.EnsureSingle(configMap.Name, name, hash, groovyScript)
-			if err != nil || requeue {
-				return requeue, err
+// ensureParallel applies the pending groovy scripts without dependencies concurrently, when there are none
+// the first script which dependencies are applied is run
+func (g *Groovy) ensureParallel(scripts []pendingGroovyScript) (requeue bool, err error) {
+	var independent []pendingGroovyScript
+	pending := map[string]bool{}
+	for _, script := range scripts {
+		pending[script.name] = true
+		if len(g.dependencies[script.name]) == 0 {
+			independent = append(independent, script)
+		}
+	}
+	if len(independent) > 0 {
+		return true, g.applyConcurrently(independent)
+	}
+
+	for _, script := range scripts {
+		ready := true
+		for _, dependency := range g.dependencies[script.name] {
+			if pending[dependency] {
+				ready = false
+				break
 			}
 		}
+		if ready {
+			g.logger.Info(fmt.Sprintf("%s ConfigMap '%s' name '%s' running groovy script", g.configurationType, script.source, script.name))
+			return g.EnsureSingle(script.source, script.name, script.hash, script.script)
+		}
 	}
 
-	return false, nil
+	var names []string
+	for _, script := range scripts {
+		names = append(names, script.name)
+	}
+	return true, errors.Errorf("%s groovy scripts '%s' have circular dependencies", g.configurationType, strings.Join(names, "', '"))
+}
+
+// applyConcurrently runs the groovy scripts concurrently and records the results in status, the error of the
+// first failed script in the apply order is returned regardless of the order of completion
+func (g *Groovy) applyConcurrently(scripts []pendingGroovyScript) error {
+	errs := make([]error, len(scripts))
+	logs := make([]string, len(scripts))
+	semaphore := make(chan struct{}, g.parallelism)
+	wg := sync.WaitGroup{}
+	for i, script := range scripts {
+		wg.Add(1)
+		go func(i int, script pendingGroovyScript) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			g.logger.Info(fmt.Sprintf("%s ConfigMap '%s' name '%s' running groovy script", g.configurationType, script.source, script.name))
+			logs[i], errs[i] = g.execute(script.source, script.name, script.script)
+		}(i, script)
+	}
+	wg.Wait()
+
+	var firstErr error
+	for i, script := range scripts {
+		g.reportProgress(script.source, script.name, logs[i], errs[i])
+		g.setGroovyScriptResult(script.source, script.name, script.hash, logs[i], errs[i])
+		if errs[i] != nil {
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
+		}
+		g.setGroovyScriptApplied(script.source, script.name, script.hash)
+	}
+	if err := g.k8sClient.Status().Update(context.TODO(), g.jenkins); err != nil {
+		return err
+	}
+	return firstErr
 }
 
 func (g *Groovy) calculateCustomizationHash(secret corev1.Secret, key, groovyScript string) (string, error) {
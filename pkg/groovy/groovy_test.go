@@ -17,6 +17,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -68,6 +69,10 @@ func TestGroovy_EnsureSingle(t *testing.T) {
 		assert.Equal(t, hash, jenkins.Status.AppliedGroovyScripts[0].Hash)
 		assert.Equal(t, source, jenkins.Status.AppliedGroovyScripts[0].Source)
 		assert.Equal(t, groovyScriptName, jenkins.Status.AppliedGroovyScripts[0].Name)
+		require.Len(t, jenkins.Status.GroovyScriptResults, 1)
+		assert.True(t, jenkins.Status.GroovyScriptResults[0].Succeeded)
+		assert.Equal(t, hash, jenkins.Status.GroovyScriptResults[0].Hash)
+		assert.Empty(t, jenkins.Status.GroovyScriptResults[0].Error)
 	})
 	t.Run("no execute script", func(t *testing.T) {
 		// given
@@ -344,6 +349,82 @@ func TestGroovy_EnsureSingle(t *testing.T) {
 		err = fakeClient.Get(ctx, types.NamespacedName{Name: jenkins.Name, Namespace: jenkins.Namespace}, jenkins)
 		require.NoError(t, err)
 		assert.Equal(t, 0, len(jenkins.Status.AppliedGroovyScripts))
+		require.Len(t, jenkins.Status.GroovyScriptResults, 1)
+		result := jenkins.Status.GroovyScriptResults[0]
+		assert.Equal(t, configurationType, result.ConfigurationType)
+		assert.Equal(t, source, result.Source)
+		assert.Equal(t, groovyScriptName, result.Name)
+		assert.Equal(t, hash, result.Hash)
+		assert.False(t, result.Succeeded)
+		assert.Equal(t, "script execution failed", result.Error)
+		assert.False(t, result.Time.IsZero())
+	})
+	t.Run("execute script fails with recorded output", func(t *testing.T) {
+		// given
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      jenkinsName,
+				Namespace: namespace,
+			},
+			Spec: v1alpha2.JenkinsSpec{GroovyScriptOutput: true},
+		}
+		err := v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme)
+		require.NoError(t, err)
+		fakeClient := fake.NewClientBuilder().Build()
+		err = fakeClient.Create(ctx, jenkins)
+		require.NoError(t, err)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(groovyScript).Return("fail logs", &jenkinsclient.GroovyScriptExecutionFailed{})
+
+		groovyClient := New(jenkinsClient, fakeClient, jenkins, configurationType, emptyCustomization)
+
+		// when
+		_, err = groovyClient.EnsureSingle(source, groovyScriptName, hash, groovyScript)
+
+		// then
+		require.Error(t, err)
+		err = fakeClient.Get(ctx, types.NamespacedName{Name: jenkins.Name, Namespace: jenkins.Namespace}, jenkins)
+		require.NoError(t, err)
+		require.Len(t, jenkins.Status.GroovyScriptResults, 1)
+		assert.Equal(t, "script execution failed, output:\nfail logs", jenkins.Status.GroovyScriptResults[0].Error)
+	})
+	t.Run("execute script succeeds after failure", func(t *testing.T) {
+		// given
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      jenkinsName,
+				Namespace: namespace,
+			},
+		}
+		err := v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme)
+		require.NoError(t, err)
+		fakeClient := fake.NewClientBuilder().Build()
+		err = fakeClient.Create(ctx, jenkins)
+		require.NoError(t, err)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(groovyScript).Return("fail logs", &jenkinsclient.GroovyScriptExecutionFailed{})
+		jenkinsClient.EXPECT().ExecuteScript(groovyScript).Return("logs", nil)
+
+		groovyClient := New(jenkinsClient, fakeClient, jenkins, configurationType, emptyCustomization)
+
+		// when
+		_, err = groovyClient.EnsureSingle(source, groovyScriptName, hash, groovyScript)
+		require.Error(t, err)
+		_, err = groovyClient.EnsureSingle(source, groovyScriptName, hash, groovyScript)
+
+		// then
+		require.NoError(t, err)
+		err = fakeClient.Get(ctx, types.NamespacedName{Name: jenkins.Name, Namespace: jenkins.Namespace}, jenkins)
+		require.NoError(t, err)
+		require.Len(t, jenkins.Status.GroovyScriptResults, 1)
+		assert.True(t, jenkins.Status.GroovyScriptResults[0].Succeeded)
+		assert.Empty(t, jenkins.Status.GroovyScriptResults[0].Error)
 	})
 }
 
@@ -611,6 +692,186 @@ func TestGroovy_Ensure(t *testing.T) {
 	})
 }
 
+func TestGroovy_EnsureParallel(t *testing.T) {
+	log.SetupLogger(true)
+	ctx := context.TODO()
+	configMapName := "config-map-name"
+	allGroovyScriptsFunc := func(name string) bool {
+		return true
+	}
+	noUpdateGroovyScript := func(groovyScript string) string {
+		return groovyScript
+	}
+	customization := v1alpha2.Customization{
+		Configurations: []v1alpha2.ConfigMapRef{{Name: configMapName}},
+	}
+	newFakeClient := func(t *testing.T, jenkins *v1alpha2.Jenkins) k8sclient.Client {
+		require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: configMapName, Namespace: jenkins.Namespace},
+			Data: map[string]string{
+				"a.groovy": "script-a",
+				"b.groovy": "script-b",
+				"c.groovy": "script-c",
+			},
+		}
+		return fake.NewClientBuilder().WithObjects(jenkins, configMap).Build()
+	}
+	appliedNames := func(t *testing.T, fakeClient k8sclient.Client, jenkins *v1alpha2.Jenkins) []string {
+		actual := &v1alpha2.Jenkins{}
+		require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: jenkins.Name, Namespace: jenkins.Namespace}, actual))
+		var names []string
+		for _, applied := range actual.Status.AppliedGroovyScripts {
+			names = append(names, applied.Name)
+		}
+		return names
+	}
+
+	t.Run("apply independent scripts concurrently and then dependent ones", func(t *testing.T) {
+		// given
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+		fakeClient := newFakeClient(t, jenkins)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript("script-a").Return("logs", nil)
+		jenkinsClient.EXPECT().ExecuteScript("script-b").Return("logs", nil)
+		jenkinsClient.EXPECT().ExecuteScript("script-c").Return("logs", nil)
+		groovyClient := New(jenkinsClient, fakeClient, jenkins, configurationType, customization).
+			Parallel(2, map[string][]string{"a.groovy": {"c.groovy"}})
+
+		// when
+		requeue, err := groovyClient.Ensure(allGroovyScriptsFunc, noUpdateGroovyScript)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, requeue)
+		assert.Equal(t, []string{"b.groovy", "c.groovy"}, appliedNames(t, fakeClient, jenkins))
+
+		requeue, err = groovyClient.Ensure(allGroovyScriptsFunc, noUpdateGroovyScript)
+		require.NoError(t, err)
+		assert.True(t, requeue)
+		assert.Equal(t, []string{"b.groovy", "c.groovy", "a.groovy"}, appliedNames(t, fakeClient, jenkins))
+
+		requeue, err = groovyClient.Ensure(allGroovyScriptsFunc, noUpdateGroovyScript)
+		require.NoError(t, err)
+		assert.False(t, requeue)
+	})
+	t.Run("report the first failed script in apply order", func(t *testing.T) {
+		// given
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+		fakeClient := newFakeClient(t, jenkins)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript("script-a").Return("logs", &jenkinsclient.GroovyScriptExecutionFailed{})
+		jenkinsClient.EXPECT().ExecuteScript("script-b").Return("logs", fmt.Errorf("connection refused"))
+		jenkinsClient.EXPECT().ExecuteScript("script-c").Return("logs", nil)
+		groovyClient := New(jenkinsClient, fakeClient, jenkins, configurationType, customization).Parallel(3, nil)
+
+		// when
+		requeue, err := groovyClient.Ensure(allGroovyScriptsFunc, noUpdateGroovyScript)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, requeue)
+		groovyErr, ok := err.(*jenkinsclient.GroovyScriptExecutionFailed)
+		require.True(t, ok)
+		assert.Equal(t, "a.groovy", groovyErr.Name)
+		assert.Equal(t, configMapName, groovyErr.Source)
+		assert.Equal(t, []string{"c.groovy"}, appliedNames(t, fakeClient, jenkins))
+
+		err = fakeClient.Get(context.TODO(), types.NamespacedName{Name: jenkins.Name, Namespace: jenkins.Namespace}, jenkins)
+		require.NoError(t, err)
+		require.Len(t, jenkins.Status.GroovyScriptResults, 3)
+		assert.False(t, jenkins.Status.GroovyScriptResults[0].Succeeded)
+		assert.Equal(t, "connection refused", jenkins.Status.GroovyScriptResults[1].Error)
+		assert.True(t, jenkins.Status.GroovyScriptResults[2].Succeeded)
+	})
+	t.Run("report progress", func(t *testing.T) {
+		// given
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+		fakeClient := newFakeClient(t, jenkins)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript("script-a").Return("a done", nil)
+		jenkinsClient.EXPECT().ExecuteScript("script-b").Return("b failed", &jenkinsclient.GroovyScriptExecutionFailed{})
+		jenkinsClient.EXPECT().ExecuteScript("script-b").Return("b done", nil)
+		jenkinsClient.EXPECT().ExecuteScript("script-c").Return("c done", nil)
+		var progress []Progress
+		groovyClient := New(jenkinsClient, fakeClient, jenkins, configurationType, customization).
+			WithProgress(func(p Progress) { progress = append(progress, p) })
+
+		// when
+		for i := 0; i < 4; i++ {
+			_, _ = groovyClient.Ensure(allGroovyScriptsFunc, noUpdateGroovyScript)
+		}
+
+		// then
+		require.Len(t, progress, 4)
+		assert.Equal(t, Progress{ConfigurationType: configurationType, Source: configMapName, Name: "a.groovy", Logs: "a done", Applied: 1, Total: 3}, progress[0])
+		assert.Equal(t, "b.groovy", progress[1].Name)
+		assert.Equal(t, 1, progress[1].Applied)
+		assert.Error(t, progress[1].Err)
+		assert.Equal(t, 2, progress[2].Applied)
+		assert.Equal(t, 3, progress[3].Applied)
+		assert.Equal(t, "c done", progress[3].Logs)
+	})
+	t.Run("circular dependencies", func(t *testing.T) {
+		// given
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+		fakeClient := newFakeClient(t, jenkins)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+		groovyClient := New(jenkinsClient, fakeClient, jenkins, configurationType, customization).
+			Parallel(2, map[string][]string{"a.groovy": {"b.groovy"}, "b.groovy": {"c.groovy"}, "c.groovy": {"a.groovy"}})
+
+		// when
+		_, err := groovyClient.Ensure(allGroovyScriptsFunc, noUpdateGroovyScript)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "circular dependencies")
+	})
+	t.Run("prune results of removed scripts", func(t *testing.T) {
+		// given
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+		jenkins.Status.GroovyScriptResults = []v1alpha2.GroovyScriptResult{
+			{ConfigurationType: configurationType, Source: configMapName, Name: "removed.groovy"},
+			{ConfigurationType: configurationType, Source: "removed-config-map", Name: "a.groovy"},
+			{ConfigurationType: configurationType, Source: ConfigMapsSource, Name: "synchronizing-configmaps.groovy"},
+			{ConfigurationType: "other-conf-type", Source: "other", Name: "other.groovy"},
+		}
+		fakeClient := newFakeClient(t, jenkins)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(gomock.Any()).Return("", nil).Times(3)
+		groovyClient := New(jenkinsClient, fakeClient, jenkins, configurationType, customization).Parallel(3, nil)
+
+		// when
+		_, err := groovyClient.Ensure(allGroovyScriptsFunc, noUpdateGroovyScript)
+
+		// then
+		require.NoError(t, err)
+		err = fakeClient.Get(ctx, types.NamespacedName{Name: jenkins.Name, Namespace: jenkins.Namespace}, jenkins)
+		require.NoError(t, err)
+		var results []string
+		for _, result := range jenkins.Status.GroovyScriptResults {
+			results = append(results, result.Source+"/"+result.Name)
+		}
+		assert.Equal(t, []string{
+			ConfigMapsSource + "/synchronizing-configmaps.groovy",
+			"other/other.groovy",
+			configMapName + "/a.groovy",
+			configMapName + "/b.groovy",
+			configMapName + "/c.groovy",
+		}, results)
+	})
+}
+
 func TestGroovy_isGroovyScriptAlreadyApplied(t *testing.T) {
 	log.SetupLogger(true)
 	emptyCustomization := v1alpha2.Customization{}
@@ -663,6 +924,22 @@ func TestGroovy_isGroovyScriptAlreadyApplied(t *testing.T) {
 	})
 }
 
+func TestGroovy_IsChanged(t *testing.T) {
+	log.SetupLogger(true)
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+	fakeClient := fake.NewClientBuilder().WithObjects(jenkins).Build()
+	groovyClient := New(nil, fakeClient, jenkins, configurationType, v1alpha2.Customization{})
+
+	assert.False(t, groovyClient.IsChanged(ConfigMapsSource, "configurations", "hash"))
+
+	require.NoError(t, groovyClient.SetApplied(ConfigMapsSource, "configurations", "hash"))
+
+	assert.False(t, groovyClient.IsChanged(ConfigMapsSource, "configurations", "hash"))
+	assert.True(t, groovyClient.IsChanged(ConfigMapsSource, "configurations", "new-hash"))
+	assert.False(t, groovyClient.IsChanged(ConfigMapsSource, "other", "new-hash"))
+}
+
 func TestAddSecretsLoaderToGroovyScript(t *testing.T) {
 	secretsPath := "/var/jenkins/groovy-scripts-secrets"
 	secretsLoader := fmt.Sprintf(secretsLoaderGroovyScriptFmt, secretsPath)
@@ -703,3 +980,14 @@ import com.foo.bar2`
 		assert.Equal(t, imports+"\n\n"+secretsLoader+"\n\n\n"+tail, got)
 	})
 }
+
+func TestTailOutput(t *testing.T) {
+	assert.Equal(t, "short", TailOutput("short\n"))
+
+	line := strings.Repeat("x", 100)
+	output := TailOutput(strings.Repeat(line+"\n", 10) + "last")
+
+	assert.True(t, strings.HasSuffix(output, "\nlast"))
+	assert.True(t, strings.HasPrefix(output, line))
+	assert.LessOrEqual(t, len(output), MaxOutputLength)
+}
@@ -0,0 +1,105 @@
+package groovy
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"text/template"
+
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TemplateData is the data available in templated groovy scripts and Configuration as Code files
+type TemplateData struct {
+	// Namespace is the namespace of Jenkins CR
+	Namespace string
+	// JenkinsName is the name of Jenkins CR
+	JenkinsName string
+	// HTTPServiceName is the name of Jenkins HTTP service
+	HTTPServiceName string
+	// SlaveServiceName is the name of Jenkins slave service
+	SlaveServiceName string
+	// Values are the key/values of ConfigMap referenced by spec.values
+	Values map[string]string
+}
+
+// WithClusterDomain sets the Kubernetes cluster domain used by fqdn template function
+func (g *Groovy) WithClusterDomain(kubernetesClusterDomain string) *Groovy {
+	g.kubernetesClusterDomain = kubernetesClusterDomain
+	return g
+}
+
+// renderTemplate resolves Go template placeholders in the script when templating is enabled in customization
+func (g *Groovy) renderTemplate(source, name, script string) (string, error) {
+	if !g.customization.Templating {
+		return script, nil
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(g.templateFuncs()).Parse(script)
+	if err != nil {
+		return "", errors.Wrapf(err, "%s ConfigMap '%s' name '%s' invalid template", g.configurationType, source, name)
+	}
+	values, err := configuration.GetValues(g.k8sClient, g.jenkins)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	data := TemplateData{
+		Namespace:        g.jenkins.Namespace,
+		JenkinsName:      g.jenkins.Name,
+		HTTPServiceName:  resources.GetJenkinsHTTPServiceName(g.jenkins),
+		SlaveServiceName: resources.GetJenkinsSlavesServiceName(g.jenkins),
+		Values:           values,
+	}
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, data); err != nil {
+		return "", errors.Wrapf(err, "%s ConfigMap '%s' name '%s' failed to render template", g.configurationType, source, name)
+	}
+	return output.String(), nil
+}
+
+func (g *Groovy) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"fqdn": func(service string) (string, error) {
+			return resources.GetServiceFQDN(service, g.jenkins.Namespace, g.kubernetesClusterDomain)
+		},
+		"secret": func(secretName, key string) (string, error) {
+			if !g.isTemplateSecret(secretName) {
+				return "", errors.Errorf("secret '%s' can't be read, only the secrets referenced by spec.groovyScripts.secret and spec.configurationAsCode.secret are available", secretName)
+			}
+			secret := &corev1.Secret{}
+			err := g.k8sClient.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: g.jenkins.Namespace}, secret)
+			if err != nil {
+				return "", errors.WithStack(err)
+			}
+			value, found := secret.Data[key]
+			if !found {
+				return "", errors.Errorf("secret '%s' has no key '%s'", secretName, key)
+			}
+			return string(value), nil
+		},
+		"b64": func(value string) string {
+			return base64.StdEncoding.EncodeToString([]byte(value))
+		},
+		"b64dec": func(value string) (string, error) {
+			decoded, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return "", errors.WithStack(err)
+			}
+			return string(decoded), nil
+		},
+	}
+}
+
+// isTemplateSecret returns true if the secret is referenced by spec.groovyScripts.secret or
+// spec.configurationAsCode.secret, the templates can't read other secrets in the namespace of Jenkins CR
+func (g *Groovy) isTemplateSecret(secretName string) bool {
+	if len(secretName) == 0 {
+		return false
+	}
+	return secretName == g.jenkins.Spec.GroovyScripts.Secret.Name || secretName == g.jenkins.Spec.ConfigurationAsCode.Secret.Name
+}
@@ -0,0 +1,100 @@
+package groovy
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGroovy_renderTemplate(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "ci"},
+		Spec: v1alpha2.JenkinsSpec{
+			ConfigurationAsCode: v1alpha2.ConfigurationAsCode{
+				Customization: v1alpha2.Customization{Secret: v1alpha2.SecretRef{Name: "credentials"}},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "ci"},
+		Data:       map[string][]byte{"token": []byte("s3cr3t")},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	templating := v1alpha2.Customization{Templating: true}
+
+	t.Run("templating disabled", func(t *testing.T) {
+		groovyClient := New(nil, fakeClient, jenkins, configurationType, v1alpha2.Customization{})
+
+		script, err := groovyClient.renderTemplate("config-map", "script.groovy", "println '{{ .Namespace }}'")
+
+		require.NoError(t, err)
+		assert.Equal(t, "println '{{ .Namespace }}'", script)
+	})
+	t.Run("data and functions", func(t *testing.T) {
+		groovyClient := New(nil, fakeClient, jenkins, configurationType, templating).WithClusterDomain("cluster.local")
+
+		script, err := groovyClient.renderTemplate("config-map", "jenkins.yaml",
+			`{{ .JenkinsName }}/{{ .Namespace }} {{ fqdn .HTTPServiceName }} {{ secret "credentials" "token" }} {{ b64 "user:pass" }} {{ b64dec "dXNlcg==" }}`)
+
+		require.NoError(t, err)
+		assert.Equal(t, "jenkins/ci jenkins-operator-http-jenkins.ci.svc.cluster.local s3cr3t dXNlcjpwYXNz user", script)
+	})
+	t.Run("missing secret key", func(t *testing.T) {
+		groovyClient := New(nil, fakeClient, jenkins, configurationType, templating)
+
+		_, err := groovyClient.renderTemplate("config-map", "script.groovy", `{{ secret "credentials" "password" }}`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "secret 'credentials' has no key 'password'")
+	})
+	t.Run("secret not referenced by Jenkins CR", func(t *testing.T) {
+		other := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ci"},
+			Data:       map[string][]byte{"token": []byte("s3cr3t")},
+		}
+		groovyClient := New(nil, fake.NewClientBuilder().WithObjects(secret, other).Build(), jenkins, configurationType, templating)
+
+		_, err := groovyClient.renderTemplate("config-map", "script.groovy", `{{ secret "other" "token" }}`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "secret 'other' can't be read")
+	})
+	t.Run("values", func(t *testing.T) {
+		values := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "values", Namespace: "ci"},
+			Data:       map[string]string{"environment": "staging"},
+		}
+		jenkins := jenkins.DeepCopy()
+		jenkins.Spec.Values = &v1alpha2.ConfigMapRef{Name: "values"}
+		groovyClient := New(nil, fake.NewClientBuilder().WithObjects(values).Build(), jenkins, configurationType, templating)
+
+		script, err := groovyClient.renderTemplate("config-map", "script.groovy", `println '{{ .Values.environment }}'`)
+
+		require.NoError(t, err)
+		assert.Equal(t, "println 'staging'", script)
+	})
+	t.Run("missing values ConfigMap", func(t *testing.T) {
+		jenkins := jenkins.DeepCopy()
+		jenkins.Spec.Values = &v1alpha2.ConfigMapRef{Name: "values"}
+		groovyClient := New(nil, fakeClient, jenkins, configurationType, templating)
+
+		_, err := groovyClient.renderTemplate("config-map", "script.groovy", `println '{{ .Values.environment }}'`)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get values ConfigMap 'values'")
+	})
+	t.Run("invalid template", func(t *testing.T) {
+		groovyClient := New(nil, fakeClient, jenkins, configurationType, templating)
+
+		_, err := groovyClient.renderTemplate("config-map", "script.groovy", `{{ .Namespace `)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ConfigMap 'config-map' name 'script.groovy' invalid template")
+	})
+}
@@ -0,0 +1,77 @@
+// Package inheritance merges spec of the Jenkins CR referenced by spec.inheritFrom into the inheriting Jenkins CR.
+package inheritance
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	stackerr "github.com/pkg/errors"
+)
+
+// InheritedSpecHashAnnotation holds the hash of the parent spec which the Jenkins CR is reconciled with
+const InheritedSpecHashAnnotation = "jenkins.io/inherited-spec-hash"
+
+// Inherit deep-merges the parent spec as defaults into the Jenkins CR spec in memory, the merged spec is never stored
+// in the Jenkins CR. spec holds the values set in the Jenkins CR as stored in the API server, they override
+// the inherited ones including zero values, maps are merged recursively and lists are replaced as a whole.
+// It returns the hash of the inherited parent spec.
+func Inherit(jenkins *v1alpha2.Jenkins, spec map[string]interface{}, parent *v1alpha2.Jenkins) (string, error) {
+	parentSpec := parent.Spec.DeepCopy()
+	parentSpec.InheritFrom = ""
+	parentSpec.Restore.RecoveryOnce = 0
+	parentValues, err := toMap(parentSpec)
+	if err != nil {
+		return "", err
+	}
+	inheritedSpec, err := json.Marshal(parentValues)
+	if err != nil {
+		return "", stackerr.WithStack(err)
+	}
+
+	merged, err := json.Marshal(merge(parentValues, spec))
+	if err != nil {
+		return "", stackerr.WithStack(err)
+	}
+	mergedSpec := v1alpha2.JenkinsSpec{}
+	if err := json.Unmarshal(merged, &mergedSpec); err != nil {
+		return "", stackerr.WithStack(err)
+	}
+	jenkins.Spec = mergedSpec
+	return fmt.Sprintf("%x", sha256.Sum256(inheritedSpec)), nil
+}
+
+func toMap(spec interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	return values, nil
+}
+
+// merge returns defaults overridden by the values, only null values are treated as not set
+func merge(defaults, values map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, value := range defaults {
+		result[key] = value
+	}
+	for key, value := range values {
+		if value == nil {
+			continue
+		}
+		valueMap, valueIsMap := value.(map[string]interface{})
+		defaultMap, defaultIsMap := result[key].(map[string]interface{})
+		if valueIsMap && defaultIsMap {
+			result[key] = merge(defaultMap, valueMap)
+		} else {
+			result[key] = value
+		}
+	}
+	return result
+}
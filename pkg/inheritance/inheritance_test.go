@@ -0,0 +1,150 @@
+package inheritance
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func templateJenkins() *v1alpha2.Jenkins {
+	return &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "template"},
+		Spec: v1alpha2.JenkinsSpec{
+			Master: v1alpha2.JenkinsMaster{
+				Containers: []v1alpha2.Container{{Name: "jenkins-master", Image: "jenkins/jenkins:2.319.1-lts"}},
+				Plugins:    []v1alpha2.Plugin{{Name: "git", Version: "4.10.1"}},
+				NodeSelector: map[string]string{
+					"pool": "jenkins",
+				},
+			},
+			JenkinsAPISettings: v1alpha2.JenkinsAPISettings{AuthorizationStrategy: v1alpha2.CreateUserAuthorizationStrategy},
+			Restore:            v1alpha2.Restore{RecoveryOnce: 3},
+		},
+	}
+}
+
+// storedSpec returns the spec values of the Jenkins CR as stored in the API server when only the non zero values
+// have been set by the user
+func storedSpec(t *testing.T, spec v1alpha2.JenkinsSpec) map[string]interface{} {
+	values, err := toMap(spec)
+	require.NoError(t, err)
+	return withoutZeroValues(values)
+}
+
+func withoutZeroValues(values map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case map[string]interface{}:
+			if v = withoutZeroValues(v); len(v) > 0 {
+				result[key] = v
+			}
+		case []interface{}:
+			if len(v) > 0 {
+				result[key] = v
+			}
+		default:
+			if !reflect.ValueOf(v).IsZero() {
+				result[key] = v
+			}
+		}
+	}
+	return result
+}
+
+func TestInherit(t *testing.T) {
+	t.Run("inherits defaults", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: v1alpha2.JenkinsSpec{
+				InheritFrom: "template",
+				Master: v1alpha2.JenkinsMaster{
+					NodeSelector: map[string]string{"team": "a"},
+				},
+				SeedJobs: []v1alpha2.SeedJob{{ID: "team-a"}},
+			},
+		}
+
+		hash, err := Inherit(jenkins, storedSpec(t, jenkins.Spec), templateJenkins())
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, hash)
+		assert.Equal(t, "template", jenkins.Spec.InheritFrom)
+		assert.Equal(t, "jenkins/jenkins:2.319.1-lts", jenkins.Spec.Master.Containers[0].Image)
+		assert.Equal(t, []v1alpha2.Plugin{{Name: "git", Version: "4.10.1"}}, jenkins.Spec.Master.Plugins)
+		assert.Equal(t, map[string]string{"pool": "jenkins", "team": "a"}, jenkins.Spec.Master.NodeSelector)
+		assert.Equal(t, v1alpha2.CreateUserAuthorizationStrategy, jenkins.Spec.JenkinsAPISettings.AuthorizationStrategy)
+		assert.Equal(t, []v1alpha2.SeedJob{{ID: "team-a"}}, jenkins.Spec.SeedJobs)
+		assert.Equal(t, uint64(0), jenkins.Spec.Restore.RecoveryOnce)
+		assert.Empty(t, jenkins.Annotations)
+
+		sameHash, err := Inherit(jenkins, storedSpec(t, jenkins.Spec), templateJenkins())
+
+		require.NoError(t, err)
+		assert.Equal(t, hash, sameHash)
+	})
+	t.Run("overrides inherited values", func(t *testing.T) {
+		jenkins := &v1alpha2.Jenkins{
+			Spec: v1alpha2.JenkinsSpec{
+				InheritFrom: "template",
+				Master: v1alpha2.JenkinsMaster{
+					Plugins: []v1alpha2.Plugin{{Name: "git", Version: "4.11.0"}},
+				},
+			},
+		}
+
+		_, err := Inherit(jenkins, storedSpec(t, jenkins.Spec), templateJenkins())
+
+		require.NoError(t, err)
+		assert.Equal(t, []v1alpha2.Plugin{{Name: "git", Version: "4.11.0"}}, jenkins.Spec.Master.Plugins)
+		assert.Equal(t, "jenkins/jenkins:2.319.1-lts", jenkins.Spec.Master.Containers[0].Image)
+	})
+	t.Run("overrides inherited values with zero values", func(t *testing.T) {
+		parent := templateJenkins()
+		parent.Spec.Master.DisableCSRFProtection = true
+		jenkins := &v1alpha2.Jenkins{Spec: v1alpha2.JenkinsSpec{InheritFrom: "template"}}
+		spec := map[string]interface{}{
+			"inheritFrom": "template",
+			"master": map[string]interface{}{
+				"disableCSRFProtection": false,
+				"plugins":               []interface{}{},
+				"nodeSelector":          map[string]interface{}{},
+			},
+		}
+
+		_, err := Inherit(jenkins, spec, parent)
+
+		require.NoError(t, err)
+		assert.False(t, jenkins.Spec.Master.DisableCSRFProtection)
+		assert.Empty(t, jenkins.Spec.Master.Plugins)
+		assert.Equal(t, map[string]string{"pool": "jenkins"}, jenkins.Spec.Master.NodeSelector)
+		assert.Equal(t, "jenkins/jenkins:2.319.1-lts", jenkins.Spec.Master.Containers[0].Image)
+	})
+	t.Run("propagates parent changes", func(t *testing.T) {
+		spec := v1alpha2.JenkinsSpec{
+			InheritFrom: "template",
+			Master: v1alpha2.JenkinsMaster{
+				NodeSelector: map[string]string{"team": "a"},
+			},
+		}
+		jenkins := &v1alpha2.Jenkins{Spec: spec}
+		hash, err := Inherit(jenkins, storedSpec(t, spec), templateJenkins())
+		require.NoError(t, err)
+		parent := templateJenkins()
+		parent.Spec.Master.Plugins = []v1alpha2.Plugin{{Name: "git", Version: "4.11.0"}}
+		delete(parent.Spec.Master.NodeSelector, "pool")
+
+		changedHash, err := Inherit(jenkins, storedSpec(t, spec), parent)
+
+		require.NoError(t, err)
+		assert.NotEqual(t, hash, changedHash)
+		assert.Equal(t, []v1alpha2.Plugin{{Name: "git", Version: "4.11.0"}}, jenkins.Spec.Master.Plugins)
+		assert.Equal(t, map[string]string{"team": "a"}, jenkins.Spec.Master.NodeSelector)
+	})
+}
@@ -2,6 +2,7 @@ package log
 
 import (
 	"log"
+	"sync/atomic"
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
@@ -12,8 +13,11 @@ import (
 // Log represents global logger.
 var Log = logf.Log.WithName("controller-jenkins")
 
-// Debug indicates that debug level is set.
-var Debug bool
+// debugEnabled indicates that debug level is set, it's changed at runtime by SetDebug while reconcile workers read it.
+var debugEnabled atomic.Bool
+
+// Level is the global logger level, it can be changed at runtime by SetDebug.
+var Level = zap.NewAtomicLevelAt(zap.InfoLevel)
 
 const (
 	// VWarn defines warning log level
@@ -26,11 +30,8 @@ func zapLogger(debug bool) logr.Logger {
 	var zapLog *zap.Logger
 	var err error
 	zapLogCfg := zap.NewDevelopmentConfig()
-	if debug {
-		zapLogCfg.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
-	} else {
-		zapLogCfg.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
-	}
+	SetDebug(debug)
+	zapLogCfg.Level = Level
 	zapLog, err = zapLogCfg.Build(zap.AddStacktrace(zap.DPanicLevel), zap.AddCallerSkip(1))
 	// who watches the watchmen?
 	fatalIfErr(err, log.Fatalf)
@@ -43,9 +44,23 @@ func fatalIfErr(err error, f func(format string, v ...interface{})) {
 	}
 }
 
+// IsDebug returns true if debug level is set.
+func IsDebug() bool {
+	return debugEnabled.Load()
+}
+
+// SetDebug toggles debug level of the global logger.
+func SetDebug(enabled bool) {
+	debugEnabled.Store(enabled)
+	if enabled {
+		Level.SetLevel(zap.DebugLevel)
+	} else {
+		Level.SetLevel(zap.InfoLevel)
+	}
+}
+
 // SetupLogger setups global logger.
 func SetupLogger(debug bool) {
-	Debug = debug
 	logf.SetLogger(zapLogger(debug))
 	Log = logf.Log.WithName("controller-jenkins")
 }
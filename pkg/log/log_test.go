@@ -0,0 +1,22 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestSetDebug(t *testing.T) {
+	defer SetDebug(false)
+
+	SetDebug(true)
+
+	assert.True(t, IsDebug())
+	assert.Equal(t, zap.DebugLevel, Level.Level())
+
+	SetDebug(false)
+
+	assert.False(t, IsDebug())
+	assert.Equal(t, zap.InfoLevel, Level.Level())
+}
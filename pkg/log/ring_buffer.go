@@ -0,0 +1,50 @@
+package log
+
+import (
+	"strings"
+	"sync"
+)
+
+// Recent keeps the latest operator log entries, they are included in support bundles.
+var Recent = NewRingBuffer(5000)
+
+// RingBuffer is an io.Writer keeping the last entries written to it. The logger writes every entry, including
+// multiline messages, in a single call.
+type RingBuffer struct {
+	mutex   sync.Mutex
+	entries []string
+	next    int
+	full    bool
+}
+
+// NewRingBuffer creates the buffer keeping up to capacity entries.
+func NewRingBuffer(capacity int) *RingBuffer {
+	return &RingBuffer{entries: make([]string, capacity)}
+}
+
+// Write stores p as a single entry, overwriting the oldest one when the buffer is full.
+func (b *RingBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if len(b.entries) == 0 {
+		return len(p), nil
+	}
+	b.entries[b.next] = strings.TrimSuffix(string(p), "\n")
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+	return len(p), nil
+}
+
+// Entries returns the stored entries from the oldest to the newest.
+func (b *RingBuffer) Entries() []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.full {
+		return append([]string{}, b.entries[:b.next]...)
+	}
+	return append(append([]string{}, b.entries[b.next:]...), b.entries[:b.next]...)
+}
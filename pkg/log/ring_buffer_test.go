@@ -0,0 +1,27 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingBuffer(t *testing.T) {
+	t.Run("keeps entries in order", func(t *testing.T) {
+		buffer := NewRingBuffer(3)
+
+		_, _ = buffer.Write([]byte("first\n"))
+		_, _ = buffer.Write([]byte("second\nwith logs\n"))
+
+		assert.Equal(t, []string{"first", "second\nwith logs"}, buffer.Entries())
+	})
+	t.Run("drops the oldest entries", func(t *testing.T) {
+		buffer := NewRingBuffer(3)
+
+		for _, entry := range []string{"1", "2", "3", "4", "5"} {
+			_, _ = buffer.Write([]byte(entry))
+		}
+
+		assert.Equal(t, []string{"3", "4", "5"}, buffer.Entries())
+	})
+}
@@ -0,0 +1,175 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	stackerr "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DiskUsage defines utilization of a volume.
+type DiskUsage struct {
+	// UsedBytes is the number of used bytes
+	UsedBytes int64
+	// SizeBytes is the number of bytes available to Jenkins including the used ones
+	SizeBytes int64
+	// Percentage is the used space in percent
+	Percentage int
+}
+
+// DiskUsageMonitor periodically checks utilization of Jenkins home volume by executing df in the Jenkins master
+// container, it updates the metrics and sends a warning notification when the threshold is exceeded. External Jenkins
+// instances are skipped, the operator doesn't manage their pods.
+type DiskUsageMonitor struct {
+	Client             client.Client
+	ClientSet          kubernetes.Clientset
+	Config             rest.Config
+	NotificationEvents *chan event.Event
+	Interval           time.Duration
+	// ThresholdPercentage is the used space in percent above which the warning notification is sent
+	ThresholdPercentage int
+
+	monitored map[types.NamespacedName]*v1alpha2.Jenkins
+	// percentages are the last observed used space in percent, the notification is sent only when the threshold is
+	// crossed
+	percentages map[types.NamespacedName]int
+}
+
+// Start checks Jenkins home disk usage every interval until the context is done.
+func (m *DiskUsageMonitor) Start(ctx context.Context) error {
+	m.monitored = map[types.NamespacedName]*v1alpha2.Jenkins{}
+	m.percentages = map[types.NamespacedName]int{}
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			m.checkAll(ctx)
+		}
+	}
+}
+
+func (m *DiskUsageMonitor) checkAll(ctx context.Context) {
+	jenkinsList := &v1alpha2.JenkinsList{}
+	if err := m.Client.List(ctx, jenkinsList); err != nil {
+		log.Log.V(log.VWarn).Info(fmt.Sprintf("Failed to list Jenkins CRs for disk usage monitoring: %s", err))
+		return
+	}
+
+	current := map[types.NamespacedName]bool{}
+	for i := range jenkinsList.Items {
+		jenkins := &jenkinsList.Items[i]
+		if jenkins.Spec.ExternalJenkins != nil || jenkins.Status.BaseConfigurationCompletedTime == nil {
+			continue
+		}
+		key := types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name}
+		current[key] = true
+		m.monitored[key] = jenkins
+
+		if err := m.check(jenkins); err != nil {
+			log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace).V(log.VDebug).Info(fmt.Sprintf("Failed to check Jenkins home disk usage: %s", err))
+		}
+	}
+
+	for key, jenkins := range m.monitored {
+		if !current[key] {
+			JenkinsHomeUsedBytes.Delete(jenkinsLabels(jenkins))
+			JenkinsHomeSizeBytes.Delete(jenkinsLabels(jenkins))
+			delete(m.monitored, key)
+			delete(m.percentages, key)
+		}
+	}
+}
+
+func (m *DiskUsageMonitor) check(jenkins *v1alpha2.Jenkins) error {
+	config := configuration.Configuration{
+		Client:    m.Client,
+		ClientSet: m.ClientSet,
+		Config:    &m.Config,
+		Jenkins:   jenkins,
+	}
+	stdout, _, err := config.Exec(resources.GetJenkinsMasterPodName(jenkins), resources.JenkinsMasterContainerName,
+		[]string{"df", "-Pk", resources.GetJenkinsHomePath(jenkins)})
+	if err != nil {
+		return err
+	}
+	usage, err := ParseDiskUsage(stdout.String())
+	if err != nil {
+		return err
+	}
+
+	m.report(jenkins, usage)
+	return nil
+}
+
+// report updates the metrics and sends the warning notification when the used space exceeds the threshold for
+// the first time since the operator started or since it dropped below the threshold
+func (m *DiskUsageMonitor) report(jenkins *v1alpha2.Jenkins, usage *DiskUsage) {
+	labels := jenkinsLabels(jenkins)
+	JenkinsHomeUsedBytes.With(labels).Set(float64(usage.UsedBytes))
+	JenkinsHomeSizeBytes.With(labels).Set(float64(usage.SizeBytes))
+
+	key := types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name}
+	previous, found := m.percentages[key]
+	m.percentages[key] = usage.Percentage
+	if usage.Percentage < m.ThresholdPercentage || (found && previous >= m.ThresholdPercentage) {
+		return
+	}
+
+	message := fmt.Sprintf("Jenkins home volume is %d%% full, threshold is %d%%", usage.Percentage, m.ThresholdPercentage)
+	log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace).V(log.VWarn).Info(message)
+	*m.NotificationEvents <- event.Event{
+		Jenkins: *jenkins,
+		Phase:   event.PhaseBase,
+		Level:   v1alpha2.NotificationLevelWarning,
+		Reason: reason.NewDiskUsageHigh(reason.KubernetesSource, []string{message},
+			message, fmt.Sprintf("Used %d of %d bytes", usage.UsedBytes, usage.SizeBytes)),
+	}
+}
+
+// ParseDiskUsage parses POSIX output of 'df -Pk' command with a single file system.
+func ParseDiskUsage(output string) (*DiskUsage, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return nil, stackerr.Errorf("unexpected df output '%s'", output)
+	}
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 4 {
+		return nil, stackerr.Errorf("unexpected df output '%s'", output)
+	}
+	usedKilobytes, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	availableKilobytes, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+
+	usage := &DiskUsage{
+		UsedBytes: usedKilobytes * 1024,
+		SizeBytes: (usedKilobytes + availableKilobytes) * 1024,
+	}
+	if usage.SizeBytes > 0 {
+		// rounded up like df does
+		usage.Percentage = int((usage.UsedBytes*100 + usage.SizeBytes - 1) / usage.SizeBytes)
+	}
+	return usage, nil
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestParseDiskUsage(t *testing.T) {
+	t.Run("happy", func(t *testing.T) {
+		output := `Filesystem     1024-blocks    Used Available Capacity Mounted on
+/dev/sda1          1000000  901000     99000      91% /var/lib/jenkins
+`
+
+		usage, err := ParseDiskUsage(output)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &DiskUsage{UsedBytes: 901000 * 1024, SizeBytes: 1000000 * 1024, Percentage: 91}, usage)
+	})
+	t.Run("rounds up", func(t *testing.T) {
+		output := `Filesystem     1024-blocks    Used Available Capacity Mounted on
+tmpfs                 1000     101       899      11% /var/lib/jenkins
+`
+
+		usage, err := ParseDiskUsage(output)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 11, usage.Percentage)
+	})
+	t.Run("invalid output", func(t *testing.T) {
+		_, err := ParseDiskUsage("df: /var/lib/jenkins: No such file or directory")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDiskUsageMonitorReport(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+	notificationEvents := make(chan event.Event, 10)
+	monitor := &DiskUsageMonitor{
+		NotificationEvents:  &notificationEvents,
+		ThresholdPercentage: 90,
+		percentages:         map[types.NamespacedName]int{},
+	}
+
+	for _, percentage := range []int{50, 91, 95, 80, 90} {
+		monitor.report(jenkins, &DiskUsage{UsedBytes: int64(percentage), SizeBytes: 100, Percentage: percentage})
+	}
+
+	assert.Len(t, notificationEvents, 2)
+	assert.Equal(t, float64(90), testutil.ToFloat64(JenkinsHomeUsedBytes.With(jenkinsLabels(jenkins))))
+	assert.Equal(t, float64(100), testutil.ToFloat64(JenkinsHomeSizeBytes.With(jenkinsLabels(jenkins))))
+	assert.Empty(t, jenkins.Status)
+}
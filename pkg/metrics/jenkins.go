@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	stackerr "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// JenkinsCollector periodically scrapes queue and executor statistics of Jenkins instances managed by the operator
+// and re-exports them as Prometheus metrics, it's useful when the Jenkins metrics plugin can't be installed.
+type JenkinsCollector struct {
+	Client                       client.Client
+	ClientSet                    kubernetes.Clientset
+	Config                       rest.Config
+	JenkinsAPIConnectionSettings jenkinsclient.JenkinsAPIConnectionSettings
+	KubernetesClusterDomain      string
+	Interval                     time.Duration
+
+	collected map[types.NamespacedName]*v1alpha2.Jenkins
+}
+
+// Start scrapes Jenkins instances every interval until the context is done.
+func (c *JenkinsCollector) Start(ctx context.Context) error {
+	c.collected = map[types.NamespacedName]*v1alpha2.Jenkins{}
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.collectAll(ctx)
+		}
+	}
+}
+
+func (c *JenkinsCollector) collectAll(ctx context.Context) {
+	jenkinsList := &v1alpha2.JenkinsList{}
+	if err := c.Client.List(ctx, jenkinsList); err != nil {
+		log.Log.V(log.VWarn).Info(fmt.Sprintf("Failed to list Jenkins CRs for metrics: %s", err))
+		return
+	}
+
+	current := map[types.NamespacedName]bool{}
+	for i := range jenkinsList.Items {
+		jenkins := &jenkinsList.Items[i]
+		if jenkins.Status.BaseConfigurationCompletedTime == nil {
+			continue
+		}
+		key := types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name}
+		current[key] = true
+		c.collected[key] = jenkins
+
+		config := configuration.Configuration{
+			Client:                       c.Client,
+			ClientSet:                    c.ClientSet,
+			Config:                       &c.Config,
+			Jenkins:                      jenkins,
+			JenkinsAPIConnectionSettings: c.JenkinsAPIConnectionSettings,
+			KubernetesClusterDomain:      c.KubernetesClusterDomain,
+			JenkinsClientOptions:         []jenkinsclient.Option{WithJenkinsAPIRequestDuration(jenkins)},
+		}
+		jenkinsClient, err := config.GetJenkinsClient()
+		if err == nil {
+			err = CollectJenkinsMetrics(jenkinsClient, jenkins)
+		}
+		if err != nil {
+			JenkinsUp.With(jenkinsLabels(jenkins)).Set(0)
+			log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace).V(log.VDebug).Info(fmt.Sprintf("Failed to scrape Jenkins metrics: %s", err))
+		}
+	}
+
+	for key, jenkins := range c.collected {
+		if !current[key] {
+			DeleteJenkinsMetrics(jenkins)
+			delete(c.collected, key)
+		}
+	}
+}
+
+// CollectJenkinsMetrics scrapes queue and executor statistics from Jenkins API and updates metrics of the given CR.
+func CollectJenkinsMetrics(jenkinsClient jenkinsclient.Jenkins, jenkins *v1alpha2.Jenkins) error {
+	queue, err := jenkinsClient.GetQueue()
+	if err != nil {
+		return stackerr.WithStack(err)
+	}
+	queueLength := 0
+	if queue != nil && queue.Raw != nil {
+		queueLength = len(queue.Tasks())
+	}
+
+	nodes, err := jenkinsClient.GetAllNodes()
+	if err != nil {
+		return stackerr.WithStack(err)
+	}
+	var executors, busyExecutors, nodesOnline int64
+	for _, node := range nodes {
+		executors += node.Raw.NumExecutors
+		for _, executor := range node.Raw.Executors {
+			if executor.CurrentExecutable.URL != "" {
+				busyExecutors++
+			}
+		}
+		if !node.Raw.Offline {
+			nodesOnline++
+		}
+	}
+
+	labels := jenkinsLabels(jenkins)
+	JenkinsQueueLength.With(labels).Set(float64(queueLength))
+	JenkinsExecutors.With(labels).Set(float64(executors))
+	JenkinsBusyExecutors.With(labels).Set(float64(busyExecutors))
+	JenkinsNodes.With(labels).Set(float64(len(nodes)))
+	JenkinsNodesOnline.With(labels).Set(float64(nodesOnline))
+	JenkinsUp.With(labels).Set(1)
+	return nil
+}
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/client"
+
+	"github.com/bndr/gojenkins"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newNode(t *testing.T, raw string) *gojenkins.Node {
+	node := &gojenkins.Node{}
+	require.NoError(t, json.Unmarshal([]byte(raw), &node.Raw))
+	return node
+}
+
+func TestCollectJenkinsMetrics(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"}}
+	labels := jenkinsLabels(jenkins)
+
+	t.Run("queue and executors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		queue := &gojenkins.Queue{}
+		require.NoError(t, json.Unmarshal([]byte(`{"items":[{"id":1},{"id":2},{"id":3}]}`), &queue.Raw))
+		nodes := []*gojenkins.Node{
+			newNode(t, `{"displayName":"master","numExecutors":2,"executors":[{"currentExecutable":{"url":"http://jenkins/job/a/1/"}},{"currentExecutable":null}]}`),
+			newNode(t, `{"displayName":"agent-1","numExecutors":3,"executors":[{"currentExecutable":{"url":"http://jenkins/job/b/1/"}},{"currentExecutable":{"url":"http://jenkins/job/c/1/"}}]}`),
+			newNode(t, `{"displayName":"agent-2","numExecutors":1,"offline":true}`),
+		}
+		jenkinsClient.EXPECT().GetQueue().Return(queue, nil)
+		jenkinsClient.EXPECT().GetAllNodes().Return(nodes, nil)
+
+		err := CollectJenkinsMetrics(jenkinsClient, jenkins)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(1), testutil.ToFloat64(JenkinsUp.With(labels)))
+		assert.Equal(t, float64(3), testutil.ToFloat64(JenkinsQueueLength.With(labels)))
+		assert.Equal(t, float64(6), testutil.ToFloat64(JenkinsExecutors.With(labels)))
+		assert.Equal(t, float64(3), testutil.ToFloat64(JenkinsBusyExecutors.With(labels)))
+		assert.Equal(t, float64(3), testutil.ToFloat64(JenkinsNodes.With(labels)))
+		assert.Equal(t, float64(2), testutil.ToFloat64(JenkinsNodesOnline.With(labels)))
+	})
+	t.Run("Jenkins API error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().GetQueue().Return(nil, errors.New("connection refused"))
+
+		err := CollectJenkinsMetrics(jenkinsClient, jenkins)
+
+		assert.Error(t, err)
+	})
+	t.Run("delete metrics", func(t *testing.T) {
+		JenkinsQueueLength.With(labels).Set(1)
+
+		DeleteJenkinsMetrics(jenkins)
+
+		assert.Equal(t, 0, testutil.CollectAndCount(JenkinsQueueLength))
+	})
+}
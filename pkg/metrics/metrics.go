@@ -0,0 +1,141 @@
+// Package metrics exports Prometheus metrics about Jenkins instances managed by the operator.
+package metrics
+
+import (
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespace = "jenkins_operator"
+
+	namespaceLabel = "namespace"
+	nameLabel      = "name"
+)
+
+var (
+	// JenkinsUp tells if the last Jenkins metrics scrape was successful
+	JenkinsUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "jenkins_up",
+		Help:      "Whether the last scrape of Jenkins API was successful.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsQueueLength is the number of items waiting in Jenkins build queue
+	JenkinsQueueLength = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "jenkins_queue_length",
+		Help:      "Number of items waiting in Jenkins build queue.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsExecutors is the number of Jenkins executors on all nodes
+	JenkinsExecutors = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "jenkins_executors",
+		Help:      "Number of Jenkins executors on all nodes.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsBusyExecutors is the number of Jenkins executors running a build
+	JenkinsBusyExecutors = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "jenkins_busy_executors",
+		Help:      "Number of Jenkins executors running a build.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsNodes is the number of Jenkins nodes
+	JenkinsNodes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "jenkins_nodes",
+		Help:      "Number of Jenkins nodes including the master.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsNodesOnline is the number of online Jenkins nodes
+	JenkinsNodesOnline = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "jenkins_nodes_online",
+		Help:      "Number of online Jenkins nodes including the master.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsHomeUsedBytes is the number of used bytes of Jenkins home volume
+	JenkinsHomeUsedBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "jenkins_home_used_bytes",
+		Help:      "Number of used bytes of Jenkins home volume.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsHomeSizeBytes is the size of Jenkins home volume in bytes
+	JenkinsHomeSizeBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "jenkins_home_size_bytes",
+		Help:      "Size of Jenkins home volume in bytes.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsRestoreRehearsalSucceeded tells if the latest restore rehearsal was successful
+	JenkinsRestoreRehearsalSucceeded = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "jenkins_restore_rehearsal_succeeded",
+		Help:      "Whether the latest restore rehearsal of Jenkins backup was successful.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsRestoreRehearsalTimestamp is the completion time of the latest restore rehearsal
+	JenkinsRestoreRehearsalTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "jenkins_restore_rehearsal_timestamp_seconds",
+		Help:      "Completion time of the latest restore rehearsal of Jenkins backup in seconds since epoch.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsUnmanagedCredentials is the number of credentials present in Jenkins which are not managed by the operator
+	JenkinsUnmanagedCredentials = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "jenkins_unmanaged_credentials",
+		Help:      "Number of credentials present in Jenkins which are not managed by the operator.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsMissingCredentials is the number of credentials managed by the operator which are not present in Jenkins
+	JenkinsMissingCredentials = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "jenkins_missing_credentials",
+		Help:      "Number of credentials managed by the operator which are not present in Jenkins.",
+	}, []string{namespaceLabel, nameLabel})
+)
+
+var jenkinsGauges = []*prometheus.GaugeVec{
+	JenkinsUp,
+	JenkinsQueueLength,
+	JenkinsExecutors,
+	JenkinsBusyExecutors,
+	JenkinsNodes,
+	JenkinsNodesOnline,
+	JenkinsHomeUsedBytes,
+	JenkinsHomeSizeBytes,
+	JenkinsRestoreRehearsalSucceeded,
+	JenkinsRestoreRehearsalTimestamp,
+	JenkinsUnmanagedCredentials,
+	JenkinsMissingCredentials,
+}
+
+func init() {
+	for _, gauge := range jenkinsGauges {
+		metrics.Registry.MustRegister(gauge)
+	}
+}
+
+// SetRestoreRehearsalMetrics updates restore rehearsal metrics of the given CR.
+func SetRestoreRehearsalMetrics(jenkins *v1alpha2.Jenkins, succeeded bool, completionTime time.Time) {
+	value := 0.0
+	if succeeded {
+		value = 1
+	}
+	JenkinsRestoreRehearsalSucceeded.With(jenkinsLabels(jenkins)).Set(value)
+	JenkinsRestoreRehearsalTimestamp.With(jenkinsLabels(jenkins)).Set(float64(completionTime.Unix()))
+}
+
+// SetCredentialsUsageMetrics updates the numbers of unmanaged and missing credentials of the given CR.
+func SetCredentialsUsageMetrics(jenkins *v1alpha2.Jenkins, unmanaged, missing int) {
+	JenkinsUnmanagedCredentials.With(jenkinsLabels(jenkins)).Set(float64(unmanaged))
+	JenkinsMissingCredentials.With(jenkinsLabels(jenkins)).Set(float64(missing))
+}
+
+func jenkinsLabels(jenkins *v1alpha2.Jenkins) prometheus.Labels {
+	return prometheus.Labels{namespaceLabel: jenkins.Namespace, nameLabel: jenkins.Name}
+}
+
+// DeleteJenkinsMetrics removes all Jenkins metrics of the given CR.
+func DeleteJenkinsMetrics(jenkins *v1alpha2.Jenkins) {
+	for _, gauge := range jenkinsGauges {
+		gauge.Delete(jenkinsLabels(jenkins))
+	}
+}
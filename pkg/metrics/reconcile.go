@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const phaseLabel = "phase"
+
+var (
+	// JenkinsReconcileDuration is the duration of reconcile loops of Jenkins CR
+	JenkinsReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of reconcile loops of Jenkins CR in seconds.",
+		Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsReconcileFailures is the number of failed reconcile loops of Jenkins CR by the configuration phase
+	JenkinsReconcileFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_failures_total",
+		Help:      "Number of reconcile loops of Jenkins CR failed in base or user configuration phase.",
+	}, []string{namespaceLabel, nameLabel, phaseLabel})
+	// JenkinsPluginMismatches is the number of plugins which don't match the spec found by the latest plugins verification
+	JenkinsPluginMismatches = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "plugin_mismatches",
+		Help:      "Number of missing, incompatible or not locked plugins found by the latest plugins verification.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsSeedJobValidationErrors is the number of errors found by the latest seed jobs validation
+	JenkinsSeedJobValidationErrors = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "seed_job_validation_errors",
+		Help:      "Number of errors found by the latest validation of seed jobs.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsAPIRequestDuration is the latency of requests sent by the operator to Jenkins API
+	JenkinsAPIRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "jenkins_api_request_duration_seconds",
+		Help:      "Latency of requests sent by the operator to Jenkins API in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{namespaceLabel, nameLabel})
+)
+
+var reconcilePhases = []event.Phase{event.PhaseBase, event.PhaseUser}
+
+func init() {
+	metrics.Registry.MustRegister(JenkinsReconcileDuration, JenkinsReconcileFailures, JenkinsPluginMismatches,
+		JenkinsSeedJobValidationErrors, JenkinsAPIRequestDuration)
+}
+
+// ObserveReconcileDuration records the duration of the reconcile loop of the given CR.
+func ObserveReconcileDuration(jenkins *v1alpha2.Jenkins, duration time.Duration) {
+	JenkinsReconcileDuration.With(jenkinsLabels(jenkins)).Observe(duration.Seconds())
+}
+
+// IncReconcileFailures counts the failed reconcile loop of the given CR in the configuration phase.
+func IncReconcileFailures(jenkins *v1alpha2.Jenkins, phase event.Phase) {
+	JenkinsReconcileFailures.With(phaseLabels(jenkins, phase)).Inc()
+}
+
+// SetPluginMismatches updates the number of plugins of the given CR which don't match the spec.
+func SetPluginMismatches(jenkins *v1alpha2.Jenkins, count int) {
+	JenkinsPluginMismatches.With(jenkinsLabels(jenkins)).Set(float64(count))
+}
+
+// SetSeedJobValidationErrors updates the number of seed jobs validation errors of the given CR.
+func SetSeedJobValidationErrors(jenkins *v1alpha2.Jenkins, count int) {
+	JenkinsSeedJobValidationErrors.With(jenkinsLabels(jenkins)).Set(float64(count))
+}
+
+// WithJenkinsAPIRequestDuration returns Jenkins API client option which records the latency of all requests
+// to Jenkins API of the given CR.
+func WithJenkinsAPIRequestDuration(jenkins *v1alpha2.Jenkins) jenkinsclient.Option {
+	observer := JenkinsAPIRequestDuration.With(jenkinsLabels(jenkins))
+	return func(httpClient *http.Client) {
+		httpClient.Transport = &observeDuration{observer: observer, rt: httpClient.Transport}
+	}
+}
+
+// DeleteReconcileMetrics removes reconcile and Jenkins API metrics of the given CR.
+func DeleteReconcileMetrics(jenkins *v1alpha2.Jenkins) {
+	JenkinsReconcileDuration.Delete(jenkinsLabels(jenkins))
+	for _, phase := range reconcilePhases {
+		JenkinsReconcileFailures.Delete(phaseLabels(jenkins, phase))
+	}
+	JenkinsPluginMismatches.Delete(jenkinsLabels(jenkins))
+	JenkinsSeedJobValidationErrors.Delete(jenkinsLabels(jenkins))
+	JenkinsAPIRequestDuration.Delete(jenkinsLabels(jenkins))
+}
+
+func phaseLabels(jenkins *v1alpha2.Jenkins, phase event.Phase) prometheus.Labels {
+	labels := jenkinsLabels(jenkins)
+	labels[phaseLabel] = string(phase)
+	return labels
+}
+
+type observeDuration struct {
+	rt       http.RoundTripper
+	observer prometheus.Observer
+}
+
+func (t *observeDuration) transport() http.RoundTripper {
+	if t.rt != nil {
+		return t.rt
+	}
+	return http.DefaultTransport
+}
+
+func (t *observeDuration) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+	response, err := t.transport().RoundTrip(r)
+	t.observer.Observe(time.Since(start).Seconds())
+	return response, err
+}
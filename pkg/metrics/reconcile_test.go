@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileMetrics(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"}}
+	labels := jenkinsLabels(jenkins)
+
+	ObserveReconcileDuration(jenkins, 2*time.Second)
+	IncReconcileFailures(jenkins, event.PhaseBase)
+	IncReconcileFailures(jenkins, event.PhaseUser)
+	IncReconcileFailures(jenkins, event.PhaseUser)
+	SetPluginMismatches(jenkins, 3)
+	SetSeedJobValidationErrors(jenkins, 1)
+
+	assert.Equal(t, 1, testutil.CollectAndCount(JenkinsReconcileDuration))
+	assert.Equal(t, 1.0, testutil.ToFloat64(JenkinsReconcileFailures.With(phaseLabels(jenkins, event.PhaseBase))))
+	assert.Equal(t, 2.0, testutil.ToFloat64(JenkinsReconcileFailures.With(phaseLabels(jenkins, event.PhaseUser))))
+	assert.Equal(t, 3.0, testutil.ToFloat64(JenkinsPluginMismatches.With(labels)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(JenkinsSeedJobValidationErrors.With(labels)))
+
+	DeleteReconcileMetrics(jenkins)
+
+	assert.Equal(t, 0, testutil.CollectAndCount(JenkinsReconcileDuration))
+	assert.Equal(t, 0, testutil.CollectAndCount(JenkinsReconcileFailures))
+	assert.Equal(t, 0, testutil.CollectAndCount(JenkinsPluginMismatches))
+	assert.Equal(t, 0, testutil.CollectAndCount(JenkinsSeedJobValidationErrors))
+}
+
+func TestWithJenkinsAPIRequestDuration(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	httpClient := &http.Client{}
+	WithJenkinsAPIRequestDuration(jenkins)(httpClient)
+
+	response, err := httpClient.Get(server.URL)
+	require.NoError(t, err)
+	_ = response.Body.Close()
+
+	assert.Equal(t, 1, testutil.CollectAndCount(JenkinsAPIRequestDuration))
+
+	DeleteReconcileMetrics(jenkins)
+
+	assert.Equal(t, 0, testutil.CollectAndCount(JenkinsAPIRequestDuration))
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Names of the metrics exported from Jenkins CR status, they are used by the generated alerts
+const (
+	JenkinsReadyMetricName             = namespace + "_jenkins_ready"
+	JenkinsLastBackupMetricName        = namespace + "_jenkins_last_backup"
+	JenkinsMasterPodRestartsMetricName = namespace + "_jenkins_master_pod_restarts"
+)
+
+const sourceLabel = "source"
+
+var (
+	// JenkinsReady tells if Jenkins instance is ready
+	JenkinsReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: JenkinsReadyMetricName,
+		Help: "Whether Jenkins instance is configured and ready.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsLastBackup is the number of the latest Jenkins backup
+	JenkinsLastBackup = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: JenkinsLastBackupMetricName,
+		Help: "Number of the latest Jenkins backup.",
+	}, []string{namespaceLabel, nameLabel})
+	// JenkinsMasterPodRestarts is the number of Jenkins master pod restarts by their source
+	JenkinsMasterPodRestarts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: JenkinsMasterPodRestartsMetricName,
+		Help: "Number of Jenkins master pod restarts by their source.",
+	}, []string{namespaceLabel, nameLabel, sourceLabel})
+)
+
+var restartSources = []reason.Source{reason.OperatorSource, reason.KubernetesSource}
+
+func init() {
+	metrics.Registry.MustRegister(JenkinsReady, JenkinsLastBackup, JenkinsMasterPodRestarts)
+}
+
+// SetStatusMetrics updates metrics exported from the status of the given CR.
+func SetStatusMetrics(jenkins *v1alpha2.Jenkins) {
+	labels := jenkinsLabels(jenkins)
+	ready := 0.0
+	if jenkins.Status.Ready {
+		ready = 1
+	}
+	JenkinsReady.With(labels).Set(ready)
+	JenkinsLastBackup.With(labels).Set(float64(jenkins.Status.LastBackup))
+
+	restarts := jenkins.Status.PodRestarts
+	if restarts == nil {
+		restarts = &v1alpha2.PodRestarts{}
+	}
+	counts := map[reason.Source]uint64{
+		reason.OperatorSource:   restarts.Operator,
+		reason.KubernetesSource: restarts.Kubernetes,
+	}
+	for _, source := range restartSources {
+		JenkinsMasterPodRestarts.With(restartLabels(jenkins, source)).Set(float64(counts[source]))
+	}
+}
+
+// DeleteStatusMetrics removes metrics exported from the status of the given CR.
+func DeleteStatusMetrics(jenkins *v1alpha2.Jenkins) {
+	JenkinsReady.Delete(jenkinsLabels(jenkins))
+	JenkinsLastBackup.Delete(jenkinsLabels(jenkins))
+	for _, source := range restartSources {
+		JenkinsMasterPodRestarts.Delete(restartLabels(jenkins, source))
+	}
+}
+
+func restartLabels(jenkins *v1alpha2.Jenkins, source reason.Source) prometheus.Labels {
+	labels := jenkinsLabels(jenkins)
+	labels[sourceLabel] = string(source)
+	return labels
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetStatusMetrics(t *testing.T) {
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+		Status: v1alpha2.JenkinsStatus{
+			Ready:       true,
+			LastBackup:  7,
+			PodRestarts: &v1alpha2.PodRestarts{Operator: 2, Kubernetes: 1},
+		},
+	}
+	labels := jenkinsLabels(jenkins)
+
+	SetStatusMetrics(jenkins)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(JenkinsReady.With(labels)))
+	assert.Equal(t, 7.0, testutil.ToFloat64(JenkinsLastBackup.With(labels)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(JenkinsMasterPodRestarts.With(restartLabels(jenkins, "operator"))))
+	assert.Equal(t, 1.0, testutil.ToFloat64(JenkinsMasterPodRestarts.With(restartLabels(jenkins, "kubernetes"))))
+
+	DeleteStatusMetrics(jenkins)
+
+	assert.Equal(t, 0, testutil.CollectAndCount(JenkinsReady))
+	assert.Equal(t, 0, testutil.CollectAndCount(JenkinsMasterPodRestarts))
+}
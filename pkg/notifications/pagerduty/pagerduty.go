@@ -0,0 +1,146 @@
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/provider"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// eventsURL is the endpoint of PagerDuty Events API v2
+	eventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+	triggerAction = "trigger"
+	source        = "jenkins-operator"
+)
+
+// PagerDuty is a PagerDuty notification service
+type PagerDuty struct {
+	httpClient http.Client
+	k8sClient  k8sclient.Client
+	config     v1alpha2.Notification
+	url        string
+}
+
+// New returns instance of PagerDuty
+func New(k8sClient k8sclient.Client, config v1alpha2.Notification, httpClient http.Client) *PagerDuty {
+	return &PagerDuty{k8sClient: k8sClient, config: config, httpClient: httpClient, url: eventsURL}
+}
+
+// Event is representation of PagerDuty Events API v2 json message structure
+type Event struct {
+	RoutingKey  string  `json:"routing_key"`
+	EventAction string  `json:"event_action"`
+	DedupKey    string  `json:"dedup_key"`
+	Payload     Payload `json:"payload"`
+}
+
+// Payload is the alert details of PagerDuty event
+type Payload struct {
+	Summary       string                     `json:"summary"`
+	Source        string                     `json:"source"`
+	Severity      v1alpha2.PagerDutySeverity `json:"severity"`
+	Component     string                     `json:"component"`
+	Group         string                     `json:"group"`
+	Class         string                     `json:"class"`
+	CustomDetails map[string]string          `json:"custom_details"`
+}
+
+func (p PagerDuty) getSeverity(level v1alpha2.NotificationLevel) v1alpha2.PagerDutySeverity {
+	switch level {
+	case v1alpha2.NotificationLevelWarning:
+		if len(p.config.PagerDuty.WarningSeverity) > 0 {
+			return p.config.PagerDuty.WarningSeverity
+		}
+		return v1alpha2.PagerDutySeverityError
+	default:
+		if len(p.config.PagerDuty.InfoSeverity) > 0 {
+			return p.config.PagerDuty.InfoSeverity
+		}
+		return v1alpha2.PagerDutySeverityInfo
+	}
+}
+
+func (p PagerDuty) generateEvent(e event.Event, routingKey string) Event {
+	var messages []string
+	if p.config.Verbose {
+		messages = e.Reason.Verbose()
+	} else {
+		messages = e.Reason.Short()
+	}
+	reasonType := reflect.Indirect(reflect.ValueOf(e.Reason)).Type().Name()
+
+	return Event{
+		RoutingKey:  routingKey,
+		EventAction: triggerAction,
+		// alerts of the same reason are grouped into a single incident
+		DedupKey: fmt.Sprintf("%s/%s/%s", e.Jenkins.Namespace, e.Jenkins.Name, reasonType),
+		Payload: Payload{
+			Summary:   fmt.Sprintf("%s: %s", provider.NotificationTitle(e), strings.Join(messages, "; ")),
+			Source:    source,
+			Severity:  p.getSeverity(e.Level),
+			Component: fmt.Sprintf("%s/%s", e.Jenkins.Namespace, e.Jenkins.Name),
+			Group:     string(e.Phase),
+			Class:     reasonType,
+			CustomDetails: map[string]string{
+				provider.CrNameFieldName:    e.Jenkins.Name,
+				provider.NamespaceFieldName: e.Jenkins.Namespace,
+				provider.PhaseFieldName:     string(e.Phase),
+				provider.LevelFieldName:     string(e.Level),
+				provider.MessageFieldName:   strings.Join(messages, "\n"),
+			},
+		},
+	}
+}
+
+// Send is function for sending directly to API
+func (p PagerDuty) Send(e event.Event) error {
+	secret := &corev1.Secret{}
+	selector := p.config.PagerDuty.RoutingKeySecretKeySelector
+
+	err := p.k8sClient.Get(context.TODO(), types.NamespacedName{Name: selector.Name, Namespace: e.Jenkins.Namespace}, secret)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	routingKey := string(secret.Data[selector.Key])
+	if routingKey == "" {
+		return errors.Errorf("PagerDuty routing key is empty in secret '%s/%s[%s]", e.Jenkins.Namespace, selector.Name, selector.Key)
+	}
+
+	msg, err := json.Marshal(p.generateEvent(e, routingKey))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, p.url, bytes.NewBuffer(msg))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(request)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return errors.Errorf("Invalid response from server: %s", resp.Status)
+	}
+
+	return nil
+}
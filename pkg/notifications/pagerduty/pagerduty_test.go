@@ -0,0 +1,103 @@
+package pagerduty
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/provider"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPagerDuty_Send(t *testing.T) {
+	e := event.Event{
+		Jenkins: v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "test-cr", Namespace: "default"}},
+		Phase:   event.PhaseBase,
+		Level:   v1alpha2.NotificationLevelWarning,
+		Reason:  reason.NewPodRestart(reason.KubernetesSource, []string{"test-reason-1"}, "test-verbose-1"),
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pagerduty", Namespace: "default"},
+		Data:       map[string][]byte{"routing-key": []byte("R0UT1NGK3Y")},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	config := v1alpha2.Notification{
+		PagerDuty: &v1alpha2.PagerDuty{
+			RoutingKeySecretKeySelector: v1alpha2.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "pagerduty"},
+				Key:                  "routing-key",
+			},
+		},
+	}
+
+	t.Run("trigger", func(t *testing.T) {
+		var received Event
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+		pagerDuty := New(fakeClient, config, http.Client{})
+		pagerDuty.url = server.URL
+
+		err := pagerDuty.Send(e)
+
+		require.NoError(t, err)
+		assert.Equal(t, "R0UT1NGK3Y", received.RoutingKey)
+		assert.Equal(t, "trigger", received.EventAction)
+		assert.Equal(t, "default/test-cr/PodRestart", received.DedupKey)
+		assert.Equal(t, provider.WarnTitleText+": "+e.Reason.Short()[0], received.Payload.Summary)
+		assert.Equal(t, v1alpha2.PagerDutySeverityError, received.Payload.Severity)
+		assert.Equal(t, "default/test-cr", received.Payload.Component)
+		assert.Equal(t, "base", received.Payload.Group)
+		assert.Equal(t, "PodRestart", received.Payload.Class)
+	})
+	t.Run("error response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+		pagerDuty := New(fakeClient, config, http.Client{})
+		pagerDuty.url = server.URL
+
+		err := pagerDuty.Send(e)
+
+		assert.EqualError(t, err, "Invalid response from server: 400 Bad Request")
+	})
+	t.Run("empty routing key", func(t *testing.T) {
+		config := *config.DeepCopy()
+		config.PagerDuty.RoutingKeySecretKeySelector.Key = "missing"
+
+		err := New(fakeClient, config, http.Client{}).Send(e)
+
+		assert.EqualError(t, err, "PagerDuty routing key is empty in secret 'default/pagerduty[missing]")
+	})
+}
+
+func TestPagerDuty_getSeverity(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		pagerDuty := New(nil, v1alpha2.Notification{PagerDuty: &v1alpha2.PagerDuty{}}, http.Client{})
+
+		assert.Equal(t, v1alpha2.PagerDutySeverityError, pagerDuty.getSeverity(v1alpha2.NotificationLevelWarning))
+		assert.Equal(t, v1alpha2.PagerDutySeverityInfo, pagerDuty.getSeverity(v1alpha2.NotificationLevelInfo))
+	})
+	t.Run("mapping", func(t *testing.T) {
+		pagerDuty := New(nil, v1alpha2.Notification{PagerDuty: &v1alpha2.PagerDuty{
+			WarningSeverity: v1alpha2.PagerDutySeverityCritical,
+			InfoSeverity:    v1alpha2.PagerDutySeverityWarning,
+		}}, http.Client{})
+
+		assert.Equal(t, v1alpha2.PagerDutySeverityCritical, pagerDuty.getSeverity(v1alpha2.NotificationLevelWarning))
+		assert.Equal(t, v1alpha2.PagerDutySeverityWarning, pagerDuty.getSeverity(v1alpha2.NotificationLevelInfo))
+	})
+}
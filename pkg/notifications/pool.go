@@ -0,0 +1,108 @@
+package notifications
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// workersPerProvider is the number of goroutines sending notifications of a single provider kind
+	workersPerProvider = 4
+	// queueSizePerProvider is the number of notifications waiting for a worker, when the queue is full
+	// the notifications are dropped to not block the reconcile loops
+	queueSizePerProvider = 100
+	// maxSendAttempts is the number of attempts to send a notification before it's dropped
+	maxSendAttempts = 3
+	// initialSendBackoff is the delay before the first retry, it's doubled on every retry
+	initialSendBackoff = time.Second
+)
+
+// notification is a single notification waiting in the provider queue
+type notification struct {
+	name     string
+	provider Provider
+	event    event.Event
+}
+
+// workerPool sends notifications using a bounded number of goroutines, every provider kind has its own queue so
+// a slow or unavailable service doesn't delay notifications sent by the other ones.
+type workerPool struct {
+	workers     int
+	queueSize   int
+	maxAttempts int
+	backoff     time.Duration
+	queues      map[string]chan notification
+}
+
+func newWorkerPool() *workerPool {
+	return &workerPool{
+		workers:     workersPerProvider,
+		queueSize:   queueSizePerProvider,
+		maxAttempts: maxSendAttempts,
+		backoff:     initialSendBackoff,
+		queues:      map[string]chan notification{},
+	}
+}
+
+// submit queues the notification without blocking, false is returned when the provider queue is full.
+// It must be called from a single goroutine.
+func (p *workerPool) submit(kind string, n notification) bool {
+	queue, found := p.queues[kind]
+	if !found {
+		queue = make(chan notification, p.queueSize)
+		p.queues[kind] = queue
+		for i := 0; i < p.workers; i++ {
+			go p.work(queue)
+		}
+	}
+
+	select {
+	case queue <- n:
+		return true
+	default:
+		return false
+	}
+}
+
+// close stops the workers once the queued notifications are sent.
+func (p *workerPool) close() {
+	for kind, queue := range p.queues {
+		close(queue)
+		delete(p.queues, kind)
+	}
+}
+
+func (p *workerPool) work(queue chan notification) {
+	for n := range queue {
+		if err := p.send(n); err != nil {
+			logger := log.Log.WithValues("cr", n.event.Jenkins.Name, "namespace", n.event.Jenkins.Namespace)
+			wrapped := errors.WithMessage(err, fmt.Sprintf("failed to send notification '%s'", n.name))
+			if log.IsDebug() {
+				logger.Error(nil, fmt.Sprintf("%+v", wrapped))
+			} else {
+				logger.Error(nil, fmt.Sprintf("%s", wrapped))
+			}
+		}
+	}
+}
+
+// send retries failed notifications with exponential backoff, the jitter spreads retries of notifications failed
+// at the same time
+func (p *workerPool) send(n notification) error {
+	backoff := p.backoff
+	for attempt := 1; ; attempt++ {
+		err := n.provider.Send(n.event)
+		if err == nil || attempt >= p.maxAttempts {
+			return err
+		}
+		jitter := time.Duration(rand.Int63n(int64(backoff)/2 + 1))
+		time.Sleep(backoff + jitter)
+		backoff *= 2
+	}
+}
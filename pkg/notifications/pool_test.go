@@ -0,0 +1,88 @@
+package notifications
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeProvider struct {
+	sync.Mutex
+	failures int
+	calls    int
+	block    chan struct{}
+	sent     chan struct{}
+}
+
+func (p *fakeProvider) Send(event.Event) error {
+	if p.block != nil {
+		<-p.block
+	}
+	p.Lock()
+	defer p.Unlock()
+	p.calls++
+	if p.calls <= p.failures {
+		return errors.New("service unavailable")
+	}
+	if p.sent != nil {
+		p.sent <- struct{}{}
+	}
+	return nil
+}
+
+func TestWorkerPool(t *testing.T) {
+	t.Run("retries failed notification", func(t *testing.T) {
+		pool := newWorkerPool()
+		pool.backoff = time.Millisecond
+		defer pool.close()
+		provider := &fakeProvider{failures: 2, sent: make(chan struct{}, 1)}
+
+		assert.True(t, pool.submit("slack", notification{name: "slack", provider: provider}))
+
+		select {
+		case <-provider.sent:
+		case <-time.After(5 * time.Second):
+			assert.Fail(t, "notification not sent")
+		}
+		provider.Lock()
+		defer provider.Unlock()
+		assert.Equal(t, 3, provider.calls)
+	})
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		pool := newWorkerPool()
+		pool.backoff = time.Millisecond
+		provider := &fakeProvider{failures: 10}
+
+		err := pool.send(notification{name: "slack", provider: provider})
+
+		assert.EqualError(t, err, "service unavailable")
+		assert.Equal(t, maxSendAttempts, provider.calls)
+	})
+	t.Run("drops notifications when queue is full", func(t *testing.T) {
+		pool := newWorkerPool()
+		pool.workers = 1
+		pool.queueSize = 1
+		blocked := &fakeProvider{block: make(chan struct{})}
+		other := &fakeProvider{sent: make(chan struct{}, 1)}
+
+		assert.True(t, pool.submit("slack", notification{name: "slack", provider: blocked}))
+		// wait for the worker to take the first notification
+		assert.Eventually(t, func() bool { return len(pool.queues["slack"]) == 0 }, 5*time.Second, time.Millisecond)
+		assert.True(t, pool.submit("slack", notification{name: "slack", provider: blocked}))
+		assert.False(t, pool.submit("slack", notification{name: "slack", provider: blocked}))
+		assert.True(t, pool.submit("smtp", notification{name: "smtp", provider: other}))
+
+		select {
+		case <-other.sent:
+		case <-time.After(5 * time.Second):
+			assert.Fail(t, "notification of other provider not sent")
+		}
+		close(blocked.block)
+		pool.close()
+	})
+}
@@ -1,8 +1,15 @@
 package provider
 
 import (
+	"context"
+
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
 	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -39,3 +46,25 @@ func NotificationTitle(event event.Event) string {
 		return ""
 	}
 }
+
+// GetCustomSecretParameter returns the value of the secret parameter of the custom notification provider
+func GetCustomSecretParameter(k8sClient k8sclient.Client, e event.Event, config v1alpha2.Notification, name string) (string, error) {
+	if config.Custom == nil {
+		return "", errors.Errorf("notification '%s' is not handled by a custom provider", config.Name)
+	}
+	selector, found := config.Custom.SecretParameters[name]
+	if !found {
+		return "", errors.Errorf("notification '%s' doesn't define '%s' secret parameter", config.Name, name)
+	}
+
+	secret := &corev1.Secret{}
+	err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: selector.Name, Namespace: e.Jenkins.Namespace}, secret)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	value, found := secret.Data[selector.Key]
+	if !found {
+		return "", errors.Errorf("secret '%s' doesn't contain '%s' key", selector.Name, selector.Key)
+	}
+	return string(value), nil
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetCustomSecretParameter(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "acme", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("secret-token")},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	e := event.Event{Jenkins: v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}}
+	config := v1alpha2.Notification{
+		Name: "acme",
+		Custom: &v1alpha2.CustomNotification{
+			Provider: "acme",
+			SecretParameters: map[string]v1alpha2.SecretKeySelector{
+				"token":   {LocalObjectReference: corev1.LocalObjectReference{Name: "acme"}, Key: "token"},
+				"missing": {LocalObjectReference: corev1.LocalObjectReference{Name: "acme"}, Key: "missing"},
+			},
+		},
+	}
+
+	t.Run("secret parameter", func(t *testing.T) {
+		value, err := GetCustomSecretParameter(k8sClient, e, config, "token")
+
+		require.NoError(t, err)
+		assert.Equal(t, "secret-token", value)
+	})
+	t.Run("undefined parameter", func(t *testing.T) {
+		_, err := GetCustomSecretParameter(k8sClient, e, config, "password")
+
+		assert.EqualError(t, err, "notification 'acme' doesn't define 'password' secret parameter")
+	})
+	t.Run("missing key", func(t *testing.T) {
+		_, err := GetCustomSecretParameter(k8sClient, e, config, "missing")
+
+		assert.EqualError(t, err, "secret 'acme' doesn't contain 'missing' key")
+	})
+}
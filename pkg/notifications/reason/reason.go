@@ -1,6 +1,9 @@
 package reason
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 const (
 	// OperatorSource defines that notification concerns operator
@@ -16,6 +19,7 @@ type Reason interface {
 	Short() []string
 	Verbose() []string
 	HasMessages() bool
+	Source() Source
 }
 
 // Undefined is base or untraceable reason.
@@ -35,11 +39,66 @@ type PodCreation struct {
 	Undefined
 }
 
+// PodStartingFailed defines the reason why Jenkins master pod didn't start.
+type PodStartingFailed struct {
+	Undefined
+}
+
 // ReconcileLoopFailed defines the reason why the reconcile loop failed.
 type ReconcileLoopFailed struct {
 	Undefined
 }
 
+// ReconcileLoopStalled defines the reason why the reconcile loop is considered stalled.
+type ReconcileLoopStalled struct {
+	Undefined
+}
+
+// DiskUsageHigh informs that Jenkins home volume is running out of space.
+type DiskUsageHigh struct {
+	Undefined
+}
+
+// UpdatesAvailable informs that newer Jenkins core or plugin versions are available in the update center.
+type UpdatesAvailable struct {
+	Undefined
+}
+
+// ResourceAdopted informs that a pre-existing resource has been taken over by the operator.
+type ResourceAdopted struct {
+	Undefined
+}
+
+// SpecUpdated informs that spec of Jenkins CR has been changed.
+type SpecUpdated struct {
+	Undefined
+}
+
+// JenkinsCloned informs that a clone of Jenkins CR has been created.
+type JenkinsCloned struct {
+	Undefined
+}
+
+// CrashLoopRollback informs that the spec has been rolled back because Jenkins master pod entered crash loop.
+type CrashLoopRollback struct {
+	Undefined
+}
+
+// VolumeResized informs that the expansion of a volume is complete.
+type VolumeResized struct {
+	Undefined
+}
+
+// VolumeMigrated informs that the migration of a volume to another storage class is complete.
+type VolumeMigrated struct {
+	Undefined
+}
+
+// RestoreDryRun informs about the content of the backup listed by restore dry run.
+type RestoreDryRun struct {
+	Undefined
+}
+
 // GroovyScriptExecutionFailed defines the reason why the groovy script execution failed.
 type GroovyScriptExecutionFailed struct {
 	Undefined
@@ -94,6 +153,46 @@ func NewPodRestart(source Source, short []string, verbose ...string) *PodRestart
 	}
 }
 
+// NewPodRestartBatch returns new instance of PodRestart which lists the messages of all given restart reasons, it's
+// used when multiple changes are applied by a single restart. The source is the most significant source of the
+// reasons: kubernetes, then human, then operator.
+func NewPodRestartBatch(reasons ...Reason) *PodRestart {
+	source := OperatorSource
+	var short, verbose []string
+	for _, restartReason := range reasons {
+		if sourcePriority(restartReason.Source()) > sourcePriority(source) {
+			source = restartReason.Source()
+		}
+		short = append(short, podRestartMessages(restartReason.Source(), restartReason.Short())...)
+		verbose = append(verbose, podRestartMessages(restartReason.Source(), restartReason.Verbose())...)
+	}
+	return NewPodRestart(source, short, verbose...)
+}
+
+// podRestartMessages removes the header added by NewPodRestart to not repeat it for every batched reason
+func podRestartMessages(source Source, messages []string) []string {
+	restartPodMessage := fmt.Sprintf("Jenkins master pod restarted by %s:", source)
+	if len(messages) > 1 && messages[0] == restartPodMessage {
+		return messages[1:]
+	}
+	var trimmed []string
+	for _, message := range messages {
+		trimmed = append(trimmed, strings.TrimPrefix(message, restartPodMessage+" "))
+	}
+	return trimmed
+}
+
+func sourcePriority(source Source) int {
+	switch source {
+	case KubernetesSource:
+		return 2
+	case HumanSource:
+		return 1
+	default:
+		return 0
+	}
+}
+
 // NewPodCreation returns new instance of PodCreation.
 func NewPodCreation(source Source, short []string, verbose ...string) *PodCreation {
 	return &PodCreation{
@@ -105,6 +204,17 @@ func NewPodCreation(source Source, short []string, verbose ...string) *PodCreati
 	}
 }
 
+// NewPodStartingFailed returns new instance of PodStartingFailed.
+func NewPodStartingFailed(source Source, short []string, verbose ...string) *PodStartingFailed {
+	return &PodStartingFailed{
+		Undefined{
+			source:  source,
+			short:   short,
+			verbose: checkIfVerboseEmpty(short, verbose),
+		},
+	}
+}
+
 // NewReconcileLoopFailed returns new instance of ReconcileLoopFailed.
 func NewReconcileLoopFailed(source Source, short []string, verbose ...string) *ReconcileLoopFailed {
 	return &ReconcileLoopFailed{
@@ -116,6 +226,116 @@ func NewReconcileLoopFailed(source Source, short []string, verbose ...string) *R
 	}
 }
 
+// NewReconcileLoopStalled returns new instance of ReconcileLoopStalled.
+func NewReconcileLoopStalled(source Source, short []string, verbose ...string) *ReconcileLoopStalled {
+	return &ReconcileLoopStalled{
+		Undefined{
+			source:  source,
+			short:   short,
+			verbose: checkIfVerboseEmpty(short, verbose),
+		},
+	}
+}
+
+// NewDiskUsageHigh returns new instance of DiskUsageHigh.
+func NewDiskUsageHigh(source Source, short []string, verbose ...string) *DiskUsageHigh {
+	return &DiskUsageHigh{
+		Undefined{
+			source:  source,
+			short:   short,
+			verbose: checkIfVerboseEmpty(short, verbose),
+		},
+	}
+}
+
+// NewUpdatesAvailable returns new instance of UpdatesAvailable.
+func NewUpdatesAvailable(source Source, short []string, verbose ...string) *UpdatesAvailable {
+	return &UpdatesAvailable{
+		Undefined{
+			source:  source,
+			short:   short,
+			verbose: checkIfVerboseEmpty(short, verbose),
+		},
+	}
+}
+
+// NewResourceAdopted returns new instance of ResourceAdopted.
+func NewResourceAdopted(source Source, short []string, verbose ...string) *ResourceAdopted {
+	return &ResourceAdopted{
+		Undefined{
+			source:  source,
+			short:   short,
+			verbose: checkIfVerboseEmpty(short, verbose),
+		},
+	}
+}
+
+// NewSpecUpdated returns new instance of SpecUpdated.
+func NewSpecUpdated(source Source, short []string, verbose ...string) *SpecUpdated {
+	return &SpecUpdated{
+		Undefined{
+			source:  source,
+			short:   short,
+			verbose: checkIfVerboseEmpty(short, verbose),
+		},
+	}
+}
+
+// NewJenkinsCloned returns new instance of JenkinsCloned.
+func NewJenkinsCloned(source Source, short []string, verbose ...string) *JenkinsCloned {
+	return &JenkinsCloned{
+		Undefined{
+			source:  source,
+			short:   short,
+			verbose: checkIfVerboseEmpty(short, verbose),
+		},
+	}
+}
+
+// NewCrashLoopRollback returns new instance of CrashLoopRollback.
+func NewCrashLoopRollback(source Source, short []string, verbose ...string) *CrashLoopRollback {
+	return &CrashLoopRollback{
+		Undefined{
+			source:  source,
+			short:   short,
+			verbose: checkIfVerboseEmpty(short, verbose),
+		},
+	}
+}
+
+// NewVolumeResized returns new instance of VolumeResized.
+func NewVolumeResized(source Source, short []string, verbose ...string) *VolumeResized {
+	return &VolumeResized{
+		Undefined{
+			source:  source,
+			short:   short,
+			verbose: checkIfVerboseEmpty(short, verbose),
+		},
+	}
+}
+
+// NewVolumeMigrated returns new instance of VolumeMigrated.
+func NewVolumeMigrated(source Source, short []string, verbose ...string) *VolumeMigrated {
+	return &VolumeMigrated{
+		Undefined{
+			source:  source,
+			short:   short,
+			verbose: checkIfVerboseEmpty(short, verbose),
+		},
+	}
+}
+
+// NewRestoreDryRun returns new instance of RestoreDryRun.
+func NewRestoreDryRun(source Source, short []string, verbose ...string) *RestoreDryRun {
+	return &RestoreDryRun{
+		Undefined{
+			source:  source,
+			short:   short,
+			verbose: checkIfVerboseEmpty(short, verbose),
+		},
+	}
+}
+
 // NewGroovyScriptExecutionFailed returns new instance of GroovyScriptExecutionFailed.
 func NewGroovyScriptExecutionFailed(source Source, short []string, verbose ...string) *GroovyScriptExecutionFailed {
 	return &GroovyScriptExecutionFailed{
@@ -189,6 +409,11 @@ func (p Undefined) HasMessages() bool {
 	return len(p.short) > 0 || len(p.verbose) > 0
 }
 
+// Source is the source of the reason: operator, kubernetes or human.
+func (p Undefined) Source() Source {
+	return p.source
+}
+
 func checkIfVerboseEmpty(short []string, verbose []string) []string {
 	if len(verbose) == 0 {
 		return short
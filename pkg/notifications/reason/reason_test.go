@@ -75,3 +75,21 @@ func TestPodRestartPrepend(t *testing.T) {
 		assert.Equal(t, fmt.Sprintf("Jenkins master pod restarted by %s:", KubernetesSource), podRestart.short[0])
 	})
 }
+
+func TestNewPodRestartBatch(t *testing.T) {
+	t.Run("lists messages of all reasons", func(t *testing.T) {
+		podRestart := NewPodRestartBatch(
+			NewPodRestart(OperatorSource, []string{"Some plugins have changed"}),
+			NewPodRestart(HumanSource, []string{"first", "second"}, "first verbose", "second verbose"),
+		)
+
+		assert.Equal(t, HumanSource, podRestart.Source())
+		assert.Equal(t, []string{"Jenkins master pod restarted by human:", "Some plugins have changed", "first", "second"}, podRestart.Short())
+		assert.Equal(t, []string{"Jenkins master pod restarted by human:", "Some plugins have changed", "first verbose", "second verbose"}, podRestart.Verbose())
+	})
+	t.Run("single reason", func(t *testing.T) {
+		podRestart := NewPodRestartBatch(NewPodRestart(KubernetesSource, []string{"Invalid Jenkins pod phase 'Failed'"}))
+
+		assert.Equal(t, NewPodRestart(KubernetesSource, []string{"Invalid Jenkins pod phase 'Failed'"}), podRestart)
+	})
+}
@@ -0,0 +1,108 @@
+package notifications
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/mailgun"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/msteams"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/pagerduty"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/slack"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/smtp"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/webhook"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ProviderFactory creates the Provider handling the notification configuration, nil is returned when
+// the configuration is meant for another provider kind.
+type ProviderFactory func(k8sClient k8sclient.Client, config v1alpha2.Notification, httpClient http.Client) Provider
+
+type registeredProvider struct {
+	kind    string
+	factory ProviderFactory
+}
+
+var (
+	providersMutex sync.RWMutex
+	providers      []registeredProvider
+)
+
+func init() {
+	RegisterProvider("slack", func(k8sClient k8sclient.Client, config v1alpha2.Notification, httpClient http.Client) Provider {
+		if config.Slack == nil {
+			return nil
+		}
+		return slack.New(k8sClient, config, httpClient)
+	})
+	RegisterProvider("teams", func(k8sClient k8sclient.Client, config v1alpha2.Notification, httpClient http.Client) Provider {
+		if config.Teams == nil {
+			return nil
+		}
+		return msteams.New(k8sClient, config, httpClient)
+	})
+	RegisterProvider("mailgun", func(k8sClient k8sclient.Client, config v1alpha2.Notification, _ http.Client) Provider {
+		if config.Mailgun == nil {
+			return nil
+		}
+		return mailgun.New(k8sClient, config)
+	})
+	RegisterProvider("smtp", func(k8sClient k8sclient.Client, config v1alpha2.Notification, _ http.Client) Provider {
+		if config.SMTP == nil {
+			return nil
+		}
+		return smtp.New(k8sClient, config)
+	})
+	RegisterProvider("webhook", func(k8sClient k8sclient.Client, config v1alpha2.Notification, httpClient http.Client) Provider {
+		if config.Webhook == nil {
+			return nil
+		}
+		return webhook.New(k8sClient, config, httpClient)
+	})
+	RegisterProvider("pagerduty", func(k8sClient k8sclient.Client, config v1alpha2.Notification, httpClient http.Client) Provider {
+		if config.PagerDuty == nil {
+			return nil
+		}
+		return pagerduty.New(k8sClient, config, httpClient)
+	})
+}
+
+// RegisterProvider registers a notification provider kind, the providers are asked in the order of registration and
+// the first one returning a Provider sends the notification. Every kind has its own queue of notifications.
+// It panics when the kind is already registered.
+func RegisterProvider(kind string, factory ProviderFactory) {
+	providersMutex.Lock()
+	defer providersMutex.Unlock()
+	for _, registered := range providers {
+		if registered.kind == kind {
+			panic(fmt.Sprintf("notification provider '%s' is already registered", kind))
+		}
+	}
+	providers = append(providers, registeredProvider{kind: kind, factory: factory})
+}
+
+// RegisterCustomProvider registers a notification provider shipped out of the operator tree, e.g. by a binary embedding
+// the operator. The factory is called for notification configurations with spec.notifications[].custom.provider
+// equal to the kind. It panics when the kind is already registered.
+func RegisterCustomProvider(kind string, factory ProviderFactory) {
+	RegisterProvider(kind, func(k8sClient k8sclient.Client, config v1alpha2.Notification, httpClient http.Client) Provider {
+		if config.Custom == nil || config.Custom.Provider != kind {
+			return nil
+		}
+		return factory(k8sClient, config, httpClient)
+	})
+}
+
+// newProvider returns the kind and the Provider of the first registered provider handling the notification configuration
+func newProvider(k8sClient k8sclient.Client, config v1alpha2.Notification, httpClient http.Client) (string, Provider, bool) {
+	providersMutex.RLock()
+	defer providersMutex.RUnlock()
+	for _, registered := range providers {
+		if provider := registered.factory(k8sClient, config, httpClient); provider != nil {
+			return registered.kind, provider, true
+		}
+	}
+	return "", nil, false
+}
@@ -0,0 +1,67 @@
+package notifications
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/webhook"
+
+	"github.com/stretchr/testify/assert"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRegisterProvider(t *testing.T) {
+	t.Run("built-in provider", func(t *testing.T) {
+		kind, provider, found := newProvider(nil, v1alpha2.Notification{Webhook: &v1alpha2.Webhook{}}, http.Client{})
+
+		assert.True(t, found)
+		assert.Equal(t, "webhook", kind)
+		assert.IsType(t, &webhook.Webhook{}, provider)
+	})
+	t.Run("unknown provider", func(t *testing.T) {
+		_, _, found := newProvider(nil, v1alpha2.Notification{}, http.Client{})
+
+		assert.False(t, found)
+	})
+	t.Run("custom provider", func(t *testing.T) {
+		registered := providers
+		defer func() { providers = registered }()
+		custom := &fakeProvider{}
+
+		RegisterProvider("custom", func(_ k8sclient.Client, config v1alpha2.Notification, _ http.Client) Provider {
+			if config.Name != "custom" {
+				return nil
+			}
+			return custom
+		})
+		kind, provider, found := newProvider(nil, v1alpha2.Notification{Name: "custom"}, http.Client{})
+
+		assert.True(t, found)
+		assert.Equal(t, "custom", kind)
+		assert.Equal(t, custom, provider)
+	})
+	t.Run("out of tree provider", func(t *testing.T) {
+		registered := providers
+		defer func() { providers = registered }()
+		custom := &fakeProvider{}
+
+		RegisterCustomProvider("acme", func(k8sclient.Client, v1alpha2.Notification, http.Client) Provider {
+			return custom
+		})
+		kind, provider, found := newProvider(nil, v1alpha2.Notification{Custom: &v1alpha2.CustomNotification{Provider: "acme"}}, http.Client{})
+
+		assert.True(t, found)
+		assert.Equal(t, "acme", kind)
+		assert.Equal(t, custom, provider)
+
+		_, _, found = newProvider(nil, v1alpha2.Notification{Custom: &v1alpha2.CustomNotification{Provider: "other"}}, http.Client{})
+
+		assert.False(t, found)
+	})
+	t.Run("already registered", func(t *testing.T) {
+		assert.Panics(t, func() {
+			RegisterProvider("slack", func(k8sclient.Client, v1alpha2.Notification, http.Client) Provider { return nil })
+		})
+	})
+}
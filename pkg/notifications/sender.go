@@ -3,6 +3,7 @@ package notifications
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strings"
 
@@ -10,12 +11,7 @@ import (
 	k8sevent "github.com/maximba/kubernetes-operator/pkg/event"
 	"github.com/maximba/kubernetes-operator/pkg/log"
 	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
-	"github.com/maximba/kubernetes-operator/pkg/notifications/mailgun"
-	"github.com/maximba/kubernetes-operator/pkg/notifications/msteams"
-	"github.com/maximba/kubernetes-operator/pkg/notifications/slack"
-	"github.com/maximba/kubernetes-operator/pkg/notifications/smtp"
 
-	"github.com/pkg/errors"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -24,11 +20,17 @@ type Provider interface {
 	Send(event event.Event) error
 }
 
-// Listen listens for incoming events and send it as notifications.
-func Listen(events chan event.Event, k8sEvent k8sevent.Recorder, k8sClient k8sclient.Client) {
-	httpClient := http.Client{}
+// Listen listens for incoming events and send it as notifications, the notifications are sent by the bounded pool
+// of workers with a queue per provider kind. HTTP notifications are sent through the proxy returned by the proxy
+// function, it is called for every request so the proxy can change at runtime.
+func Listen(events chan event.Event, k8sEvent k8sevent.Recorder, k8sClient k8sclient.Client, proxy func(*http.Request) (*url.URL, error)) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	httpClient := http.Client{Transport: transport}
+	pool := newWorkerPool()
+	defer pool.close()
 	for e := range events {
-		logger := log.Log.WithValues("cr", e.Jenkins.Name)
+		logger := log.Log.WithValues("cr", e.Jenkins.Name, "namespace", e.Jenkins.Namespace)
 
 		if !e.Reason.HasMessages() {
 			logger.V(log.VWarn).Info("Reason has no messages, this should not happen")
@@ -42,18 +44,8 @@ func Listen(events chan event.Event, k8sEvent k8sevent.Recorder, k8sClient k8scl
 		)
 
 		for _, notificationConfig := range e.Jenkins.Spec.Notifications {
-			var err error
-			var provider Provider
-			switch {
-			case notificationConfig.Slack != nil:
-				provider = slack.New(k8sClient, notificationConfig, httpClient)
-			case notificationConfig.Teams != nil:
-				provider = msteams.New(k8sClient, notificationConfig, httpClient)
-			case notificationConfig.Mailgun != nil:
-				provider = mailgun.New(k8sClient, notificationConfig)
-			case notificationConfig.SMTP != nil:
-				provider = smtp.New(k8sClient, notificationConfig)
-			default:
+			kind, provider, found := newProvider(k8sClient, notificationConfig, httpClient)
+			if !found {
 				logger.V(log.VWarn).Info(fmt.Sprintf("Unknown notification service `%+v`", notificationConfig))
 				continue
 			}
@@ -64,18 +56,9 @@ func Listen(events chan event.Event, k8sEvent k8sevent.Recorder, k8sClient k8scl
 				continue // skip the event
 			}
 
-			go func(notificationConfig v1alpha2.Notification) {
-				err = provider.Send(e)
-				if err != nil {
-					wrapped := errors.WithMessage(err,
-						fmt.Sprintf("failed to send notification '%s'", notificationConfig.Name))
-					if log.Debug {
-						logger.Error(nil, fmt.Sprintf("%+v", wrapped))
-					} else {
-						logger.Error(nil, fmt.Sprintf("%s", wrapped))
-					}
-				}
-			}(notificationConfig)
+			if !pool.submit(kind, notification{name: notificationConfig.Name, provider: provider, event: e}) {
+				logger.V(log.VWarn).Info(fmt.Sprintf("Queue of %s notifications is full, dropping notification '%s'", kind, notificationConfig.Name))
+			}
 		}
 	}
 }
@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"text/template"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/provider"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Webhook is a generic HTTP notification service
+type Webhook struct {
+	httpClient http.Client
+	k8sClient  k8sclient.Client
+	config     v1alpha2.Notification
+}
+
+// New returns instance of Webhook
+func New(k8sClient k8sclient.Client, config v1alpha2.Notification, httpClient http.Client) *Webhook {
+	return &Webhook{k8sClient: k8sClient, config: config, httpClient: httpClient}
+}
+
+// Payload is the data of notification, it's the request body when the payload template is not set
+type Payload struct {
+	Title     string                     `json:"title"`
+	Level     v1alpha2.NotificationLevel `json:"level"`
+	Phase     event.Phase                `json:"phase"`
+	Namespace string                     `json:"namespace"`
+	Name      string                     `json:"name"`
+	Messages  []string                   `json:"messages"`
+}
+
+func (w Webhook) generatePayload(e event.Event) Payload {
+	messages := e.Reason.Short()
+	if w.config.Verbose {
+		messages = e.Reason.Verbose()
+	}
+	return Payload{
+		Title:     provider.NotificationTitle(e),
+		Level:     e.Level,
+		Phase:     e.Phase,
+		Namespace: e.Jenkins.Namespace,
+		Name:      e.Jenkins.Name,
+		Messages:  messages,
+	}
+}
+
+func (w Webhook) generateBody(e event.Event) ([]byte, error) {
+	payload := w.generatePayload(e)
+	if len(w.config.Webhook.PayloadTemplate) == 0 {
+		body, err := json.Marshal(payload)
+		return body, errors.WithStack(err)
+	}
+
+	tmpl, err := template.New(w.config.Name).Option("missingkey=error").Funcs(template.FuncMap{
+		"json": func(value interface{}) (string, error) {
+			encoded, err := json.Marshal(value)
+			return string(encoded), errors.WithStack(err)
+		},
+	}).Parse(w.config.Webhook.PayloadTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid payload template")
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, payload); err != nil {
+		return nil, errors.Wrap(err, "failed to render payload template")
+	}
+	return body.Bytes(), nil
+}
+
+func (w Webhook) getSecret(name, namespace string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := w.k8sClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, secret)
+	return secret, errors.WithStack(err)
+}
+
+// Send is function for sending directly to API
+func (w Webhook) Send(e event.Event) error {
+	selector := w.config.Webhook.URLSecretKeySelector
+	secret, err := w.getSecret(selector.Name, e.Jenkins.Namespace)
+	if err != nil {
+		return err
+	}
+	url := string(secret.Data[selector.Key])
+	if url == "" {
+		return errors.Errorf("Webhook URL is empty in secret '%s/%s[%s]", e.Jenkins.Namespace, selector.Name, selector.Key)
+	}
+
+	body, err := w.generateBody(e)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if headersSecret := w.config.Webhook.HeadersSecret; headersSecret != nil {
+		secret, err := w.getSecret(headersSecret.Name, e.Jenkins.Namespace)
+		if err != nil {
+			return err
+		}
+		for name, value := range secret.Data {
+			request.Header.Set(name, string(value))
+		}
+	}
+
+	resp, err := w.httpClient.Do(request)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("Invalid response from server: %s", resp.Status)
+	}
+
+	return nil
+}
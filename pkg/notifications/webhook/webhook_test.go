@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/provider"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWebhook_Send(t *testing.T) {
+	e := event.Event{
+		Jenkins: v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "test-cr", Namespace: "default"}},
+		Phase:   event.PhaseBase,
+		Level:   v1alpha2.NotificationLevelWarning,
+		Reason:  reason.NewPodRestart(reason.KubernetesSource, []string{"test-reason-1"}, "test-verbose-1"),
+	}
+	var requests []*http.Request
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, r)
+		bodies = append(bodies, string(body))
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	urlSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook", Namespace: "default"},
+		Data:       map[string][]byte{"url": []byte(server.URL), "error-url": []byte(server.URL + "/error")},
+	}
+	headersSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-headers", Namespace: "default"},
+		Data:       map[string][]byte{"Authorization": []byte("Bearer token")},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(urlSecret, headersSecret).Build()
+	newWebhook := func(config v1alpha2.Webhook) *Webhook {
+		return New(fakeClient, v1alpha2.Notification{Name: "webhook", Webhook: &config}, http.Client{})
+	}
+	urlSelector := func(key string) v1alpha2.SecretKeySelector {
+		return v1alpha2.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "webhook"}, Key: key}
+	}
+
+	t.Run("default payload", func(t *testing.T) {
+		requests, bodies = nil, nil
+
+		err := newWebhook(v1alpha2.Webhook{URLSecretKeySelector: urlSelector("url")}).Send(e)
+
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		assert.Equal(t, http.MethodPost, requests[0].Method)
+		assert.Equal(t, "application/json", requests[0].Header.Get("Content-Type"))
+		var payload Payload
+		require.NoError(t, json.Unmarshal([]byte(bodies[0]), &payload))
+		assert.Equal(t, Payload{
+			Title:     provider.WarnTitleText,
+			Level:     v1alpha2.NotificationLevelWarning,
+			Phase:     event.PhaseBase,
+			Namespace: "default",
+			Name:      "test-cr",
+			Messages:  e.Reason.Short(),
+		}, payload)
+	})
+	t.Run("payload template and headers", func(t *testing.T) {
+		requests, bodies = nil, nil
+
+		err := newWebhook(v1alpha2.Webhook{
+			URLSecretKeySelector: urlSelector("url"),
+			HeadersSecret:        &v1alpha2.SecretRef{Name: "webhook-headers"},
+			PayloadTemplate:      `{"text": {{ json (printf "%s/%s: %s" .Namespace .Name (index .Messages 0)) }}}`,
+		}).Send(e)
+
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "Bearer token", requests[0].Header.Get("Authorization"))
+		assert.Equal(t, `{"text": "default/test-cr: `+e.Reason.Short()[0]+`"}`, bodies[0])
+	})
+	t.Run("invalid payload template", func(t *testing.T) {
+		requests, bodies = nil, nil
+
+		err := newWebhook(v1alpha2.Webhook{URLSecretKeySelector: urlSelector("url"), PayloadTemplate: "{{ .Unknown }}"}).Send(e)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to render payload template")
+		assert.Empty(t, requests)
+	})
+	t.Run("empty URL", func(t *testing.T) {
+		err := newWebhook(v1alpha2.Webhook{URLSecretKeySelector: urlSelector("missing")}).Send(e)
+
+		assert.EqualError(t, err, "Webhook URL is empty in secret 'default/webhook[missing]")
+	})
+	t.Run("error response", func(t *testing.T) {
+		err := newWebhook(v1alpha2.Webhook{URLSecretKeySelector: urlSelector("error-url")}).Send(e)
+
+		assert.EqualError(t, err, "Invalid response from server: 500 Internal Server Error")
+	})
+}
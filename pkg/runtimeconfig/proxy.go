@@ -0,0 +1,19 @@
+package runtimeconfig
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// NotificationProxy returns the proxy of the HTTP request sending a notification, it is meant to be http.Transport
+// Proxy. When NotificationProxy of the current settings is empty the proxy is taken from the environment variables.
+func (c *Config) NotificationProxy(request *http.Request) (*url.URL, error) {
+	proxy := c.Get().NotificationProxy
+	if proxy == "" {
+		return http.ProxyFromEnvironment(request)
+	}
+	proxyURL, err := url.Parse(proxy)
+	return proxyURL, errors.WithStack(err)
+}
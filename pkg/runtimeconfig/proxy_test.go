@@ -0,0 +1,35 @@
+package runtimeconfig
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_NotificationProxy(t *testing.T) {
+	request, err := http.NewRequest(http.MethodPost, "https://hooks.slack.com/services/test", nil)
+	require.NoError(t, err)
+
+	t.Run("proxy from environment", func(t *testing.T) {
+		// requests to localhost are never proxied by the environment variables
+		localRequest, err := http.NewRequest(http.MethodPost, "http://localhost:8080/webhook", nil)
+		require.NoError(t, err)
+
+		proxy, err := New(Settings{}).NotificationProxy(localRequest)
+
+		assert.NoError(t, err)
+		assert.Nil(t, proxy)
+	})
+	t.Run("proxy changed at runtime", func(t *testing.T) {
+		config := New(Settings{NotificationProxy: "http://proxy:3128"})
+		_, err := config.Apply(map[string]string{NotificationProxyKey: "socks5://other-proxy:1080"})
+		require.NoError(t, err)
+
+		proxy, err := config.NotificationProxy(request)
+
+		require.NoError(t, err)
+		assert.Equal(t, "socks5://other-proxy:1080", proxy.String())
+	})
+}
@@ -0,0 +1,117 @@
+package runtimeconfig
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	defaultWorkqueueBaseDelay = 5 * time.Millisecond
+	defaultWorkqueueMaxDelay  = 1000 * time.Second
+)
+
+// workqueueRateLimiter delays failed items exponentially like workqueue.ItemExponentialFailureRateLimiter, but the
+// delays are read from the current settings on every failure.
+type workqueueRateLimiter struct {
+	config   *Config
+	mutex    sync.Mutex
+	failures map[interface{}]int
+}
+
+// NewWorkqueueRateLimiter returns the workqueue rate limiter which delays failed reconciles of every item separately
+// using WorkqueueBaseDelay and WorkqueueMaxDelay of the current settings. Unset delays fall back to the
+// controller-runtime defaults.
+func (c *Config) NewWorkqueueRateLimiter() workqueue.RateLimiter {
+	return &workqueueRateLimiter{config: c, failures: map[interface{}]int{}}
+}
+
+func (r *workqueueRateLimiter) When(item interface{}) time.Duration {
+	settings := r.config.Get()
+	baseDelay, maxDelay := settings.WorkqueueBaseDelay, settings.WorkqueueMaxDelay
+	if baseDelay <= 0 {
+		baseDelay = defaultWorkqueueBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultWorkqueueMaxDelay
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	exp := r.failures[item]
+	r.failures[item]++
+
+	backoff := float64(baseDelay.Nanoseconds()) * math.Pow(2, float64(exp))
+	if backoff > float64(maxDelay.Nanoseconds()) {
+		return maxDelay
+	}
+	return time.Duration(backoff)
+}
+
+func (r *workqueueRateLimiter) NumRequeues(item interface{}) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.failures[item]
+}
+
+func (r *workqueueRateLimiter) Forget(item interface{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.failures, item)
+}
+
+// kubeAPIRateLimiter limits the operator queries to the Kubernetes API with the token bucket, the bucket is replaced
+// when KubeAPIQPS or KubeAPIBurst of the current settings change.
+type kubeAPIRateLimiter struct {
+	config  *Config
+	mutex   sync.Mutex
+	qps     float32
+	burst   int
+	limiter flowcontrol.RateLimiter
+}
+
+// NewKubeAPIRateLimiter returns the rate limiter of Kubernetes API clients which follows KubeAPIQPS and KubeAPIBurst
+// of the current settings, it is set as rest.Config RateLimiter.
+func (c *Config) NewKubeAPIRateLimiter() flowcontrol.RateLimiter {
+	return &kubeAPIRateLimiter{config: c}
+}
+
+func (r *kubeAPIRateLimiter) current() flowcontrol.RateLimiter {
+	settings := r.config.Get()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.limiter == nil || r.qps != settings.KubeAPIQPS || r.burst != settings.KubeAPIBurst {
+		r.qps, r.burst = settings.KubeAPIQPS, settings.KubeAPIBurst
+		r.limiter = flowcontrol.NewTokenBucketRateLimiter(r.qps, r.burst)
+	}
+	return r.limiter
+}
+
+func (r *kubeAPIRateLimiter) TryAccept() bool {
+	return r.current().TryAccept()
+}
+
+func (r *kubeAPIRateLimiter) Accept() {
+	r.current().Accept()
+}
+
+func (r *kubeAPIRateLimiter) Stop() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.limiter != nil {
+		r.limiter.Stop()
+	}
+}
+
+func (r *kubeAPIRateLimiter) QPS() float32 {
+	return r.current().QPS()
+}
+
+func (r *kubeAPIRateLimiter) Wait(ctx context.Context) error {
+	return r.current().Wait(ctx)
+}
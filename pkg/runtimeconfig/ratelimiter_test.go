@@ -0,0 +1,57 @@
+package runtimeconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_NewWorkqueueRateLimiter(t *testing.T) {
+	t.Run("custom delays", func(t *testing.T) {
+		rateLimiter := New(Settings{WorkqueueBaseDelay: time.Second, WorkqueueMaxDelay: 3 * time.Second}).NewWorkqueueRateLimiter()
+
+		assert.Equal(t, time.Second, rateLimiter.When("jenkins-a"))
+		assert.Equal(t, 2*time.Second, rateLimiter.When("jenkins-a"))
+		assert.Equal(t, 3*time.Second, rateLimiter.When("jenkins-a"))
+		assert.Equal(t, time.Second, rateLimiter.When("jenkins-b"))
+		assert.Equal(t, 3, rateLimiter.NumRequeues("jenkins-a"))
+
+		rateLimiter.Forget("jenkins-a")
+		assert.Equal(t, time.Second, rateLimiter.When("jenkins-a"))
+	})
+	t.Run("default delays", func(t *testing.T) {
+		rateLimiter := New(Settings{}).NewWorkqueueRateLimiter()
+
+		assert.Equal(t, 5*time.Millisecond, rateLimiter.When("jenkins"))
+	})
+	t.Run("delays changed at runtime", func(t *testing.T) {
+		config := New(Settings{WorkqueueBaseDelay: time.Second, WorkqueueMaxDelay: time.Minute})
+		rateLimiter := config.NewWorkqueueRateLimiter()
+		assert.Equal(t, time.Second, rateLimiter.When("jenkins"))
+
+		_, err := config.Apply(map[string]string{WorkqueueBaseDelayKey: "10s", WorkqueueMaxDelayKey: "15s"})
+		require.NoError(t, err)
+
+		assert.Equal(t, 15*time.Second, rateLimiter.When("jenkins"))
+		rateLimiter.Forget("jenkins")
+		assert.Equal(t, 10*time.Second, rateLimiter.When("jenkins"))
+	})
+}
+
+func TestConfig_NewKubeAPIRateLimiter(t *testing.T) {
+	config := New(Settings{KubeAPIQPS: 20, KubeAPIBurst: 1})
+	rateLimiter := config.NewKubeAPIRateLimiter()
+	assert.Equal(t, float32(20), rateLimiter.QPS())
+	assert.True(t, rateLimiter.TryAccept())
+	assert.False(t, rateLimiter.TryAccept())
+
+	_, err := config.Apply(map[string]string{KubeAPIQPSKey: "50", KubeAPIBurstKey: "2"})
+	require.NoError(t, err)
+
+	assert.Equal(t, float32(50), rateLimiter.QPS())
+	assert.True(t, rateLimiter.TryAccept())
+	assert.True(t, rateLimiter.TryAccept())
+	assert.False(t, rateLimiter.TryAccept())
+}
@@ -0,0 +1,238 @@
+// Package runtimeconfig keeps operator settings which can be changed at runtime through a ConfigMap. Only the keys
+// listed in Keys are accepted, the other command line flags are applied on operator start only and the ConfigMap
+// with any other key is rejected.
+package runtimeconfig
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DebugKey is the ConfigMap key which toggles debug logging
+	DebugKey = "debug"
+	// ReconcileFailLimitKey is the ConfigMap key with the number of the same consecutive reconcile errors after which the operator gives up
+	ReconcileFailLimitKey = "reconcile-fail-limit"
+	// ReconcileStalledThresholdKey is the ConfigMap key with the duration after which a requeueing Jenkins CR is marked as stalled
+	ReconcileStalledThresholdKey = "reconcile-stalled-threshold"
+	// WorkqueueBaseDelayKey is the ConfigMap key with the initial requeue delay of a Jenkins CR after a failed reconcile
+	WorkqueueBaseDelayKey = "workqueue-base-delay"
+	// WorkqueueMaxDelayKey is the ConfigMap key with the maximum requeue delay of a Jenkins CR after failed reconciles
+	WorkqueueMaxDelayKey = "workqueue-max-delay"
+	// NotificationProxyKey is the ConfigMap key with the URL of the proxy used to send HTTP notifications
+	NotificationProxyKey = "notification-proxy"
+	// KubeAPIQPSKey is the ConfigMap key with the maximum queries per second of the operator to the Kubernetes API
+	KubeAPIQPSKey = "kube-api-qps"
+	// KubeAPIBurstKey is the ConfigMap key with the maximum burst of the operator queries to the Kubernetes API
+	KubeAPIBurstKey = "kube-api-burst"
+)
+
+// Keys are the ConfigMap keys accepted by Apply
+var Keys = []string{
+	DebugKey,
+	ReconcileFailLimitKey,
+	ReconcileStalledThresholdKey,
+	WorkqueueBaseDelayKey,
+	WorkqueueMaxDelayKey,
+	NotificationProxyKey,
+	KubeAPIQPSKey,
+	KubeAPIBurstKey,
+}
+
+// Settings holds operator settings which can be changed at runtime.
+type Settings struct {
+	// Debug enables debug logging
+	Debug bool
+	// ReconcileFailLimit is the number of the same consecutive reconcile errors after which the operator gives up
+	ReconcileFailLimit uint64
+	// ReconcileStalledThreshold is how long a Jenkins CR can be requeued before it is marked as stalled, zero disables the detection
+	ReconcileStalledThreshold time.Duration
+	// WorkqueueBaseDelay is the initial requeue delay of a Jenkins CR after a failed reconcile, the delay doubles on
+	// every consecutive failure of the same CR
+	WorkqueueBaseDelay time.Duration
+	// WorkqueueMaxDelay is the maximum requeue delay of a Jenkins CR after failed reconciles
+	WorkqueueMaxDelay time.Duration
+	// NotificationProxy is the URL of the proxy used to send HTTP notifications, when empty the proxy is taken from
+	// the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+	NotificationProxy string
+	// KubeAPIQPS is the maximum queries per second of the operator to the Kubernetes API
+	KubeAPIQPS float32
+	// KubeAPIBurst is the maximum burst of the operator queries to the Kubernetes API
+	KubeAPIBurst int
+}
+
+// Config stores current operator settings, settings missing in the ConfigMap fall back to the defaults.
+type Config struct {
+	mutex    sync.RWMutex
+	defaults Settings
+	current  Settings
+}
+
+// New creates Config with the given default settings, usually taken from the command line flags.
+func New(defaults Settings) *Config {
+	return &Config{defaults: defaults, current: defaults}
+}
+
+// Get returns current operator settings.
+func (c *Config) Get() Settings {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.current
+}
+
+// Apply parses the ConfigMap data and replaces current settings, on error current settings are left untouched.
+func (c *Config) Apply(data map[string]string) (Settings, error) {
+	settings, err := parse(c.defaults, data)
+	if err != nil {
+		return c.Get(), err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.current = settings
+	return settings, nil
+}
+
+func parse(defaults Settings, data map[string]string) (Settings, error) {
+	if err := validateKeys(data); err != nil {
+		return Settings{}, err
+	}
+
+	settings := defaults
+
+	if value, found := data[DebugKey]; found {
+		debug, err := strconv.ParseBool(value)
+		if err != nil {
+			return Settings{}, errors.Wrapf(err, "invalid '%s' value", DebugKey)
+		}
+		settings.Debug = debug
+	}
+
+	if value, found := data[ReconcileFailLimitKey]; found {
+		failLimit, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return Settings{}, errors.Wrapf(err, "invalid '%s' value", ReconcileFailLimitKey)
+		}
+		if failLimit == 0 {
+			return Settings{}, errors.Errorf("invalid '%s' value, it must be greater than 0", ReconcileFailLimitKey)
+		}
+		settings.ReconcileFailLimit = failLimit
+	}
+
+	if value, found := data[ReconcileStalledThresholdKey]; found {
+		threshold, err := time.ParseDuration(value)
+		if err != nil {
+			return Settings{}, errors.Wrapf(err, "invalid '%s' value", ReconcileStalledThresholdKey)
+		}
+		if threshold < 0 {
+			return Settings{}, errors.Errorf("invalid '%s' value, it can't be negative", ReconcileStalledThresholdKey)
+		}
+		settings.ReconcileStalledThreshold = threshold
+	}
+
+	// the delays are validated together as the max delay can't be lower than the base delay
+	_, baseDelayFound := data[WorkqueueBaseDelayKey]
+	_, maxDelayFound := data[WorkqueueMaxDelayKey]
+	if value := data[WorkqueueBaseDelayKey]; baseDelayFound {
+		baseDelay, err := time.ParseDuration(value)
+		if err != nil {
+			return Settings{}, errors.Wrapf(err, "invalid '%s' value", WorkqueueBaseDelayKey)
+		}
+		settings.WorkqueueBaseDelay = baseDelay
+	}
+
+	if value := data[WorkqueueMaxDelayKey]; maxDelayFound {
+		maxDelay, err := time.ParseDuration(value)
+		if err != nil {
+			return Settings{}, errors.Wrapf(err, "invalid '%s' value", WorkqueueMaxDelayKey)
+		}
+		settings.WorkqueueMaxDelay = maxDelay
+	}
+
+	if (baseDelayFound || maxDelayFound) && (settings.WorkqueueBaseDelay <= 0 || settings.WorkqueueMaxDelay < settings.WorkqueueBaseDelay) {
+		return Settings{}, errors.Errorf("invalid '%s' or '%s' value, the base delay must be positive and not greater than the max delay",
+			WorkqueueBaseDelayKey, WorkqueueMaxDelayKey)
+	}
+
+	if value, found := data[NotificationProxyKey]; found {
+		if err := ValidateProxy(value); err != nil {
+			return Settings{}, errors.Wrapf(err, "invalid '%s' value", NotificationProxyKey)
+		}
+		settings.NotificationProxy = value
+	}
+
+	if value, found := data[KubeAPIQPSKey]; found {
+		qps, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return Settings{}, errors.Wrapf(err, "invalid '%s' value", KubeAPIQPSKey)
+		}
+		if qps <= 0 {
+			return Settings{}, errors.Errorf("invalid '%s' value, it must be greater than 0", KubeAPIQPSKey)
+		}
+		settings.KubeAPIQPS = float32(qps)
+	}
+
+	if value, found := data[KubeAPIBurstKey]; found {
+		burst, err := strconv.Atoi(value)
+		if err != nil {
+			return Settings{}, errors.Wrapf(err, "invalid '%s' value", KubeAPIBurstKey)
+		}
+		if burst <= 0 {
+			return Settings{}, errors.Errorf("invalid '%s' value, it must be greater than 0", KubeAPIBurstKey)
+		}
+		settings.KubeAPIBurst = burst
+	}
+
+	return settings, nil
+}
+
+// ValidateProxy returns error when the proxy isn't an absolute http, https or socks5 URL, an empty proxy is valid
+// and means the proxy from the environment variables
+func ValidateProxy(proxy string) error {
+	if proxy == "" {
+		return nil
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return errors.Errorf("unsupported proxy scheme '%s', the supported schemes are 'http', 'https', 'socks5'", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return errors.Errorf("proxy '%s' has no host", proxy)
+	}
+	return nil
+}
+
+// validateKeys returns error when the ConfigMap data has keys which can't be changed at runtime, they would be
+// silently ignored otherwise
+func validateKeys(data map[string]string) error {
+	var unknown []string
+	for key := range data {
+		supported := false
+		for _, supportedKey := range Keys {
+			if key == supportedKey {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	return errors.Errorf("unsupported keys '%s', the supported keys are '%s'", strings.Join(unknown, "', '"), strings.Join(Keys, "', '"))
+}
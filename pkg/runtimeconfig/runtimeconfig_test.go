@@ -0,0 +1,110 @@
+package runtimeconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Apply(t *testing.T) {
+	defaults := Settings{
+		Debug:                     false,
+		ReconcileFailLimit:        10,
+		ReconcileStalledThreshold: 15 * time.Minute,
+		WorkqueueBaseDelay:        5 * time.Millisecond,
+		WorkqueueMaxDelay:         1000 * time.Second,
+		KubeAPIQPS:                20,
+		KubeAPIBurst:              30,
+	}
+
+	t.Run("empty data uses defaults", func(t *testing.T) {
+		config := New(defaults)
+
+		settings, err := config.Apply(nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, defaults, settings)
+		assert.Equal(t, defaults, config.Get())
+	})
+	t.Run("all settings", func(t *testing.T) {
+		config := New(defaults)
+
+		settings, err := config.Apply(map[string]string{
+			DebugKey:                     "true",
+			ReconcileFailLimitKey:        "3",
+			ReconcileStalledThresholdKey: "1h",
+			WorkqueueBaseDelayKey:        "1s",
+			WorkqueueMaxDelayKey:         "1m",
+			NotificationProxyKey:         "http://proxy:3128",
+			KubeAPIQPSKey:                "50.5",
+			KubeAPIBurstKey:              "100",
+		})
+
+		expected := Settings{
+			Debug:                     true,
+			ReconcileFailLimit:        3,
+			ReconcileStalledThreshold: time.Hour,
+			WorkqueueBaseDelay:        time.Second,
+			WorkqueueMaxDelay:         time.Minute,
+			NotificationProxy:         "http://proxy:3128",
+			KubeAPIQPS:                50.5,
+			KubeAPIBurst:              100,
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expected, settings)
+		assert.Equal(t, expected, config.Get())
+	})
+	t.Run("removed key falls back to default", func(t *testing.T) {
+		config := New(defaults)
+		_, err := config.Apply(map[string]string{DebugKey: "true", ReconcileFailLimitKey: "3"})
+		assert.NoError(t, err)
+
+		settings, err := config.Apply(map[string]string{ReconcileFailLimitKey: "3"})
+
+		assert.NoError(t, err)
+		assert.False(t, settings.Debug)
+		assert.Equal(t, uint64(3), settings.ReconcileFailLimit)
+	})
+	t.Run("invalid value keeps current settings", func(t *testing.T) {
+		config := New(defaults)
+		_, err := config.Apply(map[string]string{DebugKey: "true"})
+		assert.NoError(t, err)
+
+		for _, data := range []map[string]string{
+			{DebugKey: "maybe"},
+			{ReconcileFailLimitKey: "0"},
+			{ReconcileFailLimitKey: "-1"},
+			{ReconcileStalledThresholdKey: "forever"},
+			{ReconcileStalledThresholdKey: "-1m"},
+			{WorkqueueBaseDelayKey: "0s"},
+			{WorkqueueBaseDelayKey: "soon"},
+			{WorkqueueMaxDelayKey: "1ms"},
+			{WorkqueueBaseDelayKey: "1m", WorkqueueMaxDelayKey: "1s"},
+			{NotificationProxyKey: "proxy:3128"},
+			{NotificationProxyKey: "ftp://proxy"},
+			{NotificationProxyKey: "http://"},
+			{KubeAPIQPSKey: "0"},
+			{KubeAPIQPSKey: "fast"},
+			{KubeAPIBurstKey: "-1"},
+			{"cluster-domain": "local"},
+		} {
+			settings, err := config.Apply(data)
+
+			assert.Error(t, err, data)
+			assert.True(t, settings.Debug)
+			assert.True(t, config.Get().Debug)
+		}
+	})
+}
+
+func TestValidateKeys(t *testing.T) {
+	assert.NoError(t, validateKeys(nil))
+	assert.NoError(t, validateKeys(map[string]string{DebugKey: "true", ReconcileStalledThresholdKey: "1h"}))
+
+	err := validateKeys(map[string]string{DebugKey: "true", "jenkins-api-port": "8080", "cluster-domain": "local"})
+
+	assert.EqualError(t, err, "unsupported keys 'cluster-domain', 'jenkins-api-port', the supported keys are 'debug', "+
+		"'reconcile-fail-limit', 'reconcile-stalled-threshold', 'workqueue-base-delay', 'workqueue-max-delay', "+
+		"'notification-proxy', 'kube-api-qps', 'kube-api-burst'")
+}
@@ -0,0 +1,279 @@
+// Package scmwebhook receives GitHub and GitLab webhooks and triggers seed jobs of the pushed repositories, it's
+// useful when Jenkins is not exposed to the SCM.
+package scmwebhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/user/seedjobs"
+	"github.com/maximba/kubernetes-operator/pkg/constants"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PathPrefix is the path prefix of webhook endpoint, the full path is /scm-webhook/<namespace>/<name>
+	PathPrefix = "/scm-webhook/"
+	// SecretKey is the key in webhook secret with the shared secret
+	SecretKey = "secret"
+
+	// maxPayloadBytes is the maximum payload size of GitHub webhooks, larger payloads are rejected before the
+	// signature is computed
+	maxPayloadBytes = 25 * 1024 * 1024
+
+	readHeaderTimeout = 10 * time.Second
+	readTimeout       = 30 * time.Second
+	writeTimeout      = time.Minute
+	idleTimeout       = 2 * time.Minute
+)
+
+// Server is the HTTP server receiving SCM webhooks.
+type Server struct {
+	Client                       client.Client
+	ClientSet                    kubernetes.Clientset
+	Config                       rest.Config
+	JenkinsAPIConnectionSettings jenkinsclient.JenkinsAPIConnectionSettings
+	KubernetesClusterDomain      string
+	BindAddress                  string
+
+	// getJenkinsClient is used in tests instead of the Jenkins API client
+	getJenkinsClient func(jenkins *v1alpha2.Jenkins) (jenkinsclient.Jenkins, error)
+}
+
+// Start serves webhooks until the context is done.
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(PathPrefix, s)
+	// the endpoint is often exposed to the internet, slow clients can't hold connections forever
+	server := &http.Server{
+		Addr:              s.BindAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+
+	log.Log.Info(fmt.Sprintf("Starting SCM webhook server on %s", s.BindAddress))
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return stackerr.WithStack(err)
+	}
+	return nil
+}
+
+// ServeHTTP validates the webhook and triggers seed jobs of the pushed repository and branch.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, PathPrefix), "/")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	if r.ContentLength > maxPayloadBytes {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadBytes))
+	if err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	jenkins := &v1alpha2.Jenkins{}
+	err = s.Client.Get(r.Context(), types.NamespacedName{Namespace: parts[0], Name: parts[1]}, jenkins)
+	if err != nil && apierrors.IsNotFound(err) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		s.fail(w, jenkins, err)
+		return
+	}
+	if jenkins.Spec.SCMWebhook == nil {
+		http.NotFound(w, r)
+		return
+	}
+	logger := log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace)
+
+	secret := &corev1.Secret{}
+	err = s.Client.Get(r.Context(), types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Spec.SCMWebhook.SecretName}, secret)
+	if err != nil {
+		s.fail(w, jenkins, err)
+		return
+	}
+
+	push, err := parsePush(r.Header, payload, secret.Data[SecretKey])
+	if err != nil {
+		logger.V(log.VWarn).Info(fmt.Sprintf("Rejected SCM webhook: %s", err))
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if push == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	jobs := matchingSeedJobs(jenkins.Spec.SeedJobs, *push)
+	if len(jobs) == 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	jenkinsClient, err := s.jenkinsClient(jenkins)
+	if err != nil {
+		s.fail(w, jenkins, err)
+		return
+	}
+	for _, job := range jobs {
+		logger.Info(fmt.Sprintf("Triggering seed job '%s' on push to '%s'", job, push.Branch))
+		if _, err := jenkinsClient.BuildJob(job); err != nil {
+			s.fail(w, jenkins, err)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = fmt.Fprintf(w, "triggered %s\n", strings.Join(jobs, ", "))
+}
+
+func (s *Server) fail(w http.ResponseWriter, jenkins *v1alpha2.Jenkins, err error) {
+	log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace).V(log.VWarn).Info(fmt.Sprintf("Failed to handle SCM webhook: %s", err))
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
+
+func (s *Server) jenkinsClient(jenkins *v1alpha2.Jenkins) (jenkinsclient.Jenkins, error) {
+	if s.getJenkinsClient != nil {
+		return s.getJenkinsClient(jenkins)
+	}
+	config := configuration.Configuration{
+		Client:                       s.Client,
+		ClientSet:                    s.ClientSet,
+		Config:                       &s.Config,
+		Jenkins:                      jenkins,
+		JenkinsAPIConnectionSettings: s.JenkinsAPIConnectionSettings,
+		KubernetesClusterDomain:      s.KubernetesClusterDomain,
+	}
+	return config.GetJenkinsClient()
+}
+
+// push is the repository and branch of SCM push event
+type push struct {
+	RepositoryURLs []string
+	Branch         string
+}
+
+type githubPush struct {
+	Ref        string `json:"ref"`
+	Repository struct {
+		CloneURL string `json:"clone_url"`
+		SSHURL   string `json:"ssh_url"`
+		HTMLURL  string `json:"html_url"`
+	} `json:"repository"`
+}
+
+type gitlabPush struct {
+	Ref     string `json:"ref"`
+	Project struct {
+		GitHTTPURL string `json:"git_http_url"`
+		GitSSHURL  string `json:"git_ssh_url"`
+		WebURL     string `json:"web_url"`
+	} `json:"project"`
+}
+
+// parsePush validates the webhook and returns pushed repository and branch, nil is returned for other events
+func parsePush(header http.Header, payload []byte, secret []byte) (*push, error) {
+	if len(secret) == 0 {
+		return nil, stackerr.Errorf("webhook secret key '%s' is empty", SecretKey)
+	}
+
+	if event := header.Get("X-GitHub-Event"); len(event) > 0 {
+		signature := strings.TrimPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+		mac := hmac.New(sha256.New, secret)
+		_, _ = mac.Write(payload)
+		expected := hex.EncodeToString(mac.Sum(nil))
+		if !hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil, stackerr.New("invalid GitHub webhook signature")
+		}
+		if event != "push" {
+			return nil, nil
+		}
+		body := githubPush{}
+		if err := json.Unmarshal(payload, &body); err != nil {
+			return nil, stackerr.WithStack(err)
+		}
+		return &push{
+			RepositoryURLs: []string{body.Repository.CloneURL, body.Repository.SSHURL, body.Repository.HTMLURL},
+			Branch:         strings.TrimPrefix(body.Ref, "refs/heads/"),
+		}, nil
+	}
+
+	if event := header.Get("X-Gitlab-Event"); len(event) > 0 {
+		if subtle.ConstantTimeCompare([]byte(header.Get("X-Gitlab-Token")), secret) != 1 {
+			return nil, stackerr.New("invalid GitLab webhook token")
+		}
+		if event != "Push Hook" {
+			return nil, nil
+		}
+		body := gitlabPush{}
+		if err := json.Unmarshal(payload, &body); err != nil {
+			return nil, stackerr.WithStack(err)
+		}
+		return &push{
+			RepositoryURLs: []string{body.Project.GitHTTPURL, body.Project.GitSSHURL, body.Project.WebURL},
+			Branch:         strings.TrimPrefix(body.Ref, "refs/heads/"),
+		}, nil
+	}
+
+	return nil, stackerr.New("unknown webhook, expected GitHub or GitLab event")
+}
+
+// matchingSeedJobs returns Jenkins job names of seed jobs configured for the pushed repository and branch
+func matchingSeedJobs(seedJobs []v1alpha2.SeedJob, push push) []string {
+	var jobs []string
+	for _, seedJob := range seedJobs {
+		if strings.TrimPrefix(seedJob.RepositoryBranch, "*/") != push.Branch {
+			continue
+		}
+		for _, url := range push.RepositoryURLs {
+			if len(url) > 0 && normalizeRepositoryURL(url) == normalizeRepositoryURL(seedJob.RepositoryURL) {
+				job := fmt.Sprintf("%s-%s", seedJob.ID, constants.SeedJobSuffix)
+				if folder := seedjobs.JobFolder(seedJob); len(folder) > 0 {
+					job = fmt.Sprintf("%s/job/%s", folder, job)
+				}
+				jobs = append(jobs, job)
+				break
+			}
+		}
+	}
+	return jobs
+}
+
+func normalizeRepositoryURL(url string) string {
+	url = strings.ToLower(strings.TrimSpace(url))
+	url = strings.TrimSuffix(url, "/")
+	return strings.TrimSuffix(url, ".git")
+}
@@ -0,0 +1,183 @@
+package scmwebhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	webhookSecret = "s3cr3t"
+	githubPayload = `{"ref":"refs/heads/master","repository":{"clone_url":"https://github.com/maximba/kubernetes-operator.git","ssh_url":"git@github.com:maximba/kubernetes-operator.git"}}`
+	gitlabPayload = `{"ref":"refs/heads/master","project":{"git_http_url":"https://gitlab.com/maximba/kubernetes-operator.git"}}`
+)
+
+func githubSignature(payload string) string {
+	mac := hmac.New(sha256.New, []byte(webhookSecret))
+	_, _ = mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestParsePush(t *testing.T) {
+	t.Run("GitHub push", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-GitHub-Event", "push")
+		header.Set("X-Hub-Signature-256", githubSignature(githubPayload))
+
+		push, err := parsePush(header, []byte(githubPayload), []byte(webhookSecret))
+
+		require.NoError(t, err)
+		assert.Equal(t, "master", push.Branch)
+		assert.Contains(t, push.RepositoryURLs, "git@github.com:maximba/kubernetes-operator.git")
+	})
+	t.Run("GitHub invalid signature", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-GitHub-Event", "push")
+		header.Set("X-Hub-Signature-256", githubSignature("{}"))
+
+		_, err := parsePush(header, []byte(githubPayload), []byte(webhookSecret))
+
+		assert.EqualError(t, err, "invalid GitHub webhook signature")
+	})
+	t.Run("GitHub ping", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-GitHub-Event", "ping")
+		header.Set("X-Hub-Signature-256", githubSignature("{}"))
+
+		push, err := parsePush(header, []byte("{}"), []byte(webhookSecret))
+
+		assert.NoError(t, err)
+		assert.Nil(t, push)
+	})
+	t.Run("GitLab push", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-Gitlab-Event", "Push Hook")
+		header.Set("X-Gitlab-Token", webhookSecret)
+
+		push, err := parsePush(header, []byte(gitlabPayload), []byte(webhookSecret))
+
+		require.NoError(t, err)
+		assert.Equal(t, "master", push.Branch)
+		assert.Contains(t, push.RepositoryURLs, "https://gitlab.com/maximba/kubernetes-operator.git")
+	})
+	t.Run("GitLab invalid token", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-Gitlab-Event", "Push Hook")
+		header.Set("X-Gitlab-Token", "wrong")
+
+		_, err := parsePush(header, []byte(gitlabPayload), []byte(webhookSecret))
+
+		assert.EqualError(t, err, "invalid GitLab webhook token")
+	})
+	t.Run("unknown webhook", func(t *testing.T) {
+		_, err := parsePush(http.Header{}, []byte(githubPayload), []byte(webhookSecret))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestMatchingSeedJobs(t *testing.T) {
+	seedJobs := []v1alpha2.SeedJob{
+		{ID: "operator", RepositoryURL: "https://github.com/maximba/kubernetes-operator", RepositoryBranch: "master"},
+		{ID: "operator-ssh", RepositoryURL: "git@github.com:maximba/kubernetes-operator.git", RepositoryBranch: "*/master", CredentialFolder: "team-a"},
+		{ID: "operator-develop", RepositoryURL: "https://github.com/maximba/kubernetes-operator.git", RepositoryBranch: "develop"},
+		{ID: "other", RepositoryURL: "https://github.com/maximba/other.git", RepositoryBranch: "master"},
+		{ID: "operator-team-b", RepositoryURL: "https://github.com/maximba/kubernetes-operator.git", RepositoryBranch: "master", Folder: "team-b"},
+	}
+
+	jobs := matchingSeedJobs(seedJobs, push{
+		RepositoryURLs: []string{"https://github.com/maximba/kubernetes-operator.git", "git@github.com:maximba/kubernetes-operator.git"},
+		Branch:         "master",
+	})
+
+	assert.Equal(t, []string{"operator-job-dsl-seed", "team-a/job/operator-ssh-job-dsl-seed", "team-b/job/operator-team-b-job-dsl-seed"}, jobs)
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"},
+		Spec: v1alpha2.JenkinsSpec{
+			SCMWebhook: &v1alpha2.SCMWebhook{SecretName: "scm-webhook"},
+			SeedJobs: []v1alpha2.SeedJob{
+				{ID: "operator", RepositoryURL: "https://github.com/maximba/kubernetes-operator.git", RepositoryBranch: "master"},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "scm-webhook", Namespace: "default"},
+		Data:       map[string][]byte{SecretKey: []byte(webhookSecret)},
+	}
+
+	t.Run("triggers seed job", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().BuildJob("operator-job-dsl-seed").Return(int64(1), nil)
+		server := &Server{
+			Client: fake.NewClientBuilder().WithObjects(jenkins, secret).Build(),
+			getJenkinsClient: func(*v1alpha2.Jenkins) (jenkinsclient.Jenkins, error) {
+				return jenkinsClient, nil
+			},
+		}
+		request := httptest.NewRequest(http.MethodPost, "/scm-webhook/default/jenkins", strings.NewReader(githubPayload))
+		request.Header.Set("X-GitHub-Event", "push")
+		request.Header.Set("X-Hub-Signature-256", githubSignature(githubPayload))
+		recorder := httptest.NewRecorder()
+
+		server.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusAccepted, recorder.Code)
+	})
+	t.Run("rejects invalid signature", func(t *testing.T) {
+		server := &Server{Client: fake.NewClientBuilder().WithObjects(jenkins, secret).Build()}
+		request := httptest.NewRequest(http.MethodPost, "/scm-webhook/default/jenkins", strings.NewReader(githubPayload))
+		request.Header.Set("X-GitHub-Event", "push")
+		request.Header.Set("X-Hub-Signature-256", "sha256=invalid")
+		recorder := httptest.NewRecorder()
+
+		server.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+	t.Run("rejects too large payload", func(t *testing.T) {
+		server := &Server{Client: fake.NewClientBuilder().WithObjects(jenkins, secret).Build()}
+		payload := strings.Repeat("a", maxPayloadBytes+1)
+
+		request := httptest.NewRequest(http.MethodPost, "/scm-webhook/default/jenkins", strings.NewReader(payload))
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+
+		// the payload of unknown length is cut off while reading
+		request = httptest.NewRequest(http.MethodPost, "/scm-webhook/default/jenkins", io.MultiReader(strings.NewReader(payload)))
+		recorder = httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+	t.Run("webhook not enabled", func(t *testing.T) {
+		server := &Server{Client: fake.NewClientBuilder().WithObjects(secret).Build()}
+		request := httptest.NewRequest(http.MethodPost, "/scm-webhook/default/jenkins", strings.NewReader(githubPayload))
+		recorder := httptest.NewRecorder()
+
+		server.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
@@ -0,0 +1,291 @@
+// Package supportbundle serves support bundles of Jenkins CRs, a tarball with the redacted CR, its status, recent
+// events, the differences between Jenkins master pod and the CR, the last groovy script errors and operator logs.
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base"
+	"github.com/maximba/kubernetes-operator/pkg/configuration/base/resources"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	stackerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PathPrefix is the path prefix of support bundle endpoint, the full path is /support-bundle/<namespace>/<name>
+	PathPrefix = "/support-bundle/"
+
+	maxEvents        = 100
+	maxGroovyErrors  = 20
+	redactedValue    = "<redacted>"
+	groovyErrorMatch = "groovy script execution failed"
+)
+
+// sensitiveName matches field names which values are redacted in the bundle
+var sensitiveName = regexp.MustCompile(`(?i)(password|passwd|secret|token|apikey|api_key|credential|private_?key)`)
+
+// Server is the HTTP handler serving support bundles, it's served by the metrics server of the manager, so it's
+// protected by the same authentication and authorization as the metrics endpoint.
+type Server struct {
+	Client client.Client
+	// APIReader lists events directly from the API server, the cached client would watch all events in the namespace
+	APIReader client.Reader
+	// Logs are the recent operator log entries
+	Logs *log.RingBuffer
+}
+
+// ServeHTTP writes the support bundle of Jenkins CR as a gzipped tarball.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, PathPrefix), "/")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	jenkins := &v1alpha2.Jenkins{}
+	err := s.Client.Get(r.Context(), types.NamespacedName{Namespace: parts[0], Name: parts[1]}, jenkins)
+	if err != nil && apierrors.IsNotFound(err) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		s.fail(w, jenkins, err)
+		return
+	}
+
+	files, err := s.collect(r.Context(), jenkins)
+	if err != nil {
+		s.fail(w, jenkins, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", bundleName(jenkins)+".tar.gz"))
+	if err := writeTarball(w, bundleName(jenkins), files); err != nil {
+		log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace).V(log.VWarn).Info(fmt.Sprintf("Failed to write support bundle: %s", err))
+	}
+}
+
+func (s *Server) fail(w http.ResponseWriter, jenkins *v1alpha2.Jenkins, err error) {
+	log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace).V(log.VWarn).Info(fmt.Sprintf("Failed to collect support bundle: %s", err))
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
+
+type file struct {
+	name    string
+	content []byte
+}
+
+// collect returns the files of the bundle, the pod drift is best effort because the pod may not exist
+func (s *Server) collect(ctx context.Context, jenkins *v1alpha2.Jenkins) ([]file, error) {
+	redacted, err := redactJenkins(jenkins)
+	if err != nil {
+		return nil, err
+	}
+	// status contains the snapshot of spec taken when Jenkins was ready
+	status, err := marshalRedacted(jenkins.Status)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.events(ctx, jenkins)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []string
+	if s.Logs != nil {
+		logs = s.Logs.Entries()
+	}
+	operatorLogs := filterLogs(logs, jenkins, "")
+	groovyErrors := filterLogs(logs, jenkins, groovyErrorMatch)
+	if len(groovyErrors) > maxGroovyErrors {
+		groovyErrors = groovyErrors[len(groovyErrors)-maxGroovyErrors:]
+	}
+
+	return []file{
+		{name: "jenkins.json", content: redacted},
+		{name: "status.json", content: status},
+		{name: "events.txt", content: events},
+		{name: "pod-drift.txt", content: []byte(s.podDrift(jenkins))},
+		{name: "groovy-errors.log", content: []byte(joinLines(groovyErrors))},
+		{name: "operator.log", content: []byte(joinLines(operatorLogs))},
+	}, nil
+}
+
+// events returns the latest events of Jenkins CR and Jenkins master pod, one per line
+func (s *Server) events(ctx context.Context, jenkins *v1alpha2.Jenkins) ([]byte, error) {
+	eventList := &corev1.EventList{}
+	if err := s.APIReader.List(ctx, eventList, client.InNamespace(jenkins.Namespace)); err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+
+	podName := resources.GetJenkinsMasterPodName(jenkins)
+	var events []corev1.Event
+	for _, event := range eventList.Items {
+		involved := event.InvolvedObject
+		if (involved.Kind == v1alpha2.Kind && involved.Name == jenkins.Name) || (involved.Kind == "Pod" && involved.Name == podName) {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+	if len(events) > maxEvents {
+		events = events[len(events)-maxEvents:]
+	}
+
+	var lines []string
+	for _, event := range events {
+		lines = append(lines, fmt.Sprintf("%s %s %s/%s %s: %s (x%d)", eventTime(event).Format(time.RFC3339), event.Type,
+			event.InvolvedObject.Kind, event.InvolvedObject.Name, event.Reason, event.Message, event.Count))
+	}
+	return []byte(joinLines(lines)), nil
+}
+
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// podDrift describes the differences between Jenkins master pod and the pod required by Jenkins CR
+func (s *Server) podDrift(jenkins *v1alpha2.Jenkins) string {
+	reconciler := base.New(configuration.Configuration{Client: s.Client, Jenkins: jenkins}, jenkinsclient.JenkinsAPIConnectionSettings{})
+	pod, err := reconciler.GetJenkinsMasterPod()
+	if err != nil {
+		return fmt.Sprintf("Failed to get Jenkins master pod: %s\n", err)
+	}
+	drift, err := reconciler.PodDrift(*pod)
+	if err != nil {
+		return fmt.Sprintf("Failed to compare Jenkins master pod: %s\n", err)
+	}
+	if len(drift) == 0 {
+		return "Jenkins master pod matches Jenkins CR\n"
+	}
+	return joinLines(drift)
+}
+
+// redactJenkins returns Jenkins CR without managed fields and the last applied configuration, with values of
+// environment variables and sensitive fields redacted
+func redactJenkins(jenkins *v1alpha2.Jenkins) ([]byte, error) {
+	jenkinsCopy := jenkins.DeepCopy()
+	jenkinsCopy.ManagedFields = nil
+	delete(jenkinsCopy.Annotations, corev1.LastAppliedConfigAnnotation)
+	return marshalRedacted(jenkinsCopy)
+}
+
+// marshalRedacted returns indented JSON with values of environment variables and sensitive fields redacted
+func marshalRedacted(object interface{}) ([]byte, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	return marshal(redact(value))
+}
+
+// marshal returns indented JSON, HTML characters like in '<redacted>' aren't escaped
+func marshal(value interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	return buffer.Bytes(), nil
+}
+
+func redact(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		_, isEnvVar := typed["name"].(string)
+		for key, item := range typed {
+			if sensitiveName.MatchString(key) || (isEnvVar && key == "value") {
+				if _, isString := item.(string); isString {
+					typed[key] = redactedValue
+					continue
+				}
+			}
+			typed[key] = redact(item)
+		}
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = redact(item)
+		}
+	}
+	return value
+}
+
+// filterLogs returns log entries of Jenkins CR containing match, the entries are written by the console or the JSON
+// zap encoder. Both the name and the namespace of the CR must match, so logs of CRs with the same name in other
+// namespaces don't leak into the bundle.
+func filterLogs(entries []string, jenkins *v1alpha2.Jenkins, match string) []string {
+	crField := regexp.MustCompile(fmt.Sprintf(`"cr": ?"%s"`, regexp.QuoteMeta(jenkins.Name)))
+	namespaceField := regexp.MustCompile(fmt.Sprintf(`"namespace": ?"%s"`, regexp.QuoteMeta(jenkins.Namespace)))
+	var filtered []string
+	for _, entry := range entries {
+		if crField.MatchString(entry) && namespaceField.MatchString(entry) && strings.Contains(entry, match) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+func joinLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func bundleName(jenkins *v1alpha2.Jenkins) string {
+	return fmt.Sprintf("support-bundle-%s-%s", jenkins.Namespace, jenkins.Name)
+}
+
+func writeTarball(w http.ResponseWriter, directory string, files []file) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	now := time.Now()
+	for _, f := range files {
+		header := &tar.Header{Name: directory + "/" + f.name, Mode: 0644, Size: int64(len(f.content)), ModTime: now}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return stackerr.WithStack(err)
+		}
+		if _, err := tarWriter.Write(f.content); err != nil {
+			return stackerr.WithStack(err)
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return stackerr.WithStack(err)
+	}
+	return stackerr.WithStack(gzipWriter.Close())
+}
@@ -0,0 +1,126 @@
+package supportbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func readTarball(t *testing.T, body io.Reader) map[string]string {
+	gzipReader, err := gzip.NewReader(body)
+	require.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+	files := map[string]string{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return files
+		}
+		require.NoError(t, err)
+		content, err := ioutil.ReadAll(tarReader)
+		require.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	jenkins := &v1alpha2.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "jenkins",
+			Namespace:   "default",
+			Annotations: map[string]string{corev1.LastAppliedConfigAnnotation: "{}"},
+		},
+		Spec: v1alpha2.JenkinsSpec{
+			Master: v1alpha2.JenkinsMaster{
+				Containers: []v1alpha2.Container{{
+					Name:  "jenkins-master",
+					Image: "jenkins/jenkins:lts",
+					Env:   []corev1.EnvVar{{Name: "JAVA_OPTS", Value: "-Dproxy.password=admin"}},
+				}},
+			},
+		},
+		Status: v1alpha2.JenkinsStatus{
+			OperatorVersion:   "v0.7.0",
+			LastKnownGoodSpec: &runtime.RawExtension{Raw: []byte(`{"master":{"containers":[{"name":"jenkins-master","env":[{"name":"JAVA_OPTS","value":"-Dproxy.password=admin"}]}]}}`)},
+		},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "jenkins.1", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: v1alpha2.Kind, Name: "jenkins"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "PodRestart",
+		Message:        "Jenkins master pod restarted",
+		Count:          2,
+	}
+	otherEvent := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "other.1", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "other"},
+		Message:        "Pulled image",
+	}
+	logs := log.NewRingBuffer(10)
+	_, _ = logs.Write([]byte(`INFO	controller-jenkins	Base ConfigMap 'jenkins-operator-base-configuration-jenkins' running groovy script	{"cr": "jenkins", "namespace": "default"}` + "\n"))
+	_, _ = logs.Write([]byte(`INFO	controller-jenkins	User Source 'scripts' Name 'a.groovy' groovy script execution failed, logs :` + "\nboom\t" + `{"cr": "jenkins", "namespace": "default"}` + "\n"))
+	_, _ = logs.Write([]byte(`INFO	controller-jenkins	Creating a new Jenkins Master Pod	{"cr": "other", "namespace": "default"}` + "\n"))
+	_, _ = logs.Write([]byte(`INFO	controller-jenkins	Restoring backup	{"cr": "jenkins", "namespace": "team-b"}` + "\n"))
+	_, _ = logs.Write([]byte(`{"level":"info","logger":"controller-jenkins","msg":"Ensuring seed jobs","cr":"jenkins","namespace":"default"}` + "\n"))
+	_, _ = logs.Write([]byte(`{"level":"info","logger":"controller-jenkins","msg":"Ensuring plugins","cr":"jenkins-2","namespace":"default"}` + "\n"))
+	k8sClient := fake.NewClientBuilder().WithObjects(jenkins, event, otherEvent).Build()
+	server := &Server{Client: k8sClient, APIReader: k8sClient, Logs: logs}
+
+	t.Run("bundle", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, PathPrefix+"default/jenkins", nil))
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		files := readTarball(t, recorder.Body)
+		require.Len(t, files, 6)
+		directory := "support-bundle-default-jenkins/"
+		assert.Contains(t, files[directory+"jenkins.json"], `"image": "jenkins/jenkins:lts"`)
+		assert.Contains(t, files[directory+"jenkins.json"], `"value": "<redacted>"`)
+		assert.NotContains(t, files[directory+"jenkins.json"], "-Dproxy.password=admin")
+		assert.NotContains(t, files[directory+"jenkins.json"], corev1.LastAppliedConfigAnnotation)
+		assert.Contains(t, files[directory+"status.json"], `"operatorVersion": "v0.7.0"`)
+		assert.NotContains(t, files[directory+"status.json"], "-Dproxy.password=admin")
+		assert.Contains(t, files[directory+"events.txt"], "Warning Jenkins/jenkins PodRestart: Jenkins master pod restarted (x2)")
+		assert.NotContains(t, files[directory+"events.txt"], "Pulled image")
+		assert.Contains(t, files[directory+"pod-drift.txt"], "Failed to get Jenkins master pod")
+		assert.Contains(t, files[directory+"groovy-errors.log"], "'a.groovy' groovy script execution failed, logs :\nboom")
+		assert.NotContains(t, files[directory+"groovy-errors.log"], "running groovy script")
+		assert.Contains(t, files[directory+"operator.log"], "running groovy script")
+		assert.NotContains(t, files[directory+"operator.log"], "Creating a new Jenkins Master Pod")
+		assert.Contains(t, files[directory+"operator.log"], "Ensuring seed jobs")
+		assert.NotContains(t, files[directory+"operator.log"], "Ensuring plugins")
+		assert.NotContains(t, files[directory+"operator.log"], "Restoring backup")
+	})
+	t.Run("not found", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, PathPrefix+"default/missing", nil))
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+	t.Run("method not allowed", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, PathPrefix+"default/jenkins", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	})
+}
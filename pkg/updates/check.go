@@ -0,0 +1,229 @@
+// Package updates periodically compares Jenkins core and plugins running in Jenkins instances with the latest
+// versions published in the update center and reports the available updates.
+package updates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+	"github.com/maximba/kubernetes-operator/pkg/log"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/reason"
+	"github.com/maximba/kubernetes-operator/pkg/plugins"
+
+	stackerr "github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultUpdateCenterURL is the update center of the Jenkins LTS line
+	DefaultUpdateCenterURL = "https://updates.jenkins.io/stable/update-center.actual.json"
+
+	coreVersionGroovyScript = "println Jenkins.VERSION"
+	fetchAllPlugins         = 1
+	updateCenterTimeout     = time.Minute
+)
+
+// updateCenterClient downloads the update center JSON, the timeout covers also the read of the response body
+var updateCenterClient = &http.Client{Timeout: updateCenterTimeout}
+
+// UpdateCenter contains the latest core and plugin versions published in the update center.
+type UpdateCenter struct {
+	Core struct {
+		Version string `json:"version"`
+	} `json:"core"`
+	Plugins map[string]struct {
+		Version string `json:"version"`
+	} `json:"plugins"`
+}
+
+// Checker periodically checks the update center for newer Jenkins core and plugin versions, it updates the CR status
+// and sends an info notification when the available updates change.
+type Checker struct {
+	Client                       client.Client
+	ClientSet                    kubernetes.Clientset
+	Config                       rest.Config
+	JenkinsAPIConnectionSettings jenkinsclient.JenkinsAPIConnectionSettings
+	KubernetesClusterDomain      string
+	NotificationEvents           *chan event.Event
+	Interval                     time.Duration
+	UpdateCenterURL              string
+}
+
+// Start checks available updates every interval until the context is done.
+func (c *Checker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.checkAll(ctx)
+		}
+	}
+}
+
+func (c *Checker) checkAll(ctx context.Context) {
+	jenkinsList := &v1alpha2.JenkinsList{}
+	if err := c.Client.List(ctx, jenkinsList); err != nil {
+		log.Log.V(log.VWarn).Info(fmt.Sprintf("Failed to list Jenkins CRs for update check: %s", err))
+		return
+	}
+	if len(jenkinsList.Items) == 0 {
+		return
+	}
+
+	updateCenter, err := FetchUpdateCenter(ctx, c.UpdateCenterURL)
+	if err != nil {
+		log.Log.V(log.VWarn).Info(fmt.Sprintf("Failed to fetch update center '%s': %s", c.UpdateCenterURL, err))
+		return
+	}
+
+	for i := range jenkinsList.Items {
+		jenkins := &jenkinsList.Items[i]
+		if jenkins.Status.BaseConfigurationCompletedTime == nil {
+			continue
+		}
+		config := configuration.Configuration{
+			Client:                       c.Client,
+			ClientSet:                    c.ClientSet,
+			Config:                       &c.Config,
+			Jenkins:                      jenkins,
+			JenkinsAPIConnectionSettings: c.JenkinsAPIConnectionSettings,
+			KubernetesClusterDomain:      c.KubernetesClusterDomain,
+		}
+		jenkinsClient, err := config.GetJenkinsClient()
+		if err == nil {
+			err = c.check(ctx, jenkinsClient, jenkins, updateCenter)
+		}
+		if err != nil {
+			log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace).V(log.VDebug).Info(fmt.Sprintf("Failed to check available updates: %s", err))
+		}
+	}
+}
+
+func (c *Checker) check(ctx context.Context, jenkinsClient jenkinsclient.Jenkins, jenkins *v1alpha2.Jenkins, updateCenter *UpdateCenter) error {
+	updates, err := GetAvailableUpdates(jenkinsClient, updateCenter)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(jenkins.Status.AvailableUpdates, updates) {
+		return nil
+	}
+
+	if updates != nil {
+		var short []string
+		if len(updates.Core) > 0 {
+			short = append(short, fmt.Sprintf("Jenkins %s is available", updates.Core))
+		}
+		if len(updates.Plugins) > 0 {
+			short = append(short, fmt.Sprintf("%d plugin update(s) are available", len(updates.Plugins)))
+		}
+		verbose := append([]string{}, short...)
+		verbose = append(verbose, updates.Plugins...)
+		log.Log.WithValues("cr", jenkins.Name, "namespace", jenkins.Namespace).Info(strings.Join(short, ", "))
+		*c.NotificationEvents <- event.Event{
+			Jenkins: *jenkins,
+			Phase:   event.PhaseBase,
+			Level:   v1alpha2.NotificationLevelInfo,
+			Reason:  reason.NewUpdatesAvailable(reason.OperatorSource, short, verbose...),
+		}
+	}
+
+	jenkins.Status.AvailableUpdates = updates
+	return stackerr.WithStack(c.Client.Status().Update(ctx, jenkins))
+}
+
+// FetchUpdateCenter downloads the update center JSON.
+func FetchUpdateCenter(ctx context.Context, url string) (*UpdateCenter, error) {
+	ctx, cancel := context.WithTimeout(ctx, updateCenterTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	response, err := updateCenterClient.Do(request)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode != http.StatusOK {
+		return nil, stackerr.Errorf("unexpected update center response status '%s'", response.Status)
+	}
+
+	updateCenter := &UpdateCenter{}
+	if err := json.NewDecoder(response.Body).Decode(updateCenter); err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	return updateCenter, nil
+}
+
+// GetAvailableUpdates returns the core and plugin versions from the update center newer than the ones running in
+// Jenkins, nil is returned when Jenkins is up to date.
+func GetAvailableUpdates(jenkinsClient jenkinsclient.Jenkins, updateCenter *UpdateCenter) (*v1alpha2.AvailableUpdates, error) {
+	output, err := jenkinsClient.ExecuteScript(coreVersionGroovyScript)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+	installedPlugins, err := jenkinsClient.GetPlugins(fetchAllPlugins)
+	if err != nil {
+		return nil, stackerr.WithStack(err)
+	}
+
+	updates := &v1alpha2.AvailableUpdates{}
+	if coreVersion := strings.TrimSpace(output); len(updateCenter.Core.Version) > 0 &&
+		CompareVersions(updateCenter.Core.Version, coreVersion) > 0 {
+		updates.Core = updateCenter.Core.Version
+	}
+	for _, plugin := range installedPlugins.Raw.Plugins {
+		latest, found := updateCenter.Plugins[plugin.ShortName]
+		if found && CompareVersions(latest.Version, plugin.Version) > 0 {
+			updates.Plugins = append(updates.Plugins, plugins.Plugin{Name: plugin.ShortName, Version: latest.Version}.String())
+		}
+	}
+	if len(updates.Core) == 0 && len(updates.Plugins) == 0 {
+		return nil, nil
+	}
+	sort.Strings(updates.Plugins)
+	return updates, nil
+}
+
+// CompareVersions compares Jenkins versions part by part, numeric parts are compared as numbers. It returns a positive
+// number when first version is newer, a negative one when it's older and 0 when the versions are equal.
+func CompareVersions(first, second string) int {
+	split := func(version string) []string {
+		return strings.FieldsFunc(version, func(r rune) bool { return r == '.' || r == '-' || r == '_' })
+	}
+	firstParts, secondParts := split(first), split(second)
+	for i := 0; i < len(firstParts) && i < len(secondParts); i++ {
+		firstNumber, firstErr := strconv.ParseInt(firstParts[i], 10, 64)
+		secondNumber, secondErr := strconv.ParseInt(secondParts[i], 10, 64)
+		if firstErr == nil && secondErr == nil {
+			if firstNumber != secondNumber {
+				if firstNumber > secondNumber {
+					return 1
+				}
+				return -1
+			}
+			continue
+		}
+		if result := strings.Compare(firstParts[i], secondParts[i]); result != 0 {
+			return result
+		}
+	}
+	return len(firstParts) - len(secondParts)
+}
@@ -0,0 +1,101 @@
+package updates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	jenkinsclient "github.com/maximba/kubernetes-operator/pkg/client"
+	"github.com/maximba/kubernetes-operator/pkg/notifications/event"
+
+	"github.com/bndr/gojenkins"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const updateCenterJSON = `{"core":{"version":"2.319.2"},"plugins":{"git":{"version":"4.10.2"},"workflow-job":{"version":"1145.v7f2433caa07f"},"job-dsl":{"version":"1.78.1"}}}`
+
+func TestCompareVersions(t *testing.T) {
+	assert.True(t, CompareVersions("2.319.2", "2.319.1") > 0)
+	assert.True(t, CompareVersions("2.319.1", "2.319") > 0)
+	assert.True(t, CompareVersions("1145.v7f2433caa07f", "1144.v6f2433caa07f") > 0)
+	assert.True(t, CompareVersions("4.9.1", "4.10.0") < 0)
+	assert.Equal(t, 0, CompareVersions("1.78.1", "1.78.1"))
+}
+
+func TestFetchUpdateCenter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, updateCenterJSON)
+	}))
+	defer server.Close()
+
+	updateCenter, err := FetchUpdateCenter(context.TODO(), server.URL)
+
+	require.NoError(t, err)
+	assert.Equal(t, "2.319.2", updateCenter.Core.Version)
+	assert.Equal(t, "4.10.2", updateCenter.Plugins["git"].Version)
+}
+
+func TestChecker_check(t *testing.T) {
+	require.NoError(t, v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme))
+	updateCenter := &UpdateCenter{}
+	require.NoError(t, json.Unmarshal([]byte(updateCenterJSON), updateCenter))
+	installedPlugins := &gojenkins.Plugins{Raw: &gojenkins.PluginResponse{Plugins: []gojenkins.Plugin{
+		{ShortName: "git", Version: "4.10.1"},
+		{ShortName: "workflow-job", Version: "1145.v7f2433caa07f"},
+		{ShortName: "job-dsl", Version: "1.78.1"},
+		{ShortName: "custom", Version: "1.0"},
+	}}}
+
+	t.Run("reports available updates", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(coreVersionGroovyScript).Return("2.319.1\n", nil).Times(2)
+		jenkinsClient.EXPECT().GetPlugins(fetchAllPlugins).Return(installedPlugins, nil).Times(2)
+		jenkins := &v1alpha2.Jenkins{ObjectMeta: metav1.ObjectMeta{Name: "jenkins", Namespace: "default"}}
+		notifications := make(chan event.Event, 1)
+		checker := &Checker{
+			Client:             fake.NewClientBuilder().WithObjects(jenkins).Build(),
+			NotificationEvents: &notifications,
+		}
+
+		err := checker.check(context.TODO(), jenkinsClient, jenkins, updateCenter)
+
+		require.NoError(t, err)
+		expected := &v1alpha2.AvailableUpdates{Core: "2.319.2", Plugins: []string{"git:4.10.2"}}
+		actual := &v1alpha2.Jenkins{}
+		require.NoError(t, checker.Client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "jenkins"}, actual))
+		assert.Equal(t, expected, actual.Status.AvailableUpdates)
+		require.Len(t, notifications, 1)
+		notification := <-notifications
+		assert.Equal(t, v1alpha2.NotificationLevelInfo, notification.Level)
+		assert.Equal(t, []string{"Jenkins 2.319.2 is available", "1 plugin update(s) are available"}, notification.Reason.Short())
+
+		err = checker.check(context.TODO(), jenkinsClient, actual, updateCenter)
+
+		require.NoError(t, err)
+		assert.Len(t, notifications, 0)
+	})
+	t.Run("up to date", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := jenkinsclient.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(coreVersionGroovyScript).Return("2.319.2\n", nil)
+		jenkinsClient.EXPECT().GetPlugins(fetchAllPlugins).Return(&gojenkins.Plugins{Raw: &gojenkins.PluginResponse{}}, nil)
+
+		updates, err := GetAvailableUpdates(jenkinsClient, updateCenter)
+
+		require.NoError(t, err)
+		assert.Nil(t, updates)
+	})
+}
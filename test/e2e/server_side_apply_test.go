@@ -0,0 +1,88 @@
+package e2e
+
+import (
+	"context"
+
+	"github.com/maximba/kubernetes-operator/api/v1alpha2"
+	"github.com/maximba/kubernetes-operator/pkg/configuration"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	// +kubebuilder:scaffold:imports
+)
+
+var _ = Describe("Jenkins controller server-side apply", func() {
+
+	const (
+		jenkinsCRName     = e2e
+		configMapName     = "server-side-apply"
+		priorityClassName = ""
+	)
+
+	var (
+		namespace *corev1.Namespace
+		jenkins   *v1alpha2.Jenkins
+		config    configuration.Configuration
+	)
+
+	newConfigMap := func(value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: configMapName, Namespace: namespace.Name},
+			Data:       map[string]string{"key": value},
+		}
+	}
+	getConfigMap := func() *corev1.ConfigMap {
+		configMap := &corev1.ConfigMap{}
+		Expect(K8sClient.Get(context.TODO(), types.NamespacedName{Name: configMapName, Namespace: namespace.Name}, configMap)).Should(Succeed())
+		return configMap
+	}
+
+	BeforeEach(func() {
+		namespace = CreateNamespace()
+
+		jenkins = RenderJenkinsCR(jenkinsCRName, namespace.Name, nil, v1alpha2.GroovyScripts{}, v1alpha2.ConfigurationAsCode{}, priorityClassName)
+		Expect(K8sClient.Create(context.TODO(), jenkins)).Should(Succeed())
+		config = configuration.Configuration{Client: K8sClient, Jenkins: jenkins, Scheme: scheme.Scheme}
+	})
+
+	AfterEach(func() {
+		ShowLogsIfTestHasFailed(CurrentGinkgoTestDescription().Failed, namespace.Name)
+		DestroyNamespace(namespace)
+	})
+
+	Context("when the resource has been created before the operator switched to server-side apply", func() {
+		It("takes the fields over and returns conflicts with other managers afterwards", func() {
+			By("creating the resource with update")
+			configMap := newConfigMap("created")
+			Expect(controllerutil.SetControllerReference(jenkins, configMap, scheme.Scheme)).Should(Succeed())
+			Expect(K8sClient.Create(context.TODO(), configMap, client.FieldOwner("jenkins-operator-v0"))).Should(Succeed())
+
+			By("applying the changed resource")
+			Expect(config.CreateOrUpdateResource(newConfigMap("applied"))).Should(Succeed())
+			configMap = getConfigMap()
+			Expect(configMap.Data).Should(Equal(map[string]string{"key": "applied"}))
+			var managers []string
+			for _, managedFields := range configMap.ManagedFields {
+				if managedFields.Operation == metav1.ManagedFieldsOperationApply {
+					managers = append(managers, managedFields.Manager)
+				}
+			}
+			Expect(managers).Should(Equal([]string{configuration.FieldManager}))
+
+			By("changing the field by another manager")
+			configMap.Data["key"] = "changed"
+			Expect(K8sClient.Update(context.TODO(), configMap, client.FieldOwner("other-controller"))).Should(Succeed())
+
+			err := config.CreateOrUpdateResource(newConfigMap("applied again"))
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("has fields managed by other controllers which conflict with the operator"))
+			Expect(getConfigMap().Data).Should(Equal(map[string]string{"key": "changed"}))
+		})
+	})
+})
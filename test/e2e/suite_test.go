@@ -2,8 +2,10 @@ package e2e
 
 import (
 	"flag"
+	"net/http"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/maximba/kubernetes-operator/api/v1alpha2"
 	"github.com/maximba/kubernetes-operator/controllers"
@@ -12,6 +14,7 @@ import (
 	"github.com/maximba/kubernetes-operator/pkg/event"
 	"github.com/maximba/kubernetes-operator/pkg/notifications"
 	e "github.com/maximba/kubernetes-operator/pkg/notifications/event"
+	"github.com/maximba/kubernetes-operator/pkg/runtimeconfig"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -74,7 +77,7 @@ var _ = BeforeSuite(func(done Done) {
 	events, err := event.New(Cfg, constants.OperatorName)
 	Expect(err).NotTo(HaveOccurred())
 	notificationEvents := make(chan e.Event)
-	go notifications.Listen(notificationEvents, events, K8sClient)
+	go notifications.Listen(notificationEvents, events, K8sClient, http.ProxyFromEnvironment)
 
 	jenkinsAPIConnectionSettings := jenkinsClient.JenkinsAPIConnectionSettings{
 		Hostname:    *hostname,
@@ -90,6 +93,10 @@ var _ = BeforeSuite(func(done Done) {
 		Config:                       *Cfg,
 		NotificationEvents:           &notificationEvents,
 		KubernetesClusterDomain:      "cluster.local",
+		RuntimeConfig: runtimeconfig.New(runtimeconfig.Settings{
+			ReconcileFailLimit:        10,
+			ReconcileStalledThreshold: 15 * time.Minute,
+		}),
 	}).SetupWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
 